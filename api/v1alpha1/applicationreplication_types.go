@@ -0,0 +1,202 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ApplicationHealth is the health of an application's replication, rolled up from its members
+// +kubebuilder:validation:Enum=Healthy;Degraded;Unhealthy;Unknown
+type ApplicationHealth string
+
+const (
+	// ApplicationHealthHealthy indicates every member is ready and healthy
+	ApplicationHealthHealthy ApplicationHealth = "Healthy"
+	// ApplicationHealthDegraded indicates a member is degraded or not ready yet
+	ApplicationHealthDegraded ApplicationHealth = "Degraded"
+	// ApplicationHealthUnhealthy indicates a member has failed or is in split-brain
+	ApplicationHealthUnhealthy ApplicationHealth = "Unhealthy"
+	// ApplicationHealthUnknown indicates no member has reported its status yet
+	ApplicationHealthUnknown ApplicationHealth = "Unknown"
+)
+
+// HealthAggregationPolicy is how the health of an application is derived from its members'
+// +kubebuilder:validation:Enum=WorstOf;Weighted;Majority
+type HealthAggregationPolicy string
+
+const (
+	// HealthAggregationWorstOf takes the worst health among the members
+	HealthAggregationWorstOf HealthAggregationPolicy = "WorstOf"
+	// HealthAggregationWeighted takes the best health that members carrying more than half of
+	// the total weight are at or better than
+	HealthAggregationWeighted HealthAggregationPolicy = "Weighted"
+	// HealthAggregationMajority takes the best health that more than half of the members are
+	// at or better than
+	HealthAggregationMajority HealthAggregationPolicy = "Majority"
+)
+
+// ApplicationReplicationSpec selects the replications of an application and its desired role
+type ApplicationReplicationSpec struct {
+	// Selector selects the UnifiedVolumeReplications of the application in the
+	// ApplicationReplication's namespace
+	// +kubebuilder:validation:Required
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// ReplicationState is the role the application's volumes should have. Changing it
+	// switches the members one at a time in PromotionOrder, waiting for each to reach the
+	// role before the next. Empty leaves the roles of the members alone.
+	// +kubebuilder:validation:Enum=source;replica
+	// +optional
+	ReplicationState ReplicationState `json:"replicationState,omitempty"`
+
+	// PromotionOrder names the members to promote first, in order, for example the database
+	// before the services using it. Members not listed are promoted after them, in name
+	// order. Demotions run in the reverse order.
+	// +optional
+	PromotionOrder []string `json:"promotionOrder,omitempty"`
+
+	// HealthAggregation is how the application's health is derived from its members'. WorstOf,
+	// the default, lets any unhealthy member mark the application unhealthy.
+	// +kubebuilder:default=WorstOf
+	// +optional
+	HealthAggregation HealthAggregationPolicy `json:"healthAggregation,omitempty"`
+
+	// MemberWeights maps member names to their weight in Weighted health aggregation, for
+	// example 10 for the database and 1 for a cache. Members not listed weigh 1, and members
+	// weighing 0 or less do not count.
+	// +optional
+	MemberWeights map[string]int32 `json:"memberWeights,omitempty"`
+}
+
+// HealthPolicy returns the health aggregation policy, WorstOf when unset
+func (s *ApplicationReplicationSpec) HealthPolicy() HealthAggregationPolicy {
+	if s.HealthAggregation == "" {
+		return HealthAggregationWorstOf
+	}
+	return s.HealthAggregation
+}
+
+// MemberWeight returns the weight of a member in Weighted health aggregation
+func (s *ApplicationReplicationSpec) MemberWeight(name string) int32 {
+	if weight, ok := s.MemberWeights[name]; ok {
+		return max(weight, 0)
+	}
+	return 1
+}
+
+// ApplicationMemberStatus is the rolled-up status of one member replication
+type ApplicationMemberStatus struct {
+	// Name of the UnifiedVolumeReplication
+	Name string `json:"name"`
+
+	// State is the replication state last observed on the backend
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// Health of the member, read from its conditions
+	Health ApplicationHealth `json:"health"`
+
+	// Weight of the member in Weighted health aggregation
+	// +optional
+	Weight int32 `json:"weight,omitempty"`
+
+	// RPOCompliant is true when the member has synced within its RPO
+	RPOCompliant bool `json:"rpoCompliant"`
+
+	// FailoverReady is true when the member can be promoted: its initial sync has completed
+	// and it is not in split-brain
+	FailoverReady bool `json:"failoverReady"`
+
+	// Message explains why the member is not healthy, compliant or ready
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ApplicationReplicationStatus rolls up the status of the application's replications
+type ApplicationReplicationStatus struct {
+	// Conditions represent the latest available observations of the application's replication
+	// +optional
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// ObservedGeneration reflects the generation of the most recently observed spec
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Health is the health of the members, aggregated with HealthPolicy
+	// +optional
+	Health ApplicationHealth `json:"health,omitempty"`
+
+	// HealthPolicy is the aggregation policy Health was derived with
+	// +optional
+	HealthPolicy HealthAggregationPolicy `json:"healthPolicy,omitempty"`
+
+	// HealthBasis explains how Health was derived, e.g. which member decided it or the share
+	// of the weight at each health
+	// +optional
+	HealthBasis string `json:"healthBasis,omitempty"`
+
+	// RPOCompliant is true while every member is within its RPO
+	RPOCompliant bool `json:"rpoCompliant"`
+
+	// FailoverReady is true when every member can be promoted
+	FailoverReady bool `json:"failoverReady"`
+
+	// Members reports each selected replication, in promotion order
+	// +optional
+	Members []ApplicationMemberStatus `json:"members,omitempty"`
+
+	// CurrentStep is the member a role change is switching, or the member it last switched
+	// while it waits out the grace period before the next one
+	// +optional
+	CurrentStep string `json:"currentStep,omitempty"`
+
+	// GracePeriodEnd is when the grace period after CurrentStep ends and the next member is
+	// switched; set only while waiting it out
+	// +optional
+	GracePeriodEnd *metav1.Time `json:"gracePeriodEnd,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Namespaced,shortName=apprep
+//+kubebuilder:printcolumn:name="State",type="string",JSONPath=".spec.replicationState"
+//+kubebuilder:printcolumn:name="Health",type="string",JSONPath=".status.health"
+//+kubebuilder:printcolumn:name="RPO Compliant",type="boolean",JSONPath=".status.rpoCompliant"
+//+kubebuilder:printcolumn:name="Failover Ready",type="boolean",JSONPath=".status.failoverReady"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ApplicationReplication groups the replications of an application, rolls up their status
+// and switches their roles together
+type ApplicationReplication struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ApplicationReplicationSpec   `json:"spec,omitempty"`
+	Status ApplicationReplicationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ApplicationReplicationList contains a list of ApplicationReplication
+type ApplicationReplicationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ApplicationReplication `json:"items"`
+}
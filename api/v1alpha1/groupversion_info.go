@@ -37,4 +37,7 @@ var (
 
 func init() {
 	SchemeBuilder.Register(&UnifiedVolumeReplication{}, &UnifiedVolumeReplicationList{})
+	SchemeBuilder.Register(&ApplicationReplication{}, &ApplicationReplicationList{})
+	SchemeBuilder.Register(&ReplicationTemplate{}, &ReplicationTemplateList{})
+	SchemeBuilder.Register(&NamespacePromotion{}, &NamespacePromotionList{})
 }
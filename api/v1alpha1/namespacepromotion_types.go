@@ -0,0 +1,90 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NamespacePromotionSpec describes an emergency promotion of every replica in a namespace
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="spec is immutable, create a new NamespacePromotion instead"
+type NamespacePromotionSpec struct {
+	// Force promotes replicas that fail the per-replication safety checks: a planned
+	// operation in progress, split-brain, or an initial sync that has not completed
+	// +optional
+	Force bool `json:"force,omitempty"`
+}
+
+// NamespacePromotionStatus reports the progress of a NamespacePromotion
+type NamespacePromotionStatus struct {
+	// StartTime is when the replicas were switched to the source role
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when every promoted replica was first observed as a source
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Total is the number of replicas the request promotes
+	Total int32 `json:"total"`
+
+	// Promoted is the number of them observed as sources
+	Promoted int32 `json:"promoted"`
+
+	// InProgress is the number of them being promoted
+	InProgress int32 `json:"inProgress"`
+
+	// Queued is the number of them waiting for the concurrent failover limit
+	Queued int32 `json:"queued"`
+
+	// Skipped maps the replicas left out of the request to the reason
+	// +optional
+	Skipped map[string]string `json:"skipped,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Namespaced,shortName=nsprom
+//+kubebuilder:printcolumn:name="Force",type="boolean",JSONPath=".spec.force"
+//+kubebuilder:printcolumn:name="Total",type="integer",JSONPath=".status.total"
+//+kubebuilder:printcolumn:name="Promoted",type="integer",JSONPath=".status.promoted"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// NamespacePromotion is a one-shot request to promote every replica UnifiedVolumeReplication
+// in its namespace, for when the source site is down. Creating it starts the promotions;
+// its status reports their progress.
+type NamespacePromotion struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NamespacePromotionSpec   `json:"spec,omitempty"`
+	Status NamespacePromotionStatus `json:"status,omitempty"`
+}
+
+// Completed reports whether every replica of the request has been promoted
+func (p *NamespacePromotion) Completed() bool {
+	return p.Status.StartTime != nil && p.Status.Promoted == p.Status.Total
+}
+
+//+kubebuilder:object:root=true
+
+// NamespacePromotionList contains a list of NamespacePromotion
+type NamespacePromotionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NamespacePromotion `json:"items"`
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ReplicationTemplateSpec selects PVCs and describes the replication each of them gets
+type ReplicationTemplateSpec struct {
+	// Selector selects the PersistentVolumeClaims to replicate in the ReplicationTemplate's
+	// namespace
+	// +kubebuilder:validation:Required
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// Template is the replication generated for every selected PVC
+	// +kubebuilder:validation:Required
+	Template ReplicationSpecTemplate `json:"template"`
+}
+
+// ReplicationSpecTemplate is the spec of the UnifiedVolumeReplications a ReplicationTemplate
+// generates, without the volume mapping, which is derived from each PVC: the PVC is the
+// source and its name is the destination volume handle
+type ReplicationSpecTemplate struct {
+	// Labels are set on every generated replication, for example for an
+	// ApplicationReplication to select them
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// SourceEndpoint defines the source replication endpoint
+	// +kubebuilder:validation:Required
+	SourceEndpoint Endpoint `json:"sourceEndpoint"`
+
+	// DestinationEndpoint defines the destination replication endpoint
+	// +kubebuilder:validation:Required
+	DestinationEndpoint Endpoint `json:"destinationEndpoint"`
+
+	// DestinationNamespace is the namespace of the destination volumes; the
+	// ReplicationTemplate's namespace when empty
+	// +optional
+	DestinationNamespace string `json:"destinationNamespace,omitempty"`
+
+	// ReplicationState is the replication state of newly generated UVRs. Changing it does
+	// not affect existing ones, whose state can change independently, e.g. on failover.
+	// +kubebuilder:validation:Required
+	ReplicationState ReplicationState `json:"replicationState"`
+
+	// ReplicationMode defines the replication consistency mode. When omitted, the default
+	// of the selected backend is used and reported in status.effectiveConfig.
+	// +optional
+	ReplicationMode ReplicationMode `json:"replicationMode,omitempty"`
+
+	// Schedule defines the replication scheduling configuration
+	// +kubebuilder:validation:Required
+	Schedule Schedule `json:"schedule"`
+
+	// Extensions for vendor-specific configurations
+	// +optional
+	Extensions *Extensions `json:"extensions,omitempty"`
+}
+
+// ReplicationTemplateStatus reports the replications a ReplicationTemplate generated
+type ReplicationTemplateStatus struct {
+	// Conditions represent the latest available observations of the template
+	// +optional
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// ObservedGeneration reflects the generation of the most recently observed spec
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ReplicationCount is the number of replications generated from the template
+	ReplicationCount int32 `json:"replicationCount"`
+
+	// Replications names the generated replications, sorted
+	// +optional
+	Replications []string `json:"replications,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Namespaced,shortName=reptmpl
+//+kubebuilder:printcolumn:name="State",type="string",JSONPath=".spec.template.replicationState"
+//+kubebuilder:printcolumn:name="Replications",type="integer",JSONPath=".status.replicationCount"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ReplicationTemplate generates and maintains a UnifiedVolumeReplication for every PVC its
+// selector matches, and deletes those whose PVC no longer matches
+type ReplicationTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ReplicationTemplateSpec   `json:"spec,omitempty"`
+	Status ReplicationTemplateStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ReplicationTemplateList contains a list of ReplicationTemplate
+type ReplicationTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ReplicationTemplate `json:"items"`
+}
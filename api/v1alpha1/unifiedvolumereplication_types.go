@@ -18,9 +18,13 @@ package v1alpha1
 
 import (
 	"fmt"
+	"math"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -58,7 +62,7 @@ const (
 )
 
 // ScheduleMode defines the replication scheduling mode
-// +kubebuilder:validation:Enum=continuous;interval
+// +kubebuilder:validation:Enum=continuous;interval;auto
 type ScheduleMode string
 
 const (
@@ -66,8 +70,46 @@ const (
 	ScheduleModeContinuous ScheduleMode = "continuous"
 	// ScheduleModeInterval provides interval-based replication
 	ScheduleModeInterval ScheduleMode = "interval"
+	// ScheduleModeAuto lets the operator pick the sync interval needed to meet the RPO
+	ScheduleModeAuto ScheduleMode = "auto"
 )
 
+// FeatureGate names an experimental behavior that can be enabled per replication
+type FeatureGate string
+
+const (
+	// FeatureGateAdaptiveSchedule lets auto schedule mode shorten the sync interval further
+	// while the replica lags behind, instead of only accounting for the sync duration
+	FeatureGateAdaptiveSchedule FeatureGate = "AdaptiveSchedule"
+)
+
+// KnownFeatureGates lists the feature gates accepted in spec.featureGates. The CEL rule on
+// spec.featureGates must match FeatureGatesValidationRule; a test checks both the marker and
+// the CRD.
+var KnownFeatureGates = []FeatureGate{
+	FeatureGateAdaptiveSchedule,
+}
+
+// DefaultFeatureGates holds the state of gates not listed in spec.featureGates. Gates are off
+// by default, except behaviors that shipped enabled before they became gated.
+var DefaultFeatureGates = map[FeatureGate]bool{
+	FeatureGateAdaptiveSchedule: true,
+}
+
+// FeatureGatesValidationRule returns the CEL rule and message restricting spec.featureGates
+// to KnownFeatureGates
+func FeatureGatesValidationRule() (rule, message string) {
+	quoted := make([]string, 0, len(KnownFeatureGates))
+	names := make([]string, 0, len(KnownFeatureGates))
+	for _, gate := range KnownFeatureGates {
+		quoted = append(quoted, "'"+string(gate)+"'")
+		names = append(names, string(gate))
+	}
+	rule = fmt.Sprintf("self.all(gate, gate in [%s])", strings.Join(quoted, ", "))
+	message = "unknown feature gate, must be one of: " + strings.Join(names, ", ")
+	return rule, message
+}
+
 // Endpoint defines a replication endpoint with cluster, region, and storage information
 type Endpoint struct {
 	// Cluster identifier for the Kubernetes cluster
@@ -97,6 +139,25 @@ type VolumeSource struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinLength=1
 	Namespace string `json:"namespace" yaml:"namespace"`
+
+	// BaselineSnapshot seeds the initial sync from a VolumeSnapshot of the source PVC, so the
+	// replica starts from a consistent point in time. Only honored by backends that accept a
+	// data source for the replication (currently Ceph).
+	// +optional
+	BaselineSnapshot *BaselineSnapshot `json:"baselineSnapshot,omitempty" yaml:"baselineSnapshot,omitempty"`
+}
+
+// BaselineSnapshot configures the VolumeSnapshot used as the initial replication baseline
+type BaselineSnapshot struct {
+	// Name of an existing VolumeSnapshot of the source PVC, in the source namespace. When
+	// empty the operator snapshots the source PVC itself and deletes that snapshot once the
+	// initial sync has completed.
+	// +optional
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// VolumeSnapshotClassName is the snapshot class used when the operator creates the snapshot
+	// +optional
+	VolumeSnapshotClassName *string `json:"volumeSnapshotClassName,omitempty" yaml:"volumeSnapshotClassName,omitempty"`
 }
 
 // VolumeDestination defines the destination volume information
@@ -110,6 +171,18 @@ type VolumeDestination struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinLength=1
 	Namespace string `json:"namespace" yaml:"namespace"`
+
+	// AutoCreate provisions the destination volume, sized from the source PVC, when it
+	// does not exist yet. Volumes created this way are removed when the replication is deleted.
+	// Defaults to false, which expects the destination volume to be pre-created.
+	// +optional
+	AutoCreate *bool `json:"autoCreate,omitempty" yaml:"autoCreate,omitempty"`
+
+	// ReserveCapacity reserves the source volume size on the destination storage class
+	// before the initial sync, so concurrent replications cannot overcommit it. Only
+	// enforced where the destination CSI driver publishes storage capacity.
+	// +optional
+	ReserveCapacity *bool `json:"reserveCapacity,omitempty" yaml:"reserveCapacity,omitempty"`
 }
 
 // VolumeMapping defines the source to destination volume mapping
@@ -126,18 +199,31 @@ type VolumeMapping struct {
 // Schedule defines replication scheduling configuration
 type Schedule struct {
 	// RPO (Recovery Point Objective) - maximum acceptable data loss duration
-	// +kubebuilder:validation:Pattern=`^[0-9]+(s|m|h|d)$`
+	// +kubebuilder:validation:Pattern=`^([0-9]+d([0-9]+h)?([0-9]+m)?([0-9]+s)?|[0-9]+h([0-9]+m)?([0-9]+s)?|[0-9]+m([0-9]+s)?|[0-9]+s)$`
 	// +optional
 	Rpo string `json:"rpo,omitempty" yaml:"rpo,omitempty"`
 
 	// RTO (Recovery Time Objective) - maximum acceptable recovery time
-	// +kubebuilder:validation:Pattern=`^[0-9]+(s|m|h|d)$`
+	// +kubebuilder:validation:Pattern=`^([0-9]+d([0-9]+h)?([0-9]+m)?([0-9]+s)?|[0-9]+h([0-9]+m)?([0-9]+s)?|[0-9]+m([0-9]+s)?|[0-9]+s)$`
 	// +optional
 	Rto string `json:"rto,omitempty" yaml:"rto,omitempty"`
 
+	// MaxLag is the replication lag beyond which applications writing to the source should
+	// pause, signalled by the WritesShouldPause condition. The operator does not pause
+	// writes itself. Unset disables the signal.
+	// +kubebuilder:validation:Pattern=`^([0-9]+d([0-9]+h)?([0-9]+m)?([0-9]+s)?|[0-9]+h([0-9]+m)?([0-9]+s)?|[0-9]+m([0-9]+s)?|[0-9]+s)$`
+	// +optional
+	MaxLag string `json:"maxLag,omitempty" yaml:"maxLag,omitempty"`
+
 	// Mode defines the scheduling approach
 	// +kubebuilder:validation:Required
 	Mode ScheduleMode `json:"mode" yaml:"mode"`
+
+	// Delegate hands the sync schedule to the backend's native scheduler, configured from
+	// the RPO. The operator then only monitors the replication. Requires interval mode and a
+	// backend with a native scheduler.
+	// +optional
+	Delegate bool `json:"delegate,omitempty" yaml:"delegate,omitempty"`
 }
 
 // CephExtensions defines Ceph-specific configuration
@@ -190,13 +276,19 @@ type UnifiedVolumeReplicationSpec struct {
 	// +kubebuilder:validation:Required
 	VolumeMapping VolumeMapping `json:"volumeMapping" yaml:"volumeMapping"`
 
+	// GroupMembers lists additional volumes replicated together with VolumeMapping as one
+	// consistency group. Members can be added or removed without disrupting the others.
+	// +optional
+	GroupMembers []VolumeMapping `json:"groupMembers,omitempty" yaml:"groupMembers,omitempty"`
+
 	// ReplicationState defines the desired replication state
 	// +kubebuilder:validation:Required
 	ReplicationState ReplicationState `json:"replicationState" yaml:"replicationState"`
 
-	// ReplicationMode defines the replication consistency mode
-	// +kubebuilder:validation:Required
-	ReplicationMode ReplicationMode `json:"replicationMode" yaml:"replicationMode"`
+	// ReplicationMode defines the replication consistency mode. When omitted, the default
+	// of the selected backend is used and reported in status.effectiveConfig.
+	// +optional
+	ReplicationMode ReplicationMode `json:"replicationMode,omitempty" yaml:"replicationMode,omitempty"`
 
 	// Schedule defines the replication scheduling configuration
 	// +kubebuilder:validation:Required
@@ -205,8 +297,82 @@ type UnifiedVolumeReplicationSpec struct {
 	// Extensions for vendor-specific configurations
 	// +optional
 	Extensions *Extensions `json:"extensions,omitempty" yaml:"extensions,omitempty"`
+
+	// Priority orders reconciles when many replications are queued at once, for example after
+	// an operator restart. Higher values are reconciled first when the operator runs with
+	// priority ordering.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	Priority int32 `json:"priority,omitempty" yaml:"priority,omitempty"`
+
+	// FeatureGates enables or disables experimental behaviors for this replication only. Gates
+	// not listed keep their default.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self.all(gate, gate in ['AdaptiveSchedule'])",message="unknown feature gate, must be one of: AdaptiveSchedule"
+	FeatureGates map[FeatureGate]bool `json:"featureGates,omitempty" yaml:"featureGates,omitempty"`
+
+	// Deactivated stops the replication's syncs and leaves its backend relationship dormant
+	// without deleting it, as a reversible step before deleting the replication. Unlike a
+	// pause, a dormant replication is not ready for failover. Clearing it resumes syncing.
+	// +optional
+	Deactivated bool `json:"deactivated,omitempty" yaml:"deactivated,omitempty"`
+
+	// CredentialsSecretRef names a Secret in the replication's namespace holding the backend
+	// credentials. The replication waits while the Secret is missing and is reconciled again
+	// when it changes, so rotated credentials are picked up promptly.
+	// +optional
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty" yaml:"credentialsSecretRef,omitempty"`
+
+	// ProfileRef names a ConfigMap in the replication's namespace holding a replication
+	// profile. The replication waits while the ConfigMap is missing and is reconciled again
+	// when it changes.
+	// +optional
+	ProfileRef *corev1.LocalObjectReference `json:"profileRef,omitempty" yaml:"profileRef,omitempty"`
+
+	// Qos caps the bandwidth of the replication's syncs during recurring time windows, so
+	// replication can share links with production traffic
+	// +optional
+	Qos *QosSpec `json:"qos,omitempty" yaml:"qos,omitempty"`
+}
+
+// QosSpec caps the bandwidth of syncs during time windows. Outside the windows syncs are
+// not capped.
+type QosSpec struct {
+	// BandwidthWindows are the windows with a bandwidth cap. When windows overlap, the first
+	// one listed applies.
+	// +kubebuilder:validation:MinItems=1
+	BandwidthWindows []BandwidthWindow `json:"bandwidthWindows" yaml:"bandwidthWindows"`
+
+	// TimeZone is the IANA time zone of the window times, e.g. "Europe/Berlin". UTC when empty.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty" yaml:"timeZone,omitempty"`
+}
+
+// BandwidthWindow is a daily time window during which syncs are capped
+type BandwidthWindow struct {
+	// Start is the time of day the window opens, as HH:MM
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start" yaml:"start"`
+
+	// End is the time of day the window closes, as HH:MM. A window ending before its start
+	// closes the next day.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end" yaml:"end"`
+
+	// Days are the days of the week the window opens on. Every day when empty.
+	// +optional
+	Days []Weekday `json:"days,omitempty" yaml:"days,omitempty"`
+
+	// Limit is the bandwidth cap in Kbps, Mbps or Gbps, e.g. "50Mbps"
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?(Kbps|Mbps|Gbps)$`
+	Limit string `json:"limit" yaml:"limit"`
 }
 
+// Weekday is a day of the week
+// +kubebuilder:validation:Enum=Mon;Tue;Wed;Thu;Fri;Sat;Sun
+type Weekday string
+
 // UnifiedVolumeReplicationStatus defines the observed state of UnifiedVolumeReplication
 type UnifiedVolumeReplicationStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
@@ -222,9 +388,591 @@ type UnifiedVolumeReplicationStatus struct {
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
+	// CurrentState is the replication state the backend last reported. Changes of
+	// spec.replicationState are validated against it. Empty until the backend reports one of
+	// the replication states.
+	// +optional
+	// +kubebuilder:validation:Enum=source;replica;promoting;demoting;syncing;failed
+	CurrentState ReplicationState `json:"currentState,omitempty"`
+
 	// DiscoveredBackends lists the storage backends discovered in the cluster
 	// +optional
 	DiscoveredBackends []BackendInfo `json:"discoveredBackends,omitempty"`
+
+	// PrimaryCluster is the cluster currently holding the primary copy, as reported by the backend
+	// +optional
+	PrimaryCluster string `json:"primaryCluster,omitempty"`
+
+	// PrimarySite is the region or site currently holding the primary copy
+	// +optional
+	PrimarySite string `json:"primarySite,omitempty"`
+
+	// Direction reports whether data flows from the source endpoint to the destination
+	// endpoint as configured (Forward), or the other way round after a failover (Reverse)
+	// +optional
+	Direction ReplicationDirection `json:"direction,omitempty"`
+
+	// AdoptedResource is the backend resource taken over through the adopt annotation. The
+	// UVR keeps managing it under this name once the annotation is removed.
+	// +optional
+	AdoptedResource string `json:"adoptedResource,omitempty"`
+
+	// ConsistencyLevel reports what the latest recovery point would recover to:
+	// ApplicationConsistent when it was taken while the application was quiesced,
+	// CrashConsistent otherwise, Unknown when there is no recovery point yet
+	// +optional
+	// +kubebuilder:validation:Enum=ApplicationConsistent;CrashConsistent;Unknown
+	ConsistencyLevel string `json:"consistencyLevel,omitempty"`
+
+	// LastReconcile reports the outcome of the most recent reconcile
+	// +optional
+	LastReconcile *LastReconcile `json:"lastReconcile,omitempty"`
+
+	// Diff summarizes where the state observed on the backend differs from the spec, for
+	// example "desired state=source, observed=replica; promotion pending". Empty once the
+	// backend has converged on the spec.
+	// +optional
+	Diff string `json:"diff,omitempty"`
+
+	// StateHistory records the most recent changes of the observed replication state,
+	// oldest first. Older entries are dropped once the list is full.
+	// +optional
+	// +kubebuilder:validation:MaxItems=20
+	StateHistory []StateHistoryEntry `json:"stateHistory,omitempty"`
+
+	// ComputedSchedule reports the sync interval chosen by the operator when the
+	// schedule mode is auto
+	// +optional
+	ComputedSchedule *ComputedSchedule `json:"computedSchedule,omitempty"`
+
+	// Schedule is the resolved sync schedule: the cadence in effect, the last sync and when
+	// the next one is due
+	// +optional
+	Schedule *ScheduleStatus `json:"schedule,omitempty"`
+
+	// Qos reports the bandwidth cap in force and when it changes next
+	// +optional
+	Qos *QosStatus `json:"qos,omitempty"`
+
+	// EstablishmentDuration is how long the replication took to complete its initial sync,
+	// measured from creation or from the start of the latest full resync
+	// +optional
+	EstablishmentDuration *metav1.Duration `json:"establishmentDuration,omitempty"`
+
+	// EstablishmentStartTime is when the latest full resync restarted establishment. When
+	// empty, establishment is measured from the creation timestamp.
+	// +optional
+	EstablishmentStartTime *metav1.Time `json:"establishmentStartTime,omitempty"`
+
+	// GroupMembers reports the outcome of the latest membership change for each group member
+	// +optional
+	GroupMembers []GroupMemberStatus `json:"groupMembers,omitempty"`
+
+	// EffectiveConfig summarizes the configuration in force after backend selection,
+	// translation and operator-chosen defaults, as applied by the latest successful reconcile
+	// +optional
+	EffectiveConfig *EffectiveConfig `json:"effectiveConfig,omitempty"`
+
+	// ReplicaReadable reports whether the replica can currently be mounted read-only, as
+	// last verified by the backend adapter. Empty while this side is the source or the
+	// backend cannot report it.
+	// +optional
+	ReplicaReadable *ReplicaReadability `json:"replicaReadable,omitempty"`
+
+	// BackendUsage reports how many replication relationships the backend holds against
+	// its limit. Empty when the backend does not report limits.
+	// +optional
+	BackendUsage *BackendUsage `json:"backendUsage,omitempty"`
+
+	// LastSuccessfulOperations records when each backend operation last succeeded on this
+	// replication, as reported by the backend adapter
+	// +optional
+	LastSuccessfulOperations *OperationTimestamps `json:"lastSuccessfulOperations,omitempty"`
+
+	// DataTransfer estimates how much data the replication has sent to the destination, for
+	// attributing egress cost. Empty until the backend reports sync progress.
+	// +optional
+	DataTransfer *DataTransfer `json:"dataTransfer,omitempty"`
+
+	// RPO is how the replication meets spec.schedule.rpo: the lag since the last sync and the
+	// compliance classified against the operator's thresholds. Empty until a sync time or a
+	// compliance is reported.
+	// +optional
+	RPO *RPOStatus `json:"rpo,omitempty"`
+
+	// RPOCompliancePercent is how well the last sync meets spec.schedule.rpo: 100 while it is
+	// within the RPO, falling as the last sync ages past it (50 at twice the RPO). Empty
+	// without an RPO or a sync time.
+	// +optional
+	RPOCompliancePercent *float64 `json:"rpoCompliancePercent,omitempty"`
+
+	// RPOViolated is true while the last sync is older than spec.schedule.rpo
+	// +optional
+	RPOViolated bool `json:"rpoViolated,omitempty"`
+
+	// LastSyncTime is when the backend last reported a completed sync. Empty until the first
+	// sync and for backends that do not report sync times.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// Explanation traces the decisions of the latest reconcile while the explain annotation
+	// is set; removed with the annotation
+	// +optional
+	Explanation *ReconcileExplanation `json:"explanation,omitempty"`
+
+	// FailoverQueuePosition is the position of the UVR's promotion among the failovers
+	// waiting for the operator's concurrent failover limit, 1 being next; zero when not queued
+	// +optional
+	FailoverQueuePosition int32 `json:"failoverQueuePosition,omitempty"`
+
+	// RecoveryObjectives reports the schedule's RPO, RTO and max lag as written in the spec and
+	// in canonical form, so equal objectives compare equal across UVRs
+	// +optional
+	RecoveryObjectives *RecoveryObjectives `json:"recoveryObjectives,omitempty"`
+
+	// Relationship reports how long the backend replication relationship has existed and how
+	// often it was rebuilt. Empty until the relationship is first established.
+	// +optional
+	Relationship *RelationshipStatus `json:"relationship,omitempty"`
+
+	// CancelledOperation records the operation last cancelled with the cancel-operation
+	// annotation. The replication is held at the role it had before the operation until the
+	// spec changes.
+	// +optional
+	CancelledOperation *CancelledOperation `json:"cancelledOperation,omitempty"`
+
+	// SplitBrainResolution records the survivor selected with the surviving-primary
+	// annotation. When the peer survived, the local volume is held as a replica until the
+	// spec changes.
+	// +optional
+	SplitBrainResolution *SplitBrainResolution `json:"splitBrainResolution,omitempty"`
+
+	// CapacityReservation is the destination capacity held for this replication until its
+	// destination volume is bound or the initial sync completes. Persisted so reservations
+	// survive operator restarts.
+	// +optional
+	CapacityReservation *CapacityReservation `json:"capacityReservation,omitempty"`
+}
+
+// CapacityReservation is destination capacity reserved ahead of the initial sync
+type CapacityReservation struct {
+	// StorageClass is the destination storage class the capacity is reserved on
+	StorageClass string `json:"storageClass"`
+
+	// Bytes is the reserved size, taken from the source volume
+	Bytes int64 `json:"bytes"`
+
+	// Time is when the reservation was first recorded
+	Time metav1.Time `json:"time"`
+}
+
+// CancelledOperation acknowledges a cancel-operation request
+type CancelledOperation struct {
+	// ObservedGeneration is the spec generation whose operation was cancelled. The
+	// cancellation holds while the spec stays at this generation.
+	ObservedGeneration int64 `json:"observedGeneration"`
+
+	// RequestedState is the replicationState the cancelled operation was moving to
+	// +optional
+	RequestedState ReplicationState `json:"requestedState,omitempty"`
+
+	// HeldState is the role the replication is held at instead: the last source or replica
+	// role observed on the backend. Empty when none was observed, in which case no backend
+	// operation is issued until the spec changes.
+	// +optional
+	HeldState ReplicationState `json:"heldState,omitempty"`
+
+	// Reason is the value of the cancel-operation annotation
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Time is when the cancellation was acknowledged
+	Time metav1.Time `json:"time"`
+}
+
+// SplitBrainResolution records how a dual-primary situation was resolved
+type SplitBrainResolution struct {
+	// Survivor is the endpoint cluster that keeps the primary role
+	Survivor string `json:"survivor"`
+
+	// ObservedGeneration is the spec generation the resolution was made at. It holds while
+	// the spec stays at this generation.
+	ObservedGeneration int64 `json:"observedGeneration"`
+
+	// HeldState is the role the local volume is held at instead of spec.replicationState:
+	// replica when the peer survived. Empty when the local volume survived, in which case
+	// the peer is demoted and resynced by the operator managing it.
+	// +optional
+	HeldState ReplicationState `json:"heldState,omitempty"`
+
+	// Time is when the resolution was made
+	Time metav1.Time `json:"time"`
+}
+
+// RecoveryObjectives holds the durations of the schedule, each unset when the spec omits it
+type RecoveryObjectives struct {
+	// Rpo is the recovery point objective
+	// +optional
+	Rpo *NormalizedDuration `json:"rpo,omitempty"`
+
+	// Rto is the recovery time objective
+	// +optional
+	Rto *NormalizedDuration `json:"rto,omitempty"`
+
+	// MaxLag is the replication lag beyond which writes should pause
+	// +optional
+	MaxLag *NormalizedDuration `json:"maxLag,omitempty"`
+}
+
+// NormalizedDuration is a schedule duration as written and in canonical form
+type NormalizedDuration struct {
+	// Raw is the duration as written in the spec, e.g. "60m"
+	Raw string `json:"raw"`
+
+	// Normalized is the duration with the largest units first and zero components omitted,
+	// e.g. "1h"
+	Normalized string `json:"normalized"`
+}
+
+// RelationshipStatus tracks the age and churn of the backend replication relationship.
+// Frequent churn points to an unstable replication worth investigating.
+type RelationshipStatus struct {
+	// CreationTime is when the operator first established the relationship on the backend
+	CreationTime metav1.Time `json:"creationTime"`
+
+	// ChurnCount counts how often the relationship was recreated or fully resynced since
+	// it was established
+	// +optional
+	ChurnCount int32 `json:"churnCount,omitempty"`
+
+	// LastChurnTime is when the relationship was last recreated or fully resynced
+	// +optional
+	LastChurnTime *metav1.Time `json:"lastChurnTime,omitempty"`
+
+	// LastChurnReason is why the relationship was last rebuilt: Recreated or FullResync
+	// +optional
+	LastChurnReason string `json:"lastChurnReason,omitempty"`
+}
+
+// Age returns how long the relationship has existed at now
+func (r *RelationshipStatus) Age(now time.Time) time.Duration {
+	if r == nil || r.CreationTime.IsZero() {
+		return 0
+	}
+	return max(now.Sub(r.CreationTime.Time), 0)
+}
+
+// GroupMemberPhase describes whether a group member is part of the backend group
+// +kubebuilder:validation:Enum=Active;AddFailed;RemoveFailed
+type GroupMemberPhase string
+
+const (
+	// GroupMemberActive indicates the member was added to the backend group
+	GroupMemberActive GroupMemberPhase = "Active"
+	// GroupMemberAddFailed indicates adding the member failed; it is retried on the next reconcile
+	GroupMemberAddFailed GroupMemberPhase = "AddFailed"
+	// GroupMemberRemoveFailed indicates removing the member failed; it is still part of the group
+	GroupMemberRemoveFailed GroupMemberPhase = "RemoveFailed"
+)
+
+// GroupMemberStatus reports the membership of one volume in the replication group
+type GroupMemberStatus struct {
+	// PvcName is the source PVC of the member
+	PvcName string `json:"pvcName"`
+
+	// Namespace of the source PVC
+	Namespace string `json:"namespace"`
+
+	// VolumeHandle is the destination volume of the member
+	VolumeHandle string `json:"volumeHandle"`
+
+	// Phase is the outcome of the latest membership change
+	Phase GroupMemberPhase `json:"phase"`
+
+	// Message provides details when the membership change failed
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastTransitionTime is when the phase last changed
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+}
+
+// EffectiveConfig is the replication configuration actually applied to the backend
+type EffectiveConfig struct {
+	// Backend is the storage backend selected for the replication
+	Backend string `json:"backend"`
+
+	// ReplicationMode is the replication mode in force: the requested mode, or the default
+	// of the backend when the spec omits it
+	ReplicationMode ReplicationMode `json:"replicationMode"`
+
+	// ReplicationModeDefaulted is true when ReplicationMode is the backend default
+	// +optional
+	ReplicationModeDefaulted bool `json:"replicationModeDefaulted,omitempty"`
+
+	// BackendMode is the replication mode as configured on the backend, e.g. the Trident
+	// replication policy
+	// +optional
+	BackendMode string `json:"backendMode,omitempty"`
+
+	// ScheduleMode is the scheduling mode of the replication
+	ScheduleMode ScheduleMode `json:"scheduleMode"`
+
+	// SyncInterval is the sync interval configured on the backend; in auto mode this is
+	// the interval computed by the operator
+	// +optional
+	SyncInterval string `json:"syncInterval,omitempty"`
+
+	// Rpo is the recovery point objective the replication is monitored against
+	Rpo string `json:"rpo"`
+
+	// ReplicationClass is the backend replication class in use, when the backend has one
+	// +optional
+	ReplicationClass string `json:"replicationClass,omitempty"`
+
+	// Extensions lists backend-specific settings the operator applied
+	// +optional
+	Extensions map[string]string `json:"extensions,omitempty"`
+
+	// ActiveFeatures lists the adapter features this replication relies on, derived from the
+	// spec and the features the backend supports, e.g. AsyncReplication and AutoResync
+	// +optional
+	ActiveFeatures []string `json:"activeFeatures,omitempty"`
+}
+
+// ReplicaReadability reports whether the replica volume is mountable read-only
+type ReplicaReadability struct {
+	// Readable is true when the replica can be mounted read-only without promoting it
+	Readable bool `json:"readable"`
+
+	// Reason explains the result, e.g. why the backend does not expose the replica
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// LastVerifiedTime is when the adapter last verified the replica
+	LastVerifiedTime metav1.Time `json:"lastVerifiedTime"`
+}
+
+// BackendUsage reports the backend's replication relationship usage against its limit
+type BackendUsage struct {
+	// Replications is the number of replication relationships on the backend
+	Replications int64 `json:"replications"`
+
+	// MaxReplications is the number of replication relationships the backend allows
+	MaxReplications int64 `json:"maxReplications"`
+
+	// LastCheckedTime is when the usage was last read from the backend
+	LastCheckedTime metav1.Time `json:"lastCheckedTime"`
+}
+
+// DataTransfer estimates the data a replication has sent to the destination
+type DataTransfer struct {
+	// BytesTransferred is the cumulative number of bytes synced to the destination, summed
+	// from the progress of each sync. Full resyncs add to it rather than resetting it.
+	BytesTransferred int64 `json:"bytesTransferred"`
+
+	// ObservedSyncedBytes is the synced byte count of the current sync when it was last read,
+	// which the next progress report is measured against
+	ObservedSyncedBytes int64 `json:"observedSyncedBytes"`
+
+	// LastUpdateTime is when BytesTransferred last grew
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// ReconcileExplanation is a human-readable trace of the decisions made by one reconcile
+type ReconcileExplanation struct {
+	// ObservedGeneration is the spec generation that was reconciled
+	ObservedGeneration int64 `json:"observedGeneration"`
+
+	// Time is when the reconcile completed
+	Time metav1.Time `json:"time"`
+
+	// Steps are the decisions in the order they were made, ending with the outcome
+	Steps []string `json:"steps"`
+}
+
+// RPOStatus is how the replication meets its RPO, with the compliance classified against
+// the operator's thresholds
+type RPOStatus struct {
+	// Lag is the time since the last sync, e.g. "4m30s". Empty before the first sync.
+	// +optional
+	Lag string `json:"lag,omitempty"`
+
+	// CompliancePercent is how well the replication meets the RPO, e.g. "97.50": the share of
+	// recent syncs that met it for backends that report one, otherwise 100 while the last
+	// sync is within the RPO and the RPO divided by the lag beyond it. Empty without either.
+	// +optional
+	CompliancePercent string `json:"compliancePercent,omitempty"`
+
+	// ComplianceSource is Backend when the backend reported the compliance and LastSync when
+	// the operator derived it from the lag
+	// +optional
+	ComplianceSource string `json:"complianceSource,omitempty"`
+
+	// WarningThreshold is the compliance below which the replication is Degraded. Empty when
+	// no thresholds are configured for the backend.
+	// +optional
+	WarningThreshold string `json:"warningThreshold,omitempty"`
+
+	// CriticalThreshold is the compliance below which the replication is Unhealthy
+	// +optional
+	CriticalThreshold string `json:"criticalThreshold,omitempty"`
+
+	// Health is Healthy, Degraded or Unhealthy as classified from CompliancePercent alone, or
+	// empty when no thresholds are configured
+	// +optional
+	Health string `json:"health,omitempty"`
+
+	// LastUpdateTime is when the operator last evaluated the RPO
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// OperationTimestamps records when each backend operation last succeeded
+type OperationTimestamps struct {
+	// Create is when the backend replication resource was last created
+	// +optional
+	Create *metav1.Time `json:"create,omitempty"`
+
+	// Update is when the backend replication resource was last updated
+	// +optional
+	Update *metav1.Time `json:"update,omitempty"`
+
+	// Promote is when the replica was last promoted to source
+	// +optional
+	Promote *metav1.Time `json:"promote,omitempty"`
+
+	// Demote is when the source was last demoted to replica
+	// +optional
+	Demote *metav1.Time `json:"demote,omitempty"`
+
+	// Resync is when a resync was last triggered
+	// +optional
+	Resync *metav1.Time `json:"resync,omitempty"`
+}
+
+// ComputedSchedule is the sync cadence derived from the target RPO and observed sync durations
+type ComputedSchedule struct {
+	// Interval is the sync interval currently applied to the backend
+	Interval string `json:"interval"`
+
+	// ObservedSyncDuration is the most recent sync duration reported by the backend
+	// +optional
+	ObservedSyncDuration *metav1.Duration `json:"observedSyncDuration,omitempty"`
+
+	// LastUpdateTime is when the interval was last changed
+	LastUpdateTime metav1.Time `json:"lastUpdateTime"`
+}
+
+// ScheduleStatus is the sync schedule as the operator resolved it from the spec
+type ScheduleStatus struct {
+	// Mode is the schedule mode of the spec
+	Mode ScheduleMode `json:"mode"`
+
+	// Cadence is the sync interval in effect, normalized as in recoveryObjectives: the RPO,
+	// or the computed interval in auto mode. Empty for continuous replication.
+	// +optional
+	Cadence string `json:"cadence,omitempty"`
+
+	// Cron is the cron expression the backend's native scheduler runs syncs on, when the
+	// schedule is delegated to one that takes cron schedules
+	// +optional
+	Cron string `json:"cron,omitempty"`
+
+	// Delegated is true when the backend's native scheduler runs the syncs
+	// +optional
+	Delegated bool `json:"delegated,omitempty"`
+
+	// LastSyncTime is when the backend last completed a sync
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// NextSyncTime is when the next sync is due: the next cron run for a cron schedule, one
+	// cadence after the last sync otherwise. In the past while a sync is overdue. Unset for
+	// continuous replication and before the first sync.
+	// +optional
+	NextSyncTime *metav1.Time `json:"nextSyncTime,omitempty"`
+}
+
+// QosStatus reports the bandwidth cap applied to the backend
+type QosStatus struct {
+	// BandwidthLimit is the cap in force, empty while syncs are not capped
+	// +optional
+	BandwidthLimit string `json:"bandwidthLimit,omitempty"`
+
+	// Window is the window the cap comes from, as start-end, e.g. "09:00-17:00"
+	// +optional
+	Window string `json:"window,omitempty"`
+
+	// NextChangeTime is when the cap changes next. Unset when it never changes.
+	// +optional
+	NextChangeTime *metav1.Time `json:"nextChangeTime,omitempty"`
+}
+
+// StateHistoryEntry records a change of the observed replication state
+type StateHistoryEntry struct {
+	// Timestamp is when the change was observed
+	Timestamp metav1.Time `json:"timestamp"`
+
+	// From is the previously observed state; empty for the first observation
+	// +optional
+	From string `json:"from,omitempty"`
+
+	// To is the newly observed state
+	To string `json:"to"`
+
+	// Reason explains why the state changed
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// ReplicationDirection describes which way data flows between the endpoints
+// +kubebuilder:validation:Enum=Forward;Reverse
+type ReplicationDirection string
+
+const (
+	// ReplicationDirectionForward indicates the source endpoint holds the primary copy
+	ReplicationDirectionForward ReplicationDirection = "Forward"
+	// ReplicationDirectionReverse indicates the destination endpoint holds the primary copy
+	ReplicationDirectionReverse ReplicationDirection = "Reverse"
+)
+
+// ReconcileResult summarizes how far the desired state has been realized
+// +kubebuilder:validation:Enum=Synced;Progressing;Degraded
+type ReconcileResult string
+
+const (
+	// ReconcileResultSynced indicates the backend reports the desired state as healthy
+	ReconcileResultSynced ReconcileResult = "Synced"
+	// ReconcileResultProgressing indicates the desired state was applied but is not yet realized
+	ReconcileResultProgressing ReconcileResult = "Progressing"
+	// ReconcileResultDegraded indicates the reconcile failed or the backend is unhealthy
+	ReconcileResultDegraded ReconcileResult = "Degraded"
+)
+
+// LastReconcile is a machine-readable report of a reconcile, intended for GitOps health checks
+type LastReconcile struct {
+	// ObservedGeneration is the spec generation that was reconciled
+	ObservedGeneration int64 `json:"observedGeneration"`
+
+	// Result of the reconcile
+	Result ReconcileResult `json:"result"`
+
+	// RealizedState is the replication state reported by the backend
+	// +optional
+	RealizedState string `json:"realizedState,omitempty"`
+
+	// RealizedMode is the replication mode reported by the backend
+	// +optional
+	RealizedMode string `json:"realizedMode,omitempty"`
+
+	// Time is when the reconcile completed
+	Time metav1.Time `json:"time"`
+
+	// Message provides details about the result
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // BackendInfo provides information about discovered storage backends
@@ -271,13 +1019,169 @@ type UnifiedVolumeReplicationList struct {
 	Items           []UnifiedVolumeReplication `json:"items"`
 }
 
+// SyncInterval returns the sync interval backends should be configured with. In auto mode
+// this is the interval computed by the operator, falling back to the RPO until one is known.
+func (uvr *UnifiedVolumeReplication) SyncInterval() string {
+	if uvr.Spec.Schedule.Mode == ScheduleModeAuto && uvr.Status.ComputedSchedule != nil && uvr.Status.ComputedSchedule.Interval != "" {
+		return uvr.Status.ComputedSchedule.Interval
+	}
+	return uvr.Spec.Schedule.Rpo
+}
+
+// FeatureGateEnabled reports whether an experimental behavior is enabled for this replication,
+// falling back to the gate's default when spec.featureGates does not list it
+func (uvr *UnifiedVolumeReplication) FeatureGateEnabled(gate FeatureGate) bool {
+	if enabled, ok := uvr.Spec.FeatureGates[gate]; ok {
+		return enabled
+	}
+	return DefaultFeatureGates[gate]
+}
+
 // Validation methods and helpers
 
+// ScheduleDurationPattern matches the durations accepted for the RPO, RTO and max lag: whole
+// days, hours, minutes and seconds, largest unit first, e.g. "30s", "90m", "1h30m" or "1d12h"
+const ScheduleDurationPattern = `^([0-9]+d([0-9]+h)?([0-9]+m)?([0-9]+s)?|[0-9]+h([0-9]+m)?([0-9]+s)?|[0-9]+m([0-9]+s)?|[0-9]+s)$`
+
 var (
-	// timePatternRegex validates time duration patterns like "5m", "1h", "30s", "1d"
-	timePatternRegex = regexp.MustCompile(`^[0-9]+(s|m|h|d)$`)
+	// timePatternRegex validates time duration patterns like "5m", "1h", "90m", "1h30m", "1d"
+	timePatternRegex = regexp.MustCompile(ScheduleDurationPattern)
+
+	// scheduleDurationComponentRegex splits a schedule duration into its number and unit pairs
+	scheduleDurationComponentRegex = regexp.MustCompile(`([0-9]+)([dhms])`)
 )
 
+// scheduleDurationUnits are the units of a schedule duration, largest first
+var scheduleDurationUnits = []struct {
+	suffix string
+	unit   time.Duration
+}{
+	{"d", 24 * time.Hour},
+	{"h", time.Hour},
+	{"m", time.Minute},
+	{"s", time.Second},
+}
+
+// ParseScheduleDuration parses a duration matching ScheduleDurationPattern
+func ParseScheduleDuration(value string) (time.Duration, error) {
+	if !timePatternRegex.MatchString(value) {
+		return 0, fmt.Errorf("invalid duration %q, expected e.g. '30s', '90m', '1h30m' or '1d'", value)
+	}
+
+	var total time.Duration
+	for _, component := range scheduleDurationComponentRegex.FindAllStringSubmatch(value, -1) {
+		n, err := strconv.ParseInt(component[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", value, err)
+		}
+		for _, u := range scheduleDurationUnits {
+			if u.suffix != component[2] {
+				continue
+			}
+			if n > int64((math.MaxInt64-total)/u.unit) {
+				return 0, fmt.Errorf("invalid duration %q: out of range", value)
+			}
+			total += time.Duration(n) * u.unit
+		}
+	}
+	return total, nil
+}
+
+// NormalizeScheduleDuration returns the canonical form of a schedule duration, so equivalent
+// values compare equal: largest units first, zero components omitted, e.g. "60m" becomes
+// "1h", "90m" becomes "1h30m" and "24h" becomes "1d". A zero duration is "0s".
+func NormalizeScheduleDuration(value string) (string, error) {
+	remaining, err := ParseScheduleDuration(value)
+	if err != nil {
+		return "", err
+	}
+	if remaining == 0 {
+		return "0s", nil
+	}
+
+	var normalized strings.Builder
+	for _, u := range scheduleDurationUnits {
+		if n := remaining / u.unit; n > 0 {
+			fmt.Fprintf(&normalized, "%d%s", n, u.suffix)
+			remaining -= n * u.unit
+		}
+	}
+	return normalized.String(), nil
+}
+
+// BandwidthLimitPattern matches a bandwidth cap: a number with a unit of Kbps, Mbps or Gbps
+const BandwidthLimitPattern = `^[0-9]+(\.[0-9]+)?(Kbps|Mbps|Gbps)$`
+
+var (
+	bandwidthLimitRegex = regexp.MustCompile(BandwidthLimitPattern)
+
+	// timeOfDayRegex matches a time of day as HH:MM
+	timeOfDayRegex = regexp.MustCompile(`^([01][0-9]|2[0-3]):([0-5][0-9])$`)
+)
+
+// bandwidthUnits are the bits per second of each bandwidth unit
+var bandwidthUnits = map[string]float64{"Kbps": 1e3, "Mbps": 1e6, "Gbps": 1e9}
+
+// weekdays maps the days of a bandwidth window to time.Weekday
+var weekdays = map[Weekday]time.Weekday{
+	"Sun": time.Sunday, "Mon": time.Monday, "Tue": time.Tuesday, "Wed": time.Wednesday,
+	"Thu": time.Thursday, "Fri": time.Friday, "Sat": time.Saturday,
+}
+
+// ParseBandwidthLimit returns the bits per second of a limit matching BandwidthLimitPattern
+func ParseBandwidthLimit(limit string) (int64, error) {
+	if !bandwidthLimitRegex.MatchString(limit) {
+		return 0, fmt.Errorf("invalid bandwidth limit %q, expected e.g. '500Kbps', '50Mbps' or '1.5Gbps'", limit)
+	}
+	unit := limit[len(limit)-4:]
+	value, err := strconv.ParseFloat(limit[:len(limit)-4], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid bandwidth limit %q: %w", limit, err)
+	}
+	bitsPerSecond := value * bandwidthUnits[unit]
+	if bitsPerSecond < 1 || bitsPerSecond > math.MaxInt64/2 {
+		return 0, fmt.Errorf("invalid bandwidth limit %q: out of range", limit)
+	}
+	return int64(bitsPerSecond), nil
+}
+
+// ParseTimeOfDay returns the minutes since midnight of a time of day given as HH:MM
+func ParseTimeOfDay(value string) (int, error) {
+	match := timeOfDayRegex.FindStringSubmatch(value)
+	if match == nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", value)
+	}
+	hours, _ := strconv.Atoi(match[1])
+	minutes, _ := strconv.Atoi(match[2])
+	return hours*60 + minutes, nil
+}
+
+// Location returns the time zone of the window times
+func (q *QosSpec) Location() (*time.Location, error) {
+	if q.TimeZone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(q.TimeZone)
+}
+
+// OpensOn reports whether the window opens on a day of the week
+func (w BandwidthWindow) OpensOn(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if weekdays[d] == day {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the window as start-end
+func (w BandwidthWindow) String() string {
+	return w.Start + "-" + w.End
+}
+
 // ValidateSpec performs comprehensive validation of the UnifiedVolumeReplication spec
 func (uvr *UnifiedVolumeReplication) ValidateSpec() error {
 	if err := uvr.validateEndpoints(); err != nil {
@@ -292,10 +1196,22 @@ func (uvr *UnifiedVolumeReplication) ValidateSpec() error {
 		return err
 	}
 
+	if err := uvr.validateGroupMembers(); err != nil {
+		return err
+	}
+
 	if err := uvr.validateExtensions(); err != nil {
 		return err
 	}
 
+	if err := uvr.validateFeatureGates(); err != nil {
+		return err
+	}
+
+	if err := uvr.validateQos(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -383,6 +1299,11 @@ func (uvr *UnifiedVolumeReplication) validateVolumeMapping() error {
 		return fmt.Errorf("volume mapping destination namespace '%s' is not a valid Kubernetes name", mapping.Destination.Namespace)
 	}
 
+	// Auto-created destination volumes are named after the volume handle
+	if mapping.Destination.AutoCreate != nil && *mapping.Destination.AutoCreate && !isValidKubernetesName(mapping.Destination.VolumeHandle) {
+		return fmt.Errorf("volume mapping destination volumeHandle '%s' must be a valid Kubernetes name when autoCreate is enabled", mapping.Destination.VolumeHandle)
+	}
+
 	return nil
 }
 
@@ -392,12 +1313,17 @@ func (uvr *UnifiedVolumeReplication) validateSchedule() error {
 
 	// Validate RPO pattern if provided
 	if schedule.Rpo != "" && !timePatternRegex.MatchString(schedule.Rpo) {
-		return fmt.Errorf("schedule RPO '%s' does not match required pattern (e.g., '5m', '1h', '30s', '1d')", schedule.Rpo)
+		return fmt.Errorf("schedule RPO '%s' does not match required pattern (e.g., '5m', '1h30m', '30s', '1d')", schedule.Rpo)
 	}
 
 	// Validate RTO pattern if provided
 	if schedule.Rto != "" && !timePatternRegex.MatchString(schedule.Rto) {
-		return fmt.Errorf("schedule RTO '%s' does not match required pattern (e.g., '5m', '1h', '30s', '1d')", schedule.Rto)
+		return fmt.Errorf("schedule RTO '%s' does not match required pattern (e.g., '5m', '1h30m', '30s', '1d')", schedule.Rto)
+	}
+
+	// Validate max lag pattern if provided
+	if schedule.MaxLag != "" && !timePatternRegex.MatchString(schedule.MaxLag) {
+		return fmt.Errorf("schedule maxLag '%s' does not match required pattern (e.g., '5m', '1h30m', '30s', '1d')", schedule.MaxLag)
 	}
 
 	// Mode-specific validation
@@ -406,10 +1332,38 @@ func (uvr *UnifiedVolumeReplication) validateSchedule() error {
 		if schedule.Rpo == "" {
 			return fmt.Errorf("schedule RPO is required when mode is 'interval'")
 		}
+	case ScheduleModeAuto:
+		if schedule.Rpo == "" {
+			return fmt.Errorf("schedule RPO is required when mode is 'auto'")
+		}
 	case ScheduleModeContinuous:
 		// For continuous mode, RPO/RTO are optional as they represent target objectives
 	default:
-		return fmt.Errorf("invalid schedule mode '%s', must be one of: continuous, interval", schedule.Mode)
+		return fmt.Errorf("invalid schedule mode '%s', must be one of: continuous, interval, auto", schedule.Mode)
+	}
+
+	// Only interval schedules are run by the backend; auto mode is driven by the operator
+	if schedule.Delegate && schedule.Mode != ScheduleModeInterval {
+		return fmt.Errorf("schedule delegate requires mode 'interval', got '%s'", schedule.Mode)
+	}
+
+	return nil
+}
+
+// validateGroupMembers ensures every group member names a distinct source PVC
+func (uvr *UnifiedVolumeReplication) validateGroupMembers() error {
+	primary := uvr.Spec.VolumeMapping.Source
+	seen := map[string]bool{primary.Namespace + "/" + primary.PvcName: true}
+
+	for i, member := range uvr.Spec.GroupMembers {
+		if member.Source.PvcName == "" || member.Destination.VolumeHandle == "" {
+			return fmt.Errorf("group member %d must set source pvcName and destination volumeHandle", i)
+		}
+		key := member.Source.Namespace + "/" + member.Source.PvcName
+		if seen[key] {
+			return fmt.Errorf("group member %d: source PVC %s is already replicated by this group", i, key)
+		}
+		seen[key] = true
 	}
 
 	return nil
@@ -447,6 +1401,64 @@ func (uvr *UnifiedVolumeReplication) validateExtensions() error {
 	return nil
 }
 
+// validateFeatureGates rejects feature gates this version does not know about
+func (uvr *UnifiedVolumeReplication) validateFeatureGates() error {
+	for gate := range uvr.Spec.FeatureGates {
+		known := false
+		for _, knownGate := range KnownFeatureGates {
+			if gate == knownGate {
+				known = true
+				break
+			}
+		}
+		if !known {
+			names := make([]string, 0, len(KnownFeatureGates))
+			for _, knownGate := range KnownFeatureGates {
+				names = append(names, string(knownGate))
+			}
+			return fmt.Errorf("unknown feature gate '%s', must be one of: %s", gate, strings.Join(names, ", "))
+		}
+	}
+
+	return nil
+}
+
+// validateQos validates the bandwidth windows: their times, days and limits, and the time zone
+func (uvr *UnifiedVolumeReplication) validateQos() error {
+	qos := uvr.Spec.Qos
+	if qos == nil {
+		return nil
+	}
+	if len(qos.BandwidthWindows) == 0 {
+		return fmt.Errorf("qos must define at least one bandwidth window")
+	}
+	if _, err := qos.Location(); err != nil {
+		return fmt.Errorf("qos timeZone '%s' is not a known time zone: %w", qos.TimeZone, err)
+	}
+	for i, window := range qos.BandwidthWindows {
+		start, err := ParseTimeOfDay(window.Start)
+		if err != nil {
+			return fmt.Errorf("qos bandwidth window %d start: %w", i, err)
+		}
+		end, err := ParseTimeOfDay(window.End)
+		if err != nil {
+			return fmt.Errorf("qos bandwidth window %d end: %w", i, err)
+		}
+		if start == end {
+			return fmt.Errorf("qos bandwidth window %d must not start and end at the same time (%s)", i, window.Start)
+		}
+		for _, day := range window.Days {
+			if _, ok := weekdays[day]; !ok {
+				return fmt.Errorf("qos bandwidth window %d day '%s' must be one of Mon, Tue, Wed, Thu, Fri, Sat, Sun", i, day)
+			}
+		}
+		if _, err := ParseBandwidthLimit(window.Limit); err != nil {
+			return fmt.Errorf("qos bandwidth window %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
 // validateCephExtensions validates Ceph-specific configuration
 func validateCephExtensions(ceph *CephExtensions) error {
 	if ceph.MirroringMode != nil {
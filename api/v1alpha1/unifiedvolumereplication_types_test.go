@@ -177,11 +177,13 @@ func TestScheduleMode_Constants(t *testing.T) {
 	}{
 		{"continuous mode", ScheduleModeContinuous},
 		{"interval mode", ScheduleModeInterval},
+		{"auto mode", ScheduleModeAuto},
 	}
 
 	validModes := []ScheduleMode{
 		ScheduleModeContinuous,
 		ScheduleModeInterval,
+		ScheduleModeAuto,
 	}
 
 	for _, tt := range tests {
@@ -17,9 +17,13 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestUnifiedVolumeReplication_ValidateSpec(t *testing.T) {
@@ -267,6 +271,17 @@ func TestValidateSchedule(t *testing.T) {
 			wantErr:  true,
 			errMsg:   "schedule RPO is required when mode is 'interval'",
 		},
+		{
+			name:     "valid auto mode",
+			schedule: Schedule{Mode: ScheduleModeAuto, Rpo: "15m"},
+			wantErr:  false,
+		},
+		{
+			name:     "auto without RPO",
+			schedule: Schedule{Mode: ScheduleModeAuto},
+			wantErr:  true,
+			errMsg:   "schedule RPO is required when mode is 'auto'",
+		},
 		{
 			name:     "invalid RPO pattern",
 			schedule: Schedule{Mode: ScheduleModeInterval, Rpo: "invalid"},
@@ -279,6 +294,28 @@ func TestValidateSchedule(t *testing.T) {
 			wantErr:  true,
 			errMsg:   "does not match required pattern",
 		},
+		{
+			name:     "invalid max lag pattern",
+			schedule: Schedule{Mode: ScheduleModeInterval, Rpo: "15m", MaxLag: "soon"},
+			wantErr:  true,
+			errMsg:   "schedule maxLag 'soon' does not match required pattern",
+		},
+		{
+			name:     "valid max lag",
+			schedule: Schedule{Mode: ScheduleModeContinuous, MaxLag: "30s"},
+			wantErr:  false,
+		},
+		{
+			name:     "valid delegated interval",
+			schedule: Schedule{Mode: ScheduleModeInterval, Rpo: "15m", Delegate: true},
+			wantErr:  false,
+		},
+		{
+			name:     "delegated auto mode",
+			schedule: Schedule{Mode: ScheduleModeAuto, Rpo: "15m", Delegate: true},
+			wantErr:  true,
+			errMsg:   "schedule delegate requires mode 'interval'",
+		},
 		{
 			name:     "valid time patterns",
 			schedule: Schedule{Mode: ScheduleModeInterval, Rpo: "5s", Rto: "1d"},
@@ -413,6 +450,182 @@ func TestValidateCephExtensions(t *testing.T) {
 	}
 }
 
+func TestValidateFeatureGates(t *testing.T) {
+	tests := []struct {
+		name         string
+		featureGates map[FeatureGate]bool
+		wantEnabled  bool
+		wantErr      bool
+		errMsg       string
+	}{
+		{
+			name:        "no feature gates",
+			wantEnabled: true,
+			wantErr:     false,
+		},
+		{
+			name:         "known gate enabled",
+			featureGates: map[FeatureGate]bool{FeatureGateAdaptiveSchedule: true},
+			wantEnabled:  true,
+			wantErr:      false,
+		},
+		{
+			name:         "known gate disabled",
+			featureGates: map[FeatureGate]bool{FeatureGateAdaptiveSchedule: false},
+			wantEnabled:  false,
+			wantErr:      false,
+		},
+		{
+			name:         "unknown gate",
+			featureGates: map[FeatureGate]bool{"TimeTravel": true},
+			wantErr:      true,
+			errMsg:       "unknown feature gate 'TimeTravel'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uvr := &UnifiedVolumeReplication{
+				Spec: UnifiedVolumeReplicationSpec{
+					FeatureGates: tt.featureGates,
+				},
+			}
+			err := uvr.validateFeatureGates()
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.errMsg != "" {
+					assert.Contains(t, err.Error(), tt.errMsg)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantEnabled, uvr.FeatureGateEnabled(FeatureGateAdaptiveSchedule))
+			}
+		})
+	}
+}
+
+// TestFeatureGatesValidationRule keeps the CEL rule in the marker and in the CRD in step with
+// KnownFeatureGates, since the CRD is not regenerated when a gate is added
+func TestFeatureGatesValidationRule(t *testing.T) {
+	rule, message := FeatureGatesValidationRule()
+	for _, gate := range KnownFeatureGates {
+		assert.Contains(t, rule, "'"+string(gate)+"'")
+		assert.Contains(t, message, string(gate))
+	}
+
+	types, err := os.ReadFile("unifiedvolumereplication_types.go")
+	require.NoError(t, err)
+	assert.Contains(t, string(types), fmt.Sprintf("// +kubebuilder:validation:XValidation:rule=%q,message=%q", rule, message))
+
+	crd, err := os.ReadFile("../../config/crd/bases/replication.unified.io_unifiedvolumereplications.yaml")
+	require.NoError(t, err)
+	assert.Contains(t, string(crd), fmt.Sprintf("- message: '%s'\n                  rule: %s\n", message, rule))
+}
+
+func TestValidateQos(t *testing.T) {
+	businessHours := BandwidthWindow{Start: "09:00", End: "17:00", Days: []Weekday{"Mon", "Fri"}, Limit: "50Mbps"}
+	tests := []struct {
+		name    string
+		qos     *QosSpec
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "no qos",
+			wantErr: false,
+		},
+		{
+			name:    "business hours",
+			qos:     &QosSpec{BandwidthWindows: []BandwidthWindow{businessHours}, TimeZone: "Europe/Berlin"},
+			wantErr: false,
+		},
+		{
+			name:    "overnight window",
+			qos:     &QosSpec{BandwidthWindows: []BandwidthWindow{{Start: "22:00", End: "06:00", Limit: "1.5Gbps"}}},
+			wantErr: false,
+		},
+		{
+			name:    "no windows",
+			qos:     &QosSpec{},
+			wantErr: true,
+			errMsg:  "at least one bandwidth window",
+		},
+		{
+			name:    "invalid start",
+			qos:     &QosSpec{BandwidthWindows: []BandwidthWindow{{Start: "9:00", End: "17:00", Limit: "50Mbps"}}},
+			wantErr: true,
+			errMsg:  "window 0 start",
+		},
+		{
+			name:    "invalid end",
+			qos:     &QosSpec{BandwidthWindows: []BandwidthWindow{businessHours, {Start: "09:00", End: "24:00", Limit: "50Mbps"}}},
+			wantErr: true,
+			errMsg:  "window 1 end",
+		},
+		{
+			name:    "empty window",
+			qos:     &QosSpec{BandwidthWindows: []BandwidthWindow{{Start: "09:00", End: "09:00", Limit: "50Mbps"}}},
+			wantErr: true,
+			errMsg:  "must not start and end at the same time",
+		},
+		{
+			name:    "invalid day",
+			qos:     &QosSpec{BandwidthWindows: []BandwidthWindow{{Start: "09:00", End: "17:00", Days: []Weekday{"Monday"}, Limit: "50Mbps"}}},
+			wantErr: true,
+			errMsg:  "day 'Monday'",
+		},
+		{
+			name:    "invalid limit",
+			qos:     &QosSpec{BandwidthWindows: []BandwidthWindow{{Start: "09:00", End: "17:00", Limit: "50MB/s"}}},
+			wantErr: true,
+			errMsg:  "invalid bandwidth limit",
+		},
+		{
+			name:    "unknown time zone",
+			qos:     &QosSpec{BandwidthWindows: []BandwidthWindow{businessHours}, TimeZone: "Mars/Olympus"},
+			wantErr: true,
+			errMsg:  "not a known time zone",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uvr := &UnifiedVolumeReplication{Spec: UnifiedVolumeReplicationSpec{Qos: tt.qos}}
+			err := uvr.validateQos()
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseBandwidthLimit(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"500Kbps", 500_000},
+		{"50Mbps", 50_000_000},
+		{"1.5Gbps", 1_500_000_000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			bitsPerSecond, err := ParseBandwidthLimit(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, bitsPerSecond)
+		})
+	}
+
+	for _, invalid := range []string{"", "50", "50mbps", "50 Mbps", "-1Mbps", "0Kbps", "0.0001Kbps"} {
+		_, err := ParseBandwidthLimit(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
 func TestIsValidKubernetesName(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -455,7 +668,14 @@ func TestTimePatternRegex(t *testing.T) {
 		{"valid days", "1d", true},
 		{"multiple digits", "123m", true},
 		{"zero value", "0s", true},
+		{"compound hours and minutes", "1h30m", true},
+		{"compound days and hours", "1d12h", true},
+		{"compound every unit", "1d2h3m4s", true},
 		{"invalid no unit", "30", false},
+		{"invalid unit order", "30m1h", false},
+		{"invalid repeated unit", "1h1h", false},
+		{"invalid trailing digits", "1h30", false},
+		{"invalid spaces", "1h 30m", false},
 		{"invalid multiple units", "30sm", false},
 		{"invalid characters", "30x", false},
 		{"invalid format", "s30", false},
@@ -471,3 +691,59 @@ func TestTimePatternRegex(t *testing.T) {
 		})
 	}
 }
+
+func TestParseScheduleDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"30s", 30 * time.Second},
+		{"90m", 90 * time.Minute},
+		{"1h30m", 90 * time.Minute},
+		{"1d12h", 36 * time.Hour},
+		{"1d2h3m4s", 26*time.Hour + 3*time.Minute + 4*time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			duration, err := ParseScheduleDuration(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, duration)
+		})
+	}
+
+	for _, invalid := range []string{"", "1.5h", "5ms", "30m1h", "99999999999999999999d", "200000d"} {
+		_, err := ParseScheduleDuration(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestNormalizeScheduleDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"60m", "1h"},
+		{"1h", "1h"},
+		{"3600s", "1h"},
+		{"90m", "1h30m"},
+		{"1h30m", "1h30m"},
+		{"24h", "1d"},
+		{"1d0h", "1d"},
+		{"36h", "1d12h"},
+		{"0m", "0s"},
+		{"61s", "1m1s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			normalized, err := NormalizeScheduleDuration(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, normalized)
+			assert.Regexp(t, ScheduleDurationPattern, normalized, "the normalized form is itself valid")
+		})
+	}
+
+	_, err := NormalizeScheduleDuration("1 hour")
+	assert.Error(t, err)
+}
@@ -21,106 +21,861 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationMemberStatus) DeepCopyInto(out *ApplicationMemberStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationMemberStatus.
+func (in *ApplicationMemberStatus) DeepCopy() *ApplicationMemberStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationMemberStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationReplication) DeepCopyInto(out *ApplicationReplication) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationReplication.
+func (in *ApplicationReplication) DeepCopy() *ApplicationReplication {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationReplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ApplicationReplication) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationReplicationList) DeepCopyInto(out *ApplicationReplicationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ApplicationReplication, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationReplicationList.
+func (in *ApplicationReplicationList) DeepCopy() *ApplicationReplicationList {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationReplicationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ApplicationReplicationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationReplicationSpec) DeepCopyInto(out *ApplicationReplicationSpec) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	if in.PromotionOrder != nil {
+		in, out := &in.PromotionOrder, &out.PromotionOrder
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MemberWeights != nil {
+		in, out := &in.MemberWeights, &out.MemberWeights
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationReplicationSpec.
+func (in *ApplicationReplicationSpec) DeepCopy() *ApplicationReplicationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationReplicationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationReplicationStatus) DeepCopyInto(out *ApplicationReplicationStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]ApplicationMemberStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.GracePeriodEnd != nil {
+		in, out := &in.GracePeriodEnd, &out.GracePeriodEnd
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationReplicationStatus.
+func (in *ApplicationReplicationStatus) DeepCopy() *ApplicationReplicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationReplicationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackendInfo) DeepCopyInto(out *BackendInfo) {
 	*out = *in
-	if in.Capabilities != nil {
-		in, out := &in.Capabilities, &out.Capabilities
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendInfo.
+func (in *BackendInfo) DeepCopy() *BackendInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(BackendInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackendUsage) DeepCopyInto(out *BackendUsage) {
+	*out = *in
+	in.LastCheckedTime.DeepCopyInto(&out.LastCheckedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendUsage.
+func (in *BackendUsage) DeepCopy() *BackendUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(BackendUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BandwidthWindow) DeepCopyInto(out *BandwidthWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]Weekday, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BandwidthWindow.
+func (in *BandwidthWindow) DeepCopy() *BandwidthWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(BandwidthWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BaselineSnapshot) DeepCopyInto(out *BaselineSnapshot) {
+	*out = *in
+	if in.VolumeSnapshotClassName != nil {
+		in, out := &in.VolumeSnapshotClassName, &out.VolumeSnapshotClassName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BaselineSnapshot.
+func (in *BaselineSnapshot) DeepCopy() *BaselineSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(BaselineSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CancelledOperation) DeepCopyInto(out *CancelledOperation) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CancelledOperation.
+func (in *CancelledOperation) DeepCopy() *CancelledOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(CancelledOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacityReservation) DeepCopyInto(out *CapacityReservation) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityReservation.
+func (in *CapacityReservation) DeepCopy() *CapacityReservation {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacityReservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephExtensions) DeepCopyInto(out *CephExtensions) {
+	*out = *in
+	if in.MirroringMode != nil {
+		in, out := &in.MirroringMode, &out.MirroringMode
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephExtensions.
+func (in *CephExtensions) DeepCopy() *CephExtensions {
+	if in == nil {
+		return nil
+	}
+	out := new(CephExtensions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComputedSchedule) DeepCopyInto(out *ComputedSchedule) {
+	*out = *in
+	if in.ObservedSyncDuration != nil {
+		in, out := &in.ObservedSyncDuration, &out.ObservedSyncDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComputedSchedule.
+func (in *ComputedSchedule) DeepCopy() *ComputedSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(ComputedSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataTransfer) DeepCopyInto(out *DataTransfer) {
+	*out = *in
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataTransfer.
+func (in *DataTransfer) DeepCopy() *DataTransfer {
+	if in == nil {
+		return nil
+	}
+	out := new(DataTransfer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EffectiveConfig) DeepCopyInto(out *EffectiveConfig) {
+	*out = *in
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ActiveFeatures != nil {
+		in, out := &in.ActiveFeatures, &out.ActiveFeatures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EffectiveConfig.
+func (in *EffectiveConfig) DeepCopy() *EffectiveConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EffectiveConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Endpoint) DeepCopyInto(out *Endpoint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Endpoint.
+func (in *Endpoint) DeepCopy() *Endpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(Endpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Extensions) DeepCopyInto(out *Extensions) {
+	*out = *in
+	if in.Ceph != nil {
+		in, out := &in.Ceph, &out.Ceph
+		*out = new(CephExtensions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Trident != nil {
+		in, out := &in.Trident, &out.Trident
+		*out = new(TridentExtensions)
+		**out = **in
+	}
+	if in.Powerstore != nil {
+		in, out := &in.Powerstore, &out.Powerstore
+		*out = new(PowerStoreExtensions)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Extensions.
+func (in *Extensions) DeepCopy() *Extensions {
+	if in == nil {
+		return nil
+	}
+	out := new(Extensions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupMemberStatus) DeepCopyInto(out *GroupMemberStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupMemberStatus.
+func (in *GroupMemberStatus) DeepCopy() *GroupMemberStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupMemberStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LastReconcile) DeepCopyInto(out *LastReconcile) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LastReconcile.
+func (in *LastReconcile) DeepCopy() *LastReconcile {
+	if in == nil {
+		return nil
+	}
+	out := new(LastReconcile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacePromotion) DeepCopyInto(out *NamespacePromotion) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacePromotion.
+func (in *NamespacePromotion) DeepCopy() *NamespacePromotion {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacePromotion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespacePromotion) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacePromotionList) DeepCopyInto(out *NamespacePromotionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NamespacePromotion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacePromotionList.
+func (in *NamespacePromotionList) DeepCopy() *NamespacePromotionList {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacePromotionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespacePromotionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacePromotionSpec) DeepCopyInto(out *NamespacePromotionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacePromotionSpec.
+func (in *NamespacePromotionSpec) DeepCopy() *NamespacePromotionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacePromotionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacePromotionStatus) DeepCopyInto(out *NamespacePromotionStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Skipped != nil {
+		in, out := &in.Skipped, &out.Skipped
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacePromotionStatus.
+func (in *NamespacePromotionStatus) DeepCopy() *NamespacePromotionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacePromotionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NormalizedDuration) DeepCopyInto(out *NormalizedDuration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NormalizedDuration.
+func (in *NormalizedDuration) DeepCopy() *NormalizedDuration {
+	if in == nil {
+		return nil
+	}
+	out := new(NormalizedDuration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperationTimestamps) DeepCopyInto(out *OperationTimestamps) {
+	*out = *in
+	if in.Create != nil {
+		in, out := &in.Create, &out.Create
+		*out = (*in).DeepCopy()
+	}
+	if in.Update != nil {
+		in, out := &in.Update, &out.Update
+		*out = (*in).DeepCopy()
+	}
+	if in.Promote != nil {
+		in, out := &in.Promote, &out.Promote
+		*out = (*in).DeepCopy()
+	}
+	if in.Demote != nil {
+		in, out := &in.Demote, &out.Demote
+		*out = (*in).DeepCopy()
+	}
+	if in.Resync != nil {
+		in, out := &in.Resync, &out.Resync
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationTimestamps.
+func (in *OperationTimestamps) DeepCopy() *OperationTimestamps {
+	if in == nil {
+		return nil
+	}
+	out := new(OperationTimestamps)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerStoreExtensions) DeepCopyInto(out *PowerStoreExtensions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerStoreExtensions.
+func (in *PowerStoreExtensions) DeepCopy() *PowerStoreExtensions {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerStoreExtensions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QosSpec) DeepCopyInto(out *QosSpec) {
+	*out = *in
+	if in.BandwidthWindows != nil {
+		in, out := &in.BandwidthWindows, &out.BandwidthWindows
+		*out = make([]BandwidthWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QosSpec.
+func (in *QosSpec) DeepCopy() *QosSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(QosSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QosStatus) DeepCopyInto(out *QosStatus) {
+	*out = *in
+	if in.NextChangeTime != nil {
+		in, out := &in.NextChangeTime, &out.NextChangeTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QosStatus.
+func (in *QosStatus) DeepCopy() *QosStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(QosStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RPOStatus) DeepCopyInto(out *RPOStatus) {
+	*out = *in
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RPOStatus.
+func (in *RPOStatus) DeepCopy() *RPOStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RPOStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconcileExplanation) DeepCopyInto(out *ReconcileExplanation) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendInfo.
-func (in *BackendInfo) DeepCopy() *BackendInfo {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcileExplanation.
+func (in *ReconcileExplanation) DeepCopy() *ReconcileExplanation {
 	if in == nil {
 		return nil
 	}
-	out := new(BackendInfo)
+	out := new(ReconcileExplanation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephExtensions) DeepCopyInto(out *CephExtensions) {
+func (in *RecoveryObjectives) DeepCopyInto(out *RecoveryObjectives) {
 	*out = *in
-	if in.MirroringMode != nil {
-		in, out := &in.MirroringMode, &out.MirroringMode
-		*out = new(string)
+	if in.Rpo != nil {
+		in, out := &in.Rpo, &out.Rpo
+		*out = new(NormalizedDuration)
+		**out = **in
+	}
+	if in.Rto != nil {
+		in, out := &in.Rto, &out.Rto
+		*out = new(NormalizedDuration)
+		**out = **in
+	}
+	if in.MaxLag != nil {
+		in, out := &in.MaxLag, &out.MaxLag
+		*out = new(NormalizedDuration)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephExtensions.
-func (in *CephExtensions) DeepCopy() *CephExtensions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoveryObjectives.
+func (in *RecoveryObjectives) DeepCopy() *RecoveryObjectives {
 	if in == nil {
 		return nil
 	}
-	out := new(CephExtensions)
+	out := new(RecoveryObjectives)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Endpoint) DeepCopyInto(out *Endpoint) {
+func (in *RelationshipStatus) DeepCopyInto(out *RelationshipStatus) {
 	*out = *in
+	in.CreationTime.DeepCopyInto(&out.CreationTime)
+	if in.LastChurnTime != nil {
+		in, out := &in.LastChurnTime, &out.LastChurnTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Endpoint.
-func (in *Endpoint) DeepCopy() *Endpoint {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RelationshipStatus.
+func (in *RelationshipStatus) DeepCopy() *RelationshipStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Endpoint)
+	out := new(RelationshipStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Extensions) DeepCopyInto(out *Extensions) {
+func (in *ReplicaReadability) DeepCopyInto(out *ReplicaReadability) {
 	*out = *in
-	if in.Ceph != nil {
-		in, out := &in.Ceph, &out.Ceph
-		*out = new(CephExtensions)
+	in.LastVerifiedTime.DeepCopyInto(&out.LastVerifiedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaReadability.
+func (in *ReplicaReadability) DeepCopy() *ReplicaReadability {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaReadability)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationSpecTemplate) DeepCopyInto(out *ReplicationSpecTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.SourceEndpoint = in.SourceEndpoint
+	out.DestinationEndpoint = in.DestinationEndpoint
+	out.Schedule = in.Schedule
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = new(Extensions)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Trident != nil {
-		in, out := &in.Trident, &out.Trident
-		*out = new(TridentExtensions)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationSpecTemplate.
+func (in *ReplicationSpecTemplate) DeepCopy() *ReplicationSpecTemplate {
+	if in == nil {
+		return nil
 	}
-	if in.Powerstore != nil {
-		in, out := &in.Powerstore, &out.Powerstore
-		*out = new(PowerStoreExtensions)
-		**out = **in
+	out := new(ReplicationSpecTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationTemplate) DeepCopyInto(out *ReplicationTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationTemplate.
+func (in *ReplicationTemplate) DeepCopy() *ReplicationTemplate {
+	if in == nil {
+		return nil
 	}
+	out := new(ReplicationTemplate)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Extensions.
-func (in *Extensions) DeepCopy() *Extensions {
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ReplicationTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationTemplateList) DeepCopyInto(out *ReplicationTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ReplicationTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationTemplateList.
+func (in *ReplicationTemplateList) DeepCopy() *ReplicationTemplateList {
 	if in == nil {
 		return nil
 	}
-	out := new(Extensions)
+	out := new(ReplicationTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ReplicationTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationTemplateSpec) DeepCopyInto(out *ReplicationTemplateSpec) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationTemplateSpec.
+func (in *ReplicationTemplateSpec) DeepCopy() *ReplicationTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationTemplateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PowerStoreExtensions) DeepCopyInto(out *PowerStoreExtensions) {
+func (in *ReplicationTemplateStatus) DeepCopyInto(out *ReplicationTemplateStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Replications != nil {
+		in, out := &in.Replications, &out.Replications
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerStoreExtensions.
-func (in *PowerStoreExtensions) DeepCopy() *PowerStoreExtensions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationTemplateStatus.
+func (in *ReplicationTemplateStatus) DeepCopy() *ReplicationTemplateStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PowerStoreExtensions)
+	out := new(ReplicationTemplateStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -140,6 +895,61 @@ func (in *Schedule) DeepCopy() *Schedule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleStatus) DeepCopyInto(out *ScheduleStatus) {
+	*out = *in
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextSyncTime != nil {
+		in, out := &in.NextSyncTime, &out.NextSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleStatus.
+func (in *ScheduleStatus) DeepCopy() *ScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SplitBrainResolution) DeepCopyInto(out *SplitBrainResolution) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SplitBrainResolution.
+func (in *SplitBrainResolution) DeepCopy() *SplitBrainResolution {
+	if in == nil {
+		return nil
+	}
+	out := new(SplitBrainResolution)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StateHistoryEntry) DeepCopyInto(out *StateHistoryEntry) {
+	*out = *in
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StateHistoryEntry.
+func (in *StateHistoryEntry) DeepCopy() *StateHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(StateHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TridentExtensions) DeepCopyInto(out *TridentExtensions) {
 	*out = *in
@@ -219,13 +1029,42 @@ func (in *UnifiedVolumeReplicationSpec) DeepCopyInto(out *UnifiedVolumeReplicati
 	*out = *in
 	out.SourceEndpoint = in.SourceEndpoint
 	out.DestinationEndpoint = in.DestinationEndpoint
-	out.VolumeMapping = in.VolumeMapping
+	in.VolumeMapping.DeepCopyInto(&out.VolumeMapping)
+	if in.GroupMembers != nil {
+		in, out := &in.GroupMembers, &out.GroupMembers
+		*out = make([]VolumeMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	out.Schedule = in.Schedule
 	if in.Extensions != nil {
 		in, out := &in.Extensions, &out.Extensions
 		*out = new(Extensions)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make(map[FeatureGate]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ProfileRef != nil {
+		in, out := &in.ProfileRef, &out.ProfileRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Qos != nil {
+		in, out := &in.Qos, &out.Qos
+		*out = new(QosSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnifiedVolumeReplicationSpec.
@@ -255,6 +1094,118 @@ func (in *UnifiedVolumeReplicationStatus) DeepCopyInto(out *UnifiedVolumeReplica
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastReconcile != nil {
+		in, out := &in.LastReconcile, &out.LastReconcile
+		*out = new(LastReconcile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StateHistory != nil {
+		in, out := &in.StateHistory, &out.StateHistory
+		*out = make([]StateHistoryEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ComputedSchedule != nil {
+		in, out := &in.ComputedSchedule, &out.ComputedSchedule
+		*out = new(ComputedSchedule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(ScheduleStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Qos != nil {
+		in, out := &in.Qos, &out.Qos
+		*out = new(QosStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EstablishmentDuration != nil {
+		in, out := &in.EstablishmentDuration, &out.EstablishmentDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.EstablishmentStartTime != nil {
+		in, out := &in.EstablishmentStartTime, &out.EstablishmentStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.GroupMembers != nil {
+		in, out := &in.GroupMembers, &out.GroupMembers
+		*out = make([]GroupMemberStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EffectiveConfig != nil {
+		in, out := &in.EffectiveConfig, &out.EffectiveConfig
+		*out = new(EffectiveConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplicaReadable != nil {
+		in, out := &in.ReplicaReadable, &out.ReplicaReadable
+		*out = new(ReplicaReadability)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackendUsage != nil {
+		in, out := &in.BackendUsage, &out.BackendUsage
+		*out = new(BackendUsage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastSuccessfulOperations != nil {
+		in, out := &in.LastSuccessfulOperations, &out.LastSuccessfulOperations
+		*out = new(OperationTimestamps)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DataTransfer != nil {
+		in, out := &in.DataTransfer, &out.DataTransfer
+		*out = new(DataTransfer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RPO != nil {
+		in, out := &in.RPO, &out.RPO
+		*out = new(RPOStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RPOCompliancePercent != nil {
+		in, out := &in.RPOCompliancePercent, &out.RPOCompliancePercent
+		*out = new(float64)
+		**out = **in
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Explanation != nil {
+		in, out := &in.Explanation, &out.Explanation
+		*out = new(ReconcileExplanation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RecoveryObjectives != nil {
+		in, out := &in.RecoveryObjectives, &out.RecoveryObjectives
+		*out = new(RecoveryObjectives)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Relationship != nil {
+		in, out := &in.Relationship, &out.Relationship
+		*out = new(RelationshipStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CancelledOperation != nil {
+		in, out := &in.CancelledOperation, &out.CancelledOperation
+		*out = new(CancelledOperation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SplitBrainResolution != nil {
+		in, out := &in.SplitBrainResolution, &out.SplitBrainResolution
+		*out = new(SplitBrainResolution)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CapacityReservation != nil {
+		in, out := &in.CapacityReservation, &out.CapacityReservation
+		*out = new(CapacityReservation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnifiedVolumeReplicationStatus.
@@ -270,6 +1221,16 @@ func (in *UnifiedVolumeReplicationStatus) DeepCopy() *UnifiedVolumeReplicationSt
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeDestination) DeepCopyInto(out *VolumeDestination) {
 	*out = *in
+	if in.AutoCreate != nil {
+		in, out := &in.AutoCreate, &out.AutoCreate
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ReserveCapacity != nil {
+		in, out := &in.ReserveCapacity, &out.ReserveCapacity
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeDestination.
@@ -285,8 +1246,8 @@ func (in *VolumeDestination) DeepCopy() *VolumeDestination {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeMapping) DeepCopyInto(out *VolumeMapping) {
 	*out = *in
-	out.Source = in.Source
-	out.Destination = in.Destination
+	in.Source.DeepCopyInto(&out.Source)
+	in.Destination.DeepCopyInto(&out.Destination)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeMapping.
@@ -302,6 +1263,11 @@ func (in *VolumeMapping) DeepCopy() *VolumeMapping {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeSource) DeepCopyInto(out *VolumeSource) {
 	*out = *in
+	if in.BaselineSnapshot != nil {
+		in, out := &in.BaselineSnapshot, &out.BaselineSnapshot
+		*out = new(BaselineSnapshot)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeSource.
@@ -0,0 +1,78 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// urep-replay feeds a reconcile captured with --reconcile-capture-dir back through the
+// reconcile logic offline and reports whether it reaches the same decision.
+//
+// Usage:
+//
+//	urep-replay [-v] <capture.json>
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/unified-replication/operator/controllers"
+)
+
+func main() {
+	verbose := flag.Bool("v", false, "Log the replayed reconcile")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-v] <capture.json>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	capture, err := controllers.LoadReconcileCapture(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(2)
+	}
+
+	log := logr.Discard()
+	if *verbose {
+		log = zap.New(zap.UseDevMode(true))
+	}
+	decision, err := controllers.ReplayReconcile(context.Background(), capture, log)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: replay failed: %v\n", err)
+		os.Exit(2)
+	}
+
+	out, _ := json.MarshalIndent(decision, "", "  ")
+	fmt.Printf("Replay of %s/%s captured at %s\n%s\n",
+		capture.UVR.Namespace, capture.UVR.Name, capture.CapturedAt.UTC().Format("2006-01-02T15:04:05Z"), out)
+
+	if diffs := capture.Decision.Diff(decision); len(diffs) > 0 {
+		fmt.Println("Decision differs from the captured reconcile:")
+		for _, diff := range diffs {
+			fmt.Printf("  %s\n", diff)
+		}
+		os.Exit(1)
+	}
+	fmt.Println("Decision matches the captured reconcile")
+}
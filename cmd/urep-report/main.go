@@ -0,0 +1,85 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// urep-report lists the UnifiedVolumeReplications of a cluster and prints a DR readiness
+// report for audits: backend, mode, RPO target against the actual lag, last sync, phase,
+// whether the RPO is met and the estimated failover time. It only reads from the cluster.
+//
+// Usage:
+//
+//	urep-report [-format csv|json] [-namespace <namespace>] [-kubeconfig <path>]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/controllers"
+)
+
+func main() {
+	format := flag.String("format", "csv", "Report format: csv or json")
+	namespace := flag.String("namespace", "", "Only report replications in this namespace (default all namespaces)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-format csv|json] [-namespace <namespace>] [-kubeconfig <path>]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 0 || (*format != "csv" && *format != "json") {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	config, err := ctrl.GetConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(2)
+	}
+	scheme := runtime.NewScheme()
+	if err := replicationv1alpha1.AddToScheme(scheme); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(2)
+	}
+	c, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(2)
+	}
+
+	entries, err := controllers.ListDRReport(context.Background(), c, *namespace, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *format == "json" {
+		err = controllers.WriteDRReportJSON(os.Stdout, entries)
+	} else {
+		err = controllers.WriteDRReportCSV(os.Stdout, entries)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+// acquireAdapter returns the adapter for the UVR and a release function to call once the
+// reconcile is done with it. With an AdapterManager, adapters are cached across reconciles
+// and swapped when the backend's factory or configuration changes; otherwise a fresh
+// adapter is created each time.
+func (r *UnifiedVolumeReplicationReconciler) acquireAdapter(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) (adapters.ReplicationAdapter, func(), error) {
+	if r.AdapterManager == nil {
+		adapter, err := r.getAdapter(ctx, uvr, log)
+		r.setAdapterEventRecorder(adapter)
+		return adapter, func() {}, err
+	}
+
+	backend, err := r.resolveBackend(ctx, uvr, log)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	adapter, release, err := r.AdapterManager.AcquireAdapter(ctx, uvr, backend, r.Client, r.TranslationEngine)
+	if err != nil {
+		return nil, nil, err
+	}
+	r.setAdapterEventRecorder(adapter)
+	return adapter, release, nil
+}
+
+// setAdapterEventRecorder lets an adapter that records events, such as the Ceph adapter's
+// state transitions, record them on the UVRs with the reconciler's recorder
+func (r *UnifiedVolumeReplicationReconciler) setAdapterEventRecorder(adapter adapters.ReplicationAdapter) {
+	if setter, ok := adapter.(adapters.EventRecorderSetter); ok && r.Recorder != nil {
+		setter.SetEventRecorder(r.Recorder)
+	}
+}
+
+// resolveBackend picks the backend for a UVR: the engine's choice among discovered backends,
+// falling back to the spec hints used for backend partitioning
+func (r *UnifiedVolumeReplicationReconciler) resolveBackend(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) (translation.Backend, error) {
+	backends, err := r.DiscoveryEngine.DiscoverBackends(ctx)
+	if err != nil {
+		log.Error(err, "Discovery failed, falling back to extension-based selection")
+		explainf(ctx, "Backend discovery failed: %v", err)
+	} else if adapters.FeedDiscoveryResult(r.AdapterRegistry, backends); backends != nil &&
+		(len(backends.AvailableBackends) > 0 || adapters.GenericCSISupports(r.AdapterRegistry, uvr)) {
+		explainf(ctx, "Discovered backends: %v", backends.AvailableBackends)
+		backend, err := r.selectBackendViaEngine(ctx, uvr, backends.AvailableBackends, log)
+		if err == nil {
+			return backend, nil
+		}
+		if r.BackendSelectionWebhook != nil && !r.BackendSelectionWebhook.FailOpen {
+			return "", err
+		}
+	}
+
+	if partition := backendPartitionFor(uvr); partition != defaultBackendPartition {
+		explainf(ctx, "Backend %s chosen from the spec extensions and storage class", partition)
+		return translation.Backend(partition), nil
+	}
+	return "", fmt.Errorf("no backend adapter found for this configuration")
+}
+
+// registerAdapterSwapHandler records an event on the UVR whenever its cached adapter is
+// replaced after a configuration change
+func (r *UnifiedVolumeReplicationReconciler) registerAdapterSwapHandler() {
+	if r.AdapterManager == nil {
+		return
+	}
+
+	r.AdapterManager.SetSwapHandler(func(uvr *replicationv1alpha1.UnifiedVolumeReplication, backend translation.Backend) {
+		r.recordEventf(uvr, corev1.EventTypeNormal, "AdapterSwapped",
+			"Recreated %s adapter after a configuration change", backend)
+	})
+}
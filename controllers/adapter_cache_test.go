@@ -0,0 +1,73 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+func TestReconciler_AdapterSwapOnConfigChange(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	uvr := createTestUVR("test-adapter-swap", "default")
+	fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).WithStatusSubresource(uvr).Build()
+	reconciler := createTestReconciler(fakeClient, s)
+
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewMockAdapterFactory(translation.BackendTrident, adapters.DefaultMockConfig())))
+	reconciler.AdapterManager = adapters.NewAdapterManager(registry, nil)
+	reconciler.registerAdapterSwapHandler()
+
+	// The first reconcile only adds the finalizer
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+	_, _ = reconciler.Reconcile(ctx, req)
+	_, _ = reconciler.Reconcile(ctx, req)
+
+	original, ok := reconciler.AdapterManager.GetAdapter(uvr)
+	require.True(t, ok)
+	assert.True(t, original.IsHealthy())
+
+	drainEvents(reconciler.Recorder.(*record.FakeRecorder))
+
+	// Rotating the backend credentials swaps the adapter on the next reconcile
+	config := adapters.DefaultAdapterConfig(translation.BackendTrident)
+	config.CustomSettings["credentialsSecret"] = "trident-creds-v2"
+	reconciler.AdapterManager.SetBackendConfig(translation.BackendTrident, config)
+
+	_, _ = reconciler.Reconcile(ctx, req)
+
+	current, ok := reconciler.AdapterManager.GetAdapter(uvr)
+	require.True(t, ok)
+	assert.NotSame(t, original, current)
+	assert.True(t, current.IsHealthy())
+	assert.False(t, original.IsHealthy(), "the replaced adapter is cleaned up")
+
+	assert.Contains(t, drainEvents(reconciler.Recorder.(*record.FakeRecorder)),
+		"Normal AdapterSwapped Recreated trident adapter after a configuration change")
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// adoptedCondition reports whether the UVR took over an existing backend resource
+const adoptedCondition = "Adopted"
+
+// adoptBackendResource takes over the backend resource named by the adopt annotation before
+// the UVR is ensured, so the existing replication is managed instead of a new one being
+// created. Adoption happens once; later reconciles manage the resource like any other, under
+// the name recorded in status.adoptedResource even after the annotation is removed. It
+// returns false, with the Ready condition set, when the resource cannot be adopted, or when
+// it was adopted with the PreferExisting policy and still differs from the spec.
+func (r *UnifiedVolumeReplicationReconciler) adoptBackendResource(ctx context.Context, adapter adapters.ReplicationAdapter, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) bool {
+	name, ok := uvr.Annotations[adapters.AdoptAnnotation]
+	if !ok {
+		return true
+	}
+	message := fmt.Sprintf("Adopted %s backend resource %s", adapter.GetBackendType(), name)
+	if existing := r.getCondition(uvr, adoptedCondition); existing != nil &&
+		existing.Status == metav1.ConditionTrue && existing.Message == message {
+		uvr.Status.AdoptedResource = name
+		return true
+	}
+
+	adopter, ok := adapter.(adapters.ReplicationAdopter)
+	if !ok {
+		r.refuseAdoption(uvr, "AdoptionUnsupported",
+			fmt.Sprintf("Backend %s cannot adopt existing resources, remove the %s annotation", adapter.GetBackendType(), adapters.AdoptAnnotation))
+		return false
+	}
+
+	conflicts, err := adopter.AdoptReplication(ctx, uvr)
+	if err != nil {
+		log.Error(err, "Failed to adopt backend resource", "resource", name)
+		if conflict, ok := adapters.AsResourceConflictError(err); ok {
+			r.setResourceConflict(ctx, uvr, conflict, log)
+		} else if adapters.IsAdoptionConflictError(err) {
+			r.refuseAdoption(uvr, "AdoptionConflict", err.Error())
+		} else {
+			r.refuseAdoption(uvr, "AdoptionFailed", fmt.Sprintf("Failed to adopt %s: %v", name, err))
+		}
+		return false
+	}
+
+	if len(conflicts) > 0 {
+		descriptions := make([]string, 0, len(conflicts))
+		for _, conflict := range conflicts {
+			descriptions = append(descriptions, conflict.String())
+		}
+		drift := strings.Join(descriptions, ", ")
+
+		// The policy is valid, or the adapter would have refused the adoption
+		if policy, _ := adapters.AdoptConflictPolicyFor(uvr); policy == adapters.AdoptConflictPolicyPreferExisting {
+			log.Info("Adopted backend resource differs from the spec, keeping its settings", "resource", name, "drift", drift)
+			uvr.Status.AdoptedResource = name
+			r.keepAdoptedSettings(uvr, name, drift)
+			return false
+		}
+		log.Info("Adopted backend resource differs from the spec, re-applying the spec", "resource", name, "drift", drift)
+		r.recordEventf(uvr, corev1.EventTypeNormal, "AdoptionSpecApplied", "Re-applying the spec to %s: %s", name, drift)
+	}
+
+	uvr.Status.AdoptedResource = name
+	log.Info("Adopted existing backend resource", "resource", name)
+	r.recordEventf(uvr, corev1.EventTypeNormal, "Adopted", "%s", message)
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               adoptedCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "BackendResourceAdopted",
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+	return true
+}
+
+// keepAdoptedSettings reports an adopted backend resource whose settings are kept over the
+// spec. The spec is not applied until the two match, by editing either of them.
+func (r *UnifiedVolumeReplicationReconciler) keepAdoptedSettings(uvr *replicationv1alpha1.UnifiedVolumeReplication, name, drift string) {
+	message := fmt.Sprintf("Keeping the settings of adopted backend resource %s, which differ from the spec: %s", name, drift)
+	if existing := r.getCondition(uvr, "Ready"); existing == nil || existing.Message != message {
+		r.recordEventf(uvr, corev1.EventTypeWarning, "AdoptionDrift", "%s", message)
+	}
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               adoptedCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "ExistingSettingsKept",
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             "AdoptionDrift",
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+}
+
+// refuseAdoption reports a backend resource that could not be adopted
+func (r *UnifiedVolumeReplicationReconciler) refuseAdoption(uvr *replicationv1alpha1.UnifiedVolumeReplication, reason, message string) {
+	if existing := r.getCondition(uvr, "Ready"); existing == nil || existing.Reason != reason {
+		r.recordEventf(uvr, corev1.EventTypeWarning, reason, "%s", message)
+	}
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               adoptedCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+}
@@ -0,0 +1,289 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestReconciler_AdoptExistingVolumeReplication(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+	s.AddKnownTypes(schema.GroupVersion{Group: "replication.storage.openshift.io", Version: "v1alpha1"},
+		&adapters.VolumeReplication{}, &adapters.VolumeReplicationList{})
+
+	uvr := createTestUVR("test-adopt", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	uvr.Spec.Extensions = &replicationv1alpha1.Extensions{Ceph: &replicationv1alpha1.CephExtensions{}}
+	uvr.Spec.SourceEndpoint.StorageClass = "ceph-rbd"
+	uvr.Spec.DestinationEndpoint.StorageClass = "ceph-rbd"
+	uvr.Annotations = map[string]string{adapters.AdoptAnnotation: "legacy-vr"}
+
+	// A VolumeReplication created before the operator managed the volume
+	existing := &adapters.VolumeReplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "legacy-vr", Namespace: "default"},
+		Spec: adapters.VolumeReplicationSpec{
+			VolumeReplicationClass: "rbd-volumereplicationclass",
+			PvcName:                "source-pvc",
+			ReplicationState:       "secondary",
+		},
+	}
+
+	var creates []string
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(cephCRDs(), uvr, existing)...).
+		WithStatusSubresource(uvr).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				creates = append(creates, obj.GetName())
+				return c.Create(ctx, obj, opts...)
+			},
+		}).Build()
+	reconciler := createTestReconciler(c, s)
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+	for i := 0; i < 2; i++ {
+		_, err := reconciler.Reconcile(ctx, req)
+		require.NoError(t, err)
+	}
+	assert.Empty(t, creates, "the adopted VolumeReplication is managed, not recreated")
+
+	adopted := &adapters.VolumeReplication{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(existing), adopted))
+	assert.Equal(t, "unified-replication-operator", adopted.Labels["managed-by"])
+	assert.Equal(t, "default/test-adopt", adopted.Annotations[adapters.AdoptedByAnnotation])
+	assert.Equal(t, "secondary", adopted.Spec.ReplicationState)
+
+	updated := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	condition := reconciler.getCondition(updated, adoptedCondition)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, "BackendResourceAdopted", condition.Reason)
+	assert.Equal(t, "legacy-vr", updated.Status.AdoptedResource)
+
+	// Removing the annotation keeps the UVR on the adopted VolumeReplication
+	delete(updated.Annotations, adapters.AdoptAnnotation)
+	require.NoError(t, c.Update(ctx, updated))
+	for i := 0; i < 2; i++ {
+		_, err := reconciler.Reconcile(ctx, req)
+		require.NoError(t, err)
+	}
+	assert.Empty(t, creates, "no VolumeReplication of the UVR's own name is created")
+	err := c.Get(ctx, client.ObjectKey{Name: "test-adopt-vr", Namespace: "default"}, &adapters.VolumeReplication{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestReconciler_AdoptionConflict(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+	s.AddKnownTypes(schema.GroupVersion{Group: "replication.storage.openshift.io", Version: "v1alpha1"},
+		&adapters.VolumeReplication{}, &adapters.VolumeReplicationList{})
+
+	uvr := createTestUVR("test-adopt-conflict", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	uvr.Spec.Extensions = &replicationv1alpha1.Extensions{Ceph: &replicationv1alpha1.CephExtensions{}}
+	uvr.Annotations = map[string]string{adapters.AdoptAnnotation: "legacy-vr"}
+
+	// The existing VolumeReplication replicates a different PVC as the primary
+	existing := &adapters.VolumeReplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "legacy-vr", Namespace: "default"},
+		Spec: adapters.VolumeReplicationSpec{
+			VolumeReplicationClass: "rbd-volumereplicationclass",
+			PvcName:                "other-pvc",
+			ReplicationState:       "primary",
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(cephCRDs(), uvr, existing)...).WithStatusSubresource(uvr).Build()
+	reconciler := createTestReconciler(c, s)
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+	result, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, requeueDelayError, result.RequeueAfter)
+
+	updated := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	ready := reconciler.getCondition(updated, "Ready")
+	require.NotNil(t, ready)
+	assert.Equal(t, "AdoptionConflict", ready.Reason)
+	assert.Contains(t, ready.Message, `pvcName: desired "source-pvc", observed "other-pvc"`)
+	assert.Contains(t, ready.Message, `replicationState: desired "secondary", observed "primary"`)
+
+	untouched := &adapters.VolumeReplication{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(existing), untouched))
+	assert.Empty(t, untouched.Annotations[adapters.AdoptedByAnnotation], "a conflicting resource is left alone")
+}
+
+func TestReconciler_AdoptConflictPolicies(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     string
+		ready      string
+		adopted    string
+		adoptedBy  string
+		finalState string
+		pvcName    string
+	}{
+		{name: "default fails", ready: "AdoptionConflict", finalState: "primary"},
+		{name: "fail", policy: "Fail", ready: "AdoptionConflict", finalState: "primary"},
+		{name: "prefer spec", policy: "PreferSpec", adopted: "BackendResourceAdopted",
+			adoptedBy: "default/test-adopt-policy", finalState: "secondary"},
+		{name: "prefer existing", policy: "PreferExisting", ready: "AdoptionDrift", adopted: "ExistingSettingsKept",
+			adoptedBy: "default/test-adopt-policy", finalState: "primary"},
+		{name: "invalid policy", policy: "PreferBoth", ready: "AdoptionConflict", finalState: "primary"},
+		// Re-applying another PVC would recreate the VolumeReplication and lose the replication
+		{name: "prefer spec with immutable drift", policy: "PreferSpec", ready: "AdoptionConflict", finalState: "primary",
+			pvcName: "other-pvc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := createTestScheme(t)
+			require.NoError(t, apiextensionsv1.AddToScheme(s))
+			s.AddKnownTypes(schema.GroupVersion{Group: "replication.storage.openshift.io", Version: "v1alpha1"},
+				&adapters.VolumeReplication{}, &adapters.VolumeReplicationList{})
+
+			uvr := createTestUVR("test-adopt-policy", "default")
+			uvr.Finalizers = []string{unifiedReplicationFinalizer}
+			uvr.Spec.Extensions = &replicationv1alpha1.Extensions{Ceph: &replicationv1alpha1.CephExtensions{}}
+			uvr.Spec.SourceEndpoint.StorageClass = "ceph-rbd"
+			uvr.Spec.DestinationEndpoint.StorageClass = "ceph-rbd"
+			uvr.Annotations = map[string]string{adapters.AdoptAnnotation: "legacy-vr"}
+			if tt.policy != "" {
+				uvr.Annotations[adapters.AdoptConflictPolicyAnnotation] = tt.policy
+			}
+
+			// The existing VolumeReplication is the primary while the spec asks for a replica
+			pvcName := "source-pvc"
+			if tt.pvcName != "" {
+				pvcName = tt.pvcName
+			}
+			existing := &adapters.VolumeReplication{
+				ObjectMeta: metav1.ObjectMeta{Name: "legacy-vr", Namespace: "default"},
+				Spec: adapters.VolumeReplicationSpec{
+					VolumeReplicationClass: "rbd-volumereplicationclass",
+					PvcName:                pvcName,
+					ReplicationState:       "primary",
+				},
+			}
+
+			c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(cephCRDs(), uvr, existing)...).WithStatusSubresource(uvr).Build()
+			reconciler := createTestReconciler(c, s)
+
+			req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+			for i := 0; i < 2; i++ {
+				_, err := reconciler.Reconcile(ctx, req)
+				require.NoError(t, err)
+			}
+
+			updated := &replicationv1alpha1.UnifiedVolumeReplication{}
+			require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+			ready := reconciler.getCondition(updated, "Ready")
+			require.NotNil(t, ready)
+			if tt.ready != "" {
+				assert.Equal(t, tt.ready, ready.Reason)
+			} else {
+				assert.NotContains(t, []string{"AdoptionConflict", "AdoptionDrift", "AdoptionFailed"}, ready.Reason)
+			}
+			if tt.adopted != "" {
+				adopted := reconciler.getCondition(updated, adoptedCondition)
+				require.NotNil(t, adopted)
+				assert.Equal(t, metav1.ConditionTrue, adopted.Status)
+				assert.Equal(t, tt.adopted, adopted.Reason)
+			}
+			if tt.ready == "AdoptionDrift" {
+				assert.Contains(t, ready.Message, `replicationState: desired "secondary", observed "primary"`)
+			}
+
+			vr := &adapters.VolumeReplication{}
+			require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(existing), vr))
+			assert.Equal(t, tt.adoptedBy, vr.Annotations[adapters.AdoptedByAnnotation])
+			assert.Equal(t, tt.finalState, vr.Spec.ReplicationState)
+			assert.Equal(t, pvcName, vr.Spec.PvcName)
+			if tt.pvcName != "" {
+				assert.Contains(t, ready.Message, `cannot change without recreating it`)
+				assert.True(t, containsEvent(drainEvents(reconciler.Recorder.(*record.FakeRecorder)), "Warning AdoptionConflict"))
+			}
+		})
+	}
+}
+
+func TestReconciler_PreferExistingAdoptionResumesOnceSpecMatches(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+	s.AddKnownTypes(schema.GroupVersion{Group: "replication.storage.openshift.io", Version: "v1alpha1"},
+		&adapters.VolumeReplication{}, &adapters.VolumeReplicationList{})
+
+	uvr := createTestUVR("test-prefer-existing", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	uvr.Spec.Extensions = &replicationv1alpha1.Extensions{Ceph: &replicationv1alpha1.CephExtensions{}}
+	uvr.Spec.SourceEndpoint.StorageClass = "ceph-rbd"
+	uvr.Spec.DestinationEndpoint.StorageClass = "ceph-rbd"
+	uvr.Annotations = map[string]string{
+		adapters.AdoptAnnotation:               "legacy-vr",
+		adapters.AdoptConflictPolicyAnnotation: string(adapters.AdoptConflictPolicyPreferExisting),
+	}
+	existing := &adapters.VolumeReplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "legacy-vr", Namespace: "default"},
+		Spec: adapters.VolumeReplicationSpec{
+			VolumeReplicationClass: "rbd-volumereplicationclass",
+			PvcName:                "source-pvc",
+			ReplicationState:       "primary",
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(cephCRDs(), uvr, existing)...).WithStatusSubresource(uvr).Build()
+	reconciler := createTestReconciler(c, s)
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, req.NamespacedName, uvr))
+	assert.Equal(t, "AdoptionDrift", reconciler.getCondition(uvr, "Ready").Reason)
+
+	// Aligning the spec with the adopted resource lets the UVR manage it
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+	require.NoError(t, c.Update(ctx, uvr))
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	require.NoError(t, c.Get(ctx, req.NamespacedName, uvr))
+	adopted := reconciler.getCondition(uvr, adoptedCondition)
+	require.NotNil(t, adopted)
+	assert.Equal(t, "BackendResourceAdopted", adopted.Reason)
+	assert.NotEqual(t, "AdoptionDrift", reconciler.getCondition(uvr, "Ready").Reason)
+}
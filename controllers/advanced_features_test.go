@@ -155,7 +155,7 @@ func TestRetryManager(t *testing.T) {
 			InitialDelay: 1 * time.Second,
 			MaxDelay:     1 * time.Minute,
 			Multiplier:   2.0,
-			Jitter:       0,
+			JitterFactor: -1,
 		})
 
 		resourceKey := "test-backoff"
@@ -177,6 +177,38 @@ func TestRetryManager(t *testing.T) {
 		t.Logf("Delays: %v, %v, %v", delay0, delay1, delay2)
 	})
 
+	t.Run("Jitter", func(t *testing.T) {
+		strategy := &RetryStrategy{
+			InitialDelay: 1 * time.Second,
+			MaxDelay:     10 * time.Second,
+			Multiplier:   2.0,
+		}
+		rm := NewRetryManager(strategy)
+		assert.Equal(t, defaultJitterFactor, rm.jitterFactor(), "unset defaults to 0.1")
+
+		for _, factor := range []float64{0, 0.5, 1} {
+			strategy.JitterFactor = factor
+			j := rm.jitterFactor()
+			for attempts := 0; attempts <= 6; attempts++ {
+				delay := min(time.Duration(float64(time.Second)*pow(2, float64(max(attempts-1, 0)))), strategy.MaxDelay)
+				lower := time.Duration(float64(delay) * (1 - j))
+				spread := map[time.Duration]bool{}
+				for range 50 {
+					jittered := rm.backoffDelay(attempts)
+					assert.GreaterOrEqual(t, jittered, lower, "factor %v attempts %d", factor, attempts)
+					assert.LessOrEqual(t, jittered, delay, "factor %v attempts %d", factor, attempts)
+					assert.LessOrEqual(t, jittered, strategy.MaxDelay)
+					spread[jittered] = true
+				}
+				assert.Greater(t, len(spread), 1, "delays are spread, factor %v attempts %d", factor, attempts)
+			}
+		}
+
+		// A negative factor disables jitter
+		strategy.JitterFactor = -1
+		assert.Equal(t, 4*time.Second, rm.backoffDelay(3))
+	})
+
 	t.Run("WithRetry", func(t *testing.T) {
 		if testing.Short() {
 			t.Skip("Skipping retry test in short mode")
@@ -294,7 +326,7 @@ func TestAdvancedReconciliation(t *testing.T) {
 	reconciler := createTestReconciler(fakeClient, s)
 	reconciler.StateMachine = NewStateMachine()
 	reconciler.RetryManager = NewRetryManager(nil)
-	reconciler.CircuitBreaker = NewCircuitBreaker(5, 2, 1*time.Minute)
+	reconciler.CircuitBreakers = NewBackendCircuitBreakers(5, 2, 1*time.Minute)
 
 	req := reconcile.Request{
 		NamespacedName: types.NamespacedName{
@@ -0,0 +1,48 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg"
+)
+
+// reconcileAPICalls observes how many API requests each reconcile made, by backend
+var reconcileAPICalls = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name: "unified_replication_reconcile_api_calls",
+	Help: "API requests made by a reconcile, including those of the backend adapters",
+	// 1 up to 256
+	Buckets: prometheus.ExponentialBuckets(1, 2, 9),
+}, []string{"backend"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(reconcileAPICalls)
+}
+
+// recordAPICalls observes the API requests a reconcile made, and logs them by verb when
+// they exceed APICallLogThreshold so chatty reconcile paths can be found
+func (r *UnifiedVolumeReplicationReconciler) recordAPICalls(uvr *replicationv1alpha1.UnifiedVolumeReplication, calls *pkg.APICalls, log logr.Logger) {
+	total := calls.Total()
+	reconcileAPICalls.WithLabelValues(backendPartitionFor(uvr)).Observe(float64(total))
+	if r.APICallLogThreshold > 0 && total > r.APICallLogThreshold {
+		log.Info("Reconcile made many API calls", "calls", total, "threshold", r.APICallLogThreshold, "byVerb", calls.ByVerb())
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/unified-replication/operator/pkg"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestReconciler_CountsAPICalls(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+
+	uvr := createTestUVR("test-api-calls", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+
+	// Count every request reaching the API independently of the counting client
+	requests := 0
+	count := func() { requests++ }
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(tridentCRDs(""), uvr)...).
+		WithStatusSubresource(uvr).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				count()
+				return c.Get(ctx, key, obj, opts...)
+			},
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				count()
+				return c.List(ctx, list, opts...)
+			},
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				count()
+				return c.Create(ctx, obj, opts...)
+			},
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				count()
+				return c.Update(ctx, obj, opts...)
+			},
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				count()
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				count()
+				return c.Delete(ctx, obj, opts...)
+			},
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				count()
+				return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+			},
+			SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				count()
+				return c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+			},
+		}).Build()
+
+	counted := pkg.CountingClient(c)
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewTridentAdapterFactory()))
+	reconciler := createTestReconciler(counted, s)
+	reconciler.AdapterRegistry = registry
+	reconciler.ControllerEngine = pkg.NewControllerEngine(counted, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+		registry, pkg.DefaultControllerEngineConfig())
+
+	histogram := func() (uint64, float64) {
+		metric := &dto.Metric{}
+		require.NoError(t, reconcileAPICalls.WithLabelValues("trident").(prometheus.Histogram).Write(metric))
+		return metric.GetHistogram().GetSampleCount(), metric.GetHistogram().GetSampleSum()
+	}
+	observations, sum := histogram()
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.Positive(t, requests)
+
+	afterObservations, afterSum := histogram()
+	assert.Equal(t, observations+1, afterObservations, "one observation per reconcile")
+	assert.Equal(t, float64(requests), afterSum-sum, "every request of the reconcile is counted")
+
+	// Requests outside a reconcile are not counted
+	requests = 0
+	require.NoError(t, counted.Get(ctx, req.NamespacedName, uvr))
+	assert.Equal(t, 1, requests)
+	_, afterSum2 := histogram()
+	assert.Equal(t, afterSum, afterSum2)
+
+	// Counts are kept per context, by verb
+	countedCtx, calls := pkg.WithAPICallCounting(ctx)
+	require.NoError(t, counted.Get(countedCtx, req.NamespacedName, uvr))
+	require.NoError(t, counted.Status().Update(countedCtx, uvr))
+	assert.Equal(t, 2, calls.Total())
+	assert.Equal(t, map[string]int{"get": 1, "status-update": 1}, calls.ByVerb())
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+const (
+	// apiThrottledCondition is True while the API server is rejecting requests with 429
+	apiThrottledCondition = "APIThrottled"
+
+	// defaultThrottleRequeueDelay is used when a 429 carries no Retry-After
+	defaultThrottleRequeueDelay = 30 * time.Second
+
+	// maxThrottleRequeueDelay caps the Retry-After honored, so a bogus value cannot park the
+	// UVR for long
+	maxThrottleRequeueDelay = 5 * time.Minute
+)
+
+// throttleRequeueDelay returns the delay the API server asked for when err is a 429
+func throttleRequeueDelay(err error) (time.Duration, bool) {
+	if err == nil || !apierrors.IsTooManyRequests(err) {
+		return 0, false
+	}
+	delay := defaultThrottleRequeueDelay
+	if seconds, ok := apierrors.SuggestsClientDelay(err); ok && seconds > 0 {
+		delay = time.Duration(seconds) * time.Second
+	}
+	return min(delay, maxThrottleRequeueDelay), true
+}
+
+// handleAPIThrottling backs off when a reconcile step was throttled by the API server. The
+// UVR is requeued after the server's Retry-After rather than returning the error, which
+// would retry on the rate limiter's schedule and add to the load. It returns false when
+// err is not a throttling error.
+func (r *UnifiedVolumeReplicationReconciler) handleAPIThrottling(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, err error, log logr.Logger) (ctrl.Result, bool) {
+	delay, throttled := throttleRequeueDelay(err)
+	if !throttled {
+		return ctrl.Result{}, false
+	}
+	log.Info("API server is throttling requests, backing off", "retryAfter", delay, "error", err.Error())
+
+	message := fmt.Sprintf("API server throttled the request, retrying in %s: %v", delay, err)
+	if existing := r.getCondition(uvr, apiThrottledCondition); existing == nil || existing.Status != metav1.ConditionTrue {
+		r.recordEventf(uvr, corev1.EventTypeWarning, "APIThrottled", "%s", message)
+	}
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               apiThrottledCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "TooManyRequests",
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             "APIThrottled",
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+
+	r.recordFailedReconcile(uvr)
+	if err := r.Status().Update(ctx, uvr); err != nil {
+		// The status update is likely throttled too; it is retried with the reconcile
+		log.V(1).Info("Failed to update status while throttled", "error", err.Error())
+	}
+	return ctrl.Result{RequeueAfter: delay}, true
+}
+
+// clearAPIThrottled reports that requests are being accepted again
+func (r *UnifiedVolumeReplicationReconciler) clearAPIThrottled(uvr *replicationv1alpha1.UnifiedVolumeReplication) {
+	if existing := r.getCondition(uvr, apiThrottledCondition); existing == nil || existing.Status != metav1.ConditionTrue {
+		return
+	}
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               apiThrottledCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             "RequestsAccepted",
+		Message:            "The API server is accepting requests again",
+		ObservedGeneration: uvr.Generation,
+	})
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestReconciler_APIThrottling(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+
+	uvr := createTestUVR("test-api-throttling", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+
+	// The API server rejects creating the mirror relationship with a 429 and Retry-After
+	throttle := true
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(tridentCRDs(""), uvr)...).
+		WithStatusSubresource(uvr).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if u, ok := obj.(*unstructured.Unstructured); ok && throttle && u.GroupVersionKind() == adapters.TridentMirrorRelationshipGVK {
+					return apierrors.NewTooManyRequests("the server has received too many requests", 42)
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).Build()
+
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewTridentAdapterFactory()))
+	reconciler := createTestReconciler(c, s)
+	reconciler.AdapterRegistry = registry
+	reconciler.ControllerEngine = pkg.NewControllerEngine(c, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+		registry, pkg.DefaultControllerEngineConfig())
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+	result, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err, "a throttled reconcile is requeued, not failed")
+	assert.Equal(t, 42*time.Second, result.RequeueAfter, "the requeue honors Retry-After")
+
+	updated := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	throttled := reconciler.getCondition(updated, apiThrottledCondition)
+	require.NotNil(t, throttled)
+	assert.Equal(t, metav1.ConditionTrue, throttled.Status)
+	assert.Equal(t, "TooManyRequests", throttled.Reason)
+	assert.Equal(t, "APIThrottled", reconciler.getCondition(updated, "Ready").Reason)
+
+	// Once the API server accepts requests again the condition clears
+	throttle = false
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	throttled = reconciler.getCondition(updated, apiThrottledCondition)
+	require.NotNil(t, throttled)
+	assert.Equal(t, metav1.ConditionFalse, throttled.Status)
+	assert.Equal(t, "RequestsAccepted", throttled.Reason)
+}
+
+func TestThrottleRequeueDelay(t *testing.T) {
+	_, throttled := throttleRequeueDelay(apierrors.NewServiceUnavailable("unavailable"))
+	assert.False(t, throttled)
+
+	delay, throttled := throttleRequeueDelay(apierrors.NewTooManyRequests("slow down", 0))
+	assert.True(t, throttled)
+	assert.Equal(t, defaultThrottleRequeueDelay, delay, "no Retry-After")
+
+	delay, _ = throttleRequeueDelay(apierrors.NewTooManyRequests("slow down", 3600))
+	assert.Equal(t, maxThrottleRequeueDelay, delay)
+}
@@ -0,0 +1,388 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+const (
+	// roleChangeCondition is True while the members are being switched to the application's role
+	roleChangeCondition = "RoleChangeProgressing"
+
+	// roleChangePollDelay is how often a role change checks whether the member being
+	// switched has reached its new role
+	roleChangePollDelay = 10 * time.Second
+)
+
+// applicationHealthRank orders member health from best to worst for the roll-up
+var applicationHealthRank = map[replicationv1alpha1.ApplicationHealth]int{
+	replicationv1alpha1.ApplicationHealthHealthy:   0,
+	replicationv1alpha1.ApplicationHealthUnknown:   1,
+	replicationv1alpha1.ApplicationHealthDegraded:  2,
+	replicationv1alpha1.ApplicationHealthUnhealthy: 3,
+}
+
+// ApplicationReplicationReconciler rolls up the status of the UVRs an ApplicationReplication
+// selects and switches their roles together
+type ApplicationReplicationReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// StepGracePeriods maps backend names to how long a role change lets a switched member
+	// settle before switching the next; members of other backends are switched right away
+	StepGracePeriods map[string]time.Duration
+
+	// PromotionGate decides whether a member can be promoted, holding back a replication group
+	// until every volume of it has caught up; nil promotes members without the check
+	PromotionGate func(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (bool, string)
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *ApplicationReplicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&replicationv1alpha1.ApplicationReplication{}).
+		Watches(&replicationv1alpha1.UnifiedVolumeReplication{},
+			handler.EnqueueRequestsFromMapFunc(r.applicationsForReplication)).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups=replication.unified.io,resources=applicationreplications,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=replication.unified.io,resources=applicationreplications/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=replication.unified.io,resources=applicationreplications/finalizers,verbs=update
+
+// Reconcile rolls up the members' status and advances any role change
+func (r *ApplicationReplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("applicationreplication", req.NamespacedName)
+
+	app := &replicationv1alpha1.ApplicationReplication{}
+	if err := r.Get(ctx, req.NamespacedName, app); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&app.Spec.Selector)
+	if err != nil {
+		// Nothing can be selected until the spec is fixed, which triggers a new reconcile
+		apimeta.SetStatusCondition(&app.Status.Conditions, metav1.Condition{
+			Type:               "Ready",
+			Status:             metav1.ConditionFalse,
+			Reason:             "InvalidSelector",
+			Message:            err.Error(),
+			ObservedGeneration: app.Generation,
+		})
+		app.Status.ObservedGeneration = app.Generation
+		return ctrl.Result{}, r.Status().Update(ctx, app)
+	}
+
+	uvrs := &replicationv1alpha1.UnifiedVolumeReplicationList{}
+	if err := r.List(ctx, uvrs, client.InNamespace(app.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list member replications: %w", err)
+	}
+	members := applicationPromotionOrder(uvrs.Items, app.Spec.PromotionOrder)
+
+	now := time.Now()
+	app.Status.Members = make([]replicationv1alpha1.ApplicationMemberStatus, 0, len(members))
+	for _, member := range members {
+		memberStatus := applicationMemberStatus(member, now)
+		memberStatus.Weight = app.Spec.MemberWeight(member.Name)
+		app.Status.Members = append(app.Status.Members, memberStatus)
+	}
+	rollUpApplicationStatus(app.Spec.HealthPolicy(), &app.Status)
+	r.setApplicationReadyCondition(app)
+
+	requeueAfter := requeueDelaySuccess
+	if app.Spec.ReplicationState == "" {
+		apimeta.RemoveStatusCondition(&app.Status.Conditions, roleChangeCondition)
+		app.Status.CurrentStep = ""
+		app.Status.GracePeriodEnd = nil
+	} else {
+		progressing, reason, message, err := r.switchRoles(ctx, app, members, now)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		wasProgressing := apimeta.IsStatusConditionTrue(app.Status.Conditions, roleChangeCondition)
+		status := metav1.ConditionFalse
+		if progressing {
+			status = metav1.ConditionTrue
+			requeueAfter = roleChangePollDelay
+			if app.Status.GracePeriodEnd != nil {
+				requeueAfter = min(requeueAfter, max(app.Status.GracePeriodEnd.Sub(now), time.Second))
+			}
+		} else if wasProgressing {
+			log.Info("Role change completed", "state", app.Spec.ReplicationState)
+			r.Recorder.Eventf(app, corev1.EventTypeNormal, "RoleChangeCompleted", "Every member is %s", app.Spec.ReplicationState)
+		}
+		apimeta.SetStatusCondition(&app.Status.Conditions, metav1.Condition{
+			Type:               roleChangeCondition,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			ObservedGeneration: app.Generation,
+		})
+	}
+
+	app.Status.ObservedGeneration = app.Generation
+	if err := r.Status().Update(ctx, app); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update application status: %w", err)
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// switchRoles moves the members to the application's role one at a time, in promotion order
+// for a promotion and in reverse for a demotion. A member is only switched once the one
+// before it reports the new role and its backend's step grace period has passed, and a
+// promotion only starts while every member is ready for failover. Each member is promoted
+// only once the promotion gate of its replication group allows it. It reports whether the
+// change is still in progress, and why; the current step is kept in status.
+func (r *ApplicationReplicationReconciler) switchRoles(ctx context.Context, app *replicationv1alpha1.ApplicationReplication,
+	members []*replicationv1alpha1.UnifiedVolumeReplication, now time.Time) (bool, string, string, error) {
+	target := app.Spec.ReplicationState
+	if len(members) == 0 {
+		app.Status.CurrentStep = ""
+		app.Status.GracePeriodEnd = nil
+		return false, "NoMembers", "no replication matches the selector", nil
+	}
+
+	ordered := slices.Clone(members)
+	if target == replicationv1alpha1.ReplicationStateReplica {
+		slices.Reverse(ordered)
+	}
+	started := slices.ContainsFunc(ordered, func(uvr *replicationv1alpha1.UnifiedVolumeReplication) bool {
+		return uvr.Spec.ReplicationState == target
+	})
+
+	var previous *replicationv1alpha1.UnifiedVolumeReplication
+	for _, member := range ordered {
+		if member.Spec.ReplicationState == target {
+			if lastObservedState(member) == string(target) {
+				previous = member
+				continue
+			}
+			app.Status.CurrentStep = member.Name
+			app.Status.GracePeriodEnd = nil
+			return true, "InProgress", fmt.Sprintf("waiting for %s to become %s", member.Name, target), nil
+		}
+		if target == replicationv1alpha1.ReplicationStateSource && !started && !app.Status.FailoverReady {
+			return true, "NotFailoverReady", "not every member is ready for failover", nil
+		}
+		if previous != nil {
+			if remaining := r.waitStepGrace(app, previous, now); remaining > 0 {
+				return true, "GracePeriod", fmt.Sprintf("letting %s settle for %s before switching %s to %s",
+					previous.Name, remaining.Round(time.Second), member.Name, target), nil
+			}
+		}
+
+		if target == replicationv1alpha1.ReplicationStateSource && r.PromotionGate != nil {
+			if ok, message := r.PromotionGate(ctx, member); !ok {
+				app.Status.CurrentStep = member.Name
+				app.Status.GracePeriodEnd = nil
+				return true, "PromotionBlocked", fmt.Sprintf("promotion of %s blocked: %s", member.Name, message), nil
+			}
+		}
+
+		member.Spec.ReplicationState = target
+		if err := r.Update(ctx, member); err != nil {
+			return false, "", "", fmt.Errorf("failed to switch %s to %s: %w", member.Name, target, err)
+		}
+		app.Status.CurrentStep = member.Name
+		app.Status.GracePeriodEnd = nil
+		r.Recorder.Eventf(app, corev1.EventTypeNormal, "MemberRoleChanged", "Switching %s to %s", member.Name, target)
+		return true, "InProgress", fmt.Sprintf("switching %s to %s", member.Name, target), nil
+	}
+	app.Status.CurrentStep = ""
+	app.Status.GracePeriodEnd = nil
+	return false, "Completed", fmt.Sprintf("every member is %s", target), nil
+}
+
+// setApplicationReadyCondition sets Ready from the rolled-up health
+func (r *ApplicationReplicationReconciler) setApplicationReadyCondition(app *replicationv1alpha1.ApplicationReplication) {
+	condition := metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionTrue,
+		Reason:             "AllMembersReady",
+		Message:            fmt.Sprintf("%d replications are healthy", len(app.Status.Members)),
+		ObservedGeneration: app.Generation,
+	}
+	var notReady []string
+	for _, member := range app.Status.Members {
+		if member.Health != replicationv1alpha1.ApplicationHealthHealthy {
+			notReady = append(notReady, member.Name)
+		}
+	}
+	switch {
+	case len(app.Status.Members) == 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NoMembers"
+		condition.Message = "no replication matches the selector"
+	case len(notReady) > 0 && app.Status.Health == replicationv1alpha1.ApplicationHealthHealthy:
+		// Only a Weighted or Majority policy leaves the application healthy with such members
+		condition.Reason = "HealthyByPolicy"
+		condition.Message = fmt.Sprintf("healthy under %s health aggregation; not healthy: %s", app.Status.HealthPolicy, strings.Join(notReady, ", "))
+	case len(notReady) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "MembersNotReady"
+		condition.Message = fmt.Sprintf("not healthy: %s", strings.Join(notReady, ", "))
+	}
+	apimeta.SetStatusCondition(&app.Status.Conditions, condition)
+}
+
+// applicationsForReplication maps a UVR to the applications in its namespace that select it
+func (r *ApplicationReplicationReconciler) applicationsForReplication(ctx context.Context, obj client.Object) []reconcile.Request {
+	apps := &replicationv1alpha1.ApplicationReplicationList{}
+	if err := r.List(ctx, apps, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "Failed to list applications for replication", "replication", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, app := range apps.Items {
+		selector, err := metav1.LabelSelectorAsSelector(&app.Spec.Selector)
+		if err != nil || !selector.Matches(labels.Set(obj.GetLabels())) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&app)})
+	}
+	return requests
+}
+
+// applicationPromotionOrder orders the members for promotion: the ones named in order come
+// first, in that order, and the rest follow by name
+func applicationPromotionOrder(uvrs []replicationv1alpha1.UnifiedVolumeReplication, order []string) []*replicationv1alpha1.UnifiedVolumeReplication {
+	members := make([]*replicationv1alpha1.UnifiedVolumeReplication, 0, len(uvrs))
+	for i := range uvrs {
+		members = append(members, &uvrs[i])
+	}
+	position := func(name string) int {
+		if i := slices.Index(order, name); i >= 0 {
+			return i
+		}
+		return len(order)
+	}
+	sort.SliceStable(members, func(i, j int) bool {
+		pi, pj := position(members[i].Name), position(members[j].Name)
+		if pi != pj {
+			return pi < pj
+		}
+		return members[i].Name < members[j].Name
+	})
+	return members
+}
+
+// applicationMemberStatus reads a member's health, RPO compliance and failover readiness
+// from its status
+func applicationMemberStatus(uvr *replicationv1alpha1.UnifiedVolumeReplication, now time.Time) replicationv1alpha1.ApplicationMemberStatus {
+	status := replicationv1alpha1.ApplicationMemberStatus{
+		Name:         uvr.Name,
+		State:        lastObservedState(uvr),
+		Health:       applicationMemberHealth(uvr),
+		RPOCompliant: drReportEntry(uvr, now).RPOCompliant,
+	}
+
+	var problems []string
+	splitBrain := apimeta.IsStatusConditionTrue(uvr.Status.Conditions, splitBrainCondition)
+	if splitBrain {
+		problems = append(problems, "split-brain")
+	}
+	if degraded := apimeta.FindStatusCondition(uvr.Status.Conditions, degradedCondition); degraded != nil &&
+		degraded.Status == metav1.ConditionTrue && degraded.Message != "" {
+		problems = append(problems, degraded.Message)
+	} else if ready := apimeta.FindStatusCondition(uvr.Status.Conditions, "Ready"); ready != nil &&
+		ready.Status != metav1.ConditionTrue && ready.Message != "" {
+		problems = append(problems, ready.Message)
+	}
+	status.FailoverReady = !splitBrain && apimeta.IsStatusConditionTrue(uvr.Status.Conditions, "InitialSyncComplete")
+	if !status.FailoverReady && !splitBrain {
+		problems = append(problems, "initial sync has not completed")
+	}
+	if !status.RPOCompliant {
+		problems = append(problems, "not synced within the RPO")
+	}
+	status.Message = strings.Join(problems, "; ")
+	return status
+}
+
+// applicationMemberHealth reads a member's health from its conditions: split-brain and
+// backend failures make it unhealthy, any other degradation or an unready replication
+// degraded
+func applicationMemberHealth(uvr *replicationv1alpha1.UnifiedVolumeReplication) replicationv1alpha1.ApplicationHealth {
+	if apimeta.IsStatusConditionTrue(uvr.Status.Conditions, splitBrainCondition) {
+		return replicationv1alpha1.ApplicationHealthUnhealthy
+	}
+	if degraded := apimeta.FindStatusCondition(uvr.Status.Conditions, degradedCondition); degraded != nil && degraded.Status == metav1.ConditionTrue {
+		switch adapters.DegradedReason(degraded.Reason) {
+		case adapters.DegradedReasonBackendError, adapters.DegradedReasonBackendUnreachable, adapters.DegradedReasonSessionFailure:
+			return replicationv1alpha1.ApplicationHealthUnhealthy
+		}
+		return replicationv1alpha1.ApplicationHealthDegraded
+	}
+	ready := apimeta.FindStatusCondition(uvr.Status.Conditions, "Ready")
+	switch {
+	case ready == nil:
+		return replicationv1alpha1.ApplicationHealthUnknown
+	case ready.Status == metav1.ConditionTrue:
+		return replicationv1alpha1.ApplicationHealthHealthy
+	default:
+		return replicationv1alpha1.ApplicationHealthDegraded
+	}
+}
+
+// rollUpApplicationStatus aggregates the application's health from its members' under policy
+// and requires every member to be RPO compliant and ready for failover
+func rollUpApplicationStatus(policy replicationv1alpha1.HealthAggregationPolicy, status *replicationv1alpha1.ApplicationReplicationStatus) {
+	status.HealthPolicy = policy
+	status.Health, status.HealthBasis = aggregateApplicationHealth(policy, status.Members)
+	if len(status.Members) == 0 {
+		status.RPOCompliant = false
+		status.FailoverReady = false
+		return
+	}
+	status.RPOCompliant = true
+	status.FailoverReady = true
+	for _, member := range status.Members {
+		status.RPOCompliant = status.RPOCompliant && member.RPOCompliant
+		status.FailoverReady = status.FailoverReady && member.FailoverReady
+	}
+}
+
+// lastObservedState returns the replication state last observed on the backend
+func lastObservedState(uvr *replicationv1alpha1.UnifiedVolumeReplication) string {
+	if n := len(uvr.Status.StateHistory); n > 0 {
+		return uvr.Status.StateHistory[n-1].To
+	}
+	return ""
+}
@@ -0,0 +1,279 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+// createAppMember returns a UVR labelled for the test application that is healthy, in sync
+// and observed as a replica
+func createAppMember(name string) *replicationv1alpha1.UnifiedVolumeReplication {
+	uvr := createTestUVR(name, "default")
+	uvr.Labels = map[string]string{"app": "shop"}
+	lastSync := metav1.NewTime(time.Now().Add(-time.Minute))
+	uvr.Status.LastSyncTime = &lastSync
+	uvr.Status.StateHistory = []replicationv1alpha1.StateHistoryEntry{{Timestamp: lastSync, To: "replica"}}
+	uvr.Status.Conditions = []metav1.Condition{
+		{Type: "Ready", Status: metav1.ConditionTrue, Reason: "ReconciliationSucceeded", LastTransitionTime: lastSync},
+		{Type: "InitialSyncComplete", Status: metav1.ConditionTrue, Reason: "Established", LastTransitionTime: lastSync},
+	}
+	return uvr
+}
+
+func createTestApplication(state replicationv1alpha1.ReplicationState, order ...string) *replicationv1alpha1.ApplicationReplication {
+	return &replicationv1alpha1.ApplicationReplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "default"},
+		Spec: replicationv1alpha1.ApplicationReplicationSpec{
+			Selector:         metav1.LabelSelector{MatchLabels: map[string]string{"app": "shop"}},
+			ReplicationState: state,
+			PromotionOrder:   order,
+		},
+	}
+}
+
+func createTestApplicationReconciler(c client.Client) *ApplicationReplicationReconciler {
+	return &ApplicationReplicationReconciler{
+		Client:   c,
+		Log:      ctrl.Log.WithName("test").WithName("ApplicationReplication"),
+		Scheme:   c.Scheme(),
+		Recorder: record.NewFakeRecorder(100),
+	}
+}
+
+func TestApplicationReplication_RollUp(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	healthy := createAppMember("db")
+
+	lagging := createAppMember("web")
+	stale := metav1.NewTime(time.Now().Add(-time.Hour))
+	lagging.Status.LastSyncTime = &stale
+	lagging.Status.Conditions = append(lagging.Status.Conditions, metav1.Condition{
+		Type: degradedCondition, Status: metav1.ConditionTrue, Reason: "SyncLagExceeded",
+		Message: "sync lag exceeds the RPO", LastTransitionTime: stale,
+	})
+
+	establishing := createAppMember("cache")
+	establishing.Status.LastSyncTime = nil
+	establishing.Status.StateHistory = nil
+	establishing.Status.Conditions = nil
+
+	unrelated := createAppMember("billing")
+	unrelated.Labels = map[string]string{"app": "billing"}
+
+	app := createTestApplication("")
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithObjects(app, healthy, lagging, establishing, unrelated).
+		WithStatusSubresource(app, healthy, lagging, establishing, unrelated).Build()
+	reconciler := createTestApplicationReconciler(c)
+
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(app)})
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(app), app))
+
+	require.Len(t, app.Status.Members, 3, "only the selected replications are members")
+	members := map[string]replicationv1alpha1.ApplicationMemberStatus{}
+	for _, member := range app.Status.Members {
+		members[member.Name] = member
+	}
+	assert.Equal(t, replicationv1alpha1.ApplicationHealthHealthy, members["db"].Health)
+	assert.True(t, members["db"].RPOCompliant)
+	assert.True(t, members["db"].FailoverReady)
+	assert.Equal(t, "replica", members["db"].State)
+
+	assert.Equal(t, replicationv1alpha1.ApplicationHealthDegraded, members["web"].Health)
+	assert.False(t, members["web"].RPOCompliant)
+	assert.True(t, members["web"].FailoverReady)
+	assert.Contains(t, members["web"].Message, "sync lag exceeds the RPO")
+
+	assert.Equal(t, replicationv1alpha1.ApplicationHealthUnknown, members["cache"].Health)
+	assert.False(t, members["cache"].FailoverReady)
+	assert.Contains(t, members["cache"].Message, "initial sync has not completed")
+
+	assert.Equal(t, replicationv1alpha1.ApplicationHealthDegraded, app.Status.Health, "the worst member health wins")
+	assert.False(t, app.Status.RPOCompliant)
+	assert.False(t, app.Status.FailoverReady)
+	ready := apimeta.FindStatusCondition(app.Status.Conditions, "Ready")
+	require.NotNil(t, ready)
+	assert.Equal(t, metav1.ConditionFalse, ready.Status)
+	assert.Equal(t, "MembersNotReady", ready.Reason)
+	assert.Nil(t, apimeta.FindStatusCondition(app.Status.Conditions, roleChangeCondition), "no role was requested")
+
+	// A split-brain makes the whole application unhealthy
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(healthy), healthy))
+	healthy.Status.Conditions = append(healthy.Status.Conditions, metav1.Condition{
+		Type: splitBrainCondition, Status: metav1.ConditionTrue, Reason: "DualPrimary", LastTransitionTime: metav1.Now(),
+	})
+	require.NoError(t, c.Status().Update(ctx, healthy))
+
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(app)})
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(app), app))
+	assert.Equal(t, replicationv1alpha1.ApplicationHealthUnhealthy, app.Status.Health)
+}
+
+func TestApplicationReplication_CoordinatedPromotion(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	db, api, web := createAppMember("db"), createAppMember("api"), createAppMember("web")
+	app := createTestApplication(replicationv1alpha1.ReplicationStateSource, "db", "api")
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithObjects(app, db, api, web).
+		WithStatusSubresource(app, db, api, web).Build()
+	reconciler := createTestApplicationReconciler(c)
+	recorder := reconciler.Recorder.(*record.FakeRecorder)
+
+	reconcile := func() *metav1.Condition {
+		t.Helper()
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(app)})
+		require.NoError(t, err)
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(app), app))
+		condition := apimeta.FindStatusCondition(app.Status.Conditions, roleChangeCondition)
+		require.NotNil(t, condition)
+		if condition.Status == metav1.ConditionTrue {
+			assert.Equal(t, roleChangePollDelay, result.RequeueAfter)
+		}
+		return condition
+	}
+	desiredStates := func() []replicationv1alpha1.ReplicationState {
+		t.Helper()
+		var states []replicationv1alpha1.ReplicationState
+		for _, uvr := range []*replicationv1alpha1.UnifiedVolumeReplication{db, api, web} {
+			current := &replicationv1alpha1.UnifiedVolumeReplication{}
+			require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(uvr), current))
+			states = append(states, current.Spec.ReplicationState)
+		}
+		return states
+	}
+	// observe simulates the UVR controller reporting that the backend reached the new role
+	observe := func(uvr *replicationv1alpha1.UnifiedVolumeReplication) {
+		t.Helper()
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(uvr), uvr))
+		uvr.Status.StateHistory = append(uvr.Status.StateHistory,
+			replicationv1alpha1.StateHistoryEntry{Timestamp: metav1.Now(), From: "replica", To: "source"})
+		require.NoError(t, c.Status().Update(ctx, uvr))
+	}
+	source, replica := replicationv1alpha1.ReplicationStateSource, replicationv1alpha1.ReplicationStateReplica
+
+	condition := reconcile()
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, []replicationv1alpha1.ReplicationState{source, replica, replica}, desiredStates(), "db is promoted first")
+
+	condition = reconcile()
+	assert.Equal(t, "InProgress", condition.Reason)
+	assert.Contains(t, condition.Message, "waiting for db")
+	assert.Equal(t, []replicationv1alpha1.ReplicationState{source, replica, replica}, desiredStates(), "api waits for db")
+
+	observe(db)
+	reconcile()
+	assert.Equal(t, []replicationv1alpha1.ReplicationState{source, source, replica}, desiredStates())
+
+	observe(api)
+	reconcile()
+	assert.Equal(t, []replicationv1alpha1.ReplicationState{source, source, source}, desiredStates())
+
+	observe(web)
+	condition = reconcile()
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "Completed", condition.Reason)
+
+	events := drainEvents(recorder)
+	require.Len(t, events, 4)
+	assert.Contains(t, events[0], "Switching db to source")
+	assert.Contains(t, events[1], "Switching api to source")
+	assert.Contains(t, events[2], "Switching web to source")
+	assert.Contains(t, events[3], "RoleChangeCompleted")
+}
+
+func TestApplicationReplication_PromotionWaitsForFailoverReadiness(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	db, web := createAppMember("db"), createAppMember("web")
+	web.Status.Conditions = web.Status.Conditions[:1]
+	app := createTestApplication(replicationv1alpha1.ReplicationStateSource)
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithObjects(app, db, web).
+		WithStatusSubresource(app, db, web).Build()
+	reconciler := createTestApplicationReconciler(c)
+
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(app)})
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(app), app))
+
+	condition := apimeta.FindStatusCondition(app.Status.Conditions, roleChangeCondition)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, "NotFailoverReady", condition.Reason)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(db), db))
+	assert.Equal(t, replicationv1alpha1.ReplicationStateReplica, db.Spec.ReplicationState, "no member is promoted")
+}
+
+func TestApplicationReplication_PromotionWaitsForGroupGate(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	db := createAppMember("db")
+	db.Spec.GroupMembers = []replicationv1alpha1.VolumeMapping{{
+		Source:      replicationv1alpha1.VolumeSource{PvcName: "db-logs", Namespace: "default"},
+		Destination: replicationv1alpha1.VolumeDestination{VolumeHandle: "db-logs-dest", Namespace: "default"},
+	}}
+	app := createTestApplication(replicationv1alpha1.ReplicationStateSource)
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithObjects(app, db).
+		WithStatusSubresource(app, db).Build()
+	reconciler := createTestApplicationReconciler(c)
+	caughtUp := false
+	reconciler.PromotionGate = func(_ context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (bool, string) {
+		if !caughtUp {
+			return false, "group member db-logs has not completed a sync"
+		}
+		return true, ""
+	}
+
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(app)})
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(app), app))
+	condition := apimeta.FindStatusCondition(app.Status.Conditions, roleChangeCondition)
+	require.NotNil(t, condition)
+	assert.Equal(t, "PromotionBlocked", condition.Reason)
+	assert.Contains(t, condition.Message, "db-logs has not completed a sync")
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(db), db))
+	assert.Equal(t, replicationv1alpha1.ReplicationStateReplica, db.Spec.ReplicationState, "the group is not promoted before it caught up")
+
+	caughtUp = true
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(app)})
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(db), db))
+	assert.Equal(t, replicationv1alpha1.ReplicationStateSource, db.Spec.ReplicationState)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// minAutoSyncInterval is the shortest interval auto mode will ask a backend to sync at
+const minAutoSyncInterval = time.Minute
+
+// autoIntervalHysteresis is the relative change below which a recomputed interval is not
+// applied, so small swings in the observed sync duration do not reconfigure the backend
+const autoIntervalHysteresis = 0.1
+
+// computeSyncInterval picks a sync interval that keeps the recovery point within the RPO.
+// A sync started at the end of the interval only lands once it completes, so the interval
+// is the RPO minus the observed sync duration. When the current lag already exceeds that
+// interval the backend is falling behind, and the interval is halved to catch up.
+//
+// Against the interval currently applied, a change smaller than autoIntervalHysteresis is
+// ignored, and a longer interval is only adopted once the lag is back under half the current
+// one, so the interval does not flap between the halved and the full value. Intervals are
+// whole minutes, which every backend schedule can express.
+func computeSyncInterval(rpo, syncDuration, lag, current time.Duration) time.Duration {
+	interval := rpo - syncDuration
+	if lag > interval {
+		interval /= 2
+	}
+	interval = interval.Truncate(time.Minute)
+	if interval < minAutoSyncInterval {
+		interval = minAutoSyncInterval
+	}
+	if current <= 0 {
+		return interval
+	}
+
+	if interval > current && lag > current/2 {
+		return current
+	}
+	change := interval - current
+	if change < 0 {
+		change = -change
+	}
+	if float64(change) < autoIntervalHysteresis*float64(current) {
+		return current
+	}
+	return interval
+}
+
+// formatScheduleDuration renders a duration in the same form as the schedule RPO, e.g. "14m"
+func formatScheduleDuration(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+}
+
+// updateComputedSchedule recomputes the sync interval of an auto-mode UVR from the latest
+// backend status. The new interval is applied to the backend on the next reconcile.
+func (r *UnifiedVolumeReplicationReconciler) updateComputedSchedule(uvr *replicationv1alpha1.UnifiedVolumeReplication, status *adapters.ReplicationStatus, log logr.Logger) {
+	if uvr.Spec.Schedule.Mode != replicationv1alpha1.ScheduleModeAuto {
+		uvr.Status.ComputedSchedule = nil
+		return
+	}
+
+	rpo, err := parseScheduleDuration(uvr.Spec.Schedule.Rpo)
+	if err != nil || rpo <= 0 {
+		return
+	}
+
+	computed := uvr.Status.ComputedSchedule
+	if computed == nil {
+		computed = &replicationv1alpha1.ComputedSchedule{}
+	}
+
+	// Backends that do not report sync durations keep the last one observed
+	if status.LastSyncDuration != nil {
+		computed.ObservedSyncDuration = &metav1.Duration{Duration: *status.LastSyncDuration}
+	}
+	var syncDuration time.Duration
+	if computed.ObservedSyncDuration != nil {
+		syncDuration = computed.ObservedSyncDuration.Duration
+	}
+	// Reacting to the current lag is experimental and only done when the UVR opts in
+	var lag time.Duration
+	if status.LastSyncTime != nil && uvr.FeatureGateEnabled(replicationv1alpha1.FeatureGateAdaptiveSchedule) {
+		lag = time.Since(*status.LastSyncTime)
+	}
+
+	var current time.Duration
+	if computed.Interval != "" {
+		current, _ = parseScheduleDuration(computed.Interval)
+	}
+	interval := formatScheduleDuration(computeSyncInterval(rpo, syncDuration, lag, current))
+	if interval != computed.Interval {
+		log.Info("Adjusted sync interval for RPO", "rpo", uvr.Spec.Schedule.Rpo,
+			"syncDuration", syncDuration, "previous", computed.Interval, "interval", interval)
+		if computed.Interval != "" {
+			r.recordEventf(uvr, corev1.EventTypeNormal, "SyncIntervalAdjusted",
+				"Sync interval changed from %s to %s to meet RPO %s", computed.Interval, interval, uvr.Spec.Schedule.Rpo)
+		}
+		computed.Interval = interval
+		computed.LastUpdateTime = metav1.Now()
+	}
+	uvr.Status.ComputedSchedule = computed
+}
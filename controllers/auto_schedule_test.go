@@ -0,0 +1,134 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestComputeSyncInterval(t *testing.T) {
+	rpo := 15 * time.Minute
+
+	// The interval tightens as sync durations approach the RPO budget
+	previous := rpo
+	for _, syncDuration := range []time.Duration{0, time.Minute, 5 * time.Minute, 10 * time.Minute, 14 * time.Minute} {
+		interval := computeSyncInterval(rpo, syncDuration, 0, 0)
+		assert.LessOrEqual(t, interval, previous, "sync duration %s", syncDuration)
+		assert.LessOrEqual(t, interval+syncDuration, rpo, "sync duration %s", syncDuration)
+		previous = interval
+	}
+
+	// Growing lag tightens the interval further
+	assert.Equal(t, 10*time.Minute, computeSyncInterval(rpo, 5*time.Minute, time.Minute, 0))
+	assert.Equal(t, 5*time.Minute, computeSyncInterval(rpo, 5*time.Minute, 12*time.Minute, 0))
+
+	// Syncs slower than the RPO cannot be scheduled away, sync as often as allowed
+	assert.Equal(t, minAutoSyncInterval, computeSyncInterval(rpo, 20*time.Minute, 0, 0))
+
+	// Intervals are whole minutes
+	assert.Equal(t, 14*time.Minute, computeSyncInterval(rpo, 37*time.Second, 0, 0))
+}
+
+func TestComputeSyncInterval_Hysteresis(t *testing.T) {
+	rpo := time.Hour
+
+	// A sync a minute slower than before is not worth reconfiguring the backend for
+	assert.Equal(t, 50*time.Minute, computeSyncInterval(rpo, 11*time.Minute, 0, 50*time.Minute))
+	assert.Equal(t, 40*time.Minute, computeSyncInterval(rpo, 20*time.Minute, 0, 50*time.Minute))
+
+	// Once halved to catch up, the interval stays tight while the lag is still high...
+	assert.Equal(t, 25*time.Minute, computeSyncInterval(rpo, 10*time.Minute, 55*time.Minute, 0))
+	assert.Equal(t, 25*time.Minute, computeSyncInterval(rpo, 10*time.Minute, 20*time.Minute, 25*time.Minute))
+
+	// ...and relaxes once the replica has clearly caught up
+	assert.Equal(t, 50*time.Minute, computeSyncInterval(rpo, 10*time.Minute, 5*time.Minute, 25*time.Minute))
+}
+
+func TestFormatScheduleDuration(t *testing.T) {
+	assert.Equal(t, "2h", formatScheduleDuration(2*time.Hour))
+	assert.Equal(t, "14m", formatScheduleDuration(14*time.Minute))
+	assert.Equal(t, "90s", formatScheduleDuration(90*time.Second))
+	assert.Equal(t, "48h", formatScheduleDuration(48*time.Hour), "days are not a unit every backend accepts")
+}
+
+func TestReconciler_AutoSchedule(t *testing.T) {
+	s := createTestScheme(t)
+	uvr := createTestUVR("test-auto-schedule", "default")
+	uvr.Spec.Schedule.Mode = replicationv1alpha1.ScheduleModeAuto
+	reconciler := createTestReconciler(fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).Build(), s)
+
+	lastSync := time.Now()
+	observe := func(syncDuration time.Duration) string {
+		reconciler.updateStatusFromEngineStatus(uvr, &adapters.ReplicationStatus{
+			State:            "replica",
+			Health:           adapters.ReplicationHealthHealthy,
+			LastSyncTime:     &lastSync,
+			LastSyncDuration: &syncDuration,
+		}, reconciler.Log)
+		require.NotNil(t, uvr.Status.ComputedSchedule)
+		return uvr.Status.ComputedSchedule.Interval
+	}
+
+	assert.Equal(t, "14m", observe(time.Minute))
+	assert.Equal(t, "14m", uvr.SyncInterval())
+
+	// Syncs taking longer shrink the interval applied to the backend
+	assert.Equal(t, "5m", observe(10*time.Minute))
+	assert.Equal(t, "5m", uvr.SyncInterval())
+	assert.Equal(t, 10*time.Minute, uvr.Status.ComputedSchedule.ObservedSyncDuration.Duration)
+
+	// Switching away from auto mode drops the computed schedule
+	uvr.Spec.Schedule.Mode = replicationv1alpha1.ScheduleModeInterval
+	reconciler.updateStatusFromEngineStatus(uvr, &adapters.ReplicationStatus{State: "replica", Health: adapters.ReplicationHealthHealthy}, reconciler.Log)
+	assert.Nil(t, uvr.Status.ComputedSchedule)
+	assert.Equal(t, uvr.Spec.Schedule.Rpo, uvr.SyncInterval())
+}
+
+func TestReconciler_AdaptiveScheduleFeatureGate(t *testing.T) {
+	s := createTestScheme(t)
+
+	// A replica 12m behind with 5m syncs: 10m without catch-up, halved to 5m with it
+	interval := func(featureGates map[replicationv1alpha1.FeatureGate]bool) string {
+		uvr := createTestUVR("test-adaptive-schedule", "default")
+		uvr.Spec.Schedule.Mode = replicationv1alpha1.ScheduleModeAuto
+		uvr.Spec.FeatureGates = featureGates
+		reconciler := createTestReconciler(fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).Build(), s)
+
+		lastSync := time.Now().Add(-12 * time.Minute)
+		syncDuration := 5 * time.Minute
+		reconciler.updateStatusFromEngineStatus(uvr, &adapters.ReplicationStatus{
+			State:            "replica",
+			Health:           adapters.ReplicationHealthHealthy,
+			LastSyncTime:     &lastSync,
+			LastSyncDuration: &syncDuration,
+		}, reconciler.Log)
+		require.NotNil(t, uvr.Status.ComputedSchedule)
+		return uvr.Status.ComputedSchedule.Interval
+	}
+
+	assert.Equal(t, "5m", interval(nil), "catch-up is enabled by default")
+	assert.Equal(t, "10m", interval(map[replicationv1alpha1.FeatureGate]bool{replicationv1alpha1.FeatureGateAdaptiveSchedule: false}), "disabling the gate ignores lag")
+	assert.Equal(t, "5m", interval(map[replicationv1alpha1.FeatureGate]bool{replicationv1alpha1.FeatureGateAdaptiveSchedule: true}))
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+// backendUnavailableCondition is True while the circuit breaker is open and backend calls
+// are short-circuited
+const backendUnavailableCondition = "BackendUnavailable"
+
+// BackendCircuitBreakers keeps one circuit breaker per backend, created on first use with
+// the same thresholds, so failures of one backend do not short-circuit the replications of
+// the others
+type BackendCircuitBreakers struct {
+	mu               sync.Mutex
+	failureThreshold int
+	successThreshold int
+	timeout          time.Duration
+	breakers         map[translation.Backend]*CircuitBreaker
+}
+
+// NewBackendCircuitBreakers creates per-backend circuit breakers with the thresholds and
+// timeout of NewCircuitBreaker
+func NewBackendCircuitBreakers(failureThreshold, successThreshold int, timeout time.Duration) *BackendCircuitBreakers {
+	return &BackendCircuitBreakers{
+		failureThreshold: failureThreshold,
+		successThreshold: successThreshold,
+		timeout:          timeout,
+		breakers:         make(map[translation.Backend]*CircuitBreaker),
+	}
+}
+
+// For returns the circuit breaker of backend
+func (b *BackendCircuitBreakers) For(backend translation.Backend) *CircuitBreaker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	breaker, ok := b.breakers[backend]
+	if !ok {
+		breaker = NewCircuitBreaker(b.failureThreshold, b.successThreshold, b.timeout)
+		b.breakers[backend] = breaker
+	}
+	return breaker
+}
+
+// isBackendFailure reports whether err says the backend is failing, as opposed to the
+// request being wrong or interrupted. Only backend failures count towards opening the
+// circuit breaker of the backend, so a misconfigured UVR cannot cut off every other
+// replication on it.
+func isBackendFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || apierrors.IsTooManyRequests(err) {
+		return false
+	}
+	if _, ok := adapters.AsResourceConflictError(err); ok {
+		return false
+	}
+	if adapterErr, ok := adapters.GetAdapterError(err); ok {
+		switch adapterErr.Type {
+		case adapters.ErrorTypeValidation, adapters.ErrorTypeConfiguration, adapters.ErrorTypePermission:
+			return false
+		}
+	}
+	return true
+}
+
+// callBackend runs an operation on backend through the backend's circuit breaker and
+// records the breaker's state changes as events on uvr. It returns ErrCircuitOpen without
+// calling fn while the breaker is open. Without circuit breakers fn is called directly.
+func (r *UnifiedVolumeReplicationReconciler) callBackend(uvr *replicationv1alpha1.UnifiedVolumeReplication, backend translation.Backend, operation string, fn func() error) error {
+	if r.CircuitBreakers == nil {
+		return fn()
+	}
+
+	var err error
+	from, to, breakerErr := r.CircuitBreakers.For(backend).Execute(func() error {
+		err = fn()
+		if !isBackendFailure(err) {
+			return nil
+		}
+		return err
+	})
+	if errors.Is(breakerErr, ErrCircuitOpen) {
+		err = breakerErr
+	}
+
+	if from != to {
+		switch to {
+		case StateOpen:
+			r.recordEventf(uvr, corev1.EventTypeWarning, "CircuitBreakerOpened",
+				"Calls to the %s backend suspended after repeated failures, last %s failed: %v", backend, operation, err)
+		case StateHalfOpen:
+			r.recordEventf(uvr, corev1.EventTypeNormal, "CircuitBreakerHalfOpen",
+				"Probing the %s backend with %s after the circuit breaker timeout", backend, operation)
+		case StateClosed:
+			r.recordEventf(uvr, corev1.EventTypeNormal, "CircuitBreakerClosed",
+				"Calls to the %s backend resumed, %s succeeded", backend, operation)
+		}
+	}
+	return err
+}
+
+// handleBackendUnavailable stops a reconcile whose backend call was short-circuited by the
+// open circuit breaker. The UVR is requeued quickly so it resumes soon after the breaker
+// lets calls through again; each short-circuited reconcile is cheap. It returns false when
+// err is not ErrCircuitOpen.
+func (r *UnifiedVolumeReplicationReconciler) handleBackendUnavailable(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, err error, log logr.Logger) (ctrl.Result, bool) {
+	if !errors.Is(err, ErrCircuitOpen) {
+		return ctrl.Result{}, false
+	}
+	log.Info("Backend circuit breaker is open, skipping backend calls")
+
+	message := "Backend calls are suspended by the circuit breaker after repeated backend failures"
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               backendUnavailableCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "CircuitOpen",
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             "BackendUnavailable",
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+
+	r.recordFailedReconcile(uvr)
+	if err := r.Status().Update(ctx, uvr); err != nil {
+		log.Error(err, "Failed to update status")
+	}
+	return ctrl.Result{RequeueAfter: requeueDelayFast}, true
+}
+
+// clearBackendUnavailable reports that backend calls are going through again
+func (r *UnifiedVolumeReplicationReconciler) clearBackendUnavailable(uvr *replicationv1alpha1.UnifiedVolumeReplication) {
+	if existing := r.getCondition(uvr, backendUnavailableCondition); existing == nil || existing.Status != metav1.ConditionTrue {
+		return
+	}
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               backendUnavailableCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             "CircuitClosed",
+		Message:            "Backend calls are going through again",
+		ObservedGeneration: uvr.Generation,
+	})
+}
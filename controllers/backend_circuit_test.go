@@ -0,0 +1,150 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg"
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+func TestReconciler_CircuitBreakerOpensOnBackendFailures(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+
+	uvr := createTestUVR("test-circuit-breaker", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+
+	// The backend fails every attempt to create the mirror relationship
+	failing := true
+	creates := 0
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(tridentCRDs(""), uvr)...).
+		WithStatusSubresource(uvr).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if u, ok := obj.(*unstructured.Unstructured); ok && u.GroupVersionKind() == adapters.TridentMirrorRelationshipGVK {
+					creates++
+					if failing {
+						return apierrors.NewServiceUnavailable("backend is down")
+					}
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).Build()
+
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewTridentAdapterFactory()))
+	reconciler := createTestReconciler(c, s)
+	reconciler.AdapterRegistry = registry
+	reconciler.ControllerEngine = pkg.NewControllerEngine(c, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+		registry, pkg.DefaultControllerEngineConfig())
+	reconciler.CircuitBreakers = NewBackendCircuitBreakers(3, 1, time.Minute)
+	breaker := reconciler.CircuitBreakers.For(translation.BackendTrident)
+	recorder := reconciler.Recorder.(*record.FakeRecorder)
+	drainEvents(recorder)
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+	for i := 0; i < 3; i++ {
+		_, err := reconciler.Reconcile(ctx, req)
+		require.Error(t, err)
+	}
+	assert.Equal(t, 3, creates)
+	assert.Equal(t, StateOpen, breaker.GetState())
+	assert.True(t, containsEvent(drainEvents(recorder), "Warning CircuitBreakerOpened Calls to the trident backend suspended"))
+	assert.Equal(t, StateClosed, reconciler.CircuitBreakers.For(translation.BackendCeph).GetState(),
+		"other backends keep going through")
+
+	// The open breaker short-circuits without calling the backend
+	result, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err, "a short-circuited reconcile is requeued, not failed")
+	assert.Equal(t, requeueDelayFast, result.RequeueAfter)
+	assert.Equal(t, 3, creates, "the adapter is not called while the breaker is open")
+
+	updated := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	unavailable := reconciler.getCondition(updated, backendUnavailableCondition)
+	require.NotNil(t, unavailable)
+	assert.Equal(t, metav1.ConditionTrue, unavailable.Status)
+	assert.Equal(t, "BackendUnavailable", reconciler.getCondition(updated, "Ready").Reason)
+
+	// Once the timeout passes the backend is probed again, and a success closes the breaker
+	failing = false
+	breaker.openedAt = time.Now().Add(-2 * time.Minute)
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, 4, creates)
+	assert.Equal(t, StateClosed, breaker.GetState())
+	assert.True(t, containsEvent(drainEvents(recorder), "Normal CircuitBreakerClosed"))
+
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	unavailable = reconciler.getCondition(updated, backendUnavailableCondition)
+	require.NotNil(t, unavailable)
+	assert.Equal(t, metav1.ConditionFalse, unavailable.Status)
+}
+
+func TestBackendCircuitBreakers(t *testing.T) {
+	breakers := NewBackendCircuitBreakers(2, 1, time.Minute)
+	trident := breakers.For(translation.BackendTrident)
+	assert.Same(t, trident, breakers.For(translation.BackendTrident))
+
+	for range 2 {
+		_ = trident.Call(func() error { return errors.New("backend is down") })
+	}
+	assert.Equal(t, StateOpen, trident.GetState())
+	assert.ErrorIs(t, trident.Call(func() error { return nil }), ErrCircuitOpen)
+	assert.NoError(t, breakers.For(translation.BackendCeph).Call(func() error { return nil }),
+		"a failing backend does not short-circuit the others")
+}
+
+func TestIsBackendFailure(t *testing.T) {
+	assert.False(t, isBackendFailure(nil))
+	assert.False(t, isBackendFailure(context.Canceled))
+	assert.False(t, isBackendFailure(apierrors.NewTooManyRequests("slow down", 1)))
+	assert.False(t, isBackendFailure(adapters.NewAdapterError(adapters.ErrorTypeValidation, "trident", "ensure", "db", "bad spec")),
+		"a wrong request says nothing about the backend")
+	assert.True(t, isBackendFailure(adapters.NewAdapterError(adapters.ErrorTypeConnection, "trident", "ensure", "db", "refused")))
+	assert.True(t, isBackendFailure(errors.New("unexpected")))
+}
+
+func containsEvent(events []string, prefix string) bool {
+	for _, event := range events {
+		if strings.HasPrefix(event, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+// BackendSettings are the operator-level settings for one backend, as written in the backend
+// config ConfigMap. Unset fields keep the adapter defaults.
+type BackendSettings struct {
+	Timeout            metav1.Duration        `json:"timeout,omitempty"`
+	RetryAttempts      int                    `json:"retryAttempts,omitempty"`
+	RetryDelay         metav1.Duration        `json:"retryDelay,omitempty"`
+	StatusCacheTTL     metav1.Duration        `json:"statusCacheTTL,omitempty"`
+	StatusCacheMaxSize int                    `json:"statusCacheMaxSize,omitempty"`
+	Settings           map[string]interface{} `json:"settings,omitempty"`
+	// States overrides entries of the backend's state map, unified state to backend state
+	States map[string]string `json:"states,omitempty"`
+}
+
+// ParseBackendConfig parses the data of the backend config ConfigMap: one key per backend,
+// named after it, holding its BackendSettings as JSON. It returns the adapter configuration
+// and the state map overrides of each backend.
+func ParseBackendConfig(data map[string]string) (map[translation.Backend]*adapters.AdapterConfig, map[translation.Backend]map[string]string, error) {
+	configs := make(map[translation.Backend]*adapters.AdapterConfig, len(data))
+	states := make(map[translation.Backend]map[string]string)
+	for key, value := range data {
+		backend := translation.Backend(key)
+		if !slices.Contains(translation.GetSupportedBackends(), backend) {
+			return nil, nil, fmt.Errorf("unknown backend %q", key)
+		}
+
+		settings := BackendSettings{}
+		decoder := json.NewDecoder(strings.NewReader(value))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&settings); err != nil {
+			return nil, nil, fmt.Errorf("invalid settings for backend %s: %w", key, err)
+		}
+		if len(settings.States) > 0 {
+			states[backend] = settings.States
+		}
+
+		config := adapters.DefaultAdapterConfig(backend)
+		if settings.Timeout.Duration > 0 {
+			config.Timeout = settings.Timeout.Duration
+		}
+		if settings.RetryAttempts > 0 {
+			config.RetryAttempts = settings.RetryAttempts
+		}
+		if settings.RetryDelay.Duration > 0 {
+			config.RetryDelay = settings.RetryDelay.Duration
+		}
+		config.StatusCacheTTL = settings.StatusCacheTTL.Duration
+		config.StatusCacheMaxSize = settings.StatusCacheMaxSize
+		config.CustomSettings = settings.Settings
+		configs[backend] = config
+	}
+	return configs, states, nil
+}
+
+// BackendConfigReconciler applies the per-backend adapter configuration held in a ConfigMap
+// to the adapter manager, and its state map overrides to the translation engine. Cached
+// adapters created from a different configuration are swapped on their next use, so edits
+// take effect without restarting the operator. A ConfigMap that does not parse, or whose
+// overrides do not round-trip, leaves the configuration in force.
+type BackendConfigReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Recorder record.EventRecorder
+
+	// AdapterManager receives the configuration
+	AdapterManager *adapters.AdapterManager
+
+	// TranslationEngine receives the state map overrides; nil ignores them
+	TranslationEngine *translation.Engine
+
+	// ConfigMap is the ConfigMap holding the configuration
+	ConfigMap types.NamespacedName
+
+	// applied are the backends configured by the previous reconcile, cleared again when
+	// their key is removed. The controller runs a single worker, so no lock is needed.
+	applied []translation.Backend
+
+	// reader reads the ConfigMap: the dedicated cache set up by SetupWithManager, or the
+	// client when nil
+	reader client.Reader
+}
+
+// backendConfigCacheOptions returns the options of a cache holding only the ConfigMap ref
+// names. The manager cache would list and hold every ConfigMap in the cluster to serve it.
+func backendConfigCacheOptions(ref types.NamespacedName) cache.Options {
+	return cache.Options{
+		DefaultNamespaces: map[string]cache.Config{ref.Namespace: {}},
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.ConfigMap{}: {Field: fields.OneTermEqualSelector("metadata.name", ref.Name)},
+		},
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager. The ConfigMap is watched and read
+// through a cache of its own, restricted to it.
+func (r *BackendConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	options := backendConfigCacheOptions(r.ConfigMap)
+	options.HTTPClient = mgr.GetHTTPClient()
+	options.Scheme = mgr.GetScheme()
+	options.Mapper = mgr.GetRESTMapper()
+	configCache, err := cache.New(mgr.GetConfig(), options)
+	if err != nil {
+		return fmt.Errorf("failed to create the backend config cache: %w", err)
+	}
+	if err := mgr.Add(configCache); err != nil {
+		return err
+	}
+	r.reader = configCache
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("backendconfig").
+		WatchesRawSource(source.Kind(configCache, &corev1.ConfigMap{}, &handler.TypedEnqueueRequestForObject[*corev1.ConfigMap]{})).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+// Reconcile applies the current content of the backend config ConfigMap
+func (r *BackendConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("configmap", req.NamespacedName)
+
+	reader := client.Reader(r.Client)
+	if r.reader != nil {
+		reader = r.reader
+	}
+	configMap := &corev1.ConfigMap{}
+	if err := reader.Get(ctx, r.ConfigMap, configMap); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		// Without the ConfigMap every backend falls back to the adapter defaults and the
+		// built-in state maps
+		if err := r.reloadStateMaps(nil); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.apply(nil, log)
+		return ctrl.Result{}, nil
+	}
+
+	configs, states, err := ParseBackendConfig(configMap.Data)
+	if err == nil {
+		err = r.reloadStateMaps(states)
+	}
+	if err != nil {
+		log.Error(err, "Ignoring invalid backend configuration")
+		r.Recorder.Eventf(configMap, corev1.EventTypeWarning, "InvalidBackendConfig",
+			"Backend configuration not applied: %v", err)
+		return ctrl.Result{}, nil
+	}
+
+	if backends := r.apply(configs, log); len(backends) > 0 {
+		r.Recorder.Eventf(configMap, corev1.EventTypeNormal, "BackendConfigApplied",
+			"Applied configuration for backends %s", strings.Join(backends, ", "))
+	}
+	return ctrl.Result{}, nil
+}
+
+// reloadStateMaps hands the state map overrides to the translation engine, which keeps the
+// maps in force when they do not round-trip
+func (r *BackendConfigReconciler) reloadStateMaps(states map[translation.Backend]map[string]string) error {
+	if r.TranslationEngine == nil {
+		return nil
+	}
+	return r.TranslationEngine.ReloadStateMaps(states)
+}
+
+// apply hands the configuration to the adapter manager, clearing backends no longer
+// configured, and returns the configured backends
+func (r *BackendConfigReconciler) apply(configs map[translation.Backend]*adapters.AdapterConfig, log logr.Logger) []string {
+	for _, backend := range r.applied {
+		if _, ok := configs[backend]; !ok {
+			log.Info("Backend configuration removed, using adapter defaults", "backend", backend)
+			r.AdapterManager.SetBackendConfig(backend, nil)
+		}
+	}
+
+	r.applied = r.applied[:0]
+	var names []string
+	for backend, config := range configs {
+		r.AdapterManager.SetBackendConfig(backend, config)
+		r.applied = append(r.applied, backend)
+		names = append(names, string(backend))
+	}
+	sort.Strings(names)
+	if len(names) > 0 {
+		log.Info("Applied backend configuration", "backends", names)
+	}
+	return names
+}
+
+// ParseConfigMapRef parses a namespace/name reference to a ConfigMap
+func ParseConfigMapRef(value string) (types.NamespacedName, error) {
+	namespace, name, ok := strings.Cut(value, "/")
+	if !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return types.NamespacedName{}, fmt.Errorf("%q is not of the form namespace/name", value)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+func TestParseBackendConfig(t *testing.T) {
+	configs, states, err := ParseBackendConfig(map[string]string{
+		"trident": `{"timeout": "45s", "statusCacheTTL": "10s", "settings": {"credentialsSecret": "trident-creds"}}`,
+		"ceph":    `{"states": {"syncing": "syncing"}}`,
+	})
+	require.NoError(t, err)
+	require.Contains(t, configs, translation.BackendTrident)
+	config := configs[translation.BackendTrident]
+	assert.Equal(t, 45*time.Second, config.Timeout)
+	assert.Equal(t, 10*time.Second, config.StatusCacheTTL)
+	assert.Equal(t, 3, config.RetryAttempts, "unset fields keep the adapter defaults")
+	assert.Equal(t, "trident-creds", config.CustomSettings["credentialsSecret"])
+	assert.Equal(t, map[translation.Backend]map[string]string{translation.BackendCeph: {"syncing": "syncing"}}, states)
+
+	_, _, err = ParseBackendConfig(map[string]string{"netapp": `{}`})
+	assert.ErrorContains(t, err, `unknown backend "netapp"`)
+
+	_, _, err = ParseBackendConfig(map[string]string{"ceph": `{"timeot": "5s"}`})
+	assert.ErrorContains(t, err, "invalid settings for backend ceph")
+}
+
+func TestParseConfigMapRef(t *testing.T) {
+	ref, err := ParseConfigMapRef("operator-system/backend-config")
+	require.NoError(t, err)
+	assert.Equal(t, types.NamespacedName{Namespace: "operator-system", Name: "backend-config"}, ref)
+
+	for _, value := range []string{"backend-config", "/backend-config", "operator-system/", "a/b/c"} {
+		_, err := ParseConfigMapRef(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestBackendConfigCacheOptions(t *testing.T) {
+	options := backendConfigCacheOptions(types.NamespacedName{Namespace: "operator-system", Name: "backend-config"})
+	assert.Equal(t, map[string]cache.Config{"operator-system": {}}, options.DefaultNamespaces)
+
+	// Only the named ConfigMap is listed and watched, not every ConfigMap in the namespace
+	require.Len(t, options.ByObject, 1)
+	for obj, byObject := range options.ByObject {
+		assert.IsType(t, &corev1.ConfigMap{}, obj)
+		require.NotNil(t, byObject.Field)
+		assert.True(t, byObject.Field.Matches(fields.Set{"metadata.name": "backend-config"}))
+		assert.False(t, byObject.Field.Matches(fields.Set{"metadata.name": "other"}))
+	}
+}
+
+func TestBackendConfigReconciler_SwapsAdapters(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	uvr := createTestUVR("test-backend-config", "default")
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend-config", Namespace: "operator-system"},
+		Data:       map[string]string{"trident": `{"settings": {"credentialsSecret": "trident-creds-v1"}}`},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(uvr, configMap).WithStatusSubresource(uvr).Build()
+	reconciler := createTestReconciler(fakeClient, s)
+
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewMockAdapterFactory(translation.BackendTrident, adapters.DefaultMockConfig())))
+	reconciler.AdapterManager = adapters.NewAdapterManager(registry, nil)
+
+	configRecorder := record.NewFakeRecorder(10)
+	configReconciler := &BackendConfigReconciler{
+		Client:         fakeClient,
+		Log:            ctrl.Log.WithName("test"),
+		Recorder:       configRecorder,
+		AdapterManager: reconciler.AdapterManager,
+		ConfigMap:      client.ObjectKeyFromObject(configMap),
+	}
+	configReq := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(configMap)}
+	_, err := configReconciler.Reconcile(ctx, configReq)
+	require.NoError(t, err)
+	assert.Contains(t, drainEvents(configRecorder), "Normal BackendConfigApplied Applied configuration for backends trident")
+
+	// The first reconcile only adds the finalizer
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+	_, _ = reconciler.Reconcile(ctx, req)
+	_, _ = reconciler.Reconcile(ctx, req)
+	original, ok := reconciler.AdapterManager.GetAdapter(uvr)
+	require.True(t, ok)
+
+	// Editing the ConfigMap swaps the adapter on the next reconcile of the UVR
+	configMap.Data["trident"] = `{"settings": {"credentialsSecret": "trident-creds-v2"}}`
+	require.NoError(t, fakeClient.Update(ctx, configMap))
+	_, err = configReconciler.Reconcile(ctx, configReq)
+	require.NoError(t, err)
+	_, _ = reconciler.Reconcile(ctx, req)
+
+	current, ok := reconciler.AdapterManager.GetAdapter(uvr)
+	require.True(t, ok)
+	assert.NotSame(t, original, current)
+	assert.False(t, original.IsHealthy(), "the replaced adapter is cleaned up")
+
+	// An invalid edit keeps the configuration in force
+	configMap.Data["trident"] = `{"settings": `
+	require.NoError(t, fakeClient.Update(ctx, configMap))
+	_, err = configReconciler.Reconcile(ctx, configReq)
+	require.NoError(t, err)
+	assert.True(t, containsEvent(drainEvents(configRecorder), "Warning InvalidBackendConfig"))
+	_, _ = reconciler.Reconcile(ctx, req)
+	unchanged, ok := reconciler.AdapterManager.GetAdapter(uvr)
+	require.True(t, ok)
+	assert.Same(t, current, unchanged)
+
+	// Deleting the ConfigMap falls back to the adapter defaults
+	require.NoError(t, fakeClient.Delete(ctx, configMap))
+	_, err = configReconciler.Reconcile(ctx, configReq)
+	require.NoError(t, err)
+	_, _ = reconciler.Reconcile(ctx, req)
+	defaulted, ok := reconciler.AdapterManager.GetAdapter(uvr)
+	require.True(t, ok)
+	assert.NotSame(t, current, defaulted)
+}
+
+func TestBackendConfigReconciler_ReloadsStateMaps(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend-config", Namespace: "operator-system"},
+		Data:       map[string]string{"trident": `{"states": {"syncing": "established-failed", "failed": "established-syncing"}}`},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(configMap).Build()
+	engine := translation.NewEngine()
+	var reloads int
+	engine.OnReload(func(translation.StateMapChange) { reloads++ })
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &BackendConfigReconciler{
+		Client:            fakeClient,
+		Log:               ctrl.Log.WithName("test"),
+		Recorder:          recorder,
+		AdapterManager:    adapters.NewAdapterManager(adapters.NewRegistry(), nil),
+		TranslationEngine: engine,
+		ConfigMap:         client.ObjectKeyFromObject(configMap),
+	}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(configMap)}
+	backendState := func() string {
+		state, err := engine.TranslateStateToBackend(translation.BackendTrident, "syncing")
+		require.NoError(t, err)
+		return state
+	}
+
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, "established-failed", backendState())
+	assert.Equal(t, 1, reloads, "the reload reaches the requeuing of reinterpreted replications")
+
+	// Overrides that do not round-trip keep the maps in force
+	configMap.Data["trident"] = `{"states": {"replica": "established"}}`
+	require.NoError(t, fakeClient.Update(ctx, configMap))
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.True(t, containsEvent(drainEvents(recorder), "Warning InvalidBackendConfig"))
+	assert.Equal(t, "established-failed", backendState())
+
+	// Deleting the ConfigMap restores the built-in maps
+	require.NoError(t, fakeClient.Delete(ctx, configMap))
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, "established-syncing", backendState())
+	assert.Equal(t, 2, reloads)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// exceedsBackendLimits records the backend's replication usage in status and reports whether
+// creating the UVR's replication would go over the backend's limit. A replication whose
+// backend resource already exists, created earlier or adopted, is counted in the usage and
+// is never refused.
+func (r *UnifiedVolumeReplicationReconciler) exceedsBackendLimits(ctx context.Context, adapter adapters.ReplicationAdapter, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) bool {
+	reporter, ok := adapter.(adapters.BackendLimitsReporter)
+	if !ok {
+		uvr.Status.BackendUsage = nil
+		return false
+	}
+
+	limits, err := reporter.GetBackendLimits(ctx, uvr)
+	if err != nil {
+		// Never block on the check itself, creation surfaces real backend failures
+		log.Error(err, "Failed to read backend limits")
+		return false
+	}
+
+	uvr.Status.BackendUsage = &replicationv1alpha1.BackendUsage{
+		Replications:    limits.Replications,
+		MaxReplications: limits.MaxReplications,
+		LastCheckedTime: metav1.Now(),
+	}
+
+	// The effective config is only recorded once the backend resource has been ensured
+	if !limits.Exhausted() || limits.Counted || uvr.Status.EffectiveConfig != nil {
+		return false
+	}
+
+	message := fmt.Sprintf("Backend %s is at its replication limit (%d of %d in use), delete unused replications or raise the limit",
+		adapter.GetBackendType(), limits.Replications, limits.MaxReplications)
+	if existing := r.getCondition(uvr, "Ready"); existing == nil || existing.Reason != "BackendLimitReached" {
+		r.recordEventf(uvr, corev1.EventTypeWarning, "BackendLimitReached", "%s", message)
+	}
+	log.Info("Refusing to create replication, backend limit reached",
+		"replications", limits.Replications, "maxReplications", limits.MaxReplications)
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             "BackendLimitReached",
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+	return true
+}
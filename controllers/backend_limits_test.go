@@ -0,0 +1,114 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+func TestReconciler_BackendLimitReached(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	uvr := createTestUVR("test-backend-limit", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	uvr.Spec.Extensions = &replicationv1alpha1.Extensions{Powerstore: &replicationv1alpha1.PowerStoreExtensions{}}
+
+	// Another replication already uses the only replication group the backend allows
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(adapters.DellCSIReplicationGroupGVK)
+	existing.SetName("other-replication")
+	existing.SetNamespace("default")
+
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(uvr, existing).WithStatusSubresource(uvr).Build()
+	reconciler := createTestReconciler(c, s)
+
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewPowerStoreAdapterFactory()))
+	reconciler.AdapterManager = adapters.NewAdapterManager(registry, nil)
+	config := adapters.DefaultAdapterConfig(translation.BackendPowerStore)
+	config.CustomSettings[adapters.MaxReplicationGroupsSetting] = 1
+	reconciler.AdapterManager.SetBackendConfig(translation.BackendPowerStore, config)
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+	result, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, requeueDelaySuccess, result.RequeueAfter)
+
+	rg := &unstructured.Unstructured{}
+	rg.SetGroupVersionKind(adapters.DellCSIReplicationGroupGVK)
+	err = c.Get(ctx, req.NamespacedName, rg)
+	assert.True(t, apierrors.IsNotFound(err), "no replication group is created over the limit")
+
+	updated := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	require.NotNil(t, updated.Status.BackendUsage)
+	assert.Equal(t, int64(1), updated.Status.BackendUsage.Replications)
+	assert.Equal(t, int64(1), updated.Status.BackendUsage.MaxReplications)
+
+	message := "Backend powerstore is at its replication limit (1 of 1 in use), delete unused replications or raise the limit"
+	ready := reconciler.getCondition(updated, "Ready")
+	require.NotNil(t, ready)
+	assert.Equal(t, metav1.ConditionFalse, ready.Status)
+	assert.Equal(t, "BackendLimitReached", ready.Reason)
+	assert.Equal(t, message, ready.Message)
+	assert.Contains(t, drainEvents(reconciler.Recorder.(*record.FakeRecorder)), "Warning BackendLimitReached "+message)
+}
+
+func TestReconciler_BackendLimitsCountExistingReplication(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	uvr := createTestUVR("test-backend-limit-existing", "default")
+	uvr.Spec.Extensions = &replicationv1alpha1.Extensions{Powerstore: &replicationv1alpha1.PowerStoreExtensions{}}
+	// The replication group already exists, e.g. created by a reconcile whose status update
+	// failed, so it is part of the usage even though no effective config was recorded
+
+	rg := &unstructured.Unstructured{}
+	rg.SetGroupVersionKind(adapters.DellCSIReplicationGroupGVK)
+	rg.SetName(uvr.Name)
+	rg.SetNamespace(uvr.Namespace)
+
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(rg).Build()
+	reconciler := createTestReconciler(c, s)
+
+	factory := adapters.NewPowerStoreAdapterFactory()
+	config := adapters.DefaultAdapterConfig(translation.BackendPowerStore)
+	config.CustomSettings[adapters.MaxReplicationGroupsSetting] = 1
+	adapter, err := factory.CreateAdapter(translation.BackendPowerStore, c, translation.NewEngine(), config)
+	require.NoError(t, err)
+
+	assert.False(t, reconciler.exceedsBackendLimits(ctx, adapter, uvr, reconciler.Log))
+	require.NotNil(t, uvr.Status.BackendUsage)
+	assert.Equal(t, int64(1), uvr.Status.BackendUsage.Replications)
+	assert.Nil(t, reconciler.getCondition(uvr, "Ready"))
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+const (
+	// backendMaintenanceCondition is True while the backend signals maintenance
+	backendMaintenanceCondition = "BackendMaintenance"
+
+	// requeueDelayMaintenance is how often a deferred replication checks whether the
+	// maintenance signal has cleared
+	requeueDelayMaintenance = 1 * time.Minute
+)
+
+// isCriticalOperation reports whether the reconcile carries a role change that must not wait
+// for backend maintenance: a planned operation in progress, or a requested state that differs
+// from the last state observed on the backend
+func (r *UnifiedVolumeReplicationReconciler) isCriticalOperation(uvr *replicationv1alpha1.UnifiedVolumeReplication) bool {
+	if r.isPlannedOperationActive(uvr) {
+		return true
+	}
+	if n := len(uvr.Status.StateHistory); n > 0 {
+		return uvr.Status.StateHistory[n-1].To != string(uvr.Spec.ReplicationState)
+	}
+	return false
+}
+
+// deferForBackendMaintenance reports whether the reconcile should stop before touching the
+// backend because it signals maintenance. Critical operations go ahead anyway; routine
+// reconciles wait until the signal clears.
+func (r *UnifiedVolumeReplicationReconciler) deferForBackendMaintenance(ctx context.Context, backend translation.Backend, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) bool {
+	if r.DiscoveryEngine == nil {
+		return false
+	}
+
+	signal, err := r.DiscoveryEngine.CheckBackendMaintenance(ctx, backend)
+	if err != nil {
+		// Never block on the check itself, the backend operations surface real failures
+		log.Error(err, "Failed to check backend maintenance signal", "backend", backend)
+		return false
+	}
+
+	existing := r.getCondition(uvr, backendMaintenanceCondition)
+	inMaintenance := existing != nil && existing.Status == metav1.ConditionTrue
+
+	if signal == nil {
+		if inMaintenance {
+			log.Info("Backend maintenance ended, resuming operations", "backend", backend)
+			r.updateCondition(uvr, metav1.Condition{
+				Type:               backendMaintenanceCondition,
+				Status:             metav1.ConditionFalse,
+				Reason:             "MaintenanceEnded",
+				Message:            fmt.Sprintf("Backend %s is no longer under maintenance", backend),
+				ObservedGeneration: uvr.Generation,
+			})
+			r.recordEventf(uvr, corev1.EventTypeNormal, "BackendMaintenanceEnded", "Backend %s maintenance ended, resuming operations", backend)
+		}
+		return false
+	}
+
+	message := fmt.Sprintf("Backend %s signals maintenance on %s: %s", backend, signal.CRD, signal.Reason)
+	if !inMaintenance {
+		r.recordEventf(uvr, corev1.EventTypeWarning, "BackendMaintenance", "%s", message)
+	}
+
+	if r.isCriticalOperation(uvr) {
+		log.Info("Backend under maintenance, proceeding with critical operation", "backend", backend, "reason", signal.Reason)
+		r.updateCondition(uvr, metav1.Condition{
+			Type:               backendMaintenanceCondition,
+			Status:             metav1.ConditionTrue,
+			Reason:             "CriticalOperationProceeding",
+			Message:            message,
+			ObservedGeneration: uvr.Generation,
+		})
+		return false
+	}
+
+	log.Info("Backend under maintenance, deferring operations", "backend", backend, "reason", signal.Reason)
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               backendMaintenanceCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "OperationsDeferred",
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+	return true
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/discovery"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+// tridentCRDs returns the Trident CRDs, the mirror relationship one carrying the given
+// maintenance annotation value when it is not empty
+func tridentCRDs(maintenance string) []client.Object {
+	var objects []client.Object
+	for _, definition := range discovery.TridentCRDs {
+		crd := establishedCRD(definition)
+		if maintenance != "" && definition.Kind == "TridentMirrorRelationship" {
+			crd.Annotations = map[string]string{discovery.MaintenanceAnnotation: maintenance}
+		}
+		objects = append(objects, crd)
+	}
+	return objects
+}
+
+func cephCRDs() []client.Object {
+	var objects []client.Object
+	for _, definition := range discovery.CephCRDs {
+		objects = append(objects, establishedCRD(definition))
+	}
+	return objects
+}
+
+func establishedCRD(definition discovery.CRDDefinition) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: definition.Name},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: definition.Group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: definition.Kind},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: definition.Version, Served: true, Storage: true},
+			},
+		},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{
+			Conditions: []apiextensionsv1.CustomResourceDefinitionCondition{
+				{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue},
+			},
+		},
+	}
+}
+
+func TestReconciler_BackendMaintenance(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+
+	newUVR := func() *replicationv1alpha1.UnifiedVolumeReplication {
+		uvr := createTestUVR("test-maintenance", "default")
+		uvr.Finalizers = []string{unifiedReplicationFinalizer}
+		return uvr
+	}
+
+	t.Run("DeferredUntilSignalClears", func(t *testing.T) {
+		uvr := newUVR()
+		c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(tridentCRDs("snapmirror upgrade"), uvr)...).
+			WithStatusSubresource(uvr).Build()
+		reconciler := createTestReconciler(c, s)
+		req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+
+		result, err := reconciler.Reconcile(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, requeueDelayMaintenance, result.RequeueAfter)
+
+		updated := &replicationv1alpha1.UnifiedVolumeReplication{}
+		require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+		cond := reconciler.getCondition(updated, backendMaintenanceCondition)
+		require.NotNil(t, cond)
+		assert.Equal(t, metav1.ConditionTrue, cond.Status)
+		assert.Equal(t, "OperationsDeferred", cond.Reason)
+		assert.Contains(t, cond.Message, "snapmirror upgrade")
+		assert.Nil(t, reconciler.getCondition(updated, "Ready"), "no backend operation was attempted")
+		assert.Nil(t, updated.Status.LastReconcile)
+		assert.Contains(t, drainEvents(reconciler.Recorder.(*record.FakeRecorder)),
+			"Warning BackendMaintenance Backend trident signals maintenance on tridentmirrorrelationships.trident.netapp.io: snapmirror upgrade")
+
+		// Clear the signal
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		require.NoError(t, c.Get(ctx, client.ObjectKey{Name: "tridentmirrorrelationships.trident.netapp.io"}, crd))
+		crd.Annotations = nil
+		require.NoError(t, c.Update(ctx, crd))
+
+		result, err = reconciler.Reconcile(ctx, req)
+		require.NoError(t, err)
+		assert.NotEqual(t, requeueDelayMaintenance, result.RequeueAfter)
+
+		require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+		cond = reconciler.getCondition(updated, backendMaintenanceCondition)
+		require.NotNil(t, cond)
+		assert.Equal(t, metav1.ConditionFalse, cond.Status)
+		assert.Equal(t, "MaintenanceEnded", cond.Reason)
+		assert.Contains(t, drainEvents(reconciler.Recorder.(*record.FakeRecorder)),
+			"Normal BackendMaintenanceEnded Backend trident maintenance ended, resuming operations")
+	})
+
+	t.Run("CriticalOperationProceeds", func(t *testing.T) {
+		uvr := newUVR()
+		// The backend last reported replica, the spec requests a promotion
+		uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+		uvr.Status.StateHistory = []replicationv1alpha1.StateHistoryEntry{
+			{Timestamp: metav1.Now(), To: string(replicationv1alpha1.ReplicationStateReplica)},
+		}
+		reconciler := createTestReconciler(fake.NewClientBuilder().WithScheme(s).WithObjects(tridentCRDs("snapmirror upgrade")...).Build(), s)
+
+		assert.False(t, reconciler.deferForBackendMaintenance(ctx, translation.BackendTrident, uvr, reconciler.Log))
+		cond := reconciler.getCondition(uvr, backendMaintenanceCondition)
+		require.NotNil(t, cond)
+		assert.Equal(t, "CriticalOperationProceeding", cond.Reason)
+	})
+
+	t.Run("NoSignal", func(t *testing.T) {
+		uvr := newUVR()
+		reconciler := createTestReconciler(fake.NewClientBuilder().WithScheme(s).WithObjects(tridentCRDs("")...).Build(), s)
+
+		assert.False(t, reconciler.deferForBackendMaintenance(ctx, translation.BackendTrident, uvr, reconciler.Log))
+		assert.Nil(t, reconciler.getCondition(uvr, backendMaintenanceCondition))
+	})
+}
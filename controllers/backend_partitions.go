@@ -0,0 +1,122 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+// defaultBackendPartition holds UVRs whose backend cannot be inferred from the spec
+const defaultBackendPartition = "default"
+
+// backendPartitions lists the partitions that get their own controller and workqueue when
+// backend isolation is enabled
+var backendPartitions = []string{
+	string(translation.BackendCeph),
+	string(translation.BackendTrident),
+	string(translation.BackendPowerStore),
+	string(translation.BackendGenericCSI),
+	defaultBackendPartition,
+}
+
+// backendPartitionFor infers which backend partition a UVR belongs to. It only looks at the
+// spec (extension hints first, then the storage class name) so it is cheap enough to run in
+// event predicates, and it always returns exactly one partition.
+func backendPartitionFor(uvr *replicationv1alpha1.UnifiedVolumeReplication) string {
+	if uvr.Spec.Extensions != nil {
+		switch {
+		case uvr.Spec.Extensions.Ceph != nil:
+			return string(translation.BackendCeph)
+		case uvr.Spec.Extensions.Trident != nil:
+			return string(translation.BackendTrident)
+		case uvr.Spec.Extensions.Powerstore != nil:
+			return string(translation.BackendPowerStore)
+		}
+	}
+
+	storageClass := uvr.Spec.SourceEndpoint.StorageClass
+	switch {
+	case contains(storageClass, "ceph") || contains(storageClass, "rbd"):
+		return string(translation.BackendCeph)
+	case contains(storageClass, "trident") || contains(storageClass, "netapp"):
+		return string(translation.BackendTrident)
+	case contains(storageClass, "powerstore") || contains(storageClass, "dell"):
+		return string(translation.BackendPowerStore)
+	}
+
+	return defaultBackendPartition
+}
+
+// backendPartition returns the partition whose controller reconciles the UVR. UVRs the spec
+// does not tie to a backend go to the generic CSI partition when the generic CSI adapter has a
+// default class, as it is the adapter chosen for them. Discovery is not consulted, so a UVR
+// does not move between partitions, and workqueues, once the VolumeReplication CRD is found.
+func (r *UnifiedVolumeReplicationReconciler) backendPartition(uvr *replicationv1alpha1.UnifiedVolumeReplication) string {
+	partition := backendPartitionFor(uvr)
+	if partition == defaultBackendPartition && adapters.GenericCSIConfigured(r.AdapterRegistry) {
+		return string(translation.BackendGenericCSI)
+	}
+	return partition
+}
+
+// backendPartitionPredicate admits only UVRs that belong to the given partition
+func (r *UnifiedVolumeReplicationReconciler) backendPartitionPredicate(partition string) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		uvr, ok := obj.(*replicationv1alpha1.UnifiedVolumeReplication)
+		return ok && r.backendPartition(uvr) == partition
+	})
+}
+
+// backendControllerName returns the controller name used for a backend partition
+func backendControllerName(partition string) string {
+	return fmt.Sprintf("unifiedvolumereplication-%s", partition)
+}
+
+// getMaxConcurrentReconcilesFor returns the worker count for a backend partition
+func (r *UnifiedVolumeReplicationReconciler) getMaxConcurrentReconcilesFor(partition string) int {
+	if n := r.MaxConcurrentReconcilesPerBackend[partition]; n > 0 {
+		return n
+	}
+	return r.getMaxConcurrentReconciles()
+}
+
+// setupBackendPartitionedControllers registers one controller per backend partition. Each
+// controller has its own workqueue and workers, so slow operations against one backend
+// cannot occupy the workers reconciling UVRs of another.
+func (r *UnifiedVolumeReplicationReconciler) setupBackendPartitionedControllers(mgr ctrl.Manager) error {
+	for _, partition := range backendPartitions {
+		b := r.watchReplications(ctrl.NewControllerManagedBy(mgr), backendControllerName(partition),
+			r.backendPartitionPredicate(partition))
+		err := r.watchReferencedObjects(b, func(uvr *replicationv1alpha1.UnifiedVolumeReplication) bool {
+			return r.backendPartition(uvr) == partition
+		}).
+			WithOptions(r.controllerOptions(r.getMaxConcurrentReconcilesFor(partition))).
+			Complete(r)
+		if err != nil {
+			return fmt.Errorf("failed to set up controller for backend %s: %w", partition, err)
+		}
+	}
+	return nil
+}
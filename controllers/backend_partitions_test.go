@@ -0,0 +1,184 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+func TestBackendPartitionFor(t *testing.T) {
+	cephUVR := createTestUVR("ceph", "default")
+	cephUVR.Spec.Extensions = &replicationv1alpha1.Extensions{Ceph: &replicationv1alpha1.CephExtensions{}}
+
+	powerstoreUVR := createTestUVR("powerstore", "default")
+	powerstoreUVR.Spec.Extensions = nil
+	powerstoreUVR.Spec.SourceEndpoint.StorageClass = "powerstore-block"
+
+	unknownUVR := createTestUVR("unknown", "default")
+	unknownUVR.Spec.Extensions = nil
+
+	assert.Equal(t, string(translation.BackendCeph), backendPartitionFor(cephUVR))
+	assert.Equal(t, string(translation.BackendTrident), backendPartitionFor(createTestUVR("trident", "default")))
+	assert.Equal(t, string(translation.BackendPowerStore), backendPartitionFor(powerstoreUVR))
+	assert.Equal(t, defaultBackendPartition, backendPartitionFor(unknownUVR))
+}
+
+func TestBackendPartition_GenericCSI(t *testing.T) {
+	registry := adapters.NewRegistry()
+	factory := adapters.NewGenericCSIAdapterFactory()
+	require.NoError(t, registry.RegisterFactory(factory))
+	r := &UnifiedVolumeReplicationReconciler{AdapterRegistry: registry}
+
+	unknownUVR := createTestUVR("unknown", "default")
+	unknownUVR.Spec.Extensions = nil
+
+	// Without a default class the generic CSI adapter is never chosen
+	assert.Equal(t, defaultBackendPartition, r.backendPartition(unknownUVR))
+
+	// With one, UVRs no backend claims get the generic CSI partition before discovery runs
+	require.NoError(t, factory.SetDefaults("vrc-generic", nil))
+	assert.Equal(t, string(translation.BackendGenericCSI), r.backendPartition(unknownUVR))
+	assert.Equal(t, string(translation.BackendTrident), r.backendPartition(createTestUVR("trident", "default")))
+	assert.True(t, r.backendPartitionPredicate(string(translation.BackendGenericCSI)).Create(event.CreateEvent{Object: unknownUVR}))
+	assert.False(t, r.backendPartitionPredicate(defaultBackendPartition).Create(event.CreateEvent{Object: unknownUVR}))
+}
+
+// startBackendPartitions starts one unmanaged controller per backend partition, configured the
+// same way as setupBackendPartitionedControllers. Events sent on the returned channel are
+// delivered to every partition, as the shared informer would.
+func startBackendPartitions(ctx context.Context, t *testing.T, r *UnifiedVolumeReplicationReconciler, do reconcile.Reconciler) chan<- event.GenericEvent {
+	events := make(chan event.GenericEvent)
+	partitionEvents := make([]chan event.GenericEvent, 0, len(backendPartitions))
+
+	for _, partition := range backendPartitions {
+		c, err := controller.NewUnmanaged(backendControllerName(partition), controller.Options{
+			Reconciler:              do,
+			MaxConcurrentReconciles: r.getMaxConcurrentReconcilesFor(partition),
+			SkipNameValidation:      ptr.To(true),
+		})
+		require.NoError(t, err)
+
+		ch := make(chan event.GenericEvent, 10)
+		partitionEvents = append(partitionEvents, ch)
+		require.NoError(t, c.Watch(source.Channel(ch, &handler.EnqueueRequestForObject{},
+			source.WithPredicates[client.Object, reconcile.Request](r.backendPartitionPredicate(partition)))))
+
+		go func() {
+			_ = c.Start(ctx)
+		}()
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case evt := <-events:
+				for _, ch := range partitionEvents {
+					ch <- evt
+				}
+			}
+		}
+	}()
+
+	return events
+}
+
+func TestBackendPartitions_StalledBackendDoesNotBlockOthers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// A single worker per backend makes a stalled reconcile occupy the whole partition
+	r := &UnifiedVolumeReplicationReconciler{MaxConcurrentReconciles: 1}
+
+	stalled := make(chan struct{})
+	release := make(chan struct{})
+	processed := make(chan string, 10)
+	do := reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		if req.Name == "slow-ceph" {
+			close(stalled)
+			<-release
+		}
+		processed <- req.Name
+		return reconcile.Result{}, nil
+	})
+
+	events := startBackendPartitions(ctx, t, r, do)
+
+	slowCeph := createTestUVR("slow-ceph", "default")
+	slowCeph.Spec.Extensions = &replicationv1alpha1.Extensions{Ceph: &replicationv1alpha1.CephExtensions{}}
+	queuedCeph := slowCeph.DeepCopy()
+	queuedCeph.Name = "queued-ceph"
+
+	events <- event.GenericEvent{Object: slowCeph}
+	select {
+	case <-stalled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("ceph reconcile never started")
+	}
+
+	events <- event.GenericEvent{Object: queuedCeph}
+	events <- event.GenericEvent{Object: createTestUVR("fast-trident", "default")}
+
+	// The Trident UVR is reconciled while the Ceph partition is still stalled
+	select {
+	case name := <-processed:
+		assert.Equal(t, "fast-trident", name)
+	case <-time.After(5 * time.Second):
+		t.Fatal("trident reconcile was blocked by the stalled ceph reconcile")
+	}
+
+	// Ceph UVRs queue behind the stalled one until it finishes
+	close(release)
+	var cephProcessed []string
+	for len(cephProcessed) < 2 {
+		select {
+		case name := <-processed:
+			cephProcessed = append(cephProcessed, name)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("ceph partition did not drain, processed %v", cephProcessed)
+		}
+	}
+	assert.Equal(t, []string{"slow-ceph", "queued-ceph"}, cephProcessed)
+}
+
+func TestBackendPartitions_PerBackendConcurrency(t *testing.T) {
+	r := &UnifiedVolumeReplicationReconciler{
+		MaxConcurrentReconciles:           2,
+		MaxConcurrentReconcilesPerBackend: map[string]int{string(translation.BackendCeph): 5},
+	}
+
+	assert.Equal(t, 5, r.getMaxConcurrentReconcilesFor(string(translation.BackendCeph)))
+	assert.Equal(t, 2, r.getMaxConcurrentReconcilesFor(string(translation.BackendTrident)))
+	assert.Equal(t, 2, r.getMaxConcurrentReconcilesFor(defaultBackendPartition))
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+const (
+	// defaultBackendSelectionTimeout bounds a single call to the selection webhook
+	defaultBackendSelectionTimeout = 5 * time.Second
+
+	// maxBackendSelectionResponseSize limits how much of the webhook response is read
+	maxBackendSelectionResponseSize = 64 * 1024
+)
+
+// BackendSelectionRequest is sent to the backend selection webhook
+type BackendSelectionRequest struct {
+	Name              string                                           `json:"name"`
+	Namespace         string                                           `json:"namespace"`
+	Spec              replicationv1alpha1.UnifiedVolumeReplicationSpec `json:"spec"`
+	AvailableBackends []translation.Backend                            `json:"availableBackends"`
+}
+
+// BackendSelectionResponse is returned by the backend selection webhook
+type BackendSelectionResponse struct {
+	Backend translation.Backend `json:"backend"`
+	Reason  string              `json:"reason,omitempty"`
+}
+
+// BackendSelectionWebhook delegates backend selection to an external HTTP endpoint, for
+// organizations with their own placement rules (cost, compliance, data residency)
+type BackendSelectionWebhook struct {
+	URL     string
+	Timeout time.Duration
+
+	// FailOpen falls back to built-in selection when the webhook fails. When false the
+	// reconcile fails instead, so no backend is chosen without the webhook's approval.
+	FailOpen bool
+
+	client *http.Client
+}
+
+// NewBackendSelectionWebhook creates a webhook client for the given endpoint
+func NewBackendSelectionWebhook(url string, timeout time.Duration, failOpen bool) *BackendSelectionWebhook {
+	if timeout <= 0 {
+		timeout = defaultBackendSelectionTimeout
+	}
+
+	return &BackendSelectionWebhook{
+		URL:      url,
+		Timeout:  timeout,
+		FailOpen: failOpen,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// SelectBackend asks the webhook to choose one of the available backends for the UVR
+func (w *BackendSelectionWebhook) SelectBackend(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, availableBackends []translation.Backend) (*BackendSelectionResponse, error) {
+	body, err := json.Marshal(BackendSelectionRequest{
+		Name:              uvr.Name,
+		Namespace:         uvr.Namespace,
+		Spec:              uvr.Spec,
+		AvailableBackends: availableBackends,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode selection request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build selection request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("selection webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("selection webhook returned status %d", resp.StatusCode)
+	}
+
+	response := &BackendSelectionResponse{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxBackendSelectionResponseSize)).Decode(response); err != nil {
+		return nil, fmt.Errorf("failed to decode selection response: %w", err)
+	}
+
+	for _, backend := range availableBackends {
+		if backend == response.Backend {
+			return response, nil
+		}
+	}
+
+	return nil, fmt.Errorf("selection webhook chose unavailable backend %q", response.Backend)
+}
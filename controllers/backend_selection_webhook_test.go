@@ -0,0 +1,98 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+var allTestBackends = []translation.Backend{
+	translation.BackendCeph,
+	translation.BackendTrident,
+	translation.BackendPowerStore,
+}
+
+func newSelectionServer(t *testing.T, status int, response BackendSelectionResponse) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := BackendSelectionRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, "webhook-uvr", request.Name)
+		assert.ElementsMatch(t, allTestBackends, request.AvailableBackends)
+
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestBackendSelectionWebhook(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	uvr := createTestUVR("webhook-uvr", "default")
+
+	t.Run("WebhookChoiceHonored", func(t *testing.T) {
+		server := newSelectionServer(t, http.StatusOK, BackendSelectionResponse{Backend: translation.BackendPowerStore, Reason: "data residency"})
+		reconciler := createTestReconciler(fake.NewClientBuilder().WithScheme(s).Build(), s)
+		reconciler.BackendSelectionWebhook = NewBackendSelectionWebhook(server.URL, time.Second, false)
+
+		// The Trident extension hint is overridden by the webhook
+		backend, err := reconciler.selectBackendViaEngine(ctx, uvr, allTestBackends, reconciler.Log)
+		require.NoError(t, err)
+		assert.Equal(t, translation.BackendPowerStore, backend)
+	})
+
+	t.Run("FailOpenFallsBack", func(t *testing.T) {
+		server := newSelectionServer(t, http.StatusInternalServerError, BackendSelectionResponse{})
+		reconciler := createTestReconciler(fake.NewClientBuilder().WithScheme(s).Build(), s)
+		reconciler.BackendSelectionWebhook = NewBackendSelectionWebhook(server.URL, time.Second, true)
+
+		backend, err := reconciler.selectBackendViaEngine(ctx, uvr, allTestBackends, reconciler.Log)
+		require.NoError(t, err)
+		assert.Equal(t, translation.BackendTrident, backend)
+	})
+
+	t.Run("FailClosedReturnsError", func(t *testing.T) {
+		server := newSelectionServer(t, http.StatusInternalServerError, BackendSelectionResponse{})
+		reconciler := createTestReconciler(fake.NewClientBuilder().WithScheme(s).Build(), s)
+		reconciler.BackendSelectionWebhook = NewBackendSelectionWebhook(server.URL, time.Second, false)
+
+		_, err := reconciler.selectBackendViaEngine(ctx, uvr, allTestBackends, reconciler.Log)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "status 500")
+	})
+
+	t.Run("RejectsUnavailableBackend", func(t *testing.T) {
+		server := newSelectionServer(t, http.StatusOK, BackendSelectionResponse{Backend: "netapp"})
+		webhook := NewBackendSelectionWebhook(server.URL, time.Second, false)
+
+		_, err := webhook.SelectBackend(ctx, uvr, allTestBackends)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unavailable backend")
+	})
+}
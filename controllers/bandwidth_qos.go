@@ -0,0 +1,192 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// bandwidthLimitedCondition is True while a bandwidth window caps the replication's syncs
+const bandwidthLimitedCondition = "BandwidthLimited"
+
+// bandwidthChangeHorizon bounds the search for the next change of the cap: windows repeat weekly
+const bandwidthChangeHorizon = 8 * 24 * time.Hour
+
+// activeBandwidthWindow returns the window whose cap is in force at t, the first listed of
+// the windows open at t. A window ending before its start is open from its start on the
+// days it lists until its end on the following day.
+func activeBandwidthWindow(qos *replicationv1alpha1.QosSpec, loc *time.Location, t time.Time) (replicationv1alpha1.BandwidthWindow, bool) {
+	local := t.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	today := local.Weekday()
+	yesterday := (today + 6) % 7
+
+	for _, window := range qos.BandwidthWindows {
+		start, err := replicationv1alpha1.ParseTimeOfDay(window.Start)
+		if err != nil {
+			continue
+		}
+		end, err := replicationv1alpha1.ParseTimeOfDay(window.End)
+		if err != nil {
+			continue
+		}
+		var open bool
+		if start < end {
+			open = window.OpensOn(today) && minute >= start && minute < end
+		} else {
+			open = (window.OpensOn(today) && minute >= start) || (window.OpensOn(yesterday) && minute < end)
+		}
+		if open {
+			return window, true
+		}
+	}
+	return replicationv1alpha1.BandwidthWindow{}, false
+}
+
+// nextBandwidthChange returns the first minute after t at which another window, or none,
+// is in force. It returns false when the cap never changes.
+func nextBandwidthChange(qos *replicationv1alpha1.QosSpec, loc *time.Location, t time.Time) (time.Time, bool) {
+	// The cap can only change where a window starts or ends, or at midnight for the days
+	boundaries := map[int]bool{0: true}
+	for _, window := range qos.BandwidthWindows {
+		for _, value := range []string{window.Start, window.End} {
+			if minute, err := replicationv1alpha1.ParseTimeOfDay(value); err == nil {
+				boundaries[minute] = true
+			}
+		}
+	}
+
+	current, _ := activeBandwidthWindow(qos, loc, t)
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	for end := t.Add(bandwidthChangeHorizon); next.Before(end); next = next.Add(time.Minute) {
+		if local := next.In(loc); !boundaries[local.Hour()*60+local.Minute()] {
+			continue
+		}
+		if window, _ := activeBandwidthWindow(qos, loc, next); window.String() != current.String() || window.Limit != current.Limit {
+			return next, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// reconcileBandwidthLimit applies the bandwidth cap of the window in force at now to the
+// backend and reports it in status.qos, removing the cap once spec.qos is unset. The cap is
+// applied on every reconcile, so a change made on the backend is undone. It returns the
+// delay until the cap changes next, 0 when it never does.
+func (r *UnifiedVolumeReplicationReconciler) reconcileBandwidthLimit(ctx context.Context, adapter adapters.ReplicationAdapter, uvr *replicationv1alpha1.UnifiedVolumeReplication, now time.Time, log logr.Logger) time.Duration {
+	limiter, supported := adapter.(adapters.BandwidthLimiter)
+	qos := uvr.Spec.Qos
+	if qos == nil {
+		if uvr.Status.Qos == nil {
+			return 0
+		}
+		if supported && uvr.Status.Qos.BandwidthLimit != "" {
+			if err := limiter.SetBandwidthLimit(ctx, uvr, 0); err != nil {
+				log.Error(err, "Failed to remove bandwidth limit")
+				return requeueDelayError
+			}
+			r.recordEventf(uvr, corev1.EventTypeNormal, "BandwidthLimitRemoved", "Bandwidth cap of %s removed", uvr.Status.Qos.BandwidthLimit)
+		}
+		uvr.Status.Qos = nil
+		apimeta.RemoveStatusCondition(&uvr.Status.Conditions, bandwidthLimitedCondition)
+		return 0
+	}
+
+	if !supported {
+		message := fmt.Sprintf("Backend %s cannot cap replication bandwidth, remove qos from the spec", adapter.GetBackendType())
+		if existing := r.getCondition(uvr, bandwidthLimitedCondition); existing == nil || existing.Reason != "Unsupported" {
+			r.recordEventf(uvr, corev1.EventTypeWarning, "BandwidthLimitUnsupported", "%s", message)
+		}
+		r.setBandwidthLimited(uvr, metav1.ConditionFalse, "Unsupported", message)
+		uvr.Status.Qos = nil
+		return 0
+	}
+
+	loc, err := qos.Location()
+	if err != nil {
+		r.setBandwidthLimited(uvr, metav1.ConditionFalse, "Failed", fmt.Sprintf("Invalid qos timeZone: %v", err))
+		return 0
+	}
+	window, open := activeBandwidthWindow(qos, loc, now)
+	var limit string
+	var bitsPerSecond int64
+	if open {
+		limit = window.Limit
+		bitsPerSecond, _ = replicationv1alpha1.ParseBandwidthLimit(limit)
+	}
+	if err := limiter.SetBandwidthLimit(ctx, uvr, bitsPerSecond); err != nil {
+		log.Error(err, "Failed to apply bandwidth limit", "limit", limit)
+		r.setBandwidthLimited(uvr, metav1.ConditionFalse, "Failed", fmt.Sprintf("Failed to apply bandwidth limit: %v", err))
+		return requeueDelayError
+	}
+
+	applied := ""
+	if uvr.Status.Qos != nil {
+		applied = uvr.Status.Qos.BandwidthLimit
+	}
+	status := &replicationv1alpha1.QosStatus{BandwidthLimit: limit}
+	if open {
+		status.Window = window.String()
+	}
+	var delay time.Duration
+	until := ""
+	if next, ok := nextBandwidthChange(qos, loc, now); ok {
+		status.NextChangeTime = &metav1.Time{Time: next}
+		// Requeue just past the boundary, so the window in force has changed
+		delay = next.Sub(now) + time.Second
+		until = " until " + next.UTC().Format(time.RFC3339)
+	}
+	uvr.Status.Qos = status
+
+	if open {
+		if limit != applied {
+			log.Info("Capping replication bandwidth", "limit", limit, "window", status.Window)
+			r.recordEventf(uvr, corev1.EventTypeNormal, "BandwidthLimitApplied", "Bandwidth capped at %s by window %s", limit, status.Window)
+		}
+		r.setBandwidthLimited(uvr, metav1.ConditionTrue, "WindowActive",
+			fmt.Sprintf("Syncs are capped at %s by window %s%s", limit, status.Window, until))
+	} else {
+		if applied != "" {
+			log.Info("Lifting replication bandwidth cap", "limit", applied)
+			r.recordEventf(uvr, corev1.EventTypeNormal, "BandwidthLimitRemoved", "Bandwidth cap of %s lifted outside its window", applied)
+		}
+		r.setBandwidthLimited(uvr, metav1.ConditionFalse, "Unlimited",
+			fmt.Sprintf("No bandwidth window is open, syncs are not capped%s", until))
+	}
+	return delay
+}
+
+// setBandwidthLimited sets the BandwidthLimited condition
+func (r *UnifiedVolumeReplicationReconciler) setBandwidthLimited(uvr *replicationv1alpha1.UnifiedVolumeReplication, status metav1.ConditionStatus, reason, message string) {
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               bandwidthLimitedCondition,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+}
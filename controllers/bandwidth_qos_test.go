@@ -0,0 +1,234 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+// throttlingAdapter stands in for a backend with a per-replication throttle, none of the
+// real adapters has one
+type throttlingAdapter struct {
+	adapters.ReplicationAdapter
+	bitsPerSecond int64
+}
+
+func (a *throttlingAdapter) SetBandwidthLimit(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, bitsPerSecond int64) error {
+	a.bitsPerSecond = bitsPerSecond
+	return nil
+}
+
+// throttlingFactory creates its adapters wrapped in a throttlingAdapter
+type throttlingFactory struct {
+	adapters.AdapterFactory
+}
+
+func (f throttlingFactory) CreateAdapter(backend translation.Backend, c client.Client, translator *translation.Engine, config *adapters.AdapterConfig) (adapters.ReplicationAdapter, error) {
+	adapter, err := f.AdapterFactory.CreateAdapter(backend, c, translator, config)
+	if err != nil {
+		return nil, err
+	}
+	return &throttlingAdapter{ReplicationAdapter: adapter}, nil
+}
+
+// businessHoursQos caps syncs to 50Mbps 9-5 on weekdays and to 1Gbps overnight
+func businessHoursQos() *replicationv1alpha1.QosSpec {
+	return &replicationv1alpha1.QosSpec{BandwidthWindows: []replicationv1alpha1.BandwidthWindow{
+		{Start: "09:00", End: "17:00", Days: []replicationv1alpha1.Weekday{"Mon", "Tue", "Wed", "Thu", "Fri"}, Limit: "50Mbps"},
+		{Start: "22:00", End: "02:00", Limit: "1Gbps"},
+	}}
+}
+
+func TestActiveBandwidthWindow(t *testing.T) {
+	qos := businessHoursQos()
+	// Only opens on Fridays, closing on Saturday morning
+	qos.BandwidthWindows = append(qos.BandwidthWindows, replicationv1alpha1.BandwidthWindow{
+		Start: "20:00", End: "08:00", Days: []replicationv1alpha1.Weekday{"Fri"}, Limit: "500Kbps",
+	})
+	friday := func(hour, minute int) time.Time { return time.Date(2026, 10, 16, hour, minute, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name  string
+		at    time.Time
+		limit string
+	}{
+		{name: "BeforeBusinessHours", at: friday(8, 59)},
+		{name: "BusinessHoursStart", at: friday(9, 0), limit: "50Mbps"},
+		{name: "BusinessHoursLastMinute", at: friday(16, 59), limit: "50Mbps"},
+		{name: "BusinessHoursEnd", at: friday(17, 0)},
+		{name: "FridayEvening", at: friday(20, 30), limit: "500Kbps"},
+		{name: "OverlapFirstListedWins", at: friday(23, 0), limit: "1Gbps"},
+		{name: "OvernightAfterMidnight", at: friday(25, 30), limit: "1Gbps"},
+		{name: "FridayWindowSaturdayMorning", at: friday(31, 0), limit: "500Kbps"},
+		{name: "SaturdayBusinessHours", at: friday(33, 0)},
+		{name: "FridayWindowNotOnSunday", at: friday(55, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, open := activeBandwidthWindow(qos, time.UTC, tt.at)
+			assert.Equal(t, tt.limit != "", open)
+			assert.Equal(t, tt.limit, window.Limit)
+		})
+	}
+
+	// Window times are read in the time zone of the spec
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	window, open := activeBandwidthWindow(qos, berlin, friday(7, 30))
+	assert.True(t, open, "07:30 UTC is 09:30 in Berlin")
+	assert.Equal(t, "50Mbps", window.Limit)
+}
+
+func TestNextBandwidthChange(t *testing.T) {
+	qos := businessHoursQos()
+	friday := func(hour, minute int) time.Time { return time.Date(2026, 10, 16, hour, minute, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name string
+		at   time.Time
+		next time.Time
+	}{
+		{name: "OpensAtNine", at: friday(8, 0), next: friday(9, 0)},
+		{name: "ClosesAtFive", at: friday(9, 0), next: friday(17, 0)},
+		{name: "OvernightOpens", at: friday(17, 30), next: friday(22, 0)},
+		{name: "OvernightCloses", at: friday(23, 15), next: friday(26, 0)},
+		{name: "SkipsTheWeekend", at: friday(26, 0), next: friday(46, 0)},
+		{name: "MondayBusinessHours", at: friday(74, 0), next: friday(81, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, ok := nextBandwidthChange(qos, time.UTC, tt.at)
+			require.True(t, ok)
+			assert.Equal(t, tt.next, next)
+		})
+	}
+}
+
+func TestReconciler_BandwidthLimitFollowsWindows(t *testing.T) {
+	ctx := context.Background()
+	uvr := createTestUVR("test-bandwidth", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	uvr.Spec.Qos = businessHoursQos()
+	reconciler, c := newDeactivationTestReconciler(t, throttlingFactory{adapters.NewTridentAdapterFactory()}, uvr)
+	recorder := reconciler.Recorder.(*record.FakeRecorder)
+	key := client.ObjectKeyFromObject(uvr)
+
+	// A full reconcile creates the relationship and requeues no later than the next change
+	result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, key, uvr))
+	require.NotNil(t, uvr.Status.Qos)
+	require.NotNil(t, uvr.Status.Qos.NextChangeTime)
+	assert.LessOrEqual(t, result.RequeueAfter, time.Until(uvr.Status.Qos.NextChangeTime.Time)+time.Second)
+	drainEvents(recorder)
+	// Start the simulated clock from a replication that was never capped
+	uvr.Status.Qos = nil
+
+	trident, err := adapters.NewTridentAdapter(c, reconciler.TranslationEngine)
+	require.NoError(t, err)
+	adapter := &throttlingAdapter{ReplicationAdapter: trident}
+
+	// The clock crosses the window boundaries of a Friday and the following night
+	friday := func(hour, minute int) time.Time { return time.Date(2026, 10, 16, hour, minute, 0, 0, time.UTC) }
+	steps := []struct {
+		at       time.Time
+		limit    string
+		throttle int64
+		status   metav1.ConditionStatus
+		event    string
+		delay    time.Duration
+	}{
+		{at: friday(8, 59), status: metav1.ConditionFalse, delay: time.Minute + time.Second},
+		{at: friday(9, 0), limit: "50Mbps", throttle: 50_000_000, status: metav1.ConditionTrue,
+			event: "Normal BandwidthLimitApplied Bandwidth capped at 50Mbps by window 09:00-17:00", delay: 8*time.Hour + time.Second},
+		{at: friday(16, 59), limit: "50Mbps", throttle: 50_000_000, status: metav1.ConditionTrue, delay: time.Minute + time.Second},
+		{at: friday(17, 0), status: metav1.ConditionFalse,
+			event: "Normal BandwidthLimitRemoved Bandwidth cap of 50Mbps lifted outside its window", delay: 5*time.Hour + time.Second},
+		{at: friday(22, 0), limit: "1Gbps", throttle: 1_000_000_000, status: metav1.ConditionTrue,
+			event: "Normal BandwidthLimitApplied Bandwidth capped at 1Gbps by window 22:00-02:00", delay: 4*time.Hour + time.Second},
+		{at: friday(26, 0), status: metav1.ConditionFalse,
+			event: "Normal BandwidthLimitRemoved Bandwidth cap of 1Gbps lifted outside its window", delay: 20*time.Hour + time.Second},
+		{at: friday(33, 0), status: metav1.ConditionFalse, delay: 13*time.Hour + time.Second},
+	}
+	for _, step := range steps {
+		delay := reconciler.reconcileBandwidthLimit(ctx, adapter, uvr, step.at, logr.Discard())
+		assert.Equal(t, step.delay, delay, step.at)
+
+		assert.Equal(t, step.throttle, adapter.bitsPerSecond, step.at)
+		assert.Equal(t, step.limit, uvr.Status.Qos.BandwidthLimit, step.at)
+		condition := reconciler.getCondition(uvr, bandwidthLimitedCondition)
+		require.NotNil(t, condition)
+		assert.Equal(t, step.status, condition.Status, step.at)
+
+		events := drainEvents(recorder)
+		if step.event == "" {
+			assert.Empty(t, events, step.at)
+		} else {
+			assert.Equal(t, []string{step.event}, events, step.at)
+		}
+	}
+
+	// A cap changed on the backend is put back on the next reconcile
+	reconciler.reconcileBandwidthLimit(ctx, adapter, uvr, friday(9, 30), logr.Discard())
+	drainEvents(recorder)
+	adapter.bitsPerSecond = 1
+	reconciler.reconcileBandwidthLimit(ctx, adapter, uvr, friday(9, 31), logr.Discard())
+	assert.Equal(t, int64(50_000_000), adapter.bitsPerSecond)
+
+	// Removing qos from the spec lifts the cap and clears the status
+	uvr.Spec.Qos = nil
+	assert.Zero(t, reconciler.reconcileBandwidthLimit(ctx, adapter, uvr, friday(9, 32), logr.Discard()))
+	assert.Zero(t, adapter.bitsPerSecond)
+	assert.Nil(t, uvr.Status.Qos)
+	assert.Nil(t, reconciler.getCondition(uvr, bandwidthLimitedCondition))
+	assert.Equal(t, []string{"Normal BandwidthLimitRemoved Bandwidth cap of 50Mbps removed"}, drainEvents(recorder))
+}
+
+func TestReconciler_BandwidthLimitUnsupported(t *testing.T) {
+	ctx := context.Background()
+	uvr := createTestUVR("test-bandwidth-unsupported", "default")
+	uvr.Spec.Qos = businessHoursQos()
+	reconciler, c := newDeactivationTestReconciler(t, adapters.NewTridentAdapterFactory(), uvr)
+	recorder := reconciler.Recorder.(*record.FakeRecorder)
+
+	adapter, err := adapters.NewTridentAdapter(c, reconciler.TranslationEngine)
+	require.NoError(t, err)
+	for range 2 {
+		assert.Zero(t, reconciler.reconcileBandwidthLimit(ctx, adapter, uvr, time.Now(), logr.Discard()))
+	}
+
+	condition := reconciler.getCondition(uvr, bandwidthLimitedCondition)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "Unsupported", condition.Reason)
+	assert.Nil(t, uvr.Status.Qos)
+	assert.Len(t, drainEvents(recorder), 1, "the warning is recorded once")
+}
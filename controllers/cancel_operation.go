@@ -0,0 +1,220 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+const (
+	// CancelOperationAnnotation asks the operator to abort the operation in progress on a
+	// UVR and return to the last observed role. The value is recorded as the reason; the
+	// annotation is removed once the cancellation is acknowledged in
+	// status.cancelledOperation.
+	CancelOperationAnnotation = "replication.storage.io/cancel-operation"
+
+	// defaultCancelPollInterval is how often a running operation checks for the annotation
+	defaultCancelPollInterval = 2 * time.Second
+)
+
+// cancelRequestedPredicate lets through updates that add the cancel-operation annotation,
+// which do not change the generation, so a cancellation is acknowledged promptly
+func cancelRequestedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectNew == nil {
+				return false
+			}
+			_, ok := e.ObjectNew.GetAnnotations()[CancelOperationAnnotation]
+			return ok
+		},
+	}
+}
+
+// getCancelPollInterval returns the configured cancel-operation poll interval
+func (r *UnifiedVolumeReplicationReconciler) getCancelPollInterval() time.Duration {
+	if r.CancelPollInterval > 0 {
+		return r.CancelPollInterval
+	}
+	return defaultCancelPollInterval
+}
+
+// withOperationCancel returns a context that is cancelled once the cancel-operation
+// annotation appears on the UVR. The UVR is not reconciled concurrently, so the annotation
+// is polled while a backend operation runs; between operations it is checked at the start
+// of every reconcile.
+// The returned function stops polling and reports whether cancellation was requested.
+func (r *UnifiedVolumeReplicationReconciler) withOperationCancel(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) (context.Context, func() bool) {
+	operationCtx, cancel := context.WithCancel(ctx)
+	var requested atomic.Bool
+	stopped := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(r.getCancelPollInterval())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopped:
+				return
+			case <-operationCtx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			current := &replicationv1alpha1.UnifiedVolumeReplication{}
+			if err := r.Get(operationCtx, client.ObjectKeyFromObject(uvr), current); err != nil {
+				continue
+			}
+			if _, ok := current.Annotations[CancelOperationAnnotation]; ok {
+				log.Info("Cancelling operation in progress")
+				requested.Store(true)
+				cancel()
+				return
+			}
+		}
+	}()
+
+	return operationCtx, func() bool {
+		close(stopped)
+		<-done
+		cancel()
+		return requested.Load()
+	}
+}
+
+// cancelOperation acknowledges a cancel-operation request. It records the cancellation in
+// status.cancelledOperation, holding the replication at the last role observed on the
+// backend for the current spec generation, removes the annotation and emits an
+// OperationCancelled event. The spec is left as the user wrote it: the next reconcile
+// returns the backend to the held role, and any spec change ends the cancellation.
+func (r *UnifiedVolumeReplicationReconciler) cancelOperation(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, operationErr error, log logr.Logger) (ctrl.Result, error) {
+	current := &replicationv1alpha1.UnifiedVolumeReplication{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(uvr), current); err != nil {
+		return ctrl.Result{}, err
+	}
+	if operationErr != nil {
+		log.V(1).Info("Cancelled operation returned", "error", operationErr.Error())
+	}
+
+	patched := current.DeepCopy()
+	reason := patched.Annotations[CancelOperationAnnotation]
+	delete(patched.Annotations, CancelOperationAnnotation)
+	if err := r.Patch(ctx, patched, client.MergeFrom(current)); err != nil {
+		log.Error(err, "Failed to acknowledge cancelled operation")
+		return ctrl.Result{}, err
+	}
+
+	// The status in memory carries this reconcile's observations; only the metadata and
+	// spec are taken from the server
+	cancelled := uvr.DeepCopy()
+	cancelled.ObjectMeta = patched.ObjectMeta
+	cancelled.Spec = patched.Spec
+	requested := cancelled.Spec.ReplicationState
+	held := lastObservedRole(cancelled)
+	if held == requested {
+		held = ""
+	}
+	cancelled.Status.CancelledOperation = &replicationv1alpha1.CancelledOperation{
+		ObservedGeneration: cancelled.Generation,
+		RequestedState:     requested,
+		HeldState:          held,
+		Reason:             reason,
+		Time:               metav1.Now(),
+	}
+
+	message := cancelledOperationMessage(cancelled.Status.CancelledOperation)
+	log.Info(message)
+	r.updateCondition(cancelled, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             "OperationCancelled",
+		Message:            message,
+		ObservedGeneration: cancelled.Generation,
+	})
+	r.recordEventf(cancelled, corev1.EventTypeWarning, "OperationCancelled", "%s", message)
+
+	r.recordFailedReconcile(cancelled)
+	if err := r.Status().Update(ctx, cancelled); err != nil {
+		log.Error(err, "Failed to update status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: requeueDelayFast}, nil
+}
+
+// applyCancelledOperation enforces an acknowledged cancellation. While the spec stays at the
+// cancelled generation the held role replaces spec.replicationState in memory, so the
+// reconcile returns the backend to it; the spec itself is never changed. It returns true when
+// nothing can be held and no backend operation must be issued. A cancellation of an earlier
+// generation is cleared: the user has asked for something new.
+func (r *UnifiedVolumeReplicationReconciler) applyCancelledOperation(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) bool {
+	cancelled := uvr.Status.CancelledOperation
+	if cancelled == nil {
+		return false
+	}
+	if cancelled.ObservedGeneration != uvr.Generation {
+		log.Info("Spec changed since the operation was cancelled, resuming")
+		uvr.Status.CancelledOperation = nil
+		return false
+	}
+	if cancelled.HeldState == "" {
+		explainf(ctx, "Operation to %s cancelled, nothing is issued until the spec changes", cancelled.RequestedState)
+		return true
+	}
+	explainf(ctx, "Operation to %s cancelled, holding replicationState at %s", cancelled.RequestedState, cancelled.HeldState)
+	uvr.Spec.ReplicationState = cancelled.HeldState
+	return false
+}
+
+// cancelledOperationMessage describes an acknowledged cancellation
+func cancelledOperationMessage(cancelled *replicationv1alpha1.CancelledOperation) string {
+	if cancelled.HeldState == "" {
+		return fmt.Sprintf("Operation to %s cancelled, no role to roll back to; nothing is issued until the spec changes",
+			cancelled.RequestedState)
+	}
+	return fmt.Sprintf("Operation to %s cancelled, holding the replication at %s until the spec changes",
+		cancelled.RequestedState, cancelled.HeldState)
+}
+
+// lastObservedRole returns the most recent source or replica role recorded in the state
+// history, or "" when the backend has not reported one
+func lastObservedRole(uvr *replicationv1alpha1.UnifiedVolumeReplication) replicationv1alpha1.ReplicationState {
+	for i := len(uvr.Status.StateHistory) - 1; i >= 0; i-- {
+		switch state := replicationv1alpha1.ReplicationState(uvr.Status.StateHistory[i].To); state {
+		case replicationv1alpha1.ReplicationStateSource, replicationv1alpha1.ReplicationStateReplica:
+			return state
+		}
+	}
+	return ""
+}
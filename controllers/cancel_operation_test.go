@@ -0,0 +1,148 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg"
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+func TestReconciler_CancelOperation(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+
+	// A replica being promoted
+	uvr := createTestUVR("test-cancel", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+	uvr.Status.StateHistory = []replicationv1alpha1.StateHistoryEntry{
+		{Timestamp: metav1.Now(), To: string(replicationv1alpha1.ReplicationStateReplica)},
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(tridentCRDs(""), uvr)...).
+		WithStatusSubresource(uvr).Build()
+
+	// Every backend operation hangs for far longer than the test
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewMockAdapterFactory(translation.BackendTrident, &adapters.MockConfig{
+		LatencyMin: time.Hour,
+		LatencyMax: time.Hour,
+	})))
+	reconciler := createTestReconciler(c, s)
+	reconciler.AdapterRegistry = registry
+	reconciler.ControllerEngine = pkg.NewControllerEngine(c, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+		registry, pkg.DefaultControllerEngineConfig())
+	reconciler.CancelPollInterval = 10 * time.Millisecond
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+
+	type outcome struct {
+		result ctrl.Result
+		err    error
+	}
+	finished := make(chan outcome, 1)
+	go func() {
+		result, err := reconciler.Reconcile(ctx, req)
+		finished <- outcome{result, err}
+	}()
+
+	// Let the promotion start, then ask for it to be cancelled
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case <-finished:
+		t.Fatal("the slow operation returned before it was cancelled")
+	default:
+	}
+	current := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, current))
+	patched := current.DeepCopy()
+	patched.Annotations = map[string]string{CancelOperationAnnotation: "drill aborted"}
+	require.NoError(t, c.Patch(ctx, patched, client.MergeFrom(current)))
+
+	var done outcome
+	select {
+	case done = <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the operation was not aborted")
+	}
+	require.NoError(t, done.err)
+	assert.Equal(t, requeueDelayFast, done.result.RequeueAfter)
+
+	require.NoError(t, c.Get(ctx, req.NamespacedName, current))
+	assert.Equal(t, replicationv1alpha1.ReplicationStateSource, current.Spec.ReplicationState, "the user's spec is left alone")
+	assert.NotContains(t, current.Annotations, CancelOperationAnnotation)
+	require.NotNil(t, current.Status.CancelledOperation)
+	assert.Equal(t, replicationv1alpha1.ReplicationStateReplica, current.Status.CancelledOperation.HeldState, "the promotion is rolled back")
+	assert.Equal(t, replicationv1alpha1.ReplicationStateSource, current.Status.CancelledOperation.RequestedState)
+	assert.Equal(t, "drill aborted", current.Status.CancelledOperation.Reason)
+	cond := reconciler.getCondition(current, "Ready")
+	require.NotNil(t, cond)
+	assert.Equal(t, "OperationCancelled", cond.Reason)
+	assert.Contains(t, drainEvents(reconciler.Recorder.(*record.FakeRecorder)),
+		"Warning OperationCancelled Operation to source cancelled, holding the replication at replica until the spec changes")
+
+	// Later reconciles hold the observed role without touching the spec
+	held := current.DeepCopy()
+	assert.False(t, reconciler.applyCancelledOperation(ctx, held, reconciler.Log))
+	assert.Equal(t, replicationv1alpha1.ReplicationStateReplica, held.Spec.ReplicationState)
+	assert.NotNil(t, held.Status.CancelledOperation)
+
+	// A spec change ends the cancellation
+	changed := current.DeepCopy()
+	changed.Generation++
+	assert.False(t, reconciler.applyCancelledOperation(ctx, changed, reconciler.Log))
+	assert.Equal(t, replicationv1alpha1.ReplicationStateSource, changed.Spec.ReplicationState)
+	assert.Nil(t, changed.Status.CancelledOperation)
+
+	// Without an observed role nothing is issued until the spec changes
+	fresh := createTestUVR("test-cancel-new", "default")
+	fresh.Annotations = map[string]string{CancelOperationAnnotation: ""}
+	require.NoError(t, c.Create(ctx, fresh))
+	_, err := reconciler.cancelOperation(ctx, fresh, nil, reconciler.Log)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(fresh), current))
+	assert.Equal(t, replicationv1alpha1.ReplicationStateReplica, current.Spec.ReplicationState)
+	assert.NotContains(t, current.Annotations, CancelOperationAnnotation)
+	require.NotNil(t, current.Status.CancelledOperation)
+	assert.Empty(t, current.Status.CancelledOperation.HeldState)
+	assert.True(t, reconciler.applyCancelledOperation(ctx, current, reconciler.Log))
+}
+
+func TestCancelRequestedPredicate(t *testing.T) {
+	uvr := createTestUVR("test-cancel-predicate", "default")
+	cancelled := uvr.DeepCopy()
+	cancelled.Annotations = map[string]string{CancelOperationAnnotation: ""}
+
+	p := cancelRequestedPredicate()
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: uvr, ObjectNew: cancelled}))
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: uvr, ObjectNew: uvr.DeepCopy()}))
+}
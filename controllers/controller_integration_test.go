@@ -87,6 +87,8 @@ func TestControllerIntegration_CreateUpdateDelete(t *testing.T) {
 
 	// Step 4: Update spec
 	t.Log("Step 4: Updating resource spec")
+	// The reconcile updates the managed labels, so work on the latest version
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, updatedUVR))
 	updatedUVR.Spec.ReplicationState = replicationv1alpha1.ReplicationStatePromoting
 	require.NoError(t, fakeClient.Update(ctx, updatedUVR))
 
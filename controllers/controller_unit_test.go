@@ -18,19 +18,25 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
@@ -121,6 +127,62 @@ func TestReconciler_StatusUpdate(t *testing.T) {
 	}
 }
 
+func TestReconciler_LastReconcileReport(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	uvr := createTestUVR("test-last-reconcile", "default")
+	uvr.Generation = 3
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(uvr).
+		WithStatusSubresource(uvr).
+		Build()
+
+	reconciler := createTestReconciler(fakeClient, s)
+
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "test-last-reconcile",
+			Namespace: "default",
+		},
+	}
+
+	// No backends are available, so the reconcile fails and reports Degraded
+	_, _ = reconciler.Reconcile(ctx, req)
+
+	updatedUVR := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, updatedUVR))
+	require.NotNil(t, updatedUVR.Status.LastReconcile)
+	assert.Equal(t, updatedUVR.Generation, updatedUVR.Status.LastReconcile.ObservedGeneration)
+	assert.Equal(t, replicationv1alpha1.ReconcileResultDegraded, updatedUVR.Status.LastReconcile.Result)
+	assert.NotEmpty(t, updatedUVR.Status.LastReconcile.Message)
+	assert.False(t, updatedUVR.Status.LastReconcile.Time.IsZero())
+
+	// Backend still converging on the desired state
+	reconciler.recordLastReconcile(updatedUVR, &adapters.ReplicationStatus{
+		State:  "syncing",
+		Mode:   "asynchronous",
+		Health: adapters.ReplicationHealthHealthy,
+	})
+	assert.Equal(t, replicationv1alpha1.ReconcileResultProgressing, updatedUVR.Status.LastReconcile.Result)
+	assert.Equal(t, "syncing", updatedUVR.Status.LastReconcile.RealizedState)
+
+	// Desired state realized at a newer generation
+	updatedUVR.Generation = 4
+	reconciler.recordLastReconcile(updatedUVR, &adapters.ReplicationStatus{
+		State:  "replica",
+		Mode:   "asynchronous",
+		Health: adapters.ReplicationHealthHealthy,
+	})
+	assert.Equal(t, replicationv1alpha1.ReconcileResultSynced, updatedUVR.Status.LastReconcile.Result)
+	assert.Equal(t, int64(4), updatedUVR.Status.LastReconcile.ObservedGeneration)
+	assert.Equal(t, "replica", updatedUVR.Status.LastReconcile.RealizedState)
+	assert.Equal(t, "asynchronous", updatedUVR.Status.LastReconcile.RealizedMode)
+}
+
 func TestReconciler_Deletion(t *testing.T) {
 	ctx := context.Background()
 	s := createTestScheme(t)
@@ -132,6 +194,7 @@ func TestReconciler_Deletion(t *testing.T) {
 	fakeClient := fake.NewClientBuilder().
 		WithScheme(s).
 		WithObjects(uvr).
+		WithStatusSubresource(uvr).
 		Build()
 
 	reconciler := createTestReconciler(fakeClient, s)
@@ -159,6 +222,325 @@ func TestReconciler_Deletion(t *testing.T) {
 	t.Log("Deletion test passed")
 }
 
+func TestReconciler_InitialSyncComplete(t *testing.T) {
+	s := createTestScheme(t)
+	uvr := createTestUVR("test-initial-sync", "default")
+
+	fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).Build()
+	reconciler := createTestReconciler(fakeClient, s)
+
+	report := func(percent float64) *metav1.Condition {
+		reconciler.updateStatusFromEngineStatus(uvr, &adapters.ReplicationStatus{
+			State:        "replica",
+			Health:       adapters.ReplicationHealthHealthy,
+			SyncProgress: &adapters.SyncProgress{PercentComplete: percent},
+		}, reconciler.Log)
+		return reconciler.getCondition(uvr, "InitialSyncComplete")
+	}
+
+	cond := report(40)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+
+	cond = report(100)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, "InitialSyncCompleted", cond.Reason)
+	completedAt := cond.LastTransitionTime
+
+	// Incremental syncs report partial progress again without clearing the latch
+	for _, percent := range []float64{12, 0, 87} {
+		cond = report(percent)
+		require.NotNil(t, cond)
+		assert.Equal(t, metav1.ConditionTrue, cond.Status)
+		assert.Equal(t, completedAt, cond.LastTransitionTime)
+	}
+}
+
+func TestReconciler_ReadyWithheldUntilInitialSync(t *testing.T) {
+	s := createTestScheme(t)
+	uvr := createTestUVR("test-ready", "default")
+
+	fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).Build()
+	reconciler := createTestReconciler(fakeClient, s)
+
+	ready := func(status *adapters.ReplicationStatus) *metav1.Condition {
+		if status != nil {
+			reconciler.updateStatusFromEngineStatus(uvr, status, reconciler.Log)
+		}
+		reconciler.recordReady(uvr, status)
+		return reconciler.getCondition(uvr, "Ready")
+	}
+
+	cond := ready(nil)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, "StatusUnavailable", cond.Reason)
+
+	// Relationship created but the baseline transfer is still running
+	cond = ready(&adapters.ReplicationStatus{
+		State:        "replica",
+		Health:       adapters.ReplicationHealthHealthy,
+		SyncProgress: &adapters.SyncProgress{PercentComplete: 40},
+	})
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, "Progressing", cond.Reason)
+
+	cond = ready(&adapters.ReplicationStatus{
+		State:        "replica",
+		Health:       adapters.ReplicationHealthHealthy,
+		SyncProgress: &adapters.SyncProgress{PercentComplete: 100},
+	})
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, "ReconciliationSucceeded", cond.Reason)
+
+	// An unhealthy relationship is not usable for DR even after the initial sync
+	cond = ready(&adapters.ReplicationStatus{
+		State:   "replica",
+		Health:  adapters.ReplicationHealthUnhealthy,
+		Message: "mirror broken",
+	})
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, "ReplicationUnhealthy", cond.Reason)
+	assert.Contains(t, cond.Message, "mirror broken")
+
+	// Backends without progress reporting become Ready after their first sync
+	other := createTestUVR("test-ready-last-sync", "default")
+	lastSync := time.Now()
+	reconciler.recordReady(other, &adapters.ReplicationStatus{State: "replica", Health: adapters.ReplicationHealthHealthy})
+	assert.Equal(t, "Progressing", reconciler.getCondition(other, "Ready").Reason)
+	reconciler.recordReady(other, &adapters.ReplicationStatus{State: "replica", Health: adapters.ReplicationHealthHealthy, LastSyncTime: &lastSync})
+	assert.Equal(t, metav1.ConditionTrue, reconciler.getCondition(other, "Ready").Status)
+}
+
+func TestReconciler_EstablishmentDuration(t *testing.T) {
+	s := createTestScheme(t)
+	uvr := createTestUVR("test-establishment", "default")
+
+	fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).Build()
+	reconciler := createTestReconciler(fakeClient, s)
+	uvr.CreationTimestamp = metav1.NewTime(time.Now().Add(-10 * time.Minute))
+
+	observations := func() uint64 {
+		metric := &dto.Metric{}
+		require.NoError(t, establishmentDurationSeconds.WithLabelValues("trident").(prometheus.Histogram).Write(metric))
+		return metric.GetHistogram().GetSampleCount()
+	}
+	report := func(percent float64) {
+		reconciler.updateStatusFromEngineStatus(uvr, &adapters.ReplicationStatus{
+			State:        "replica",
+			Health:       adapters.ReplicationHealthHealthy,
+			SyncProgress: &adapters.SyncProgress{PercentComplete: percent},
+		}, reconciler.Log)
+	}
+	before := observations()
+
+	report(40)
+	assert.Nil(t, uvr.Status.EstablishmentDuration)
+
+	report(100)
+	require.NotNil(t, uvr.Status.EstablishmentDuration)
+	assert.InDelta(t, (10 * time.Minute).Seconds(), uvr.Status.EstablishmentDuration.Seconds(), 5)
+	assert.Equal(t, before+1, observations())
+
+	// Later syncs do not record establishment again
+	report(100)
+	assert.Equal(t, before+1, observations())
+
+	// A full resync clears the duration and measures the next initial sync from the resync
+	reconciler.restartEstablishment(uvr, "Resyncing")
+	assert.Nil(t, uvr.Status.EstablishmentDuration)
+	require.NotNil(t, uvr.Status.EstablishmentStartTime)
+	cond := reconciler.getCondition(uvr, "InitialSyncComplete")
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, "FullResync", cond.Reason)
+
+	report(100)
+	require.NotNil(t, uvr.Status.EstablishmentDuration)
+	assert.Less(t, uvr.Status.EstablishmentDuration.Duration, time.Minute)
+	assert.Equal(t, before+2, observations())
+}
+
+func TestReconciler_StateHistory(t *testing.T) {
+	s := createTestScheme(t)
+	uvr := createTestUVR("test-state-history", "default")
+
+	fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).Build()
+	reconciler := createTestReconciler(fakeClient, s)
+
+	report := func(state string) {
+		reconciler.updateStatusFromEngineStatus(uvr, &adapters.ReplicationStatus{
+			State:  state,
+			Health: adapters.ReplicationHealthHealthy,
+		}, reconciler.Log)
+	}
+
+	// Repeated reports of the same state are not changes
+	report("replica")
+	report("replica")
+	report("promoting")
+	report("source")
+	report("source")
+
+	history := uvr.Status.StateHistory
+	require.Len(t, history, 3)
+	assert.Equal(t, "", history[0].From)
+	assert.Equal(t, "replica", history[0].To)
+	assert.Equal(t, "SpecRequested", history[0].Reason)
+	assert.Equal(t, "replica", history[1].From)
+	assert.Equal(t, "promoting", history[1].To)
+	assert.Equal(t, "BackendReported", history[1].Reason)
+	assert.Equal(t, "promoting", history[2].From)
+	assert.Equal(t, "source", history[2].To)
+	assert.Len(t, reconciler.StateMachine.GetHistory(), 3)
+
+	// Flapping past the cap keeps only the most recent entries, oldest first
+	for i := 0; i < maxStateHistoryEntries; i++ {
+		if i%2 == 0 {
+			report("demoting")
+		} else {
+			report("source")
+		}
+	}
+
+	history = uvr.Status.StateHistory
+	require.Len(t, history, maxStateHistoryEntries)
+	assert.Equal(t, "source", history[0].From, "entries older than the cap are dropped")
+	assert.Equal(t, "demoting", history[0].To)
+	assert.Equal(t, "demoting", history[len(history)-1].From)
+	assert.Equal(t, "source", history[len(history)-1].To)
+	for i := 1; i < len(history); i++ {
+		assert.Equal(t, history[i-1].To, history[i].From)
+		assert.False(t, history[i].Timestamp.Before(&history[i-1].Timestamp))
+	}
+}
+
+func TestReconciler_CurrentStateValidatesTransitions(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	uvr := createTestUVR("test-current-state", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+
+	fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).WithStatusSubresource(uvr).Build()
+	reconciler := createTestReconciler(fakeClient, s)
+
+	// The state the backend reports is kept in status; states outside the enum clear it
+	reconciler.updateStatusFromEngineStatus(uvr, &adapters.ReplicationStatus{State: "unknown"}, reconciler.Log)
+	assert.Empty(t, uvr.Status.CurrentState)
+	reconciler.updateStatusFromEngineStatus(uvr, &adapters.ReplicationStatus{State: "source"}, reconciler.Log)
+	assert.Equal(t, replicationv1alpha1.ReplicationStateSource, reconciler.getCurrentState(uvr))
+	require.NoError(t, fakeClient.Status().Update(ctx, uvr))
+
+	// A source cannot go straight to promoting
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStatePromoting
+	require.NoError(t, fakeClient.Update(ctx, uvr))
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid state transition from source to promoting")
+
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(uvr), uvr))
+	ready := reconciler.getCondition(uvr, "Ready")
+	require.NotNil(t, ready)
+	assert.Equal(t, metav1.ConditionFalse, ready.Status)
+	assert.Equal(t, "InvalidStateTransition", ready.Reason)
+	assert.Equal(t, "Invalid transition from source to promoting", ready.Message)
+
+	// A role change is carried out through its transitional state
+	sm := reconciler.StateMachine
+	assert.NoError(t, sm.ValidateRequestedTransition(replicationv1alpha1.ReplicationStateSource, replicationv1alpha1.ReplicationStateReplica))
+	assert.NoError(t, sm.ValidateRequestedTransition(replicationv1alpha1.ReplicationStateReplica, replicationv1alpha1.ReplicationStateSource))
+	// A replica whose source is lost, or that is resyncing, can still be failed over
+	assert.NoError(t, sm.ValidateRequestedTransition(replicationv1alpha1.ReplicationStateFailed, replicationv1alpha1.ReplicationStateSource))
+	assert.NoError(t, sm.ValidateRequestedTransition(replicationv1alpha1.ReplicationStateSyncing, replicationv1alpha1.ReplicationStateSource))
+	assert.Error(t, sm.ValidateRequestedTransition(replicationv1alpha1.ReplicationStateSource, replicationv1alpha1.ReplicationStateSyncing))
+
+	// A replica the backend reports as failed, after losing its source, is promoted
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(uvr), uvr))
+	uvr.Status.CurrentState = replicationv1alpha1.ReplicationStateFailed
+	require.NoError(t, fakeClient.Status().Update(ctx, uvr))
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+	require.NoError(t, fakeClient.Update(ctx, uvr))
+	_, _ = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)})
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(uvr), uvr))
+	ready = reconciler.getCondition(uvr, "Ready")
+	require.NotNil(t, ready)
+	assert.NotEqual(t, "InvalidStateTransition", ready.Reason)
+}
+
+func TestReconciler_DeletionFailureStaysOnDeletionPath(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	s.AddKnownTypes(schema.GroupVersion{Group: "replication.storage.openshift.io", Version: "v1alpha1"},
+		&adapters.VolumeReplication{}, &adapters.VolumeReplicationList{})
+
+	uvr := createTestUVR("test-delete-retry", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	uvr.Spec.Extensions = &replicationv1alpha1.Extensions{
+		Ceph: &replicationv1alpha1.CephExtensions{},
+	}
+	vr := &adapters.VolumeReplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-delete-retry-vr", Namespace: "default"},
+	}
+
+	failDelete := true
+	var creates, updates int
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(uvr, vr).
+		WithStatusSubresource(uvr).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				creates++
+				return c.Create(ctx, obj, opts...)
+			},
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				updates++
+				return c.Update(ctx, obj, opts...)
+			},
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				if _, ok := obj.(*adapters.VolumeReplication); ok && failDelete {
+					return errors.New("backend unavailable")
+				}
+				return c.Delete(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	reconciler := createTestReconciler(fakeClient, s)
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+
+	require.NoError(t, fakeClient.Delete(ctx, uvr))
+
+	// Backend deletion fails and every requeued reconcile retries deletion only
+	for i := 0; i < 2; i++ {
+		result, err := reconciler.Reconcile(ctx, req)
+		require.Error(t, err)
+		assert.Equal(t, requeueDelayError, result.RequeueAfter)
+
+		current := &replicationv1alpha1.UnifiedVolumeReplication{}
+		require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, current))
+		assert.False(t, current.DeletionTimestamp.IsZero())
+		assert.Equal(t, []string{unifiedReplicationFinalizer}, current.Finalizers)
+
+		cond := reconciler.getCondition(current, "Ready")
+		require.NotNil(t, cond)
+		assert.Equal(t, "DeletionFailed", cond.Reason)
+	}
+	assert.Zero(t, creates, "backend resources must not be re-created during deletion")
+	assert.Zero(t, updates, "finalizer must not be re-added during deletion")
+
+	// Once the backend recovers the finalizer is released
+	failDelete = false
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	err = fakeClient.Get(ctx, req.NamespacedName, &replicationv1alpha1.UnifiedVolumeReplication{})
+	assert.True(t, apierrors.IsNotFound(err))
+	assert.Zero(t, creates)
+}
+
 func TestReconciler_ConditionManagement(t *testing.T) {
 	s := createTestScheme(t)
 	reconciler := createTestReconciler(nil, s)
@@ -204,6 +586,73 @@ func TestReconciler_ConditionManagement(t *testing.T) {
 	t.Log("Condition management test passed")
 }
 
+func TestReconciler_ConditionTransitions(t *testing.T) {
+	s := createTestScheme(t)
+	reconciler := createTestReconciler(nil, s)
+
+	uvr := createTestUVR("test-cond-transitions", "default")
+	uvr.Generation = 1
+
+	reconciler.updateCondition(uvr, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             "Progressing",
+		Message:            "Waiting for the first sync",
+		ObservedGeneration: uvr.Generation,
+	})
+	transitioned := reconciler.getCondition(uvr, "Ready").LastTransitionTime
+	require.False(t, transitioned.IsZero())
+
+	// A message-only update evaluated against a newer generation keeps the transition time
+	time.Sleep(10 * time.Millisecond)
+	uvr.Generation = 2
+	reconciler.updateCondition(uvr, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             "Progressing",
+		Message:            "Still waiting for the first sync",
+		ObservedGeneration: uvr.Generation,
+	})
+	cond := reconciler.getCondition(uvr, "Ready")
+	assert.Equal(t, transitioned, cond.LastTransitionTime, "message-only updates keep the transition time")
+	assert.Equal(t, int64(2), cond.ObservedGeneration)
+	assert.Equal(t, "Still waiting for the first sync", cond.Message)
+
+	// A condition evaluated against an older generation does not claim the newer one
+	reconciler.updateCondition(uvr, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             "OperationCancelled",
+		Message:            "Operation cancelled",
+		ObservedGeneration: 1,
+	})
+	cond = reconciler.getCondition(uvr, "Ready")
+	assert.Equal(t, int64(1), cond.ObservedGeneration)
+	assert.Equal(t, transitioned, cond.LastTransitionTime, "a reason change without a status flip is not a transition")
+
+	// Conditions set without a generation record the generation being reconciled
+	reconciler.updateCondition(uvr, metav1.Condition{
+		Type:    "Synced",
+		Status:  metav1.ConditionTrue,
+		Reason:  "StatusUpdated",
+		Message: "Status synchronized",
+	})
+	assert.Equal(t, int64(2), reconciler.getCondition(uvr, "Synced").ObservedGeneration)
+
+	// A status flip moves the transition time
+	time.Sleep(10 * time.Millisecond)
+	reconciler.updateCondition(uvr, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionTrue,
+		Reason:             "ReconciliationSucceeded",
+		Message:            "Replication is operating normally",
+		ObservedGeneration: uvr.Generation,
+	})
+	cond = reconciler.getCondition(uvr, "Ready")
+	assert.True(t, cond.LastTransitionTime.After(transitioned.Time))
+	assert.Equal(t, int64(2), cond.ObservedGeneration)
+}
+
 // Operation determination tests removed (behavior now handled by EnsureReplication)
 
 func TestReconciler_ErrorHandling(t *testing.T) {
@@ -331,7 +780,7 @@ func createTestReconciler(client client.Client, s *runtime.Scheme) *UnifiedVolum
 		AdapterRegistry:   adapterRegistry,
 		StateMachine:      NewStateMachine(),
 		RetryManager:      NewRetryManager(nil),
-		CircuitBreaker:    NewCircuitBreaker(5, 2, 1*time.Minute),
+		CircuitBreakers:   NewBackendCircuitBreakers(5, 2, 1*time.Minute),
 	}
 }
 
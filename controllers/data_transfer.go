@@ -0,0 +1,97 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// bytesTransferredTotal counts the bytes each replication synced to its destination, so
+// egress cost can be attributed to it
+var bytesTransferredTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "unified_replication_bytes_transferred_total",
+	Help: "Estimated bytes synced to the destination, summed from the sync progress reported by the backend",
+}, []string{"namespace", "name", "backend"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(bytesTransferredTotal)
+}
+
+// recordDataTransfer adds the bytes synced since the previous reconcile to the cumulative
+// transfer in status. Each sync reports progress from zero, so progress lower than last seen
+// means a new sync started and all of it is new. The metric follows once the status is
+// persisted, see publishDataTransfer.
+func (r *UnifiedVolumeReplicationReconciler) recordDataTransfer(uvr *replicationv1alpha1.UnifiedVolumeReplication, status *adapters.ReplicationStatus) {
+	if status.SyncProgress == nil || status.SyncProgress.SyncedBytes < 0 {
+		return
+	}
+
+	transfer := uvr.Status.DataTransfer
+	if transfer == nil {
+		transfer = &replicationv1alpha1.DataTransfer{}
+		uvr.Status.DataTransfer = transfer
+	}
+
+	synced := status.SyncProgress.SyncedBytes
+	delta := synced - transfer.ObservedSyncedBytes
+	if delta < 0 {
+		delta = synced
+	}
+	transfer.ObservedSyncedBytes = synced
+	if delta == 0 {
+		return
+	}
+
+	now := metav1.Now()
+	transfer.BytesTransferred += delta
+	transfer.LastUpdateTime = &now
+}
+
+// dataTransferred returns the cumulative transfer recorded in the UVR's status
+func dataTransferred(uvr *replicationv1alpha1.UnifiedVolumeReplication) int64 {
+	if uvr.Status.DataTransfer == nil {
+		return 0
+	}
+	return uvr.Status.DataTransfer.BytesTransferred
+}
+
+// publishDataTransfer adds the transfer recorded since the status read by the reconcile to
+// the metric. It is called once the status update succeeded, so bytes of a status that was
+// never persisted are not counted twice when the next reconcile records them again.
+func publishDataTransfer(uvr *replicationv1alpha1.UnifiedVolumeReplication, persisted int64) {
+	if delta := dataTransferred(uvr) - persisted; delta > 0 {
+		bytesTransferredTotal.WithLabelValues(uvr.Namespace, uvr.Name, backendPartitionFor(uvr)).Add(float64(delta))
+	}
+}
+
+// restartDataTransfer measures the next progress report from zero after a full resync. The
+// bytes the resync sends are billed again, so the cumulative transfer is kept.
+func restartDataTransfer(uvr *replicationv1alpha1.UnifiedVolumeReplication) {
+	if uvr.Status.DataTransfer != nil {
+		uvr.Status.DataTransfer.ObservedSyncedBytes = 0
+	}
+}
+
+// forgetDataTransfer drops the transfer metric of a deleted replication
+func forgetDataTransfer(uvr *replicationv1alpha1.UnifiedVolumeReplication) {
+	bytesTransferredTotal.DeletePartialMatch(prometheus.Labels{"namespace": uvr.Namespace, "name": uvr.Name})
+}
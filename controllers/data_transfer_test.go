@@ -0,0 +1,91 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestReconciler_DataTransfer(t *testing.T) {
+	s := createTestScheme(t)
+	uvr := createTestUVR("test-data-transfer", "default")
+
+	fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).Build()
+	reconciler := createTestReconciler(fakeClient, s)
+	defer forgetDataTransfer(uvr)
+
+	transferred := func() float64 {
+		metric := &dto.Metric{}
+		require.NoError(t, bytesTransferredTotal.WithLabelValues("default", "test-data-transfer", "trident").(prometheus.Counter).Write(metric))
+		return metric.GetCounter().GetValue()
+	}
+	record := func(uvr *replicationv1alpha1.UnifiedVolumeReplication, synced int64) {
+		reconciler.updateStatusFromEngineStatus(uvr, &adapters.ReplicationStatus{
+			State:        "replica",
+			Health:       adapters.ReplicationHealthHealthy,
+			SyncProgress: &adapters.SyncProgress{TotalBytes: 1000, SyncedBytes: synced},
+		}, reconciler.Log)
+	}
+	// report records the progress and persists the status
+	report := func(synced int64) {
+		persisted := dataTransferred(uvr)
+		record(uvr, synced)
+		publishDataTransfer(uvr, persisted)
+	}
+
+	// The initial sync progresses across reconciles
+	report(300)
+	report(1000)
+	require.NotNil(t, uvr.Status.DataTransfer)
+	assert.Equal(t, int64(1000), uvr.Status.DataTransfer.BytesTransferred)
+	assert.Equal(t, float64(1000), transferred())
+
+	// Unchanged progress transfers nothing more
+	report(1000)
+	assert.Equal(t, int64(1000), uvr.Status.DataTransfer.BytesTransferred)
+
+	// An incremental sync reports progress from zero again
+	report(200)
+	report(250)
+	assert.Equal(t, int64(1250), uvr.Status.DataTransfer.BytesTransferred)
+	assert.Equal(t, float64(1250), transferred())
+
+	// A status that fails to persist is not counted; the next reconcile records the
+	// progress again from the persisted status
+	record(uvr.DeepCopy(), 500)
+	assert.Equal(t, float64(1250), transferred())
+	report(500)
+	assert.Equal(t, int64(1500), uvr.Status.DataTransfer.BytesTransferred)
+	assert.Equal(t, float64(1500), transferred())
+
+	// A full resync starts over and sends the whole volume again
+	reconciler.restartEstablishment(uvr, "Resyncing from scratch")
+	report(400)
+	assert.Equal(t, int64(1900), uvr.Status.DataTransfer.BytesTransferred)
+	assert.Equal(t, int64(400), uvr.Status.DataTransfer.ObservedSyncedBytes)
+	assert.Equal(t, float64(1900), transferred())
+	assert.NotNil(t, uvr.Status.DataTransfer.LastUpdateTime)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// deactivatedCondition is True while the UVR is deactivated and its backend relationship dormant
+const deactivatedCondition = "Deactivated"
+
+// reconcileDeactivation leaves the backend relationship of a UVR with spec.deactivated dormant,
+// and reactivates it once the flag is cleared. It returns true with the result the reconcile
+// should end with while the UVR is deactivated or its deactivation failed; nothing else is
+// changed on the backend then. After a reactivation the reconcile continues and
+// EnsureReplication restores the settings the deactivation cleared.
+func (r *UnifiedVolumeReplicationReconciler) reconcileDeactivation(ctx context.Context, adapter adapters.ReplicationAdapter, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) (bool, ctrl.Result) {
+	existing := r.getCondition(uvr, deactivatedCondition)
+	dormant := existing != nil && existing.Status == metav1.ConditionTrue
+	deactivator, supported := adapter.(adapters.ReplicationDeactivator)
+
+	if !uvr.Spec.Deactivated {
+		if !dormant {
+			return false, ctrl.Result{}
+		}
+		if supported {
+			if err := r.ControllerEngine.RunOperation(ctx, uvr, "reactivate", log, func(ctx context.Context) error {
+				return deactivator.ReactivateReplication(ctx, uvr)
+			}); err != nil {
+				log.Error(err, "Failed to reactivate replication")
+				r.recordEventf(uvr, corev1.EventTypeWarning, "ReactivationFailed", "Failed to reactivate replication: %v", err)
+				r.setDeactivatedReady(uvr, "ReactivationFailed", fmt.Sprintf("Failed to reactivate replication: %v", err))
+				r.recordFailedReconcile(uvr)
+				return true, ctrl.Result{RequeueAfter: requeueDelayError}
+			}
+		}
+		log.Info("Replication reactivated")
+		r.recordEventf(uvr, corev1.EventTypeNormal, "Reactivated", "Replication reactivated, syncing resumes")
+		r.updateCondition(uvr, metav1.Condition{
+			Type:               deactivatedCondition,
+			Status:             metav1.ConditionFalse,
+			Reason:             "Reactivated",
+			Message:            "Replication reactivated",
+			ObservedGeneration: uvr.Generation,
+		})
+		return false, ctrl.Result{}
+	}
+
+	if !supported {
+		message := fmt.Sprintf("The %s backend cannot deactivate a replication, it keeps syncing; clear spec.deactivated or delete the replication", adapter.GetBackendType())
+		if existing == nil || existing.Reason != "DeactivationUnsupported" {
+			r.recordEventf(uvr, corev1.EventTypeWarning, "DeactivationUnsupported", "%s", message)
+		}
+		r.updateCondition(uvr, metav1.Condition{
+			Type:               deactivatedCondition,
+			Status:             metav1.ConditionFalse,
+			Reason:             "DeactivationUnsupported",
+			Message:            message,
+			ObservedGeneration: uvr.Generation,
+		})
+		r.setDeactivatedReady(uvr, "DeactivationUnsupported", message)
+		r.recordFailedReconcile(uvr)
+		return true, ctrl.Result{}
+	}
+
+	if !dormant {
+		if err := r.ControllerEngine.RunOperation(ctx, uvr, "deactivate", log, func(ctx context.Context) error {
+			return deactivator.DeactivateReplication(ctx, uvr)
+		}); err != nil {
+			log.Error(err, "Failed to deactivate replication")
+			r.recordEventf(uvr, corev1.EventTypeWarning, "DeactivationFailed", "Failed to deactivate replication: %v", err)
+			r.setDeactivatedReady(uvr, "DeactivationFailed", fmt.Sprintf("Failed to deactivate replication: %v", err))
+			r.recordFailedReconcile(uvr)
+			return true, ctrl.Result{RequeueAfter: requeueDelayError}
+		}
+		log.Info("Replication deactivated")
+		r.recordEventf(uvr, corev1.EventTypeNormal, "Deactivated", "Replication deactivated, the backend relationship is dormant")
+	}
+
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               deactivatedCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Dormant",
+		Message:            "Syncs are stopped, the backend relationship is kept",
+		ObservedGeneration: uvr.Generation,
+	})
+	r.setDeactivatedReady(uvr, "Deactivated", "Replication is deactivated, clear spec.deactivated to resume syncing")
+	uvr.Status.LastReconcile = &replicationv1alpha1.LastReconcile{
+		ObservedGeneration: uvr.Generation,
+		Result:             replicationv1alpha1.ReconcileResultSynced,
+		Message:            "Replication is deactivated",
+		Time:               metav1.Now(),
+	}
+	return true, ctrl.Result{}
+}
+
+// setDeactivatedReady reports a deactivated or not yet deactivated UVR as not ready
+func (r *UnifiedVolumeReplicationReconciler) setDeactivatedReady(uvr *replicationv1alpha1.UnifiedVolumeReplication, reason, message string) {
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+}
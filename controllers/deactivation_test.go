@@ -0,0 +1,150 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg"
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+func newDeactivationTestReconciler(t *testing.T, factory adapters.AdapterFactory, uvr *replicationv1alpha1.UnifiedVolumeReplication) (*UnifiedVolumeReplicationReconciler, client.Client) {
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(tridentCRDs(""), uvr)...).
+		WithStatusSubresource(uvr).Build()
+
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(factory))
+	reconciler := createTestReconciler(c, s)
+	reconciler.AdapterRegistry = registry
+	reconciler.ControllerEngine = pkg.NewControllerEngine(c, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+		registry, pkg.DefaultControllerEngineConfig())
+	return reconciler, c
+}
+
+func TestReconciler_DeactivateAndReactivate(t *testing.T) {
+	ctx := context.Background()
+	uvr := createTestUVR("test-deactivate", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	uvr.Spec.Schedule = replicationv1alpha1.Schedule{Mode: replicationv1alpha1.ScheduleModeInterval, Rpo: "15m"}
+	reconciler, c := newDeactivationTestReconciler(t, adapters.NewTridentAdapterFactory(), uvr)
+	recorder := reconciler.Recorder.(*record.FakeRecorder)
+	key := client.ObjectKeyFromObject(uvr)
+
+	reconcile := func() ctrl.Result {
+		t.Helper()
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+		require.NoError(t, c.Get(ctx, key, uvr))
+		return result
+	}
+	relationship := func() *unstructured.Unstructured {
+		t.Helper()
+		tmr := &unstructured.Unstructured{}
+		tmr.SetGroupVersionKind(adapters.TridentMirrorRelationshipGVK)
+		require.NoError(t, c.Get(ctx, key, tmr), "the backend resource is kept")
+		return tmr
+	}
+	setDeactivated := func(deactivated bool) {
+		t.Helper()
+		uvr.Spec.Deactivated = deactivated
+		uvr.Generation++
+		require.NoError(t, c.Update(ctx, uvr))
+	}
+
+	reconcile()
+	schedule, found, _ := unstructured.NestedString(relationship().Object, "spec", "replicationSchedule")
+	require.True(t, found)
+	assert.Equal(t, "15m", schedule)
+	drainEvents(recorder)
+
+	// Deactivation stops the schedule but keeps the relationship
+	setDeactivated(true)
+	result := reconcile()
+	assert.Zero(t, result.RequeueAfter)
+	tmr := relationship()
+	_, found, _ = unstructured.NestedString(tmr.Object, "spec", "replicationSchedule")
+	assert.False(t, found, "scheduled syncs are stopped")
+	assert.Contains(t, tmr.GetAnnotations(), adapters.DeactivatedAnnotation)
+	state, _, _ := unstructured.NestedString(tmr.Object, "spec", "state")
+	assert.Equal(t, "established", state, "the relationship stays established")
+
+	deactivated := reconciler.getCondition(uvr, deactivatedCondition)
+	require.NotNil(t, deactivated)
+	assert.Equal(t, metav1.ConditionTrue, deactivated.Status)
+	assert.Equal(t, "Dormant", deactivated.Reason)
+	ready := reconciler.getCondition(uvr, "Ready")
+	require.NotNil(t, ready)
+	assert.Equal(t, metav1.ConditionFalse, ready.Status)
+	assert.Equal(t, "Deactivated", ready.Reason)
+	events := drainEvents(recorder)
+	require.Len(t, events, 1)
+	assert.Contains(t, events[0], "Deactivated")
+
+	// Later reconciles leave the dormant relationship alone
+	reconcile()
+	_, found, _ = unstructured.NestedString(relationship().Object, "spec", "replicationSchedule")
+	assert.False(t, found)
+	assert.Empty(t, drainEvents(recorder))
+
+	// Reactivation restores the schedule
+	setDeactivated(false)
+	reconcile()
+	tmr = relationship()
+	schedule, _, _ = unstructured.NestedString(tmr.Object, "spec", "replicationSchedule")
+	assert.Equal(t, "15m", schedule)
+	assert.NotContains(t, tmr.GetAnnotations(), adapters.DeactivatedAnnotation)
+	deactivated = reconciler.getCondition(uvr, deactivatedCondition)
+	require.NotNil(t, deactivated)
+	assert.Equal(t, metav1.ConditionFalse, deactivated.Status)
+	assert.Equal(t, "Reactivated", deactivated.Reason)
+	assert.NotEqual(t, "Deactivated", reconciler.getCondition(uvr, "Ready").Reason)
+}
+
+func TestReconciler_DeactivationUnsupported(t *testing.T) {
+	ctx := context.Background()
+	uvr := createTestUVR("test-deactivate-unsupported", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	uvr.Spec.Deactivated = true
+	factory := adapters.NewBaseAdapterFactory(translation.BackendTrident, "Base Adapter", "1.0.0", "Cannot deactivate")
+	reconciler, c := newDeactivationTestReconciler(t, factory, uvr)
+
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)})
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(uvr), uvr))
+
+	deactivated := reconciler.getCondition(uvr, deactivatedCondition)
+	require.NotNil(t, deactivated)
+	assert.Equal(t, metav1.ConditionFalse, deactivated.Status)
+	assert.Equal(t, "DeactivationUnsupported", deactivated.Reason)
+	assert.Equal(t, "DeactivationUnsupported", reconciler.getCondition(uvr, "Ready").Reason)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+// defaultReplicationModes maps each backend to the mode used when the spec omits one.
+// PowerStore favours Metro (synchronous) replication, while Ceph RBD mirroring and
+// Trident mirror relationships are asynchronous by nature.
+var defaultReplicationModes = map[translation.Backend]replicationv1alpha1.ReplicationMode{
+	translation.BackendPowerStore: replicationv1alpha1.ReplicationModeSynchronous,
+	translation.BackendCeph:       replicationv1alpha1.ReplicationModeAsynchronous,
+	translation.BackendTrident:    replicationv1alpha1.ReplicationModeAsynchronous,
+}
+
+// modeFeatures maps replication modes to the adapter feature required to run them
+var modeFeatures = map[replicationv1alpha1.ReplicationMode]adapters.AdapterFeature{
+	replicationv1alpha1.ReplicationModeSynchronous:  adapters.FeatureSyncReplication,
+	replicationv1alpha1.ReplicationModeAsynchronous: adapters.FeatureAsyncReplication,
+}
+
+// resolveReplicationMode picks the replication mode for a spec that omits one. The
+// backend default is used when the adapter supports it, otherwise the other mode the
+// adapter supports.
+func resolveReplicationMode(adapter adapters.ReplicationAdapter) (replicationv1alpha1.ReplicationMode, error) {
+	supported := make(map[adapters.AdapterFeature]bool)
+	for _, feature := range adapter.GetSupportedFeatures() {
+		supported[feature] = true
+	}
+
+	candidates := []replicationv1alpha1.ReplicationMode{
+		replicationv1alpha1.ReplicationModeAsynchronous,
+		replicationv1alpha1.ReplicationModeSynchronous,
+	}
+	if preferred, ok := defaultReplicationModes[adapter.GetBackendType()]; ok {
+		candidates = append([]replicationv1alpha1.ReplicationMode{preferred}, candidates...)
+	}
+
+	for _, mode := range candidates {
+		if supported[modeFeatures[mode]] {
+			return mode, nil
+		}
+	}
+
+	return "", fmt.Errorf("backend %s supports neither synchronous nor asynchronous replication", adapter.GetBackendType())
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg"
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+// featureLimitedAdapter overrides the features an adapter advertises
+type featureLimitedAdapter struct {
+	adapters.ReplicationAdapter
+	features []adapters.AdapterFeature
+}
+
+func (a *featureLimitedAdapter) GetSupportedFeatures() []adapters.AdapterFeature {
+	return a.features
+}
+
+func TestResolveReplicationMode(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(createTestScheme(t)).Build()
+
+	ceph, err := adapters.NewCephAdapter(c, translation.NewEngine())
+	require.NoError(t, err)
+	powerstore, err := adapters.NewPowerStoreAdapter(c, translation.NewEngine())
+	require.NoError(t, err)
+	trident, err := adapters.NewTridentAdapter(c, translation.NewEngine())
+	require.NoError(t, err)
+
+	t.Run("BackendDefaults", func(t *testing.T) {
+		mode, err := resolveReplicationMode(ceph)
+		require.NoError(t, err)
+		assert.Equal(t, replicationv1alpha1.ReplicationModeAsynchronous, mode)
+
+		mode, err = resolveReplicationMode(powerstore)
+		require.NoError(t, err)
+		assert.Equal(t, replicationv1alpha1.ReplicationModeSynchronous, mode)
+
+		mode, err = resolveReplicationMode(trident)
+		require.NoError(t, err)
+		assert.Equal(t, replicationv1alpha1.ReplicationModeAsynchronous, mode)
+	})
+
+	t.Run("FallsBackWhenDefaultUnsupported", func(t *testing.T) {
+		limited := &featureLimitedAdapter{
+			ReplicationAdapter: powerstore,
+			features:           []adapters.AdapterFeature{adapters.FeatureAsyncReplication},
+		}
+		mode, err := resolveReplicationMode(limited)
+		require.NoError(t, err)
+		assert.Equal(t, replicationv1alpha1.ReplicationModeAsynchronous, mode)
+	})
+
+	t.Run("NoSupportedMode", func(t *testing.T) {
+		limited := &featureLimitedAdapter{ReplicationAdapter: ceph}
+		_, err := resolveReplicationMode(limited)
+		assert.Error(t, err)
+	})
+}
+
+func TestReconciler_DefaultReplicationMode(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+
+	uvr := createTestUVR("test-default-mode", "default")
+	uvr.Spec.ReplicationMode = ""
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(tridentCRDs(""), uvr)...).
+		WithStatusSubresource(uvr).Build()
+
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewTridentAdapterFactory()))
+	reconciler := createTestReconciler(c, s)
+	reconciler.AdapterRegistry = registry
+	reconciler.ControllerEngine = pkg.NewControllerEngine(c, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+		registry, pkg.DefaultControllerEngineConfig())
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	updated := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	assert.Empty(t, updated.Spec.ReplicationMode, "the default is not written back to the spec")
+	require.NotNil(t, updated.Status.EffectiveConfig)
+	assert.Equal(t, replicationv1alpha1.ReplicationModeAsynchronous, updated.Status.EffectiveConfig.ReplicationMode)
+	assert.Equal(t, "Async", updated.Status.EffectiveConfig.BackendMode)
+	assert.True(t, updated.Status.EffectiveConfig.ReplicationModeDefaulted)
+}
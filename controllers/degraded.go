@@ -0,0 +1,125 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// degradedCondition is True while the replication is degraded; its reason is one of the
+// adapters.DegradedReason values so alerts can match on the cause
+const degradedCondition = "Degraded"
+
+// parseScheduleDuration parses RPO/RTO values such as "15m", "1h30m" or "1d", falling back to
+// Go durations for values the schedule pattern does not cover
+func parseScheduleDuration(value string) (time.Duration, error) {
+	if duration, err := replicationv1alpha1.ParseScheduleDuration(value); err == nil {
+		return duration, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// syncLag is the time since the last sync measured against a limit from the schedule
+type syncLag struct {
+	// Lag is the time since the last sync, rounded to the second
+	Lag time.Duration
+	// Limit is the schedule duration the lag is measured against
+	Limit time.Duration
+	// Exceeded is true while the lag is beyond the limit
+	Exceeded bool
+}
+
+// lagLimit parses a schedule duration bounding the lag, such as spec.schedule.rpo or maxLag.
+// ok is false when it is unset, does not parse or is not positive.
+func lagLimit(value string) (time.Duration, bool) {
+	limit, err := parseScheduleDuration(value)
+	if value == "" || err != nil || limit <= 0 {
+		return 0, false
+	}
+	return limit, true
+}
+
+// lastSyncOf returns the last sync the backend reports, or else the last one recorded on the
+// UVR, so a replication that stopped reporting syncs keeps aging. Nil before the first sync.
+func lastSyncOf(uvr *replicationv1alpha1.UnifiedVolumeReplication, status *adapters.ReplicationStatus) *time.Time {
+	if status.LastSyncTime != nil {
+		return status.LastSyncTime
+	}
+	if uvr.Status.LastSyncTime != nil {
+		return &uvr.Status.LastSyncTime.Time
+	}
+	return nil
+}
+
+// measureSyncLag measures the time since lastSync against limit. The RPO checks and the
+// write pause all measure through it, so the lag they report and their verdicts agree.
+func measureSyncLag(lastSync time.Time, limit time.Duration) syncLag {
+	lag := time.Since(lastSync).Round(time.Second)
+	return syncLag{Lag: lag, Limit: limit, Exceeded: lag > limit}
+}
+
+// checkSyncLag marks an otherwise healthy status as degraded when the last sync is older
+// than the RPO in the UVR schedule, as measured by checkRPO
+func (r *UnifiedVolumeReplicationReconciler) checkSyncLag(uvr *replicationv1alpha1.UnifiedVolumeReplication, status *adapters.ReplicationStatus, rpo syncLag) {
+	if !rpo.Exceeded || status.Health != adapters.ReplicationHealthHealthy {
+		return
+	}
+	status.Health = adapters.ReplicationHealthDegraded
+	status.DegradedReason = adapters.DegradedReasonSyncLagExceeded
+	status.Message = fmt.Sprintf("Last sync %s ago exceeds RPO %s", rpo.Lag, uvr.Spec.Schedule.Rpo)
+}
+
+// recordDegraded sets the Degraded condition from the backend-reported health
+func (r *UnifiedVolumeReplicationReconciler) recordDegraded(uvr *replicationv1alpha1.UnifiedVolumeReplication, status *adapters.ReplicationStatus) {
+	switch status.Health {
+	case adapters.ReplicationHealthDegraded, adapters.ReplicationHealthUnhealthy:
+		reason := status.DegradedReason
+		if reason == "" {
+			reason = adapters.DegradedReasonUnknown
+		}
+		r.setDegraded(uvr, reason, fmt.Sprintf("Replication health is %s: %s", status.Health, status.Message))
+	case adapters.ReplicationHealthHealthy:
+		reason, message := "Healthy", "Replication is healthy"
+		if status.DegradedReason == adapters.DegradedReasonUnknownState {
+			reason, message = "UnknownStateIgnored", status.Message
+		}
+		r.updateCondition(uvr, metav1.Condition{
+			Type:               degradedCondition,
+			Status:             metav1.ConditionFalse,
+			Reason:             reason,
+			Message:            message,
+			ObservedGeneration: uvr.Generation,
+		})
+	}
+}
+
+// setDegraded raises the Degraded condition with a taxonomy reason
+func (r *UnifiedVolumeReplicationReconciler) setDegraded(uvr *replicationv1alpha1.UnifiedVolumeReplication, reason adapters.DegradedReason, message string) {
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               degradedCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             string(reason),
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestDegradedReason(t *testing.T) {
+	s := createTestScheme(t)
+
+	recent := time.Now().Add(-time.Minute)
+	stale := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name   string
+		status *adapters.ReplicationStatus
+		want   metav1.ConditionStatus
+		reason string
+	}{
+		{
+			name:   "Healthy",
+			status: &adapters.ReplicationStatus{State: "replica", Health: adapters.ReplicationHealthHealthy, LastSyncTime: &recent},
+			want:   metav1.ConditionFalse,
+			reason: "Healthy",
+		},
+		{
+			name:   "SyncLagExceeded",
+			status: &adapters.ReplicationStatus{State: "replica", Health: adapters.ReplicationHealthHealthy, LastSyncTime: &stale},
+			want:   metav1.ConditionTrue,
+			reason: string(adapters.DegradedReasonSyncLagExceeded),
+		},
+		{
+			name: "SessionFailure",
+			status: &adapters.ReplicationStatus{State: "replica", Health: adapters.ReplicationHealthDegraded,
+				DegradedReason: adapters.DegradedReasonSessionFailure, Message: "Session connectivity issues"},
+			want:   metav1.ConditionTrue,
+			reason: string(adapters.DegradedReasonSessionFailure),
+		},
+		{
+			// A backend-specific reason takes precedence over sync lag
+			name: "BackendReasonWins",
+			status: &adapters.ReplicationStatus{State: "replica", Health: adapters.ReplicationHealthUnhealthy,
+				DegradedReason: adapters.DegradedReasonBackendError, LastSyncTime: &stale},
+			want:   metav1.ConditionTrue,
+			reason: string(adapters.DegradedReasonBackendError),
+		},
+		{
+			name:   "Unclassified",
+			status: &adapters.ReplicationStatus{State: "replica", Health: adapters.ReplicationHealthDegraded},
+			want:   metav1.ConditionTrue,
+			reason: string(adapters.DegradedReasonUnknown),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uvr := createTestUVR("test-degraded", "default")
+			reconciler := createTestReconciler(fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).Build(), s)
+
+			reconciler.updateStatusFromEngineStatus(uvr, tt.status, reconciler.Log)
+
+			cond := reconciler.getCondition(uvr, degradedCondition)
+			require.NotNil(t, cond)
+			assert.Equal(t, tt.want, cond.Status)
+			assert.Equal(t, tt.reason, cond.Reason)
+		})
+	}
+}
+
+func TestDegradedReason_InvalidTransition(t *testing.T) {
+	s := createTestScheme(t)
+	uvr := createTestUVR("test-degraded-transition", "default")
+	reconciler := createTestReconciler(fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).Build(), s)
+
+	reconciler.setDegraded(uvr, adapters.DegradedReasonInvalidTransition, "Invalid transition from replica to demoting")
+
+	cond := reconciler.getCondition(uvr, degradedCondition)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, "InvalidTransition", cond.Reason)
+}
+
+func TestParseScheduleDuration(t *testing.T) {
+	d, err := parseScheduleDuration("15m")
+	require.NoError(t, err)
+	assert.Equal(t, 15*time.Minute, d)
+
+	d, err = parseScheduleDuration("2d")
+	require.NoError(t, err)
+	assert.Equal(t, 48*time.Hour, d)
+
+	d, err = parseScheduleDuration("1d12h")
+	require.NoError(t, err)
+	assert.Equal(t, 36*time.Hour, d)
+
+	_, err = parseScheduleDuration("xd")
+	assert.Error(t, err)
+}
+
+func TestSyncLagChecksAgree(t *testing.T) {
+	s := createTestScheme(t)
+	uvr := createTestUVR("test-sync-lag-agree", "default")
+	uvr.Spec.Schedule.Rpo = "1m"
+	uvr.Spec.Schedule.MaxLag = "1m"
+	reconciler := createTestReconciler(fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).Build(), s)
+
+	// The backend stopped reporting syncs; every check ages the last recorded one
+	stale := metav1.NewTime(time.Now().Add(-5 * time.Minute))
+	uvr.Status.LastSyncTime = &stale
+	reconciler.updateStatusFromEngineStatus(uvr,
+		&adapters.ReplicationStatus{State: "replica", Health: adapters.ReplicationHealthHealthy}, reconciler.Log)
+
+	assert.True(t, uvr.Status.RPOViolated)
+	assert.Equal(t, metav1.ConditionFalse, reconciler.getCondition(uvr, rpoCompliantCondition).Status)
+	degraded := reconciler.getCondition(uvr, degradedCondition)
+	require.NotNil(t, degraded)
+	assert.Equal(t, string(adapters.DegradedReasonSyncLagExceeded), degraded.Reason)
+	assert.Equal(t, metav1.ConditionTrue, reconciler.getCondition(uvr, writesShouldPauseCondition).Status)
+
+	// The lag they report is measured the same way
+	assert.Equal(t, "5m0s", uvr.Status.RPO.Lag)
+	assert.Contains(t, degraded.Message, "Last sync 5m0s ago exceeds RPO 1m")
+	assert.Contains(t, reconciler.getCondition(uvr, writesShouldPauseCondition).Message, "Replication lag 5m0s exceeds")
+
+	lag, ok := lagLimit("")
+	assert.False(t, ok)
+	assert.Zero(t, lag)
+}
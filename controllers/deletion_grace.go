@@ -0,0 +1,151 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// DeletionSyncPolicy selects whether deleting a UVR waits for an in-progress sync to finish
+// before the backend replication is torn down
+type DeletionSyncPolicy string
+
+const (
+	// DeletionSyncImmediate tears the backend replication down straight away, interrupting
+	// any sync in progress
+	DeletionSyncImmediate DeletionSyncPolicy = "immediate"
+	// DeletionSyncWait holds the deletion until the backend reports no sync in progress, or
+	// until the deletion sync timeout passes, so the destination is left at a complete
+	// recovery point
+	DeletionSyncWait DeletionSyncPolicy = "wait"
+)
+
+// defaultDeletionSyncTimeout bounds the wait for a sync when no timeout is configured
+const defaultDeletionSyncTimeout = 5 * time.Minute
+
+// deletionWaitingForSyncReason is the Ready reason of a deletion held for a sync
+const deletionWaitingForSyncReason = "DeletionWaitingForSync"
+
+// ParseDeletionSyncPolicy validates a deletion sync policy name, defaulting to immediate
+// when empty
+func ParseDeletionSyncPolicy(value string) (DeletionSyncPolicy, error) {
+	switch policy := DeletionSyncPolicy(value); policy {
+	case "":
+		return DeletionSyncImmediate, nil
+	case DeletionSyncImmediate, DeletionSyncWait:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown deletion sync policy %q, must be one of: %s, %s",
+			value, DeletionSyncWait, DeletionSyncImmediate)
+	}
+}
+
+// getDeletionSyncPolicy returns the configured policy, immediate when unset
+func (r *UnifiedVolumeReplicationReconciler) getDeletionSyncPolicy() DeletionSyncPolicy {
+	if r.DeletionSyncPolicy == "" {
+		return DeletionSyncImmediate
+	}
+	return r.DeletionSyncPolicy
+}
+
+// getDeletionSyncTimeout returns the configured timeout, defaultDeletionSyncTimeout when unset
+func (r *UnifiedVolumeReplicationReconciler) getDeletionSyncTimeout() time.Duration {
+	if r.DeletionSyncTimeout <= 0 {
+		return defaultDeletionSyncTimeout
+	}
+	return r.DeletionSyncTimeout
+}
+
+// syncInProgress reports whether the backend signals a sync in progress, either state syncing
+// or a resync, with a description of how far it got. Progress alone is not a signal: backends
+// that cannot measure it report a partial or zero percentage for a settled replication.
+func syncInProgress(status *adapters.ReplicationStatus) (bool, string) {
+	var signal string
+	switch {
+	case replicationv1alpha1.ReplicationState(status.State) == replicationv1alpha1.ReplicationStateSyncing:
+		signal = "backend state syncing"
+	case status.DegradedReason == adapters.DegradedReasonResyncing:
+		signal = "backend resyncing"
+	default:
+		return false, ""
+	}
+	if status.SyncProgress != nil && status.SyncProgress.PercentComplete < 100 {
+		signal = fmt.Sprintf("%s, %.0f%% complete", signal, status.SyncProgress.PercentComplete)
+	}
+	return true, signal
+}
+
+// awaitSyncBeforeDeletion holds a deletion while the backend reports a sync in progress, so
+// tearing the replication down does not leave the destination half-written. The wait is
+// bounded by the deletion sync timeout, counted from the deletion timestamp; once it passes,
+// or when the status cannot be read, the deletion goes ahead. Returns true with the result
+// the reconcile should end with while the deletion is held.
+func (r *UnifiedVolumeReplicationReconciler) awaitSyncBeforeDeletion(ctx context.Context, adapter adapters.ReplicationAdapter, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) (bool, ctrl.Result) {
+	if r.getDeletionSyncPolicy() != DeletionSyncWait {
+		return false, ctrl.Result{}
+	}
+
+	status, err := adapter.GetReplicationStatus(ctx, uvr)
+	if err != nil {
+		log.Info("Cannot read replication status, deleting without waiting for a sync", "error", err.Error())
+		return false, ctrl.Result{}
+	}
+	syncing, progress := syncInProgress(status)
+	if !syncing {
+		return false, ctrl.Result{}
+	}
+
+	timeout := r.getDeletionSyncTimeout()
+	deadline := time.Now()
+	if uvr.DeletionTimestamp != nil {
+		deadline = uvr.DeletionTimestamp.Add(timeout)
+	}
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		log.Info("Sync still in progress after the deletion sync timeout, deleting anyway", "progress", progress, "timeout", timeout)
+		r.Recorder.Eventf(uvr, corev1.EventTypeWarning, "DeletionSyncTimedOut",
+			"Sync still in progress (%s) after %s, deleting anyway; the destination may hold a partial sync", progress, timeout)
+		return false, ctrl.Result{}
+	}
+
+	if cond := r.getCondition(uvr, "Ready"); cond == nil || cond.Reason != deletionWaitingForSyncReason {
+		r.Recorder.Eventf(uvr, corev1.EventTypeNormal, deletionWaitingForSyncReason,
+			"Waiting up to %s for the in-progress sync (%s) to finish before deleting", timeout, progress)
+	}
+	log.Info("Waiting for the in-progress sync to finish before deleting", "progress", progress, "remaining", remaining)
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             deletionWaitingForSyncReason,
+		Message:            fmt.Sprintf("Waiting for the in-progress sync (%s) to finish before deleting, at most until %s", progress, deadline.UTC().Format(time.RFC3339)),
+		ObservedGeneration: uvr.Generation,
+	})
+	if err := r.Status().Update(ctx, uvr); err != nil {
+		log.Error(err, "Failed to update status")
+	}
+	return true, ctrl.Result{RequeueAfter: min(requeueDelayFast, remaining)}
+}
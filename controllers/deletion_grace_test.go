@@ -0,0 +1,167 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestParseDeletionSyncPolicy(t *testing.T) {
+	policy, err := ParseDeletionSyncPolicy("")
+	require.NoError(t, err)
+	assert.Equal(t, DeletionSyncImmediate, policy)
+
+	policy, err = ParseDeletionSyncPolicy("wait")
+	require.NoError(t, err)
+	assert.Equal(t, DeletionSyncWait, policy)
+
+	_, err = ParseDeletionSyncPolicy("drain")
+	assert.Error(t, err)
+}
+
+func TestSyncInProgress(t *testing.T) {
+	// Progress below 100% on a settled replication does not count as a sync
+	syncing, _ := syncInProgress(&adapters.ReplicationStatus{
+		State:        "replica",
+		SyncProgress: &adapters.SyncProgress{PercentComplete: 0},
+	})
+	assert.False(t, syncing)
+
+	syncing, progress := syncInProgress(&adapters.ReplicationStatus{
+		State:          "replica",
+		DegradedReason: adapters.DegradedReasonResyncing,
+		SyncProgress:   &adapters.SyncProgress{PercentComplete: 40},
+	})
+	assert.True(t, syncing)
+	assert.Equal(t, "backend resyncing, 40% complete", progress)
+
+	syncing, progress = syncInProgress(&adapters.ReplicationStatus{State: "syncing"})
+	assert.True(t, syncing)
+	assert.Equal(t, "backend state syncing", progress)
+}
+
+func TestReconciler_DeletionWaitsForSync(t *testing.T) {
+	ctx := context.Background()
+
+	// setup establishes a replication, reports a sync in progress on it and deletes it
+	setup := func(t *testing.T, name string, policy DeletionSyncPolicy, timeout time.Duration) (*UnifiedVolumeReplicationReconciler, client.Client, ctrl.Request) {
+		uvr := createTestUVR(name, "default")
+		uvr.Finalizers = []string{unifiedReplicationFinalizer}
+		reconciler, c := newDeactivationTestReconciler(t, adapters.NewTridentAdapterFactory(), uvr)
+		reconciler.DeletionSyncPolicy = policy
+		reconciler.DeletionSyncTimeout = timeout
+		req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+
+		_, err := reconciler.Reconcile(ctx, req)
+		require.NoError(t, err)
+		setTransferState(t, c, req, "established-syncing")
+		require.NoError(t, c.Get(ctx, req.NamespacedName, uvr))
+		require.NoError(t, c.Delete(ctx, uvr))
+		drainEvents(reconciler.Recorder.(*record.FakeRecorder))
+		return reconciler, c, req
+	}
+	relationshipExists := func(t *testing.T, c client.Client, req ctrl.Request) bool {
+		tmr := &unstructured.Unstructured{}
+		tmr.SetGroupVersionKind(adapters.TridentMirrorRelationshipGVK)
+		err := c.Get(ctx, req.NamespacedName, tmr)
+		if errors.IsNotFound(err) {
+			return false
+		}
+		require.NoError(t, err)
+		return true
+	}
+	uvrExists := func(t *testing.T, c client.Client, req ctrl.Request) bool {
+		err := c.Get(ctx, req.NamespacedName, createTestUVR(req.Name, req.Namespace))
+		if errors.IsNotFound(err) {
+			return false
+		}
+		require.NoError(t, err)
+		return true
+	}
+
+	t.Run("WaitsUntilSyncSettles", func(t *testing.T) {
+		reconciler, c, req := setup(t, "test-deletion-wait", DeletionSyncWait, time.Hour)
+		recorder := reconciler.Recorder.(*record.FakeRecorder)
+
+		// The deletion is held while the sync runs, and announced once
+		for range 2 {
+			result, err := reconciler.Reconcile(ctx, req)
+			require.NoError(t, err)
+			assert.Equal(t, requeueDelayFast, result.RequeueAfter)
+			assert.True(t, relationshipExists(t, c, req), "the relationship is kept while the sync runs")
+		}
+		events := drainEvents(recorder)
+		require.Len(t, events, 1)
+		assert.Contains(t, events[0], "Normal DeletionWaitingForSync Waiting up to 1h0m0s for the in-progress sync (backend state syncing) to finish before deleting")
+
+		held := createTestUVR(req.Name, req.Namespace)
+		require.NoError(t, c.Get(ctx, req.NamespacedName, held))
+		ready := meta.FindStatusCondition(held.Status.Conditions, "Ready")
+		require.NotNil(t, ready)
+		assert.Equal(t, deletionWaitingForSyncReason, ready.Reason)
+
+		// Once the sync settles the replication is torn down
+		setTransferState(t, c, req, "established")
+		_, err := reconciler.Reconcile(ctx, req)
+		require.NoError(t, err)
+		assert.False(t, relationshipExists(t, c, req))
+		assert.False(t, uvrExists(t, c, req))
+	})
+
+	t.Run("TimesOut", func(t *testing.T) {
+		reconciler, c, req := setup(t, "test-deletion-timeout", DeletionSyncWait, time.Nanosecond)
+
+		_, err := reconciler.Reconcile(ctx, req)
+		require.NoError(t, err)
+		assert.False(t, relationshipExists(t, c, req))
+		assert.False(t, uvrExists(t, c, req))
+		assert.Contains(t, drainEvents(reconciler.Recorder.(*record.FakeRecorder)),
+			"Warning DeletionSyncTimedOut Sync still in progress (backend state syncing) after 1ns, deleting anyway; the destination may hold a partial sync")
+	})
+
+	t.Run("Immediate", func(t *testing.T) {
+		reconciler, c, req := setup(t, "test-deletion-immediate", DeletionSyncImmediate, time.Hour)
+
+		_, err := reconciler.Reconcile(ctx, req)
+		require.NoError(t, err)
+		assert.False(t, relationshipExists(t, c, req))
+		assert.False(t, uvrExists(t, c, req))
+	})
+}
+
+// setTransferState sets the state the TridentMirrorRelationship reports
+func setTransferState(t *testing.T, c client.Client, req ctrl.Request, state string) {
+	t.Helper()
+	tmr := &unstructured.Unstructured{}
+	tmr.SetGroupVersionKind(adapters.TridentMirrorRelationshipGVK)
+	require.NoError(t, c.Get(context.Background(), req.NamespacedName, tmr))
+	require.NoError(t, unstructured.SetNestedField(tmr.Object, state, "status", "state"))
+	require.NoError(t, c.Update(context.Background(), tmr))
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// destinationFullCondition is True while the replication is paused because the destination
+// storage class is nearly out of space
+const destinationFullCondition = "DestinationFull"
+
+// guardDestinationCapacity pauses the replication when the free space published for the
+// destination storage class drops below DestinationFreeSpaceThreshold, so syncs do not drive
+// the destination pool to 100%, and resumes it once space has been reclaimed. Only a pause
+// made here is undone here. Storage classes whose driver publishes no capacity are skipped.
+func (r *UnifiedVolumeReplicationReconciler) guardDestinationCapacity(ctx context.Context, adapter adapters.ReplicationAdapter, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) {
+	if r.DestinationFreeSpaceThreshold <= 0 {
+		return
+	}
+	reserver, ok := adapter.(adapters.CapacityReserver)
+	if !ok {
+		return
+	}
+
+	storageClass := uvr.Spec.DestinationEndpoint.StorageClass
+	info, err := reserver.GetCapacityInfo(ctx, storageClass)
+	if err != nil {
+		log.Error(err, "Failed to read destination capacity", "storageClass", storageClass)
+		return
+	}
+	if info == nil {
+		return
+	}
+
+	free := resource.NewQuantity(info.FreeBytes(), resource.BinarySI).String()
+	threshold := resource.NewQuantity(r.DestinationFreeSpaceThreshold, resource.BinarySI).String()
+	existing := r.getCondition(uvr, destinationFullCondition)
+	paused := pausedForFullDestination(existing)
+
+	if info.FreeBytes() < r.DestinationFreeSpaceThreshold {
+		if paused {
+			return
+		}
+		message := fmt.Sprintf("Destination storage class %s has %s free, below the %s threshold; replication paused", storageClass, free, threshold)
+		if err := r.ControllerEngine.RunOperation(ctx, uvr, "pause", log, func(ctx context.Context) error {
+			return adapter.PauseReplication(ctx, uvr)
+		}); err != nil {
+			log.Error(err, "Failed to pause replication on a full destination", "storageClass", storageClass)
+			message = fmt.Sprintf("Destination storage class %s has %s free, below the %s threshold; pausing failed: %v", storageClass, free, threshold, err)
+			if existing == nil || existing.Reason != "PauseFailed" {
+				r.recordEventf(uvr, corev1.EventTypeWarning, "DestinationFull", "%s", message)
+			}
+			r.setDestinationFull(uvr, metav1.ConditionFalse, "PauseFailed", message)
+			return
+		}
+		log.Info("Paused replication, destination is nearly full", "storageClass", storageClass, "free", free)
+		r.recordEventf(uvr, corev1.EventTypeWarning, "DestinationFull", "%s", message)
+		r.setDestinationFull(uvr, metav1.ConditionTrue, "ReplicationPaused", message)
+		return
+	}
+
+	if !paused {
+		if existing != nil && existing.Reason == "PauseFailed" {
+			r.setDestinationFull(uvr, metav1.ConditionFalse, "SufficientFreeSpace",
+				fmt.Sprintf("Destination storage class %s has %s free", storageClass, free))
+		}
+		return
+	}
+
+	if err := r.ControllerEngine.RunOperation(ctx, uvr, "resume", log, func(ctx context.Context) error {
+		return adapter.ResumeReplication(ctx, uvr)
+	}); err != nil {
+		// Stay paused and try again on the next reconcile
+		log.Error(err, "Failed to resume replication after destination capacity was reclaimed", "storageClass", storageClass)
+		r.recordEventf(uvr, corev1.EventTypeWarning, "DestinationResumeFailed", "Failed to resume replication: %v", err)
+		return
+	}
+	message := fmt.Sprintf("Destination storage class %s has %s free again; replication resumed", storageClass, free)
+	log.Info("Resumed replication, destination capacity reclaimed", "storageClass", storageClass, "free", free)
+	r.recordEventf(uvr, corev1.EventTypeNormal, "DestinationCapacityReclaimed", "%s", message)
+	r.setDestinationFull(uvr, metav1.ConditionFalse, "CapacityReclaimed", message)
+}
+
+// pausedForFullDestination reports whether the DestinationFull condition records that the
+// operator paused the replication
+func pausedForFullDestination(condition *metav1.Condition) bool {
+	return condition != nil && condition.Status == metav1.ConditionTrue
+}
+
+// setDestinationFull records whether the replication is paused for a full destination
+func (r *UnifiedVolumeReplicationReconciler) setDestinationFull(uvr *replicationv1alpha1.UnifiedVolumeReplication, status metav1.ConditionStatus, reason, message string) {
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               destinationFullCondition,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestReconciler_PausesOnFullDestination(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	// The CSI driver publishes the free space of the destination storage class
+	uvr := createTestUVR("test-destination-full", "default")
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+	capacity := &storagev1.CSIStorageCapacity{
+		ObjectMeta:       metav1.ObjectMeta{Name: "fast-ssd-capacity", Namespace: "default"},
+		StorageClassName: uvr.Spec.DestinationEndpoint.StorageClass,
+		Capacity:         resource.NewQuantity(100<<30, resource.BinarySI),
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(uvr, capacity).Build()
+	reconciler := createTestReconciler(c, s)
+	reconciler.DestinationFreeSpaceThreshold = 10 << 30
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(uvr), uvr))
+	adapter := newSplitBrainMockAdapter(t, c, uvr)
+
+	setFree := func(bytes int64) {
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(capacity), capacity))
+		capacity.Capacity = resource.NewQuantity(bytes, resource.BinarySI)
+		require.NoError(t, c.Update(ctx, capacity))
+	}
+	replicationState := func() string {
+		repl, ok := adapter.GetMockReplication(uvr)
+		require.True(t, ok)
+		return repl.State
+	}
+
+	// Plenty of room: nothing happens
+	reconciler.guardDestinationCapacity(ctx, adapter, uvr, reconciler.Log)
+	assert.Nil(t, reconciler.getCondition(uvr, destinationFullCondition))
+	assert.Equal(t, "source", replicationState())
+
+	// The destination shrinks below the threshold and the replication is paused once
+	setFree(5 << 30)
+	reconciler.guardDestinationCapacity(ctx, adapter, uvr, reconciler.Log)
+	cond := reconciler.getCondition(uvr, destinationFullCondition)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, "ReplicationPaused", cond.Reason)
+	assert.Contains(t, cond.Message, "5Gi free, below the 10Gi threshold")
+	assert.Equal(t, "paused", replicationState())
+
+	setFree(2 << 30)
+	reconciler.guardDestinationCapacity(ctx, adapter, uvr, reconciler.Log)
+	repl, _ := adapter.GetMockReplication(uvr)
+	pauses := 0
+	for _, event := range repl.Events {
+		if event.Type == adapters.EventTypePaused {
+			pauses++
+		}
+	}
+	assert.Equal(t, 1, pauses, "an already paused replication is not paused again")
+
+	// Reclaiming space resumes it
+	setFree(50 << 30)
+	reconciler.guardDestinationCapacity(ctx, adapter, uvr, reconciler.Log)
+	cond = reconciler.getCondition(uvr, destinationFullCondition)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, "CapacityReclaimed", cond.Reason)
+	assert.NotEqual(t, "paused", replicationState())
+	repl, _ = adapter.GetMockReplication(uvr)
+	assert.Equal(t, adapters.EventTypeResumed, repl.Events[len(repl.Events)-1].Type)
+}
+
+func TestReconciler_DestinationCapacityCheckDisabled(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	uvr := createTestUVR("test-destination-unchecked", "default")
+	capacity := &storagev1.CSIStorageCapacity{
+		ObjectMeta:       metav1.ObjectMeta{Name: "fast-ssd-capacity", Namespace: "default"},
+		StorageClassName: uvr.Spec.DestinationEndpoint.StorageClass,
+		Capacity:         resource.NewQuantity(1<<30, resource.BinarySI),
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(uvr, capacity).Build()
+	reconciler := createTestReconciler(c, s)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(uvr), uvr))
+	adapter := newSplitBrainMockAdapter(t, c, uvr)
+
+	// Without a threshold a nearly full destination is left to the backend
+	reconciler.guardDestinationCapacity(ctx, adapter, uvr, reconciler.Log)
+	assert.Nil(t, reconciler.getCondition(uvr, destinationFullCondition))
+	repl, ok := adapter.GetMockReplication(uvr)
+	require.True(t, ok)
+	assert.NotEqual(t, "paused", repl.State)
+}
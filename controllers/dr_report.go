@@ -0,0 +1,160 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+// DRReportColumns are the CSV columns of a DR report, in order
+var DRReportColumns = []string{
+	"namespace", "name", "backend", "mode", "rpoTarget", "lag", "lastSyncTime", "phase", "rpoCompliant", "estimatedFailoverTime",
+}
+
+// DRReportEntry is the DR readiness of one replication, read from its status
+type DRReportEntry struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Backend   string `json:"backend"`
+	Mode      string `json:"mode"`
+
+	// RPOTarget is the RPO from the spec, empty when none is set
+	RPOTarget string `json:"rpoTarget,omitempty"`
+
+	// Lag is the time since the last sync, empty before the first sync
+	Lag string `json:"lag,omitempty"`
+
+	LastSyncTime *time.Time `json:"lastSyncTime,omitempty"`
+
+	// Phase is the result of the latest reconcile: Synced, Progressing or Degraded
+	Phase string `json:"phase,omitempty"`
+
+	// RPOCompliant is true when the replication has synced and its lag is within the RPO
+	RPOCompliant bool `json:"rpoCompliant"`
+
+	// EstimatedFailoverTime is how long a failover is expected to take before the replica
+	// is current: nothing for synchronous replication, one final sync otherwise. Empty when
+	// no sync duration has been observed yet.
+	EstimatedFailoverTime string `json:"estimatedFailoverTime,omitempty"`
+}
+
+// ListDRReport reads all UVRs, or those of one namespace, and builds a DR report from them.
+// It only reads from the API server.
+func ListDRReport(ctx context.Context, c client.Reader, namespace string, now time.Time) ([]DRReportEntry, error) {
+	list := &replicationv1alpha1.UnifiedVolumeReplicationList{}
+	if err := c.List(ctx, list, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list UnifiedVolumeReplications: %w", err)
+	}
+	return BuildDRReport(list.Items, now), nil
+}
+
+// BuildDRReport builds the report entries for uvrs as of now, sorted by namespace and name
+func BuildDRReport(uvrs []replicationv1alpha1.UnifiedVolumeReplication, now time.Time) []DRReportEntry {
+	entries := make([]DRReportEntry, 0, len(uvrs))
+	for i := range uvrs {
+		entries = append(entries, drReportEntry(&uvrs[i], now))
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Namespace != entries[j].Namespace {
+			return entries[i].Namespace < entries[j].Namespace
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+func drReportEntry(uvr *replicationv1alpha1.UnifiedVolumeReplication, now time.Time) DRReportEntry {
+	entry := DRReportEntry{
+		Namespace: uvr.Namespace,
+		Name:      uvr.Name,
+		Backend:   backendPartitionFor(uvr),
+		Mode:      string(uvr.Spec.ReplicationMode),
+		RPOTarget: uvr.Spec.Schedule.Rpo,
+	}
+	if config := uvr.Status.EffectiveConfig; config != nil {
+		if config.Backend != "" {
+			entry.Backend = config.Backend
+		}
+		if config.ReplicationMode != "" {
+			entry.Mode = string(config.ReplicationMode)
+		}
+	}
+	if uvr.Status.LastReconcile != nil {
+		entry.Phase = string(uvr.Status.LastReconcile.Result)
+	}
+
+	if uvr.Status.LastSyncTime != nil {
+		lastSync := uvr.Status.LastSyncTime.UTC()
+		lag := now.Sub(lastSync).Round(time.Second)
+		entry.LastSyncTime = &lastSync
+		entry.Lag = lag.String()
+		entry.RPOCompliant = true
+		if entry.RPOTarget != "" {
+			if rpo, err := parseScheduleDuration(entry.RPOTarget); err == nil {
+				entry.RPOCompliant = lag <= rpo
+			}
+		}
+	}
+
+	switch {
+	case entry.Mode == string(replicationv1alpha1.ReplicationModeSynchronous):
+		entry.EstimatedFailoverTime = time.Duration(0).String()
+	case uvr.Status.ComputedSchedule != nil && uvr.Status.ComputedSchedule.ObservedSyncDuration != nil:
+		entry.EstimatedFailoverTime = uvr.Status.ComputedSchedule.ObservedSyncDuration.Duration.Round(time.Second).String()
+	}
+	return entry
+}
+
+// WriteDRReportCSV writes the entries as CSV with a DRReportColumns header
+func WriteDRReportCSV(w io.Writer, entries []DRReportEntry) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(DRReportColumns); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		lastSync := ""
+		if entry.LastSyncTime != nil {
+			lastSync = entry.LastSyncTime.Format(time.RFC3339)
+		}
+		if err := out.Write([]string{
+			entry.Namespace, entry.Name, entry.Backend, entry.Mode, entry.RPOTarget, entry.Lag,
+			lastSync, entry.Phase, strconv.FormatBool(entry.RPOCompliant), entry.EstimatedFailoverTime,
+		}); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// WriteDRReportJSON writes the entries as an indented JSON array
+func WriteDRReportJSON(w io.Writer, entries []DRReportEntry) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(entries)
+}
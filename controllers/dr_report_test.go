@@ -0,0 +1,150 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func drReportFixtures(now time.Time) []replicationv1alpha1.UnifiedVolumeReplication {
+	// Within its 15m RPO
+	current := createTestUVR("db", "prod")
+	current.Status.LastSyncTime = &metav1.Time{Time: now.Add(-5 * time.Minute)}
+	current.Status.LastReconcile = &replicationv1alpha1.LastReconcile{Result: replicationv1alpha1.ReconcileResultSynced}
+	current.Status.ComputedSchedule = &replicationv1alpha1.ComputedSchedule{
+		Interval:             "5m",
+		ObservedSyncDuration: &metav1.Duration{Duration: 90 * time.Second},
+	}
+
+	// Behind its RPO
+	behind := createTestUVR("logs", "prod")
+	behind.Status.LastSyncTime = &metav1.Time{Time: now.Add(-time.Hour)}
+	behind.Status.LastReconcile = &replicationv1alpha1.LastReconcile{Result: replicationv1alpha1.ReconcileResultDegraded}
+	behind.Status.EffectiveConfig = &replicationv1alpha1.EffectiveConfig{Backend: "trident", ReplicationMode: replicationv1alpha1.ReplicationModeAsynchronous}
+
+	// Synchronous and never synced
+	pending := createTestUVR("cache", "dev")
+	pending.Spec.ReplicationMode = replicationv1alpha1.ReplicationModeSynchronous
+
+	return []replicationv1alpha1.UnifiedVolumeReplication{*current, *behind, *pending}
+}
+
+func TestBuildDRReport(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	entries := BuildDRReport(drReportFixtures(now), now)
+	require.Len(t, entries, 3)
+
+	// Sorted by namespace and name
+	pending, current, behind := entries[0], entries[1], entries[2]
+	assert.Equal(t, "dev/cache", pending.Namespace+"/"+pending.Name)
+	assert.Equal(t, "prod/db", current.Namespace+"/"+current.Name)
+	assert.Equal(t, "prod/logs", behind.Namespace+"/"+behind.Name)
+
+	assert.Equal(t, DRReportEntry{
+		Namespace:             "prod",
+		Name:                  "db",
+		Backend:               "trident",
+		Mode:                  "asynchronous",
+		RPOTarget:             "15m",
+		Lag:                   "5m0s",
+		LastSyncTime:          &[]time.Time{now.Add(-5 * time.Minute)}[0],
+		Phase:                 "Synced",
+		RPOCompliant:          true,
+		EstimatedFailoverTime: "1m30s",
+	}, current)
+
+	assert.Equal(t, "1h0m0s", behind.Lag)
+	assert.False(t, behind.RPOCompliant)
+	assert.Equal(t, "Degraded", behind.Phase)
+	assert.Empty(t, behind.EstimatedFailoverTime, "no sync duration observed")
+
+	assert.Nil(t, pending.LastSyncTime)
+	assert.Empty(t, pending.Lag)
+	assert.False(t, pending.RPOCompliant, "a replication that never synced does not meet its RPO")
+	assert.Equal(t, "0s", pending.EstimatedFailoverTime)
+}
+
+func TestWriteDRReport(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	entries := BuildDRReport(drReportFixtures(now), now)
+
+	var out bytes.Buffer
+	require.NoError(t, WriteDRReportCSV(&out, entries))
+	records, err := csv.NewReader(&out).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.Equal(t, DRReportColumns, records[0])
+	assert.Equal(t, []string{"dev", "cache", "trident", "synchronous", "15m", "", "", "", "false", "0s"}, records[1])
+	assert.Equal(t, []string{"prod", "db", "trident", "asynchronous", "15m", "5m0s", "2024-06-01T11:55:00Z", "Synced", "true", "1m30s"}, records[2])
+
+	out.Reset()
+	require.NoError(t, WriteDRReportJSON(&out, entries))
+	var decoded []map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	require.Len(t, decoded, 3)
+	assert.Equal(t, "logs", decoded[2]["name"])
+	assert.Equal(t, false, decoded[2]["rpoCompliant"])
+	assert.Equal(t, "2024-06-01T11:00:00Z", decoded[2]["lastSyncTime"])
+}
+
+func TestListDRReport(t *testing.T) {
+	now := time.Now()
+	s := createTestScheme(t)
+	builder := fake.NewClientBuilder().WithScheme(s)
+	for _, uvr := range drReportFixtures(now) {
+		builder = builder.WithObjects(uvr.DeepCopy())
+	}
+	c := builder.Build()
+
+	entries, err := ListDRReport(context.Background(), c, "", now)
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+
+	entries, err = ListDRReport(context.Background(), c, "prod", now)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "db", entries[0].Name)
+	assert.Equal(t, "logs", entries[1].Name)
+}
+
+func TestReconciler_RecordsLastSyncTime(t *testing.T) {
+	s := createTestScheme(t)
+	reconciler := createTestReconciler(fake.NewClientBuilder().WithScheme(s).Build(), s)
+	uvr := createTestUVR("test-last-sync", "default")
+
+	lastSync := time.Now().Add(-time.Minute).Truncate(time.Second)
+	reconciler.updateStatusFromEngineStatus(uvr, &adapters.ReplicationStatus{State: "source", Health: adapters.ReplicationHealthHealthy, LastSyncTime: &lastSync}, reconciler.Log)
+	require.NotNil(t, uvr.Status.LastSyncTime)
+	assert.True(t, lastSync.Equal(uvr.Status.LastSyncTime.Time))
+
+	// A status without a sync time keeps the last one
+	reconciler.updateStatusFromEngineStatus(uvr, &adapters.ReplicationStatus{State: "source", Health: adapters.ReplicationHealthHealthy}, reconciler.Log)
+	assert.True(t, lastSync.Equal(uvr.Status.LastSyncTime.Time))
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"slices"
+
+	"github.com/go-logr/logr"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// recordEffectiveConfig records the configuration the operator applied to the backend,
+// after backend selection, mode translation and operator-chosen defaults such as the
+// computed sync interval in auto mode. modeDefaulted reports that the replication mode
+// was chosen by the operator because the spec left it unset.
+func (r *UnifiedVolumeReplicationReconciler) recordEffectiveConfig(adapter adapters.ReplicationAdapter, uvr *replicationv1alpha1.UnifiedVolumeReplication, modeDefaulted bool, log logr.Logger) {
+	backend := adapter.GetBackendType()
+	config := &replicationv1alpha1.EffectiveConfig{
+		Backend:         string(backend),
+		ReplicationMode: uvr.Spec.ReplicationMode,
+		ScheduleMode:    uvr.Spec.Schedule.Mode,
+		SyncInterval:    uvr.SyncInterval(),
+		Rpo:             uvr.Spec.Schedule.Rpo,
+
+		ReplicationModeDefaulted: modeDefaulted,
+	}
+
+	if r.TranslationEngine != nil {
+		if mode, err := r.TranslationEngine.TranslateModeToBackend(backend, string(uvr.Spec.ReplicationMode)); err == nil {
+			config.BackendMode = mode
+		} else {
+			log.V(1).Info("Unable to translate mode for effective config", "error", err.Error())
+		}
+	}
+
+	if reporter, ok := adapter.(adapters.EffectiveConfigReporter); ok {
+		config.ReplicationClass, config.Extensions = reporter.EffectiveBackendConfig(uvr)
+	}
+	config.ActiveFeatures = activeFeatures(adapter.GetSupportedFeatures(), uvr)
+
+	uvr.Status.EffectiveConfig = config
+}
+
+// alwaysActiveFeatures are used by every replication on a backend that supports them: the
+// backend resyncs and reports on its own, without anything to enable in the spec
+var alwaysActiveFeatures = []adapters.AdapterFeature{
+	adapters.FeatureAutoResync,
+	adapters.FeatureMetrics,
+	adapters.FeatureProgressTracking,
+	adapters.FeatureRealTimeStatus,
+}
+
+// activeFeatures returns the features among those supported by the backend that the UVR
+// relies on, given its spec and replica readability
+func activeFeatures(supported []adapters.AdapterFeature, uvr *replicationv1alpha1.UnifiedVolumeReplication) []string {
+	var wanted []adapters.AdapterFeature
+	switch uvr.Spec.ReplicationMode {
+	case replicationv1alpha1.ReplicationModeAsynchronous:
+		wanted = append(wanted, adapters.FeatureAsyncReplication)
+		if uvr.Spec.Schedule.Mode != replicationv1alpha1.ScheduleModeContinuous {
+			wanted = append(wanted, adapters.FeatureScheduledSync)
+		}
+	case replicationv1alpha1.ReplicationModeSynchronous:
+		wanted = append(wanted, adapters.FeatureSyncReplication)
+	}
+	if uvr.Spec.Extensions != nil && uvr.Spec.Extensions.Ceph != nil && uvr.Spec.Extensions.Ceph.MirroringMode != nil {
+		switch *uvr.Spec.Extensions.Ceph.MirroringMode {
+		case "journal":
+			wanted = append(wanted, adapters.FeatureJournalBased)
+		case "snapshot":
+			wanted = append(wanted, adapters.FeatureSnapshotBased)
+		}
+	}
+	if len(uvr.Spec.GroupMembers) > 0 {
+		wanted = append(wanted, adapters.FeatureVolumeGroups, adapters.FeatureConsistencyGroups)
+	}
+	if uvr.Spec.VolumeMapping.Destination.AutoCreate != nil && *uvr.Spec.VolumeMapping.Destination.AutoCreate {
+		wanted = append(wanted, adapters.FeatureVolumeProvisioning)
+	}
+	if uvr.Status.ReplicaReadable != nil && uvr.Status.ReplicaReadable.Readable {
+		wanted = append(wanted, adapters.FeatureReplicaRead)
+	}
+	wanted = append(wanted, alwaysActiveFeatures...)
+
+	var active []string
+	for _, feature := range wanted {
+		if slices.Contains(supported, feature) {
+			active = append(active, string(feature))
+		}
+	}
+	return active
+}
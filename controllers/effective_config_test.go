@@ -0,0 +1,168 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg"
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+func TestReconciler_RecordEffectiveConfig(t *testing.T) {
+	s := createTestScheme(t)
+	c := fake.NewClientBuilder().WithScheme(s).Build()
+	reconciler := createTestReconciler(c, s)
+
+	t.Run("CephDefaultsAndComputedInterval", func(t *testing.T) {
+		adapter, err := adapters.NewCephAdapter(c, translation.NewEngine())
+		require.NoError(t, err)
+
+		// The spec names no replication class and asks the operator to pick the interval
+		uvr := createTestUVR("ceph-effective", "default")
+		uvr.Spec.Extensions = &replicationv1alpha1.Extensions{Ceph: &replicationv1alpha1.CephExtensions{}}
+		uvr.Spec.Schedule.Mode = replicationv1alpha1.ScheduleModeAuto
+		uvr.Status.ComputedSchedule = &replicationv1alpha1.ComputedSchedule{Interval: "4m", LastUpdateTime: metav1.Now()}
+
+		reconciler.recordEffectiveConfig(adapter, uvr, false, reconciler.Log)
+		config := uvr.Status.EffectiveConfig
+		require.NotNil(t, config)
+		assert.Equal(t, "ceph", config.Backend)
+		assert.Equal(t, replicationv1alpha1.ReplicationModeAsynchronous, config.ReplicationMode)
+		assert.Equal(t, "async", config.BackendMode)
+		assert.Equal(t, replicationv1alpha1.ScheduleModeAuto, config.ScheduleMode)
+		assert.Equal(t, "4m", config.SyncInterval, "computed interval, not the RPO")
+		assert.Equal(t, "15m", config.Rpo)
+		assert.Equal(t, "rbd-volumereplicationclass", config.ReplicationClass)
+		assert.Equal(t, []string{"AsyncReplication", "AutoResync", "Metrics", "ProgressTracking", "RealTimeStatus"},
+			config.ActiveFeatures)
+	})
+
+	t.Run("TridentTranslatedMode", func(t *testing.T) {
+		adapter, err := adapters.NewTridentAdapter(c, translation.NewEngine())
+		require.NoError(t, err)
+
+		uvr := createTestUVR("trident-effective", "default")
+		uvr.Spec.ReplicationMode = replicationv1alpha1.ReplicationModeSynchronous
+
+		reconciler.recordEffectiveConfig(adapter, uvr, false, reconciler.Log)
+		config := uvr.Status.EffectiveConfig
+		require.NotNil(t, config)
+		assert.Equal(t, "trident", config.Backend)
+		assert.Equal(t, "Sync", config.BackendMode)
+		assert.Equal(t, "15m", config.SyncInterval, "interval mode falls back to the RPO")
+		assert.Empty(t, config.ReplicationClass)
+		assert.Equal(t, map[string]string{"volumeGroupName": "trident-effective-vg"}, config.Extensions)
+		assert.Equal(t, []string{"SyncReplication"}, config.ActiveFeatures)
+	})
+}
+
+func TestActiveFeatures(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(createTestScheme(t)).Build()
+	ceph, err := adapters.NewCephAdapter(c, translation.NewEngine())
+	require.NoError(t, err)
+	trident, err := adapters.NewTridentAdapter(c, translation.NewEngine())
+	require.NoError(t, err)
+	autoCreate := true
+	snapshot, journal := "snapshot", "journal"
+
+	tests := []struct {
+		name     string
+		adapter  adapters.ReplicationAdapter
+		mutate   func(uvr *replicationv1alpha1.UnifiedVolumeReplication)
+		expected []string
+	}{
+		{
+			name:    "CephSnapshotMirroringWithProvisioning",
+			adapter: ceph,
+			mutate: func(uvr *replicationv1alpha1.UnifiedVolumeReplication) {
+				uvr.Spec.Extensions = &replicationv1alpha1.Extensions{Ceph: &replicationv1alpha1.CephExtensions{MirroringMode: &snapshot}}
+				uvr.Spec.VolumeMapping.Destination.AutoCreate = &autoCreate
+			},
+			expected: []string{"AsyncReplication", "SnapshotBased", "VolumeProvisioning", "AutoResync", "Metrics", "ProgressTracking", "RealTimeStatus"},
+		},
+		{
+			name:    "CephJournalMirroringUnsupported",
+			adapter: ceph,
+			mutate: func(uvr *replicationv1alpha1.UnifiedVolumeReplication) {
+				uvr.Spec.Extensions = &replicationv1alpha1.Extensions{Ceph: &replicationv1alpha1.CephExtensions{MirroringMode: &journal}}
+			},
+			expected: []string{"AsyncReplication", "AutoResync", "Metrics", "ProgressTracking", "RealTimeStatus"},
+		},
+		{
+			name:     "TridentAsync",
+			adapter:  trident,
+			mutate:   func(uvr *replicationv1alpha1.UnifiedVolumeReplication) {},
+			expected: []string{"AsyncReplication"},
+		},
+		{
+			name:    "TridentReadableReplica",
+			adapter: trident,
+			mutate: func(uvr *replicationv1alpha1.UnifiedVolumeReplication) {
+				uvr.Status.ReplicaReadable = &replicationv1alpha1.ReplicaReadability{Readable: true}
+			},
+			expected: []string{"AsyncReplication", "ReplicaRead"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uvr := createTestUVR("test-features", "default")
+			tt.mutate(uvr)
+			assert.Equal(t, tt.expected, activeFeatures(tt.adapter.GetSupportedFeatures(), uvr))
+		})
+	}
+}
+
+func TestReconciler_EffectiveConfigPersisted(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+
+	uvr := createTestUVR("test-effective-config", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(tridentCRDs(""), uvr)...).
+		WithStatusSubresource(uvr).Build()
+
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewTridentAdapterFactory()))
+	reconciler := createTestReconciler(c, s)
+	reconciler.AdapterRegistry = registry
+	reconciler.ControllerEngine = pkg.NewControllerEngine(c, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+		registry, pkg.DefaultControllerEngineConfig())
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	updated := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	require.NotNil(t, updated.Status.EffectiveConfig)
+	assert.Equal(t, "trident", updated.Status.EffectiveConfig.Backend)
+	assert.Equal(t, "Async", updated.Status.EffectiveConfig.BackendMode)
+}
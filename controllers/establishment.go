@@ -0,0 +1,85 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+// establishmentDurationSeconds observes how long replications take to complete their
+// initial sync, by backend
+var establishmentDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name: "unified_replication_establishment_duration_seconds",
+	Help: "Time from creation, or from the start of a full resync, until the initial sync of a replication completed",
+	// 1m up to ~17h
+	Buckets: prometheus.ExponentialBuckets(60, 2, 11),
+}, []string{"backend"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(establishmentDurationSeconds)
+}
+
+// recordEstablishment records how long the initial sync took, in status and as a metric.
+// It is called once, when InitialSyncComplete latches.
+func (r *UnifiedVolumeReplicationReconciler) recordEstablishment(uvr *replicationv1alpha1.UnifiedVolumeReplication, completedAt time.Time, log logr.Logger) {
+	start := uvr.CreationTimestamp
+	if uvr.Status.EstablishmentStartTime != nil {
+		start = *uvr.Status.EstablishmentStartTime
+	}
+	if start.IsZero() {
+		return
+	}
+
+	duration := completedAt.Sub(start.Time)
+	if duration < 0 {
+		duration = 0
+	}
+	uvr.Status.EstablishmentDuration = &metav1.Duration{Duration: duration.Truncate(time.Second)}
+	establishmentDurationSeconds.WithLabelValues(backendPartitionFor(uvr)).Observe(duration.Seconds())
+	log.Info("Replication established", "duration", uvr.Status.EstablishmentDuration.Duration)
+}
+
+// restartEstablishment clears the establishment record after a full resync, which rebuilds
+// the replica from scratch, and counts the resync as churn. The next initial sync is
+// measured from now.
+func (r *UnifiedVolumeReplicationReconciler) restartEstablishment(uvr *replicationv1alpha1.UnifiedVolumeReplication, message string) {
+	r.recordRelationshipChurn(uvr, churnReasonFullResync, time.Now())
+	r.resetEstablishment(uvr, "FullResync", message)
+}
+
+// resetEstablishment clears the establishment record and reopens InitialSyncComplete, so the
+// next initial sync is measured from now
+func (r *UnifiedVolumeReplicationReconciler) resetEstablishment(uvr *replicationv1alpha1.UnifiedVolumeReplication, reason, message string) {
+	now := metav1.Now()
+	uvr.Status.EstablishmentStartTime = &now
+	uvr.Status.EstablishmentDuration = nil
+	restartDataTransfer(uvr)
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               "InitialSyncComplete",
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+const (
+	// ExplainAnnotation, set to "true", makes each reconcile write a trace of its decisions to
+	// status.explanation. Removing it clears the trace.
+	ExplainAnnotation = "replication.storage.io/explain"
+
+	// maxExplanationSteps bounds the trace so a pathological reconcile cannot bloat the status
+	maxExplanationSteps = 50
+)
+
+// explainTrace collects the decisions of one reconcile
+type explainTrace struct {
+	mu    sync.Mutex
+	steps []string
+}
+
+type explainTraceKey struct{}
+
+// explainf adds a step to the trace of the current reconcile; it does nothing unless the
+// UVR asked for an explanation
+func explainf(ctx context.Context, format string, args ...interface{}) {
+	trace, _ := ctx.Value(explainTraceKey{}).(*explainTrace)
+	if trace == nil {
+		return
+	}
+	trace.mu.Lock()
+	defer trace.mu.Unlock()
+	if len(trace.steps) < maxExplanationSteps {
+		trace.steps = append(trace.steps, fmt.Sprintf(format, args...))
+	}
+}
+
+// startExplain returns a context tracing the reconcile when the explain annotation is set
+func startExplain(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (context.Context, *explainTrace) {
+	if uvr.Annotations[ExplainAnnotation] != "true" {
+		return ctx, nil
+	}
+	trace := &explainTrace{}
+	ctx = context.WithValue(ctx, explainTraceKey{}, trace)
+	explainf(ctx, "Reconciling generation %d: desired state %s, mode %s", uvr.Generation, uvr.Spec.ReplicationState, uvr.Spec.ReplicationMode)
+	return ctx, trace
+}
+
+// finishExplain closes the trace with the outcome of the reconcile and writes it to status.
+// Without a trace, an explanation left from an earlier reconcile is removed.
+func (r *UnifiedVolumeReplicationReconciler) finishExplain(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, trace *explainTrace, result ctrl.Result, err error, log logr.Logger) {
+	if trace == nil {
+		if uvr.Status.Explanation == nil {
+			return
+		}
+		uvr.Status.Explanation = nil
+		if err := r.Status().Update(ctx, uvr); err != nil {
+			log.Error(err, "Failed to clear explanation")
+		}
+		return
+	}
+
+	// The outcome is always recorded, even when the steps were cut short
+	trace.mu.Lock()
+	steps := append([]string(nil), trace.steps...)
+	trace.mu.Unlock()
+	if ready := r.getCondition(uvr, "Ready"); ready != nil {
+		steps = append(steps, fmt.Sprintf("Ready is %s (%s): %s", ready.Status, ready.Reason, ready.Message))
+	}
+	switch {
+	case err != nil:
+		steps = append(steps, fmt.Sprintf("Outcome: failed with %v, retrying", err))
+	case result.RequeueAfter > 0:
+		steps = append(steps, fmt.Sprintf("Outcome: next reconcile in %s", result.RequeueAfter))
+	default:
+		steps = append(steps, "Outcome: done")
+	}
+
+	uvr.Status.Explanation = &replicationv1alpha1.ReconcileExplanation{
+		ObservedGeneration: uvr.Generation,
+		Time:               metav1.Now(),
+		Steps:              steps,
+	}
+	if err := r.Status().Update(ctx, uvr); err != nil {
+		log.Error(err, "Failed to record explanation")
+	}
+}
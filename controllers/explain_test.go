@@ -0,0 +1,139 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// stepIndex returns the position of the first step containing substr, -1 when none does
+func stepIndex(steps []string, substr string) int {
+	for i, step := range steps {
+		if strings.Contains(step, substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+func newExplainReconciler(t *testing.T, uvr *replicationv1alpha1.UnifiedVolumeReplication) (*UnifiedVolumeReplicationReconciler, client.Client) {
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(tridentCRDs(""), uvr)...).
+		WithStatusSubresource(uvr).Build()
+
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewTridentAdapterFactory()))
+	reconciler := createTestReconciler(c, s)
+	reconciler.AdapterRegistry = registry
+	reconciler.ControllerEngine = pkg.NewControllerEngine(c, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+		registry, pkg.DefaultControllerEngineConfig())
+	return reconciler, c
+}
+
+func TestReconciler_Explain(t *testing.T) {
+	ctx := context.Background()
+	uvr := createTestUVR("test-explain", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	uvr.Annotations = map[string]string{ExplainAnnotation: "true"}
+	reconciler, c := newExplainReconciler(t, uvr)
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	updated := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	require.NotNil(t, updated.Status.Explanation)
+	assert.Equal(t, updated.Generation, updated.Status.Explanation.ObservedGeneration)
+	steps := updated.Status.Explanation.Steps
+
+	// The key decisions appear in the order they were made
+	decisions := []string{
+		"Reconciling generation",
+		"Spec validation passed",
+		"Discovered backends: [trident]",
+		"Backend trident chosen from the spec extensions",
+		"Using the trident adapter",
+		"Issued EnsureReplication to the trident backend for state replica",
+		"Ready is",
+		"Outcome: next reconcile in",
+	}
+	last := -1
+	for _, decision := range decisions {
+		i := stepIndex(steps, decision)
+		require.GreaterOrEqual(t, i, 0, "missing %q in %v", decision, steps)
+		assert.Greater(t, i, last, "%q out of order in %v", decision, steps)
+		last = i
+	}
+
+	// Removing the annotation clears the explanation
+	delete(updated.Annotations, ExplainAnnotation)
+	require.NoError(t, c.Update(ctx, updated))
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	assert.Nil(t, updated.Status.Explanation)
+}
+
+func TestReconciler_ExplainValidationFailure(t *testing.T) {
+	ctx := context.Background()
+	uvr := createTestUVR("test-explain-invalid", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	uvr.Annotations = map[string]string{ExplainAnnotation: "true"}
+	uvr.Spec.Schedule.Rpo = "soon"
+	reconciler, c := newExplainReconciler(t, uvr)
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	updated := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	require.NotNil(t, updated.Status.Explanation)
+	steps := updated.Status.Explanation.Steps
+	assert.GreaterOrEqual(t, stepIndex(steps, "Spec validation failed"), 0, steps)
+	assert.GreaterOrEqual(t, stepIndex(steps, "Ready is False (ValidationFailed)"), 0, steps)
+	assert.Equal(t, -1, stepIndex(steps, "Issued EnsureReplication"), "nothing is sent to the backend")
+}
+
+func TestReconciler_ExplainDisabled(t *testing.T) {
+	ctx := context.Background()
+	uvr := createTestUVR("test-explain-off", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	reconciler, c := newExplainReconciler(t, uvr)
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	updated := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	assert.Nil(t, updated.Status.Explanation)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+const (
+	// FailoverApprovedAnnotation, set to "true", approves the pending failover of a UVR when
+	// the operator requires manual failover approval. It is removed once the UVR is observed
+	// as a source, so every failover needs its own approval.
+	FailoverApprovedAnnotation = "replication.storage.io/failover-approved"
+
+	// awaitingFailoverApprovalReason is the Ready reason of a failover held for approval
+	awaitingFailoverApprovalReason = "AwaitingFailoverApproval"
+)
+
+// isFailoverPending returns true when the spec asks for the source role while the volume is
+// last known as a replica or as failed
+func isFailoverPending(uvr *replicationv1alpha1.UnifiedVolumeReplication) bool {
+	if uvr.Spec.ReplicationState != replicationv1alpha1.ReplicationStateSource {
+		return false
+	}
+	switch uvr.Status.CurrentState {
+	case replicationv1alpha1.ReplicationStateReplica, replicationv1alpha1.ReplicationStateFailed:
+		return true
+	}
+	return isPromotionPending(uvr)
+}
+
+// awaitFailoverApproval holds a failover until the failover-approved annotation is set, when
+// RequireFailoverApproval is on, so a transient backend failure cannot lead to an automatic
+// promotion. Returns true with the result the reconcile should end with while the failover
+// is held, after setting Ready False with reason AwaitingFailoverApproval.
+func (r *UnifiedVolumeReplicationReconciler) awaitFailoverApproval(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) (bool, ctrl.Result) {
+	if !r.RequireFailoverApproval {
+		return false, ctrl.Result{}
+	}
+	if !isFailoverPending(uvr) {
+		r.consumeFailoverApproval(ctx, uvr, log)
+		return false, ctrl.Result{}
+	}
+
+	held := r.getCondition(uvr, "Ready")
+	wasHeld := held != nil && held.Reason == awaitingFailoverApprovalReason
+	if uvr.Annotations[FailoverApprovedAnnotation] == "true" {
+		if wasHeld {
+			log.Info("Failover approved")
+			r.recordEventf(uvr, corev1.EventTypeNormal, "FailoverApproved", "Failover approved, promoting")
+		}
+		return false, ctrl.Result{}
+	}
+
+	message := fmt.Sprintf("Failover to source is held until it is approved with the %s=true annotation", FailoverApprovedAnnotation)
+	if !wasHeld {
+		log.Info("Failover awaiting approval")
+		r.recordEventf(uvr, corev1.EventTypeWarning, awaitingFailoverApprovalReason, "%s", message)
+	}
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             awaitingFailoverApprovalReason,
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+	return true, ctrl.Result{RequeueAfter: requeueDelayError}
+}
+
+// consumeFailoverApproval removes the approval of a failover that has completed, so it does
+// not approve the next one
+func (r *UnifiedVolumeReplicationReconciler) consumeFailoverApproval(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) {
+	if _, ok := uvr.Annotations[FailoverApprovedAnnotation]; !ok ||
+		uvr.Status.CurrentState != replicationv1alpha1.ReplicationStateSource {
+		return
+	}
+	patched := uvr.DeepCopy()
+	delete(patched.Annotations, FailoverApprovedAnnotation)
+	if err := r.Patch(ctx, patched, client.MergeFrom(uvr)); err != nil {
+		log.Error(err, "Failed to remove the failover approval")
+		return
+	}
+	uvr.Annotations = patched.Annotations
+	uvr.ResourceVersion = patched.ResourceVersion
+	log.Info("Failover completed, approval removed")
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestIsFailoverPending(t *testing.T) {
+	uvr := createTestUVR("test-failover-pending", "default")
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+	assert.False(t, isFailoverPending(uvr), "a new source is not a failover")
+
+	for _, state := range []replicationv1alpha1.ReplicationState{replicationv1alpha1.ReplicationStateReplica, replicationv1alpha1.ReplicationStateFailed} {
+		uvr.Status.CurrentState = state
+		assert.True(t, isFailoverPending(uvr), "promoting from %s", state)
+	}
+
+	uvr.Status.CurrentState = replicationv1alpha1.ReplicationStateSource
+	assert.False(t, isFailoverPending(uvr))
+
+	uvr.Status.CurrentState = ""
+	uvr.Status.StateHistory = []replicationv1alpha1.StateHistoryEntry{{Timestamp: metav1.Now(), To: "replica"}}
+	assert.True(t, isFailoverPending(uvr), "the state history tells a replica apart")
+
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateReplica
+	assert.False(t, isFailoverPending(uvr))
+}
+
+func TestReconciler_FailoverApproval(t *testing.T) {
+	ctx := context.Background()
+
+	// setup creates a replica whose spec asks for the source role
+	setup := func(t *testing.T, name string, requireApproval bool) (*UnifiedVolumeReplicationReconciler, client.Client, ctrl.Request) {
+		uvr := createTestUVR(name, "default")
+		uvr.Finalizers = []string{unifiedReplicationFinalizer}
+		uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+		uvr.Status.StateHistory = []replicationv1alpha1.StateHistoryEntry{{Timestamp: metav1.Now(), To: "replica"}}
+		reconciler, c := newDeactivationTestReconciler(t, adapters.NewTridentAdapterFactory(), uvr)
+		reconciler.RequireFailoverApproval = requireApproval
+		return reconciler, c, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+	}
+	// promoted reports whether the promotion reached the backend, which creates the relationship
+	promoted := func(c client.Client, req ctrl.Request) bool {
+		tmr := &unstructured.Unstructured{}
+		tmr.SetGroupVersionKind(adapters.TridentMirrorRelationshipGVK)
+		return c.Get(ctx, req.NamespacedName, tmr) == nil
+	}
+	reconcile := func(t *testing.T, reconciler *UnifiedVolumeReplicationReconciler, c client.Client, req ctrl.Request) (*replicationv1alpha1.UnifiedVolumeReplication, ctrl.Result) {
+		t.Helper()
+		result, err := reconciler.Reconcile(ctx, req)
+		require.NoError(t, err)
+		uvr := &replicationv1alpha1.UnifiedVolumeReplication{}
+		require.NoError(t, c.Get(ctx, req.NamespacedName, uvr))
+		return uvr, result
+	}
+
+	t.Run("Gated", func(t *testing.T) {
+		reconciler, c, req := setup(t, "test-failover-gated", true)
+		recorder := reconciler.Recorder.(*record.FakeRecorder)
+
+		// The failover is held, and announced once
+		for range 2 {
+			uvr, result := reconcile(t, reconciler, c, req)
+			assert.False(t, promoted(c, req))
+			assert.Equal(t, requeueDelayError, result.RequeueAfter)
+			ready := meta.FindStatusCondition(uvr.Status.Conditions, "Ready")
+			require.NotNil(t, ready)
+			assert.Equal(t, metav1.ConditionFalse, ready.Status)
+			assert.Equal(t, awaitingFailoverApprovalReason, ready.Reason)
+			assert.Contains(t, ready.Message, "replication.storage.io/failover-approved=true")
+		}
+		events := drainEvents(recorder)
+		require.Len(t, events, 1)
+		assert.Contains(t, events[0], "Warning AwaitingFailoverApproval")
+	})
+
+	t.Run("Approved", func(t *testing.T) {
+		reconciler, c, req := setup(t, "test-failover-approved", true)
+		recorder := reconciler.Recorder.(*record.FakeRecorder)
+		uvr, _ := reconcile(t, reconciler, c, req)
+		require.False(t, promoted(c, req))
+		drainEvents(recorder)
+
+		uvr.Annotations = map[string]string{FailoverApprovedAnnotation: "true"}
+		require.NoError(t, c.Update(ctx, uvr))
+		uvr, _ = reconcile(t, reconciler, c, req)
+		assert.True(t, promoted(c, req))
+		assert.Contains(t, drainEvents(recorder), "Normal FailoverApproved Failover approved, promoting")
+		assert.Equal(t, "true", uvr.Annotations[FailoverApprovedAnnotation], "the approval stands until the failover completes")
+
+		// Once the volume is observed as a source the approval is used up
+		uvr.Status.CurrentState = replicationv1alpha1.ReplicationStateSource
+		uvr.Status.StateHistory = append(uvr.Status.StateHistory,
+			replicationv1alpha1.StateHistoryEntry{Timestamp: metav1.Now(), From: "replica", To: "source"})
+		require.NoError(t, c.Status().Update(ctx, uvr))
+		uvr, _ = reconcile(t, reconciler, c, req)
+		assert.NotContains(t, uvr.Annotations, FailoverApprovedAnnotation)
+	})
+
+	t.Run("NotRequired", func(t *testing.T) {
+		reconciler, c, req := setup(t, "test-failover-ungated", false)
+		reconcile(t, reconciler, c, req)
+		assert.True(t, promoted(c, req))
+	})
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+// failoverQueuePollDelay is how often a queued failover checks whether a slot has freed up
+const failoverQueuePollDelay = 5 * time.Second
+
+// FailoverLimiter caps how many UVRs may be promoted at once across the cluster, so a mass
+// failover does not overwhelm the destination site. Promotions beyond the cap wait in a
+// queue and proceed in the order they were requested. A promotion holds its slot until the
+// UVR is observed as a source, stops asking for the source role, or the backend refuses the
+// promotion. A slot held for longer than the slot timeout is taken back, so a promotion that
+// never completes cannot starve the queue; the UVR then queues again behind the others.
+type FailoverLimiter struct {
+	mu            sync.Mutex
+	maxConcurrent int
+	slotTimeout   time.Duration
+	active        map[string]time.Time
+	queue         []string
+}
+
+// NewFailoverLimiter creates a limiter letting maxConcurrent failovers run at once, each for
+// at most slotTimeout; slots never expire when slotTimeout is zero
+func NewFailoverLimiter(maxConcurrent int, slotTimeout time.Duration) *FailoverLimiter {
+	return &FailoverLimiter{maxConcurrent: maxConcurrent, slotTimeout: slotTimeout, active: map[string]time.Time{}}
+}
+
+// Admit reports whether the failover of the UVR with the given key may proceed. When it
+// must wait, it returns its position in the queue, 1 being next.
+func (l *FailoverLimiter) Admit(key string) (bool, int) {
+	if l == nil || l.maxConcurrent <= 0 {
+		return true, 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	for active, since := range l.active {
+		if l.slotTimeout > 0 && now.Sub(since) > l.slotTimeout {
+			delete(l.active, active)
+		}
+	}
+	if _, ok := l.active[key]; ok {
+		return true, 0
+	}
+
+	position := slices.Index(l.queue, key)
+	if position < 0 {
+		l.queue = append(l.queue, key)
+		position = len(l.queue) - 1
+	}
+	// Queued failovers ahead of this one may take the free slots first
+	if position < l.maxConcurrent-len(l.active) {
+		l.queue = slices.Delete(l.queue, position, position+1)
+		l.active[key] = now
+		return true, 0
+	}
+	return false, position + 1
+}
+
+// Release ends or withdraws the failover of the UVR with the given key, freeing its slot
+func (l *FailoverLimiter) Release(key string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.active, key)
+	if position := slices.Index(l.queue, key); position >= 0 {
+		l.queue = slices.Delete(l.queue, position, position+1)
+	}
+}
+
+// InProgress returns the number of failovers holding a slot
+func (l *FailoverLimiter) InProgress() int {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.active)
+}
+
+// releaseFailover frees the failover slot of a UVR that is no longer being promoted
+func (r *UnifiedVolumeReplicationReconciler) releaseFailover(uvr *replicationv1alpha1.UnifiedVolumeReplication) {
+	if isPromotionPending(uvr) && uvr.DeletionTimestamp.IsZero() {
+		return
+	}
+	r.FailoverLimiter.Release(client.ObjectKeyFromObject(uvr).String())
+	uvr.Status.FailoverQueuePosition = 0
+}
+
+// releaseFailedFailover frees the failover slot of a promotion the backend did not carry
+// out, so the next queued failover can proceed; the UVR asks for a slot again when it retries
+func (r *UnifiedVolumeReplicationReconciler) releaseFailedFailover(uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) {
+	if r.FailoverLimiter == nil || !isPromotionPending(uvr) {
+		return
+	}
+	log.Info("Promotion failed, releasing its failover slot")
+	r.FailoverLimiter.Release(client.ObjectKeyFromObject(uvr).String())
+}
+
+// queueFailover holds the promotion of a UVR while the concurrent failover limit is reached.
+// It returns true with the result the reconcile should end with while the UVR is queued,
+// after recording its position in status.
+func (r *UnifiedVolumeReplicationReconciler) queueFailover(uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) (bool, ctrl.Result) {
+	if !isPromotionPending(uvr) {
+		return false, ctrl.Result{}
+	}
+	admitted, position := r.FailoverLimiter.Admit(client.ObjectKeyFromObject(uvr).String())
+	if admitted {
+		if uvr.Status.FailoverQueuePosition > 0 {
+			log.Info("Queued failover proceeding")
+			r.recordEventf(uvr, corev1.EventTypeNormal, "FailoverDequeued", "Failover slot available, promoting")
+		}
+		uvr.Status.FailoverQueuePosition = 0
+		return false, ctrl.Result{}
+	}
+
+	message := fmt.Sprintf("Failover queued at position %d, %d of %d concurrent failovers in progress",
+		position, r.FailoverLimiter.InProgress(), r.FailoverLimiter.maxConcurrent)
+	if uvr.Status.FailoverQueuePosition == 0 {
+		log.Info("Concurrent failover limit reached, queueing promotion", "position", position)
+		r.recordEventf(uvr, corev1.EventTypeWarning, "FailoverQueued", "%s", message)
+	}
+	uvr.Status.FailoverQueuePosition = int32(position)
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             "FailoverQueued",
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+	return true, ctrl.Result{RequeueAfter: failoverQueuePollDelay}
+}
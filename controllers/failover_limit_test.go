@@ -0,0 +1,242 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestFailoverLimiter_Queue(t *testing.T) {
+	limiter := NewFailoverLimiter(2, 0)
+
+	admitted, _ := limiter.Admit("a")
+	assert.True(t, admitted)
+	admitted, _ = limiter.Admit("b")
+	assert.True(t, admitted)
+	admitted, position := limiter.Admit("c")
+	assert.False(t, admitted)
+	assert.Equal(t, 1, position)
+	_, position = limiter.Admit("d")
+	assert.Equal(t, 2, position)
+
+	// An admitted failover keeps its slot until released
+	admitted, _ = limiter.Admit("a")
+	assert.True(t, admitted)
+	assert.Equal(t, 2, limiter.InProgress())
+
+	// A freed slot goes to the head of the queue, not to whoever asks first
+	limiter.Release("a")
+	admitted, position = limiter.Admit("d")
+	assert.False(t, admitted)
+	assert.Equal(t, 2, position)
+	admitted, _ = limiter.Admit("c")
+	assert.True(t, admitted)
+	_, position = limiter.Admit("d")
+	assert.Equal(t, 1, position)
+
+	// A withdrawn failover leaves the queue
+	limiter.Release("d")
+	_, position = limiter.Admit("e")
+	assert.Equal(t, 1, position)
+
+	// A slot held past the slot timeout goes to the head of the queue, and its holder queues again
+	expiring := NewFailoverLimiter(1, time.Minute)
+	admitted, _ = expiring.Admit("a")
+	assert.True(t, admitted)
+	admitted, _ = expiring.Admit("b")
+	assert.False(t, admitted)
+	expiring.active["a"] = time.Now().Add(-2 * time.Minute)
+	admitted, _ = expiring.Admit("b")
+	assert.True(t, admitted)
+	admitted, position = expiring.Admit("a")
+	assert.False(t, admitted)
+	assert.Equal(t, 1, position)
+
+	// Without a limit, or a limiter, every failover proceeds
+	admitted, _ = NewFailoverLimiter(0, 0).Admit("a")
+	assert.True(t, admitted)
+	var unset *FailoverLimiter
+	admitted, _ = unset.Admit("a")
+	assert.True(t, admitted)
+	unset.Release("a")
+}
+
+func TestReconciler_ConcurrentFailoverLimit(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+
+	// A mass failover of five replicas, with room for two at a time
+	const maxConcurrent = 2
+	objects := tridentCRDs("")
+	var uvrs []*replicationv1alpha1.UnifiedVolumeReplication
+	for i := 0; i < 5; i++ {
+		uvr := createTestUVR(fmt.Sprintf("test-failover-%d", i), "default")
+		uvr.Finalizers = []string{unifiedReplicationFinalizer}
+		uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+		uvr.Status.StateHistory = []replicationv1alpha1.StateHistoryEntry{{Timestamp: metav1.Now(), To: "replica"}}
+		uvrs = append(uvrs, uvr)
+		objects = append(objects, uvr)
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(objects...).
+		WithStatusSubresource(&replicationv1alpha1.UnifiedVolumeReplication{}).Build()
+
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewTridentAdapterFactory()))
+	reconciler := createTestReconciler(c, s)
+	reconciler.AdapterRegistry = registry
+	reconciler.ControllerEngine = pkg.NewControllerEngine(c, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+		registry, pkg.DefaultControllerEngineConfig())
+	reconciler.FailoverLimiter = NewFailoverLimiter(maxConcurrent, 0)
+
+	// issued reports whether the promotion reached the backend, which creates the relationship
+	issued := func(uvr *replicationv1alpha1.UnifiedVolumeReplication) bool {
+		tmr := &unstructured.Unstructured{}
+		tmr.SetGroupVersionKind(adapters.TridentMirrorRelationshipGVK)
+		return c.Get(ctx, client.ObjectKeyFromObject(uvr), tmr) == nil
+	}
+	promoted := map[string]bool{}
+	inFlight := func() int {
+		count := 0
+		for _, uvr := range uvrs {
+			if issued(uvr) && !promoted[uvr.Name] {
+				count++
+			}
+		}
+		return count
+	}
+	reconcileAll := func() {
+		t.Helper()
+		for _, uvr := range uvrs {
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)})
+			require.NoError(t, err)
+			require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(uvr), uvr))
+			assert.LessOrEqual(t, inFlight(), maxConcurrent, "failovers in flight stay within the limit")
+			assert.LessOrEqual(t, reconciler.FailoverLimiter.InProgress(), maxConcurrent)
+		}
+	}
+	// observe simulates the backend reporting the promoted volumes as sources
+	observe := func() {
+		t.Helper()
+		for _, uvr := range uvrs {
+			if !issued(uvr) || promoted[uvr.Name] {
+				continue
+			}
+			uvr.Status.StateHistory = append(uvr.Status.StateHistory,
+				replicationv1alpha1.StateHistoryEntry{Timestamp: metav1.Now(), From: "replica", To: "source"})
+			require.NoError(t, c.Status().Update(ctx, uvr))
+			promoted[uvr.Name] = true
+		}
+	}
+
+	reconcileAll()
+	assert.Equal(t, maxConcurrent, inFlight())
+	for i, uvr := range uvrs[maxConcurrent:] {
+		assert.False(t, issued(uvr))
+		assert.Equal(t, int32(i+1), uvr.Status.FailoverQueuePosition, "queued in request order")
+		assert.Equal(t, "FailoverQueued", reconciler.getCondition(uvr, "Ready").Reason)
+	}
+
+	// Reconciling again does not let queued failovers jump the limit
+	reconcileAll()
+	assert.Equal(t, maxConcurrent, inFlight())
+
+	// As promotions complete, queued failovers proceed until every volume is promoted
+	for round := 0; round < len(uvrs) && len(promoted) < len(uvrs); round++ {
+		observe()
+		reconcileAll()
+	}
+	observe()
+	reconcileAll()
+	assert.Len(t, promoted, len(uvrs), "the whole failover completes")
+	assert.Zero(t, reconciler.FailoverLimiter.InProgress())
+	for _, uvr := range uvrs {
+		assert.Zero(t, uvr.Status.FailoverQueuePosition)
+	}
+}
+
+func TestReconciler_FailedPromotionReleasesFailoverSlot(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+
+	objects := tridentCRDs("")
+	var uvrs []*replicationv1alpha1.UnifiedVolumeReplication
+	for _, name := range []string{"test-failing-promotion", "test-queued-promotion"} {
+		uvr := createTestUVR(name, "default")
+		uvr.Finalizers = []string{unifiedReplicationFinalizer}
+		uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+		uvr.Status.StateHistory = []replicationv1alpha1.StateHistoryEntry{{Timestamp: metav1.Now(), To: "replica"}}
+		uvrs = append(uvrs, uvr)
+		objects = append(objects, uvr)
+	}
+	// The backend refuses to promote the first replica
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(objects...).
+		WithStatusSubresource(&replicationv1alpha1.UnifiedVolumeReplication{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if obj.GetName() == uvrs[0].Name {
+					return fmt.Errorf("mirror relationship rejected")
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).Build()
+
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewTridentAdapterFactory()))
+	reconciler := createTestReconciler(c, s)
+	reconciler.AdapterRegistry = registry
+	reconciler.ControllerEngine = pkg.NewControllerEngine(c, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+		registry, pkg.DefaultControllerEngineConfig())
+	reconciler.FailoverLimiter = NewFailoverLimiter(1, 0)
+
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvrs[0])})
+	require.Error(t, err)
+	assert.Zero(t, reconciler.FailoverLimiter.InProgress(), "the failed promotion does not keep its slot")
+
+	// The other replica is promoted instead of queueing behind the failing one
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvrs[1])})
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(uvrs[1]), uvrs[1]))
+	assert.Zero(t, uvrs[1].Status.FailoverQueuePosition)
+	tmr := &unstructured.Unstructured{}
+	tmr.SetGroupVersionKind(adapters.TridentMirrorRelationshipGVK)
+	assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(uvrs[1]), tmr))
+
+	// The failing replica queues again on its retry
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvrs[0])})
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(uvrs[0]), uvrs[0]))
+	assert.Equal(t, int32(1), uvrs[0].Status.FailoverQueuePosition)
+}
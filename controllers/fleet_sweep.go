@@ -0,0 +1,300 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// Outcomes of the reconciles triggered by a fleet sweep
+const (
+	sweepResultHealthy   = "healthy"
+	sweepResultUnhealthy = "unhealthy"
+	sweepResultError     = "error"
+	sweepResultDeleted   = "deleted"
+)
+
+var (
+	// fleetSweeps counts the fleet sweeps, by whether the UVRs could be listed
+	fleetSweeps = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "unified_replication_fleet_sweeps_total",
+		Help: "Number of periodic fleet sweeps, by result: completed, or failed when the replications could not be listed",
+	}, []string{"result"})
+
+	// fleetSweepEnqueued counts the reconciles queued by fleet sweeps
+	fleetSweepEnqueued = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "unified_replication_fleet_sweep_enqueued_total",
+		Help: "Number of reconciles queued by periodic fleet sweeps",
+	})
+
+	// fleetSweepResults counts the outcomes of the reconciles queued by fleet sweeps
+	fleetSweepResults = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "unified_replication_fleet_sweep_results_total",
+		Help: "Outcomes of the reconciles queued by periodic fleet sweeps: healthy, unhealthy when not Ready, error, or deleted",
+	}, []string{"result"})
+
+	// fleetSweepOrphans reports the backend resources whose owning UVR no longer exists
+	fleetSweepOrphans = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "unified_replication_fleet_sweep_orphaned_resources",
+		Help: "Backend resources labelled as owned by a replication that no longer exists, as found by the latest periodic fleet sweep, by kind",
+	}, []string{"kind"})
+
+	// fleetSweepLastCompletion records when the latest fleet sweep queued its last UVR
+	fleetSweepLastCompletion = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "unified_replication_fleet_sweep_last_completion_timestamp_seconds",
+		Help: "Unix time at which the latest periodic fleet sweep queued its last replication",
+	})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(fleetSweeps, fleetSweepEnqueued, fleetSweepResults, fleetSweepOrphans, fleetSweepLastCompletion)
+}
+
+// FleetSweep periodically queues a reconcile of every UVR, so drift that no watch reports,
+// such as a relationship changed or removed directly on the backend, is still caught. The
+// reconciles of a sweep are spread evenly across the interval rather than queued at once,
+// so the backends and the API server do not see a burst every interval. Each sweep also
+// reports the backend resources left behind by UVRs that no longer exist.
+type FleetSweep struct {
+	reader   client.Reader
+	interval time.Duration
+	log      logr.Logger
+
+	// namespaces are searched for orphaned backend resources; all namespaces when empty
+	namespaces []string
+
+	// channels feed the swept UVRs to the controllers, one per controller
+	channels []chan event.GenericEvent
+
+	mu sync.Mutex
+	// pending holds the UVRs queued by the current sweep whose reconcile has not run yet
+	pending map[string]bool
+
+	// wait sleeps for d, returning false when ctx is done first; replaced in tests
+	wait func(ctx context.Context, d time.Duration) bool
+}
+
+var _ manager.Runnable = &FleetSweep{}
+
+// NewFleetSweep creates a sweep listing UVRs from reader and reconciling each of them once
+// per interval. Orphaned backend resources are looked for in namespaces, or in all
+// namespaces when empty.
+func NewFleetSweep(reader client.Reader, interval time.Duration, namespaces []string, log logr.Logger) *FleetSweep {
+	return &FleetSweep{
+		reader:     reader,
+		interval:   interval,
+		namespaces: namespaces,
+		log:        log,
+		pending:    map[string]bool{},
+		wait:       sleepContext,
+	}
+}
+
+// sleepContext sleeps for d, returning false when ctx is done first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// Start runs sweeps back to back until ctx is done. Each sweep takes about one interval.
+func (s *FleetSweep) Start(ctx context.Context) error {
+	s.log.Info("Starting periodic fleet sweep", "interval", s.interval)
+	for ctx.Err() == nil {
+		if err := s.sweep(ctx); err != nil && ctx.Err() == nil {
+			s.log.Error(err, "Fleet sweep failed")
+			fleetSweeps.WithLabelValues("failed").Inc()
+			s.wait(ctx, s.interval)
+		}
+	}
+	return nil
+}
+
+// sweep queues a reconcile of every UVR, the i-th of n after (i+1)*interval/n, so the last
+// one is queued as the interval ends
+func (s *FleetSweep) sweep(ctx context.Context) error {
+	// Backend resources are listed before the UVRs, so one created for a new UVR in between
+	// is not taken for an orphan
+	owned := s.listOwnedResources(ctx)
+	uvrs := &replicationv1alpha1.UnifiedVolumeReplicationList{}
+	if err := s.reader.List(ctx, uvrs); err != nil {
+		return fmt.Errorf("failed to list replications: %w", err)
+	}
+	s.reportOrphans(owned, uvrs.Items)
+	if len(uvrs.Items) == 0 {
+		s.wait(ctx, s.interval)
+		return nil
+	}
+	// Sweep in a stable order, so each UVR is reconciled about one interval apart
+	sort.Slice(uvrs.Items, func(i, j int) bool {
+		return client.ObjectKeyFromObject(&uvrs.Items[i]).String() < client.ObjectKeyFromObject(&uvrs.Items[j]).String()
+	})
+
+	s.mu.Lock()
+	s.pending = map[string]bool{}
+	s.mu.Unlock()
+
+	spacing := s.interval / time.Duration(len(uvrs.Items))
+	s.log.V(1).Info("Sweeping fleet", "replications", len(uvrs.Items), "spacing", spacing)
+	for i := range uvrs.Items {
+		if !s.wait(ctx, spacing) {
+			return nil
+		}
+		if !s.enqueue(ctx, &uvrs.Items[i]) {
+			return nil
+		}
+	}
+	fleetSweeps.WithLabelValues("completed").Inc()
+	fleetSweepLastCompletion.SetToCurrentTime()
+	return nil
+}
+
+// listOwnedResources lists the backend resources labelled with the UID of their UVR, by
+// kind. Kinds whose CRD is not installed are listed as empty; kinds that cannot be listed
+// are left out.
+func (s *FleetSweep) listOwnedResources(ctx context.Context) map[string][]unstructured.Unstructured {
+	namespaces := s.namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	owned := map[string][]unstructured.Unstructured{}
+	for _, gvk := range adapters.OwnedResourceKinds {
+		var items []unstructured.Unstructured
+		listed := true
+		for _, namespace := range namespaces {
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+			err := s.reader.List(ctx, list, client.InNamespace(namespace), client.HasLabels{adapters.OwnerUIDLabel})
+			if apimeta.IsNoMatchError(err) {
+				break
+			}
+			if err != nil {
+				s.log.Error(err, "Failed to list backend resources for orphans", "kind", gvk.Kind, "namespace", namespace)
+				listed = false
+				break
+			}
+			items = append(items, list.Items...)
+		}
+		if listed {
+			owned[gvk.Kind] = items
+		}
+	}
+	return owned
+}
+
+// reportOrphans logs the backend resources whose owning UVR is not among uvrs and sets the
+// orphan gauge of every kind listed. Orphans are left in place; deleting one may tear down
+// a replication that is still wanted on the backend.
+func (s *FleetSweep) reportOrphans(owned map[string][]unstructured.Unstructured, uvrs []replicationv1alpha1.UnifiedVolumeReplication) {
+	uids := make(map[types.UID]bool, len(uvrs))
+	for i := range uvrs {
+		uids[uvrs[i].UID] = true
+	}
+	for kind, items := range owned {
+		orphans := 0
+		for i := range items {
+			owner := types.UID(items[i].GetLabels()[adapters.OwnerUIDLabel])
+			if uids[owner] {
+				continue
+			}
+			orphans++
+			s.log.Info("Backend resource has no replication", "kind", kind,
+				"namespace", items[i].GetNamespace(), "name", items[i].GetName(), "ownerUID", owner)
+		}
+		fleetSweepOrphans.WithLabelValues(kind).Set(float64(orphans))
+	}
+}
+
+// enqueue hands a UVR to every controller, returning false when ctx is done first
+func (s *FleetSweep) enqueue(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) bool {
+	s.mu.Lock()
+	s.pending[client.ObjectKeyFromObject(uvr).String()] = true
+	s.mu.Unlock()
+
+	for _, ch := range s.channels {
+		select {
+		case ch <- event.GenericEvent{Object: uvr}:
+		case <-ctx.Done():
+			return false
+		}
+	}
+	fleetSweepEnqueued.Inc()
+	return true
+}
+
+// watch adds the swept UVRs as a source of the controller built by b. The predicates keep
+// the controller to its own UVRs, e.g. its backend partition.
+func (s *FleetSweep) watch(b *builder.Builder, h handler.EventHandler, predicates ...predicate.Predicate) *builder.Builder {
+	ch := make(chan event.GenericEvent)
+	s.channels = append(s.channels, ch)
+	return b.WatchesRawSource(source.Channel(ch, h, source.WithPredicates[client.Object, reconcile.Request](predicates...)))
+}
+
+// observe records the outcome of a UVR's reconcile when it was queued by the current sweep.
+// uvr is left empty by a reconcile that did not find it.
+func (s *FleetSweep) observe(key string, uvr *replicationv1alpha1.UnifiedVolumeReplication, err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	swept := s.pending[key]
+	delete(s.pending, key)
+	s.mu.Unlock()
+	if !swept {
+		return
+	}
+
+	switch {
+	case err != nil:
+		fleetSweepResults.WithLabelValues(sweepResultError).Inc()
+	case uvr.Name == "":
+		fleetSweepResults.WithLabelValues(sweepResultDeleted).Inc()
+	case apimeta.IsStatusConditionTrue(uvr.Status.Conditions, "Ready"):
+		fleetSweepResults.WithLabelValues(sweepResultHealthy).Inc()
+	default:
+		fleetSweepResults.WithLabelValues(sweepResultUnhealthy).Inc()
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestFleetSweep_StaggersEnqueues(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	const interval = time.Minute
+	var objects []client.Object
+	for i := 0; i < 4; i++ {
+		objects = append(objects, createTestUVR(fmt.Sprintf("test-sweep-%d", i), "default"))
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(objects...).Build()
+
+	sweep := NewFleetSweep(c, interval, nil, ctrl.Log.WithName("test").WithName("FleetSweep"))
+	events := make(chan event.GenericEvent, len(objects))
+	sweep.channels = append(sweep.channels, events)
+
+	// A fake clock: waiting advances it, and the UVRs received so far are stamped with it
+	var now time.Duration
+	queuedAt := map[string][]time.Duration{}
+	receive := func() {
+		for len(events) > 0 {
+			e := <-events
+			key := client.ObjectKeyFromObject(e.Object).String()
+			queuedAt[key] = append(queuedAt[key], now)
+		}
+	}
+	sweep.wait = func(_ context.Context, d time.Duration) bool {
+		receive()
+		now += d
+		return true
+	}
+
+	enqueued := counterValue(t, fleetSweepEnqueued)
+	require.NoError(t, sweep.sweep(ctx))
+	receive()
+
+	// Every UVR is queued once, evenly spread across the interval
+	require.Len(t, queuedAt, len(objects))
+	for i, obj := range objects {
+		at := queuedAt[client.ObjectKeyFromObject(obj).String()]
+		assert.Equal(t, []time.Duration{time.Duration(i+1) * interval / 4}, at, "%s", obj.GetName())
+	}
+	assert.Equal(t, interval, now, "the sweep takes one interval")
+	assert.Equal(t, enqueued+4, counterValue(t, fleetSweepEnqueued))
+}
+
+func TestFleetSweep_ObserveResults(t *testing.T) {
+	sweep := NewFleetSweep(nil, time.Minute, nil, ctrl.Log.WithName("test").WithName("FleetSweep"))
+	result := func(name string) float64 {
+		return counterValue(t, fleetSweepResults.WithLabelValues(name).(prometheus.Counter))
+	}
+	healthy, unhealthy, failed, deleted := result(sweepResultHealthy), result(sweepResultUnhealthy),
+		result(sweepResultError), result(sweepResultDeleted)
+
+	ready := createTestUVR("ready", "default")
+	ready.Status.Conditions = []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue}}
+	sweep.pending = map[string]bool{"default/ready": true, "default/broken": true, "default/failing": true, "default/gone": true}
+
+	sweep.observe("default/ready", ready, nil)
+	sweep.observe("default/broken", createTestUVR("broken", "default"), nil)
+	sweep.observe("default/failing", createTestUVR("failing", "default"), errors.New("backend unavailable"))
+	sweep.observe("default/gone", &replicationv1alpha1.UnifiedVolumeReplication{}, nil)
+	// Reconciles the sweep did not queue, and later reconciles of swept UVRs, are not counted
+	sweep.observe("default/other", ready, nil)
+	sweep.observe("default/ready", ready, nil)
+
+	assert.Equal(t, healthy+1, result(sweepResultHealthy))
+	assert.Equal(t, unhealthy+1, result(sweepResultUnhealthy))
+	assert.Equal(t, failed+1, result(sweepResultError))
+	assert.Equal(t, deleted+1, result(sweepResultDeleted))
+	assert.Empty(t, sweep.pending)
+
+	// A reconciler without a sweep observes nothing
+	var unset *FleetSweep
+	unset.observe("default/ready", ready, nil)
+}
+
+func TestFleetSweep_ReportsOrphans(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	uvr := createTestUVR("test-sweep-owner", "default")
+	uvr.UID = "live-uid"
+	relationship := func(name, owner string) *unstructured.Unstructured {
+		tmr := &unstructured.Unstructured{}
+		tmr.SetGroupVersionKind(adapters.TridentMirrorRelationshipGVK)
+		tmr.SetName(name)
+		tmr.SetNamespace("default")
+		if owner != "" {
+			tmr.SetLabels(map[string]string{adapters.OwnerUIDLabel: owner})
+		}
+		return tmr
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(uvr,
+		relationship("owned", "live-uid"),
+		relationship("orphaned", "deleted-uid"),
+		relationship("unmanaged", ""),
+	).Build()
+
+	sweep := NewFleetSweep(c, time.Minute, nil, ctrl.Log.WithName("test").WithName("FleetSweep"))
+	sweep.channels = append(sweep.channels, make(chan event.GenericEvent, 1))
+	sweep.wait = func(context.Context, time.Duration) bool { return true }
+	require.NoError(t, sweep.sweep(ctx))
+
+	orphans := &dto.Metric{}
+	require.NoError(t, fleetSweepOrphans.WithLabelValues(adapters.TridentMirrorRelationshipGVK.Kind).Write(orphans))
+	assert.Equal(t, float64(1), orphans.GetGauge().GetValue(), "only the relationship of the deleted UVR is an orphan")
+}
+
+func counterValue(t *testing.T, counter prometheus.Counter) float64 {
+	t.Helper()
+	metric := &dto.Metric{}
+	require.NoError(t, counter.Write(metric))
+	return metric.GetCounter().GetValue()
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// groupMemberKey identifies a group member by its source PVC
+func groupMemberKey(namespace, pvcName string) string {
+	return namespace + "/" + pvcName
+}
+
+// inBackendGroup reports whether a member is currently part of the backend group
+func inBackendGroup(member replicationv1alpha1.GroupMemberStatus) bool {
+	return member.Phase == replicationv1alpha1.GroupMemberActive || member.Phase == replicationv1alpha1.GroupMemberRemoveFailed
+}
+
+// reconcileGroupMembership applies the difference between spec.groupMembers and the members
+// recorded in status to the backend group. Only added and removed members are touched, so
+// existing members keep replicating. The outcome for each member is recorded in
+// status.groupMembers; failed changes are retried on the next reconcile.
+func (r *UnifiedVolumeReplicationReconciler) reconcileGroupMembership(ctx context.Context, adapter adapters.ReplicationAdapter, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) {
+	current := make(map[string]replicationv1alpha1.GroupMemberStatus, len(uvr.Status.GroupMembers))
+	for _, member := range uvr.Status.GroupMembers {
+		current[groupMemberKey(member.Namespace, member.PvcName)] = member
+	}
+
+	desired := make(map[string]bool, len(uvr.Spec.GroupMembers))
+	var toAdd []replicationv1alpha1.VolumeMapping
+	for _, member := range uvr.Spec.GroupMembers {
+		key := groupMemberKey(member.Source.Namespace, member.Source.PvcName)
+		desired[key] = true
+		if existing, ok := current[key]; !ok || !inBackendGroup(existing) {
+			toAdd = append(toAdd, member)
+		}
+	}
+
+	var toRemove []replicationv1alpha1.VolumeMapping
+	for _, member := range uvr.Status.GroupMembers {
+		key := groupMemberKey(member.Namespace, member.PvcName)
+		if desired[key] {
+			continue
+		}
+		if !inBackendGroup(member) {
+			// Never made it into the group, nothing to undo
+			delete(current, key)
+			continue
+		}
+		toRemove = append(toRemove, replicationv1alpha1.VolumeMapping{
+			Source:      replicationv1alpha1.VolumeSource{PvcName: member.PvcName, Namespace: member.Namespace},
+			Destination: replicationv1alpha1.VolumeDestination{VolumeHandle: member.VolumeHandle},
+		})
+	}
+
+	if len(toAdd) > 0 || len(toRemove) > 0 {
+		r.applyGroupMembership(ctx, adapter, uvr, toAdd, toRemove, current, log)
+	}
+
+	// Keep the spec order, followed by members that could not be removed
+	members := make([]replicationv1alpha1.GroupMemberStatus, 0, len(current))
+	for _, member := range uvr.Spec.GroupMembers {
+		key := groupMemberKey(member.Source.Namespace, member.Source.PvcName)
+		if status, ok := current[key]; ok {
+			members = append(members, status)
+			delete(current, key)
+		}
+	}
+	for _, member := range uvr.Status.GroupMembers {
+		if status, ok := current[groupMemberKey(member.Namespace, member.PvcName)]; ok {
+			members = append(members, status)
+		}
+	}
+	if len(members) == 0 {
+		members = nil
+	}
+	uvr.Status.GroupMembers = members
+}
+
+// applyGroupMembership performs the membership changes against the backend, pausing the
+// group around them when the backend requires it, and records the outcome in current
+func (r *UnifiedVolumeReplicationReconciler) applyGroupMembership(ctx context.Context, adapter adapters.ReplicationAdapter, uvr *replicationv1alpha1.UnifiedVolumeReplication,
+	toAdd, toRemove []replicationv1alpha1.VolumeMapping, current map[string]replicationv1alpha1.GroupMemberStatus, log logr.Logger) {
+	setPhase := func(member replicationv1alpha1.VolumeMapping, phase replicationv1alpha1.GroupMemberPhase, message string) {
+		key := groupMemberKey(member.Source.Namespace, member.Source.PvcName)
+		status := current[key]
+		if status.Phase != phase {
+			status.LastTransitionTime = metav1.Now()
+		}
+		status.PvcName = member.Source.PvcName
+		status.Namespace = member.Source.Namespace
+		status.VolumeHandle = member.Destination.VolumeHandle
+		status.Phase = phase
+		status.Message = message
+		current[key] = status
+	}
+	failAll := func(message string) {
+		for _, member := range toAdd {
+			setPhase(member, replicationv1alpha1.GroupMemberAddFailed, message)
+		}
+		for _, member := range toRemove {
+			setPhase(member, replicationv1alpha1.GroupMemberRemoveFailed, message)
+		}
+	}
+
+	manager, ok := adapter.(adapters.GroupMembershipManager)
+	if !ok {
+		failAll(fmt.Sprintf("backend %s does not support group membership changes", adapter.GetBackendType()))
+		return
+	}
+
+	log.Info("Updating replication group membership", "add", len(toAdd), "remove", len(toRemove))
+
+	// A replication paused for a full destination is already quiet; pausing it around the
+	// change would resume it on the way out while the destination is still full
+	if manager.MembershipChangeRequiresPause() && !pausedForFullDestination(r.getCondition(uvr, destinationFullCondition)) {
+		if err := r.ControllerEngine.RunOperation(ctx, uvr, "pause", log, func(ctx context.Context) error {
+			return adapter.PauseReplication(ctx, uvr)
+		}); err != nil {
+			log.Error(err, "Failed to pause replication group for membership change")
+			r.recordEventf(uvr, corev1.EventTypeWarning, "GroupMembershipFailed", "Failed to pause replication group: %v", err)
+			failAll(fmt.Sprintf("failed to pause replication group: %v", err))
+			return
+		}
+		defer func() {
+			if err := r.ControllerEngine.RunOperation(ctx, uvr, "resume", log, func(ctx context.Context) error {
+				return adapter.ResumeReplication(ctx, uvr)
+			}); err != nil {
+				log.Error(err, "Failed to resume replication group after membership change")
+				r.recordEventf(uvr, corev1.EventTypeWarning, "GroupMembershipFailed", "Failed to resume replication group: %v", err)
+			}
+		}()
+	}
+
+	for _, member := range toRemove {
+		if err := manager.RemoveGroupMember(ctx, uvr, member); err != nil {
+			log.Error(err, "Failed to remove group member", "pvc", member.Source.PvcName)
+			r.recordEventf(uvr, corev1.EventTypeWarning, "GroupMemberRemoveFailed", "Failed to remove %s from the replication group: %v", member.Source.PvcName, err)
+			setPhase(member, replicationv1alpha1.GroupMemberRemoveFailed, err.Error())
+			continue
+		}
+		delete(current, groupMemberKey(member.Source.Namespace, member.Source.PvcName))
+		r.recordEventf(uvr, corev1.EventTypeNormal, "GroupMemberRemoved", "Removed %s from the replication group", member.Source.PvcName)
+	}
+
+	for _, member := range toAdd {
+		if err := manager.AddGroupMember(ctx, uvr, member); err != nil {
+			log.Error(err, "Failed to add group member", "pvc", member.Source.PvcName)
+			r.recordEventf(uvr, corev1.EventTypeWarning, "GroupMemberAddFailed", "Failed to add %s to the replication group: %v", member.Source.PvcName, err)
+			setPhase(member, replicationv1alpha1.GroupMemberAddFailed, err.Error())
+			continue
+		}
+		setPhase(member, replicationv1alpha1.GroupMemberActive, "")
+		r.recordEventf(uvr, corev1.EventTypeNormal, "GroupMemberAdded", "Added %s to the replication group", member.Source.PvcName)
+	}
+}
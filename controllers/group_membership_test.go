@@ -0,0 +1,187 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+// groupAdapter records the group operations it receives. Methods that group membership
+// reconciliation does not use are left to the embedded nil interface.
+type groupAdapter struct {
+	adapters.ReplicationAdapter
+	requiresPause bool
+	failAdd       map[string]bool
+	calls         []string
+}
+
+func (a *groupAdapter) GetBackendType() translation.Backend { return translation.BackendPowerStore }
+
+func (a *groupAdapter) PauseReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	a.calls = append(a.calls, "pause")
+	return nil
+}
+
+func (a *groupAdapter) ResumeReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	a.calls = append(a.calls, "resume")
+	return nil
+}
+
+func (a *groupAdapter) AddGroupMember(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, member replicationv1alpha1.VolumeMapping) error {
+	a.calls = append(a.calls, "add "+member.Source.PvcName)
+	if a.failAdd[member.Source.PvcName] {
+		return fmt.Errorf("volume %s is not eligible", member.Source.PvcName)
+	}
+	return nil
+}
+
+func (a *groupAdapter) RemoveGroupMember(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, member replicationv1alpha1.VolumeMapping) error {
+	a.calls = append(a.calls, "remove "+member.Source.PvcName)
+	return nil
+}
+
+func (a *groupAdapter) MembershipChangeRequiresPause() bool { return a.requiresPause }
+
+func groupMember(pvcName string) replicationv1alpha1.VolumeMapping {
+	return replicationv1alpha1.VolumeMapping{
+		Source:      replicationv1alpha1.VolumeSource{PvcName: pvcName, Namespace: "default"},
+		Destination: replicationv1alpha1.VolumeDestination{VolumeHandle: pvcName + "-dest", Namespace: "default"},
+	}
+}
+
+func memberPhases(uvr *replicationv1alpha1.UnifiedVolumeReplication) map[string]replicationv1alpha1.GroupMemberPhase {
+	phases := make(map[string]replicationv1alpha1.GroupMemberPhase)
+	for _, member := range uvr.Status.GroupMembers {
+		phases[member.PvcName] = member.Phase
+	}
+	return phases
+}
+
+func TestReconciler_GroupMembership(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	newReconciler := func(uvr *replicationv1alpha1.UnifiedVolumeReplication) *UnifiedVolumeReplicationReconciler {
+		return createTestReconciler(fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).Build(), s)
+	}
+
+	// An existing group with two members
+	existingGroup := func() *replicationv1alpha1.UnifiedVolumeReplication {
+		uvr := createTestUVR("test-group", "default")
+		uvr.Spec.GroupMembers = []replicationv1alpha1.VolumeMapping{groupMember("data-1"), groupMember("data-2")}
+		uvr.Status.GroupMembers = []replicationv1alpha1.GroupMemberStatus{
+			{PvcName: "data-1", Namespace: "default", VolumeHandle: "data-1-dest", Phase: replicationv1alpha1.GroupMemberActive},
+			{PvcName: "data-2", Namespace: "default", VolumeHandle: "data-2-dest", Phase: replicationv1alpha1.GroupMemberActive},
+		}
+		return uvr
+	}
+
+	t.Run("AddMember", func(t *testing.T) {
+		uvr := existingGroup()
+		uvr.Spec.GroupMembers = append(uvr.Spec.GroupMembers, groupMember("data-3"))
+		reconciler := newReconciler(uvr)
+		adapter := &groupAdapter{}
+
+		reconciler.reconcileGroupMembership(ctx, adapter, uvr, reconciler.Log)
+
+		assert.Equal(t, []string{"add data-3"}, adapter.calls)
+		assert.Equal(t, map[string]replicationv1alpha1.GroupMemberPhase{
+			"data-1": replicationv1alpha1.GroupMemberActive,
+			"data-2": replicationv1alpha1.GroupMemberActive,
+			"data-3": replicationv1alpha1.GroupMemberActive,
+		}, memberPhases(uvr))
+		assert.Contains(t, drainEvents(reconciler.Recorder.(*record.FakeRecorder)), "Normal GroupMemberAdded Added data-3 to the replication group")
+
+		// A second pass with the same spec does nothing
+		adapter.calls = nil
+		reconciler.reconcileGroupMembership(ctx, adapter, uvr, reconciler.Log)
+		assert.Empty(t, adapter.calls)
+	})
+
+	t.Run("RemoveMember", func(t *testing.T) {
+		uvr := existingGroup()
+		uvr.Spec.GroupMembers = uvr.Spec.GroupMembers[:1]
+		reconciler := newReconciler(uvr)
+		adapter := &groupAdapter{requiresPause: true}
+
+		reconciler.reconcileGroupMembership(ctx, adapter, uvr, reconciler.Log)
+
+		// The group is paused only around the change and the remaining member is left alone
+		assert.Equal(t, []string{"pause", "remove data-2", "resume"}, adapter.calls)
+		require.Len(t, uvr.Status.GroupMembers, 1)
+		assert.Equal(t, "data-1", uvr.Status.GroupMembers[0].PvcName)
+		assert.Contains(t, drainEvents(reconciler.Recorder.(*record.FakeRecorder)), "Normal GroupMemberRemoved Removed data-2 from the replication group")
+	})
+
+	t.Run("KeepsDestinationFullPause", func(t *testing.T) {
+		uvr := existingGroup()
+		uvr.Spec.GroupMembers = append(uvr.Spec.GroupMembers, groupMember("data-3"))
+		uvr.Status.Conditions = []metav1.Condition{{
+			Type:   destinationFullCondition,
+			Status: metav1.ConditionTrue,
+			Reason: "ReplicationPaused",
+		}}
+		reconciler := newReconciler(uvr)
+		adapter := &groupAdapter{requiresPause: true}
+
+		// The replication is already paused, so the change neither pauses nor resumes it
+		reconciler.reconcileGroupMembership(ctx, adapter, uvr, reconciler.Log)
+		assert.Equal(t, []string{"add data-3"}, adapter.calls)
+	})
+
+	t.Run("FailedAddIsReportedAndRetried", func(t *testing.T) {
+		uvr := existingGroup()
+		uvr.Spec.GroupMembers = append(uvr.Spec.GroupMembers, groupMember("data-3"))
+		reconciler := newReconciler(uvr)
+		adapter := &groupAdapter{failAdd: map[string]bool{"data-3": true}}
+
+		reconciler.reconcileGroupMembership(ctx, adapter, uvr, reconciler.Log)
+		assert.Equal(t, replicationv1alpha1.GroupMemberAddFailed, memberPhases(uvr)["data-3"])
+		assert.Equal(t, replicationv1alpha1.GroupMemberActive, memberPhases(uvr)["data-1"])
+		assert.Contains(t, uvr.Status.GroupMembers[2].Message, "not eligible")
+
+		adapter.failAdd = nil
+		adapter.calls = nil
+		reconciler.reconcileGroupMembership(ctx, adapter, uvr, reconciler.Log)
+		assert.Equal(t, []string{"add data-3"}, adapter.calls)
+		assert.Equal(t, replicationv1alpha1.GroupMemberActive, memberPhases(uvr)["data-3"])
+	})
+
+	t.Run("UnsupportedBackend", func(t *testing.T) {
+		uvr := createTestUVR("test-group-unsupported", "default")
+		uvr.Spec.GroupMembers = []replicationv1alpha1.VolumeMapping{groupMember("data-1")}
+		reconciler := newReconciler(uvr)
+		mock := adapters.NewMockAdapter(translation.BackendTrident, reconciler.Client, translation.NewEngine(), nil, nil)
+
+		reconciler.reconcileGroupMembership(ctx, mock, uvr, reconciler.Log)
+		require.Len(t, uvr.Status.GroupMembers, 1)
+		assert.Equal(t, replicationv1alpha1.GroupMemberAddFailed, uvr.Status.GroupMembers[0].Phase)
+		assert.Contains(t, uvr.Status.GroupMembers[0].Message, "does not support")
+	})
+}
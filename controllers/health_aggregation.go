@@ -0,0 +1,120 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+// applicationHealthOrder lists the member healths from best to worst, as ranked by
+// applicationHealthRank
+var applicationHealthOrder = []replicationv1alpha1.ApplicationHealth{
+	replicationv1alpha1.ApplicationHealthHealthy,
+	replicationv1alpha1.ApplicationHealthUnknown,
+	replicationv1alpha1.ApplicationHealthDegraded,
+	replicationv1alpha1.ApplicationHealthUnhealthy,
+}
+
+// aggregateApplicationHealth derives the health of an application from its members' under
+// policy and explains how it was derived
+func aggregateApplicationHealth(policy replicationv1alpha1.HealthAggregationPolicy, members []replicationv1alpha1.ApplicationMemberStatus) (replicationv1alpha1.ApplicationHealth, string) {
+	if len(members) == 0 {
+		return replicationv1alpha1.ApplicationHealthUnknown, "no replication matches the selector"
+	}
+
+	switch policy {
+	case replicationv1alpha1.HealthAggregationWeighted:
+		health, carried, total := healthByWeight(members, func(member replicationv1alpha1.ApplicationMemberStatus) int64 {
+			return int64(max(member.Weight, 0))
+		})
+		if total == 0 {
+			return replicationv1alpha1.ApplicationHealthUnknown, "Weighted: every member weighs 0"
+		}
+		basis := fmt.Sprintf("Weighted: members carrying %d of %d weight are %s or better", carried, total, health)
+		return health, basis + unhealthyMembers(members, true)
+
+	case replicationv1alpha1.HealthAggregationMajority:
+		health, carried, total := healthByWeight(members, func(replicationv1alpha1.ApplicationMemberStatus) int64 { return 1 })
+		basis := fmt.Sprintf("Majority: %d of %d members are %s or better", carried, total, health)
+		return health, basis + unhealthyMembers(members, false)
+
+	default:
+		worst := replicationv1alpha1.ApplicationHealthHealthy
+		for _, member := range members {
+			if applicationHealthRank[member.Health] > applicationHealthRank[worst] {
+				worst = member.Health
+			}
+		}
+		if worst == replicationv1alpha1.ApplicationHealthHealthy {
+			return worst, fmt.Sprintf("WorstOf: all %d members are Healthy", len(members))
+		}
+		var names []string
+		for _, member := range members {
+			if member.Health == worst {
+				names = append(names, member.Name)
+			}
+		}
+		verb := "is"
+		if len(names) > 1 {
+			verb = "are"
+		}
+		return worst, fmt.Sprintf("WorstOf: %s %s %s", strings.Join(names, ", "), verb, worst)
+	}
+}
+
+// healthByWeight returns the best health that members carrying more than half of the total
+// weight are at or better than, with the weight carried and the total weight. A tie is
+// resolved towards the worse health.
+func healthByWeight(members []replicationv1alpha1.ApplicationMemberStatus, weight func(replicationv1alpha1.ApplicationMemberStatus) int64) (replicationv1alpha1.ApplicationHealth, int64, int64) {
+	byHealth := map[replicationv1alpha1.ApplicationHealth]int64{}
+	var total int64
+	for _, member := range members {
+		byHealth[member.Health] += weight(member)
+		total += weight(member)
+	}
+	var carried int64
+	for _, health := range applicationHealthOrder {
+		carried += byHealth[health]
+		if 2*carried > total {
+			return health, carried, total
+		}
+	}
+	return replicationv1alpha1.ApplicationHealthUnknown, 0, total
+}
+
+// unhealthyMembers lists the members that are not healthy, with their weight when weighted,
+// for appending to a health basis
+func unhealthyMembers(members []replicationv1alpha1.ApplicationMemberStatus, weighted bool) string {
+	var unhealthy []string
+	for _, member := range members {
+		if member.Health == replicationv1alpha1.ApplicationHealthHealthy {
+			continue
+		}
+		if weighted {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s (%s, weight %d)", member.Name, member.Health, member.Weight))
+		} else {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s (%s)", member.Name, member.Health))
+		}
+	}
+	if len(unhealthy) == 0 {
+		return ""
+	}
+	return "; not healthy: " + strings.Join(unhealthy, ", ")
+}
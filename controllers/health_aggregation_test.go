@@ -0,0 +1,204 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+func TestAggregateApplicationHealth(t *testing.T) {
+	member := func(name string, health replicationv1alpha1.ApplicationHealth, weight int32) replicationv1alpha1.ApplicationMemberStatus {
+		return replicationv1alpha1.ApplicationMemberStatus{Name: name, Health: health, Weight: weight}
+	}
+	// A critical database with a degraded cache and an unhealthy reporting volume
+	mixed := []replicationv1alpha1.ApplicationMemberStatus{
+		member("db", replicationv1alpha1.ApplicationHealthHealthy, 10),
+		member("cache", replicationv1alpha1.ApplicationHealthDegraded, 1),
+		member("reports", replicationv1alpha1.ApplicationHealthUnhealthy, 2),
+	}
+	// The same members when the database fails
+	dbFailed := []replicationv1alpha1.ApplicationMemberStatus{
+		member("db", replicationv1alpha1.ApplicationHealthUnhealthy, 10),
+		member("cache", replicationv1alpha1.ApplicationHealthHealthy, 1),
+		member("reports", replicationv1alpha1.ApplicationHealthHealthy, 2),
+	}
+
+	tests := []struct {
+		name    string
+		policy  replicationv1alpha1.HealthAggregationPolicy
+		members []replicationv1alpha1.ApplicationMemberStatus
+		health  replicationv1alpha1.ApplicationHealth
+		basis   string
+	}{
+		{
+			name:    "WorstOfMixed",
+			policy:  replicationv1alpha1.HealthAggregationWorstOf,
+			members: mixed,
+			health:  replicationv1alpha1.ApplicationHealthUnhealthy,
+			basis:   "WorstOf: reports is Unhealthy",
+		},
+		{
+			name:    "WeightedMixed",
+			policy:  replicationv1alpha1.HealthAggregationWeighted,
+			members: mixed,
+			health:  replicationv1alpha1.ApplicationHealthHealthy,
+			basis:   "Weighted: members carrying 10 of 13 weight are Healthy or better; not healthy: cache (Degraded, weight 1), reports (Unhealthy, weight 2)",
+		},
+		{
+			name:    "MajorityMixed",
+			policy:  replicationv1alpha1.HealthAggregationMajority,
+			members: mixed,
+			health:  replicationv1alpha1.ApplicationHealthDegraded,
+			basis:   "Majority: 2 of 3 members are Degraded or better; not healthy: cache (Degraded), reports (Unhealthy)",
+		},
+		{
+			name:    "WorstOfCriticalMemberFailed",
+			policy:  replicationv1alpha1.HealthAggregationWorstOf,
+			members: dbFailed,
+			health:  replicationv1alpha1.ApplicationHealthUnhealthy,
+			basis:   "WorstOf: db is Unhealthy",
+		},
+		{
+			name:    "WeightedCriticalMemberFailed",
+			policy:  replicationv1alpha1.HealthAggregationWeighted,
+			members: dbFailed,
+			health:  replicationv1alpha1.ApplicationHealthUnhealthy,
+			basis:   "Weighted: members carrying 13 of 13 weight are Unhealthy or better; not healthy: db (Unhealthy, weight 10)",
+		},
+		{
+			name:    "MajorityCriticalMemberFailed",
+			policy:  replicationv1alpha1.HealthAggregationMajority,
+			members: dbFailed,
+			health:  replicationv1alpha1.ApplicationHealthHealthy,
+			basis:   "Majority: 2 of 3 members are Healthy or better; not healthy: db (Unhealthy)",
+		},
+		{
+			name:   "WeightedTieCountsAgainst",
+			policy: replicationv1alpha1.HealthAggregationWeighted,
+			members: []replicationv1alpha1.ApplicationMemberStatus{
+				member("db", replicationv1alpha1.ApplicationHealthHealthy, 1),
+				member("cache", replicationv1alpha1.ApplicationHealthDegraded, 1),
+			},
+			health: replicationv1alpha1.ApplicationHealthDegraded,
+			basis:  "Weighted: members carrying 2 of 2 weight are Degraded or better; not healthy: cache (Degraded, weight 1)",
+		},
+		{
+			name:   "WeightedIgnoresZeroWeight",
+			policy: replicationv1alpha1.HealthAggregationWeighted,
+			members: []replicationv1alpha1.ApplicationMemberStatus{
+				member("db", replicationv1alpha1.ApplicationHealthHealthy, 1),
+				member("scratch", replicationv1alpha1.ApplicationHealthUnhealthy, 0),
+			},
+			health: replicationv1alpha1.ApplicationHealthHealthy,
+			basis:  "Weighted: members carrying 1 of 1 weight are Healthy or better; not healthy: scratch (Unhealthy, weight 0)",
+		},
+		{
+			name:    "WeightedAllZero",
+			policy:  replicationv1alpha1.HealthAggregationWeighted,
+			members: []replicationv1alpha1.ApplicationMemberStatus{member("db", replicationv1alpha1.ApplicationHealthHealthy, 0)},
+			health:  replicationv1alpha1.ApplicationHealthUnknown,
+			basis:   "Weighted: every member weighs 0",
+		},
+		{
+			name:   "WorstOfAllHealthy",
+			policy: replicationv1alpha1.HealthAggregationWorstOf,
+			members: []replicationv1alpha1.ApplicationMemberStatus{
+				member("db", replicationv1alpha1.ApplicationHealthHealthy, 1),
+				member("cache", replicationv1alpha1.ApplicationHealthHealthy, 0),
+			},
+			health: replicationv1alpha1.ApplicationHealthHealthy,
+			basis:  "WorstOf: all 2 members are Healthy",
+		},
+		{
+			name:   "NoMembers",
+			policy: replicationv1alpha1.HealthAggregationWeighted,
+			health: replicationv1alpha1.ApplicationHealthUnknown,
+			basis:  "no replication matches the selector",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			health, basis := aggregateApplicationHealth(tt.policy, tt.members)
+			assert.Equal(t, tt.health, health)
+			assert.Equal(t, tt.basis, basis)
+		})
+	}
+}
+
+func TestApplicationReplication_WeightedHealth(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	db := createAppMember("db")
+	cache := createAppMember("cache")
+	cache.Status.Conditions = append(cache.Status.Conditions, metav1.Condition{
+		Type: degradedCondition, Status: metav1.ConditionTrue, Reason: "SyncLagExceeded",
+		Message: "sync lag exceeds the RPO", LastTransitionTime: metav1.Now(),
+	})
+
+	app := createTestApplication("")
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(app, db, cache).
+		WithStatusSubresource(app, db, cache).Build()
+	reconciler := createTestApplicationReconciler(c)
+	key := client.ObjectKeyFromObject(app)
+	reconcile := func() *metav1.Condition {
+		t.Helper()
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+		require.NoError(t, c.Get(ctx, key, app))
+		return apimeta.FindStatusCondition(app.Status.Conditions, "Ready")
+	}
+
+	// Worst-of is the default: the degraded cache marks the application degraded
+	ready := reconcile()
+	assert.Equal(t, replicationv1alpha1.HealthAggregationWorstOf, app.Status.HealthPolicy)
+	assert.Equal(t, replicationv1alpha1.ApplicationHealthDegraded, app.Status.Health)
+	assert.Equal(t, "WorstOf: cache is Degraded", app.Status.HealthBasis)
+	require.NotNil(t, ready)
+	assert.Equal(t, metav1.ConditionFalse, ready.Status)
+
+	// Weighted by business importance the cache does not outweigh the database
+	app.Spec.HealthAggregation = replicationv1alpha1.HealthAggregationWeighted
+	app.Spec.MemberWeights = map[string]int32{"db": 10}
+	require.NoError(t, c.Update(ctx, app))
+	ready = reconcile()
+	assert.Equal(t, replicationv1alpha1.HealthAggregationWeighted, app.Status.HealthPolicy)
+	assert.Equal(t, replicationv1alpha1.ApplicationHealthHealthy, app.Status.Health)
+	assert.Equal(t, "Weighted: members carrying 10 of 11 weight are Healthy or better; not healthy: cache (Degraded, weight 1)",
+		app.Status.HealthBasis)
+	weights := map[string]int32{}
+	for _, member := range app.Status.Members {
+		weights[member.Name] = member.Weight
+	}
+	assert.Equal(t, map[string]int32{"db": 10, "cache": 1}, weights, "unlisted members weigh 1")
+	require.NotNil(t, ready)
+	assert.Equal(t, metav1.ConditionTrue, ready.Status)
+	assert.Equal(t, "HealthyByPolicy", ready.Reason)
+	assert.Equal(t, "healthy under Weighted health aggregation; not healthy: cache", ready.Message)
+}
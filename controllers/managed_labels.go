@@ -0,0 +1,122 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-logr/logr"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+const (
+	// ManagedLabelPrefix is the label prefix owned by the operator. Labels under it are
+	// rewritten on every reconcile; all other labels are left to users.
+	ManagedLabelPrefix = "managed.replication.storage.io/"
+
+	// BackendLabel carries the backend the UVR was resolved to
+	BackendLabel = ManagedLabelPrefix + "backend"
+
+	// PhaseLabel carries the UVR's ReplicationPhase
+	PhaseLabel = ManagedLabelPrefix + "phase"
+)
+
+// ReplicationPhase summarizes where a UVR is in its lifecycle, derived from its status
+type ReplicationPhase string
+
+const (
+	// PhasePending is a replication whose backend resource has not been created yet
+	PhasePending ReplicationPhase = "Pending"
+	// PhaseEstablishing is a replication whose initial sync has not completed yet
+	PhaseEstablishing ReplicationPhase = "Establishing"
+	// PhaseReplicating is an established replication that is ready
+	PhaseReplicating ReplicationPhase = "Replicating"
+	// PhaseDegraded is a replication that is degraded, or established but not ready
+	PhaseDegraded ReplicationPhase = "Degraded"
+	// PhaseDeactivated is a replication left dormant by spec.deactivated
+	PhaseDeactivated ReplicationPhase = "Deactivated"
+)
+
+// replicationPhase derives the phase of the UVR from its conditions and effective config
+func replicationPhase(uvr *replicationv1alpha1.UnifiedVolumeReplication) ReplicationPhase {
+	conditions := uvr.Status.Conditions
+	established := apimeta.IsStatusConditionTrue(conditions, "InitialSyncComplete")
+	switch {
+	case apimeta.IsStatusConditionTrue(conditions, deactivatedCondition):
+		return PhaseDeactivated
+	case uvr.Status.EffectiveConfig == nil:
+		return PhasePending
+	case apimeta.IsStatusConditionTrue(conditions, degradedCondition),
+		established && apimeta.IsStatusConditionFalse(conditions, "Ready"):
+		return PhaseDegraded
+	case !established:
+		return PhaseEstablishing
+	default:
+		return PhaseReplicating
+	}
+}
+
+// desiredManagedLabels returns the managed labels for the UVR's resolved backend and phase.
+// The backend label is left out until the backend is resolved.
+func desiredManagedLabels(uvr *replicationv1alpha1.UnifiedVolumeReplication) map[string]string {
+	labels := map[string]string{}
+	if uvr.Status.EffectiveConfig != nil && uvr.Status.EffectiveConfig.Backend != "" {
+		labels[BackendLabel] = uvr.Status.EffectiveConfig.Backend
+	}
+	labels[PhaseLabel] = string(replicationPhase(uvr))
+	return labels
+}
+
+// reconcileManagedLabels brings the labels under ManagedLabelPrefix in line with the UVR's
+// status, so selector-based tooling can find UVRs by backend and phase. Only managed labels
+// are sent in the patch, which leaves concurrent changes to user labels intact.
+func (r *UnifiedVolumeReplicationReconciler) reconcileManagedLabels(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) error {
+	desired := desiredManagedLabels(uvr)
+
+	patched := uvr.DeepCopy()
+	changed := false
+	for key := range patched.Labels {
+		if _, ok := desired[key]; !ok && strings.HasPrefix(key, ManagedLabelPrefix) {
+			delete(patched.Labels, key)
+			changed = true
+		}
+	}
+	for key, value := range desired {
+		if patched.Labels[key] != value {
+			if patched.Labels == nil {
+				patched.Labels = map[string]string{}
+			}
+			patched.Labels[key] = value
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := r.Patch(ctx, patched, client.MergeFrom(uvr)); err != nil {
+		return err
+	}
+	log.V(1).Info("Updated managed labels", "labels", desired)
+	uvr.Labels = patched.Labels
+	uvr.ResourceVersion = patched.ResourceVersion
+	return nil
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestReconciler_ManagedLabels(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+
+	uvr := createTestUVR("test-managed-labels", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	uvr.Labels = map[string]string{
+		"app":                           "database",
+		PhaseLabel:                      "Stale",
+		ManagedLabelPrefix + "obsolete": "true",
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(tridentCRDs(""), uvr)...).
+		WithStatusSubresource(uvr).Build()
+
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewTridentAdapterFactory()))
+	reconciler := createTestReconciler(c, s)
+	reconciler.AdapterRegistry = registry
+	reconciler.ControllerEngine = pkg.NewControllerEngine(c, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+		registry, pkg.DefaultControllerEngineConfig())
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	updated := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, map[string]string{
+		"app":        "database",
+		BackendLabel: "trident",
+		PhaseLabel:   string(PhaseEstablishing),
+	}, updated.Labels, "stale managed labels are replaced, user labels kept")
+
+	// A user label added since the last reconcile survives a phase change
+	userEdit := updated.DeepCopy()
+	userEdit.Labels["team"] = "storage"
+	require.NoError(t, c.Patch(ctx, userEdit, client.MergeFrom(updated)))
+
+	reconciler.updateCondition(updated, metav1.Condition{Type: degradedCondition, Status: metav1.ConditionTrue, Reason: "Resyncing"})
+	require.NoError(t, reconciler.reconcileManagedLabels(ctx, updated, reconciler.Log))
+
+	current := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, current))
+	assert.Equal(t, map[string]string{
+		"app":        "database",
+		"team":       "storage",
+		BackendLabel: "trident",
+		PhaseLabel:   "Degraded",
+	}, current.Labels)
+}
+
+func TestReplicationPhase(t *testing.T) {
+	condition := func(conditionType string, status metav1.ConditionStatus) metav1.Condition {
+		return metav1.Condition{Type: conditionType, Status: status}
+	}
+	created := &replicationv1alpha1.EffectiveConfig{Backend: "trident"}
+
+	tests := []struct {
+		name       string
+		config     *replicationv1alpha1.EffectiveConfig
+		conditions []metav1.Condition
+		expected   ReplicationPhase
+	}{
+		{name: "not created", expected: PhasePending},
+		{name: "initial sync running", config: created, conditions: []metav1.Condition{condition("Ready", metav1.ConditionTrue)}, expected: PhaseEstablishing},
+		{
+			name:   "established",
+			config: created,
+			conditions: []metav1.Condition{
+				condition("Ready", metav1.ConditionTrue), condition("InitialSyncComplete", metav1.ConditionTrue),
+			},
+			expected: PhaseReplicating,
+		},
+		{
+			name:   "degraded",
+			config: created,
+			conditions: []metav1.Condition{
+				condition("Ready", metav1.ConditionTrue), condition("InitialSyncComplete", metav1.ConditionTrue),
+				condition(degradedCondition, metav1.ConditionTrue),
+			},
+			expected: PhaseDegraded,
+		},
+		{name: "not ready before the initial sync", config: created, conditions: []metav1.Condition{condition("Ready", metav1.ConditionFalse)}, expected: PhaseEstablishing},
+		{
+			name:   "not ready once established",
+			config: created,
+			conditions: []metav1.Condition{
+				condition("Ready", metav1.ConditionFalse), condition("InitialSyncComplete", metav1.ConditionTrue),
+			},
+			expected: PhaseDegraded,
+		},
+		{name: "deactivated", config: created, conditions: []metav1.Condition{condition(deactivatedCondition, metav1.ConditionTrue)}, expected: PhaseDeactivated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uvr := createTestUVR("test-phase", "default")
+			uvr.Status.EffectiveConfig = tt.config
+			uvr.Status.Conditions = tt.conditions
+			assert.Equal(t, tt.expected, replicationPhase(uvr))
+		})
+	}
+}
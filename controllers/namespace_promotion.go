@@ -0,0 +1,224 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+// PromoteAllRequestAnnotation marks the UVRs promoted by a NamespacePromotion with its name
+const PromoteAllRequestAnnotation = "replication.storage.io/promote-all-request"
+
+// NamespacePromotionReconciler carries out NamespacePromotions, promoting every replica UVR
+// of their namespace. Each eligible UVR is switched to the source role, so the concurrent
+// failover limit and the group consistency check of the UVR controller still apply.
+type NamespacePromotionReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Recorder record.EventRecorder
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *NamespacePromotionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("namespacepromotion").
+		For(&replicationv1alpha1.NamespacePromotion{}).
+		Watches(&replicationv1alpha1.UnifiedVolumeReplication{},
+			handler.EnqueueRequestsFromMapFunc(r.promotionForReplication)).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups=replication.unified.io,resources=namespacepromotions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=replication.unified.io,resources=namespacepromotions/status,verbs=get;update;patch
+
+// Reconcile starts a new NamespacePromotion and reports the progress of a started one
+func (r *NamespacePromotionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("namespacepromotion", req.NamespacedName)
+
+	promotion := &replicationv1alpha1.NamespacePromotion{}
+	if err := r.Get(ctx, req.NamespacedName, promotion); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if promotion.Status.CompletionTime != nil {
+		return ctrl.Result{}, nil
+	}
+
+	uvrs := &replicationv1alpha1.UnifiedVolumeReplicationList{}
+	if err := r.List(ctx, uvrs, client.InNamespace(promotion.Namespace)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list replications: %w", err)
+	}
+
+	// The start time is recorded with the first status update, so a promotion whose
+	// status update failed is started again; the replicas switched by then are no longer
+	// replicas and the marker keeps them counted
+	original := promotion.DeepCopy()
+	if promotion.Status.StartTime == nil {
+		if err := r.startPromoteAll(ctx, promotion, uvrs.Items, log); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	countPromoteAllProgress(promotion, uvrs.Items)
+
+	completed := promotion.Completed()
+	if completed {
+		now := metav1.Now()
+		promotion.Status.CompletionTime = &now
+	}
+	if !equality.Semantic.DeepEqual(original.Status, promotion.Status) {
+		if err := r.Status().Patch(ctx, promotion, client.MergeFrom(original)); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to record promotion progress: %w", err)
+		}
+	}
+
+	if !completed {
+		return ctrl.Result{RequeueAfter: requeueDelaySuccess}, nil
+	}
+	status := promotion.Status
+	log.Info("Namespace promotion completed", "promoted", status.Promoted)
+	r.Recorder.Eventf(promotion, corev1.EventTypeNormal, "PromoteAllCompleted",
+		"Promotion of namespace %s completed: %d promoted, %d skipped", promotion.Namespace, status.Promoted, len(status.Skipped))
+	return ctrl.Result{}, nil
+}
+
+// startPromoteAll switches every eligible replica of the namespace to the source role and
+// records the ones it skips in status
+func (r *NamespacePromotionReconciler) startPromoteAll(ctx context.Context, promotion *replicationv1alpha1.NamespacePromotion,
+	uvrs []replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) error {
+	apps := &replicationv1alpha1.ApplicationReplicationList{}
+	if err := r.List(ctx, apps, client.InNamespace(promotion.Namespace)); err != nil {
+		return fmt.Errorf("failed to list applications: %w", err)
+	}
+
+	status := &promotion.Status
+	promoted := 0
+	for i := range uvrs {
+		uvr := &uvrs[i]
+		if uvr.Spec.ReplicationState != replicationv1alpha1.ReplicationStateReplica {
+			continue
+		}
+		if reason := promoteAllSkipReason(uvr, apps.Items, promotion.Spec.Force); reason != "" {
+			if status.Skipped == nil {
+				status.Skipped = map[string]string{}
+			}
+			status.Skipped[uvr.Name] = reason
+			r.Recorder.Eventf(uvr, corev1.EventTypeWarning, "PromoteAllSkipped",
+				"Not promoted by NamespacePromotion %s: %s", promotion.Name, reason)
+			continue
+		}
+
+		if uvr.Annotations == nil {
+			uvr.Annotations = map[string]string{}
+		}
+		uvr.Annotations[PromoteAllRequestAnnotation] = promotion.Name
+		uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+		if err := r.Update(ctx, uvr); err != nil {
+			return fmt.Errorf("failed to promote %s: %w", uvr.Name, err)
+		}
+		r.Recorder.Eventf(uvr, corev1.EventTypeNormal, "PromoteAll", "Promoted by NamespacePromotion %s", promotion.Name)
+		promoted++
+	}
+
+	now := metav1.Now()
+	status.StartTime = &now
+	log.Info("Namespace promotion started", "forced", promotion.Spec.Force,
+		"promoting", promoted, "skipped", len(status.Skipped))
+	r.Recorder.Eventf(promotion, corev1.EventTypeWarning, "PromoteAllStarted",
+		"Promoting %d replicas of namespace %s, %d skipped", promoted, promotion.Namespace, len(status.Skipped))
+	return nil
+}
+
+// promoteAllSkipReason returns why a replica is left out of a NamespacePromotion, empty when
+// it is promoted. Forcing the promotion skips the safety checks, but never promotes a
+// replication that is being deleted, deactivated, or whose role an application controls.
+func promoteAllSkipReason(uvr *replicationv1alpha1.UnifiedVolumeReplication,
+	apps []replicationv1alpha1.ApplicationReplication, force bool) string {
+	if !uvr.DeletionTimestamp.IsZero() {
+		return "being deleted"
+	}
+	if uvr.Spec.Deactivated {
+		return "deactivated"
+	}
+	for _, app := range apps {
+		selector, err := metav1.LabelSelectorAsSelector(&app.Spec.Selector)
+		if err == nil && app.Spec.ReplicationState != "" && selector.Matches(labels.Set(uvr.Labels)) {
+			return fmt.Sprintf("role managed by ApplicationReplication %s, promote the application instead", app.Name)
+		}
+	}
+	if force {
+		return ""
+	}
+
+	if operation := uvr.Annotations[PlannedOperationAnnotation]; operation != "" {
+		return fmt.Sprintf("planned operation %s in progress", operation)
+	}
+	if apimeta.IsStatusConditionTrue(uvr.Status.Conditions, splitBrainCondition) {
+		return "split-brain detected"
+	}
+	if !apimeta.IsStatusConditionTrue(uvr.Status.Conditions, "InitialSyncComplete") {
+		return "initial sync has not completed"
+	}
+	return ""
+}
+
+// countPromoteAllProgress counts the replicas of the promotion by progress. Replicas
+// switched back to another role since are no longer part of it.
+func countPromoteAllProgress(promotion *replicationv1alpha1.NamespacePromotion, uvrs []replicationv1alpha1.UnifiedVolumeReplication) {
+	status := &promotion.Status
+	status.Total, status.Promoted, status.InProgress, status.Queued = 0, 0, 0, 0
+	for i := range uvrs {
+		uvr := &uvrs[i]
+		if uvr.Annotations[PromoteAllRequestAnnotation] != promotion.Name ||
+			uvr.Spec.ReplicationState != replicationv1alpha1.ReplicationStateSource {
+			continue
+		}
+		status.Total++
+		switch {
+		case lastObservedState(uvr) == string(replicationv1alpha1.ReplicationStateSource):
+			status.Promoted++
+		case uvr.Status.FailoverQueuePosition > 0:
+			status.Queued++
+		default:
+			status.InProgress++
+		}
+	}
+}
+
+// promotionForReplication maps a UVR promoted by a NamespacePromotion to it, so the
+// progress is updated as the promotion advances
+func (r *NamespacePromotionReconciler) promotionForReplication(_ context.Context, obj client.Object) []reconcile.Request {
+	name := obj.GetAnnotations()[PromoteAllRequestAnnotation]
+	if name == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: name}}}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+func TestNamespacePromotion_PromoteAll(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	promotion := &replicationv1alpha1.NamespacePromotion{ObjectMeta: metav1.ObjectMeta{Name: "incident-42", Namespace: "default"}}
+
+	// Two replicas ready for failover are promoted
+	db, web := createAppMember("db"), createAppMember("web")
+	db.Labels, web.Labels = nil, nil
+	// The others are skipped, or not replicas at all
+	dormant := createAppMember("dormant")
+	dormant.Labels = nil
+	dormant.Spec.Deactivated = true
+	syncing := createAppMember("syncing")
+	syncing.Labels = nil
+	syncing.Status.Conditions = nil
+	managed := createAppMember("managed")
+	app := createTestApplication(replicationv1alpha1.ReplicationStateReplica)
+	source := createAppMember("source")
+	source.Labels = nil
+	source.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+	elsewhere := createAppMember("elsewhere")
+	elsewhere.Namespace = "other"
+
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithObjects(promotion, db, web, dormant, syncing, managed, app, source, elsewhere).
+		WithStatusSubresource(promotion, db, web).Build()
+	recorder := record.NewFakeRecorder(100)
+	reconciler := &NamespacePromotionReconciler{
+		Client:   c,
+		Log:      ctrl.Log.WithName("test").WithName("NamespacePromotion"),
+		Recorder: recorder,
+	}
+
+	reconcile := func(promotion *replicationv1alpha1.NamespacePromotion) (ctrl.Result, replicationv1alpha1.NamespacePromotionStatus) {
+		t.Helper()
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(promotion)})
+		require.NoError(t, err)
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(promotion), promotion))
+		return result, promotion.Status
+	}
+	state := func(uvr *replicationv1alpha1.UnifiedVolumeReplication) replicationv1alpha1.ReplicationState {
+		t.Helper()
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(uvr), uvr))
+		return uvr.Spec.ReplicationState
+	}
+	observe := func(uvr *replicationv1alpha1.UnifiedVolumeReplication) {
+		t.Helper()
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(uvr), uvr))
+		uvr.Status.FailoverQueuePosition = 0
+		uvr.Status.StateHistory = append(uvr.Status.StateHistory,
+			replicationv1alpha1.StateHistoryEntry{Timestamp: metav1.Now(), From: "replica", To: "source"})
+		require.NoError(t, c.Status().Update(ctx, uvr))
+	}
+	replicaState := replicationv1alpha1.ReplicationStateReplica
+	sourceState := replicationv1alpha1.ReplicationStateSource
+
+	result, status := reconcile(promotion)
+	assert.Equal(t, sourceState, state(db))
+	assert.Equal(t, sourceState, state(web))
+	assert.Equal(t, "incident-42", db.Annotations[PromoteAllRequestAnnotation])
+	for _, skipped := range []*replicationv1alpha1.UnifiedVolumeReplication{dormant, syncing, managed, elsewhere} {
+		assert.Equal(t, replicaState, state(skipped), "%s is not promoted", skipped.Name)
+	}
+	assert.NotNil(t, status.StartTime)
+	assert.EqualValues(t, 2, status.Total)
+	assert.EqualValues(t, 2, status.InProgress)
+	assert.Equal(t, map[string]string{
+		"dormant": "deactivated",
+		"syncing": "initial sync has not completed",
+		"managed": "role managed by ApplicationReplication shop, promote the application instead",
+	}, status.Skipped)
+	assert.Positive(t, result.RequeueAfter)
+	events := drainEvents(recorder)
+	assert.Contains(t, events, "Warning PromoteAllStarted Promoting 2 replicas of namespace default, 3 skipped")
+
+	// Progress follows the promotions, including the ones waiting for a failover slot
+	web.Status.FailoverQueuePosition = 1
+	require.NoError(t, c.Status().Update(ctx, web))
+	observe(db)
+	_, status = reconcile(promotion)
+	assert.EqualValues(t, 1, status.Promoted)
+	assert.EqualValues(t, 1, status.Queued)
+	assert.Empty(t, drainEvents(recorder), "the promotion is not started again")
+
+	observe(web)
+	result, status = reconcile(promotion)
+	assert.EqualValues(t, 2, status.Promoted)
+	assert.NotNil(t, status.CompletionTime)
+	assert.Zero(t, result.RequeueAfter)
+	assert.Equal(t, []string{"Normal PromoteAllCompleted Promotion of namespace default completed: 2 promoted, 3 skipped"},
+		drainEvents(recorder))
+	_, _ = reconcile(promotion)
+	assert.Empty(t, drainEvents(recorder))
+
+	// A forced promotion promotes the replica that failed the safety checks, but never a
+	// deactivated replication or one whose application controls its role
+	forced := &replicationv1alpha1.NamespacePromotion{
+		ObjectMeta: metav1.ObjectMeta{Name: "incident-43", Namespace: "default"},
+		Spec:       replicationv1alpha1.NamespacePromotionSpec{Force: true},
+	}
+	require.NoError(t, c.Create(ctx, forced))
+	_, status = reconcile(forced)
+	assert.Equal(t, sourceState, state(syncing))
+	assert.Equal(t, "incident-43", syncing.Annotations[PromoteAllRequestAnnotation])
+	assert.Equal(t, replicaState, state(dormant))
+	assert.Equal(t, replicaState, state(managed))
+	assert.EqualValues(t, 1, status.Total)
+	assert.Len(t, status.Skipped, 2)
+}
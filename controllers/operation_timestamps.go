@@ -0,0 +1,58 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// recordLastSuccessfulOperations copies the per-operation success times recorded by the
+// adapters into status.lastSuccessfulOperations. The adapters only keep them in memory, so
+// a timestamp already in status is replaced only by a later one.
+func (r *UnifiedVolumeReplicationReconciler) recordLastSuccessfulOperations(uvr *replicationv1alpha1.UnifiedVolumeReplication) {
+	successes := adapters.GetOperationMetrics().LastSuccesses(client.ObjectKeyFromObject(uvr))
+	if len(successes) == 0 {
+		return
+	}
+
+	timestamps := uvr.Status.LastSuccessfulOperations
+	if timestamps == nil {
+		timestamps = &replicationv1alpha1.OperationTimestamps{}
+	}
+	for operation, field := range map[string]**metav1.Time{
+		adapters.OperationCreate:  &timestamps.Create,
+		adapters.OperationUpdate:  &timestamps.Update,
+		adapters.OperationPromote: &timestamps.Promote,
+		adapters.OperationDemote:  &timestamps.Demote,
+		adapters.OperationResync:  &timestamps.Resync,
+	} {
+		at, ok := successes[operation]
+		if !ok {
+			continue
+		}
+		if *field == nil || (*field).Time.Before(at.Truncate(time.Second)) {
+			*field = &metav1.Time{Time: at}
+		}
+	}
+	uvr.Status.LastSuccessfulOperations = timestamps
+}
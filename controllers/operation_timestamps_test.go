@@ -0,0 +1,102 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestReconciler_LastSuccessfulOperations(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+
+	uvr := createTestUVR("test-last-success", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(tridentCRDs(""), uvr)...).
+		WithStatusSubresource(uvr).Build()
+
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewTridentAdapterFactory()))
+	reconciler := createTestReconciler(c, s)
+	reconciler.AdapterRegistry = registry
+	reconciler.ControllerEngine = pkg.NewControllerEngine(c, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+		registry, pkg.DefaultControllerEngineConfig())
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	updated := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	require.NotNil(t, updated.Status.LastSuccessfulOperations)
+	created := updated.Status.LastSuccessfulOperations.Create
+	require.NotNil(t, created, "the first reconcile creates the backend resource")
+	assert.Nil(t, updated.Status.LastSuccessfulOperations.Update)
+
+	// The next reconcile updates the existing resource
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	require.NotNil(t, updated.Status.LastSuccessfulOperations.Update)
+	assert.Equal(t, created.Unix(), updated.Status.LastSuccessfulOperations.Create.Unix())
+
+	// A resync is reported once the controller reconciles again
+	adapter, err := adapters.NewTridentAdapter(c, reconciler.TranslationEngine)
+	require.NoError(t, err)
+	require.NoError(t, adapter.ResyncReplication(ctx, updated))
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	assert.NotNil(t, updated.Status.LastSuccessfulOperations.Resync)
+}
+
+func TestReconciler_LastSuccessfulOperationsKeepsNewerStatus(t *testing.T) {
+	s := createTestScheme(t)
+	reconciler := createTestReconciler(fake.NewClientBuilder().WithScheme(s).Build(), s)
+
+	uvr := createTestUVR("test-last-success-restart", "default")
+	key := client.ObjectKeyFromObject(uvr)
+	defer adapters.GetOperationMetrics().ForgetReplication(key)
+
+	// Times recorded before an operator restart stay until a newer success replaces them
+	future := metav1.NewTime(time.Now().Add(time.Hour))
+	uvr.Status.LastSuccessfulOperations = &replicationv1alpha1.OperationTimestamps{Create: &future}
+
+	adapters.GetOperationMetrics().RecordSuccess(key, adapters.OperationCreate, time.Now())
+	adapters.GetOperationMetrics().RecordSuccess(key, adapters.OperationPromote, time.Now())
+	reconciler.recordLastSuccessfulOperations(uvr)
+
+	assert.Equal(t, future, *uvr.Status.LastSuccessfulOperations.Create)
+	assert.NotNil(t, uvr.Status.LastSuccessfulOperations.Promote)
+	assert.Nil(t, uvr.Status.LastSuccessfulOperations.Demote)
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+const (
+	// PlannedOperationAnnotation flags a UVR as undergoing an intentional DR operation
+	// (e.g. a planned failover). The value is free-form and describes the operation.
+	PlannedOperationAnnotation = "replication.storage.io/planned-operation"
+
+	// PlannedEventAnnotation is attached to events emitted during a planned operation
+	PlannedEventAnnotation = "planned"
+
+	// plannedOperationCondition records when the planned operation was first observed
+	plannedOperationCondition = "PlannedOperation"
+
+	// defaultPlannedOperationTimeout bounds how long health warnings stay suppressed
+	defaultPlannedOperationTimeout = 30 * time.Minute
+)
+
+// healthBreaches counts the health breaches reported as events, labelled planned="true"
+// when they happened during a planned operation so alerts can leave those out
+var healthBreaches = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "unified_replication_health_breaches_total",
+	Help: "Times the replication health turned Degraded or Unhealthy, by reason and whether a planned operation was in progress",
+}, []string{"namespace", "name", "health", "reason", "planned"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(healthBreaches)
+}
+
+// isPlannedOperationActive returns true while the planned operation annotation is set
+func (r *UnifiedVolumeReplicationReconciler) isPlannedOperationActive(uvr *replicationv1alpha1.UnifiedVolumeReplication) bool {
+	_, ok := uvr.Annotations[PlannedOperationAnnotation]
+	return ok
+}
+
+// getPlannedOperationTimeout returns the configured planned operation timeout
+func (r *UnifiedVolumeReplicationReconciler) getPlannedOperationTimeout() time.Duration {
+	if r.PlannedOperationTimeout > 0 {
+		return r.PlannedOperationTimeout
+	}
+	return defaultPlannedOperationTimeout
+}
+
+// trackPlannedOperation starts tracking a newly flagged planned operation and clears
+// the flag once it has been active for longer than the configured timeout
+func (r *UnifiedVolumeReplicationReconciler) trackPlannedOperation(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) error {
+	if !r.isPlannedOperationActive(uvr) {
+		return nil
+	}
+
+	cond := r.getCondition(uvr, plannedOperationCondition)
+	if cond == nil || cond.Status != metav1.ConditionTrue {
+		operation := uvr.Annotations[PlannedOperationAnnotation]
+		log.Info("Planned operation started, suppressing health warnings", "operation", operation)
+		r.updateCondition(uvr, metav1.Condition{
+			Type:               plannedOperationCondition,
+			Status:             metav1.ConditionTrue,
+			Reason:             "InProgress",
+			Message:            fmt.Sprintf("Planned operation %q in progress", operation),
+			ObservedGeneration: uvr.Generation,
+		})
+		return nil
+	}
+
+	if time.Since(cond.LastTransitionTime.Time) > r.getPlannedOperationTimeout() {
+		return r.endPlannedOperation(ctx, uvr, "TimedOut",
+			fmt.Sprintf("Planned operation did not complete within %s", r.getPlannedOperationTimeout()), log)
+	}
+
+	return nil
+}
+
+// completePlannedOperation clears the planned operation once the backend reports the
+// desired state as healthy
+func (r *UnifiedVolumeReplicationReconciler) completePlannedOperation(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, status *adapters.ReplicationStatus, log logr.Logger) error {
+	if !r.isPlannedOperationActive(uvr) || status == nil {
+		return nil
+	}
+
+	if status.State != string(uvr.Spec.ReplicationState) || status.Health != adapters.ReplicationHealthHealthy {
+		return nil
+	}
+
+	return r.endPlannedOperation(ctx, uvr, "Completed", "Planned operation completed", log)
+}
+
+// endPlannedOperation removes the planned operation annotation and records the outcome.
+// The annotation is removed with a patch so in-memory status changes are preserved.
+func (r *UnifiedVolumeReplicationReconciler) endPlannedOperation(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, reason, message string, log logr.Logger) error {
+	patched := uvr.DeepCopy()
+	delete(patched.Annotations, PlannedOperationAnnotation)
+	if err := r.Patch(ctx, patched, client.MergeFrom(uvr)); err != nil {
+		return fmt.Errorf("failed to clear planned operation annotation: %w", err)
+	}
+	delete(uvr.Annotations, PlannedOperationAnnotation)
+	uvr.ResourceVersion = patched.ResourceVersion
+
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               plannedOperationCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+	r.recordEventf(uvr, corev1.EventTypeNormal, "PlannedOperationEnded", "%s", message)
+
+	log.Info("Planned operation ended, health warnings resumed", "reason", reason)
+	return nil
+}
+
+// recordEventf emits an event, labelling it as planned while a planned operation is active
+func (r *UnifiedVolumeReplicationReconciler) recordEventf(uvr *replicationv1alpha1.UnifiedVolumeReplication, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.isPlannedOperationActive(uvr) {
+		r.Recorder.AnnotatedEventf(uvr, map[string]string{PlannedEventAnnotation: "true"}, eventType, reason, messageFmt, args...)
+		return
+	}
+	r.Recorder.Eventf(uvr, eventType, reason, messageFmt, args...)
+}
+
+// recordHealthEvent emits a warning when the backend reports a health breach. During a
+// planned operation the transient degradation is expected, so it is recorded as a
+// normal event instead to avoid paging on expected DR activity. The event and the
+// breach metric fire once per breach: while the Degraded condition already carries the
+// same reason nothing is emitted. It must run before recordDegraded updates the condition.
+func (r *UnifiedVolumeReplicationReconciler) recordHealthEvent(uvr *replicationv1alpha1.UnifiedVolumeReplication, status *adapters.ReplicationStatus) {
+	var reason string
+	switch status.Health {
+	case adapters.ReplicationHealthDegraded:
+		reason = "ReplicationDegraded"
+	case adapters.ReplicationHealthUnhealthy:
+		reason = "ReplicationUnhealthy"
+	default:
+		return
+	}
+
+	degradedReason := status.DegradedReason
+	if degradedReason == "" {
+		degradedReason = adapters.DegradedReasonUnknown
+	}
+	if existing := r.getCondition(uvr, degradedCondition); existing != nil &&
+		existing.Status == metav1.ConditionTrue && existing.Reason == string(degradedReason) {
+		return
+	}
+
+	planned := r.isPlannedOperationActive(uvr)
+	healthBreaches.WithLabelValues(uvr.Namespace, uvr.Name, string(status.Health), string(degradedReason),
+		strconv.FormatBool(planned)).Inc()
+
+	eventType := corev1.EventTypeWarning
+	if planned {
+		eventType = corev1.EventTypeNormal
+	}
+	r.recordEventf(uvr, eventType, reason, "Replication health is %s: %s", status.Health, status.Message)
+}
+
+// forgetHealthBreaches drops the breach counts of a deleted replication
+func forgetHealthBreaches(uvr *replicationv1alpha1.UnifiedVolumeReplication) {
+	healthBreaches.DeletePartialMatch(prometheus.Labels{"namespace": uvr.Namespace, "name": uvr.Name})
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func drainEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestPlannedOperation_SuppressesHealthWarnings(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	uvr := createTestUVR("planned-failover", "default")
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStatePromoting
+	uvr.Annotations = map[string]string{PlannedOperationAnnotation: "failover"}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).Build()
+	reconciler := createTestReconciler(fakeClient, s)
+	recorder := reconciler.Recorder.(*record.FakeRecorder)
+
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(uvr), uvr))
+
+	// Flagged failover in progress: degradation is recorded as a planned, non-warning event
+	require.NoError(t, reconciler.trackPlannedOperation(ctx, uvr, reconciler.Log))
+	cond := reconciler.getCondition(uvr, plannedOperationCondition)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+
+	reconciler.updateStatusFromEngineStatus(uvr, &adapters.ReplicationStatus{
+		State:  "replica",
+		Health: adapters.ReplicationHealthDegraded,
+	}, reconciler.Log)
+
+	events := drainEvents(recorder)
+	require.Len(t, events, 1)
+	assert.Contains(t, events[0], "Normal ReplicationDegraded")
+	assert.Contains(t, events[0], "planned:true")
+	defer forgetHealthBreaches(uvr)
+	breaches := func(health adapters.ReplicationHealth, reason adapters.DegradedReason, planned string) float64 {
+		metric := &dto.Metric{}
+		require.NoError(t, healthBreaches.WithLabelValues(uvr.Namespace, uvr.Name, string(health), string(reason), planned).(prometheus.Counter).Write(metric))
+		return metric.GetCounter().GetValue()
+	}
+	assert.Equal(t, 1.0, breaches(adapters.ReplicationHealthDegraded, adapters.DegradedReasonUnknown, "true"))
+
+	// The same breach is not reported again on every reconcile
+	reconciler.updateStatusFromEngineStatus(uvr, &adapters.ReplicationStatus{
+		State:  "replica",
+		Health: adapters.ReplicationHealthDegraded,
+	}, reconciler.Log)
+	assert.Empty(t, drainEvents(recorder))
+	assert.Equal(t, 1.0, breaches(adapters.ReplicationHealthDegraded, adapters.DegradedReasonUnknown, "true"))
+
+	// Operation completes once the desired state is reached and healthy
+	require.NoError(t, reconciler.completePlannedOperation(ctx, uvr, &adapters.ReplicationStatus{
+		State:  "promoting",
+		Health: adapters.ReplicationHealthHealthy,
+	}, reconciler.Log))
+	assert.NotContains(t, uvr.Annotations, PlannedOperationAnnotation)
+	cond = reconciler.getCondition(uvr, plannedOperationCondition)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, "Completed", cond.Reason)
+
+	stored := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(uvr), stored))
+	assert.NotContains(t, stored.Annotations, PlannedOperationAnnotation)
+	drainEvents(recorder)
+
+	// Warnings resume afterwards
+	reconciler.updateStatusFromEngineStatus(uvr, &adapters.ReplicationStatus{
+		State:          "source",
+		Health:         adapters.ReplicationHealthUnhealthy,
+		DegradedReason: adapters.DegradedReasonSessionFailure,
+	}, reconciler.Log)
+
+	events = drainEvents(recorder)
+	require.Len(t, events, 1)
+	assert.Contains(t, events[0], "Warning ReplicationUnhealthy")
+	assert.NotContains(t, events[0], "planned:true")
+	assert.Equal(t, 1.0, breaches(adapters.ReplicationHealthUnhealthy, adapters.DegradedReasonSessionFailure, "false"))
+}
+
+func TestPlannedOperation_Timeout(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	uvr := createTestUVR("planned-timeout", "default")
+	uvr.Annotations = map[string]string{PlannedOperationAnnotation: "failover"}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).Build()
+	reconciler := createTestReconciler(fakeClient, s)
+	reconciler.PlannedOperationTimeout = time.Minute
+
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(uvr), uvr))
+	uvr.Status.Conditions = []metav1.Condition{{
+		Type:               plannedOperationCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "InProgress",
+		LastTransitionTime: metav1.NewTime(time.Now().Add(-2 * time.Minute)),
+	}}
+
+	require.NoError(t, reconciler.trackPlannedOperation(ctx, uvr, reconciler.Log))
+	assert.False(t, reconciler.isPlannedOperationActive(uvr))
+
+	cond := reconciler.getCondition(uvr, plannedOperationCondition)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, "TimedOut", cond.Reason)
+}
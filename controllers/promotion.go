@@ -0,0 +1,106 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// defaultGroupSyncTolerance is how far a group member may trail the most recently synced
+// member when the schedule has no RPO
+const defaultGroupSyncTolerance = 5 * time.Minute
+
+// isPromotionPending reports whether the spec asks to promote a volume last observed as a replica
+func isPromotionPending(uvr *replicationv1alpha1.UnifiedVolumeReplication) bool {
+	if uvr.Spec.ReplicationState != replicationv1alpha1.ReplicationStateSource {
+		return false
+	}
+	n := len(uvr.Status.StateHistory)
+	return n > 0 && uvr.Status.StateHistory[n-1].To == string(replicationv1alpha1.ReplicationStateReplica)
+}
+
+// CanPromote reports whether the volumes of a replication group are consistent enough to be
+// promoted together. A member that never synced, or whose last sync trails the most recently
+// synced member by more than the RPO, would leave the promoted group partially consistent;
+// the returned message names it. Freshness is judged relative to the group rather than to
+// the current time so a failover after the source site is lost is not blocked. Replications
+// without group members can always be promoted; a group whose member status is not reported
+// cannot, since its consistency is unknown.
+func (r *UnifiedVolumeReplicationReconciler) CanPromote(uvr *replicationv1alpha1.UnifiedVolumeReplication, status *adapters.ReplicationStatus) (bool, string) {
+	if len(uvr.Spec.GroupMembers) == 0 {
+		return true, ""
+	}
+	if status == nil || len(status.Members) == 0 {
+		return false, "the backend does not report the sync state of the group members"
+	}
+
+	tolerance := defaultGroupSyncTolerance
+	if rpo, err := parseScheduleDuration(uvr.Spec.Schedule.Rpo); err == nil && rpo > 0 {
+		tolerance = rpo
+	}
+
+	reported := make(map[string]adapters.MemberSyncStatus, len(status.Members))
+	var newest time.Time
+	for _, member := range status.Members {
+		reported[member.PvcName] = member
+		if member.LastSyncTime != nil && member.LastSyncTime.After(newest) {
+			newest = *member.LastSyncTime
+		}
+	}
+
+	members := make([]string, 0, len(uvr.Spec.GroupMembers)+1)
+	members = append(members, uvr.Spec.VolumeMapping.Source.PvcName)
+	for _, member := range uvr.Spec.GroupMembers {
+		members = append(members, member.Source.PvcName)
+	}
+
+	for _, pvcName := range members {
+		member, ok := reported[pvcName]
+		if !ok || member.LastSyncTime == nil {
+			return false, fmt.Sprintf("group member %s has not completed a sync", pvcName)
+		}
+		if behind := newest.Sub(*member.LastSyncTime); behind > tolerance {
+			return false, fmt.Sprintf("group member %s is %s behind the most recently synced member (tolerance %s)",
+				pvcName, behind.Round(time.Second), tolerance)
+		}
+	}
+
+	return true, ""
+}
+
+// PromotionAllowed reads the group member status from the backend and reports whether the
+// replication can be promoted, as decided by CanPromote. A status that cannot be read
+// blocks the promotion of a group.
+func (r *UnifiedVolumeReplicationReconciler) PromotionAllowed(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (bool, string) {
+	if len(uvr.Spec.GroupMembers) == 0 {
+		return true, ""
+	}
+	log := r.Log.WithValues("unifiedvolumereplication", client.ObjectKeyFromObject(uvr))
+	status, err := r.ControllerEngine.GetReplicationStatus(ctx, uvr, log)
+	if err != nil {
+		log.Error(err, "Failed to read group member status before promotion")
+		return false, fmt.Sprintf("group member status unavailable: %v", err)
+	}
+	return r.CanPromote(uvr, status)
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func newGroupUVR(name string) *replicationv1alpha1.UnifiedVolumeReplication {
+	uvr := createTestUVR(name, "default")
+	uvr.Spec.GroupMembers = []replicationv1alpha1.VolumeMapping{{
+		Source:      replicationv1alpha1.VolumeSource{PvcName: "data-pvc", Namespace: "default"},
+		Destination: replicationv1alpha1.VolumeDestination{VolumeHandle: "data-dest", Namespace: "default"},
+	}}
+	return uvr
+}
+
+func TestReconciler_CanPromote(t *testing.T) {
+	s := createTestScheme(t)
+	reconciler := createTestReconciler(fake.NewClientBuilder().WithScheme(s).Build(), s)
+
+	now := time.Now()
+	ago := func(d time.Duration) *time.Time {
+		t := now.Add(-d)
+		return &t
+	}
+
+	tests := []struct {
+		name    string
+		uvr     *replicationv1alpha1.UnifiedVolumeReplication
+		members []adapters.MemberSyncStatus
+		allowed bool
+		message string
+	}{
+		{
+			name: "all members fresh",
+			uvr:  newGroupUVR("fresh"),
+			members: []adapters.MemberSyncStatus{
+				{PvcName: "source-pvc", LastSyncTime: ago(time.Minute)},
+				{PvcName: "data-pvc", LastSyncTime: ago(3 * time.Minute)},
+			},
+			allowed: true,
+		},
+		{
+			name: "all members equally stale after losing the source",
+			uvr:  newGroupUVR("site-down"),
+			members: []adapters.MemberSyncStatus{
+				{PvcName: "source-pvc", LastSyncTime: ago(2 * time.Hour)},
+				{PvcName: "data-pvc", LastSyncTime: ago(2*time.Hour + time.Minute)},
+			},
+			allowed: true,
+		},
+		{
+			name: "one member lags",
+			uvr:  newGroupUVR("lagging"),
+			members: []adapters.MemberSyncStatus{
+				{PvcName: "source-pvc", LastSyncTime: ago(time.Minute)},
+				{PvcName: "data-pvc", LastSyncTime: ago(41 * time.Minute)},
+			},
+			allowed: false,
+			message: "group member data-pvc is 40m0s behind the most recently synced member (tolerance 15m0s)",
+		},
+		{
+			name: "member never synced",
+			uvr:  newGroupUVR("never-synced"),
+			members: []adapters.MemberSyncStatus{
+				{PvcName: "source-pvc", LastSyncTime: ago(time.Minute)},
+				{PvcName: "data-pvc"},
+			},
+			allowed: false,
+			message: "group member data-pvc has not completed a sync",
+		},
+		{
+			name: "member missing from backend status",
+			uvr:  newGroupUVR("missing"),
+			members: []adapters.MemberSyncStatus{
+				{PvcName: "source-pvc", LastSyncTime: ago(time.Minute)},
+			},
+			allowed: false,
+			message: "group member data-pvc has not completed a sync",
+		},
+		{
+			name:    "backend without per-volume status",
+			uvr:     newGroupUVR("unreported"),
+			allowed: false,
+			message: "the backend does not report the sync state of the group members",
+		},
+		{
+			name: "no group members",
+			uvr:  createTestUVR("single", "default"),
+			members: []adapters.MemberSyncStatus{
+				{PvcName: "source-pvc"},
+			},
+			allowed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, message := reconciler.CanPromote(tt.uvr, &adapters.ReplicationStatus{Members: tt.members})
+			assert.Equal(t, tt.allowed, allowed)
+			assert.Equal(t, tt.message, message)
+		})
+	}
+}
+
+func TestReconciler_GroupPromotion(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+
+	uvr := newGroupUVR("test-group-promotion")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(tridentCRDs(""), uvr)...).
+		WithStatusSubresource(uvr).Build()
+
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewTridentAdapterFactory()))
+	reconciler := createTestReconciler(c, s)
+	reconciler.AdapterRegistry = registry
+	reconciler.ControllerEngine = pkg.NewControllerEngine(c, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+		registry, pkg.DefaultControllerEngineConfig())
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+
+	// Establish the relationship as a replica
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	setMemberTransfers := func(dataLag time.Duration) {
+		tmr := &unstructured.Unstructured{}
+		tmr.SetGroupVersionKind(adapters.TridentMirrorRelationshipGVK)
+		require.NoError(t, c.Get(ctx, req.NamespacedName, tmr))
+		now := time.Now().UTC()
+		require.NoError(t, unstructured.SetNestedField(tmr.Object, map[string]interface{}{
+			"state":            "established",
+			"lastTransferTime": now.Format(time.RFC3339),
+			"volumeMappings": []interface{}{
+				map[string]interface{}{"localPVCName": "source-pvc", "lastTransferTime": now.Format(time.RFC3339)},
+				map[string]interface{}{"localPVCName": "data-pvc", "lastTransferTime": now.Add(-dataLag).Format(time.RFC3339)},
+			},
+		}, "status"))
+		require.NoError(t, c.Update(ctx, tmr))
+	}
+	promote := func() *replicationv1alpha1.UnifiedVolumeReplication {
+		current := &replicationv1alpha1.UnifiedVolumeReplication{}
+		require.NoError(t, c.Get(ctx, req.NamespacedName, current))
+		current.Status.StateHistory = []replicationv1alpha1.StateHistoryEntry{
+			{Timestamp: metav1.Now(), To: string(replicationv1alpha1.ReplicationStateReplica)},
+		}
+		require.NoError(t, c.Status().Update(ctx, current))
+		current.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+		require.NoError(t, c.Update(ctx, current))
+
+		_, err := reconciler.Reconcile(ctx, req)
+		require.NoError(t, err)
+		require.NoError(t, c.Get(ctx, req.NamespacedName, current))
+		return current
+	}
+	drainEvents(reconciler.Recorder.(*record.FakeRecorder))
+
+	tmrVersion := func() string {
+		tmr := &unstructured.Unstructured{}
+		tmr.SetGroupVersionKind(adapters.TridentMirrorRelationshipGVK)
+		require.NoError(t, c.Get(ctx, req.NamespacedName, tmr))
+		return tmr.GetResourceVersion()
+	}
+
+	// One member lags: promotion is refused and the member is named
+	setMemberTransfers(time.Hour)
+	before := tmrVersion()
+	updated := promote()
+	cond := reconciler.getCondition(updated, "Ready")
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, "PromotionBlocked", cond.Reason)
+	assert.Contains(t, cond.Message, "group member data-pvc is 1h0m0s behind")
+	assert.Contains(t, drainEvents(reconciler.Recorder.(*record.FakeRecorder)),
+		"Warning PromotionBlocked Promotion blocked: group member data-pvc is 1h0m0s behind the most recently synced member (tolerance 15m0s)")
+	assert.Equal(t, before, tmrVersion(), "the backend must not be touched")
+
+	// All members caught up: promotion proceeds
+	setMemberTransfers(time.Minute)
+	before = tmrVersion()
+	updated = promote()
+	cond = reconciler.getCondition(updated, "Ready")
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.NotEqual(t, before, tmrVersion(), "the promotion is applied to the backend")
+}
@@ -0,0 +1,438 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/discovery"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+// ReconcileCaptureVersion is the format version of ReconcileCapture artifacts
+const ReconcileCaptureVersion = 1
+
+// ReconcileCapture records the inputs of one reconcile and the decisions made from them, so
+// the reconcile can be replayed offline with urep-replay
+type ReconcileCapture struct {
+	Version    int         `json:"version"`
+	CapturedAt metav1.Time `json:"capturedAt"`
+
+	// UVR is the resource as read at the start of the reconcile, including its status
+	UVR *replicationv1alpha1.UnifiedVolumeReplication `json:"uvr"`
+
+	// DiscoveredBackends are the backends discovery reported as available
+	DiscoveredBackends []translation.Backend `json:"discoveredBackends"`
+
+	// AdapterCalls are the adapter results seen during the reconcile, in call order
+	AdapterCalls []AdapterCall `json:"adapterCalls,omitempty"`
+
+	// Decision is what the reconcile decided
+	Decision ReconcileDecision `json:"decision"`
+
+	mu sync.Mutex
+}
+
+// AdapterCall is the result of one adapter operation
+type AdapterCall struct {
+	Backend   translation.Backend         `json:"backend"`
+	Operation string                      `json:"operation"`
+	Status    *adapters.ReplicationStatus `json:"status,omitempty"`
+	Error     *CapturedError              `json:"error,omitempty"`
+}
+
+// CapturedError keeps an error in a form that can be rebuilt on replay. Adapter errors keep
+// their fields so type checks on the replayed error behave the same.
+type CapturedError struct {
+	Message string `json:"message"`
+
+	AdapterErrorType adapters.AdapterErrorType `json:"adapterErrorType,omitempty"`
+	Backend          translation.Backend       `json:"backend,omitempty"`
+	Operation        string                    `json:"operation,omitempty"`
+	Resource         string                    `json:"resource,omitempty"`
+	AdapterMessage   string                    `json:"adapterMessage,omitempty"`
+	Cause            string                    `json:"cause,omitempty"`
+}
+
+// ReconcileDecision is the outcome of a reconcile that a replay must reproduce. Condition
+// messages and timestamps are left out as they embed wall-clock values.
+type ReconcileDecision struct {
+	RequeueAfter string              `json:"requeueAfter,omitempty"`
+	Error        string              `json:"error,omitempty"`
+	Conditions   []DecisionCondition `json:"conditions,omitempty"`
+}
+
+// DecisionCondition is the part of a status condition a replay must reproduce
+type DecisionCondition struct {
+	Type   string                 `json:"type"`
+	Status metav1.ConditionStatus `json:"status"`
+	Reason string                 `json:"reason"`
+}
+
+// Diff lists the differences between two decisions, empty when they are identical
+func (d ReconcileDecision) Diff(other ReconcileDecision) []string {
+	var diffs []string
+	if d.RequeueAfter != other.RequeueAfter {
+		diffs = append(diffs, fmt.Sprintf("requeueAfter: %q != %q", d.RequeueAfter, other.RequeueAfter))
+	}
+	if d.Error != other.Error {
+		diffs = append(diffs, fmt.Sprintf("error: %q != %q", d.Error, other.Error))
+	}
+
+	conditions := make(map[string]DecisionCondition, len(other.Conditions))
+	for _, condition := range other.Conditions {
+		conditions[condition.Type] = condition
+	}
+	for _, condition := range d.Conditions {
+		otherCondition, ok := conditions[condition.Type]
+		delete(conditions, condition.Type)
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("condition %s: %s/%s != missing", condition.Type, condition.Status, condition.Reason))
+		} else if condition != otherCondition {
+			diffs = append(diffs, fmt.Sprintf("condition %s: %s/%s != %s/%s", condition.Type,
+				condition.Status, condition.Reason, otherCondition.Status, otherCondition.Reason))
+		}
+	}
+	for _, condition := range other.Conditions {
+		if _, ok := conditions[condition.Type]; ok {
+			diffs = append(diffs, fmt.Sprintf("condition %s: missing != %s/%s", condition.Type, condition.Status, condition.Reason))
+		}
+	}
+	return diffs
+}
+
+// newReconcileDecision summarizes the result of a reconcile
+func newReconcileDecision(uvr *replicationv1alpha1.UnifiedVolumeReplication, result ctrl.Result, err error) ReconcileDecision {
+	decision := ReconcileDecision{}
+	if result.RequeueAfter > 0 {
+		decision.RequeueAfter = result.RequeueAfter.String()
+	}
+	if err != nil {
+		decision.Error = err.Error()
+	}
+	for _, condition := range uvr.Status.Conditions {
+		decision.Conditions = append(decision.Conditions, DecisionCondition{
+			Type:   condition.Type,
+			Status: condition.Status,
+			Reason: condition.Reason,
+		})
+	}
+	return decision
+}
+
+// captureError converts an error for storage in a capture
+func captureError(err error) *CapturedError {
+	if err == nil {
+		return nil
+	}
+	captured := &CapturedError{Message: err.Error()}
+
+	var adapterErr *adapters.AdapterError
+	if errors.As(err, &adapterErr) && adapterErr.Error() == err.Error() {
+		captured.AdapterErrorType = adapterErr.Type
+		captured.Backend = adapterErr.Backend
+		captured.Operation = adapterErr.Operation
+		captured.Resource = adapterErr.Resource
+		captured.AdapterMessage = adapterErr.Message
+		if adapterErr.Cause != nil {
+			captured.Cause = adapterErr.Cause.Error()
+		}
+	}
+	return captured
+}
+
+// Err rebuilds the captured error
+func (ce *CapturedError) Err() error {
+	if ce == nil {
+		return nil
+	}
+	if ce.AdapterErrorType == "" {
+		return errors.New(ce.Message)
+	}
+	if ce.Cause != "" {
+		return adapters.NewAdapterErrorWithCause(ce.AdapterErrorType, ce.Backend, ce.Operation, ce.Resource, ce.AdapterMessage, errors.New(ce.Cause))
+	}
+	return adapters.NewAdapterError(ce.AdapterErrorType, ce.Backend, ce.Operation, ce.Resource, ce.AdapterMessage)
+}
+
+// recordAdapterCall appends an adapter result; it is a no-op on a nil capture
+func (c *ReconcileCapture) recordAdapterCall(call AdapterCall) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.AdapterCalls = append(c.AdapterCalls, call)
+}
+
+type reconcileCaptureKey struct{}
+
+// withReconcileCapture returns a context carrying the capture of the current reconcile
+func withReconcileCapture(ctx context.Context, capture *ReconcileCapture) context.Context {
+	return context.WithValue(ctx, reconcileCaptureKey{}, capture)
+}
+
+// reconcileCaptureFrom returns the capture of the current reconcile, or nil when not capturing
+func reconcileCaptureFrom(ctx context.Context) *ReconcileCapture {
+	capture, _ := ctx.Value(reconcileCaptureKey{}).(*ReconcileCapture)
+	return capture
+}
+
+// DefaultReconcileCaptureMaxFiles is the number of captures kept in the capture directory
+// unless configured otherwise
+const DefaultReconcileCaptureMaxFiles = 1000
+
+// ReconcileCapturer records reconciles as ReconcileCapture artifacts. Adapter results are
+// only recorded when the ControllerEngine uses a registry wrapped by NewCapturingRegistry.
+type ReconcileCapturer struct {
+	// Dir receives one JSON file per reconcile
+	Dir string
+
+	// MaxFiles caps the captures kept in Dir; the oldest are removed as new ones are
+	// written. Unlimited when 0.
+	MaxFiles int
+
+	// sink, when set, receives the captures instead of Dir
+	sink func(*ReconcileCapture)
+
+	mu sync.Mutex
+	// written are the captures in Dir, oldest first, listed from Dir on the first write
+	written []string
+	listed  bool
+}
+
+// NewReconcileCapturer creates a capturer writing artifacts to dir, keeping the latest
+// DefaultReconcileCaptureMaxFiles of them
+func NewReconcileCapturer(dir string) *ReconcileCapturer {
+	return &ReconcileCapturer{Dir: dir, MaxFiles: DefaultReconcileCaptureMaxFiles}
+}
+
+// start begins the capture of a reconcile of uvr
+func (rc *ReconcileCapturer) start(ctx context.Context, discoveryEngine *discovery.Engine, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) *ReconcileCapture {
+	capture := &ReconcileCapture{
+		Version:    ReconcileCaptureVersion,
+		CapturedAt: metav1.Now(),
+		UVR:        uvr.DeepCopy(),
+	}
+	if discoveryEngine != nil {
+		if result, err := discoveryEngine.DiscoverBackends(ctx); err != nil {
+			log.Error(err, "Failed to capture discovered backends")
+		} else {
+			capture.DiscoveredBackends = result.AvailableBackends
+		}
+	}
+	return capture
+}
+
+// finish records the decision and stores the capture
+func (rc *ReconcileCapturer) finish(capture *ReconcileCapture, uvr *replicationv1alpha1.UnifiedVolumeReplication, result ctrl.Result, err error, log logr.Logger) {
+	capture.Decision = newReconcileDecision(uvr, result, err)
+
+	if rc.sink != nil {
+		rc.sink(capture)
+		return
+	}
+
+	path, writeErr := rc.write(capture)
+	if writeErr != nil {
+		log.Error(writeErr, "Failed to write reconcile capture")
+		return
+	}
+	log.V(1).Info("Captured reconcile", "path", path)
+}
+
+// write stores a capture as JSON under Dir and returns its path
+func (rc *ReconcileCapturer) write(capture *ReconcileCapture) (string, error) {
+	if err := os.MkdirAll(rc.Dir, 0o750); err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(capture, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("%s_%s_%d.json", capture.UVR.Namespace, capture.UVR.Name, capture.CapturedAt.UnixNano())
+	path := filepath.Join(rc.Dir, name)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if !rc.listed {
+		existing, err := listReconcileCaptures(rc.Dir)
+		if err != nil {
+			return "", err
+		}
+		rc.written = existing
+		rc.listed = true
+	}
+
+	if err := os.WriteFile(path, data, 0o640); err != nil {
+		return "", err
+	}
+	rc.written = append(rc.written, path)
+	return path, rc.pruneLocked()
+}
+
+// pruneLocked removes the oldest captures beyond MaxFiles; callers must hold the lock
+func (rc *ReconcileCapturer) pruneLocked() error {
+	if rc.MaxFiles <= 0 {
+		return nil
+	}
+	for len(rc.written) > rc.MaxFiles {
+		if err := os.Remove(rc.written[0]); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		rc.written = rc.written[1:]
+	}
+	return nil
+}
+
+// listReconcileCaptures returns the captures already in dir, oldest first, so captures left
+// by a previous run count against the cap
+func listReconcileCaptures(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	type captureFile struct {
+		path    string
+		modTime time.Time
+	}
+	var files []captureFile
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, captureFile{path: filepath.Join(dir, entry.Name()), modTime: info.ModTime()})
+	}
+	sort.SliceStable(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, file.path)
+	}
+	return paths, nil
+}
+
+// LoadReconcileCapture reads a capture artifact
+func LoadReconcileCapture(path string) (*ReconcileCapture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	capture := &ReconcileCapture{}
+	if err := json.Unmarshal(data, capture); err != nil {
+		return nil, fmt.Errorf("invalid reconcile capture %s: %w", path, err)
+	}
+	if capture.Version != ReconcileCaptureVersion {
+		return nil, fmt.Errorf("unsupported reconcile capture version %d in %s", capture.Version, path)
+	}
+	if capture.UVR == nil {
+		return nil, fmt.Errorf("reconcile capture %s has no UnifiedVolumeReplication", path)
+	}
+	return capture, nil
+}
+
+// NewCapturingRegistry wraps a registry so the results of adapter operations are recorded
+// in the capture of the current reconcile
+func NewCapturingRegistry(registry adapters.Registry) adapters.Registry {
+	return &capturingRegistry{Registry: registry}
+}
+
+type capturingRegistry struct {
+	adapters.Registry
+}
+
+func (r *capturingRegistry) GetFactory(backend translation.Backend) (adapters.AdapterFactory, error) {
+	factory, err := r.Registry.GetFactory(backend)
+	if err != nil {
+		return nil, err
+	}
+	return &capturingFactory{AdapterFactory: factory}, nil
+}
+
+func (r *capturingRegistry) CreateAdapter(backend translation.Backend, c client.Client, translator *translation.Engine, config *adapters.AdapterConfig) (adapters.ReplicationAdapter, error) {
+	adapter, err := r.Registry.CreateAdapter(backend, c, translator, config)
+	if err != nil {
+		return nil, err
+	}
+	return &capturingAdapter{ReplicationAdapter: adapter}, nil
+}
+
+type capturingFactory struct {
+	adapters.AdapterFactory
+}
+
+func (f *capturingFactory) CreateAdapter(backend translation.Backend, c client.Client, translator *translation.Engine, config *adapters.AdapterConfig) (adapters.ReplicationAdapter, error) {
+	adapter, err := f.AdapterFactory.CreateAdapter(backend, c, translator, config)
+	if err != nil {
+		return nil, err
+	}
+	return &capturingAdapter{ReplicationAdapter: adapter}, nil
+}
+
+// capturingAdapter records the results of the operations a reconcile depends on
+type capturingAdapter struct {
+	adapters.ReplicationAdapter
+}
+
+func (a *capturingAdapter) EnsureReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	err := a.ReplicationAdapter.EnsureReplication(ctx, uvr)
+	reconcileCaptureFrom(ctx).recordAdapterCall(AdapterCall{
+		Backend:   a.GetBackendType(),
+		Operation: "ensure",
+		Error:     captureError(err),
+	})
+	return err
+}
+
+func (a *capturingAdapter) GetReplicationStatus(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (*adapters.ReplicationStatus, error) {
+	status, err := a.ReplicationAdapter.GetReplicationStatus(ctx, uvr)
+	call := AdapterCall{
+		Backend:   a.GetBackendType(),
+		Operation: "status",
+		Error:     captureError(err),
+	}
+	if status != nil {
+		// The engine translates the returned status in place, keep the backend view
+		captured := *status
+		call.Status = &captured
+	}
+	reconcileCaptureFrom(ctx).recordAdapterCall(call)
+	return status, err
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+// ReconcileOrder selects which queued UVRs are reconciled first. It matters most after a
+// restart, when every existing UVR is queued at once.
+type ReconcileOrder string
+
+const (
+	// ReconcileOrderFIFO reconciles UVRs in the order they were queued
+	ReconcileOrderFIFO ReconcileOrder = "fifo"
+	// ReconcileOrderAge reconciles the oldest UVRs first
+	ReconcileOrderAge ReconcileOrder = "age"
+	// ReconcileOrderPriority reconciles UVRs with the highest spec.priority first, oldest first
+	// among equal priorities
+	ReconcileOrderPriority ReconcileOrder = "priority"
+)
+
+// ParseReconcileOrder validates a reconcile order name, defaulting to FIFO when empty
+func ParseReconcileOrder(value string) (ReconcileOrder, error) {
+	switch order := ReconcileOrder(value); order {
+	case "":
+		return ReconcileOrderFIFO, nil
+	case ReconcileOrderFIFO, ReconcileOrderAge, ReconcileOrderPriority:
+		return order, nil
+	default:
+		return "", fmt.Errorf("unknown reconcile order %q, must be one of: %s, %s, %s",
+			value, ReconcileOrderFIFO, ReconcileOrderAge, ReconcileOrderPriority)
+	}
+}
+
+// usesPriorityQueue reports whether the order needs the controller's priority queue
+func (o ReconcileOrder) usesPriorityQueue() bool {
+	return o == ReconcileOrderAge || o == ReconcileOrderPriority
+}
+
+// queuePriority maps a UVR to its workqueue priority, higher first
+func (o ReconcileOrder) queuePriority(uvr *replicationv1alpha1.UnifiedVolumeReplication) int {
+	return int(o.rank(uvr.Spec.Priority, uvr.CreationTimestamp.Unix(), strconv.IntSize))
+}
+
+// rank computes the queue priority in int64 and fits it into an int of intSize bits. The low
+// bits rank by age so older UVRs win within the same spec.priority, which occupies the high
+// bits. Where int has 32 bits the age is coarsened to 2048s steps so spec.priority, at most
+// 1000, still fits above it.
+func (o ReconcileOrder) rank(priority int32, created int64, intSize int) int64 {
+	if created < 0 {
+		created = 0
+	}
+	if created > math.MaxUint32 {
+		created = math.MaxUint32
+	}
+	age := int64(math.MaxUint32) - created
+
+	if intSize == 32 {
+		if o == ReconcileOrderPriority {
+			return int64(priority)<<21 | age>>11
+		}
+		return age >> 1
+	}
+	if o == ReconcileOrderPriority {
+		return int64(priority)<<32 | age
+	}
+	return age
+}
+
+// replicationHandler enqueues UVRs with the priority of the configured order, and delays
+// requests for spec edits by the coalescing window. It replaces the default handler for the
+// UVR watch, so queued requests keep their priority across requeues.
+type replicationHandler struct {
+	order          ReconcileOrder
+	coalesceWindow time.Duration
+	log            logr.Logger
+}
+
+var _ handler.EventHandler = &replicationHandler{}
+
+func (h *replicationHandler) Create(_ context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	priority := h.enqueue(e.Object, 0, q)
+	if e.IsInInitialList {
+		h.log.V(1).Info("Queued for initial reconcile", "unifiedvolumereplication", client.ObjectKeyFromObject(e.Object),
+			"order", h.order, "priority", priority)
+	}
+}
+
+func (h *replicationHandler) Update(_ context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.enqueue(e.ObjectNew, h.coalesceWindow, q)
+}
+
+func (h *replicationHandler) Delete(_ context.Context, e event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.enqueue(e.Object, 0, q)
+}
+
+func (h *replicationHandler) Generic(_ context.Context, e event.GenericEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.enqueue(e.Object, 0, q)
+}
+
+// enqueue adds a reconcile request for obj, ready after delay, and returns the priority it
+// was queued with
+func (h *replicationHandler) enqueue(obj client.Object, delay time.Duration, q workqueue.TypedRateLimitingInterface[reconcile.Request]) int {
+	request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)}
+	uvr, ok := obj.(*replicationv1alpha1.UnifiedVolumeReplication)
+	pq, isPriorityQueue := q.(priorityqueue.PriorityQueue[reconcile.Request])
+	if !ok || !isPriorityQueue {
+		if delay > 0 {
+			q.AddAfter(request, delay)
+		} else {
+			q.Add(request)
+		}
+		return 0
+	}
+
+	priority := h.order.queuePriority(uvr)
+	pq.AddWithOpts(priorityqueue.AddOpts{Priority: ptr.To(priority), After: delay}, request)
+	return priority
+}
+
+// replicationControllerName is the name of the UVR controller when backends are not isolated
+const replicationControllerName = "unifiedvolumereplication"
+
+// watchReplications registers the UVR watch of a controller. With an ordered reconcile order
+// or a spec coalescing window, UVRs are enqueued through replicationHandler instead of the
+// default handler.
+func (r *UnifiedVolumeReplicationReconciler) watchReplications(b *builder.Builder, name string, predicates ...predicate.Predicate) *builder.Builder {
+	b = b.Named(name)
+	predicates = append(predicates, predicate.Or(predicate.GenerationChangedPredicate{}, cancelRequestedPredicate()))
+	if len(r.WatchNamespaces) > 0 {
+		predicates = append(predicates, watchNamespacesPredicate(r.WatchNamespaces))
+	}
+	order := r.getReconcileOrder()
+	window := r.getSpecCoalesceWindow()
+	if r.FleetSweep != nil {
+		b = r.FleetSweep.watch(b, &replicationHandler{order: order, log: r.Log.WithName(name)}, predicates...)
+	}
+	if r.translationReload != nil {
+		b = r.translationReload.watch(b, &replicationHandler{order: order, log: r.Log.WithName(name)}, predicates...)
+	}
+	if !order.usesPriorityQueue() && window == 0 {
+		return b.For(&replicationv1alpha1.UnifiedVolumeReplication{}, builder.WithPredicates(predicates...))
+	}
+	return b.Watches(&replicationv1alpha1.UnifiedVolumeReplication{},
+		&replicationHandler{order: order, coalesceWindow: window, log: r.Log.WithName(name)},
+		builder.WithPredicates(predicates...))
+}
+
+// controllerOptions returns the controller options, enabling the priority queue when the
+// reconcile order needs it
+func (r *UnifiedVolumeReplicationReconciler) controllerOptions(maxConcurrentReconciles int) controller.Options {
+	options := controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}
+	if r.getReconcileOrder().usesPriorityQueue() {
+		options.UsePriorityQueue = ptr.To(true)
+	}
+	return options
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestParseReconcileOrder(t *testing.T) {
+	order, err := ParseReconcileOrder("")
+	require.NoError(t, err)
+	assert.Equal(t, ReconcileOrderFIFO, order)
+
+	for _, value := range []string{"fifo", "age", "priority"} {
+		order, err := ParseReconcileOrder(value)
+		require.NoError(t, err)
+		assert.Equal(t, ReconcileOrder(value), order)
+	}
+
+	_, err = ParseReconcileOrder("random")
+	assert.Error(t, err)
+}
+
+func TestReconcileOrder_RankFitsInt(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	older := created - 3600
+
+	for _, intSize := range []int{32, 64} {
+		maxInt := int64(math.MaxInt64)
+		if intSize == 32 {
+			maxInt = math.MaxInt32
+		}
+		for _, order := range []ReconcileOrder{ReconcileOrderAge, ReconcileOrderPriority} {
+			top := order.rank(1000, 0, intSize)
+			assert.LessOrEqual(t, top, maxInt, "%s order with %d-bit int", order, intSize)
+			assert.GreaterOrEqual(t, order.rank(0, math.MaxInt64, intSize), int64(0))
+			assert.Greater(t, order.rank(0, older, intSize), order.rank(0, created, intSize), "older first")
+		}
+		assert.Greater(t, ReconcileOrderPriority.rank(1, created, intSize), ReconcileOrderPriority.rank(0, older, intSize),
+			"higher priority first")
+	}
+}
+
+func TestReplicationHandler_StartupSurge(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	uvrs := []struct {
+		name     string
+		priority int32
+		age      time.Duration
+	}{
+		// Listed in the order the informer happens to deliver them
+		{"bulk-new", 0, time.Hour},
+		{"critical-new", 100, 2 * time.Hour},
+		{"bulk-old", 0, 48 * time.Hour},
+		{"important", 10, 3 * time.Hour},
+		{"critical-old", 100, 24 * time.Hour},
+	}
+
+	startupOrder := func(t *testing.T, order ReconcileOrder) []string {
+		q := priorityqueue.New[reconcile.Request]("test-" + string(order))
+		defer q.ShutDown()
+
+		h := &replicationHandler{order: order, log: ctrl.Log.WithName("test")}
+		for _, u := range uvrs {
+			uvr := createTestUVR(u.name, "default")
+			uvr.Spec.Priority = u.priority
+			uvr.CreationTimestamp = metav1.NewTime(base.Add(-u.age))
+			h.Create(context.Background(), event.CreateEvent{Object: uvr, IsInInitialList: true}, q)
+		}
+
+		var names []string
+		for range uvrs {
+			request, _ := q.Get()
+			names = append(names, request.Name)
+			q.Done(request)
+		}
+		return names
+	}
+
+	t.Run("Priority", func(t *testing.T) {
+		assert.Equal(t, []string{"critical-old", "critical-new", "important", "bulk-old", "bulk-new"},
+			startupOrder(t, ReconcileOrderPriority))
+	})
+
+	t.Run("Age", func(t *testing.T) {
+		assert.Equal(t, []string{"bulk-old", "critical-old", "important", "critical-new", "bulk-new"},
+			startupOrder(t, ReconcileOrderAge))
+	})
+}
+
+func TestReplicationHandler_CoalescesSpecEdits(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+
+	uvr := createTestUVR("test-coalesce", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	uvr.Spec.Schedule = replicationv1alpha1.Schedule{Mode: replicationv1alpha1.ScheduleModeInterval, Rpo: "15m"}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(tridentCRDs(""), uvr)...).
+		WithStatusSubresource(uvr).Build()
+
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewTridentAdapterFactory()))
+	reconciler := createTestReconciler(c, s)
+	reconciler.AdapterRegistry = registry
+	reconciler.ControllerEngine = pkg.NewControllerEngine(c, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+		registry, pkg.DefaultControllerEngineConfig())
+
+	window := 200 * time.Millisecond
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+	h := &replicationHandler{order: ReconcileOrderFIFO, coalesceWindow: window, log: ctrl.Log.WithName("test")}
+
+	// A script edits the RPO three times in quick succession
+	key := client.ObjectKeyFromObject(uvr)
+	for _, rpo := range []string{"10m", "5m", "1h"} {
+		old := &replicationv1alpha1.UnifiedVolumeReplication{}
+		require.NoError(t, c.Get(ctx, key, old))
+		edited := old.DeepCopy()
+		edited.Spec.Schedule.Rpo = rpo
+		edited.Generation = old.Generation + 1
+		require.NoError(t, c.Update(ctx, edited))
+		h.Update(ctx, event.UpdateEvent{ObjectOld: old, ObjectNew: edited}, q)
+	}
+	assert.Zero(t, q.Len(), "nothing is reconciled within the window")
+
+	require.Eventually(t, func() bool { return q.Len() == 1 }, 5*time.Second, 10*time.Millisecond)
+	reconciles := 0
+	for q.Len() > 0 {
+		req, _ := q.Get()
+		_, err := reconciler.Reconcile(ctx, req)
+		require.NoError(t, err)
+		q.Done(req)
+		reconciles++
+	}
+	time.Sleep(2 * window)
+	assert.Zero(t, q.Len(), "the edits were not queued again")
+	assert.Equal(t, 1, reconciles)
+
+	// The single reconcile applied the last edit
+	tmr := &unstructured.Unstructured{}
+	tmr.SetGroupVersionKind(adapters.TridentMirrorRelationshipGVK)
+	require.NoError(t, c.Get(ctx, key, tmr))
+	schedule, _, _ := unstructured.NestedString(tmr.Object, "spec", "replicationSchedule")
+	assert.Equal(t, "1h", schedule)
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg"
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/discovery"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+// ReplayReconcile runs the reconcile recorded in capture offline and returns the decision it
+// makes. The UVR and the CRDs of the discovered backends are served by a fake client, and
+// adapters return the captured results in order. Adapter operations that are not captured
+// (pause, resume, drift detection, ...) succeed without effect.
+func ReplayReconcile(ctx context.Context, capture *ReconcileCapture, log logr.Logger) (ReconcileDecision, error) {
+	scheme := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{
+		clientgoscheme.AddToScheme,
+		apiextensionsv1.AddToScheme,
+		replicationv1alpha1.AddToScheme,
+	} {
+		if err := addToScheme(scheme); err != nil {
+			return ReconcileDecision{}, err
+		}
+	}
+
+	uvr := capture.UVR.DeepCopy()
+	uvr.ResourceVersion = ""
+	objects := []client.Object{uvr}
+	for _, backend := range capture.DiscoveredBackends {
+		for _, definition := range discovery.BackendCRDMap[backend] {
+			objects = append(objects, replayCRD(definition))
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).WithStatusSubresource(uvr).
+		WithIndex(&replicationv1alpha1.UnifiedVolumeReplication{}, sourceVolumeIndexField, indexSourceVolumes).Build()
+
+	calls := &replayCalls{pending: append([]AdapterCall(nil), capture.AdapterCalls...)}
+	registry := adapters.NewRegistry()
+	for backend := range discovery.BackendCRDMap {
+		factory := &replayAdapterFactory{
+			BaseAdapterFactory: adapters.NewBaseAdapterFactory(backend, "Replay Adapter", "1.0.0-replay", "Returns captured adapter results"),
+			calls:              calls,
+		}
+		if err := registry.RegisterFactory(factory); err != nil {
+			return ReconcileDecision{}, err
+		}
+	}
+
+	translationEngine := translation.NewEngine()
+	discoveryEngine := discovery.NewEngine(c, discovery.DefaultDiscoveryConfig())
+	var decision ReconcileDecision
+	reconciler := &UnifiedVolumeReplicationReconciler{
+		Client:            c,
+		Log:               log,
+		Scheme:            scheme,
+		Recorder:          record.NewFakeRecorder(100),
+		AdapterRegistry:   registry,
+		DiscoveryEngine:   discoveryEngine,
+		TranslationEngine: translationEngine,
+		ControllerEngine:  pkg.NewControllerEngine(c, discoveryEngine, translationEngine, registry, pkg.DefaultControllerEngineConfig()),
+		StateMachine:      NewStateMachine(),
+		RetryManager:      NewRetryManager(nil),
+		CircuitBreakers:   NewBackendCircuitBreakers(5, 2, time.Minute),
+		Capturer: &ReconcileCapturer{sink: func(replayed *ReconcileCapture) {
+			decision = replayed.Decision
+		}},
+	}
+
+	if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}); err != nil {
+		log.V(1).Info("Replayed reconcile returned an error", "error", err.Error())
+	}
+	if remaining := calls.remaining(); remaining > 0 {
+		log.Info("Replay did not use all captured adapter calls", "remaining", remaining)
+	}
+	return decision, nil
+}
+
+// replayCRD returns an established CRD for a backend CRD definition
+func replayCRD(definition discovery.CRDDefinition) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: definition.Name},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: definition.Group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: definition.Kind},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: definition.Version, Served: true, Storage: true},
+			},
+		},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{
+			Conditions: []apiextensionsv1.CustomResourceDefinitionCondition{
+				{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue},
+			},
+		},
+	}
+}
+
+// replayCalls hands out captured adapter results in the order they were recorded
+type replayCalls struct {
+	mu      sync.Mutex
+	pending []AdapterCall
+}
+
+// next returns the first pending result of an operation on a backend
+func (rc *replayCalls) next(backend translation.Backend, operation string) (AdapterCall, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for i, call := range rc.pending {
+		if call.Backend == backend && call.Operation == operation {
+			rc.pending = append(rc.pending[:i], rc.pending[i+1:]...)
+			return call, nil
+		}
+	}
+	return AdapterCall{}, fmt.Errorf("replay: no captured %s result for backend %s", operation, backend)
+}
+
+// remaining returns the number of captured results not used by the replay
+func (rc *replayCalls) remaining() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return len(rc.pending)
+}
+
+type replayAdapterFactory struct {
+	*adapters.BaseAdapterFactory
+	calls *replayCalls
+}
+
+func (f *replayAdapterFactory) CreateAdapter(backend translation.Backend, c client.Client, translator *translation.Engine, config *adapters.AdapterConfig) (adapters.ReplicationAdapter, error) {
+	return &replayAdapter{
+		ReplicationAdapter: adapters.NewMockAdapter(backend, c, translator, config, &adapters.MockConfig{}),
+		calls:              f.calls,
+	}, nil
+}
+
+// replayAdapter returns captured results for the operations recorded by capturingAdapter
+type replayAdapter struct {
+	adapters.ReplicationAdapter
+	calls *replayCalls
+}
+
+func (a *replayAdapter) EnsureReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	call, err := a.calls.next(a.GetBackendType(), "ensure")
+	if err != nil {
+		return err
+	}
+	return call.Error.Err()
+}
+
+func (a *replayAdapter) GetReplicationStatus(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (*adapters.ReplicationStatus, error) {
+	call, err := a.calls.next(a.GetBackendType(), "status")
+	if err != nil {
+		return nil, err
+	}
+	if call.Error != nil {
+		return nil, call.Error.Err()
+	}
+	return call.Status, nil
+}
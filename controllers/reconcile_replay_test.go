@@ -0,0 +1,137 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/unified-replication/operator/pkg"
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+func TestReconcileCaptureReplay(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+
+	uvr := createTestUVR("test-replay", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(tridentCRDs(""), uvr)...).
+		WithStatusSubresource(uvr).Build()
+
+	dir := t.TempDir()
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewTridentAdapterFactory()))
+	reconciler := createTestReconciler(c, s)
+	reconciler.AdapterRegistry = registry
+	reconciler.ControllerEngine = pkg.NewControllerEngine(c, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+		NewCapturingRegistry(registry), pkg.DefaultControllerEngineConfig())
+	reconciler.Capturer = NewReconcileCapturer(dir)
+
+	result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)})
+	require.NoError(t, err)
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	capture, err := LoadReconcileCapture(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	assert.Equal(t, "test-replay", capture.UVR.Name)
+	assert.Contains(t, capture.DiscoveredBackends, translation.BackendTrident)
+	require.NotEmpty(t, capture.AdapterCalls, "adapter results should be captured")
+	assert.Equal(t, translation.BackendTrident, capture.AdapterCalls[0].Backend)
+	assert.Equal(t, result.RequeueAfter.String(), capture.Decision.RequeueAfter)
+	assert.NotEmpty(t, capture.Decision.Conditions)
+
+	decision, err := ReplayReconcile(ctx, capture, ctrl.Log.WithName("test").WithName("replay"))
+	require.NoError(t, err)
+	assert.Empty(t, capture.Decision.Diff(decision))
+	assert.Equal(t, capture.Decision, decision)
+
+	t.Run("DivergentAdapterResult", func(t *testing.T) {
+		altered := &ReconcileCapture{
+			Version:            capture.Version,
+			UVR:                capture.UVR,
+			DiscoveredBackends: capture.DiscoveredBackends,
+		}
+		for _, call := range capture.AdapterCalls {
+			if call.Operation == "ensure" {
+				call.Error = &CapturedError{Message: "ensure replication failed: backend unavailable"}
+			}
+			altered.AdapterCalls = append(altered.AdapterCalls, call)
+		}
+
+		decision, err := ReplayReconcile(ctx, altered, ctrl.Log.WithName("test").WithName("replay"))
+		require.NoError(t, err)
+		assert.NotEmpty(t, capture.Decision.Diff(decision))
+	})
+
+	t.Run("UnsupportedVersion", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "capture.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"version": 99}`), 0o600))
+		_, err := LoadReconcileCapture(path)
+		assert.Error(t, err)
+	})
+}
+
+func TestReconcileCapturer_MaxFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	// A capture left by a previous run counts against the cap and is removed first
+	leftover := filepath.Join(dir, "default_old_1.json")
+	require.NoError(t, os.WriteFile(leftover, []byte("{}"), 0o640))
+	past := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(leftover, past, past))
+
+	capturer := NewReconcileCapturer(dir)
+	capturer.MaxFiles = 3
+
+	var paths []string
+	for i := 0; i < 4; i++ {
+		capture := &ReconcileCapture{
+			Version:    ReconcileCaptureVersion,
+			CapturedAt: metav1.NewTime(time.Now().Add(time.Duration(i) * time.Second)),
+			UVR:        createTestUVR("test-capture-cap", "default"),
+		}
+		path, err := capturer.write(capture)
+		require.NoError(t, err)
+		paths = append(paths, path)
+	}
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 3)
+	assert.NoFileExists(t, leftover)
+	assert.NoFileExists(t, paths[0])
+	for _, path := range paths[1:] {
+		assert.FileExists(t, path)
+	}
+}
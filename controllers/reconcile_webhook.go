@@ -0,0 +1,218 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+const (
+	// defaultReconcileWebhookTimeout bounds a single delivery to the outcome webhook
+	defaultReconcileWebhookTimeout = 5 * time.Second
+
+	// reconcileWebhookAttempts is how often a delivery is tried before it is dropped
+	reconcileWebhookAttempts = 3
+
+	// reconcileWebhookRetryDelay is the delay before the first retry, doubled for each one
+	reconcileWebhookRetryDelay = time.Second
+
+	// reconcileWebhookQueueSize bounds the outcomes waiting for delivery; newer outcomes are
+	// dropped while it is full so reconciles never wait on the webhook
+	reconcileWebhookQueueSize = 1000
+)
+
+// ReconcileOutcome is posted to the outcome webhook after each reconcile
+type ReconcileOutcome struct {
+	Name       string                              `json:"name"`
+	Namespace  string                              `json:"namespace"`
+	UID        types.UID                           `json:"uid"`
+	Generation int64                               `json:"generation"`
+	Result     replicationv1alpha1.ReconcileResult `json:"result"`
+	Message    string                              `json:"message,omitempty"`
+	Error      string                              `json:"error,omitempty"`
+	// RequeueAfter is when the UVR is reconciled again, empty when it is not requeued
+	RequeueAfter string             `json:"requeueAfter,omitempty"`
+	Conditions   []metav1.Condition `json:"conditions,omitempty"`
+	Time         metav1.Time        `json:"time"`
+}
+
+// ReconcileOutcomeWebhook posts a summary of every reconcile to an HTTP endpoint, for
+// integration pipelines that track convergence. Deliveries happen in the background and are
+// retried, so a slow or failing endpoint never delays reconciliation.
+type ReconcileOutcomeWebhook struct {
+	URL string
+
+	// Results limits deliveries to these reconcile results; all results are delivered when empty
+	Results []replicationv1alpha1.ReconcileResult
+
+	client     *http.Client
+	queue      chan ReconcileOutcome
+	retryDelay time.Duration
+	log        logr.Logger
+}
+
+// NewReconcileOutcomeWebhook creates a webhook client for the given endpoint. Outcomes are
+// only delivered once it is started.
+func NewReconcileOutcomeWebhook(url string, timeout time.Duration, results []replicationv1alpha1.ReconcileResult) *ReconcileOutcomeWebhook {
+	if timeout <= 0 {
+		timeout = defaultReconcileWebhookTimeout
+	}
+
+	return &ReconcileOutcomeWebhook{
+		URL:        url,
+		Results:    results,
+		client:     &http.Client{Timeout: timeout},
+		queue:      make(chan ReconcileOutcome, reconcileWebhookQueueSize),
+		retryDelay: reconcileWebhookRetryDelay,
+		log:        ctrl.Log.WithName("reconcile-outcome-webhook"),
+	}
+}
+
+// ParseReconcileResults parses a comma-separated list of reconcile results
+func ParseReconcileResults(value string) ([]replicationv1alpha1.ReconcileResult, error) {
+	known := []replicationv1alpha1.ReconcileResult{
+		replicationv1alpha1.ReconcileResultSynced,
+		replicationv1alpha1.ReconcileResultProgressing,
+		replicationv1alpha1.ReconcileResultDegraded,
+	}
+
+	var results []replicationv1alpha1.ReconcileResult
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		result := replicationv1alpha1.ReconcileResult(item)
+		if !slices.Contains(known, result) {
+			return nil, fmt.Errorf("unknown reconcile result %q, expected Synced, Progressing or Degraded", item)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Notify queues the outcome of a reconcile for delivery, unless its result is filtered out.
+// It never blocks; outcomes are dropped while the queue is full.
+func (w *ReconcileOutcomeWebhook) Notify(uvr *replicationv1alpha1.UnifiedVolumeReplication, result ctrl.Result, reconcileErr error) {
+	outcome := ReconcileOutcome{
+		Name:       uvr.Name,
+		Namespace:  uvr.Namespace,
+		UID:        uvr.UID,
+		Generation: uvr.Generation,
+		Conditions: slices.Clone(uvr.Status.Conditions),
+		Time:       metav1.Now(),
+	}
+	if report := uvr.Status.LastReconcile; report != nil {
+		outcome.Result = report.Result
+		outcome.Message = report.Message
+	}
+	if reconcileErr != nil {
+		outcome.Result = replicationv1alpha1.ReconcileResultDegraded
+		outcome.Error = reconcileErr.Error()
+	}
+	if outcome.Result == "" {
+		// Reconciles that stopped before any report was recorded
+		outcome.Result = replicationv1alpha1.ReconcileResultProgressing
+	}
+	if result.RequeueAfter > 0 {
+		outcome.RequeueAfter = result.RequeueAfter.String()
+	}
+
+	if len(w.Results) > 0 && !slices.Contains(w.Results, outcome.Result) {
+		return
+	}
+
+	select {
+	case w.queue <- outcome:
+	default:
+		w.log.Info("Outcome queue full, dropping reconcile outcome", "uvr", types.NamespacedName{Namespace: uvr.Namespace, Name: uvr.Name})
+	}
+}
+
+// Start delivers queued outcomes until the context is cancelled. It implements
+// manager.Runnable so the manager runs it alongside the controllers.
+func (w *ReconcileOutcomeWebhook) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case outcome := <-w.queue:
+			w.deliver(ctx, outcome)
+		}
+	}
+}
+
+// deliver posts an outcome, retrying with backoff before giving up
+func (w *ReconcileOutcomeWebhook) deliver(ctx context.Context, outcome ReconcileOutcome) {
+	body, err := json.Marshal(outcome)
+	if err != nil {
+		w.log.Error(err, "Failed to encode reconcile outcome")
+		return
+	}
+
+	delay := w.retryDelay
+	for attempt := 1; ; attempt++ {
+		err = w.post(ctx, body)
+		if err == nil {
+			return
+		}
+		if attempt == reconcileWebhookAttempts {
+			w.log.Error(err, "Dropping reconcile outcome", "uvr", types.NamespacedName{Namespace: outcome.Namespace, Name: outcome.Name}, "attempts", attempt)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// post sends one delivery attempt
+func (w *ReconcileOutcomeWebhook) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build outcome request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("outcome webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("outcome webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// newOutcomeSink returns a server that fails the first failures deliveries and passes the
+// rest to the returned channel
+func newOutcomeSink(t *testing.T, failures int32) (*httptest.Server, <-chan ReconcileOutcome) {
+	outcomes := make(chan ReconcileOutcome, 10)
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		outcome := ReconcileOutcome{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&outcome))
+		outcomes <- outcome
+	}))
+	t.Cleanup(server.Close)
+	return server, outcomes
+}
+
+func TestReconcileOutcomeWebhook(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+
+	uvr := createTestUVR("test-outcome-webhook", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(tridentCRDs(""), uvr)...).
+		WithStatusSubresource(uvr).Build()
+
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewTridentAdapterFactory()))
+	reconciler := createTestReconciler(c, s)
+	reconciler.AdapterRegistry = registry
+	reconciler.ControllerEngine = pkg.NewControllerEngine(c, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+		registry, pkg.DefaultControllerEngineConfig())
+
+	// The sink is unavailable at first, so the outcome is only delivered on a retry
+	server, outcomes := newOutcomeSink(t, 1)
+	webhook := NewReconcileOutcomeWebhook(server.URL, time.Second, nil)
+	webhook.retryDelay = 10 * time.Millisecond
+	reconciler.ReconcileOutcomeWebhook = webhook
+	go func() { _ = webhook.Start(ctx) }()
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+	result, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	select {
+	case outcome := <-outcomes:
+		assert.Equal(t, "test-outcome-webhook", outcome.Name)
+		assert.Equal(t, "default", outcome.Namespace)
+		assert.Equal(t, uvr.Generation, outcome.Generation)
+		assert.Equal(t, replicationv1alpha1.ReconcileResultProgressing, outcome.Result)
+		assert.Equal(t, result.RequeueAfter.String(), outcome.RequeueAfter)
+		assert.Empty(t, outcome.Error)
+
+		var types []string
+		for _, condition := range outcome.Conditions {
+			types = append(types, condition.Type)
+		}
+		assert.Contains(t, types, "Ready")
+	case <-time.After(5 * time.Second):
+		t.Fatal("reconcile outcome was not delivered")
+	}
+}
+
+func TestReconcileOutcomeWebhook_FiltersResults(t *testing.T) {
+	webhook := NewReconcileOutcomeWebhook("http://127.0.0.1:0", time.Second,
+		[]replicationv1alpha1.ReconcileResult{replicationv1alpha1.ReconcileResultDegraded})
+
+	uvr := createTestUVR("test-outcome-filter", "default")
+	uvr.Status.LastReconcile = &replicationv1alpha1.LastReconcile{Result: replicationv1alpha1.ReconcileResultSynced}
+	webhook.Notify(uvr, ctrl.Result{}, nil)
+	assert.Empty(t, webhook.queue, "synced reconciles are filtered out")
+
+	webhook.Notify(uvr, ctrl.Result{}, errors.New("backend unavailable"))
+	require.Len(t, webhook.queue, 1)
+	outcome := <-webhook.queue
+	assert.Equal(t, replicationv1alpha1.ReconcileResultDegraded, outcome.Result)
+	assert.Equal(t, "backend unavailable", outcome.Error)
+
+	results, err := ParseReconcileResults("Synced, Degraded")
+	require.NoError(t, err)
+	assert.Equal(t, []replicationv1alpha1.ReconcileResult{replicationv1alpha1.ReconcileResultSynced, replicationv1alpha1.ReconcileResultDegraded}, results)
+	_, err = ParseReconcileResults("Failed")
+	assert.Error(t, err)
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+const (
+	// recoveryObjectivesCondition is True while the RTO, RPO and replication mode of the spec
+	// contradict each other. It is informational; the replication is reconciled regardless.
+	recoveryObjectivesCondition = "RecoveryObjectivesInconsistent"
+
+	// recoveryObjectivesUnachievable marks objectives that cannot be met in any deployment
+	recoveryObjectivesUnachievable = "Unachievable"
+	// recoveryObjectivesRisky marks objectives that are unlikely to be met in practice
+	recoveryObjectivesRisky = "Risky"
+
+	// riskyRPOToRTORatio is how many times longer than the RTO an asynchronous RPO may be before
+	// the pair is flagged. A somewhat shorter RTO is common; one that is a fraction of the data
+	// loss window usually leaves no time to recover or reconcile the lost writes.
+	riskyRPOToRTORatio = 4
+)
+
+// evaluateRecoveryObjectives returns the reason and explanation when the spec's RTO, RPO and
+// mode are inconsistent, and an empty reason when they are not
+func evaluateRecoveryObjectives(uvr *replicationv1alpha1.UnifiedVolumeReplication) (string, string) {
+	async := uvr.Spec.ReplicationMode == replicationv1alpha1.ReplicationModeAsynchronous
+	rpo, rpoErr := parseScheduleDuration(uvr.Spec.Schedule.Rpo)
+	rto, rtoErr := parseScheduleDuration(uvr.Spec.Schedule.Rto)
+	hasRPO := uvr.Spec.Schedule.Rpo != "" && rpoErr == nil
+	hasRTO := uvr.Spec.Schedule.Rto != "" && rtoErr == nil
+
+	var unachievable, risky []string
+	if hasRTO && rto == 0 {
+		unachievable = append(unachievable, "an RTO of 0s cannot be met, failing over always takes some time")
+	}
+	if async && hasRPO && rpo == 0 {
+		unachievable = append(unachievable,
+			"an RPO of 0s cannot be met with asynchronous replication, which always lags the source; use synchronous mode")
+	}
+	if async && hasRPO && hasRTO && rto > 0 && rpo > riskyRPOToRTORatio*rto {
+		risky = append(risky, fmt.Sprintf(
+			"RTO %s is far shorter than RPO %s: an asynchronous replica may be up to %s behind, and recovering the lost writes is likely to take longer than the RTO allows",
+			uvr.Spec.Schedule.Rto, uvr.Spec.Schedule.Rpo, uvr.Spec.Schedule.Rpo))
+	}
+
+	switch {
+	case len(unachievable) > 0:
+		return recoveryObjectivesUnachievable, strings.Join(append(unachievable, risky...), "; ")
+	case len(risky) > 0:
+		return recoveryObjectivesRisky, strings.Join(risky, "; ")
+	default:
+		return "", ""
+	}
+}
+
+// checkRecoveryObjectives reports RTO, RPO and mode combinations that cannot or are unlikely
+// to be met through the RecoveryObjectivesInconsistent condition, with a warning event when
+// they become inconsistent. It never blocks the reconcile.
+func (r *UnifiedVolumeReplicationReconciler) checkRecoveryObjectives(uvr *replicationv1alpha1.UnifiedVolumeReplication) {
+	existing := r.getCondition(uvr, recoveryObjectivesCondition)
+	reason, message := evaluateRecoveryObjectives(uvr)
+	if reason == "" {
+		if existing == nil && uvr.Spec.Schedule.Rto == "" {
+			return
+		}
+		r.updateCondition(uvr, metav1.Condition{
+			Type:               recoveryObjectivesCondition,
+			Status:             metav1.ConditionFalse,
+			Reason:             "Consistent",
+			Message:            "RTO, RPO and replication mode are consistent",
+			ObservedGeneration: uvr.Generation,
+		})
+		return
+	}
+
+	if existing == nil || existing.Status != metav1.ConditionTrue || existing.Reason != reason {
+		r.recordEventf(uvr, corev1.EventTypeWarning, recoveryObjectivesCondition, "%s", message)
+	}
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               recoveryObjectivesCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+}
+
+// recordRecoveryObjectives records the RPO, RTO and max lag of the spec in status, each as
+// written and in canonical form. Values that fail to parse are left out; spec validation
+// reports them.
+func (r *UnifiedVolumeReplicationReconciler) recordRecoveryObjectives(uvr *replicationv1alpha1.UnifiedVolumeReplication) {
+	normalize := func(value string) *replicationv1alpha1.NormalizedDuration {
+		normalized, err := replicationv1alpha1.NormalizeScheduleDuration(value)
+		if value == "" || err != nil {
+			return nil
+		}
+		return &replicationv1alpha1.NormalizedDuration{Raw: value, Normalized: normalized}
+	}
+
+	objectives := &replicationv1alpha1.RecoveryObjectives{
+		Rpo:    normalize(uvr.Spec.Schedule.Rpo),
+		Rto:    normalize(uvr.Spec.Schedule.Rto),
+		MaxLag: normalize(uvr.Spec.Schedule.MaxLag),
+	}
+	if objectives.Rpo == nil && objectives.Rto == nil && objectives.MaxLag == nil {
+		objectives = nil
+	}
+	uvr.Status.RecoveryObjectives = objectives
+}
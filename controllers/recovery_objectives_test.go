@@ -0,0 +1,132 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+func TestEvaluateRecoveryObjectives(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    replicationv1alpha1.ReplicationMode
+		rpo     string
+		rto     string
+		reason  string
+		message string
+	}{
+		{name: "async RTO above RPO", mode: replicationv1alpha1.ReplicationModeAsynchronous, rpo: "15m", rto: "1h"},
+		{name: "async RTO equal to RPO", mode: replicationv1alpha1.ReplicationModeAsynchronous, rpo: "15m", rto: "15m"},
+		{name: "async RTO somewhat below RPO", mode: replicationv1alpha1.ReplicationModeAsynchronous, rpo: "15m", rto: "5m"},
+		{name: "sync RTO below RPO", mode: replicationv1alpha1.ReplicationModeSynchronous, rpo: "1h", rto: "5m"},
+		{name: "sync zero RPO", mode: replicationv1alpha1.ReplicationModeSynchronous, rpo: "0s", rto: "5m"},
+		{name: "RPO only", mode: replicationv1alpha1.ReplicationModeAsynchronous, rpo: "15m"},
+		{
+			name: "async RTO far below RPO", mode: replicationv1alpha1.ReplicationModeAsynchronous, rpo: "1d", rto: "30m",
+			reason: recoveryObjectivesRisky, message: "RTO 30m is far shorter than RPO 1d",
+		},
+		{
+			name: "async zero RPO", mode: replicationv1alpha1.ReplicationModeAsynchronous, rpo: "0s", rto: "5m",
+			reason: recoveryObjectivesUnachievable, message: "use synchronous mode",
+		},
+		{
+			name: "zero RTO", mode: replicationv1alpha1.ReplicationModeSynchronous, rpo: "0s", rto: "0s",
+			reason: recoveryObjectivesUnachievable, message: "an RTO of 0s cannot be met",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uvr := createTestUVR("test-objectives", "default")
+			uvr.Spec.ReplicationMode = tt.mode
+			uvr.Spec.Schedule.Rpo = tt.rpo
+			uvr.Spec.Schedule.Rto = tt.rto
+
+			reason, message := evaluateRecoveryObjectives(uvr)
+			assert.Equal(t, tt.reason, reason)
+			assert.Contains(t, message, tt.message)
+		})
+	}
+}
+
+func TestReconciler_RecoveryObjectivesCondition(t *testing.T) {
+	s := createTestScheme(t)
+	reconciler := createTestReconciler(fake.NewClientBuilder().WithScheme(s).Build(), s)
+	recorder := reconciler.Recorder.(*record.FakeRecorder)
+
+	// Nothing is reported for a spec without an RTO
+	uvr := createTestUVR("test-objectives", "default")
+	uvr.Spec.Schedule.Rto = ""
+	reconciler.checkRecoveryObjectives(uvr)
+	assert.Nil(t, reconciler.getCondition(uvr, recoveryObjectivesCondition))
+
+	// A tight RTO with a loose async RPO is flagged once
+	uvr.Spec.Schedule.Rpo = "4h"
+	uvr.Spec.Schedule.Rto = "15m"
+	reconciler.checkRecoveryObjectives(uvr)
+	reconciler.checkRecoveryObjectives(uvr)
+	cond := reconciler.getCondition(uvr, recoveryObjectivesCondition)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, recoveryObjectivesRisky, cond.Reason)
+	events := drainEvents(recorder)
+	require.Len(t, events, 1)
+	assert.Contains(t, events[0], "Warning RecoveryObjectivesInconsistent RTO 15m is far shorter than RPO 4h")
+
+	// Tightening the RPO resolves it
+	uvr.Spec.Schedule.Rpo = "5m"
+	reconciler.checkRecoveryObjectives(uvr)
+	cond = reconciler.getCondition(uvr, recoveryObjectivesCondition)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, "Consistent", cond.Reason)
+	assert.Empty(t, drainEvents(recorder))
+}
+
+func TestReconciler_RecordRecoveryObjectives(t *testing.T) {
+	s := createTestScheme(t)
+	reconciler := createTestReconciler(fake.NewClientBuilder().WithScheme(s).Build(), s)
+
+	// Equivalent objectives written differently share a normalized form
+	uvr := createTestUVR("test-objectives", "default")
+	uvr.Spec.Schedule.Rpo = "60m"
+	uvr.Spec.Schedule.Rto = "90m"
+	uvr.Spec.Schedule.MaxLag = "24h"
+	reconciler.recordRecoveryObjectives(uvr)
+	objectives := uvr.Status.RecoveryObjectives
+	require.NotNil(t, objectives)
+	assert.Equal(t, &replicationv1alpha1.NormalizedDuration{Raw: "60m", Normalized: "1h"}, objectives.Rpo)
+	assert.Equal(t, &replicationv1alpha1.NormalizedDuration{Raw: "90m", Normalized: "1h30m"}, objectives.Rto)
+	assert.Equal(t, &replicationv1alpha1.NormalizedDuration{Raw: "24h", Normalized: "1d"}, objectives.MaxLag)
+
+	// Unset objectives are omitted
+	uvr.Spec.Schedule.Rto = ""
+	uvr.Spec.Schedule.MaxLag = ""
+	reconciler.recordRecoveryObjectives(uvr)
+	assert.Nil(t, uvr.Status.RecoveryObjectives.Rto)
+	assert.Nil(t, uvr.Status.RecoveryObjectives.MaxLag)
+
+	uvr.Spec.Schedule.Rpo = ""
+	reconciler.recordRecoveryObjectives(uvr)
+	assert.Nil(t, uvr.Status.RecoveryObjectives)
+}
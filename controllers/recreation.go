@@ -0,0 +1,219 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+const (
+	// AllowRecreateAnnotation approves deleting and recreating the backend resource when a
+	// spec change cannot be applied in place. Recreation restarts replication from a new
+	// baseline, so it only happens once the annotation is set; the annotation is removed
+	// when recreation completes.
+	AllowRecreateAnnotation = "replication.storage.io/allow-recreate"
+
+	// recreatingCondition tracks a recreation in progress
+	recreatingCondition = "Recreating"
+
+	reasonDeletingBackendResource = "DeletingBackendResource"
+)
+
+// reconcileRecreation detects spec changes the backend cannot apply in place and, once
+// approved with the allow-recreate annotation, deletes the backend resource, waits until
+// it is gone and creates it again. Only the replication relationship is recreated; the
+// destination volume and baseline snapshot are kept. It returns true when it handled the
+// reconcile, in which case the result should be returned as is.
+//
+// No role change is applied while the resource is recreated: it is recreated in the last
+// role observed on the backend, and a pending promotion or demotion follows on the next
+// reconcile.
+func (r *UnifiedVolumeReplicationReconciler) reconcileRecreation(ctx context.Context, adapter adapters.ReplicationAdapter, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) (bool, ctrl.Result, error) {
+	detector, ok := adapter.(adapters.RecreationDetector)
+	if !ok {
+		return false, ctrl.Result{}, nil
+	}
+
+	if condition := r.getCondition(uvr, recreatingCondition); condition != nil &&
+		condition.Status == metav1.ConditionTrue && condition.Reason == reasonDeletingBackendResource {
+		result, err := r.finishRecreation(ctx, detector, uvr, log)
+		return true, result, err
+	}
+
+	changes, err := detector.RecreationRequired(ctx, uvr)
+	if err != nil {
+		log.V(1).Info("Unable to check for changes requiring recreation", "error", err.Error())
+		return false, ctrl.Result{}, nil
+	}
+	if len(changes) == 0 {
+		return false, ctrl.Result{}, nil
+	}
+
+	details := make([]string, 0, len(changes))
+	for _, change := range changes {
+		details = append(details, change.String())
+	}
+	summary := strings.Join(details, "; ")
+
+	if _, approved := uvr.Annotations[AllowRecreateAnnotation]; !approved {
+		message := fmt.Sprintf("Backend resource must be recreated to apply %s, set the %s annotation to allow it",
+			summary, AllowRecreateAnnotation)
+		if existing := r.getCondition(uvr, "Ready"); existing == nil || existing.Reason != "RecreationRequired" {
+			r.recordEventf(uvr, corev1.EventTypeWarning, "RecreationRequired", "%s", message)
+		}
+		log.Info("Spec change requires recreation", "changes", details)
+		r.updateCondition(uvr, metav1.Condition{
+			Type:               "Ready",
+			Status:             metav1.ConditionFalse,
+			Reason:             "RecreationRequired",
+			Message:            message,
+			ObservedGeneration: uvr.Generation,
+		})
+
+		r.recordFailedReconcile(uvr)
+		if err := r.Status().Update(ctx, uvr); err != nil {
+			log.Error(err, "Failed to update status")
+			return true, ctrl.Result{}, err
+		}
+		// Annotation changes do not trigger a reconcile, so look for the approval again later
+		return true, ctrl.Result{RequeueAfter: requeueDelaySuccess}, nil
+	}
+
+	log.Info("Recreating backend resource", "changes", details)
+	if err := r.ControllerEngine.RunOperation(ctx, uvr, "recreate-delete", log, func(ctx context.Context) error {
+		return detector.DeleteBackendResource(ctx, uvr)
+	}); err != nil {
+		log.Error(err, "Failed to delete backend resource for recreation")
+		r.updateCondition(uvr, metav1.Condition{
+			Type:               "Ready",
+			Status:             metav1.ConditionFalse,
+			Reason:             "RecreationFailed",
+			Message:            fmt.Sprintf("Failed to delete backend resource for recreation: %v", err),
+			ObservedGeneration: uvr.Generation,
+		})
+		r.recordEventf(uvr, corev1.EventTypeWarning, "RecreationFailed", "Failed to delete backend resource: %v", err)
+
+		r.recordFailedReconcile(uvr)
+		if err := r.Status().Update(ctx, uvr); err != nil {
+			log.Error(err, "Failed to update status")
+		}
+		return true, ctrl.Result{RequeueAfter: requeueDelayError}, err
+	}
+
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               recreatingCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             reasonDeletingBackendResource,
+		Message:            fmt.Sprintf("Deleted backend resource to apply %s, waiting for deletion to complete", summary),
+		ObservedGeneration: uvr.Generation,
+	})
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             "Recreating",
+		Message:            "Backend resource is being recreated",
+		ObservedGeneration: uvr.Generation,
+	})
+	r.recordEventf(uvr, corev1.EventTypeNormal, "RecreationStarted", "Recreating backend resource to apply %s", summary)
+
+	if err := r.Status().Update(ctx, uvr); err != nil {
+		log.Error(err, "Failed to update status")
+		return true, ctrl.Result{}, err
+	}
+	return true, ctrl.Result{RequeueAfter: requeueDelayFast}, nil
+}
+
+// finishRecreation creates the backend resource again once the backend confirms the old
+// one is gone, then clears the allow-recreate annotation. The new relationship starts from
+// a new baseline, so establishment is measured again.
+func (r *UnifiedVolumeReplicationReconciler) finishRecreation(ctx context.Context, detector adapters.RecreationDetector, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) (ctrl.Result, error) {
+	exists, err := detector.BackendResourceExists(ctx, uvr)
+	if err != nil {
+		log.Error(err, "Failed to confirm backend resource deletion")
+		return ctrl.Result{RequeueAfter: requeueDelayError}, nil
+	}
+	if exists {
+		log.Info("Waiting for backend resource deletion before recreating")
+		return ctrl.Result{RequeueAfter: requeueDelayFast}, nil
+	}
+
+	// Recreate in the last observed role so a promotion cannot happen mid-recreate
+	pinned := uvr.DeepCopy()
+	if role := lastObservedRole(uvr); role != "" {
+		pinned.Spec.ReplicationState = role
+	}
+	if err := r.ControllerEngine.EnsureReplication(ctx, pinned, log); err != nil {
+		log.Error(err, "Failed to recreate backend resource")
+		r.updateCondition(uvr, metav1.Condition{
+			Type:               "Ready",
+			Status:             metav1.ConditionFalse,
+			Reason:             "RecreationFailed",
+			Message:            fmt.Sprintf("Failed to recreate backend resource: %v", err),
+			ObservedGeneration: uvr.Generation,
+		})
+		r.recordEventf(uvr, corev1.EventTypeWarning, "RecreationFailed", "Failed to recreate backend resource: %v", err)
+
+		r.recordFailedReconcile(uvr)
+		if err := r.Status().Update(ctx, uvr); err != nil {
+			log.Error(err, "Failed to update status")
+		}
+		return ctrl.Result{RequeueAfter: requeueDelayError}, err
+	}
+
+	// Require a fresh approval before the next recreation
+	current := &replicationv1alpha1.UnifiedVolumeReplication{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(uvr), current); err != nil {
+		return ctrl.Result{}, err
+	}
+	patched := current.DeepCopy()
+	delete(patched.Annotations, AllowRecreateAnnotation)
+	if err := r.Patch(ctx, patched, client.MergeFrom(current)); err != nil {
+		log.Error(err, "Failed to remove allow-recreate annotation")
+		return ctrl.Result{}, err
+	}
+
+	patched.Status = uvr.Status
+	r.recordRelationshipChurn(patched, churnReasonRecreated, time.Now())
+	r.resetEstablishment(patched, "Recreated", "Backend resource recreated, waiting for the new baseline sync")
+	r.updateCondition(patched, metav1.Condition{
+		Type:               recreatingCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             "RecreationComplete",
+		Message:            "Backend resource recreated",
+		ObservedGeneration: uvr.Generation,
+	})
+	r.recordEventf(patched, corev1.EventTypeNormal, "Recreated", "Backend resource recreated")
+	log.Info("Backend resource recreated")
+
+	if err := r.Status().Update(ctx, patched); err != nil {
+		log.Error(err, "Failed to update status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: requeueDelayFast}, nil
+}
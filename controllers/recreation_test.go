@@ -0,0 +1,219 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestReconciler_RecreateOnModeChange(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+
+	uvr := createTestUVR("test-recreate", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+
+	// Record the backend writes made to the mirror relationship
+	var operations []string
+	isMirrorRelationship := func(obj client.Object) bool {
+		u, ok := obj.(*unstructured.Unstructured)
+		return ok && u.GroupVersionKind() == adapters.TridentMirrorRelationshipGVK
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(tridentCRDs(""), uvr)...).
+		WithStatusSubresource(uvr).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if isMirrorRelationship(obj) {
+					operations = append(operations, "create")
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				if isMirrorRelationship(obj) {
+					operations = append(operations, "delete")
+				}
+				return c.Delete(ctx, obj, opts...)
+			},
+		}).Build()
+
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewTridentAdapterFactory()))
+	reconciler := createTestReconciler(c, s)
+	reconciler.AdapterRegistry = registry
+	reconciler.ControllerEngine = pkg.NewControllerEngine(c, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+		registry, pkg.DefaultControllerEngineConfig())
+	recorder := reconciler.Recorder.(*record.FakeRecorder)
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+	mirrorPolicy := func() (string, bool) {
+		tmr := &unstructured.Unstructured{}
+		tmr.SetGroupVersionKind(adapters.TridentMirrorRelationshipGVK)
+		if err := c.Get(ctx, req.NamespacedName, tmr); err != nil {
+			require.True(t, apierrors.IsNotFound(err))
+			return "", false
+		}
+		policy, _, _ := unstructured.NestedString(tmr.Object, "spec", "replicationPolicy")
+		return policy, true
+	}
+	latest := func() *replicationv1alpha1.UnifiedVolumeReplication {
+		current := &replicationv1alpha1.UnifiedVolumeReplication{}
+		require.NoError(t, c.Get(ctx, req.NamespacedName, current))
+		return current
+	}
+
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	policy, _ := mirrorPolicy()
+	require.Equal(t, "Async", policy)
+	drainEvents(recorder)
+	defer forgetRelationship(latest())
+	require.NotNil(t, latest().Status.Relationship)
+	created := latest().Status.Relationship.CreationTime
+
+	// Trident cannot switch an existing relationship to a synchronous policy
+	current := latest()
+	current.Spec.ReplicationMode = replicationv1alpha1.ReplicationModeSynchronous
+	require.NoError(t, c.Update(ctx, current))
+
+	t.Run("HeldWithoutApproval", func(t *testing.T) {
+		_, err := reconciler.Reconcile(ctx, req)
+		require.NoError(t, err)
+
+		policy, exists := mirrorPolicy()
+		assert.True(t, exists)
+		assert.Equal(t, "Async", policy, "backend resource is left untouched")
+
+		ready := reconciler.getCondition(latest(), "Ready")
+		require.NotNil(t, ready)
+		assert.Equal(t, metav1.ConditionFalse, ready.Status)
+		assert.Equal(t, "RecreationRequired", ready.Reason)
+		assert.Contains(t, drainEvents(recorder),
+			"Warning RecreationRequired Backend resource must be recreated to apply replicationPolicy: desired \"Sync\", observed \"Async\", set the replication.storage.io/allow-recreate annotation to allow it")
+	})
+
+	t.Run("RecreatedOnceApproved", func(t *testing.T) {
+		current := latest()
+		current.Annotations = map[string]string{AllowRecreateAnnotation: "true"}
+		require.NoError(t, c.Update(ctx, current))
+
+		// The first reconcile deletes the relationship and waits for the deletion to complete
+		result, err := reconciler.Reconcile(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, requeueDelayFast, result.RequeueAfter)
+		_, exists := mirrorPolicy()
+		assert.False(t, exists)
+		recreating := reconciler.getCondition(latest(), recreatingCondition)
+		require.NotNil(t, recreating)
+		assert.Equal(t, metav1.ConditionTrue, recreating.Status)
+		assert.Equal(t, reasonDeletingBackendResource, recreating.Reason)
+
+		// The next reconcile confirms the deletion and creates it with the new policy
+		_, err = reconciler.Reconcile(ctx, req)
+		require.NoError(t, err)
+		policy, exists := mirrorPolicy()
+		assert.True(t, exists)
+		assert.Equal(t, "Sync", policy)
+
+		updated := latest()
+		assert.NotContains(t, updated.Annotations, AllowRecreateAnnotation, "approval is consumed")
+		recreating = reconciler.getCondition(updated, recreatingCondition)
+		require.NotNil(t, recreating)
+		assert.Equal(t, metav1.ConditionFalse, recreating.Status)
+		assert.Equal(t, "RecreationComplete", recreating.Reason)
+		require.NotNil(t, updated.Status.Relationship)
+		assert.Equal(t, int32(1), updated.Status.Relationship.ChurnCount, "the recreation counts as churn")
+		assert.Equal(t, churnReasonRecreated, updated.Status.Relationship.LastChurnReason)
+		assert.True(t, created.Equal(&updated.Status.Relationship.CreationTime))
+		initialSync := reconciler.getCondition(updated, "InitialSyncComplete")
+		require.NotNil(t, initialSync, "the new relationship is established again")
+		assert.Equal(t, metav1.ConditionFalse, initialSync.Status)
+		assert.Equal(t, "Recreated", initialSync.Reason)
+		assert.NotNil(t, updated.Status.EstablishmentStartTime)
+
+		assert.Equal(t, []string{"create", "delete", "create"}, operations)
+		events := drainEvents(recorder)
+		assert.Contains(t, events, "Normal RecreationStarted Recreating backend resource to apply replicationPolicy: desired \"Sync\", observed \"Async\"")
+		assert.Contains(t, events, "Normal Recreated Backend resource recreated")
+
+		// Nothing is left to recreate
+		_, err = reconciler.Reconcile(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"create", "delete", "create"}, operations)
+		assert.Equal(t, int32(1), latest().Status.Relationship.ChurnCount)
+	})
+}
+
+func TestReconciler_RecreationKeepsObservedRole(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+
+	// A promotion is requested while the recreation waits for the old resource to go away
+	uvr := createTestUVR("test-recreate-role", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+	uvr.Status.StateHistory = []replicationv1alpha1.StateHistoryEntry{
+		{Timestamp: metav1.Now(), To: string(replicationv1alpha1.ReplicationStateReplica)},
+	}
+	uvr.Status.Conditions = []metav1.Condition{{
+		Type:               recreatingCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             reasonDeletingBackendResource,
+		LastTransitionTime: metav1.Now(),
+	}}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(tridentCRDs(""), uvr)...).
+		WithStatusSubresource(uvr).Build()
+
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewTridentAdapterFactory()))
+	reconciler := createTestReconciler(c, s)
+	reconciler.AdapterRegistry = registry
+	reconciler.ControllerEngine = pkg.NewControllerEngine(c, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+		registry, pkg.DefaultControllerEngineConfig())
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	tmr := &unstructured.Unstructured{}
+	tmr.SetGroupVersionKind(adapters.TridentMirrorRelationshipGVK)
+	require.NoError(t, c.Get(ctx, req.NamespacedName, tmr))
+	state, _, _ := unstructured.NestedString(tmr.Object, "spec", "state")
+	assert.Equal(t, "established", state, "recreated as a replica, the promotion follows later")
+
+	updated := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, replicationv1alpha1.ReplicationStateSource, updated.Spec.ReplicationState, "the requested role is kept")
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+const (
+	// credentialsSecretIndexField indexes UVRs by the name of the Secret they take
+	// credentials from
+	credentialsSecretIndexField = "spec.credentialsSecretRef.name"
+	// profileConfigMapIndexField indexes UVRs by the name of the ConfigMap holding their profile
+	profileConfigMapIndexField = "spec.profileRef.name"
+
+	// referenceMissingCondition reports that a referenced Secret or ConfigMap does not exist
+	referenceMissingCondition = "ReferenceMissing"
+)
+
+// indexCredentialsSecret is the field indexer for credentialsSecretIndexField
+func indexCredentialsSecret(obj client.Object) []string {
+	uvr, ok := obj.(*replicationv1alpha1.UnifiedVolumeReplication)
+	if !ok || uvr.Spec.CredentialsSecretRef == nil || uvr.Spec.CredentialsSecretRef.Name == "" {
+		return nil
+	}
+	return []string{uvr.Spec.CredentialsSecretRef.Name}
+}
+
+// indexProfileConfigMap is the field indexer for profileConfigMapIndexField
+func indexProfileConfigMap(obj client.Object) []string {
+	uvr, ok := obj.(*replicationv1alpha1.UnifiedVolumeReplication)
+	if !ok || uvr.Spec.ProfileRef == nil || uvr.Spec.ProfileRef.Name == "" {
+		return nil
+	}
+	return []string{uvr.Spec.ProfileRef.Name}
+}
+
+// indexReferencedObjects registers the indexes used to find the UVRs referencing a Secret or
+// ConfigMap
+func indexReferencedObjects(ctx context.Context, indexer client.FieldIndexer) error {
+	if err := indexer.IndexField(ctx, &replicationv1alpha1.UnifiedVolumeReplication{},
+		credentialsSecretIndexField, indexCredentialsSecret); err != nil {
+		return err
+	}
+	return indexer.IndexField(ctx, &replicationv1alpha1.UnifiedVolumeReplication{},
+		profileConfigMapIndexField, indexProfileConfigMap)
+}
+
+// watchReferencedObjects re-reconciles the UVRs referencing a Secret or ConfigMap when it
+// changes, so a rotated credential or updated profile is picked up without waiting for the
+// periodic requeue. Only metadata is watched, keeping Secret data out of the cache. admit
+// limits the enqueued UVRs to those the controller being built is responsible for.
+func (r *UnifiedVolumeReplicationReconciler) watchReferencedObjects(b *builder.Builder, admit func(*replicationv1alpha1.UnifiedVolumeReplication) bool) *builder.Builder {
+	return b.
+		Watches(&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.replicationsReferencing(credentialsSecretIndexField, admit)),
+			builder.OnlyMetadata).
+		Watches(&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.replicationsReferencing(profileConfigMapIndexField, admit)),
+			builder.OnlyMetadata)
+}
+
+// replicationsReferencing maps a Secret or ConfigMap to the UVRs in its namespace that
+// reference it through the given index
+func (r *UnifiedVolumeReplicationReconciler) replicationsReferencing(field string, admit func(*replicationv1alpha1.UnifiedVolumeReplication) bool) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		if !r.watchesNamespace(obj.GetNamespace()) {
+			return nil
+		}
+		uvrs := &replicationv1alpha1.UnifiedVolumeReplicationList{}
+		if err := r.List(ctx, uvrs, client.InNamespace(obj.GetNamespace()), client.MatchingFields{field: obj.GetName()}); err != nil {
+			r.Log.Error(err, "Failed to list replications referencing object", "object", client.ObjectKeyFromObject(obj))
+			return nil
+		}
+		var requests []reconcile.Request
+		for i := range uvrs.Items {
+			if admit != nil && !admit(&uvrs.Items[i]) {
+				continue
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&uvrs.Items[i])})
+		}
+		return requests
+	}
+}
+
+// checkReferencedObjects verifies that the Secret and ConfigMap the UVR references exist.
+// When one is missing it sets the ReferenceMissing and Ready conditions, and returns true with
+// the result the reconcile should end with. The watch brings the UVR back once the object is
+// created; the requeue covers missed events.
+func (r *UnifiedVolumeReplicationReconciler) checkReferencedObjects(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) (bool, ctrl.Result) {
+	var missing []string
+	check := func(kind string, ref *corev1.LocalObjectReference) {
+		if ref == nil || ref.Name == "" {
+			return
+		}
+		obj := &metav1.PartialObjectMetadata{}
+		obj.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind(kind))
+		key := types.NamespacedName{Namespace: uvr.Namespace, Name: ref.Name}
+		if err := r.Get(ctx, key, obj); err != nil {
+			if !errors.IsNotFound(err) {
+				// Leave transient API errors to the backend rather than holding the UVR
+				log.Error(err, "Failed to read referenced object", "kind", kind, "name", key)
+				return
+			}
+			missing = append(missing, fmt.Sprintf("%s %s", kind, key))
+		}
+	}
+	check("Secret", uvr.Spec.CredentialsSecretRef)
+	check("ConfigMap", uvr.Spec.ProfileRef)
+
+	if len(missing) == 0 {
+		if existing := r.getCondition(uvr, referenceMissingCondition); existing != nil && existing.Status == metav1.ConditionTrue {
+			r.updateCondition(uvr, metav1.Condition{
+				Type:               referenceMissingCondition,
+				Status:             metav1.ConditionFalse,
+				Reason:             "Found",
+				Message:            "All referenced objects exist",
+				ObservedGeneration: uvr.Generation,
+			})
+		}
+		return false, ctrl.Result{}
+	}
+
+	message := fmt.Sprintf("%s not found, waiting", strings.Join(missing, " and "))
+	log.Info("Referenced object missing", "missing", missing)
+	if existing := r.getCondition(uvr, "Ready"); existing == nil || existing.Reason != referenceMissingCondition {
+		r.recordEventf(uvr, corev1.EventTypeWarning, referenceMissingCondition, "%s", message)
+	}
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               referenceMissingCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "NotFound",
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             referenceMissingCondition,
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+	return true, ctrl.Result{RequeueAfter: requeueDelaySuccess}
+}
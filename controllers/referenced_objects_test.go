@@ -0,0 +1,154 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestReferencedObjects_ChangeEnqueuesDependentReplications(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	db := createTestUVR("db", "default")
+	db.Spec.CredentialsSecretRef = &corev1.LocalObjectReference{Name: "array-creds"}
+	web := createTestUVR("web", "default")
+	web.Spec.CredentialsSecretRef = &corev1.LocalObjectReference{Name: "other-creds"}
+	profiled := createTestUVR("profiled", "default")
+	profiled.Spec.ProfileRef = &corev1.LocalObjectReference{Name: "array-creds"}
+	elsewhere := createTestUVR("db", "other")
+	elsewhere.Spec.CredentialsSecretRef = &corev1.LocalObjectReference{Name: "array-creds"}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "array-creds", Namespace: "default", ResourceVersion: "1"},
+		Data:       map[string][]byte{"password": []byte("old")},
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(db, web, profiled, elsewhere).
+		WithIndex(&replicationv1alpha1.UnifiedVolumeReplication{}, credentialsSecretIndexField, indexCredentialsSecret).
+		WithIndex(&replicationv1alpha1.UnifiedVolumeReplication{}, profileConfigMapIndexField, indexProfileConfigMap).Build()
+	reconciler := createTestReconciler(c, s)
+
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+	queued := func() []reconcile.Request {
+		t.Helper()
+		var requests []reconcile.Request
+		for q.Len() > 0 {
+			req, _ := q.Get()
+			q.Done(req)
+			q.Forget(req)
+			requests = append(requests, req)
+		}
+		return requests
+	}
+
+	// Rotating the credential enqueues only the UVR using it
+	rotated := secret.DeepCopy()
+	rotated.ResourceVersion = "2"
+	rotated.Data["password"] = []byte("new")
+	secrets := handler.EnqueueRequestsFromMapFunc(reconciler.replicationsReferencing(credentialsSecretIndexField, nil))
+	secrets.Update(ctx, event.UpdateEvent{ObjectOld: secret, ObjectNew: rotated}, q)
+	assert.Equal(t, []reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(db)}}, queued())
+
+	// A ConfigMap of the same name only enqueues the UVR using it as a profile
+	profile := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "array-creds", Namespace: "default"}}
+	configMaps := handler.EnqueueRequestsFromMapFunc(reconciler.replicationsReferencing(profileConfigMapIndexField, nil))
+	configMaps.Update(ctx, event.UpdateEvent{ObjectOld: profile, ObjectNew: profile}, q)
+	assert.Equal(t, []reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(profiled)}}, queued())
+
+	// A partitioned controller only enqueues the UVRs it reconciles
+	secrets = handler.EnqueueRequestsFromMapFunc(reconciler.replicationsReferencing(credentialsSecretIndexField,
+		func(*replicationv1alpha1.UnifiedVolumeReplication) bool { return false }))
+	secrets.Update(ctx, event.UpdateEvent{ObjectOld: secret, ObjectNew: rotated}, q)
+	assert.Empty(t, queued())
+
+	// Outside the watched namespaces nothing is enqueued
+	reconciler.WatchNamespaces = []string{"other"}
+	secrets = handler.EnqueueRequestsFromMapFunc(reconciler.replicationsReferencing(credentialsSecretIndexField, nil))
+	secrets.Update(ctx, event.UpdateEvent{ObjectOld: secret, ObjectNew: rotated}, q)
+	assert.Empty(t, queued())
+}
+
+func TestReconciler_WaitsForReferencedObjects(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+
+	uvr := createTestUVR("test-references", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	uvr.Spec.CredentialsSecretRef = &corev1.LocalObjectReference{Name: "array-creds"}
+	uvr.Spec.ProfileRef = &corev1.LocalObjectReference{Name: "gold"}
+	profile := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "gold", Namespace: "default"}}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(tridentCRDs(""), uvr, profile)...).
+		WithStatusSubresource(uvr).Build()
+
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewTridentAdapterFactory()))
+	reconciler := createTestReconciler(c, s)
+	reconciler.AdapterRegistry = registry
+	reconciler.ControllerEngine = pkg.NewControllerEngine(c, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+		registry, pkg.DefaultControllerEngineConfig())
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+	result, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, requeueDelaySuccess, result.RequeueAfter)
+
+	tmr := &unstructured.Unstructured{}
+	tmr.SetGroupVersionKind(adapters.TridentMirrorRelationshipGVK)
+	assert.Error(t, c.Get(ctx, req.NamespacedName, tmr), "nothing is created on the backend")
+
+	require.NoError(t, c.Get(ctx, req.NamespacedName, uvr))
+	missing := reconciler.getCondition(uvr, referenceMissingCondition)
+	require.NotNil(t, missing)
+	assert.Equal(t, metav1.ConditionTrue, missing.Status)
+	assert.Equal(t, "Secret default/array-creds not found, waiting", missing.Message)
+	ready := reconciler.getCondition(uvr, "Ready")
+	require.NotNil(t, ready)
+	assert.Equal(t, referenceMissingCondition, ready.Reason)
+
+	// Once the Secret is created the replication proceeds
+	require.NoError(t, c.Create(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "array-creds", Namespace: "default"}}))
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.NoError(t, c.Get(ctx, req.NamespacedName, tmr), "the backend resource is created")
+
+	require.NoError(t, c.Get(ctx, req.NamespacedName, uvr))
+	missing = reconciler.getCondition(uvr, referenceMissingCondition)
+	require.NotNil(t, missing)
+	assert.Equal(t, metav1.ConditionFalse, missing.Status)
+	assert.Equal(t, "Found", missing.Reason)
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+// Reasons a relationship is rebuilt, as reported in status.relationship.lastChurnReason
+const (
+	churnReasonRecreated  = "Recreated"
+	churnReasonFullResync = "FullResync"
+)
+
+var (
+	// relationshipCreationTimestamp exposes when each relationship was established, so its age
+	// is time() minus the sample
+	relationshipCreationTimestamp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "unified_replication_relationship_creation_timestamp_seconds",
+		Help: "Unix time at which the operator first established the backend replication relationship",
+	}, []string{"namespace", "name", "backend"})
+
+	// relationshipChurnTotal counts the recreations and full resyncs of each relationship
+	relationshipChurnTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "unified_replication_relationship_churn_total",
+		Help: "Times the backend replication relationship was recreated or fully resynced, by reason",
+	}, []string{"namespace", "name", "backend", "reason"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(relationshipCreationTimestamp, relationshipChurnTotal)
+}
+
+// recordRelationshipEstablished records when the relationship was first established, after
+// a successful EnsureReplication, and exports its creation time
+func (r *UnifiedVolumeReplicationReconciler) recordRelationshipEstablished(uvr *replicationv1alpha1.UnifiedVolumeReplication, now time.Time) {
+	if uvr.Status.Relationship == nil {
+		uvr.Status.Relationship = &replicationv1alpha1.RelationshipStatus{CreationTime: metav1.NewTime(now)}
+	}
+	relationshipCreationTimestamp.WithLabelValues(uvr.Namespace, uvr.Name, backendPartitionFor(uvr)).
+		Set(float64(uvr.Status.Relationship.CreationTime.Unix()))
+}
+
+// recordRelationshipChurn counts a recreation or full resync of the relationship, in status
+// and as a metric. The creation time is kept: the relationship is rebuilt, not new.
+func (r *UnifiedVolumeReplicationReconciler) recordRelationshipChurn(uvr *replicationv1alpha1.UnifiedVolumeReplication, reason string, now time.Time) {
+	relationship := uvr.Status.Relationship
+	if relationship == nil {
+		relationship = &replicationv1alpha1.RelationshipStatus{CreationTime: uvr.CreationTimestamp}
+		uvr.Status.Relationship = relationship
+	}
+	at := metav1.NewTime(now)
+	relationship.ChurnCount++
+	relationship.LastChurnTime = &at
+	relationship.LastChurnReason = reason
+	relationshipChurnTotal.WithLabelValues(uvr.Namespace, uvr.Name, backendPartitionFor(uvr), reason).Inc()
+}
+
+// forgetRelationship drops the relationship metrics of a deleted replication
+func forgetRelationship(uvr *replicationv1alpha1.UnifiedVolumeReplication) {
+	labels := prometheus.Labels{"namespace": uvr.Namespace, "name": uvr.Name}
+	relationshipCreationTimestamp.DeletePartialMatch(labels)
+	relationshipChurnTotal.DeletePartialMatch(labels)
+}
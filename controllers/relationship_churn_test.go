@@ -0,0 +1,84 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestReconciler_RelationshipAgeAndChurn(t *testing.T) {
+	ctx := context.Background()
+	uvr := createTestUVR("test-relationship", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	reconciler, c := newDeactivationTestReconciler(t, adapters.NewTridentAdapterFactory(), uvr)
+	defer forgetRelationship(uvr)
+	key := client.ObjectKeyFromObject(uvr)
+
+	churn := func(reason string) float64 {
+		metric := &dto.Metric{}
+		require.NoError(t, relationshipChurnTotal.WithLabelValues("default", "test-relationship", "trident", reason).(prometheus.Counter).Write(metric))
+		return metric.GetCounter().GetValue()
+	}
+	createdAt := func() float64 {
+		metric := &dto.Metric{}
+		require.NoError(t, relationshipCreationTimestamp.WithLabelValues("default", "test-relationship", "trident").(prometheus.Gauge).Write(metric))
+		return metric.GetGauge().GetValue()
+	}
+
+	// Establishing the relationship records its creation time
+	before := time.Now().Truncate(time.Second)
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, key, uvr))
+	relationship := uvr.Status.Relationship
+	require.NotNil(t, relationship)
+	created := relationship.CreationTime
+	assert.False(t, created.Time.Before(before))
+	assert.Zero(t, relationship.ChurnCount)
+	assert.Nil(t, relationship.LastChurnTime)
+	assert.Equal(t, float64(created.Unix()), createdAt())
+
+	// The age is measured from the creation time, which later reconciles keep
+	assert.Equal(t, 3*time.Hour, relationship.Age(created.Add(3*time.Hour)))
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, key, uvr))
+	assert.True(t, created.Equal(&uvr.Status.Relationship.CreationTime))
+
+	// Each full resync and recreation counts as churn
+	reconciler.restartEstablishment(uvr, "Resyncing from scratch")
+	reconciler.recordRelationshipChurn(uvr, churnReasonRecreated, time.Now())
+	reconciler.restartEstablishment(uvr, "Resyncing from scratch")
+	relationship = uvr.Status.Relationship
+	assert.Equal(t, int32(3), relationship.ChurnCount)
+	assert.Equal(t, churnReasonFullResync, relationship.LastChurnReason)
+	require.NotNil(t, relationship.LastChurnTime)
+	assert.True(t, created.Equal(&relationship.CreationTime), "a rebuilt relationship keeps its age")
+	assert.Equal(t, float64(2), churn(churnReasonFullResync))
+	assert.Equal(t, float64(1), churn(churnReasonRecreated))
+}
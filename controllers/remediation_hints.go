@@ -0,0 +1,109 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+	"regexp"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// RemediationHint turns a common failure to establish a replication into guidance an
+// operator can act on
+type RemediationHint struct {
+	// Name identifies the failure signature
+	Name string
+	// Matches reports whether an error has the signature
+	Matches func(err error) bool
+	// Remediation is the guidance appended to the condition message and event of the failure
+	Remediation string
+}
+
+// RemediationHints is consulted in order and the first matching hint is used. Append to it
+// to cover new failure signatures.
+var RemediationHints = []RemediationHint{
+	{
+		Name: "InsufficientCapacity",
+		Matches: func(err error) bool {
+			return adapters.IsInsufficientCapacityError(err) ||
+				errorMatches(err, `insufficient (capacity|space)|no space left|out of space|quota exceeded`)
+		},
+		Remediation: "free or add capacity in the destination pool, or set destinationEndpoint.storageClass to a class with enough free capacity; the CapacityReserved condition shows the shortfall",
+	},
+	{
+		Name: "CRDMissing",
+		Matches: func(err error) bool {
+			return apimeta.IsNoMatchError(err) ||
+				errorMatches(err, `no matches for kind|could not find the requested resource|no kind "?\w+"? is registered|(crd|customresourcedefinition)s? \S+ (not found|not installed)`)
+		},
+		Remediation: "install the replication CRDs of the backend (csi-addons VolumeReplication for Ceph, TridentMirrorRelationship for Trident, DellCSIReplicationGroup for PowerStore) and check they are listed by `kubectl get crd`",
+	},
+	{
+		Name: "ClassNotFound",
+		Matches: func(err error) bool {
+			return errorMatches(err, `(storage ?class|volumereplicationclass|replication ?class)(es)?\S*( "?[\w.-]+"?)? not found`)
+		},
+		Remediation: "create the storage or replication class the replication references, or correct the storage class in the spec; `kubectl get storageclass,volumereplicationclass` lists the existing ones",
+	},
+	{
+		Name: "PVCNotBound",
+		Matches: func(err error) bool {
+			return errorMatches(err, `(persistentvolumeclaim|pvc)\S*( "?[\w./-]+"?)? (is )?(not bound|unbound|pending)`)
+		},
+		Remediation: "wait for the source PVC to be Bound, or check `kubectl describe pvc` for provisioning errors; --missing-source-pvc-policy=wait holds replications until their PVC is Bound",
+	},
+	{
+		Name: "AuthFailure",
+		Matches: func(err error) bool {
+			var adapterErr *adapters.AdapterError
+			return apierrors.IsUnauthorized(err) || apierrors.IsForbidden(err) ||
+				(errors.As(err, &adapterErr) && adapterErr.Type == adapters.ErrorTypePermission) ||
+				errorMatches(err, `unauthori[sz]ed|forbidden|authentication failed|permission denied|invalid credentials|access denied`)
+		},
+		Remediation: "check the backend credentials Secret referenced by the replication, and that the operator's service account is granted the verb and resource named in the error",
+	},
+}
+
+// errorMatches reports whether the message of err matches a case-insensitive pattern
+func errorMatches(err error, pattern string) bool {
+	return regexp.MustCompile(`(?i)` + pattern).MatchString(err.Error())
+}
+
+// remediationHint returns the first hint matching err
+func remediationHint(err error) (RemediationHint, bool) {
+	if err == nil {
+		return RemediationHint{}, false
+	}
+	for _, hint := range RemediationHints {
+		if hint.Matches(err) {
+			return hint, true
+		}
+	}
+	return RemediationHint{}, false
+}
+
+// withRemediation appends the remediation of err, if a hint matches it, to message
+func withRemediation(message string, err error) string {
+	if hint, ok := remediationHint(err); ok {
+		return message + ". Remediation: " + hint.Remediation
+	}
+	return message
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg"
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+func TestRemediationHint(t *testing.T) {
+	tridentMirror := schema.GroupResource{Group: "trident.netapp.io", Resource: "tridentmirrorrelationships"}
+	tests := []struct {
+		name string
+		err  error
+		hint string
+	}{
+		{
+			name: "CRDMissingNoMatch",
+			err:  &apimeta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "replication.storage.openshift.io", Kind: "VolumeReplication"}},
+			hint: "CRDMissing",
+		},
+		{
+			name: "CRDMissingMessage",
+			err:  errors.New(`failed to create VolumeReplication: no matches for kind "VolumeReplication" in version "replication.storage.openshift.io/v1alpha1"`),
+			hint: "CRDMissing",
+		},
+		{
+			name: "StorageClassNotFound",
+			err:  apierrors.NewNotFound(schema.GroupResource{Group: "storage.k8s.io", Resource: "storageclasses"}, "fast-ssd"),
+			hint: "ClassNotFound",
+		},
+		{
+			name: "ReplicationClassNotFound",
+			err:  errors.New(`VolumeReplicationClass "rbd-volumereplicationclass" not found`),
+			hint: "ClassNotFound",
+		},
+		{
+			name: "PVCNotBound",
+			err:  errors.New(`PVC default/source-pvc is not bound`),
+			hint: "PVCNotBound",
+		},
+		{
+			name: "PVCPending",
+			err:  errors.New(`persistentvolumeclaim "source-pvc" pending`),
+			hint: "PVCNotBound",
+		},
+		{
+			name: "InsufficientCapacityAdapterError",
+			err: adapters.NewAdapterError(adapters.ErrorTypeResource, translation.BackendCeph, "reserve", "db",
+				"destination storage class has 1Gi free, 10Gi requested"),
+			hint: "InsufficientCapacity",
+		},
+		{
+			name: "InsufficientCapacityMessage",
+			err:  errors.New("SnapMirror initialize failed: No space left on device"),
+			hint: "InsufficientCapacity",
+		},
+		{
+			name: "Forbidden",
+			err:  apierrors.NewForbidden(tridentMirror, "db", errors.New("User cannot create resource")),
+			hint: "AuthFailure",
+		},
+		{
+			name: "BackendAuthentication",
+			err:  fmt.Errorf("ensure failed: %w", errors.New("PowerStore API: authentication failed (401)")),
+			hint: "AuthFailure",
+		},
+		{
+			name: "PermissionAdapterError",
+			err:  adapters.NewAdapterError(adapters.ErrorTypePermission, translation.BackendPowerStore, "ensure", "db", "credentials rejected"),
+			hint: "AuthFailure",
+		},
+		{
+			name: "Unrecognized",
+			err:  errors.New("relationship is transferring"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hint, ok := remediationHint(tt.err)
+			assert.Equal(t, tt.hint != "", ok)
+			assert.Equal(t, tt.hint, hint.Name)
+
+			message := withRemediation("Failed to ensure replication", tt.err)
+			if ok {
+				assert.Equal(t, "Failed to ensure replication. Remediation: "+hint.Remediation, message)
+			} else {
+				assert.Equal(t, "Failed to ensure replication", message)
+			}
+		})
+	}
+}
+
+func TestReconciler_EnsureFailureRemediation(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+
+	uvr := createTestUVR("test-remediation", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+
+	// The operator may not create the mirror relationship
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(tridentCRDs(""), uvr)...).
+		WithStatusSubresource(uvr).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if u, ok := obj.(*unstructured.Unstructured); ok && u.GroupVersionKind() == adapters.TridentMirrorRelationshipGVK {
+					return apierrors.NewForbidden(schema.GroupResource{Group: "trident.netapp.io", Resource: "tridentmirrorrelationships"},
+						u.GetName(), errors.New("cannot create resource in the namespace"))
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).Build()
+
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewTridentAdapterFactory()))
+	reconciler := createTestReconciler(c, s)
+	reconciler.AdapterRegistry = registry
+	reconciler.ControllerEngine = pkg.NewControllerEngine(c, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+		registry, pkg.DefaultControllerEngineConfig())
+	recorder := reconciler.Recorder.(*record.FakeRecorder)
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+	_, err := reconciler.Reconcile(ctx, req)
+	require.Error(t, err)
+
+	var hint RemediationHint
+	for _, candidate := range RemediationHints {
+		if candidate.Name == "AuthFailure" {
+			hint = candidate
+		}
+	}
+	require.NotEmpty(t, hint.Remediation)
+	updated := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	ready := reconciler.getCondition(updated, "Ready")
+	require.NotNil(t, ready)
+	assert.Equal(t, metav1.ConditionFalse, ready.Status)
+	assert.Equal(t, "ReconciliationFailed", ready.Reason)
+	assert.Contains(t, ready.Message, "is forbidden")
+	assert.Contains(t, ready.Message, "Remediation: "+hint.Remediation)
+
+	assert.Contains(t, drainEvents(recorder), "Warning ReconciliationFailed "+ready.Message,
+		"the event carries the remediation too")
+}
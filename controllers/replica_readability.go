@@ -0,0 +1,63 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// replicaReadabilityInterval is how often the adapter is asked to re-verify that the
+// replica can be mounted read-only
+const replicaReadabilityInterval = 5 * time.Minute
+
+// verifyReplicaReadable refreshes status.replicaReadable while this side is the replica.
+// The result is cleared when the volume is the source or the adapter cannot verify it.
+func (r *UnifiedVolumeReplicationReconciler) verifyReplicaReadable(ctx context.Context, adapter adapters.ReplicationAdapter, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) {
+	verifier, ok := adapter.(adapters.ReplicaReadabilityVerifier)
+	if !ok || uvr.Spec.ReplicationState != replicationv1alpha1.ReplicationStateReplica {
+		uvr.Status.ReplicaReadable = nil
+		return
+	}
+
+	previous := uvr.Status.ReplicaReadable
+	if previous != nil && time.Since(previous.LastVerifiedTime.Time) < replicaReadabilityInterval {
+		return
+	}
+
+	readable, reason, err := verifier.VerifyReplicaReadable(ctx, uvr)
+	if err != nil {
+		// Keep the previous result; it is retried on the next reconcile
+		log.V(1).Info("Unable to verify replica readability", "error", err.Error())
+		return
+	}
+
+	if previous == nil || previous.Readable != readable {
+		log.Info("Replica readability changed", "readable", readable, "reason", reason)
+	}
+	uvr.Status.ReplicaReadable = &replicationv1alpha1.ReplicaReadability{
+		Readable:         readable,
+		Reason:           reason,
+		LastVerifiedTime: metav1.Now(),
+	}
+}
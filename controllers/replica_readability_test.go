@@ -0,0 +1,95 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+func TestReconciler_VerifyReplicaReadable(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	c := fake.NewClientBuilder().WithScheme(s).Build()
+	reconciler := createTestReconciler(c, s)
+
+	t.Run("TridentReplicaReadable", func(t *testing.T) {
+		adapter, err := adapters.NewTridentAdapter(c, translation.NewEngine())
+		require.NoError(t, err)
+		uvr := createTestUVR("trident-readable", "default")
+		require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+
+		tmr := &unstructured.Unstructured{}
+		tmr.SetGroupVersionKind(adapters.TridentMirrorRelationshipGVK)
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(uvr), tmr))
+		require.NoError(t, unstructured.SetNestedField(tmr.Object, "established", "status", "state"))
+		require.NoError(t, c.Update(ctx, tmr))
+
+		reconciler.verifyReplicaReadable(ctx, adapter, uvr, logr.Discard())
+		require.NotNil(t, uvr.Status.ReplicaReadable)
+		assert.True(t, uvr.Status.ReplicaReadable.Readable)
+		assert.False(t, uvr.Status.ReplicaReadable.LastVerifiedTime.IsZero())
+
+		// Not re-verified within the interval
+		require.NoError(t, unstructured.SetNestedField(tmr.Object, "", "status", "state"))
+		require.NoError(t, c.Update(ctx, tmr))
+		reconciler.verifyReplicaReadable(ctx, adapter, uvr, logr.Discard())
+		assert.True(t, uvr.Status.ReplicaReadable.Readable)
+
+		uvr.Status.ReplicaReadable.LastVerifiedTime = metav1.NewTime(time.Now().Add(-replicaReadabilityInterval))
+		reconciler.verifyReplicaReadable(ctx, adapter, uvr, logr.Discard())
+		assert.False(t, uvr.Status.ReplicaReadable.Readable)
+
+		// Cleared once this side is promoted
+		uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+		reconciler.verifyReplicaReadable(ctx, adapter, uvr, logr.Discard())
+		assert.Nil(t, uvr.Status.ReplicaReadable)
+	})
+
+	t.Run("CephReplicaNotReadable", func(t *testing.T) {
+		adapter, err := adapters.NewCephAdapter(c, translation.NewEngine())
+		require.NoError(t, err)
+		uvr := createTestUVR("ceph-not-readable", "default")
+
+		reconciler.verifyReplicaReadable(ctx, adapter, uvr, logr.Discard())
+		require.NotNil(t, uvr.Status.ReplicaReadable)
+		assert.False(t, uvr.Status.ReplicaReadable.Readable)
+		assert.NotEmpty(t, uvr.Status.ReplicaReadable.Reason)
+	})
+
+	t.Run("UnsupportedAdapter", func(t *testing.T) {
+		adapter := adapters.NewMockAdapter(translation.BackendPowerStore, c, translation.NewEngine(), nil, &adapters.MockConfig{})
+		uvr := createTestUVR("unsupported", "default")
+		uvr.Status.ReplicaReadable = &replicationv1alpha1.ReplicaReadability{Readable: true}
+
+		reconciler.verifyReplicaReadable(ctx, adapter, uvr, logr.Discard())
+		assert.Nil(t, uvr.Status.ReplicaReadable)
+	})
+}
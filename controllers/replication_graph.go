@@ -0,0 +1,214 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+// ReplicationGraphPath is the metrics server path serving the replication topology
+const ReplicationGraphPath = "/debug/replication-graph"
+
+// defaultReplicationGraphTTL is how long a served graph is reused before the UVRs are listed again
+const defaultReplicationGraphTTL = 30 * time.Second
+
+// Kinds of graph nodes
+const (
+	GraphNodeSite   = "site"
+	GraphNodeVolume = "volume"
+)
+
+// Health of a replication edge
+const (
+	GraphEdgeHealthy     = "Healthy"
+	GraphEdgeDegraded    = "Degraded"
+	GraphEdgeDeactivated = "Deactivated"
+	GraphEdgeUnknown     = "Unknown"
+)
+
+// ReplicationGraph is the replication topology of the cluster: sites and volumes as nodes,
+// and one edge per replicated volume pair pointing from the primary copy to the replica
+type ReplicationGraph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode is a site, identified by its cluster, or a volume at a site
+type GraphNode struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+	// Label is a short display name: the cluster of a site, the PVC or volume handle of a volume
+	Label  string `json:"label"`
+	Region string `json:"region,omitempty"`
+	// Site is the ID of the site node a volume is at
+	Site string `json:"site,omitempty"`
+}
+
+// GraphEdge is the replication of one volume pair, from the volume holding the primary copy
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Replication is the namespace/name of the UVR the edge comes from
+	Replication string `json:"replication"`
+	Backend     string `json:"backend"`
+	Mode        string `json:"mode,omitempty"`
+	// Direction is Reverse when the edge runs from the destination endpoint after a failover
+	Direction replicationv1alpha1.ReplicationDirection `json:"direction"`
+	Health    string                                   `json:"health"`
+}
+
+// BuildReplicationGraph derives the graph of uvrs from their spec and status. Nodes are
+// sorted by ID, edges by replication then source volume.
+func BuildReplicationGraph(uvrs []replicationv1alpha1.UnifiedVolumeReplication) ReplicationGraph {
+	nodes := map[string]GraphNode{}
+	graph := ReplicationGraph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+
+	for i := range uvrs {
+		uvr := &uvrs[i]
+		source := graphSite(nodes, uvr.Spec.SourceEndpoint)
+		destination := graphSite(nodes, uvr.Spec.DestinationEndpoint)
+
+		direction := uvr.Status.Direction
+		if direction == "" {
+			direction = replicationv1alpha1.ReplicationDirectionForward
+		}
+		edge := GraphEdge{
+			Replication: client.ObjectKeyFromObject(uvr).String(),
+			Backend:     backendPartitionFor(uvr),
+			Mode:        string(uvr.Spec.ReplicationMode),
+			Direction:   direction,
+			Health:      graphEdgeHealth(uvr),
+		}
+		if config := uvr.Status.EffectiveConfig; config != nil && config.Backend != "" {
+			edge.Backend = config.Backend
+		}
+
+		mappings := append([]replicationv1alpha1.VolumeMapping{uvr.Spec.VolumeMapping}, uvr.Spec.GroupMembers...)
+		for _, mapping := range mappings {
+			from := graphVolume(nodes, source, mapping.Source.Namespace, mapping.Source.PvcName)
+			to := graphVolume(nodes, destination, mapping.Destination.Namespace, mapping.Destination.VolumeHandle)
+			if direction == replicationv1alpha1.ReplicationDirectionReverse {
+				from, to = to, from
+			}
+			edge.From, edge.To = from, to
+			graph.Edges = append(graph.Edges, edge)
+		}
+	}
+
+	for _, node := range nodes {
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool { return graph.Nodes[i].ID < graph.Nodes[j].ID })
+	sort.SliceStable(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].Replication != graph.Edges[j].Replication {
+			return graph.Edges[i].Replication < graph.Edges[j].Replication
+		}
+		return graph.Edges[i].From < graph.Edges[j].From
+	})
+	return graph
+}
+
+// graphSite adds the site node of an endpoint and returns its ID
+func graphSite(nodes map[string]GraphNode, endpoint replicationv1alpha1.Endpoint) string {
+	id := GraphNodeSite + "/" + endpoint.Cluster
+	if _, ok := nodes[id]; !ok {
+		nodes[id] = GraphNode{ID: id, Kind: GraphNodeSite, Label: endpoint.Cluster, Region: endpoint.Region}
+	}
+	return id
+}
+
+// graphVolume adds the node of a volume at a site and returns its ID
+func graphVolume(nodes map[string]GraphNode, site, namespace, name string) string {
+	id := GraphNodeVolume + "/" + nodes[site].Label + "/" + namespace + "/" + name
+	if _, ok := nodes[id]; !ok {
+		nodes[id] = GraphNode{ID: id, Kind: GraphNodeVolume, Label: name, Region: nodes[site].Region, Site: site}
+	}
+	return id
+}
+
+// graphEdgeHealth summarizes the conditions of a replication for its edges
+func graphEdgeHealth(uvr *replicationv1alpha1.UnifiedVolumeReplication) string {
+	switch {
+	case uvr.Spec.Deactivated:
+		return GraphEdgeDeactivated
+	case apimeta.IsStatusConditionTrue(uvr.Status.Conditions, degradedCondition):
+		return GraphEdgeDegraded
+	case apimeta.IsStatusConditionTrue(uvr.Status.Conditions, "Ready"):
+		return GraphEdgeHealthy
+	default:
+		return GraphEdgeUnknown
+	}
+}
+
+// ReplicationGraphHandler serves the replication graph of all UVRs as JSON. It only reads,
+// and reuses a graph for its TTL so dashboards polling it do not list the UVRs every time.
+type ReplicationGraphHandler struct {
+	reader client.Reader
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	graph   []byte
+	builtAt time.Time
+
+	// now returns the current time; replaced in tests
+	now func() time.Time
+}
+
+// NewReplicationGraphHandler creates a handler listing UVRs from reader, reusing each graph
+// for ttl, or 30s when zero
+func NewReplicationGraphHandler(reader client.Reader, ttl time.Duration) *ReplicationGraphHandler {
+	if ttl <= 0 {
+		ttl = defaultReplicationGraphTTL
+	}
+	return &ReplicationGraphHandler{reader: reader, ttl: ttl, now: time.Now}
+}
+
+// ServeHTTP writes the graph as indented JSON
+func (h *ReplicationGraphHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.graph == nil || h.now().Sub(h.builtAt) >= h.ttl {
+		uvrs := &replicationv1alpha1.UnifiedVolumeReplicationList{}
+		if err := h.reader.List(req.Context(), uvrs); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		body, err := json.MarshalIndent(BuildReplicationGraph(uvrs.Items), "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		h.graph, h.builtAt = append(body, '\n'), h.now()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(h.graph)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+func replicationGraphFixtures() []replicationv1alpha1.UnifiedVolumeReplication {
+	// Replicating as configured
+	db := createTestUVR("db", "prod")
+	db.Spec.VolumeMapping.Source.PvcName = "db-data"
+	db.Spec.VolumeMapping.Destination.VolumeHandle = "db-data-replica"
+	db.Status.Direction = replicationv1alpha1.ReplicationDirectionForward
+	db.Status.Conditions = []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue}}
+
+	// Failed over: the destination holds the primary copy
+	web := createTestUVR("web", "prod")
+	web.Spec.VolumeMapping.Source.PvcName = "web-data"
+	web.Spec.VolumeMapping.Destination.VolumeHandle = "web-data-replica"
+	web.Status.Direction = replicationv1alpha1.ReplicationDirectionReverse
+	web.Status.Conditions = []metav1.Condition{{Type: degradedCondition, Status: metav1.ConditionTrue}}
+	web.Status.EffectiveConfig = &replicationv1alpha1.EffectiveConfig{Backend: "trident"}
+
+	return []replicationv1alpha1.UnifiedVolumeReplication{*web, *db}
+}
+
+func TestBuildReplicationGraph(t *testing.T) {
+	graph := BuildReplicationGraph(replicationGraphFixtures())
+
+	assert.Equal(t, []GraphNode{
+		{ID: "site/dest-cluster", Kind: GraphNodeSite, Label: "dest-cluster", Region: "us-west-1"},
+		{ID: "site/source-cluster", Kind: GraphNodeSite, Label: "source-cluster", Region: "us-east-1"},
+		{ID: "volume/dest-cluster/prod/db-data-replica", Kind: GraphNodeVolume, Label: "db-data-replica", Region: "us-west-1", Site: "site/dest-cluster"},
+		{ID: "volume/dest-cluster/prod/web-data-replica", Kind: GraphNodeVolume, Label: "web-data-replica", Region: "us-west-1", Site: "site/dest-cluster"},
+		{ID: "volume/source-cluster/prod/db-data", Kind: GraphNodeVolume, Label: "db-data", Region: "us-east-1", Site: "site/source-cluster"},
+		{ID: "volume/source-cluster/prod/web-data", Kind: GraphNodeVolume, Label: "web-data", Region: "us-east-1", Site: "site/source-cluster"},
+	}, graph.Nodes, "sites shared by replications appear once")
+
+	assert.Equal(t, []GraphEdge{
+		{
+			From: "volume/source-cluster/prod/db-data", To: "volume/dest-cluster/prod/db-data-replica",
+			Replication: "prod/db", Backend: "trident", Mode: "asynchronous",
+			Direction: replicationv1alpha1.ReplicationDirectionForward, Health: GraphEdgeHealthy,
+		},
+		{
+			From: "volume/dest-cluster/prod/web-data-replica", To: "volume/source-cluster/prod/web-data",
+			Replication: "prod/web", Backend: "trident", Mode: "asynchronous",
+			Direction: replicationv1alpha1.ReplicationDirectionReverse, Health: GraphEdgeDegraded,
+		},
+	}, graph.Edges, "the failed over replication runs from its destination")
+
+	// Each group member is an edge of its own
+	group := createTestUVR("group", "prod")
+	group.Spec.GroupMembers = []replicationv1alpha1.VolumeMapping{{
+		Source:      replicationv1alpha1.VolumeSource{PvcName: "logs", Namespace: "prod"},
+		Destination: replicationv1alpha1.VolumeDestination{VolumeHandle: "logs-replica", Namespace: "prod"},
+	}}
+	graph = BuildReplicationGraph([]replicationv1alpha1.UnifiedVolumeReplication{*group})
+	require.Len(t, graph.Edges, 2)
+	assert.Equal(t, "volume/source-cluster/prod/logs", graph.Edges[0].From)
+	assert.Equal(t, GraphEdgeUnknown, graph.Edges[0].Health)
+	assert.Equal(t, replicationv1alpha1.ReplicationDirectionForward, graph.Edges[0].Direction)
+}
+
+func TestReplicationGraphHandler(t *testing.T) {
+	fixtures := replicationGraphFixtures()
+	c := fake.NewClientBuilder().WithScheme(createTestScheme(t)).WithObjects(&fixtures[0]).Build()
+	handler := NewReplicationGraphHandler(c, time.Minute)
+	now := time.Now()
+	handler.now = func() time.Time { return now }
+
+	get := func() ReplicationGraph {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, ReplicationGraphPath, nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+		graph := ReplicationGraph{}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &graph))
+		return graph
+	}
+
+	assert.Len(t, get().Edges, 1)
+
+	// The graph is reused until its TTL passes
+	require.NoError(t, c.Create(context.Background(), &fixtures[1]))
+	assert.Len(t, get().Edges, 1)
+	now = now.Add(time.Minute)
+	assert.Len(t, get().Edges, 2)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, ReplicationGraphPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+// sourceVolumeIndexField indexes UVRs by the volumes they replicate from, as returned by
+// volumeKey, so the UVRs reading from a volume another UVR writes to can be looked up
+const sourceVolumeIndexField = "spec.sourceVolumes"
+
+// volumeKey identifies a volume across UVRs. Source PVCs and destination volume handles
+// share one namespace of keys: a destination volume is taken to be the same volume as a
+// source PVC of the same name in the same cluster and namespace.
+func volumeKey(cluster, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", cluster, namespace, name)
+}
+
+// replicationMappings returns the primary volume mapping and the group members of a UVR
+func replicationMappings(uvr *replicationv1alpha1.UnifiedVolumeReplication) []replicationv1alpha1.VolumeMapping {
+	return append([]replicationv1alpha1.VolumeMapping{uvr.Spec.VolumeMapping}, uvr.Spec.GroupMembers...)
+}
+
+// indexSourceVolumes is the field indexer for sourceVolumeIndexField
+func indexSourceVolumes(obj client.Object) []string {
+	uvr, ok := obj.(*replicationv1alpha1.UnifiedVolumeReplication)
+	if !ok {
+		return nil
+	}
+	var keys []string
+	for _, mapping := range replicationMappings(uvr) {
+		keys = append(keys, volumeKey(uvr.Spec.SourceEndpoint.Cluster, mapping.Source.Namespace, mapping.Source.PvcName))
+	}
+	return keys
+}
+
+// findReplicationLoop follows the UVRs replicating from the volumes uvr writes to, and from
+// the volumes those write to, until one writes back into a source volume of uvr. It returns
+// the UVRs forming the loop, starting with uvr, or nil when there is none.
+func (r *UnifiedVolumeReplicationReconciler) findReplicationLoop(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) ([]string, error) {
+	origin := make(map[string]bool)
+	for _, key := range indexSourceVolumes(uvr) {
+		origin[key] = true
+	}
+	visited := map[string]bool{client.ObjectKeyFromObject(uvr).String(): true}
+
+	var walk func(current *replicationv1alpha1.UnifiedVolumeReplication, path []string) ([]string, error)
+	walk = func(current *replicationv1alpha1.UnifiedVolumeReplication, path []string) ([]string, error) {
+		for _, mapping := range replicationMappings(current) {
+			target := volumeKey(current.Spec.DestinationEndpoint.Cluster, mapping.Destination.Namespace, mapping.Destination.VolumeHandle)
+			if origin[target] {
+				return path, nil
+			}
+
+			readers := &replicationv1alpha1.UnifiedVolumeReplicationList{}
+			if err := r.List(ctx, readers, client.MatchingFields{sourceVolumeIndexField: target}); err != nil {
+				return nil, fmt.Errorf("failed to list replications reading from %s: %w", target, err)
+			}
+			for i := range readers.Items {
+				next := &readers.Items[i]
+				name := client.ObjectKeyFromObject(next).String()
+				if visited[name] {
+					continue
+				}
+				visited[name] = true
+				if loop, err := walk(next, append(path, name)); err != nil || loop != nil {
+					return loop, err
+				}
+			}
+		}
+		return nil, nil
+	}
+
+	return walk(uvr, []string{client.ObjectKeyFromObject(uvr).String()})
+}
+
+// replicationLoopMessage describes a loop found by findReplicationLoop
+func replicationLoopMessage(loop []string) string {
+	return fmt.Sprintf("Replication loop detected: %s -> %s replicate back into their own source volumes",
+		strings.Join(loop, " -> "), loop[0])
+}
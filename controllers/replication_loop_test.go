@@ -0,0 +1,126 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+// newLoopUVR returns a UVR replicating fromPVC in fromCluster to toVolume in toCluster
+func newLoopUVR(name, fromCluster, fromPVC, toCluster, toVolume string) *replicationv1alpha1.UnifiedVolumeReplication {
+	uvr := createTestUVR(name, "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	uvr.Spec.SourceEndpoint.Cluster = fromCluster
+	uvr.Spec.VolumeMapping.Source.PvcName = fromPVC
+	uvr.Spec.DestinationEndpoint.Cluster = toCluster
+	uvr.Spec.VolumeMapping.Destination.VolumeHandle = toVolume
+	return uvr
+}
+
+func newLoopTestReconciler(t *testing.T, objects ...client.Object) *UnifiedVolumeReplicationReconciler {
+	s := createTestScheme(t)
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(objects...).WithStatusSubresource(objects...).
+		WithIndex(&replicationv1alpha1.UnifiedVolumeReplication{}, sourceVolumeIndexField, indexSourceVolumes).Build()
+	return createTestReconciler(c, s)
+}
+
+func TestReconciler_FindReplicationLoop(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("TwoReplicationCycle", func(t *testing.T) {
+		forward := newLoopUVR("forward", "east", "data", "west", "data")
+		backward := newLoopUVR("backward", "west", "data", "east", "data")
+		reconciler := newLoopTestReconciler(t, forward, backward)
+
+		loop, err := reconciler.findReplicationLoop(ctx, forward)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"default/forward", "default/backward"}, loop)
+
+		loop, err = reconciler.findReplicationLoop(ctx, backward)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"default/backward", "default/forward"}, loop)
+	})
+
+	t.Run("ThreeReplicationCycle", func(t *testing.T) {
+		first := newLoopUVR("first", "east", "data", "west", "data")
+		second := newLoopUVR("second", "west", "data", "central", "data")
+		third := newLoopUVR("third", "central", "data", "east", "data")
+		reconciler := newLoopTestReconciler(t, first, second, third)
+
+		loop, err := reconciler.findReplicationLoop(ctx, first)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"default/first", "default/second", "default/third"}, loop)
+	})
+
+	t.Run("CycleThroughGroupMember", func(t *testing.T) {
+		forward := newLoopUVR("forward", "east", "data", "west", "data")
+		forward.Spec.GroupMembers = []replicationv1alpha1.VolumeMapping{{
+			Source:      replicationv1alpha1.VolumeSource{PvcName: "logs", Namespace: "default"},
+			Destination: replicationv1alpha1.VolumeDestination{VolumeHandle: "logs", Namespace: "default"},
+		}}
+		backward := newLoopUVR("backward", "west", "logs", "east", "logs")
+		reconciler := newLoopTestReconciler(t, forward, backward)
+
+		loop, err := reconciler.findReplicationLoop(ctx, forward)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"default/forward", "default/backward"}, loop)
+	})
+
+	t.Run("CascadeWithoutCycle", func(t *testing.T) {
+		first := newLoopUVR("first", "east", "data", "west", "data")
+		second := newLoopUVR("second", "west", "data", "central", "data")
+		unrelated := newLoopUVR("unrelated", "central", "other", "east", "data")
+		reconciler := newLoopTestReconciler(t, first, second, unrelated)
+
+		loop, err := reconciler.findReplicationLoop(ctx, first)
+		require.NoError(t, err)
+		assert.Nil(t, loop)
+	})
+}
+
+func TestReconciler_ReplicationLoopBlocksReconcile(t *testing.T) {
+	ctx := context.Background()
+	forward := newLoopUVR("forward", "east", "data", "west", "data")
+	backward := newLoopUVR("backward", "west", "data", "east", "data")
+	reconciler := newLoopTestReconciler(t, forward, backward)
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(forward)}
+	result, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, requeueDelaySuccess, result.RequeueAfter)
+
+	current := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, reconciler.Get(ctx, req.NamespacedName, current))
+	ready := reconciler.getCondition(current, "Ready")
+	require.NotNil(t, ready)
+	assert.Equal(t, "ReplicationLoop", ready.Reason)
+	assert.Contains(t, ready.Message, "default/forward -> default/backward -> default/forward")
+
+	events := drainEvents(reconciler.Recorder.(*record.FakeRecorder))
+	require.NotEmpty(t, events)
+	assert.Contains(t, events[len(events)-1], "ReplicationLoop")
+}
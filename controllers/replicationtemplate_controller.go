@@ -0,0 +1,263 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+// ReplicationTemplateLabel names the ReplicationTemplate a UVR was generated from
+const ReplicationTemplateLabel = "replication.unified.io/template"
+
+// ReplicationTemplateReconciler keeps one UVR per PVC a ReplicationTemplate selects, in sync
+// with the template
+type ReplicationTemplateReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *ReplicationTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&replicationv1alpha1.ReplicationTemplate{}).
+		Owns(&replicationv1alpha1.UnifiedVolumeReplication{}).
+		Watches(&corev1.PersistentVolumeClaim{},
+			handler.EnqueueRequestsFromMapFunc(r.templatesForClaim)).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups=replication.unified.io,resources=replicationtemplates,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=replication.unified.io,resources=replicationtemplates/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=replication.unified.io,resources=replicationtemplates/finalizers,verbs=update
+
+// Reconcile creates a UVR for every selected PVC that has none, brings the generated UVRs in
+// line with the template and deletes those whose PVC is no longer selected. The generated
+// UVRs are owned by the template, so deleting the template deletes them too.
+func (r *ReplicationTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("replicationtemplate", req.NamespacedName)
+
+	tmpl := &replicationv1alpha1.ReplicationTemplate{}
+	if err := r.Get(ctx, req.NamespacedName, tmpl); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !tmpl.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&tmpl.Spec.Selector)
+	if err != nil {
+		// Nothing can be selected until the spec is fixed, which triggers a new reconcile
+		apimeta.SetStatusCondition(&tmpl.Status.Conditions, metav1.Condition{
+			Type:               "Ready",
+			Status:             metav1.ConditionFalse,
+			Reason:             "InvalidSelector",
+			Message:            err.Error(),
+			ObservedGeneration: tmpl.Generation,
+		})
+		tmpl.Status.ObservedGeneration = tmpl.Generation
+		return ctrl.Result{}, r.Status().Update(ctx, tmpl)
+	}
+
+	pvcs := &corev1.PersistentVolumeClaimList{}
+	if err := r.List(ctx, pvcs, client.InNamespace(tmpl.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list selected claims: %w", err)
+	}
+	labelled := &replicationv1alpha1.UnifiedVolumeReplicationList{}
+	if err := r.List(ctx, labelled, client.InNamespace(tmpl.Namespace), client.MatchingLabels{ReplicationTemplateLabel: tmpl.Name}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list generated replications: %w", err)
+	}
+	generated := make(map[string]*replicationv1alpha1.UnifiedVolumeReplication, len(labelled.Items))
+	for i := range labelled.Items {
+		if metav1.IsControlledBy(&labelled.Items[i], tmpl) {
+			generated[labelled.Items[i].Name] = &labelled.Items[i]
+		}
+	}
+
+	var names, conflicts []string
+	selected := make(map[string]bool, len(pvcs.Items))
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+		if !pvc.DeletionTimestamp.IsZero() {
+			continue
+		}
+		desired, err := r.templatedReplication(tmpl, pvc)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		selected[desired.Name] = true
+
+		existing, ok := generated[desired.Name]
+		if !ok {
+			if err := r.Create(ctx, desired); err != nil {
+				if !apierrors.IsAlreadyExists(err) {
+					return ctrl.Result{}, fmt.Errorf("failed to create replication %s: %w", desired.Name, err)
+				}
+				// A replication the template does not own already has the name; leave it alone
+				conflicts = append(conflicts, desired.Name)
+				continue
+			}
+			log.Info("Created replication from template", "replication", desired.Name, "pvc", pvc.Name)
+			r.Recorder.Eventf(tmpl, corev1.EventTypeNormal, "ReplicationCreated", "Created replication %s for PVC %s", desired.Name, pvc.Name)
+		} else if existing.DeletionTimestamp.IsZero() && applyReplicationTemplate(existing, desired) {
+			if err := r.Update(ctx, existing); err != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to update replication %s: %w", existing.Name, err)
+			}
+			log.Info("Updated replication from template", "replication", existing.Name)
+			r.Recorder.Eventf(tmpl, corev1.EventTypeNormal, "ReplicationUpdated", "Updated replication %s to match the template", existing.Name)
+		}
+		names = append(names, desired.Name)
+	}
+
+	for name, uvr := range generated {
+		if selected[name] || !uvr.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if err := r.Delete(ctx, uvr); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to delete replication %s: %w", name, err)
+		}
+		log.Info("Deleted replication no longer selected by template", "replication", name)
+		r.Recorder.Eventf(tmpl, corev1.EventTypeNormal, "ReplicationDeleted", "Deleted replication %s, its PVC is no longer selected", name)
+	}
+
+	sort.Strings(names)
+	sort.Strings(conflicts)
+	tmpl.Status.Replications = names
+	tmpl.Status.ReplicationCount = int32(len(names))
+	ready := metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionTrue,
+		Reason:             "Synced",
+		Message:            fmt.Sprintf("%d replications generated", len(names)),
+		ObservedGeneration: tmpl.Generation,
+	}
+	if len(conflicts) > 0 {
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "NameConflict"
+		ready.Message = fmt.Sprintf("replications not owned by the template already exist: %s", strings.Join(conflicts, ", "))
+	}
+	if existing := apimeta.FindStatusCondition(tmpl.Status.Conditions, "Ready"); len(conflicts) > 0 &&
+		(existing == nil || existing.Message != ready.Message) {
+		r.Recorder.Eventf(tmpl, corev1.EventTypeWarning, "NameConflict", "%s", ready.Message)
+	}
+	apimeta.SetStatusCondition(&tmpl.Status.Conditions, ready)
+	tmpl.Status.ObservedGeneration = tmpl.Generation
+	if err := r.Status().Update(ctx, tmpl); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update template status: %w", err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// templatedReplication returns the UVR the template generates for a PVC: named after both,
+// replicating the PVC to a volume of the same name, and controlled by the template
+func (r *ReplicationTemplateReconciler) templatedReplication(tmpl *replicationv1alpha1.ReplicationTemplate, pvc *corev1.PersistentVolumeClaim) (*replicationv1alpha1.UnifiedVolumeReplication, error) {
+	spec := tmpl.Spec.Template.DeepCopy()
+	destinationNamespace := spec.DestinationNamespace
+	if destinationNamespace == "" {
+		destinationNamespace = tmpl.Namespace
+	}
+	labels := maps.Clone(spec.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[ReplicationTemplateLabel] = tmpl.Name
+
+	uvr := &replicationv1alpha1.UnifiedVolumeReplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tmpl.Name + "-" + pvc.Name,
+			Namespace: tmpl.Namespace,
+			Labels:    labels,
+		},
+		Spec: replicationv1alpha1.UnifiedVolumeReplicationSpec{
+			SourceEndpoint:      spec.SourceEndpoint,
+			DestinationEndpoint: spec.DestinationEndpoint,
+			VolumeMapping: replicationv1alpha1.VolumeMapping{
+				Source:      replicationv1alpha1.VolumeSource{PvcName: pvc.Name, Namespace: pvc.Namespace},
+				Destination: replicationv1alpha1.VolumeDestination{VolumeHandle: pvc.Name, Namespace: destinationNamespace},
+			},
+			ReplicationState: spec.ReplicationState,
+			ReplicationMode:  spec.ReplicationMode,
+			Schedule:         spec.Schedule,
+			Extensions:       spec.Extensions,
+		},
+	}
+	if err := controllerutil.SetControllerReference(tmpl, uvr, r.Scheme); err != nil {
+		return nil, fmt.Errorf("failed to set the owner of replication %s: %w", uvr.Name, err)
+	}
+	return uvr, nil
+}
+
+// applyReplicationTemplate copies the templated fields and labels of desired onto a
+// generated UVR, leaving the rest of its spec alone, and reports whether anything changed.
+// The replication state is only set at creation: a failover of the generated UVR is its own,
+// and copying the template state back would demote it again.
+func applyReplicationTemplate(uvr, desired *replicationv1alpha1.UnifiedVolumeReplication) bool {
+	updated := uvr.DeepCopy()
+	updated.Spec.SourceEndpoint = desired.Spec.SourceEndpoint
+	updated.Spec.DestinationEndpoint = desired.Spec.DestinationEndpoint
+	updated.Spec.VolumeMapping = desired.Spec.VolumeMapping
+	updated.Spec.ReplicationMode = desired.Spec.ReplicationMode
+	updated.Spec.Schedule = desired.Spec.Schedule
+	updated.Spec.Extensions = desired.Spec.Extensions
+	if updated.Labels == nil {
+		updated.Labels = map[string]string{}
+	}
+	maps.Copy(updated.Labels, desired.Labels)
+
+	if equality.Semantic.DeepEqual(uvr.Spec, updated.Spec) && equality.Semantic.DeepEqual(uvr.Labels, updated.Labels) {
+		return false
+	}
+	uvr.Spec = updated.Spec
+	uvr.Labels = updated.Labels
+	return true
+}
+
+// templatesForClaim maps a PVC to every template in its namespace: a PVC whose labels no
+// longer match must reach the template that selected it, which its new labels cannot tell
+func (r *ReplicationTemplateReconciler) templatesForClaim(ctx context.Context, obj client.Object) []reconcile.Request {
+	templates := &replicationv1alpha1.ReplicationTemplateList{}
+	if err := r.List(ctx, templates, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "Failed to list templates for claim", "pvc", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(templates.Items))
+	for _, tmpl := range templates.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&tmpl)})
+	}
+	return requests
+}
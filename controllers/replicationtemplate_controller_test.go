@@ -0,0 +1,176 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+func createTestTemplate() *replicationv1alpha1.ReplicationTemplate {
+	return &replicationv1alpha1.ReplicationTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "default", UID: "shop-uid"},
+		Spec: replicationv1alpha1.ReplicationTemplateSpec{
+			Selector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "shop"}},
+			Template: replicationv1alpha1.ReplicationSpecTemplate{
+				Labels: map[string]string{"tier": "data"},
+				SourceEndpoint: replicationv1alpha1.Endpoint{
+					Cluster: "source-cluster", Region: "us-east-1", StorageClass: "trident-nas",
+				},
+				DestinationEndpoint: replicationv1alpha1.Endpoint{
+					Cluster: "dest-cluster", Region: "us-west-1", StorageClass: "trident-nas",
+				},
+				DestinationNamespace: "dr",
+				ReplicationState:     replicationv1alpha1.ReplicationStateReplica,
+				ReplicationMode:      replicationv1alpha1.ReplicationModeAsynchronous,
+				Schedule: replicationv1alpha1.Schedule{
+					Rpo: "15m", Rto: "5m", Mode: replicationv1alpha1.ScheduleModeContinuous,
+				},
+			},
+		},
+	}
+}
+
+func createTemplatedPVC(name string, labels map[string]string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels}}
+}
+
+func TestReplicationTemplate_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	tmpl := createTestTemplate()
+	db := createTemplatedPVC("db", map[string]string{"app": "shop"})
+	cache := createTemplatedPVC("cache", map[string]string{"app": "shop"})
+	unrelated := createTemplatedPVC("logs", map[string]string{"app": "other"})
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithObjects(tmpl, db, cache, unrelated).
+		WithStatusSubresource(tmpl).Build()
+	reconciler := &ReplicationTemplateReconciler{
+		Client:   c,
+		Log:      ctrl.Log.WithName("test").WithName("ReplicationTemplate"),
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
+	}
+	recorder := reconciler.Recorder.(*record.FakeRecorder)
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(tmpl)}
+	reconcile := func(t *testing.T) *replicationv1alpha1.ReplicationTemplate {
+		t.Helper()
+		_, err := reconciler.Reconcile(ctx, req)
+		require.NoError(t, err)
+		current := &replicationv1alpha1.ReplicationTemplate{}
+		require.NoError(t, c.Get(ctx, req.NamespacedName, current))
+		return current
+	}
+	getUVR := func(t *testing.T, name string) *replicationv1alpha1.UnifiedVolumeReplication {
+		t.Helper()
+		uvr := &replicationv1alpha1.UnifiedVolumeReplication{}
+		require.NoError(t, c.Get(ctx, client.ObjectKey{Name: name, Namespace: "default"}, uvr))
+		return uvr
+	}
+
+	t.Run("CreatesReplicationPerSelectedPVC", func(t *testing.T) {
+		current := reconcile(t)
+		assert.Equal(t, []string{"shop-cache", "shop-db"}, current.Status.Replications)
+		assert.Equal(t, int32(2), current.Status.ReplicationCount)
+		ready := apimeta.FindStatusCondition(current.Status.Conditions, "Ready")
+		require.NotNil(t, ready)
+		assert.Equal(t, metav1.ConditionTrue, ready.Status)
+
+		uvr := getUVR(t, "shop-db")
+		assert.Equal(t, replicationv1alpha1.VolumeSource{PvcName: "db", Namespace: "default"}, uvr.Spec.VolumeMapping.Source)
+		assert.Equal(t, replicationv1alpha1.VolumeDestination{VolumeHandle: "db", Namespace: "dr"}, uvr.Spec.VolumeMapping.Destination)
+		assert.Equal(t, replicationv1alpha1.ReplicationStateReplica, uvr.Spec.ReplicationState)
+		assert.Equal(t, map[string]string{"tier": "data", ReplicationTemplateLabel: "shop"}, uvr.Labels)
+		assert.True(t, metav1.IsControlledBy(uvr, tmpl), "the template owns what it generates")
+
+		err := c.Get(ctx, client.ObjectKey{Name: "shop-logs", Namespace: "default"}, &replicationv1alpha1.UnifiedVolumeReplication{})
+		assert.True(t, apierrors.IsNotFound(err), "unselected PVCs are not replicated")
+		assert.Len(t, drainEvents(recorder), 2)
+
+		// A second pass has nothing to do
+		reconcile(t)
+		assert.Empty(t, drainEvents(recorder))
+	})
+
+	t.Run("UpdatesReplicationsOnTemplateChange", func(t *testing.T) {
+		// Fields outside the template are the replication's own and survive the update, and
+		// so does a failover
+		uvr := getUVR(t, "shop-db")
+		uvr.Spec.Priority = 10
+		uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStatePromoting
+		require.NoError(t, c.Update(ctx, uvr))
+
+		current := &replicationv1alpha1.ReplicationTemplate{}
+		require.NoError(t, c.Get(ctx, req.NamespacedName, current))
+		current.Spec.Template.Schedule.Rpo = "5m"
+		current.Spec.Template.Labels["tier"] = "critical"
+		require.NoError(t, c.Update(ctx, current))
+		reconcile(t)
+
+		for _, name := range []string{"shop-cache", "shop-db"} {
+			uvr := getUVR(t, name)
+			assert.Equal(t, "5m", uvr.Spec.Schedule.Rpo, name)
+			assert.Equal(t, "critical", uvr.Labels["tier"], name)
+		}
+		assert.Equal(t, int32(10), getUVR(t, "shop-db").Spec.Priority)
+		assert.Equal(t, replicationv1alpha1.ReplicationStatePromoting, getUVR(t, "shop-db").Spec.ReplicationState,
+			"the template does not revert a failover")
+		assert.Contains(t, drainEvents(recorder), "Normal ReplicationUpdated Updated replication shop-db to match the template")
+	})
+
+	t.Run("DeletesReplicationsNoLongerSelected", func(t *testing.T) {
+		pvc := &corev1.PersistentVolumeClaim{}
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(cache), pvc))
+		pvc.Labels = map[string]string{"app": "other"}
+		require.NoError(t, c.Update(ctx, pvc))
+
+		current := reconcile(t)
+		err := c.Get(ctx, client.ObjectKey{Name: "shop-cache", Namespace: "default"}, &replicationv1alpha1.UnifiedVolumeReplication{})
+		assert.True(t, apierrors.IsNotFound(err))
+		getUVR(t, "shop-db")
+		assert.Equal(t, []string{"shop-db"}, current.Status.Replications)
+		assert.Equal(t, []string{"Normal ReplicationDeleted Deleted replication shop-cache, its PVC is no longer selected"}, drainEvents(recorder))
+	})
+
+	t.Run("LeavesReplicationsItDoesNotOwn", func(t *testing.T) {
+		taken := createTestUVR("shop-web", "default")
+		require.NoError(t, c.Create(ctx, taken))
+		require.NoError(t, c.Create(ctx, createTemplatedPVC("web", map[string]string{"app": "shop"})))
+
+		current := reconcile(t)
+		assert.Equal(t, []string{"shop-db"}, current.Status.Replications)
+		ready := apimeta.FindStatusCondition(current.Status.Conditions, "Ready")
+		require.NotNil(t, ready)
+		assert.Equal(t, metav1.ConditionFalse, ready.Status)
+		assert.Equal(t, "NameConflict", ready.Reason)
+		assert.Equal(t, "source-pvc", getUVR(t, "shop-web").Spec.VolumeMapping.Source.PvcName)
+	})
+}
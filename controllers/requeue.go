@@ -0,0 +1,83 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+const (
+	// Bounds of the success requeue delay derived from the RPO
+	minSuccessRequeueDelay = 15 * time.Second
+	maxSuccessRequeueDelay = 10 * time.Minute
+
+	// successRequeueRPOFraction checks a healthy replication several times per RPO, so a
+	// sync lag breach is noticed well before the next RPO window ends
+	successRequeueRPOFraction = 4
+)
+
+// successRequeueDelay returns how long to wait before re-checking a healthy replication.
+// The delay is a fraction of the RPO; synchronous replications are never checked less
+// often than requeueDelaySuccess, since any lag means they are out of sync. A replication
+// within spec.schedule.maxLag is checked again no later than when the lag would exceed it,
+// so WritesShouldPause is raised on time.
+func successRequeueDelay(uvr *replicationv1alpha1.UnifiedVolumeReplication) time.Duration {
+	delay := rpoRequeueDelay(uvr)
+	if remaining, ok := remainingLagBudget(uvr); ok && remaining < delay {
+		return remaining
+	}
+	return delay
+}
+
+// rpoRequeueDelay returns the success requeue delay derived from the RPO and the mode
+func rpoRequeueDelay(uvr *replicationv1alpha1.UnifiedVolumeReplication) time.Duration {
+	delay := requeueDelaySuccess
+	if rpo, err := parseScheduleDuration(uvr.Spec.Schedule.Rpo); err == nil && rpo > 0 {
+		delay = rpo / successRequeueRPOFraction
+	}
+
+	maxDelay := maxSuccessRequeueDelay
+	if uvr.Spec.ReplicationMode == replicationv1alpha1.ReplicationModeSynchronous {
+		maxDelay = requeueDelaySuccess
+	}
+
+	switch {
+	case delay < minSuccessRequeueDelay:
+		return minSuccessRequeueDelay
+	case delay > maxDelay:
+		return maxDelay
+	default:
+		return delay
+	}
+}
+
+// remainingLagBudget returns how long until the time since the last sync exceeds
+// spec.schedule.maxLag, rounded up to the second the lag is compared at. It reports false
+// without a maximum lag or a sync time, and once the lag has already been exceeded.
+func remainingLagBudget(uvr *replicationv1alpha1.UnifiedVolumeReplication) (time.Duration, bool) {
+	maxLag, err := parseScheduleDuration(uvr.Spec.Schedule.MaxLag)
+	if uvr.Spec.Schedule.MaxLag == "" || err != nil || maxLag <= 0 || uvr.Status.LastSyncTime == nil {
+		return 0, false
+	}
+	remaining := maxLag - time.Since(uvr.Status.LastSyncTime.Time)
+	if remaining <= 0 {
+		return 0, false
+	}
+	return remaining.Truncate(time.Second) + time.Second, true
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+func TestSuccessRequeueDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     replicationv1alpha1.ReplicationMode
+		rpo      string
+		expected time.Duration
+	}{
+		{"sync short RPO", replicationv1alpha1.ReplicationModeSynchronous, "1m", minSuccessRequeueDelay},
+		{"sync capped", replicationv1alpha1.ReplicationModeSynchronous, "15m", requeueDelaySuccess},
+		{"async fraction of RPO", replicationv1alpha1.ReplicationModeAsynchronous, "15m", 225 * time.Second},
+		{"async hourly RPO", replicationv1alpha1.ReplicationModeAsynchronous, "1h", maxSuccessRequeueDelay},
+		{"async daily RPO", replicationv1alpha1.ReplicationModeAsynchronous, "1d", maxSuccessRequeueDelay},
+		{"no RPO", replicationv1alpha1.ReplicationModeAsynchronous, "", requeueDelaySuccess},
+		{"invalid RPO", replicationv1alpha1.ReplicationModeAsynchronous, "soon", requeueDelaySuccess},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uvr := createTestUVR("requeue", "default")
+			uvr.Spec.ReplicationMode = tt.mode
+			uvr.Spec.Schedule.Rpo = tt.rpo
+			assert.Equal(t, tt.expected, successRequeueDelay(uvr))
+		})
+	}
+
+	t.Run("CappedAtRemainingMaxLag", func(t *testing.T) {
+		uvr := createTestUVR("max-lag", "default")
+		uvr.Spec.ReplicationMode = replicationv1alpha1.ReplicationModeAsynchronous
+		uvr.Spec.Schedule.Rpo = "1h"
+		uvr.Spec.Schedule.MaxLag = "20m"
+		lastSync := metav1.NewTime(time.Now().Add(-15 * time.Minute))
+		uvr.Status.LastSyncTime = &lastSync
+
+		delay := successRequeueDelay(uvr)
+		assert.LessOrEqual(t, delay, 5*time.Minute+time.Second, "the check lands when the lag reaches maxLag")
+		assert.Greater(t, delay, 4*time.Minute)
+
+		// A lag already past maxLag keeps the RPO delay, a budget shorter than the minimum
+		// delay is still honoured
+		exceeded := metav1.NewTime(time.Now().Add(-30 * time.Minute))
+		uvr.Status.LastSyncTime = &exceeded
+		assert.Equal(t, maxSuccessRequeueDelay, successRequeueDelay(uvr))
+
+		almost := metav1.NewTime(time.Now().Add(-20*time.Minute + 5*time.Second))
+		uvr.Status.LastSyncTime = &almost
+		assert.Less(t, successRequeueDelay(uvr), minSuccessRequeueDelay)
+	})
+
+	t.Run("SyncCheckedMoreOftenThanHourlyAsync", func(t *testing.T) {
+		syncUVR := createTestUVR("sync", "default")
+		syncUVR.Spec.ReplicationMode = replicationv1alpha1.ReplicationModeSynchronous
+		asyncUVR := createTestUVR("async", "default")
+		asyncUVR.Spec.ReplicationMode = replicationv1alpha1.ReplicationModeAsynchronous
+		asyncUVR.Spec.Schedule.Rpo = "1h"
+		assert.Less(t, successRequeueDelay(syncUVR), successRequeueDelay(asyncUVR))
+	})
+}
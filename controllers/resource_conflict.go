@@ -0,0 +1,107 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// resourceConflictCondition is True while the UVR's backend resource belongs to another UVR
+const resourceConflictCondition = "ResourceConflict"
+
+// handleResourceConflict stops a UVR whose backend resource name is already used by another
+// UVR's resource, so the two do not overwrite each other's backend state. The UVR waits for
+// the conflict to be resolved by renaming or deleting one of them. It returns false when err
+// is not a resource conflict.
+func (r *UnifiedVolumeReplicationReconciler) handleResourceConflict(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, err error, log logr.Logger) (ctrl.Result, bool) {
+	conflict, ok := adapters.AsResourceConflictError(err)
+	if !ok {
+		return ctrl.Result{}, false
+	}
+	r.setResourceConflict(ctx, uvr, conflict, log)
+
+	r.recordFailedReconcile(uvr)
+	if err := r.Status().Update(ctx, uvr); err != nil {
+		log.Error(err, "Failed to update status")
+	}
+	return ctrl.Result{RequeueAfter: requeueDelaySuccess}, true
+}
+
+// setResourceConflict reports the UVR that owns the backend resource in the ResourceConflict
+// and Ready conditions
+func (r *UnifiedVolumeReplicationReconciler) setResourceConflict(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, conflict *adapters.ResourceConflictError, log logr.Logger) {
+	owner := r.resourceOwnerName(ctx, uvr.Namespace, conflict.OwnerUID)
+	message := fmt.Sprintf("%s is owned by %s", conflict.Resource, owner)
+	log.Info("Backend resource belongs to another UVR", "resource", conflict.Resource, "owner", owner)
+
+	if existing := r.getCondition(uvr, resourceConflictCondition); existing == nil ||
+		existing.Status != metav1.ConditionTrue || existing.Message != message {
+		r.recordEventf(uvr, corev1.EventTypeWarning, "ResourceConflict", "%s", message)
+	}
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               resourceConflictCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "OwnedByAnotherReplication",
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             "ResourceConflict",
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+}
+
+// resourceOwnerName names the UVR with the given UID, which owns a backend resource
+func (r *UnifiedVolumeReplicationReconciler) resourceOwnerName(ctx context.Context, namespace string, uid types.UID) string {
+	uvrs := &replicationv1alpha1.UnifiedVolumeReplicationList{}
+	if err := r.List(ctx, uvrs, client.InNamespace(namespace)); err == nil {
+		for _, uvr := range uvrs.Items {
+			if uvr.UID == uid {
+				return fmt.Sprintf("UnifiedVolumeReplication %s/%s", uvr.Namespace, uvr.Name)
+			}
+		}
+	}
+	return fmt.Sprintf("UnifiedVolumeReplication with uid %s, which no longer exists", uid)
+}
+
+// clearResourceConflict reports that the UVR's backend resource is its own again
+func (r *UnifiedVolumeReplicationReconciler) clearResourceConflict(uvr *replicationv1alpha1.UnifiedVolumeReplication) {
+	if existing := r.getCondition(uvr, resourceConflictCondition); existing == nil || existing.Status != metav1.ConditionTrue {
+		return
+	}
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               resourceConflictCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             "ResourceOwned",
+		Message:            "The backend resource belongs to this replication",
+		ObservedGeneration: uvr.Generation,
+	})
+}
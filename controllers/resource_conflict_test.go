@@ -0,0 +1,134 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestReconciler_ResourceConflictBlocksSecondUVR(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+	s.AddKnownTypes(schema.GroupVersion{Group: "replication.storage.openshift.io", Version: "v1alpha1"},
+		&adapters.VolumeReplication{}, &adapters.VolumeReplicationList{})
+
+	newCephUVR := func(name string, uid types.UID) *replicationv1alpha1.UnifiedVolumeReplication {
+		uvr := createTestUVR(name, "default")
+		uvr.UID = uid
+		uvr.Finalizers = []string{unifiedReplicationFinalizer}
+		uvr.Spec.Extensions = &replicationv1alpha1.Extensions{Ceph: &replicationv1alpha1.CephExtensions{}}
+		uvr.Spec.SourceEndpoint.StorageClass = "ceph-rbd"
+		uvr.Spec.DestinationEndpoint.StorageClass = "ceph-rbd"
+		return uvr
+	}
+	first := newCephUVR("db", "uid-db")
+	// The second UVR names the first's VolumeReplication, and wants it promoted
+	second := newCephUVR("db-copy", "uid-db-copy")
+	second.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+	second.Annotations = map[string]string{adapters.AdoptAnnotation: "db-vr"}
+
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(cephCRDs(), first, second)...).
+		WithStatusSubresource(first, second).Build()
+	reconciler := createTestReconciler(c, s)
+
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(first)})
+	require.NoError(t, err)
+	vr := &adapters.VolumeReplication{}
+	vrKey := client.ObjectKey{Name: "db-vr", Namespace: "default"}
+	require.NoError(t, c.Get(ctx, vrKey, vr))
+	assert.Equal(t, "uid-db", vr.Labels[adapters.OwnerUIDLabel])
+
+	result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(second)})
+	require.NoError(t, err)
+	assert.Equal(t, requeueDelayError, result.RequeueAfter)
+
+	updated := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(second), updated))
+	conflict := reconciler.getCondition(updated, resourceConflictCondition)
+	require.NotNil(t, conflict)
+	assert.Equal(t, metav1.ConditionTrue, conflict.Status)
+	assert.Contains(t, conflict.Message, "UnifiedVolumeReplication default/db")
+	assert.Equal(t, "ResourceConflict", reconciler.getCondition(updated, "Ready").Reason)
+
+	require.NoError(t, c.Get(ctx, vrKey, vr))
+	assert.Equal(t, "secondary", vr.Spec.ReplicationState, "the second UVR did not promote the first's volume")
+	assert.Empty(t, vr.Annotations[adapters.AdoptedByAnnotation])
+
+	// Deleting the second UVR leaves the first's VolumeReplication in place
+	require.NoError(t, c.Delete(ctx, updated))
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(second)})
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, vrKey, vr))
+}
+
+func TestReconciler_ResourceConflictOnEnsure(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+	s.AddKnownTypes(schema.GroupVersion{Group: "replication.storage.openshift.io", Version: "v1alpha1"},
+		&adapters.VolumeReplication{}, &adapters.VolumeReplicationList{})
+
+	uvr := createTestUVR("db", "default")
+	uvr.UID = "uid-new"
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	uvr.Spec.Extensions = &replicationv1alpha1.Extensions{Ceph: &replicationv1alpha1.CephExtensions{}}
+	uvr.Spec.SourceEndpoint.StorageClass = "ceph-rbd"
+	uvr.Spec.DestinationEndpoint.StorageClass = "ceph-rbd"
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+
+	// Left behind by an earlier UVR of the same name whose finalizer was removed
+	stale := &adapters.VolumeReplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-vr", Namespace: "default",
+			Labels: map[string]string{adapters.OwnerUIDLabel: "uid-old"}},
+		Spec: adapters.VolumeReplicationSpec{
+			VolumeReplicationClass: "rbd-volumereplicationclass",
+			PvcName:                "source-pvc",
+			ReplicationState:       "secondary",
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(cephCRDs(), uvr, stale)...).
+		WithStatusSubresource(uvr).Build()
+	reconciler := createTestReconciler(c, s)
+
+	result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)})
+	require.NoError(t, err)
+	assert.Equal(t, requeueDelaySuccess, result.RequeueAfter)
+
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(uvr), uvr))
+	conflict := reconciler.getCondition(uvr, resourceConflictCondition)
+	require.NotNil(t, conflict)
+	assert.Contains(t, conflict.Message, "uid uid-old, which no longer exists")
+
+	vr := &adapters.VolumeReplication{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(stale), vr))
+	assert.Equal(t, "secondary", vr.Spec.ReplicationState)
+}
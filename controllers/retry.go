@@ -18,6 +18,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -25,13 +26,20 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 )
 
+// defaultJitterFactor is the jitter applied when a strategy leaves JitterFactor unset
+const defaultJitterFactor = 0.1
+
 // RetryStrategy defines retry behavior
 type RetryStrategy struct {
-	MaxAttempts     int
-	InitialDelay    time.Duration
-	MaxDelay        time.Duration
-	Multiplier      float64
-	Jitter          float64
+	MaxAttempts  int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+	// JitterFactor is the fraction of each backoff delay that is randomized away, so
+	// replications retrying after a shared outage spread out: a delay d is shortened to
+	// a random value in [d*(1-JitterFactor), d]. 1 is full jitter. Unset means 0.1, a
+	// negative value disables jitter.
+	JitterFactor    float64
 	RetryableErrors []string
 }
 
@@ -42,7 +50,7 @@ func DefaultRetryStrategy() *RetryStrategy {
 		InitialDelay: 1 * time.Second,
 		MaxDelay:     5 * time.Minute,
 		Multiplier:   2.0,
-		Jitter:       0.1,
+		JitterFactor: defaultJitterFactor,
 		RetryableErrors: []string{
 			"connection refused",
 			"timeout",
@@ -123,55 +131,76 @@ func (rm *RetryManager) GetNextDelay(resourceKey string) time.Duration {
 	attempts := rm.attempts[resourceKey]
 	rm.attemptsMutex.RUnlock()
 
-	if attempts == 0 {
-		return rm.strategy.InitialDelay
-	}
+	return rm.backoffDelay(attempts)
+}
 
-	// Exponential backoff
-	delay := time.Duration(float64(rm.strategy.InitialDelay) *
-		pow(rm.strategy.Multiplier, float64(attempts-1)))
+// backoffDelay returns the jittered delay before the retry following attempts attempts
+func (rm *RetryManager) backoffDelay(attempts int) time.Duration {
+	delay := rm.strategy.InitialDelay
+	if attempts > 0 {
+		// Exponential backoff
+		delay = time.Duration(float64(rm.strategy.InitialDelay) *
+			pow(rm.strategy.Multiplier, float64(attempts-1)))
+	}
 
 	// Cap at max delay
-	if delay > rm.strategy.MaxDelay {
+	if rm.strategy.MaxDelay > 0 && delay > rm.strategy.MaxDelay {
 		delay = rm.strategy.MaxDelay
 	}
 
-	// Add jitter
-	if rm.strategy.Jitter > 0 {
-		jitter := time.Duration(float64(delay) * rm.strategy.Jitter)
-		delay += time.Duration(randomInt63n(int64(jitter)))
+	// Subtract jitter, so the delay never exceeds the cap
+	if jitter := int64(float64(delay) * rm.jitterFactor()); jitter > 0 {
+		delay -= time.Duration(randomInt63n(jitter + 1))
 	}
 
 	return delay
 }
 
-// WithRetry executes a function with retry logic
-func (rm *RetryManager) WithRetry(ctx context.Context, resourceKey string, fn func() error) error {
-	backoff := wait.Backoff{
-		Duration: rm.strategy.InitialDelay,
-		Factor:   rm.strategy.Multiplier,
-		Jitter:   rm.strategy.Jitter,
-		Steps:    rm.strategy.MaxAttempts,
-		Cap:      rm.strategy.MaxDelay,
+// jitterFactor returns the strategy's JitterFactor, defaulted and clamped to [0, 1]
+func (rm *RetryManager) jitterFactor() float64 {
+	switch factor := rm.strategy.JitterFactor; {
+	case factor == 0:
+		return defaultJitterFactor
+	case factor < 0:
+		return 0
+	case factor > 1:
+		return 1
+	default:
+		return factor
 	}
+}
 
-	err := wait.ExponentialBackoffWithContext(ctx, backoff, func(ctx context.Context) (bool, error) {
-		rm.RecordAttempt(resourceKey)
+// WithRetry executes a function with retry logic, waiting the jittered backoff delay
+// between attempts
+func (rm *RetryManager) WithRetry(ctx context.Context, resourceKey string, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
+		rm.RecordAttempt(resourceKey)
 		err := fn()
 		if err == nil {
 			rm.ResetAttempts(resourceKey)
-			return true, nil // Success
+			return nil // Success
 		}
 
 		if !rm.isRetryableError(err) {
-			return false, err // Non-retryable error
+			return err // Non-retryable error
 		}
 
-		return false, nil // Retryable error, continue
-	})
+		if attempt >= rm.strategy.MaxAttempts {
+			return wait.ErrorInterrupted(fmt.Errorf("%s failed after %d attempts: %w", resourceKey, attempt, err))
+		}
 
-	return err
+		timer := time.NewTimer(rm.backoffDelay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
 }
 
 // isRetryableError checks if an error is retryable
@@ -228,9 +257,21 @@ func NewCircuitBreaker(failureThreshold, successThreshold int, timeout time.Dura
 	}
 }
 
+// ErrCircuitOpen is returned without calling the function while the circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
 // Call executes a function through the circuit breaker
 func (cb *CircuitBreaker) Call(fn func() error) error {
+	_, _, err := cb.Execute(fn)
+	return err
+}
+
+// Execute executes a function through the circuit breaker like Call, and also returns the
+// state the breaker was in before the call and the state it was left in, so callers can
+// report transitions
+func (cb *CircuitBreaker) Execute(fn func() error) (from, to CircuitBreakerState, err error) {
 	cb.stateMutex.Lock()
+	from = cb.state
 
 	// Check circuit state
 	switch cb.state {
@@ -242,7 +283,7 @@ func (cb *CircuitBreaker) Call(fn func() error) error {
 			cb.stateMutex.Unlock()
 		} else {
 			cb.stateMutex.Unlock()
-			return fmt.Errorf("circuit breaker is open")
+			return from, StateOpen, ErrCircuitOpen
 		}
 	case StateHalfOpen:
 		// Limited calls allowed in half-open state
@@ -252,18 +293,17 @@ func (cb *CircuitBreaker) Call(fn func() error) error {
 	}
 
 	// Execute function
-	err := fn()
+	err = fn()
 
 	cb.stateMutex.Lock()
 	defer cb.stateMutex.Unlock()
 
 	if err == nil {
 		cb.onSuccess()
-		return nil
+	} else {
+		cb.onFailure()
 	}
-
-	cb.onFailure()
-	return err
+	return from, cb.state, err
 }
 
 // onSuccess handles successful execution
@@ -0,0 +1,120 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+// ParseStepGracePeriods parses per-backend grace periods between role change steps written as
+// "backend=duration" pairs separated by commas, e.g. "powerstore=5s,ceph=1m"
+func ParseStepGracePeriods(value string) (map[string]time.Duration, error) {
+	known := []translation.Backend{translation.BackendCeph, translation.BackendTrident, translation.BackendPowerStore}
+
+	periods := map[string]time.Duration{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		backend, durationValue, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid step grace period %q, expected backend=duration", item)
+		}
+		if !slices.Contains(known, translation.Backend(backend)) {
+			return nil, fmt.Errorf("unknown backend %q in step grace periods", backend)
+		}
+		period, err := time.ParseDuration(durationValue)
+		if err != nil {
+			return nil, fmt.Errorf("invalid step grace period for %s: %w", backend, err)
+		}
+		if period < 0 {
+			return nil, fmt.Errorf("step grace period for %s must not be negative", backend)
+		}
+		periods[backend] = period
+	}
+	return periods, nil
+}
+
+// stepGracePeriod returns how long to let a switched member settle before the next member is
+// switched, by the member's backend
+func (r *ApplicationReplicationReconciler) stepGracePeriod(member *replicationv1alpha1.UnifiedVolumeReplication) time.Duration {
+	return r.StepGracePeriods[backendPartitionFor(member)]
+}
+
+// awaitRoleChangeGrace holds a role change of the UVR until its backend's step grace period
+// has passed since the previous role change was observed, so a demotion settles before the
+// promotion that follows it, whether the spec was changed by hand, by an application or by a
+// NamespacePromotion. A change already under way through a transitional state is not held.
+// It returns true, with the RoleChangeProgressing condition reporting the time left, while
+// the change waits.
+func (r *UnifiedVolumeReplicationReconciler) awaitRoleChangeGrace(uvr *replicationv1alpha1.UnifiedVolumeReplication, now time.Time, log logr.Logger) (bool, ctrl.Result) {
+	grace := r.StepGracePeriods[backendPartitionFor(uvr)]
+	n := len(uvr.Status.StateHistory)
+	if grace <= 0 || n == 0 {
+		apimeta.RemoveStatusCondition(&uvr.Status.Conditions, roleChangeCondition)
+		return false, ctrl.Result{}
+	}
+
+	last := uvr.Status.StateHistory[n-1]
+	settled := last.To == string(replicationv1alpha1.ReplicationStateSource) || last.To == string(replicationv1alpha1.ReplicationStateReplica)
+	remaining := last.Timestamp.Add(grace).Sub(now)
+	if last.From == "" || !settled || last.To == string(uvr.Spec.ReplicationState) || remaining <= 0 {
+		apimeta.RemoveStatusCondition(&uvr.Status.Conditions, roleChangeCondition)
+		return false, ctrl.Result{}
+	}
+
+	message := fmt.Sprintf("Letting the change from %s to %s settle for %s before switching to %s",
+		last.From, last.To, remaining.Round(time.Second), uvr.Spec.ReplicationState)
+	log.Info("Role change held for the step grace period", "remaining", remaining)
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               roleChangeCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "GracePeriod",
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+	return true, ctrl.Result{RequeueAfter: max(remaining, time.Second)}
+}
+
+// waitStepGrace holds the next step of a role change until the grace period after the member
+// switched before it has passed. The grace period starts when that member is first seen in its
+// new role and is kept in status, so it survives restarts. It returns the time left, zero once
+// the next step may start.
+func (r *ApplicationReplicationReconciler) waitStepGrace(app *replicationv1alpha1.ApplicationReplication,
+	previous *replicationv1alpha1.UnifiedVolumeReplication, now time.Time) time.Duration {
+	grace := r.stepGracePeriod(previous)
+	if grace <= 0 {
+		return 0
+	}
+	if app.Status.CurrentStep != previous.Name || app.Status.GracePeriodEnd == nil {
+		app.Status.CurrentStep = previous.Name
+		app.Status.GracePeriodEnd = &metav1.Time{Time: now.Add(grace)}
+	}
+	return max(app.Status.GracePeriodEnd.Sub(now), 0)
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestParseStepGracePeriods(t *testing.T) {
+	periods, err := ParseStepGracePeriods("powerstore=5s, ceph=2m")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{"powerstore": 5 * time.Second, "ceph": 2 * time.Minute}, periods)
+
+	periods, err = ParseStepGracePeriods("")
+	require.NoError(t, err)
+	assert.Empty(t, periods)
+
+	for _, invalid := range []string{"ceph", "nfs=1m", "ceph=soon", "ceph=-1s"} {
+		_, err := ParseStepGracePeriods(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestApplicationReplication_StepGracePeriod(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	// Both members are on Trident, whose steps get a grace period
+	db, web := createAppMember("db"), createAppMember("web")
+	app := createTestApplication(replicationv1alpha1.ReplicationStateSource, "db", "web")
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithObjects(app, db, web).
+		WithStatusSubresource(app, db, web).Build()
+	reconciler := createTestApplicationReconciler(c)
+	reconciler.StepGracePeriods = map[string]time.Duration{"trident": 2 * time.Minute, "powerstore": time.Second}
+
+	reconcile := func() (ctrl.Result, *metav1.Condition) {
+		t.Helper()
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(app)})
+		require.NoError(t, err)
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(app), app))
+		condition := apimeta.FindStatusCondition(app.Status.Conditions, roleChangeCondition)
+		require.NotNil(t, condition)
+		return result, condition
+	}
+	desiredState := func(uvr *replicationv1alpha1.UnifiedVolumeReplication) replicationv1alpha1.ReplicationState {
+		t.Helper()
+		current := &replicationv1alpha1.UnifiedVolumeReplication{}
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(uvr), current))
+		return current.Spec.ReplicationState
+	}
+	observe := func(uvr *replicationv1alpha1.UnifiedVolumeReplication) {
+		t.Helper()
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(uvr), uvr))
+		uvr.Status.StateHistory = append(uvr.Status.StateHistory,
+			replicationv1alpha1.StateHistoryEntry{Timestamp: metav1.Now(), From: "replica", To: "source"})
+		require.NoError(t, c.Status().Update(ctx, uvr))
+	}
+	source := replicationv1alpha1.ReplicationStateSource
+
+	// The first step starts right away
+	_, condition := reconcile()
+	assert.Equal(t, "InProgress", condition.Reason)
+	assert.Equal(t, source, desiredState(db))
+	assert.Equal(t, "db", app.Status.CurrentStep)
+	assert.Nil(t, app.Status.GracePeriodEnd)
+
+	// Once db is a source, web waits out the grace period
+	observe(db)
+	before := time.Now()
+	result, condition := reconcile()
+	assert.Equal(t, "GracePeriod", condition.Reason)
+	assert.Contains(t, condition.Message, "letting db settle")
+	assert.Equal(t, replicationv1alpha1.ReplicationStateReplica, desiredState(web), "web is not switched during the grace period")
+	assert.Equal(t, "db", app.Status.CurrentStep)
+	require.NotNil(t, app.Status.GracePeriodEnd)
+	assert.WithinDuration(t, before.Add(2*time.Minute), app.Status.GracePeriodEnd.Time, 2*time.Second)
+	assert.Equal(t, roleChangePollDelay, result.RequeueAfter, "the role change keeps polling during a long grace period")
+
+	// Later reconciles keep the grace period where it started
+	end := app.Status.GracePeriodEnd.Time
+	_, condition = reconcile()
+	assert.Equal(t, "GracePeriod", condition.Reason)
+	assert.Equal(t, end.Unix(), app.Status.GracePeriodEnd.Unix())
+	assert.Equal(t, replicationv1alpha1.ReplicationStateReplica, desiredState(web))
+
+	// Close to its end the reconcile is requeued for when it ends
+	app.Status.GracePeriodEnd = &metav1.Time{Time: time.Now().Add(3 * time.Second)}
+	require.NoError(t, c.Status().Update(ctx, app))
+	result, _ = reconcile()
+	assert.LessOrEqual(t, result.RequeueAfter, 3*time.Second)
+	assert.Equal(t, replicationv1alpha1.ReplicationStateReplica, desiredState(web))
+
+	// Once it has passed, web is switched and the sequence completes
+	app.Status.GracePeriodEnd = &metav1.Time{Time: time.Now().Add(-time.Second)}
+	require.NoError(t, c.Status().Update(ctx, app))
+	_, condition = reconcile()
+	assert.Equal(t, "InProgress", condition.Reason)
+	assert.Equal(t, source, desiredState(web))
+	assert.Equal(t, "web", app.Status.CurrentStep)
+	assert.Nil(t, app.Status.GracePeriodEnd)
+
+	observe(web)
+	_, condition = reconcile()
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "Completed", condition.Reason)
+	assert.Empty(t, app.Status.CurrentStep)
+	assert.Nil(t, app.Status.GracePeriodEnd)
+}
+
+func TestReconciler_RoleChangeGracePeriod(t *testing.T) {
+	ctx := context.Background()
+
+	// The replica was demoted ten seconds ago and the spec already asks to promote it back
+	uvr := createTestUVR("test-role-change-grace", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+	demoted := metav1.NewTime(time.Now().Add(-10 * time.Second))
+	uvr.Status.StateHistory = []replicationv1alpha1.StateHistoryEntry{
+		{Timestamp: metav1.NewTime(demoted.Add(-time.Hour)), To: "source"},
+		{Timestamp: demoted, From: "source", To: "replica"},
+	}
+	reconciler, c := newDeactivationTestReconciler(t, adapters.NewTridentAdapterFactory(), uvr)
+	reconciler.StepGracePeriods = map[string]time.Duration{"trident": time.Minute}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+	promoted := func() bool {
+		tmr := &unstructured.Unstructured{}
+		tmr.SetGroupVersionKind(adapters.TridentMirrorRelationshipGVK)
+		return c.Get(ctx, req.NamespacedName, tmr) == nil
+	}
+
+	result, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.False(t, promoted(), "the promotion waits for the demotion to settle")
+	assert.InDelta(t, 50*time.Second, result.RequeueAfter, float64(2*time.Second))
+	require.NoError(t, c.Get(ctx, req.NamespacedName, uvr))
+	condition := apimeta.FindStatusCondition(uvr.Status.Conditions, roleChangeCondition)
+	require.NotNil(t, condition)
+	assert.Equal(t, "GracePeriod", condition.Reason)
+	assert.Contains(t, condition.Message, "from source to replica settle for")
+
+	// Once the grace period has passed the promotion goes ahead
+	uvr.Status.StateHistory[1].Timestamp = metav1.NewTime(time.Now().Add(-time.Minute))
+	require.NoError(t, c.Status().Update(ctx, uvr))
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.True(t, promoted())
+	require.NoError(t, c.Get(ctx, req.NamespacedName, uvr))
+	assert.Nil(t, apimeta.FindStatusCondition(uvr.Status.Conditions, roleChangeCondition))
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+// rpoCompliantCondition is False while the last sync is older than spec.schedule.rpo
+const rpoCompliantCondition = "RPOCompliant"
+
+// RPOComplianceThresholds map the RPO compliance of a backend's replications to a health: at
+// or above Warning is healthy, below Warning degraded and below Critical unhealthy
+type RPOComplianceThresholds struct {
+	Warning  float64
+	Critical float64
+}
+
+// ParseRPOComplianceThresholds parses per-backend thresholds written as
+// "backend=warning:critical" pairs separated by commas, e.g. "powerstore=99:95"
+func ParseRPOComplianceThresholds(value string) (map[string]RPOComplianceThresholds, error) {
+	known := []translation.Backend{translation.BackendCeph, translation.BackendTrident, translation.BackendPowerStore}
+
+	thresholds := map[string]RPOComplianceThresholds{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		backend, levels, ok := strings.Cut(item, "=")
+		warningValue, criticalValue, hasCritical := strings.Cut(levels, ":")
+		if !ok || !hasCritical {
+			return nil, fmt.Errorf("invalid RPO compliance thresholds %q, expected backend=warning:critical", item)
+		}
+		if !slices.Contains(known, translation.Backend(backend)) {
+			return nil, fmt.Errorf("unknown backend %q in RPO compliance thresholds", backend)
+		}
+		warning, err := strconv.ParseFloat(warningValue, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid warning threshold for %s: %w", backend, err)
+		}
+		critical, err := strconv.ParseFloat(criticalValue, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid critical threshold for %s: %w", backend, err)
+		}
+		if critical < 0 || warning > 100 || critical > warning {
+			return nil, fmt.Errorf("RPO compliance thresholds for %s must satisfy 0 <= critical <= warning <= 100", backend)
+		}
+		thresholds[backend] = RPOComplianceThresholds{Warning: warning, Critical: critical}
+	}
+	return thresholds, nil
+}
+
+// classify returns the health a compliance percentage maps to
+func (t RPOComplianceThresholds) classify(percent float64) adapters.ReplicationHealth {
+	switch {
+	case percent < t.Critical:
+		return adapters.ReplicationHealthUnhealthy
+	case percent < t.Warning:
+		return adapters.ReplicationHealthDegraded
+	default:
+		return adapters.ReplicationHealthHealthy
+	}
+}
+
+// checkRPO records how the replication meets spec.schedule.rpo: the RPO compliance and
+// violation derived from the last sync, in the status and in rpoCompliancePercent and
+// rpoViolated, the RPOCompliant condition, the SyncLagExceeded degradation, and status.rpo.
+// The lag is measured by measureSyncLag from lastSyncOf, so a replication that stopped
+// reporting syncs still trips the violation. status.rpo classifies the compliance the backend
+// reports, or else the derived one, and when thresholds are configured for the backend it
+// lowers the status health to match. Like the sync lag check it never raises the health the
+// backend reported.
+func (r *UnifiedVolumeReplicationReconciler) checkRPO(uvr *replicationv1alpha1.UnifiedVolumeReplication, status *adapters.ReplicationStatus) {
+	lastSync := lastSyncOf(uvr, status)
+	rpo, hasRPO := lagLimit(uvr.Spec.Schedule.Rpo)
+	status.RPOCompliancePercent, status.RPOViolated = nil, false
+	uvr.Status.RPOCompliancePercent, uvr.Status.RPOViolated = nil, false
+	if !hasRPO || lastSync == nil {
+		apimeta.RemoveStatusCondition(&uvr.Status.Conditions, rpoCompliantCondition)
+	}
+	if lastSync == nil && status.RPOCompliance == nil {
+		uvr.Status.RPO = nil
+		return
+	}
+
+	now := metav1.Now()
+	rpoStatus := &replicationv1alpha1.RPOStatus{LastUpdateTime: &now}
+	uvr.Status.RPO = rpoStatus
+
+	var percent *float64
+	if lastSync != nil {
+		measured := measureSyncLag(*lastSync, rpo)
+		rpoStatus.Lag = measured.Lag.String()
+		if hasRPO {
+			derived := 100.0
+			if measured.Exceeded {
+				derived = 100 * float64(rpo) / float64(measured.Lag)
+			}
+			percent = &derived
+			rpoStatus.ComplianceSource = "LastSync"
+			status.RPOCompliancePercent, status.RPOViolated = &derived, measured.Exceeded
+			uvr.Status.RPOCompliancePercent, uvr.Status.RPOViolated = &derived, measured.Exceeded
+			r.recordRPOCompliant(uvr, measured)
+			r.checkSyncLag(uvr, status, measured)
+		}
+	}
+	if status.RPOCompliance != nil {
+		percent = status.RPOCompliance
+		rpoStatus.ComplianceSource = "Backend"
+	}
+	if percent == nil {
+		return
+	}
+	rpoStatus.CompliancePercent = formatPercent(*percent)
+
+	thresholds, ok := r.RPOComplianceThresholds[backendPartitionFor(uvr)]
+	if !ok {
+		return
+	}
+	rpoStatus.WarningThreshold = formatPercent(thresholds.Warning)
+	rpoStatus.CriticalThreshold = formatPercent(thresholds.Critical)
+	health := thresholds.classify(*percent)
+	rpoStatus.Health = string(health)
+
+	if healthRank(health) <= healthRank(status.Health) {
+		return
+	}
+	threshold := thresholds.Warning
+	if health == adapters.ReplicationHealthUnhealthy {
+		threshold = thresholds.Critical
+	}
+	status.Health = health
+	status.DegradedReason = adapters.DegradedReasonRPOComplianceLow
+	status.Message = fmt.Sprintf("RPO compliance %s%% is below the threshold of %s%%", formatPercent(*percent), formatPercent(threshold))
+}
+
+// recordRPOCompliant sets the RPOCompliant condition from the lag since the last sync
+func (r *UnifiedVolumeReplicationReconciler) recordRPOCompliant(uvr *replicationv1alpha1.UnifiedVolumeReplication, rpo syncLag) {
+	condition := metav1.Condition{
+		Type:               rpoCompliantCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "WithinRPO",
+		Message:            fmt.Sprintf("Last sync %s ago is within RPO %s", rpo.Lag, uvr.Spec.Schedule.Rpo),
+		ObservedGeneration: uvr.Generation,
+	}
+	if rpo.Exceeded {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "RPOExceeded"
+		condition.Message = fmt.Sprintf("Last sync %s ago exceeds RPO %s", rpo.Lag, uvr.Spec.Schedule.Rpo)
+	}
+	r.updateCondition(uvr, condition)
+}
+
+// healthRank orders health from best to worst, so a check only ever makes it worse
+func healthRank(health adapters.ReplicationHealth) int {
+	switch health {
+	case adapters.ReplicationHealthHealthy:
+		return 0
+	case adapters.ReplicationHealthDegraded:
+		return 1
+	case adapters.ReplicationHealthUnhealthy:
+		return 2
+	default:
+		// Unknown health is left for the backend to resolve
+		return 3
+	}
+}
+
+// formatPercent renders a percentage for status, e.g. "97.50"
+func formatPercent(percent float64) string {
+	return strconv.FormatFloat(percent, 'f', 2, 64)
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestParseRPOComplianceThresholds(t *testing.T) {
+	thresholds, err := ParseRPOComplianceThresholds("powerstore=99:95, trident=98.5:90")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]RPOComplianceThresholds{
+		"powerstore": {Warning: 99, Critical: 95},
+		"trident":    {Warning: 98.5, Critical: 90},
+	}, thresholds)
+
+	thresholds, err = ParseRPOComplianceThresholds("")
+	require.NoError(t, err)
+	assert.Empty(t, thresholds)
+
+	for _, value := range []string{"powerstore=99", "powerstore", "netapp=99:95", "powerstore=high:95", "powerstore=95:99", "powerstore=101:95"} {
+		_, err := ParseRPOComplianceThresholds(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestReconciler_RPOComplianceHealth(t *testing.T) {
+	s := createTestScheme(t)
+	reconciler := createTestReconciler(fake.NewClientBuilder().WithScheme(s).Build(), s)
+	reconciler.RPOComplianceThresholds = map[string]RPOComplianceThresholds{
+		"powerstore": {Warning: 99, Critical: 95},
+		"trident":    {Warning: 90, Critical: 50},
+	}
+
+	uvr := createTestUVR("test-rpo-compliance", "default")
+	uvr.Spec.Extensions = &replicationv1alpha1.Extensions{Powerstore: &replicationv1alpha1.PowerStoreExtensions{}}
+	statusWith := func(compliance float64) *adapters.ReplicationStatus {
+		return &adapters.ReplicationStatus{State: "source", Health: adapters.ReplicationHealthHealthy, RPOCompliance: &compliance}
+	}
+
+	tests := []struct {
+		name       string
+		compliance float64
+		health     adapters.ReplicationHealth
+	}{
+		{name: "above warning", compliance: 99.5, health: adapters.ReplicationHealthHealthy},
+		{name: "at warning", compliance: 99, health: adapters.ReplicationHealthHealthy},
+		{name: "between thresholds", compliance: 97.25, health: adapters.ReplicationHealthDegraded},
+		{name: "below critical", compliance: 80, health: adapters.ReplicationHealthUnhealthy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := statusWith(tt.compliance)
+			reconciler.checkRPO(uvr, status)
+			assert.Equal(t, tt.health, status.Health)
+			if tt.health != adapters.ReplicationHealthHealthy {
+				assert.Equal(t, adapters.DegradedReasonRPOComplianceLow, status.DegradedReason)
+			}
+
+			require.NotNil(t, uvr.Status.RPO)
+			assert.Equal(t, formatPercent(tt.compliance), uvr.Status.RPO.CompliancePercent)
+			assert.Equal(t, "Backend", uvr.Status.RPO.ComplianceSource)
+			assert.Equal(t, "99.00", uvr.Status.RPO.WarningThreshold)
+			assert.Equal(t, "95.00", uvr.Status.RPO.CriticalThreshold)
+			assert.Equal(t, string(tt.health), uvr.Status.RPO.Health)
+		})
+	}
+
+	// Compliance never hides a worse health reported by the backend
+	status := statusWith(99.9)
+	status.Health = adapters.ReplicationHealthDegraded
+	status.DegradedReason = adapters.DegradedReasonSessionFailure
+	reconciler.checkRPO(uvr, status)
+	assert.Equal(t, adapters.DegradedReasonSessionFailure, status.DegradedReason)
+
+	// Backends that report no compliance are classified on the one derived from the lag
+	uvr.Spec.Extensions = &replicationv1alpha1.Extensions{Trident: &replicationv1alpha1.TridentExtensions{}}
+	uvr.Spec.Schedule.Rpo = "1m"
+	lastSync := time.Now().Add(-4 * time.Minute)
+	status = &adapters.ReplicationStatus{State: "source", Health: adapters.ReplicationHealthHealthy, LastSyncTime: &lastSync}
+	reconciler.checkRPO(uvr, status)
+	assert.Equal(t, adapters.ReplicationHealthUnhealthy, status.Health)
+	assert.Equal(t, "25.00", uvr.Status.RPO.CompliancePercent)
+	assert.Equal(t, "LastSync", uvr.Status.RPO.ComplianceSource)
+	assert.Equal(t, string(adapters.ReplicationHealthUnhealthy), uvr.Status.RPO.Health)
+
+	// Without thresholds for the backend only the value is recorded
+	uvr.Spec.Extensions = &replicationv1alpha1.Extensions{Ceph: &replicationv1alpha1.CephExtensions{}}
+	status = statusWith(50)
+	reconciler.checkRPO(uvr, status)
+	assert.Equal(t, adapters.ReplicationHealthHealthy, status.Health)
+	assert.Equal(t, "50.00", uvr.Status.RPO.CompliancePercent)
+	assert.Empty(t, uvr.Status.RPO.Health)
+	assert.Empty(t, uvr.Status.RPO.WarningThreshold)
+}
+
+func TestReconciler_RPOViolation(t *testing.T) {
+	s := createTestScheme(t)
+	reconciler := createTestReconciler(fake.NewClientBuilder().WithScheme(s).Build(), s)
+
+	uvr := createTestUVR("test-rpo-violation", "default")
+	uvr.Spec.Schedule.Rpo = "1m"
+	statusSyncedAgo := func(lag time.Duration) *adapters.ReplicationStatus {
+		lastSync := time.Now().Add(-lag)
+		return &adapters.ReplicationStatus{State: "source", Health: adapters.ReplicationHealthHealthy, LastSyncTime: &lastSync}
+	}
+
+	reconciler.checkRPO(uvr, statusSyncedAgo(10*time.Second))
+	require.NotNil(t, uvr.Status.RPO)
+	assert.Equal(t, "10s", uvr.Status.RPO.Lag)
+	assert.Equal(t, "100.00", uvr.Status.RPO.CompliancePercent)
+	assert.Equal(t, "LastSync", uvr.Status.RPO.ComplianceSource)
+	require.NotNil(t, uvr.Status.RPOCompliancePercent)
+	assert.Equal(t, 100.0, *uvr.Status.RPOCompliancePercent)
+	assert.False(t, uvr.Status.RPOViolated)
+	cond := reconciler.getCondition(uvr, rpoCompliantCondition)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, "WithinRPO", cond.Reason)
+
+	// A last sync twice the RPO old trips the violation
+	status := statusSyncedAgo(2 * time.Minute)
+	reconciler.checkRPO(uvr, status)
+	assert.Equal(t, "2m0s", uvr.Status.RPO.Lag)
+	assert.Equal(t, "50.00", uvr.Status.RPO.CompliancePercent)
+	require.NotNil(t, uvr.Status.RPOCompliancePercent)
+	assert.InDelta(t, 50.0, *uvr.Status.RPOCompliancePercent, 0.1)
+	assert.True(t, uvr.Status.RPOViolated)
+	require.NotNil(t, status.RPOCompliancePercent)
+	assert.InDelta(t, 50.0, *status.RPOCompliancePercent, 0.1)
+	assert.True(t, status.RPOViolated)
+	cond = reconciler.getCondition(uvr, rpoCompliantCondition)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, "RPOExceeded", cond.Reason)
+	assert.Contains(t, cond.Message, "exceeds RPO 1m")
+
+	// The violation follows the last sync even when the backend reports a good compliance
+	compliance := 99.0
+	status = statusSyncedAgo(2 * time.Minute)
+	status.RPOCompliance = &compliance
+	reconciler.checkRPO(uvr, status)
+	assert.Equal(t, "99.00", uvr.Status.RPO.CompliancePercent)
+	assert.Equal(t, "Backend", uvr.Status.RPO.ComplianceSource)
+	assert.True(t, uvr.Status.RPOViolated)
+
+	// A backend that stops reporting syncs is judged by the last recorded one
+	stale := metav1.NewTime(time.Now().Add(-5 * time.Minute))
+	uvr.Status.LastSyncTime = &stale
+	reconciler.checkRPO(uvr, &adapters.ReplicationStatus{State: "source", Health: adapters.ReplicationHealthHealthy})
+	assert.True(t, uvr.Status.RPOViolated)
+	require.NotNil(t, uvr.Status.RPOCompliancePercent)
+	assert.InDelta(t, 20.0, *uvr.Status.RPOCompliancePercent, 0.1)
+
+	// Without an RPO only the lag is tracked
+	uvr.Spec.Schedule.Rpo = ""
+	reconciler.checkRPO(uvr, statusSyncedAgo(2*time.Minute))
+	require.NotNil(t, uvr.Status.RPO)
+	assert.Equal(t, "2m0s", uvr.Status.RPO.Lag)
+	assert.Empty(t, uvr.Status.RPO.CompliancePercent)
+	assert.Nil(t, uvr.Status.RPOCompliancePercent)
+	assert.False(t, uvr.Status.RPOViolated)
+	assert.Nil(t, reconciler.getCondition(uvr, rpoCompliantCondition))
+
+	// Nothing is recorded before the first sync
+	uvr.Status.LastSyncTime = nil
+	reconciler.checkRPO(uvr, &adapters.ReplicationStatus{State: "source", Health: adapters.ReplicationHealthHealthy})
+	assert.Nil(t, uvr.Status.RPO)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// scheduleDelegatedCondition reports whether the backend runs the sync schedule
+const scheduleDelegatedCondition = "ScheduleDelegated"
+
+// delegateSchedule configures the backend's native schedule when the UVR delegates it, so
+// the backend triggers syncs and the operator only monitors. It returns false, with the
+// Ready condition set, when the backend has no native scheduler or configuring it failed.
+func (r *UnifiedVolumeReplicationReconciler) delegateSchedule(ctx context.Context, adapter adapters.ReplicationAdapter, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) bool {
+	if !uvr.Spec.Schedule.Delegate {
+		if existing := r.getCondition(uvr, scheduleDelegatedCondition); existing != nil && existing.Status == metav1.ConditionTrue {
+			r.updateCondition(uvr, metav1.Condition{
+				Type:               scheduleDelegatedCondition,
+				Status:             metav1.ConditionFalse,
+				Reason:             "NotDelegated",
+				Message:            "The sync schedule is managed by the operator",
+				ObservedGeneration: uvr.Generation,
+			})
+		}
+		return true
+	}
+
+	scheduler, ok := adapter.(adapters.NativeScheduler)
+	if !ok {
+		r.refuseDelegation(uvr, "ScheduleDelegationUnsupported",
+			fmt.Sprintf("Backend %s has no native scheduler, unset schedule.delegate", adapter.GetBackendType()))
+		return false
+	}
+
+	if err := scheduler.ConfigureNativeSchedule(ctx, uvr); err != nil {
+		log.Error(err, "Failed to configure native schedule")
+		r.refuseDelegation(uvr, "ScheduleDelegationFailed", fmt.Sprintf("Failed to configure native schedule: %v", err))
+		return false
+	}
+
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               scheduleDelegatedCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "BackendScheduled",
+		Message:            fmt.Sprintf("Backend %s runs syncs for RPO %s", adapter.GetBackendType(), uvr.Spec.Schedule.Rpo),
+		ObservedGeneration: uvr.Generation,
+	})
+	return true
+}
+
+// refuseDelegation reports a schedule that could not be handed to the backend
+func (r *UnifiedVolumeReplicationReconciler) refuseDelegation(uvr *replicationv1alpha1.UnifiedVolumeReplication, reason, message string) {
+	if existing := r.getCondition(uvr, "Ready"); existing == nil || existing.Reason != reason {
+		r.recordEventf(uvr, corev1.EventTypeWarning, reason, "%s", message)
+	}
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               scheduleDelegatedCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg"
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+func TestReconciler_DelegatedSchedule(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+
+	uvr := createTestUVR("test-delegated-schedule", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	uvr.Spec.Schedule = replicationv1alpha1.Schedule{Mode: replicationv1alpha1.ScheduleModeInterval, Rpo: "15m", Delegate: true}
+
+	// Count the manual SnapMirror updates issued to the backend, and the mirror
+	// relationships deleted for a recreation
+	var manualSyncs, deletes int
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(tridentCRDs(""), uvr)...).
+		WithStatusSubresource(uvr).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if u, ok := obj.(*unstructured.Unstructured); ok && u.GroupVersionKind() == adapters.TridentActionMirrorUpdateGVK {
+					manualSyncs++
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				deletes++
+				return c.Delete(ctx, obj, opts...)
+			},
+		}).Build()
+
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewTridentAdapterFactory()))
+	reconciler := createTestReconciler(c, s)
+	reconciler.AdapterRegistry = registry
+	reconciler.ControllerEngine = pkg.NewControllerEngine(c, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+		registry, pkg.DefaultControllerEngineConfig())
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+	reconcileSchedule := func(mutate func(*replicationv1alpha1.Schedule)) (string, *metav1.Condition) {
+		t.Helper()
+		if mutate != nil {
+			current := &replicationv1alpha1.UnifiedVolumeReplication{}
+			require.NoError(t, c.Get(ctx, req.NamespacedName, current))
+			mutate(&current.Spec.Schedule)
+			require.NoError(t, c.Update(ctx, current))
+		}
+		for i := 0; i < 3; i++ {
+			_, err := reconciler.Reconcile(ctx, req)
+			require.NoError(t, err)
+		}
+
+		tmr := &unstructured.Unstructured{}
+		tmr.SetGroupVersionKind(adapters.TridentMirrorRelationshipGVK)
+		require.NoError(t, c.Get(ctx, req.NamespacedName, tmr))
+		schedule, _, _ := unstructured.NestedString(tmr.Object, "spec", "replicationSchedule")
+		updated := &replicationv1alpha1.UnifiedVolumeReplication{}
+		require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+		return schedule, reconciler.getCondition(updated, scheduleDelegatedCondition)
+	}
+
+	schedule, delegated := reconcileSchedule(nil)
+	assert.Equal(t, "*/15 * * * *", schedule, "the SnapMirror schedule runs the syncs")
+	assert.Zero(t, manualSyncs, "no manual syncs while the backend schedules them")
+	require.NotNil(t, delegated)
+	assert.Equal(t, metav1.ConditionTrue, delegated.Status)
+	assert.Equal(t, "BackendScheduled", delegated.Reason)
+	assert.Equal(t, "Backend trident runs syncs for RPO 15m", delegated.Message)
+
+	// A new RPO changes the schedule of the existing relationship
+	schedule, delegated = reconcileSchedule(func(schedule *replicationv1alpha1.Schedule) { schedule.Rpo = "30m" })
+	assert.Equal(t, "*/30 * * * *", schedule)
+	assert.Equal(t, "Backend trident runs syncs for RPO 30m", delegated.Message)
+
+	// Taking the schedule back hands the relationship the operator's sync interval
+	schedule, delegated = reconcileSchedule(func(schedule *replicationv1alpha1.Schedule) { schedule.Delegate = false })
+	assert.Equal(t, "30m", schedule)
+	assert.Equal(t, metav1.ConditionFalse, delegated.Status)
+	assert.Equal(t, "NotDelegated", delegated.Reason)
+	assert.Zero(t, deletes, "the schedule changed without recreating the relationship")
+}
+
+func TestReconciler_DelegatedScheduleCeph(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+	s.AddKnownTypes(schema.GroupVersion{Group: "replication.storage.openshift.io", Version: "v1alpha1"},
+		&adapters.VolumeReplication{}, &adapters.VolumeReplicationList{})
+
+	uvr := createTestUVR("test-delegated-ceph", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	uvr.Spec.Extensions = &replicationv1alpha1.Extensions{Ceph: &replicationv1alpha1.CephExtensions{}}
+	uvr.Spec.SourceEndpoint.StorageClass = "ceph-rbd"
+	uvr.Spec.DestinationEndpoint.StorageClass = "ceph-rbd"
+	uvr.Spec.Schedule = replicationv1alpha1.Schedule{Mode: replicationv1alpha1.ScheduleModeInterval, Rpo: "15m", Delegate: true}
+
+	classGVK := schema.GroupVersionKind{Group: "replication.storage.openshift.io", Version: "v1alpha1", Kind: "VolumeReplicationClass"}
+	vrc := &unstructured.Unstructured{}
+	vrc.SetGroupVersionKind(classGVK)
+	vrc.SetName("rbd-volumereplicationclass")
+	require.NoError(t, unstructured.SetNestedStringMap(vrc.Object, map[string]string{
+		"mirroringMode":      "snapshot",
+		"schedulingInterval": "5m",
+	}, "spec", "parameters"))
+
+	var deletes int
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(cephCRDs(), uvr, vrc)...).
+		WithStatusSubresource(uvr).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				deletes++
+				return c.Delete(ctx, obj, opts...)
+			},
+		}).Build()
+	reconciler := createTestReconciler(c, s)
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+	reconcileSchedule := func(mutate func(*replicationv1alpha1.Schedule)) *replicationv1alpha1.UnifiedVolumeReplication {
+		t.Helper()
+		if mutate != nil {
+			current := &replicationv1alpha1.UnifiedVolumeReplication{}
+			require.NoError(t, c.Get(ctx, req.NamespacedName, current))
+			mutate(&current.Spec.Schedule)
+			require.NoError(t, c.Update(ctx, current))
+		}
+		for i := 0; i < 3; i++ {
+			_, err := reconciler.Reconcile(ctx, req)
+			require.NoError(t, err)
+		}
+
+		// The VolumeReplication keeps the default class, which is left as it is
+		vr := &adapters.VolumeReplication{}
+		require.NoError(t, c.Get(ctx, client.ObjectKey{Name: "test-delegated-ceph-vr", Namespace: "default"}, vr))
+		assert.Equal(t, "rbd-volumereplicationclass", vr.Spec.VolumeReplicationClass)
+		assert.Equal(t, "source-pvc", vr.Spec.PvcName)
+		classes := &unstructured.UnstructuredList{}
+		classes.SetGroupVersionKind(classGVK.GroupVersion().WithKind("VolumeReplicationClassList"))
+		require.NoError(t, c.List(ctx, classes))
+		require.Len(t, classes.Items, 1, "no class is created per RPO")
+		parameters, _, _ := unstructured.NestedStringMap(classes.Items[0].Object, "spec", "parameters")
+		assert.Equal(t, map[string]string{"mirroringMode": "snapshot", "schedulingInterval": "5m"}, parameters)
+
+		updated := &replicationv1alpha1.UnifiedVolumeReplication{}
+		require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+		return updated
+	}
+
+	updated := reconcileSchedule(nil)
+	delegated := reconciler.getCondition(updated, scheduleDelegatedCondition)
+	require.NotNil(t, delegated)
+	assert.Equal(t, metav1.ConditionTrue, delegated.Status)
+	assert.Equal(t, "Backend ceph runs syncs for RPO 15m", delegated.Message)
+
+	// Toggling delegation leaves the VolumeReplication in place
+	updated = reconcileSchedule(func(schedule *replicationv1alpha1.Schedule) { schedule.Delegate = false })
+	assert.Equal(t, "NotDelegated", reconciler.getCondition(updated, scheduleDelegatedCondition).Reason)
+	updated = reconcileSchedule(func(schedule *replicationv1alpha1.Schedule) { schedule.Delegate = true })
+	assert.Equal(t, metav1.ConditionTrue, reconciler.getCondition(updated, scheduleDelegatedCondition).Status)
+
+	// An RPO shorter than the class interval cannot be delegated
+	updated = reconcileSchedule(func(schedule *replicationv1alpha1.Schedule) { schedule.Rpo = "2m" })
+	ready := reconciler.getCondition(updated, "Ready")
+	require.NotNil(t, ready)
+	assert.Equal(t, "ScheduleDelegationFailed", ready.Reason)
+	assert.Contains(t, ready.Message, "VolumeReplicationClass rbd-volumereplicationclass schedules mirror snapshots every 5m, less often than the RPO 2m")
+	assert.Zero(t, deletes, "the VolumeReplication was never recreated")
+}
+
+func TestReconciler_DelegatedScheduleUnsupported(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	uvr := createTestUVR("test-delegation-unsupported", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	uvr.Spec.Extensions = &replicationv1alpha1.Extensions{Powerstore: &replicationv1alpha1.PowerStoreExtensions{}}
+	uvr.Spec.Schedule = replicationv1alpha1.Schedule{Mode: replicationv1alpha1.ScheduleModeInterval, Rpo: "15m", Delegate: true}
+
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).WithStatusSubresource(uvr).Build()
+	reconciler := createTestReconciler(c, s)
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewPowerStoreAdapterFactory()))
+	reconciler.AdapterManager = adapters.NewAdapterManager(registry, nil)
+	reconciler.AdapterManager.SetBackendConfig(translation.BackendPowerStore, adapters.DefaultAdapterConfig(translation.BackendPowerStore))
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+	result, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, requeueDelayError, result.RequeueAfter)
+
+	updated := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	ready := reconciler.getCondition(updated, "Ready")
+	require.NotNil(t, ready)
+	assert.Equal(t, "ScheduleDelegationUnsupported", ready.Reason)
+	assert.Equal(t, "Backend powerstore has no native scheduler, unset schedule.delegate", ready.Message)
+}
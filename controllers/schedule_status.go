@@ -0,0 +1,123 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+// recordScheduleStatus resolves the schedule of the spec into status.schedule as of now:
+// the cadence in effect, the cron expression of a schedule delegated to SnapMirror, and
+// when the next sync is due. It runs after status.lastSyncTime is updated.
+func (r *UnifiedVolumeReplicationReconciler) recordScheduleStatus(uvr *replicationv1alpha1.UnifiedVolumeReplication, now time.Time) {
+	schedule := uvr.Spec.Schedule
+	resolved := &replicationv1alpha1.ScheduleStatus{
+		Mode:         schedule.Mode,
+		Delegated:    schedule.Delegate && schedule.Mode == replicationv1alpha1.ScheduleModeInterval,
+		LastSyncTime: uvr.Status.LastSyncTime,
+	}
+	if schedule.Mode == replicationv1alpha1.ScheduleModeContinuous {
+		uvr.Status.Schedule = resolved
+		return
+	}
+
+	cadence, err := parseScheduleDuration(uvr.SyncInterval())
+	if err != nil || cadence <= 0 {
+		uvr.Status.Schedule = resolved
+		return
+	}
+	resolved.Cadence, _ = replicationv1alpha1.NormalizeScheduleDuration(formatScheduleDuration(cadence))
+
+	backend := backendPartitionFor(uvr)
+	if config := uvr.Status.EffectiveConfig; config != nil && config.Backend != "" {
+		backend = config.Backend
+	}
+	if resolved.Delegated && backend == string(translation.BackendTrident) {
+		if cron, err := adapters.SnapMirrorSchedule(schedule.Rpo); err == nil {
+			resolved.Cron = cron
+		}
+	}
+
+	switch {
+	case resolved.Cron != "":
+		if next, err := nextCronTime(resolved.Cron, now); err == nil {
+			resolved.NextSyncTime = &metav1.Time{Time: next}
+		}
+	case uvr.Status.LastSyncTime != nil:
+		resolved.NextSyncTime = &metav1.Time{Time: uvr.Status.LastSyncTime.Add(cadence)}
+	}
+	uvr.Status.Schedule = resolved
+}
+
+// nextCronTime returns the first minute after t, in UTC, matching a cron expression whose
+// minute and hour fields are "*", "*/N" or a number and whose other fields are "*", the
+// forms SnapMirrorSchedule produces
+func nextCronTime(expr string, t time.Time) (time.Time, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return time.Time{}, fmt.Errorf("cron expression %q must have 5 fields", expr)
+	}
+	for _, field := range fields[2:] {
+		if field != "*" {
+			return time.Time{}, fmt.Errorf("cron expression %q: only minute and hour may be restricted", expr)
+		}
+	}
+	minute, err := cronFieldMatcher(fields[0], 60)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("cron expression %q: %w", expr, err)
+	}
+	hour, err := cronFieldMatcher(fields[1], 24)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("cron expression %q: %w", expr, err)
+	}
+
+	next := t.UTC().Truncate(time.Minute).Add(time.Minute)
+	for end := next.Add(24 * time.Hour); next.Before(end); next = next.Add(time.Minute) {
+		if minute(next.Minute()) && hour(next.Hour()) {
+			return next, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cron expression %q never fires", expr)
+}
+
+// cronFieldMatcher parses a cron field of "*", "*/N" or a number below limit
+func cronFieldMatcher(field string, limit int) (func(int) bool, error) {
+	if field == "*" {
+		return func(int) bool { return true }, nil
+	}
+	if step, ok := strings.CutPrefix(field, "*/"); ok {
+		n, err := strconv.Atoi(step)
+		if err != nil || n <= 0 || n >= limit {
+			return nil, fmt.Errorf("invalid step %q", field)
+		}
+		return func(value int) bool { return value%n == 0 }, nil
+	}
+	n, err := strconv.Atoi(field)
+	if err != nil || n < 0 || n >= limit {
+		return nil, fmt.Errorf("invalid value %q", field)
+	}
+	return func(value int) bool { return value == n }, nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestReconciler_RecordScheduleStatus(t *testing.T) {
+	s := createTestScheme(t)
+	reconciler := createTestReconciler(fake.NewClientBuilder().WithScheme(s).Build(), s)
+	now := time.Date(2024, 6, 1, 12, 7, 30, 0, time.UTC)
+	lastSync := &metav1.Time{Time: now.Add(-10 * time.Minute)}
+
+	// Interval mode: one RPO after the last sync
+	interval := createTestUVR("interval", "default")
+	interval.Spec.Schedule = replicationv1alpha1.Schedule{Mode: replicationv1alpha1.ScheduleModeInterval, Rpo: "90m"}
+	interval.Status.LastSyncTime = lastSync
+	reconciler.recordScheduleStatus(interval, now)
+	require.NotNil(t, interval.Status.Schedule)
+	assert.Equal(t, replicationv1alpha1.ScheduleStatus{
+		Mode:         replicationv1alpha1.ScheduleModeInterval,
+		Cadence:      "1h30m",
+		LastSyncTime: lastSync,
+		NextSyncTime: &metav1.Time{Time: lastSync.Add(90 * time.Minute)},
+	}, *interval.Status.Schedule)
+
+	// Auto mode follows the computed interval
+	auto := createTestUVR("auto", "default")
+	auto.Spec.Schedule = replicationv1alpha1.Schedule{Mode: replicationv1alpha1.ScheduleModeAuto, Rpo: "15m"}
+	auto.Status.ComputedSchedule = &replicationv1alpha1.ComputedSchedule{Interval: "12m"}
+	auto.Status.LastSyncTime = lastSync
+	reconciler.recordScheduleStatus(auto, now)
+	assert.Equal(t, "12m", auto.Status.Schedule.Cadence)
+	assert.Equal(t, lastSync.Add(12*time.Minute), auto.Status.Schedule.NextSyncTime.Time)
+
+	// Delegated to SnapMirror: the next cron run, whenever the last sync was
+	cron := createTestUVR("cron", "default")
+	cron.Spec.Schedule = replicationv1alpha1.Schedule{Mode: replicationv1alpha1.ScheduleModeInterval, Rpo: "15m", Delegate: true}
+	cron.Status.LastSyncTime = lastSync
+	reconciler.recordScheduleStatus(cron, now)
+	assert.True(t, cron.Status.Schedule.Delegated)
+	assert.Equal(t, "*/15 * * * *", cron.Status.Schedule.Cron)
+	assert.Equal(t, time.Date(2024, 6, 1, 12, 15, 0, 0, time.UTC), cron.Status.Schedule.NextSyncTime.Time)
+
+	// No next sync before the first one, nor for continuous replication
+	pending := createTestUVR("pending", "default")
+	pending.Spec.Schedule = replicationv1alpha1.Schedule{Mode: replicationv1alpha1.ScheduleModeInterval, Rpo: "5m"}
+	reconciler.recordScheduleStatus(pending, now)
+	assert.Equal(t, "5m", pending.Status.Schedule.Cadence)
+	assert.Nil(t, pending.Status.Schedule.NextSyncTime)
+	continuous := createTestUVR("continuous", "default")
+	continuous.Status.LastSyncTime = lastSync
+	reconciler.recordScheduleStatus(continuous, now)
+	assert.Equal(t, replicationv1alpha1.ScheduleModeContinuous, continuous.Status.Schedule.Mode)
+	assert.Empty(t, continuous.Status.Schedule.Cadence)
+	assert.Nil(t, continuous.Status.Schedule.NextSyncTime)
+
+	// Every status update refreshes it from the last sync the backend reports
+	synced := time.Now().Add(-time.Minute)
+	reconciler.updateStatusFromEngineStatus(pending, &adapters.ReplicationStatus{
+		State: "replica", Health: adapters.ReplicationHealthHealthy, LastSyncTime: &synced,
+	}, reconciler.Log)
+	require.NotNil(t, pending.Status.Schedule.NextSyncTime)
+	assert.Equal(t, synced.Add(5*time.Minute).Unix(), pending.Status.Schedule.NextSyncTime.Unix())
+}
+
+func TestNextCronTime(t *testing.T) {
+	at := time.Date(2024, 6, 1, 12, 7, 30, 0, time.UTC)
+	for expr, want := range map[string]time.Time{
+		"*/15 * * * *": time.Date(2024, 6, 1, 12, 15, 0, 0, time.UTC),
+		"0 * * * *":    time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC),
+		"0 */6 * * *":  time.Date(2024, 6, 1, 18, 0, 0, 0, time.UTC),
+		"0 0 * * *":    time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC),
+	} {
+		next, err := nextCronTime(expr, at)
+		require.NoError(t, err, expr)
+		assert.Equal(t, want, next, expr)
+	}
+
+	// A run due exactly now is already past
+	next, err := nextCronTime("*/15 * * * *", time.Date(2024, 6, 1, 12, 15, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC), next)
+
+	for _, invalid := range []string{"*/15 * * *", "0 0 1 * *", "*/0 * * * *", "61 * * * *"} {
+		_, err := nextCronTime(invalid, at)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+const (
+	// SimulateDegradationAnnotation makes the operator report a synthetic degraded health for
+	// the UVR, so alerting and runbooks can be rehearsed without touching the replication.
+	// The value is how long the simulation lasts, defaulting to 10 minutes and capped at an
+	// hour; the annotation is removed when it ends. It only takes effect in namespaces the
+	// operator allows.
+	SimulateDegradationAnnotation = "replication.storage.io/simulate-degradation"
+
+	// simulatedDegradationCondition tracks a simulation; it is True while the reported
+	// health is synthetic
+	simulatedDegradationCondition = "SimulatedDegradation"
+
+	defaultSimulatedDegradation = 10 * time.Minute
+	maxSimulatedDegradation     = time.Hour
+)
+
+// simulatedDegradationActive is 1 while a UVR reports synthetic degraded health, so alerts
+// raised during a rehearsal can be told apart from real ones
+var simulatedDegradationActive = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "unified_replication_simulated_degradation",
+	Help: "1 while the replication reports a synthetic degradation injected with the simulate-degradation annotation",
+}, []string{"namespace", "name"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(simulatedDegradationActive)
+}
+
+// simulateDegradation replaces the backend status with a synthetic degraded one while a
+// simulation requested by the annotation is running, and ends the simulation once its
+// duration has passed. The backend status itself is never changed.
+func (r *UnifiedVolumeReplicationReconciler) simulateDegradation(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, status *adapters.ReplicationStatus, log logr.Logger) *adapters.ReplicationStatus {
+	value, requested := uvr.Annotations[SimulateDegradationAnnotation]
+	existing := r.getCondition(uvr, simulatedDegradationCondition)
+	if !requested {
+		if existing != nil && existing.Status == metav1.ConditionTrue {
+			r.endSimulatedDegradation(uvr, "Cancelled", "Simulated degradation cancelled, reporting backend health")
+		}
+		return status
+	}
+
+	if !slices.Contains(r.SimulateDegradationNamespaces, uvr.Namespace) {
+		r.refuseSimulatedDegradation(uvr, "NotAllowed",
+			fmt.Sprintf("Simulated degradation is not allowed in namespace %s", uvr.Namespace))
+		return status
+	}
+
+	duration := defaultSimulatedDegradation
+	if value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			r.refuseSimulatedDegradation(uvr, "InvalidDuration",
+				fmt.Sprintf("Invalid %s value %q, expected a duration such as 10m", SimulateDegradationAnnotation, value))
+			return status
+		}
+		duration = min(parsed, maxSimulatedDegradation)
+	}
+
+	// The simulation runs from when the condition turned True. It only ends once the
+	// annotation is removed, otherwise the next reconcile would start it again.
+	start := time.Now()
+	if existing != nil && existing.Status == metav1.ConditionTrue {
+		start = existing.LastTransitionTime.Time
+	}
+	end := start.Add(duration)
+	if !time.Now().Before(end) && r.clearSimulateDegradationAnnotation(ctx, uvr, log) {
+		r.endSimulatedDegradation(uvr, "Expired", "Simulated degradation ended, reporting backend health")
+		return status
+	}
+
+	message := fmt.Sprintf("SYNTHETIC: simulated degradation for DR rehearsal until %s, the replication is unaffected",
+		end.UTC().Format(time.RFC3339))
+	if existing == nil || existing.Status != metav1.ConditionTrue {
+		log.Info("Starting simulated degradation", "until", end)
+		r.recordEventf(uvr, corev1.EventTypeNormal, "SimulatedDegradationStarted", "%s", message)
+	}
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               simulatedDegradationCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Active",
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+	simulatedDegradationActive.WithLabelValues(uvr.Namespace, uvr.Name).Set(1)
+
+	simulated := *status
+	simulated.Health = adapters.ReplicationHealthDegraded
+	simulated.DegradedReason = adapters.DegradedReasonSimulated
+	simulated.Message = message
+	return &simulated
+}
+
+// simulatedDegradationRequeue shortens the requeue delay so a running simulation ends on time
+func (r *UnifiedVolumeReplicationReconciler) simulatedDegradationRequeue(uvr *replicationv1alpha1.UnifiedVolumeReplication, delay time.Duration) time.Duration {
+	existing := r.getCondition(uvr, simulatedDegradationCondition)
+	if existing == nil || existing.Status != metav1.ConditionTrue {
+		return delay
+	}
+	// The annotation may have changed the duration since the simulation started
+	duration := defaultSimulatedDegradation
+	if parsed, err := time.ParseDuration(uvr.Annotations[SimulateDegradationAnnotation]); err == nil && parsed > 0 {
+		duration = min(parsed, maxSimulatedDegradation)
+	}
+	remaining := time.Until(existing.LastTransitionTime.Add(duration))
+	if remaining <= 0 {
+		return requeueDelayFast
+	}
+	return min(delay, remaining+time.Second)
+}
+
+// endSimulatedDegradation reports that the synthetic health is no longer in effect
+func (r *UnifiedVolumeReplicationReconciler) endSimulatedDegradation(uvr *replicationv1alpha1.UnifiedVolumeReplication, reason, message string) {
+	r.recordEventf(uvr, corev1.EventTypeNormal, "SimulatedDegradationEnded", "%s", message)
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               simulatedDegradationCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+	forgetSimulatedDegradation(uvr)
+}
+
+// refuseSimulatedDegradation reports a simulation request that was ignored
+func (r *UnifiedVolumeReplicationReconciler) refuseSimulatedDegradation(uvr *replicationv1alpha1.UnifiedVolumeReplication, reason, message string) {
+	if existing := r.getCondition(uvr, simulatedDegradationCondition); existing == nil || existing.Reason != reason {
+		r.recordEventf(uvr, corev1.EventTypeWarning, reason, "%s", message)
+	}
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               simulatedDegradationCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+	forgetSimulatedDegradation(uvr)
+}
+
+// clearSimulateDegradationAnnotation removes the annotation once the simulation has ended,
+// so the next rehearsal needs a fresh request. It returns false when the annotation could
+// not be removed.
+func (r *UnifiedVolumeReplicationReconciler) clearSimulateDegradationAnnotation(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) bool {
+	current := &replicationv1alpha1.UnifiedVolumeReplication{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(uvr), current); err != nil {
+		log.Error(err, "Failed to remove simulate-degradation annotation")
+		return false
+	}
+	patched := current.DeepCopy()
+	delete(patched.Annotations, SimulateDegradationAnnotation)
+	if err := r.Patch(ctx, patched, client.MergeFrom(current)); err != nil {
+		log.Error(err, "Failed to remove simulate-degradation annotation")
+		return false
+	}
+	uvr.ResourceVersion = patched.ResourceVersion
+	delete(uvr.Annotations, SimulateDegradationAnnotation)
+	return true
+}
+
+// forgetSimulatedDegradation drops the simulation metric of a UVR
+func forgetSimulatedDegradation(uvr *replicationv1alpha1.UnifiedVolumeReplication) {
+	simulatedDegradationActive.DeleteLabelValues(uvr.Namespace, uvr.Name)
+}
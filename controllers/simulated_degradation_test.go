@@ -0,0 +1,107 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestReconciler_SimulatedDegradation(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	uvr := createTestUVR("test-simulated-degradation", "default")
+	uvr.Annotations = map[string]string{SimulateDegradationAnnotation: "5m"}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).WithStatusSubresource(uvr).Build()
+	reconciler := createTestReconciler(c, s)
+	recorder := reconciler.Recorder.(*record.FakeRecorder)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(uvr), uvr))
+
+	healthy := &adapters.ReplicationStatus{State: "replica", Mode: "async", Health: adapters.ReplicationHealthHealthy}
+	active := func() bool {
+		metric := &dto.Metric{}
+		if err := simulatedDegradationActive.WithLabelValues(uvr.Namespace, uvr.Name).(prometheus.Gauge).Write(metric); err != nil {
+			return false
+		}
+		return metric.GetGauge().GetValue() == 1
+	}
+
+	// Namespaces outside the allowlist cannot fake their health
+	status := reconciler.simulateDegradation(ctx, uvr, healthy, reconciler.Log)
+	assert.Same(t, healthy, status)
+	assert.Equal(t, "NotAllowed", reconciler.getCondition(uvr, simulatedDegradationCondition).Reason)
+	drainEvents(recorder)
+
+	reconciler.SimulateDegradationNamespaces = []string{"default"}
+	status = reconciler.simulateDegradation(ctx, uvr, healthy, reconciler.Log)
+	reconciler.updateStatusFromEngineStatus(uvr, status, reconciler.Log)
+	assert.Equal(t, adapters.ReplicationHealthHealthy, healthy.Health, "the backend status is left alone")
+	assert.Equal(t, adapters.ReplicationHealthDegraded, status.Health)
+
+	degraded := reconciler.getCondition(uvr, degradedCondition)
+	require.NotNil(t, degraded)
+	assert.Equal(t, metav1.ConditionTrue, degraded.Status)
+	assert.Equal(t, string(adapters.DegradedReasonSimulated), degraded.Reason)
+	assert.Contains(t, degraded.Message, "SYNTHETIC")
+	assert.True(t, active(), "the metric marks the degradation as synthetic")
+
+	events := drainEvents(recorder)
+	require.NotEmpty(t, events)
+	assert.Contains(t, events[0], "SimulatedDegradationStarted")
+	for _, event := range events {
+		assert.Contains(t, event, "SYNTHETIC")
+	}
+
+	// Requeues are shortened so the simulation ends on time
+	assert.LessOrEqual(t, reconciler.simulatedDegradationRequeue(uvr, time.Hour), 5*time.Minute+time.Second)
+
+	// Once the duration has passed the backend health is reported again
+	for i := range uvr.Status.Conditions {
+		if uvr.Status.Conditions[i].Type == simulatedDegradationCondition {
+			uvr.Status.Conditions[i].LastTransitionTime = metav1.NewTime(time.Now().Add(-6 * time.Minute))
+		}
+	}
+	status = reconciler.simulateDegradation(ctx, uvr, healthy, reconciler.Log)
+	reconciler.updateStatusFromEngineStatus(uvr, status, reconciler.Log)
+	assert.Same(t, healthy, status)
+	assert.Equal(t, metav1.ConditionFalse, reconciler.getCondition(uvr, degradedCondition).Status)
+
+	ended := reconciler.getCondition(uvr, simulatedDegradationCondition)
+	assert.Equal(t, metav1.ConditionFalse, ended.Status)
+	assert.Equal(t, "Expired", ended.Reason)
+	assert.False(t, active())
+	assert.Contains(t, drainEvents(recorder)[0], "SimulatedDegradationEnded")
+
+	stored := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(uvr), stored))
+	assert.NotContains(t, stored.Annotations, SimulateDegradationAnnotation, "the request is removed when it ends")
+	assert.NotContains(t, uvr.Annotations, SimulateDegradationAnnotation)
+}
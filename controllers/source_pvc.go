@@ -0,0 +1,152 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+// sourcePVCMissingCondition reports that the source PVC does not exist or is not Bound
+const sourcePVCMissingCondition = "SourcePVCMissing"
+
+// MissingSourcePVCPolicy selects what happens to a UVR whose source PVC does not exist or
+// is not Bound yet
+type MissingSourcePVCPolicy string
+
+const (
+	// MissingSourcePVCIgnore skips the check and leaves the backend to report the problem
+	MissingSourcePVCIgnore MissingSourcePVCPolicy = "ignore"
+	// MissingSourcePVCWait holds the UVR and checks again until the PVC is Bound
+	MissingSourcePVCWait MissingSourcePVCPolicy = "wait"
+	// MissingSourcePVCFail fails the UVR without retrying until its spec changes
+	MissingSourcePVCFail MissingSourcePVCPolicy = "fail"
+)
+
+// ParseMissingSourcePVCPolicy validates a missing source PVC policy name, defaulting to
+// ignore when empty
+func ParseMissingSourcePVCPolicy(value string) (MissingSourcePVCPolicy, error) {
+	switch policy := MissingSourcePVCPolicy(value); policy {
+	case "":
+		return MissingSourcePVCIgnore, nil
+	case MissingSourcePVCIgnore, MissingSourcePVCWait, MissingSourcePVCFail:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown missing source PVC policy %q, must be one of: %s, %s, %s",
+			value, MissingSourcePVCWait, MissingSourcePVCFail, MissingSourcePVCIgnore)
+	}
+}
+
+// getMissingSourcePVCPolicy returns the configured policy, ignore when unset
+func (r *UnifiedVolumeReplicationReconciler) getMissingSourcePVCPolicy() MissingSourcePVCPolicy {
+	if r.MissingSourcePVCPolicy == "" {
+		return MissingSourcePVCIgnore
+	}
+	return r.MissingSourcePVCPolicy
+}
+
+// checkSourcePVC verifies that the source PVC exists and is Bound before anything is created
+// on the backend, which would otherwise reference a PVC it cannot find. When the PVC is not
+// ready it sets the SourcePVCMissing and Ready conditions, and returns true with the result
+// the reconcile should end with: a requeue under the wait policy, none under fail. Once the
+// PVC was found Bound it is not read again until the spec changes.
+func (r *UnifiedVolumeReplicationReconciler) checkSourcePVC(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) (bool, ctrl.Result) {
+	policy := r.getMissingSourcePVCPolicy()
+	if policy == MissingSourcePVCIgnore {
+		return false, ctrl.Result{}
+	}
+	if existing := r.getCondition(uvr, sourcePVCMissingCondition); existing != nil && existing.Status == metav1.ConditionFalse &&
+		existing.ObservedGeneration == uvr.Generation {
+		return false, ctrl.Result{}
+	}
+
+	key := types.NamespacedName{Name: uvr.Spec.VolumeMapping.Source.PvcName, Namespace: uvr.Spec.VolumeMapping.Source.Namespace}
+	if key.Namespace == "" {
+		key.Namespace = uvr.Namespace
+	}
+
+	var reason, message string
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.Get(ctx, key, pvc); err != nil {
+		if !errors.IsNotFound(err) {
+			// Leave transient API errors to the backend rather than holding the UVR
+			log.Error(err, "Failed to read source PVC", "pvc", key)
+			return false, ctrl.Result{}
+		}
+		reason = "NotFound"
+		message = fmt.Sprintf("Source PVC %s not found", key)
+	} else if pvc.Status.Phase != corev1.ClaimBound {
+		reason = "NotBound"
+		message = fmt.Sprintf("Source PVC %s is %s, not Bound", key, pvcPhase(pvc))
+	}
+
+	if reason == "" {
+		r.updateCondition(uvr, metav1.Condition{
+			Type:               sourcePVCMissingCondition,
+			Status:             metav1.ConditionFalse,
+			Reason:             "Bound",
+			Message:            fmt.Sprintf("Source PVC %s is Bound", key),
+			ObservedGeneration: uvr.Generation,
+		})
+		return false, ctrl.Result{}
+	}
+
+	result := ctrl.Result{RequeueAfter: requeueDelayError}
+	if policy == MissingSourcePVCFail {
+		message += ", not retrying until the spec changes"
+		result = ctrl.Result{}
+	} else {
+		message += ", waiting"
+	}
+
+	log.Info("Source PVC not ready", "pvc", key, "reason", reason, "policy", policy)
+	if existing := r.getCondition(uvr, "Ready"); existing == nil || existing.Reason != sourcePVCMissingCondition {
+		r.recordEventf(uvr, corev1.EventTypeWarning, sourcePVCMissingCondition, "%s", message)
+	}
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               sourcePVCMissingCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             sourcePVCMissingCondition,
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+	return true, result
+}
+
+// pvcPhase returns the PVC phase, Pending when the API server has not set one yet
+func pvcPhase(pvc *corev1.PersistentVolumeClaim) corev1.PersistentVolumeClaimPhase {
+	if pvc.Status.Phase == "" {
+		return corev1.ClaimPending
+	}
+	return pvc.Status.Phase
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestParseMissingSourcePVCPolicy(t *testing.T) {
+	policy, err := ParseMissingSourcePVCPolicy("")
+	require.NoError(t, err)
+	assert.Equal(t, MissingSourcePVCIgnore, policy)
+
+	for _, value := range []string{"wait", "fail", "ignore"} {
+		policy, err := ParseMissingSourcePVCPolicy(value)
+		require.NoError(t, err)
+		assert.Equal(t, MissingSourcePVCPolicy(value), policy)
+	}
+
+	_, err = ParseMissingSourcePVCPolicy("retry")
+	assert.EqualError(t, err, `unknown missing source PVC policy "retry", must be one of: wait, fail, ignore`)
+}
+
+func TestReconciler_SourcePVCPreflight(t *testing.T) {
+	sourcePVC := func(phase corev1.PersistentVolumeClaimPhase) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "source-pvc", Namespace: "default"},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: phase},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		pvc           *corev1.PersistentVolumeClaim
+		policy        MissingSourcePVCPolicy
+		expectMissing bool
+		expectBound   bool
+		expectReason  string
+		expectMessage string
+		expectRequeue bool
+		expectCreated bool
+	}{
+		{
+			name:          "missing PVC waits",
+			policy:        MissingSourcePVCWait,
+			expectMissing: true,
+			expectReason:  "NotFound",
+			expectMessage: "Source PVC default/source-pvc not found, waiting",
+			expectRequeue: true,
+		},
+		{
+			name:          "missing PVC fails",
+			policy:        MissingSourcePVCFail,
+			expectMissing: true,
+			expectReason:  "NotFound",
+			expectMessage: "Source PVC default/source-pvc not found, not retrying until the spec changes",
+		},
+		{
+			name:          "unbound PVC waits",
+			pvc:           sourcePVC(corev1.ClaimPending),
+			policy:        MissingSourcePVCWait,
+			expectMissing: true,
+			expectReason:  "NotBound",
+			expectMessage: "Source PVC default/source-pvc is Pending, not Bound, waiting",
+			expectRequeue: true,
+		},
+		{
+			name:          "bound PVC proceeds",
+			pvc:           sourcePVC(corev1.ClaimBound),
+			policy:        MissingSourcePVCWait,
+			expectBound:   true,
+			expectCreated: true,
+		},
+		{
+			name:          "ignore skips the check",
+			policy:        MissingSourcePVCIgnore,
+			expectCreated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := createTestScheme(t)
+			require.NoError(t, apiextensionsv1.AddToScheme(s))
+
+			uvr := createTestUVR("test-source-pvc", "default")
+			uvr.Finalizers = []string{unifiedReplicationFinalizer}
+			objects := append(tridentCRDs(""), uvr)
+			if tt.pvc != nil {
+				objects = append(objects, tt.pvc)
+			}
+			c := fake.NewClientBuilder().WithScheme(s).WithObjects(objects...).WithStatusSubresource(uvr).Build()
+
+			registry := adapters.NewRegistry()
+			require.NoError(t, registry.RegisterFactory(adapters.NewTridentAdapterFactory()))
+			reconciler := createTestReconciler(c, s)
+			reconciler.AdapterRegistry = registry
+			reconciler.ControllerEngine = pkg.NewControllerEngine(c, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+				registry, pkg.DefaultControllerEngineConfig())
+			reconciler.MissingSourcePVCPolicy = tt.policy
+
+			req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+			result, err := reconciler.Reconcile(ctx, req)
+			require.NoError(t, err)
+
+			tmr := &unstructured.Unstructured{}
+			tmr.SetGroupVersionKind(adapters.TridentMirrorRelationshipGVK)
+			err = c.Get(ctx, req.NamespacedName, tmr)
+			if tt.expectCreated {
+				assert.NoError(t, err, "the backend resource is created")
+			} else {
+				assert.Error(t, err, "nothing is created on the backend")
+			}
+
+			updated := &replicationv1alpha1.UnifiedVolumeReplication{}
+			require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+			missing := reconciler.getCondition(updated, sourcePVCMissingCondition)
+			if tt.expectBound {
+				require.NotNil(t, missing)
+				assert.Equal(t, metav1.ConditionFalse, missing.Status)
+				assert.Equal(t, "Bound", missing.Reason)
+				return
+			}
+			if !tt.expectMissing {
+				assert.Nil(t, missing)
+				return
+			}
+
+			require.NotNil(t, missing)
+			assert.Equal(t, metav1.ConditionTrue, missing.Status)
+			assert.Equal(t, tt.expectReason, missing.Reason)
+			ready := reconciler.getCondition(updated, "Ready")
+			require.NotNil(t, ready)
+			assert.Equal(t, sourcePVCMissingCondition, ready.Reason)
+			assert.Equal(t, tt.expectMessage, ready.Message)
+			if tt.expectRequeue {
+				assert.Equal(t, requeueDelayError, result.RequeueAfter)
+			} else {
+				assert.Zero(t, result.RequeueAfter)
+			}
+		})
+	}
+}
+
+func TestReconciler_SourcePVCBecomesBound(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	uvr := createTestUVR("test-source-pvc-bound", "default")
+	uvr.Status.Conditions = []metav1.Condition{{Type: sourcePVCMissingCondition, Status: metav1.ConditionTrue, Reason: "NotBound"}}
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "source-pvc", Namespace: "default"},
+		Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(pvc).Build()
+	reconciler := createTestReconciler(c, s)
+	reconciler.MissingSourcePVCPolicy = MissingSourcePVCWait
+
+	missing, _ := reconciler.checkSourcePVC(ctx, uvr, reconciler.Log)
+	assert.False(t, missing)
+	condition := reconciler.getCondition(uvr, sourcePVCMissingCondition)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "Bound", condition.Reason)
+
+	// A Bound PVC is not read again until the spec changes
+	require.NoError(t, c.Delete(ctx, pvc))
+	missing, _ = reconciler.checkSourcePVC(ctx, uvr, reconciler.Log)
+	assert.False(t, missing)
+
+	uvr.Generation++
+	missing, _ = reconciler.checkSourcePVC(ctx, uvr, reconciler.Log)
+	assert.True(t, missing)
+	assert.Equal(t, "NotFound", reconciler.getCondition(uvr, sourcePVCMissingCondition).Reason)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+// sourceTopologyMismatchCondition reports whether the source volume lives in the region the
+// source endpoint declares
+const sourceTopologyMismatchCondition = "SourceTopologyMismatch"
+
+// volumeTopology is where a persistent volume lives, empty where the PV does not say
+type volumeTopology struct {
+	Region string
+	Zone   string
+}
+
+// String describes the topology as region/zone, or just the region
+func (t volumeTopology) String() string {
+	if t.Zone == "" {
+		return t.Region
+	}
+	return t.Region + "/" + t.Zone
+}
+
+// pvTopology reads the region and zone of a PV from its node affinity, which the provisioner
+// sets to where the volume can be attached, falling back to the well-known topology labels
+func pvTopology(pv *corev1.PersistentVolume) volumeTopology {
+	topology := volumeTopology{
+		Region: firstLabel(pv.Labels, corev1.LabelTopologyRegion, corev1.LabelFailureDomainBetaRegion),
+		Zone:   firstLabel(pv.Labels, corev1.LabelTopologyZone, corev1.LabelFailureDomainBetaZone),
+	}
+	if pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+		return topology
+	}
+	for _, term := range pv.Spec.NodeAffinity.Required.NodeSelectorTerms {
+		for _, expr := range term.MatchExpressions {
+			// Only a single allowed value pins the volume to one place
+			if expr.Operator != corev1.NodeSelectorOpIn || len(expr.Values) != 1 {
+				continue
+			}
+			switch expr.Key {
+			case corev1.LabelTopologyRegion, corev1.LabelFailureDomainBetaRegion:
+				topology.Region = expr.Values[0]
+			case corev1.LabelTopologyZone, corev1.LabelFailureDomainBetaZone:
+				topology.Zone = expr.Values[0]
+			}
+		}
+	}
+	return topology
+}
+
+// firstLabel returns the value of the first of keys set in labels
+func firstLabel(labels map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if value := labels[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// checkSourceTopology compares the region of the PV bound to the source PVC with
+// spec.sourceEndpoint.region, and warns when they differ: a misdeclared endpoint misleads DR
+// planning about which site holds the data. The check is informational and never holds the
+// reconcile; nothing is reported while the PVC is unbound or its PV carries no region.
+func (r *UnifiedVolumeReplicationReconciler) checkSourceTopology(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) {
+	key := types.NamespacedName{Name: uvr.Spec.VolumeMapping.Source.PvcName, Namespace: uvr.Spec.VolumeMapping.Source.Namespace}
+	if key.Namespace == "" {
+		key.Namespace = uvr.Namespace
+	}
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.Get(ctx, key, pvc); err != nil || pvc.Spec.VolumeName == "" {
+		return
+	}
+	pv := &corev1.PersistentVolume{}
+	if err := r.Get(ctx, types.NamespacedName{Name: pvc.Spec.VolumeName}, pv); err != nil {
+		log.V(1).Info("Cannot read the source PV topology", "pv", pvc.Spec.VolumeName, "error", err.Error())
+		return
+	}
+	topology := pvTopology(pv)
+	if topology.Region == "" {
+		return
+	}
+
+	declared := uvr.Spec.SourceEndpoint.Region
+	if topology.Region == declared {
+		r.updateCondition(uvr, metav1.Condition{
+			Type:               sourceTopologyMismatchCondition,
+			Status:             metav1.ConditionFalse,
+			Reason:             "RegionMatches",
+			Message:            fmt.Sprintf("Source volume %s is in %s", pv.Name, topology),
+			ObservedGeneration: uvr.Generation,
+		})
+		return
+	}
+
+	message := fmt.Sprintf("Source endpoint declares region %s, but source volume %s is in %s", declared, pv.Name, topology)
+	if existing := r.getCondition(uvr, sourceTopologyMismatchCondition); existing == nil || existing.Status != metav1.ConditionTrue {
+		log.Info("Source volume topology contradicts the source endpoint", "declared", declared, "actual", topology.String())
+		r.recordEventf(uvr, corev1.EventTypeWarning, sourceTopologyMismatchCondition, "%s", message)
+	}
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               sourceTopologyMismatchCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "RegionMismatch",
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// zonalPV returns a PV the provisioner pinned to a zone of a region
+func zonalPV(name, region, zone string) *corev1.PersistentVolume {
+	return &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1.PersistentVolumeSpec{
+			NodeAffinity: &corev1.VolumeNodeAffinity{
+				Required: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{
+							{Key: corev1.LabelTopologyRegion, Operator: corev1.NodeSelectorOpIn, Values: []string{region}},
+							{Key: corev1.LabelTopologyZone, Operator: corev1.NodeSelectorOpIn, Values: []string{zone}},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func TestPVTopology(t *testing.T) {
+	assert.Equal(t, volumeTopology{Region: "us-west-1", Zone: "us-west-1b"}, pvTopology(zonalPV("pv", "us-west-1", "us-west-1b")))
+
+	labelled := &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+		corev1.LabelFailureDomainBetaRegion: "eu-central-1",
+	}}}
+	assert.Equal(t, volumeTopology{Region: "eu-central-1"}, pvTopology(labelled))
+
+	// A volume that may attach in several regions is not pinned to one
+	multiRegion := zonalPV("pv", "us-west-1", "us-west-1b")
+	multiRegion.Spec.NodeAffinity.Required.NodeSelectorTerms[0].MatchExpressions[0].Values = []string{"us-west-1", "us-west-2"}
+	assert.Equal(t, volumeTopology{Zone: "us-west-1b"}, pvTopology(multiRegion))
+
+	assert.Equal(t, volumeTopology{}, pvTopology(&corev1.PersistentVolume{}))
+}
+
+func TestReconciler_SourceTopologyMismatch(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	tests := []struct {
+		name          string
+		pv            *corev1.PersistentVolume
+		expectStatus  metav1.ConditionStatus
+		expectReason  string
+		expectMessage string
+		expectEvent   string
+	}{
+		{
+			name:          "volume in another region",
+			pv:            zonalPV("pv-source", "us-west-1", "us-west-1b"),
+			expectStatus:  metav1.ConditionTrue,
+			expectReason:  "RegionMismatch",
+			expectMessage: "Source endpoint declares region us-east-1, but source volume pv-source is in us-west-1/us-west-1b",
+			expectEvent:   "Warning SourceTopologyMismatch Source endpoint declares region us-east-1, but source volume pv-source is in us-west-1/us-west-1b",
+		},
+		{
+			name:          "volume in the declared region",
+			pv:            zonalPV("pv-source", "us-east-1", "us-east-1a"),
+			expectStatus:  metav1.ConditionFalse,
+			expectReason:  "RegionMatches",
+			expectMessage: "Source volume pv-source is in us-east-1/us-east-1a",
+		},
+		{
+			name: "volume without topology",
+			pv:   &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-source"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uvr := createTestUVR("test-topology", "default")
+			uvr.Spec.SourceEndpoint.Region = "us-east-1"
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: uvr.Spec.VolumeMapping.Source.PvcName, Namespace: "default"},
+				Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "pv-source"},
+				Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+			}
+			c := fake.NewClientBuilder().WithScheme(s).WithObjects(uvr, pvc, tt.pv).Build()
+			reconciler := createTestReconciler(c, s)
+			recorder := reconciler.Recorder.(*record.FakeRecorder)
+			require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(uvr), uvr))
+
+			reconciler.checkSourceTopology(ctx, uvr, reconciler.Log)
+
+			cond := reconciler.getCondition(uvr, sourceTopologyMismatchCondition)
+			if tt.expectStatus == "" {
+				assert.Nil(t, cond, "nothing is reported without a known region")
+				assert.Empty(t, drainEvents(recorder))
+				return
+			}
+			require.NotNil(t, cond)
+			assert.Equal(t, tt.expectStatus, cond.Status)
+			assert.Equal(t, tt.expectReason, cond.Reason)
+			assert.Equal(t, tt.expectMessage, cond.Message)
+
+			events := drainEvents(recorder)
+			if tt.expectEvent == "" {
+				assert.Empty(t, events)
+				return
+			}
+			assert.Equal(t, []string{tt.expectEvent}, events)
+
+			// The warning is emitted when the mismatch appears, not on every reconcile
+			reconciler.checkSourceTopology(ctx, uvr, reconciler.Log)
+			assert.Empty(t, drainEvents(recorder))
+		})
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+const (
+	// SurvivingPrimaryAnnotation names the endpoint cluster that keeps the primary role
+	// when resolving a dual-primary (split-brain) situation
+	SurvivingPrimaryAnnotation = "replication.storage.io/surviving-primary"
+
+	// splitBrainCondition is True while both endpoints claim the primary role
+	splitBrainCondition = "SplitBrain"
+)
+
+// isSplitBrain returns true while a dual-primary situation is unresolved
+func (r *UnifiedVolumeReplicationReconciler) isSplitBrain(uvr *replicationv1alpha1.UnifiedVolumeReplication) bool {
+	cond := r.getCondition(uvr, splitBrainCondition)
+	return cond != nil && cond.Status == metav1.ConditionTrue
+}
+
+// recordSplitBrain raises the SplitBrain condition when the backend reports that both
+// endpoints hold the primary role. The condition is only cleared by an explicit resolution.
+// After a resolution keeping the local volume as the primary the peer still claims the role
+// until the operator managing it demotes it, which is not a new split-brain.
+func (r *UnifiedVolumeReplicationReconciler) recordSplitBrain(uvr *replicationv1alpha1.UnifiedVolumeReplication, status *adapters.ReplicationStatus, log logr.Logger) {
+	if !status.DualPrimary || r.isSplitBrain(uvr) {
+		return
+	}
+	if resolution := uvr.Status.SplitBrainResolution; resolution != nil && resolution.HeldState == "" &&
+		resolution.ObservedGeneration == uvr.Generation {
+		log.V(1).Info("Peer still claims the primary role, waiting for it to be demoted", "survivor", resolution.Survivor)
+		return
+	}
+
+	log.Info("Dual-primary detected, halting reconciliation until a surviving primary is selected")
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               splitBrainCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "DualPrimary",
+		Message:            fmt.Sprintf("Both endpoints claim the primary role; set the %s annotation to the surviving cluster", SurvivingPrimaryAnnotation),
+		ObservedGeneration: uvr.Generation,
+	})
+	r.recordEventf(uvr, corev1.EventTypeWarning, "SplitBrainDetected", "Both endpoints claim the primary role")
+}
+
+// resolveSplitBrain honors the surviving-primary annotation and records the choice in
+// status.splitBrainResolution; the spec is left as the user wrote it. When the destination
+// cluster survives, the local (source endpoint) volume is demoted and resynced from the
+// survivor, and applySplitBrainResolution holds it as a replica until the spec changes. When
+// the local cluster survives, nothing is issued to the local volume: the peer is the side to
+// demote and resync, which the operator managing it does once the same annotation names this
+// cluster. Returns false while no survivor has been selected.
+func (r *UnifiedVolumeReplicationReconciler) resolveSplitBrain(ctx context.Context, adapter adapters.ReplicationAdapter, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) (bool, error) {
+	survivor, ok := uvr.Annotations[SurvivingPrimaryAnnotation]
+	if !ok {
+		return false, nil
+	}
+
+	sourceCluster := uvr.Spec.SourceEndpoint.Cluster
+	destinationCluster := uvr.Spec.DestinationEndpoint.Cluster
+	if survivor != sourceCluster && survivor != destinationCluster {
+		return false, fmt.Errorf("surviving primary %q is not one of the endpoints (%s, %s)", survivor, sourceCluster, destinationCluster)
+	}
+
+	var held replicationv1alpha1.ReplicationState
+	if survivor == destinationCluster {
+		log.Info("Demoting local volume in favor of surviving primary", "survivor", survivor)
+		if err := r.ControllerEngine.RunOperation(ctx, uvr, "demote", log, func(ctx context.Context) error {
+			return adapter.DemoteSource(ctx, uvr)
+		}); err != nil {
+			return false, fmt.Errorf("failed to demote non-surviving primary: %w", err)
+		}
+		if err := r.ControllerEngine.RunOperation(ctx, uvr, "resync", log, func(ctx context.Context) error {
+			return adapter.ResyncReplication(ctx, uvr)
+		}); err != nil {
+			return false, fmt.Errorf("failed to resync from surviving primary: %w", err)
+		}
+		held = replicationv1alpha1.ReplicationStateReplica
+	} else {
+		log.Info("Local volume is the surviving primary, the peer is demoted and resynced from it", "survivor", survivor)
+	}
+
+	patched := uvr.DeepCopy()
+	delete(patched.Annotations, SurvivingPrimaryAnnotation)
+	if err := r.Patch(ctx, patched, client.MergeFrom(uvr)); err != nil {
+		return false, fmt.Errorf("failed to remove the %s annotation: %w", SurvivingPrimaryAnnotation, err)
+	}
+	uvr.Annotations = patched.Annotations
+	uvr.ResourceVersion = patched.ResourceVersion
+
+	uvr.Status.SplitBrainResolution = &replicationv1alpha1.SplitBrainResolution{
+		Survivor:           survivor,
+		ObservedGeneration: uvr.Generation,
+		HeldState:          held,
+		Time:               metav1.Now(),
+	}
+	if held != "" {
+		uvr.Spec.ReplicationState = held
+	}
+
+	// The non-surviving side is rebuilt from the survivor and has to be established again
+	r.restartEstablishment(uvr, fmt.Sprintf("Resyncing from surviving primary %s", survivor))
+
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               splitBrainCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Resolved",
+		Message:            fmt.Sprintf("Resolved with %s as the surviving primary", survivor),
+		ObservedGeneration: uvr.Generation,
+	})
+	r.recordEventf(uvr, corev1.EventTypeNormal, "SplitBrainResolved", "Resolved with %s as the surviving primary", survivor)
+	return true, nil
+}
+
+// applySplitBrainResolution enforces a resolution in which the peer survived: while the spec
+// stays at the generation it was made at, the local volume is held as a replica in memory so
+// later reconciles do not promote it again. The spec itself is never changed. A resolution of
+// an earlier generation is cleared: the user has asked for something new.
+func (r *UnifiedVolumeReplicationReconciler) applySplitBrainResolution(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) {
+	resolution := uvr.Status.SplitBrainResolution
+	if resolution == nil {
+		return
+	}
+	if resolution.ObservedGeneration != uvr.Generation {
+		log.Info("Spec changed since the split-brain was resolved, following it", "survivor", resolution.Survivor)
+		uvr.Status.SplitBrainResolution = nil
+		return
+	}
+	if resolution.HeldState != "" {
+		explainf(ctx, "Split-brain resolved in favor of %s, holding replicationState at %s", resolution.Survivor, resolution.HeldState)
+		uvr.Spec.ReplicationState = resolution.HeldState
+	}
+}
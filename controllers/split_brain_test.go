@@ -0,0 +1,178 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+func newSplitBrainMockAdapter(t *testing.T, c client.Client, uvr *replicationv1alpha1.UnifiedVolumeReplication) *adapters.MockAdapter {
+	mockConfig := adapters.DefaultMockConfig()
+	mockConfig.StateTransitions = false
+
+	adapter := adapters.NewMockAdapter(translation.BackendCeph, c, translation.NewEngine(),
+		adapters.DefaultAdapterConfig(translation.BackendCeph), mockConfig)
+	require.NoError(t, adapter.Initialize(context.Background()))
+	require.NoError(t, adapter.EnsureReplication(context.Background(), uvr))
+	return adapter
+}
+
+func TestSplitBrain_SurvivingPrimaryDemotesLocal(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	uvr := createTestUVR("split-brain", "default")
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+
+	fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).Build()
+	reconciler := createTestReconciler(fakeClient, s)
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(uvr), uvr))
+
+	adapter := newSplitBrainMockAdapter(t, fakeClient, uvr)
+
+	// Both sides report primary: reconciliation halts until a survivor is chosen
+	reconciler.updateStatusFromEngineStatus(uvr, &adapters.ReplicationStatus{
+		State:       "source",
+		Health:      adapters.ReplicationHealthDegraded,
+		DualPrimary: true,
+	}, reconciler.Log)
+	require.True(t, reconciler.isSplitBrain(uvr))
+
+	resolved, err := reconciler.resolveSplitBrain(ctx, adapter, uvr, reconciler.Log)
+	require.NoError(t, err)
+	assert.False(t, resolved)
+
+	// The destination cluster survives, so the local side is demoted and resynced
+	uvr.Annotations = map[string]string{SurvivingPrimaryAnnotation: "dest-cluster"}
+	resolved, err = reconciler.resolveSplitBrain(ctx, adapter, uvr, reconciler.Log)
+	require.NoError(t, err)
+	assert.True(t, resolved)
+
+	repl, ok := adapter.GetMockReplication(uvr)
+	require.True(t, ok)
+	require.Len(t, repl.Events, 3)
+	assert.Equal(t, adapters.EventTypeDemoted, repl.Events[1].Type)
+	assert.Equal(t, adapters.EventTypeResynced, repl.Events[2].Type)
+
+	cond := reconciler.getCondition(uvr, splitBrainCondition)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, "Resolved", cond.Reason)
+
+	// The resynced replica has to complete its initial sync again
+	cond = reconciler.getCondition(uvr, "InitialSyncComplete")
+	require.NotNil(t, cond)
+	assert.Equal(t, "FullResync", cond.Reason)
+	assert.NotNil(t, uvr.Status.EstablishmentStartTime)
+
+	// The choice is recorded in status and the spec is left as the user wrote it
+	require.NotNil(t, uvr.Status.SplitBrainResolution)
+	assert.Equal(t, "dest-cluster", uvr.Status.SplitBrainResolution.Survivor)
+	assert.Equal(t, replicationv1alpha1.ReplicationStateReplica, uvr.Status.SplitBrainResolution.HeldState)
+	assert.Equal(t, replicationv1alpha1.ReplicationStateReplica, uvr.Spec.ReplicationState, "held in memory")
+
+	stored := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(uvr), stored))
+	assert.NotContains(t, stored.Annotations, SurvivingPrimaryAnnotation)
+	assert.Equal(t, replicationv1alpha1.ReplicationStateSource, stored.Spec.ReplicationState)
+
+	// Later reconciles keep the local volume a replica while the spec is unchanged
+	stored.Status.SplitBrainResolution = uvr.Status.SplitBrainResolution
+	reconciler.applySplitBrainResolution(ctx, stored, reconciler.Log)
+	assert.Equal(t, replicationv1alpha1.ReplicationStateReplica, stored.Spec.ReplicationState)
+
+	// A spec change ends the hold
+	stored.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+	stored.Generation++
+	reconciler.applySplitBrainResolution(ctx, stored, reconciler.Log)
+	assert.Equal(t, replicationv1alpha1.ReplicationStateSource, stored.Spec.ReplicationState)
+	assert.Nil(t, stored.Status.SplitBrainResolution)
+}
+
+func TestSplitBrain_LocalSurvivorKeepsPrimary(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	uvr := createTestUVR("split-brain-local", "default")
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+	uvr.Annotations = map[string]string{SurvivingPrimaryAnnotation: "source-cluster"}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).Build()
+	reconciler := createTestReconciler(fakeClient, s)
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(uvr), uvr))
+
+	adapter := newSplitBrainMockAdapter(t, fakeClient, uvr)
+
+	resolved, err := reconciler.resolveSplitBrain(ctx, adapter, uvr, reconciler.Log)
+	require.NoError(t, err)
+	assert.True(t, resolved)
+
+	// The peer is demoted and rebuilt from the local survivor by the operator managing it;
+	// nothing is issued to the local primary
+	repl, ok := adapter.GetMockReplication(uvr)
+	require.True(t, ok)
+	assert.Len(t, repl.Events, 1, "only the creation event is expected")
+	assert.Equal(t, replicationv1alpha1.ReplicationStateSource, uvr.Spec.ReplicationState)
+	require.NotNil(t, uvr.Status.SplitBrainResolution)
+	assert.Equal(t, "source-cluster", uvr.Status.SplitBrainResolution.Survivor)
+	assert.Empty(t, uvr.Status.SplitBrainResolution.HeldState)
+
+	// The peer claims the primary role until it is demoted, which is not a new split-brain
+	reconciler.recordSplitBrain(uvr, &adapters.ReplicationStatus{State: "source", DualPrimary: true}, reconciler.Log)
+	assert.False(t, reconciler.isSplitBrain(uvr))
+
+	cond := reconciler.getCondition(uvr, "InitialSyncComplete")
+	require.NotNil(t, cond)
+	assert.Equal(t, "FullResync", cond.Reason)
+
+	stored := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(uvr), stored))
+	assert.NotContains(t, stored.Annotations, SurvivingPrimaryAnnotation)
+}
+
+func TestSplitBrain_RejectsUnknownSurvivor(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	uvr := createTestUVR("split-brain-invalid", "default")
+	uvr.Annotations = map[string]string{SurvivingPrimaryAnnotation: "other-cluster"}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).Build()
+	reconciler := createTestReconciler(fakeClient, s)
+
+	adapter := newSplitBrainMockAdapter(t, fakeClient, uvr)
+
+	resolved, err := reconciler.resolveSplitBrain(ctx, adapter, uvr, reconciler.Log)
+	require.Error(t, err)
+	assert.False(t, resolved)
+	assert.Contains(t, err.Error(), "not one of the endpoints")
+
+	repl, ok := adapter.GetMockReplication(uvr)
+	require.True(t, ok)
+	assert.Len(t, repl.Events, 1, "only the creation event is expected")
+}
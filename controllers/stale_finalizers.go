@@ -0,0 +1,113 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// removeStaleFinalizers runs once at startup, after the cache has synced. UVRs left being
+// deleted by an earlier operator version, or by a deletion that was interrupted, can keep the
+// finalizer after their backend resource is gone, which blocks the deletion for good. Those
+// whose adapter confirms the backend resource does not exist go through the same cleanup as
+// handleDeletion, serialized with it; UVRs whose backend cannot confirm it are left to the
+// regular deletion path. It returns how
+// many were unstuck.
+func (r *UnifiedVolumeReplicationReconciler) removeStaleFinalizers(ctx context.Context) (int, error) {
+	log := r.Log.WithName("stale-finalizers")
+
+	list := &replicationv1alpha1.UnifiedVolumeReplicationList{}
+	if err := r.List(ctx, list); err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for i := range list.Items {
+		uvr := &list.Items[i]
+		if uvr.DeletionTimestamp.IsZero() || !controllerutil.ContainsFinalizer(uvr, unifiedReplicationFinalizer) ||
+			!r.watchesNamespace(uvr.Namespace) {
+			continue
+		}
+		if r.removeStaleFinalizer(ctx, uvr, log.WithValues("unifiedvolumereplication", client.ObjectKeyFromObject(uvr))) {
+			removed++
+		}
+	}
+	if removed > 0 {
+		log.Info("Removed stale finalizers", "count", removed)
+	}
+	return removed, nil
+}
+
+// removeStaleFinalizer finishes the deletion of a UVR whose backend resource is confirmed
+// absent: the rest of the replication, such as an auto-provisioned destination volume, is
+// cleaned up as handleDeletion would, then the finalizer is removed. It reports whether the
+// finalizer was removed.
+func (r *UnifiedVolumeReplicationReconciler) removeStaleFinalizer(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) bool {
+	// A reconcile already handling the deletion goes first; the UVR is read again after it
+	defer r.lockDeletion(uvr)()
+	if err := r.Get(ctx, client.ObjectKeyFromObject(uvr), uvr); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "Failed to read UVR, leaving the finalizer")
+		}
+		return false
+	}
+	if !controllerutil.ContainsFinalizer(uvr, unifiedReplicationFinalizer) {
+		return false
+	}
+
+	adapter, release, err := r.acquireAdapter(ctx, uvr, log)
+	if err != nil {
+		log.V(1).Info("No adapter to check the backend resource, leaving the finalizer", "error", err.Error())
+		return false
+	}
+	defer release()
+
+	detector, ok := adapter.(adapters.RecreationDetector)
+	if !ok {
+		log.V(1).Info("Backend cannot confirm whether its resource exists, leaving the finalizer", "backend", adapter.GetBackendType())
+		return false
+	}
+	exists, err := detector.BackendResourceExists(ctx, uvr)
+	if err != nil {
+		log.Error(err, "Failed to check the backend resource, leaving the finalizer")
+		return false
+	}
+	if exists {
+		return false
+	}
+
+	if err := adapter.DeleteReplication(ctx, uvr); err != nil {
+		log.Error(err, "Failed to clean up the replication, leaving the finalizer")
+		return false
+	}
+	if err := r.finishDeletion(ctx, uvr, log); err != nil {
+		return false
+	}
+	log.Info("Removed stale finalizer, the backend resource no longer exists", "backend", adapter.GetBackendType())
+	r.recordEventf(uvr, corev1.EventTypeNormal, "StaleFinalizerRemoved",
+		"Removed the finalizer of a deletion left unfinished: the %s backend resource no longer exists", adapter.GetBackendType())
+	return true
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestReconciler_RemovesStaleFinalizers(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+
+	// Deleted by an earlier operator version that never removed its finalizer; the mirror
+	// relationship is long gone
+	stale := createTestUVR("test-stale-finalizer", "default")
+	stale.Finalizers = []string{unifiedReplicationFinalizer}
+	stale.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-24 * time.Hour)}
+	autoCreate := true
+	stale.Spec.VolumeMapping.Destination.AutoCreate = &autoCreate
+
+	// The destination volume provisioned for it is cleaned up with it
+	destination := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+		Name:        stale.Spec.VolumeMapping.Destination.VolumeHandle,
+		Namespace:   stale.Spec.VolumeMapping.Destination.Namespace,
+		Annotations: map[string]string{adapters.DestinationVolumeCreatedByAnnotation: "default/test-stale-finalizer"},
+	}}
+
+	// Being deleted while its mirror relationship still exists
+	deleting := createTestUVR("test-deleting", "default")
+	deleting.Finalizers = []string{unifiedReplicationFinalizer}
+
+	// Not being deleted, so its finalizer is needed whatever the backend holds
+	live := createTestUVR("test-live", "default")
+	live.Finalizers = []string{unifiedReplicationFinalizer}
+
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(tridentCRDs(""), stale, deleting, live, destination)...).
+		WithStatusSubresource(stale, deleting, live).Build()
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewTridentAdapterFactory()))
+	reconciler := createTestReconciler(c, s)
+	reconciler.AdapterRegistry = registry
+	reconciler.ControllerEngine = pkg.NewControllerEngine(c, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+		registry, pkg.DefaultControllerEngineConfig())
+	recorder := reconciler.Recorder.(*record.FakeRecorder)
+
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(deleting)})
+	require.NoError(t, err)
+	require.NoError(t, c.Delete(ctx, deleting))
+	drainEvents(recorder)
+
+	removed, err := reconciler.removeStaleFinalizers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	// Without its finalizer the stale UVR is gone
+	err = c.Get(ctx, client.ObjectKeyFromObject(stale), &replicationv1alpha1.UnifiedVolumeReplication{})
+	assert.True(t, apierrors.IsNotFound(err), "the stale UVR is deleted once its finalizer is removed")
+	events := drainEvents(recorder)
+	require.Len(t, events, 1)
+	assert.Contains(t, events[0], "StaleFinalizerRemoved")
+	err = c.Get(ctx, client.ObjectKeyFromObject(destination), &corev1.PersistentVolumeClaim{})
+	assert.True(t, apierrors.IsNotFound(err), "the auto-created destination volume is deleted as in handleDeletion")
+
+	for _, uvr := range []*replicationv1alpha1.UnifiedVolumeReplication{deleting, live} {
+		current := &replicationv1alpha1.UnifiedVolumeReplication{}
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(uvr), current))
+		assert.True(t, controllerutil.ContainsFinalizer(current, unifiedReplicationFinalizer), uvr.Name)
+	}
+}
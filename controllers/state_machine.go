@@ -162,6 +162,12 @@ func (sm *StateMachine) defineTransitions() {
 			Description: "Idempotent - continue syncing",
 			RequiresOp:  "update",
 		},
+		{
+			From:        replicationv1alpha1.ReplicationStateSyncing,
+			To:          replicationv1alpha1.ReplicationStatePromoting,
+			Description: "Promote during a resync (failover)",
+			RequiresOp:  "promote",
+		},
 
 		// Failed state transitions
 		{
@@ -176,6 +182,12 @@ func (sm *StateMachine) defineTransitions() {
 			Description: "Recover to replica",
 			RequiresOp:  "update",
 		},
+		{
+			From:        replicationv1alpha1.ReplicationStateFailed,
+			To:          replicationv1alpha1.ReplicationStatePromoting,
+			Description: "Promote after losing the source (disaster recovery)",
+			RequiresOp:  "promote",
+		},
 	}
 
 	sm.transitionRules = transitions
@@ -219,6 +231,24 @@ func (sm *StateMachine) ValidateTransition(from, to replicationv1alpha1.Replicat
 	return fmt.Errorf("invalid state transition from %s to %s", from, to)
 }
 
+// ValidateRequestedTransition validates a change of spec.replicationState from the state the
+// backend reports. A role change is carried out through its transitional state, so replica
+// to source is valid because replica to promoting to source is.
+func (sm *StateMachine) ValidateRequestedTransition(from, to replicationv1alpha1.ReplicationState) error {
+	if sm.IsValidTransition(from, to) {
+		return nil
+	}
+	for _, via := range []replicationv1alpha1.ReplicationState{
+		replicationv1alpha1.ReplicationStatePromoting,
+		replicationv1alpha1.ReplicationStateDemoting,
+	} {
+		if sm.IsValidTransition(from, via) && sm.IsValidTransition(via, to) {
+			return nil
+		}
+	}
+	return sm.ValidateTransition(from, to)
+}
+
 // RecordTransition records a state transition in history
 func (sm *StateMachine) RecordTransition(from, to replicationv1alpha1.ReplicationState, reason, requestID string) {
 	sm.historyMutex.Lock()
@@ -0,0 +1,71 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// recordDiff summarizes in status.diff where the backend-reported state and mode differ
+// from the spec, and clears it once they match. The diff is left as is when the backend
+// did not report status, since convergence cannot be judged.
+func (r *UnifiedVolumeReplicationReconciler) recordDiff(uvr *replicationv1alpha1.UnifiedVolumeReplication, status *adapters.ReplicationStatus) {
+	if status == nil {
+		return
+	}
+
+	var diffs []string
+	desiredState := string(uvr.Spec.ReplicationState)
+	if status.State != "" && status.State != desiredState {
+		diffs = append(diffs, fmt.Sprintf("desired state=%s, observed=%s; %s",
+			desiredState, status.State, pendingTransition(uvr.Spec.ReplicationState, status.State)))
+	}
+	desiredMode := string(uvr.Spec.ReplicationMode)
+	if desiredMode != "" && status.Mode != "" && status.Mode != desiredMode {
+		diffs = append(diffs, fmt.Sprintf("desired mode=%s, observed=%s; mode change pending", desiredMode, status.Mode))
+	}
+
+	uvr.Status.Diff = strings.Join(diffs, "; ")
+}
+
+// pendingTransition describes what the backend still has to do to reach the desired state
+func pendingTransition(desired replicationv1alpha1.ReplicationState, observed string) string {
+	switch replicationv1alpha1.ReplicationState(observed) {
+	case replicationv1alpha1.ReplicationStatePromoting:
+		return "promotion in progress"
+	case replicationv1alpha1.ReplicationStateDemoting:
+		return "demotion in progress"
+	case replicationv1alpha1.ReplicationStateSyncing:
+		return "resync in progress"
+	case replicationv1alpha1.ReplicationStateFailed:
+		return "backend reports replication failed"
+	}
+
+	switch desired {
+	case replicationv1alpha1.ReplicationStateSource, replicationv1alpha1.ReplicationStatePromoting:
+		return "promotion pending"
+	case replicationv1alpha1.ReplicationStateReplica, replicationv1alpha1.ReplicationStateDemoting:
+		return "demotion pending"
+	case replicationv1alpha1.ReplicationStateSyncing:
+		return "resync pending"
+	}
+	return "transition pending"
+}
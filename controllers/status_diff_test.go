@@ -0,0 +1,56 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestReconciler_RecordDiff(t *testing.T) {
+	s := createTestScheme(t)
+	reconciler := createTestReconciler(fake.NewClientBuilder().WithScheme(s).Build(), s)
+
+	uvr := createTestUVR("test-diff", "default")
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+
+	// Promotion requested, backend still reports the replica role
+	reconciler.recordDiff(uvr, &adapters.ReplicationStatus{State: "replica", Mode: "asynchronous"})
+	assert.Equal(t, "desired state=source, observed=replica; promotion pending", uvr.Status.Diff)
+
+	reconciler.recordDiff(uvr, &adapters.ReplicationStatus{State: "promoting", Mode: "synchronous"})
+	assert.Equal(t, "desired state=source, observed=promoting; promotion in progress; "+
+		"desired mode=asynchronous, observed=synchronous; mode change pending", uvr.Status.Diff)
+
+	// Without backend status the last diff is kept
+	reconciler.recordDiff(uvr, nil)
+	assert.Contains(t, uvr.Status.Diff, "promotion in progress")
+
+	// Converged
+	reconciler.recordDiff(uvr, &adapters.ReplicationStatus{State: "source", Mode: "asynchronous"})
+	assert.Empty(t, uvr.Status.Diff)
+
+	// Demotion requested
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateReplica
+	reconciler.recordDiff(uvr, &adapters.ReplicationStatus{State: "source", Mode: "asynchronous"})
+	assert.Equal(t, "desired state=replica, observed=source; demotion pending", uvr.Status.Diff)
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+// translationReloadedReason is the event reason of a UVR requeued by a translation reload
+const translationReloadedReason = "TranslationReloaded"
+
+// translationReload requeues the UVRs whose state a reload of the translation engine
+// reinterprets. Their status was derived under the previous state maps, so instead of
+// leaving it to read differently from then on, each is reconciled again to re-derive it
+// from the backend under the new maps.
+type translationReload struct {
+	reader   client.Reader
+	recorder record.EventRecorder
+	log      logr.Logger
+
+	// channels feed the requeued UVRs to the controllers, one per controller
+	channels []chan event.GenericEvent
+
+	mu sync.Mutex
+	// change holds the reloads not handled yet, coalesced: the maps before the first and
+	// after the last
+	change *translation.StateMapChange
+	// reloaded signals that change is set
+	reloaded chan struct{}
+}
+
+var _ manager.Runnable = &translationReload{}
+
+// newTranslationReload creates the requeuing of the reloads of engine, listing UVRs from reader
+func newTranslationReload(engine *translation.Engine, reader client.Reader, recorder record.EventRecorder, log logr.Logger) *translationReload {
+	t := &translationReload{
+		reader:   reader,
+		recorder: recorder,
+		log:      log,
+		reloaded: make(chan struct{}, 1),
+	}
+	engine.OnReload(t.reload)
+	return t
+}
+
+// reload records a change of the state maps, handled by Start so the reload does not wait
+// for the UVRs to be listed
+func (t *translationReload) reload(change translation.StateMapChange) {
+	t.mu.Lock()
+	if t.change != nil {
+		change.Previous = t.change.Previous
+	}
+	t.change = &change
+	t.mu.Unlock()
+
+	select {
+	case t.reloaded <- struct{}{}:
+	default:
+	}
+}
+
+// Start requeues the affected UVRs after each reload until ctx is done
+func (t *translationReload) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.reloaded:
+		}
+		t.mu.Lock()
+		change := t.change
+		t.change = nil
+		t.mu.Unlock()
+		if change == nil {
+			continue
+		}
+		if _, err := t.requeue(ctx, *change); err != nil && ctx.Err() == nil {
+			t.log.Error(err, "Failed to requeue the replications affected by a translation reload")
+		}
+	}
+}
+
+// requeue queues a reconcile of every UVR whose state change reinterprets: the backend
+// state its realized state was read from now reads as another state, or its desired state
+// is now written as another backend state. It returns their keys.
+func (t *translationReload) requeue(ctx context.Context, change translation.StateMapChange) ([]string, error) {
+	uvrs := &replicationv1alpha1.UnifiedVolumeReplicationList{}
+	if err := t.reader.List(ctx, uvrs); err != nil {
+		return nil, fmt.Errorf("failed to list replications: %w", err)
+	}
+	sort.Slice(uvrs.Items, func(i, j int) bool {
+		return client.ObjectKeyFromObject(&uvrs.Items[i]).String() < client.ObjectKeyFromObject(&uvrs.Items[j]).String()
+	})
+
+	var affected []string
+	for i := range uvrs.Items {
+		uvr := &uvrs.Items[i]
+		backend := translation.Backend(backendPartitionFor(uvr))
+		if config := uvr.Status.EffectiveConfig; config != nil && config.Backend != "" {
+			backend = translation.Backend(config.Backend)
+		}
+
+		var message string
+		realized := ""
+		if uvr.Status.LastReconcile != nil {
+			realized = uvr.Status.LastReconcile.RealizedState
+		}
+		reinterpreted, recognized := change.Reinterpret(backend, realized)
+		desired := string(uvr.Spec.ReplicationState)
+		switch {
+		case realized != "" && !recognized:
+			message = fmt.Sprintf("the backend state read as %s is no longer recognized", realized)
+		case realized != "" && reinterpreted != realized:
+			message = fmt.Sprintf("the backend state read as %s now reads as %s", realized, reinterpreted)
+		case change.Retranslated(backend, desired):
+			message = fmt.Sprintf("desired state %s now maps to another %s state", desired, backend)
+		default:
+			continue
+		}
+
+		key := client.ObjectKeyFromObject(uvr).String()
+		t.log.Info("Translation reload changes the state interpretation, re-deriving status",
+			"unifiedvolumereplication", key, "backend", backend, "reason", message)
+		t.recorder.Eventf(uvr, corev1.EventTypeWarning, translationReloadedReason,
+			"Translation reloaded: %s; re-deriving status from the backend", message)
+		for _, ch := range t.channels {
+			select {
+			case ch <- event.GenericEvent{Object: uvr}:
+			case <-ctx.Done():
+				return affected, ctx.Err()
+			}
+		}
+		affected = append(affected, key)
+	}
+	return affected, nil
+}
+
+// watch adds the requeued UVRs as a source of the controller built by b. The predicates
+// keep the controller to its own UVRs, e.g. its backend partition.
+func (t *translationReload) watch(b *builder.Builder, h handler.EventHandler, predicates ...predicate.Predicate) *builder.Builder {
+	ch := make(chan event.GenericEvent)
+	t.channels = append(t.channels, ch)
+	return b.WatchesRawSource(source.Channel(ch, h, source.WithPredicates[client.Object, reconcile.Request](predicates...)))
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg"
+	"github.com/unified-replication/operator/pkg/adapters"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+func TestTranslationReload_RequeuesReinterpretedReplications(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+
+	uvr := createTestUVR("reinterpreted", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	// Unaffected: read as a state whose backend state keeps its meaning, or on another backend
+	steady := createTestUVR("steady", "default")
+	steady.Status.LastReconcile = &replicationv1alpha1.LastReconcile{RealizedState: "replica"}
+	ceph := createTestUVR("ceph", "default")
+	ceph.Spec.Extensions = nil
+	ceph.Spec.SourceEndpoint.StorageClass = "ceph-rbd"
+	ceph.Status.LastReconcile = &replicationv1alpha1.LastReconcile{RealizedState: "syncing"}
+	// Affected before its first reconcile: its desired state is written differently
+	desired := createTestUVR("desired-syncing", "default")
+	desired.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSyncing
+
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(tridentCRDs(""), uvr, steady, ceph, desired)...).
+		WithStatusSubresource(uvr).Build()
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewTridentAdapterFactory()))
+	reconciler := createTestReconciler(c, s)
+	reconciler.AdapterRegistry = registry
+	reconciler.ControllerEngine = pkg.NewControllerEngine(c, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+		registry, pkg.DefaultControllerEngineConfig())
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+
+	realizedState := func() string {
+		t.Helper()
+		_, err := reconciler.Reconcile(ctx, req)
+		require.NoError(t, err)
+		require.NoError(t, c.Get(ctx, req.NamespacedName, uvr))
+		require.NotNil(t, uvr.Status.LastReconcile)
+		return uvr.Status.LastReconcile.RealizedState
+	}
+
+	// The backend reports the state read as syncing under the built-in maps
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	tmr := &unstructured.Unstructured{}
+	tmr.SetGroupVersionKind(adapters.TridentMirrorRelationshipGVK)
+	require.NoError(t, c.Get(ctx, req.NamespacedName, tmr))
+	require.NoError(t, unstructured.SetNestedField(tmr.Object, "established-syncing", "status", "state"))
+	require.NoError(t, c.Update(ctx, tmr))
+	require.Equal(t, "syncing", realizedState())
+
+	recorder := record.NewFakeRecorder(10)
+	reload := newTranslationReload(reconciler.TranslationEngine, c, recorder, logr.Discard())
+	requeued := make(chan event.GenericEvent, 10)
+	reload.channels = append(reload.channels, requeued)
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() { _ = reload.Start(runCtx) }()
+
+	require.NoError(t, reconciler.TranslationEngine.ReloadStateMaps(map[translation.Backend]map[string]string{
+		translation.BackendTrident: {"syncing": "established-failed", "failed": "established-syncing"},
+	}))
+
+	var keys []string
+	for len(keys) < 2 {
+		select {
+		case e := <-requeued:
+			keys = append(keys, client.ObjectKeyFromObject(e.Object).String())
+		case <-time.After(5 * time.Second):
+			t.Fatalf("only %v requeued", keys)
+		}
+	}
+	assert.Equal(t, []string{"default/desired-syncing", "default/reinterpreted"}, keys)
+	assert.Never(t, func() bool { return len(requeued) > 0 }, 200*time.Millisecond, 10*time.Millisecond,
+		"unaffected replications are not requeued")
+	assert.ElementsMatch(t, []string{
+		"Warning TranslationReloaded Translation reloaded: desired state syncing now maps to another trident state; re-deriving status from the backend",
+		"Warning TranslationReloaded Translation reloaded: the backend state read as syncing now reads as failed; re-deriving status from the backend",
+	}, drainEvents(recorder))
+
+	// The requeued reconcile re-derives the status under the new maps, consistently with them
+	expected, err := reconciler.TranslationEngine.TranslateStateFromBackend(translation.BackendTrident, "established-syncing")
+	require.NoError(t, err)
+	assert.Equal(t, "failed", expected)
+	assert.Equal(t, expected, realizedState())
+}
@@ -19,19 +19,21 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
-	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
 	"github.com/unified-replication/operator/pkg"
@@ -44,10 +46,14 @@ const (
 	// Finalizer name for cleanup
 	unifiedReplicationFinalizer = "replication.storage.io/finalizer"
 
-	// Requeue delays
+	// Requeue delays; healthy replications use successRequeueDelay, which falls back to
+	// requeueDelaySuccess when the UVR has no RPO
 	requeueDelaySuccess = 30 * time.Second
 	requeueDelayError   = 10 * time.Second
 	requeueDelayFast    = 5 * time.Second
+
+	// maxStateHistoryEntries caps status.stateHistory; keep in sync with the CRD MaxItems
+	maxStateHistoryEntries = 20
 )
 
 // UnifiedVolumeReplicationReconciler reconciles a UnifiedVolumeReplication object
@@ -64,23 +70,153 @@ type UnifiedVolumeReplicationReconciler struct {
 	ControllerEngine  *pkg.ControllerEngine
 
 	// Advanced features (Phase 4.3)
-	StateMachine   *StateMachine
-	RetryManager   *RetryManager
-	CircuitBreaker *CircuitBreaker
+	StateMachine    *StateMachine
+	RetryManager    *RetryManager
+	CircuitBreakers *BackendCircuitBreakers
+
+	// BackendSelectionWebhook, when set, overrides built-in backend selection
+	BackendSelectionWebhook *BackendSelectionWebhook
+
+	// ReconcileOutcomeWebhook, when set, is notified of the outcome of every reconcile
+	ReconcileOutcomeWebhook *ReconcileOutcomeWebhook
+
+	// AdapterManager, when set, caches adapters across reconciles and swaps them when
+	// their configuration changes
+	AdapterManager *adapters.AdapterManager
 
 	// Configuration
 	MaxConcurrentReconciles int
 	ReconcileTimeout        time.Duration
+	PlannedOperationTimeout time.Duration
+
+	// CancelPollInterval is how often a running operation checks for the cancel-operation
+	// annotation
+	CancelPollInterval time.Duration
+
+	// IsolateBackends runs a separate controller and workqueue per backend, optionally
+	// with per-backend worker counts in MaxConcurrentReconcilesPerBackend
+	IsolateBackends                   bool
+	MaxConcurrentReconcilesPerBackend map[string]int
+
+	// Capturer, when set, records each reconcile for offline replay
+	Capturer *ReconcileCapturer
+
+	// ReconcileOrder selects which queued UVRs are reconciled first, FIFO when empty
+	ReconcileOrder ReconcileOrder
+
+	// SpecCoalesceWindow delays reconciles triggered by spec edits so that edits made within
+	// the window are handled by a single reconcile; disabled when zero
+	SpecCoalesceWindow time.Duration
+
+	// MissingSourcePVCPolicy selects whether a UVR whose source PVC is missing or unbound
+	// waits for it or fails; the check is skipped when empty
+	MissingSourcePVCPolicy MissingSourcePVCPolicy
+
+	// RequireFailoverApproval holds every failover of a replica to source until the UVR
+	// carries the failover-approved annotation
+	RequireFailoverApproval bool
+
+	// DeletionSyncPolicy selects whether deleting a UVR waits for an in-progress sync to
+	// finish before tearing down the backend replication; immediate when empty
+	DeletionSyncPolicy DeletionSyncPolicy
+
+	// DeletionSyncTimeout bounds how long a deletion waits for a sync under the wait policy,
+	// counted from the deletion timestamp; five minutes when zero
+	DeletionSyncTimeout time.Duration
+
+	// WriteDrainTimeout bounds how long a demotion waits for writes to drain, counted from
+	// the start of the drain; five minutes when zero
+	WriteDrainTimeout time.Duration
+
+	// UnknownHealthPolicy selects how a backend state the adapter cannot map to a health is
+	// treated; Degrade when empty
+	UnknownHealthPolicy UnknownHealthPolicy
+
+	// SimulateDegradationNamespaces lists the namespaces whose UVRs may request a simulated
+	// degradation with the simulate-degradation annotation; none may when empty
+	SimulateDegradationNamespaces []string
+
+	// RPOComplianceThresholds maps backend names to the thresholds that turn the RPO
+	// compliance of their replications into health; compliance is only recorded for other
+	// backends
+	RPOComplianceThresholds map[string]RPOComplianceThresholds
+
+	// DestinationFreeSpaceThreshold is the free space, in bytes, below which a replication is
+	// paused until the destination storage class has room again; zero disables the check
+	DestinationFreeSpaceThreshold int64
+
+	// WatchNamespaces restricts the controller to UVRs in these namespaces; all namespaces
+	// are reconciled when empty
+	WatchNamespaces []string
+
+	// APICallLogThreshold logs reconciles making more API requests than this, with their
+	// requests by verb; disabled when zero. Requests are only counted through a client
+	// wrapped with pkg.CountingClient.
+	APICallLogThreshold int
+
+	// FailoverLimiter, when set, caps how many UVRs are promoted at once; promotions beyond
+	// the cap are queued
+	FailoverLimiter *FailoverLimiter
+
+	// FleetSweep, when set, periodically reconciles every UVR to catch drift no watch reports
+	FleetSweep *FleetSweep
+
+	// StepGracePeriods maps backend names to how long a role change waits after the previous
+	// one was observed, such as the demotion before a failover's promotion; none when unset
+	StepGracePeriods map[string]time.Duration
+
+	// translationReload requeues the UVRs whose state a reload of TranslationEngine
+	// reinterprets; set up by SetupWithManager
+	translationReload *translationReload
+
+	// deletionLocks serializes the deletion cleanup of each UVR between its reconcile and the
+	// startup stale finalizer pass, keyed by namespaced name
+	deletionLocks sync.Map
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *UnifiedVolumeReplicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&replicationv1alpha1.UnifiedVolumeReplication{}).
-		WithOptions(controller.Options{
-			MaxConcurrentReconciles: r.getMaxConcurrentReconciles(),
-		}).
-		WithEventFilter(predicate.GenerationChangedPredicate{}).
+	r.registerAdapterSwapHandler()
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &replicationv1alpha1.UnifiedVolumeReplication{},
+		sourceVolumeIndexField, indexSourceVolumes); err != nil {
+		return err
+	}
+	if err := indexReferencedObjects(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		return err
+	}
+
+	// Unstick deletions left behind by earlier operator versions
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		if _, err := r.removeStaleFinalizers(ctx); err != nil {
+			r.Log.Error(err, "Failed to check for stale finalizers")
+		}
+		return nil
+	})); err != nil {
+		return err
+	}
+
+	if r.FleetSweep != nil {
+		if err := mgr.Add(r.FleetSweep); err != nil {
+			return err
+		}
+	}
+
+	if r.TranslationEngine != nil {
+		r.translationReload = newTranslationReload(r.TranslationEngine, mgr.GetClient(), r.Recorder, r.Log.WithName("translation-reload"))
+		if err := mgr.Add(r.translationReload); err != nil {
+			return err
+		}
+	}
+
+	if r.IsolateBackends {
+		return r.setupBackendPartitionedControllers(mgr)
+	}
+
+	r.Log.Info("Reconcile ordering", "order", r.getReconcileOrder())
+	b := r.watchReplications(ctrl.NewControllerManagedBy(mgr), replicationControllerName)
+	return r.watchReferencedObjects(b, nil).
+		WithOptions(r.controllerOptions(r.getMaxConcurrentReconciles())).
 		Complete(r)
 }
 
@@ -88,29 +224,61 @@ func (r *UnifiedVolumeReplicationReconciler) SetupWithManager(mgr ctrl.Manager)
 // +kubebuilder:rbac:groups=replication.storage.io,resources=unifiedvolumereplications/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=replication.storage.io,resources=unifiedvolumereplications/finalizers,verbs=update
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=persistentvolumes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets;configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=csistoragecapacities,verbs=get;list;watch
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ceph.rook.io,resources=cephblockpools,verbs=get;list;watch
 
 // Reconcile implements the reconciliation loop for UnifiedVolumeReplication
-func (r *UnifiedVolumeReplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *UnifiedVolumeReplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := r.Log.WithValues(
 		"unifiedvolumereplication", req.NamespacedName,
 	)
+	if !r.watchesNamespace(req.Namespace) {
+		log.V(1).Info("Ignoring UnifiedVolumeReplication outside the watched namespaces")
+		return ctrl.Result{}, nil
+	}
 	log.Info("Starting reconciliation")
 
+	// Count the API requests of this reconcile, including those made by the adapters
+	ctx, apiCalls := pkg.WithAPICallCounting(ctx)
+	uvr := &replicationv1alpha1.UnifiedVolumeReplication{}
+	defer func() { r.recordAPICalls(uvr, apiCalls, log) }()
+	defer func() { r.FleetSweep.observe(req.String(), uvr, err) }()
+
 	// Create context with timeout
 	reconcileCtx, cancel := context.WithTimeout(ctx, r.getReconcileTimeout())
 	defer cancel()
 
 	// Fetch the UnifiedVolumeReplication instance
-	uvr := &replicationv1alpha1.UnifiedVolumeReplication{}
 	if err := r.Get(reconcileCtx, req.NamespacedName, uvr); err != nil {
 		if apierrors.IsNotFound(err) {
 			log.Info("UnifiedVolumeReplication resource not found, likely deleted")
+			r.FailoverLimiter.Release(req.String())
 			return ctrl.Result{}, nil
 		}
 		log.Error(err, "Failed to get UnifiedVolumeReplication")
 		return ctrl.Result{}, err
 	}
 
+	// Free the failover slot of a promotion that completed or was withdrawn
+	r.releaseFailover(uvr)
+
+	// Protect the API server: routine reconciles wait while the fleet's write budget is spent
+	if admitted, delay := r.admitReconcile(uvr); !admitted {
+		log.V(1).Info("Write budget exhausted, deferring reconcile", "requeueAfter", delay)
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+
+	if r.Capturer != nil {
+		capture := r.Capturer.start(reconcileCtx, r.DiscoveryEngine, uvr, log)
+		reconcileCtx = withReconcileCapture(reconcileCtx, capture)
+		defer func() { r.Capturer.finish(capture, uvr, result, err, log) }()
+	}
+
 	// Initialize status if needed
 	if uvr.Status.Conditions == nil {
 		uvr.Status.Conditions = []metav1.Condition{}
@@ -133,8 +301,22 @@ func (r *UnifiedVolumeReplicationReconciler) Reconcile(ctx context.Context, req
 		return ctrl.Result{RequeueAfter: time.Second}, nil
 	}
 
-	// Reconcile the replication
-	return r.reconcileReplication(reconcileCtx, uvr, log)
+	// Reconcile the replication, tracing its decisions when asked to explain them
+	reconcileCtx, trace := startExplain(reconcileCtx, uvr)
+	result, err = r.reconcileReplication(reconcileCtx, uvr, log)
+
+	// Keep the backend and phase labels current whatever the outcome
+	if uvr.ObjectMeta.DeletionTimestamp.IsZero() {
+		r.finishExplain(reconcileCtx, uvr, trace, result, err, log)
+		if err := r.reconcileManagedLabels(reconcileCtx, uvr, log); err != nil {
+			log.Error(err, "Failed to update managed labels")
+		}
+	}
+
+	if r.ReconcileOutcomeWebhook != nil {
+		r.ReconcileOutcomeWebhook.Notify(uvr, result, err)
+	}
+	return result, err
 }
 
 // reconcileReplication handles the main reconciliation logic
@@ -144,16 +326,48 @@ func (r *UnifiedVolumeReplicationReconciler) reconcileReplication(ctx context.Co
 		"mode", uvr.Spec.ReplicationMode,
 		"generation", uvr.Generation)
 
+	// A tombstoned object must only ever go down the deletion path
+	if !uvr.ObjectMeta.DeletionTimestamp.IsZero() {
+		log.Info("Resource is being deleted, skipping ensure")
+		return r.handleDeletion(ctx, uvr, log)
+	}
+
+	// Track planned operations so expected degradation doesn't raise alerts
+	if err := r.trackPlannedOperation(ctx, uvr, log); err != nil {
+		log.Error(err, "Failed to update planned operation")
+		return ctrl.Result{RequeueAfter: requeueDelayError}, err
+	}
+
+	// Acknowledge a cancel-operation request, then hold the role of a cancelled operation
+	// until the spec changes
+	if _, ok := uvr.Annotations[CancelOperationAnnotation]; ok {
+		explainf(ctx, "Operation cancelled with the %s annotation", CancelOperationAnnotation)
+		return r.cancelOperation(ctx, uvr, nil, log)
+	}
+	if r.applyCancelledOperation(ctx, uvr, log) {
+		r.recordReady(uvr, nil)
+		if err := r.Status().Update(ctx, uvr); err != nil {
+			log.Error(err, "Failed to update status")
+			return ctrl.Result{}, err
+		}
+		// Nothing is issued until the spec changes, which triggers a reconcile
+		return ctrl.Result{}, nil
+	}
+
+	// Keep a replica that lost a split-brain resolution demoted until the spec changes
+	r.applySplitBrainResolution(ctx, uvr, log)
+
 	// Validate state transitions using state machine
 	// Get current state from status (if available)
 	currentState := r.getCurrentState(uvr)
 	desiredState := uvr.Spec.ReplicationState
 
 	if currentState != "" && currentState != desiredState {
-		if err := r.StateMachine.ValidateTransition(currentState, desiredState); err != nil {
+		if err := r.StateMachine.ValidateRequestedTransition(currentState, desiredState); err != nil {
 			log.Error(err, "Invalid state transition",
 				"from", currentState,
 				"to", desiredState)
+			explainf(ctx, "State transition from %s to %s rejected: %v", currentState, desiredState, err)
 			r.updateCondition(uvr, metav1.Condition{
 				Type:               "Ready",
 				Status:             metav1.ConditionFalse,
@@ -161,7 +375,10 @@ func (r *UnifiedVolumeReplicationReconciler) reconcileReplication(ctx context.Co
 				Message:            fmt.Sprintf("Invalid transition from %s to %s", currentState, desiredState),
 				ObservedGeneration: uvr.Generation,
 			})
+			r.setDegraded(uvr, adapters.DegradedReasonInvalidTransition,
+				fmt.Sprintf("Invalid transition from %s to %s", currentState, desiredState))
 
+			r.recordFailedReconcile(uvr)
 			if err := r.Status().Update(ctx, uvr); err != nil {
 				log.Error(err, "Failed to update status")
 			}
@@ -172,11 +389,17 @@ func (r *UnifiedVolumeReplicationReconciler) reconcileReplication(ctx context.Co
 		// Record valid transition
 		r.StateMachine.RecordTransition(currentState, desiredState, "user_requested", "")
 		log.Info("Valid state transition", "from", currentState, "to", desiredState)
+		explainf(ctx, "State transition from %s to %s allowed", currentState, desiredState)
 	}
 
 	// Validate the spec
-	if err := uvr.ValidateSpec(); err != nil {
+	err := uvr.ValidateSpec()
+	if err == nil {
+		err = r.validateSourceNamespaces(uvr)
+	}
+	if err != nil {
 		log.Error(err, "Spec validation failed")
+		explainf(ctx, "Spec validation failed: %v", err)
 		r.updateCondition(uvr, metav1.Condition{
 			Type:               "Ready",
 			Status:             metav1.ConditionFalse,
@@ -184,8 +407,9 @@ func (r *UnifiedVolumeReplicationReconciler) reconcileReplication(ctx context.Co
 			Message:            fmt.Sprintf("Validation failed: %v", err),
 			ObservedGeneration: uvr.Generation,
 		})
-		r.Recorder.Event(uvr, corev1.EventTypeWarning, "ValidationFailed", err.Error())
+		r.recordEventf(uvr, corev1.EventTypeWarning, "ValidationFailed", "%s", err.Error())
 
+		r.recordFailedReconcile(uvr)
 		if err := r.Status().Update(ctx, uvr); err != nil {
 			log.Error(err, "Failed to update status")
 			return ctrl.Result{}, err
@@ -194,10 +418,64 @@ func (r *UnifiedVolumeReplicationReconciler) reconcileReplication(ctx context.Co
 		return ctrl.Result{RequeueAfter: requeueDelayError}, nil
 	}
 
+	explainf(ctx, "Spec validation passed")
+
+	// Refuse to replicate volumes that other UVRs replicate back into this one's sources
+	if loop, err := r.findReplicationLoop(ctx, uvr); err != nil {
+		log.Error(err, "Failed to check for replication loops")
+	} else if loop != nil {
+		message := replicationLoopMessage(loop)
+		log.Info("Replication loop detected", "replications", loop)
+		explainf(ctx, "Replication loop detected: %s", message)
+		r.updateCondition(uvr, metav1.Condition{
+			Type:               "Ready",
+			Status:             metav1.ConditionFalse,
+			Reason:             "ReplicationLoop",
+			Message:            message,
+			ObservedGeneration: uvr.Generation,
+		})
+		r.recordEventf(uvr, corev1.EventTypeWarning, "ReplicationLoop", "%s", message)
+
+		r.recordFailedReconcile(uvr)
+		if err := r.Status().Update(ctx, uvr); err != nil {
+			log.Error(err, "Failed to update status")
+			return ctrl.Result{}, err
+		}
+
+		// The other UVRs may change without triggering this one, so check again later
+		return ctrl.Result{RequeueAfter: requeueDelaySuccess}, nil
+	}
+
+	// Don't create backend resources for a source PVC that does not exist or is not Bound
+	if missing, result := r.checkSourcePVC(ctx, uvr, log); missing {
+		explainf(ctx, "Source PVC %s is not ready, nothing is created on the backend", uvr.Spec.VolumeMapping.Source.PvcName)
+		r.recordFailedReconcile(uvr)
+		if err := r.Status().Update(ctx, uvr); err != nil {
+			log.Error(err, "Failed to update status")
+			return ctrl.Result{}, err
+		}
+		return result, nil
+	}
+
+	// Warn when the source volume lives outside the declared source region
+	r.checkSourceTopology(ctx, uvr, log)
+
+	// Wait for the credentials Secret and profile ConfigMap the UVR references
+	if missing, result := r.checkReferencedObjects(ctx, uvr, log); missing {
+		explainf(ctx, "A referenced Secret or ConfigMap is missing, nothing is created on the backend")
+		r.recordFailedReconcile(uvr)
+		if err := r.Status().Update(ctx, uvr); err != nil {
+			log.Error(err, "Failed to update status")
+			return ctrl.Result{}, err
+		}
+		return result, nil
+	}
+
 	// Get the appropriate adapter
-	adapter, err := r.getAdapter(ctx, uvr, log)
+	adapter, release, err := r.acquireAdapter(ctx, uvr, log)
 	if err != nil {
 		log.Error(err, "Failed to get adapter")
+		explainf(ctx, "No backend adapter: %v", err)
 		r.updateCondition(uvr, metav1.Condition{
 			Type:               "Ready",
 			Status:             metav1.ConditionFalse,
@@ -205,14 +483,17 @@ func (r *UnifiedVolumeReplicationReconciler) reconcileReplication(ctx context.Co
 			Message:            fmt.Sprintf("Failed to get adapter: %v", err),
 			ObservedGeneration: uvr.Generation,
 		})
-		r.Recorder.Event(uvr, corev1.EventTypeWarning, "AdapterError", err.Error())
+		r.recordEventf(uvr, corev1.EventTypeWarning, "AdapterError", "%s", err.Error())
 
+		r.recordFailedReconcile(uvr)
 		if err := r.Status().Update(ctx, uvr); err != nil {
 			log.Error(err, "Failed to update status")
 		}
 
 		return ctrl.Result{RequeueAfter: requeueDelayError}, err
 	}
+	defer release()
+	explainf(ctx, "Using the %s adapter", adapter.GetBackendType())
 
 	// Initialize adapter if needed
 	if err := adapter.Initialize(ctx); err != nil {
@@ -225,6 +506,7 @@ func (r *UnifiedVolumeReplicationReconciler) reconcileReplication(ctx context.Co
 			ObservedGeneration: uvr.Generation,
 		})
 
+		r.recordFailedReconcile(uvr)
 		if err := r.Status().Update(ctx, uvr); err != nil {
 			log.Error(err, "Failed to update status")
 		}
@@ -232,51 +514,309 @@ func (r *UnifiedVolumeReplicationReconciler) reconcileReplication(ctx context.Co
 		return ctrl.Result{RequeueAfter: requeueDelayError}, err
 	}
 
+	// Fill in the backend default when the spec leaves the mode unset. The spec is only
+	// changed in memory; the choice is reported through status.effectiveConfig.
+	modeDefaulted := false
+	if uvr.Spec.ReplicationMode == "" {
+		mode, err := resolveReplicationMode(adapter)
+		if err != nil {
+			log.Error(err, "Failed to choose a default replication mode")
+			r.updateCondition(uvr, metav1.Condition{
+				Type:               "Ready",
+				Status:             metav1.ConditionFalse,
+				Reason:             "UnsupportedReplicationMode",
+				Message:            err.Error(),
+				ObservedGeneration: uvr.Generation,
+			})
+
+			r.recordFailedReconcile(uvr)
+			if err := r.Status().Update(ctx, uvr); err != nil {
+				log.Error(err, "Failed to update status")
+			}
+
+			return ctrl.Result{RequeueAfter: requeueDelayError}, nil
+		}
+		log.Info("Using backend default replication mode", "backend", adapter.GetBackendType(), "mode", mode)
+		explainf(ctx, "Replication mode not set, using the backend default %s", mode)
+		uvr.Spec.ReplicationMode = mode
+		modeDefaulted = true
+	}
+
+	// Flag RTO, RPO and mode combinations that cannot be met, without blocking the reconcile
+	r.recordRecoveryObjectives(uvr)
+	r.checkRecoveryObjectives(uvr)
+
+	// Stay halted while both endpoints claim the primary role, until a survivor is selected
+	if r.isSplitBrain(uvr) {
+		resolved, err := r.resolveSplitBrain(ctx, adapter, uvr, log)
+		if err != nil || !resolved {
+			explainf(ctx, "Both endpoints claim the primary role, reconciliation is halted")
+			message := fmt.Sprintf("Dual-primary detected, set the %s annotation to resolve", SurvivingPrimaryAnnotation)
+			if err != nil {
+				log.Error(err, "Failed to resolve split-brain")
+				message = fmt.Sprintf("Failed to resolve split-brain: %v", err)
+				r.recordEventf(uvr, corev1.EventTypeWarning, "SplitBrainResolutionFailed", "%s", err.Error())
+			}
+			r.updateCondition(uvr, metav1.Condition{
+				Type:               "Ready",
+				Status:             metav1.ConditionFalse,
+				Reason:             "SplitBrain",
+				Message:            message,
+				ObservedGeneration: uvr.Generation,
+			})
+
+			r.recordFailedReconcile(uvr)
+			if err := r.Status().Update(ctx, uvr); err != nil {
+				log.Error(err, "Failed to update status")
+			}
+
+			return ctrl.Result{RequeueAfter: requeueDelayError}, nil
+		}
+	}
+
+	// Hold off routine operations while the backend signals maintenance
+	if r.deferForBackendMaintenance(ctx, adapter.GetBackendType(), uvr, log) {
+		explainf(ctx, "The %s backend signals maintenance, routine operations are deferred", adapter.GetBackendType())
+		if err := r.Status().Update(ctx, uvr); err != nil {
+			log.Error(err, "Failed to update status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: requeueDelayMaintenance}, nil
+	}
+
+	// Keep a deactivated replication dormant, and resume syncing once it is reactivated
+	if deactivated, result := r.reconcileDeactivation(ctx, adapter, uvr, log); deactivated {
+		explainf(ctx, "The replication is deactivated, nothing is changed on the backend")
+		if err := r.Status().Update(ctx, uvr); err != nil {
+			log.Error(err, "Failed to update status")
+			return ctrl.Result{}, err
+		}
+		return result, nil
+	}
+
+	// Hold a failover until it is approved, when approval is required
+	if held, result := r.awaitFailoverApproval(ctx, uvr, log); held {
+		explainf(ctx, "Failover awaiting approval with the %s annotation", FailoverApprovedAnnotation)
+		if err := r.Status().Update(ctx, uvr); err != nil {
+			log.Error(err, "Failed to update status")
+			return ctrl.Result{}, err
+		}
+		return result, nil
+	}
+
+	// Let the previous role change settle before starting the next
+	if held, result := r.awaitRoleChangeGrace(uvr, time.Now(), log); held {
+		explainf(ctx, "Role change held for the step grace period")
+		if err := r.Status().Update(ctx, uvr); err != nil {
+			log.Error(err, "Failed to update status")
+			return ctrl.Result{}, err
+		}
+		return result, nil
+	}
+
+	// Only promote a replication group once every member has caught up
+	if isPromotionPending(uvr) && len(uvr.Spec.GroupMembers) > 0 {
+		if ok, message := r.PromotionAllowed(ctx, uvr); !ok {
+			log.Info("Promotion blocked", "reason", message)
+			explainf(ctx, "Promotion blocked: %s", message)
+			r.updateCondition(uvr, metav1.Condition{
+				Type:               "Ready",
+				Status:             metav1.ConditionFalse,
+				Reason:             "PromotionBlocked",
+				Message:            fmt.Sprintf("Promotion blocked: %s", message),
+				ObservedGeneration: uvr.Generation,
+			})
+			r.recordEventf(uvr, corev1.EventTypeWarning, "PromotionBlocked", "Promotion blocked: %s", message)
+
+			r.recordFailedReconcile(uvr)
+			if err := r.Status().Update(ctx, uvr); err != nil {
+				log.Error(err, "Failed to update status")
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: requeueDelayError}, nil
+		}
+	}
+
+	// Take over an existing backend resource named by the adopt annotation
+	if !r.adoptBackendResource(ctx, adapter, uvr, log) {
+		explainf(ctx, "Adopting the backend resource failed")
+		r.recordFailedReconcile(uvr)
+		if err := r.Status().Update(ctx, uvr); err != nil {
+			log.Error(err, "Failed to update status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: requeueDelayError}, nil
+	}
+
+	// Hand the sync schedule to the backend when the UVR delegates it, before a
+	// recreation needs the native schedule
+	if !r.delegateSchedule(ctx, adapter, uvr, log) {
+		explainf(ctx, "Delegating the sync schedule to the backend failed")
+		r.recordFailedReconcile(uvr)
+		if err := r.Status().Update(ctx, uvr); err != nil {
+			log.Error(err, "Failed to update status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: requeueDelayError}, nil
+	}
+
+	// Recreate the backend resource for spec changes it cannot take in place
+	if handled, result, err := r.reconcileRecreation(ctx, adapter, uvr, log); handled {
+		explainf(ctx, "Spec change requires recreating the backend resource")
+		return result, err
+	}
+
+	// Refuse to create a replication the backend has no room for
+	if r.exceedsBackendLimits(ctx, adapter, uvr, log) {
+		explainf(ctx, "The backend is at its replication limit")
+		r.recordFailedReconcile(uvr)
+		if err := r.Status().Update(ctx, uvr); err != nil {
+			log.Error(err, "Failed to update status")
+			return ctrl.Result{}, err
+		}
+		// Wait for other replications to be deleted or the limit to be raised
+		return ctrl.Result{RequeueAfter: requeueDelaySuccess}, nil
+	}
+
+	// Detect backend policy changes made outside the operator
+	r.checkPolicyDrift(ctx, adapter, uvr, log)
+
+	// Queue the promotion while the concurrent failover limit is reached. The slot is only
+	// taken once every other gate has passed, so a promotion they hold does not occupy it.
+	if queued, result := r.queueFailover(uvr, log); queued {
+		explainf(ctx, "Promotion queued at position %d by the concurrent failover limit", uvr.Status.FailoverQueuePosition)
+		if err := r.Status().Update(ctx, uvr); err != nil {
+			log.Error(err, "Failed to update status")
+			return ctrl.Result{}, err
+		}
+		return result, nil
+	}
+
 	// Ensure the replication is in the desired state (idempotent reconciliation)
 	log.Info("Ensuring replication is in desired state")
-	if err := r.ControllerEngine.EnsureReplication(ctx, uvr, log); err != nil {
+	if result, waiting := r.drainWritesBeforeDemotion(ctx, adapter, uvr, log); waiting {
+		explainf(ctx, "Waiting for writes to drain before demoting the source")
+		return result, nil
+	}
+	explainf(ctx, "Issued EnsureReplication to the %s backend for state %s", adapter.GetBackendType(), uvr.Spec.ReplicationState)
+	ensureCtx, cancelRequested := r.withOperationCancel(ctx, uvr, log)
+	err = r.callBackend(uvr, adapter.GetBackendType(), "EnsureReplication", func() error {
+		return r.ControllerEngine.EnsureReplication(ensureCtx, uvr, log)
+	})
+	if err != nil {
+		r.releaseFailedFailover(uvr, log)
+	}
+	if cancelRequested() {
+		return r.cancelOperation(ctx, uvr, err, log)
+	}
+	if result, unavailable := r.handleBackendUnavailable(ctx, uvr, err, log); unavailable {
+		explainf(ctx, "The backend circuit breaker is open, EnsureReplication was not issued")
+		return result, nil
+	}
+	if result, throttled := r.handleAPIThrottling(ctx, uvr, err, log); throttled {
+		return result, nil
+	}
+	if result, conflicting := r.handleResourceConflict(ctx, uvr, err, log); conflicting {
+		explainf(ctx, "The backend resource belongs to another UVR")
+		return result, nil
+	}
+	if err != nil {
 		log.Error(err, "Failed to ensure replication")
+		explainf(ctx, "EnsureReplication failed: %v", err)
+		message := withRemediation(fmt.Sprintf("Failed to ensure replication: %v", err), err)
 		r.updateCondition(uvr, metav1.Condition{
 			Type:               "Ready",
 			Status:             metav1.ConditionFalse,
 			Reason:             "ReconciliationFailed",
-			Message:            fmt.Sprintf("Failed to ensure replication: %v", err),
+			Message:            message,
 			ObservedGeneration: uvr.Generation,
 		})
-		r.Recorder.Eventf(uvr, corev1.EventTypeWarning, "ReconciliationFailed", "Failed to ensure replication: %v", err)
+		r.recordEventf(uvr, corev1.EventTypeWarning, "ReconciliationFailed", "%s", message)
+		if adapters.IsInsufficientCapacityError(err) {
+			r.updateCondition(uvr, metav1.Condition{
+				Type:               "CapacityReserved",
+				Status:             metav1.ConditionFalse,
+				Reason:             "InsufficientCapacity",
+				Message:            err.Error(),
+				ObservedGeneration: uvr.Generation,
+			})
+		}
 
+		r.recordFailedReconcile(uvr)
 		if err := r.Status().Update(ctx, uvr); err != nil {
 			log.Error(err, "Failed to update status")
 		}
 
 		return ctrl.Result{RequeueAfter: requeueDelayError}, err
 	}
+	r.recordRelationshipEstablished(uvr, time.Now())
+
+	// Add or remove consistency group members changed since the last reconcile
+	r.reconcileGroupMembership(ctx, adapter, uvr, log)
+
+	// Stop syncing into a destination that is about to run out of space
+	r.guardDestinationCapacity(ctx, adapter, uvr, log)
+
+	// Cap the bandwidth of syncs during the configured windows
+	qosDelay := r.reconcileBandwidthLimit(ctx, adapter, uvr, time.Now(), log)
 
 	// Update status from integrated engine
-	status, err := r.ControllerEngine.GetReplicationStatus(ctx, uvr, log)
+	var status *adapters.ReplicationStatus
+	persistedTransfer := dataTransferred(uvr)
+	err = r.callBackend(uvr, adapter.GetBackendType(), "GetReplicationStatus", func() error {
+		var statusErr error
+		status, statusErr = r.ControllerEngine.GetReplicationStatus(ctx, uvr, log)
+		return statusErr
+	})
+	if result, throttled := r.handleAPIThrottling(ctx, uvr, err, log); throttled {
+		return result, nil
+	}
+	if result, unavailable := r.handleBackendUnavailable(ctx, uvr, err, log); unavailable {
+		explainf(ctx, "The backend circuit breaker is open, the replication status was not read")
+		return result, nil
+	}
 	if err != nil {
 		log.Error(err, "Failed to get status from integrated engine")
+		if reason := adapters.DegradedReasonForError(err); reason == adapters.DegradedReasonBackendUnreachable {
+			r.setDegraded(uvr, reason, fmt.Sprintf("Failed to read replication status: %v", err))
+		}
 	} else if status != nil {
+		explainf(ctx, "Backend reports state %s, mode %s, health %s", status.State, status.Mode, status.Health)
+		status = r.simulateDegradation(ctx, uvr, status, log)
 		r.updateStatusFromEngineStatus(uvr, status, log)
+		r.recordConsistencyLevel(ctx, adapter, uvr, log)
+		if err := r.completePlannedOperation(ctx, uvr, status, log); err != nil {
+			log.Error(err, "Failed to complete planned operation")
+		}
 	}
 
-	// Set ready condition
-	r.updateCondition(uvr, metav1.Condition{
-		Type:               "Ready",
-		Status:             metav1.ConditionTrue,
-		Reason:             "ReconciliationSucceeded",
-		Message:            "Replication is operating normally",
-		ObservedGeneration: uvr.Generation,
-	})
+	r.recordCapacityReservation(ctx, adapter, uvr, log)
+	r.recordEffectiveConfig(adapter, uvr, modeDefaulted, log)
+	r.verifyReplicaReadable(ctx, adapter, uvr, log)
+
+	r.clearAPIThrottled(uvr)
+	r.clearResourceConflict(uvr)
+	r.clearBackendUnavailable(uvr)
+	r.recordReady(uvr, status)
+
+	r.recordLastReconcile(uvr, status)
+	r.recordDiff(uvr, status)
+	r.recordLastSuccessfulOperations(uvr)
 
 	// Update status
 	if err := r.Status().Update(ctx, uvr); err != nil {
 		log.Error(err, "Failed to update status")
 		return ctrl.Result{}, err
 	}
+	publishDataTransfer(uvr, persistedTransfer)
 
 	log.Info("Reconciliation completed successfully")
-	return ctrl.Result{RequeueAfter: requeueDelaySuccess}, nil
+	delay := successRequeueDelay(uvr)
+	if qosDelay > 0 {
+		// Reconcile again when the bandwidth cap changes
+		delay = min(delay, qosDelay)
+	}
+	return ctrl.Result{RequeueAfter: r.simulatedDegradationRequeue(uvr, delay)}, nil
 }
 
 // handleDeletion handles resource deletion with finalizer cleanup
@@ -287,9 +827,10 @@ func (r *UnifiedVolumeReplicationReconciler) handleDeletion(ctx context.Context,
 		log.Info("Finalizer already removed, skipping cleanup")
 		return ctrl.Result{}, nil
 	}
+	defer r.lockDeletion(uvr)()
 
 	// Get adapter for cleanup
-	adapter, err := r.getAdapter(ctx, uvr, log)
+	adapter, release, err := r.acquireAdapter(ctx, uvr, log)
 	if err != nil {
 		log.Error(err, "Failed to get adapter for cleanup, removing finalizer anyway")
 		// Remove finalizer even if we can't get adapter
@@ -299,28 +840,72 @@ func (r *UnifiedVolumeReplicationReconciler) handleDeletion(ctx context.Context,
 		}
 		return ctrl.Result{}, nil
 	}
+	defer release()
+
+	// Let an in-progress sync finish so the destination is left at a complete recovery point
+	if waiting, result := r.awaitSyncBeforeDeletion(ctx, adapter, uvr, log); waiting {
+		return result, nil
+	}
 
 	// Delete replication from backend
 	log.Info("Deleting replication from backend")
 	if err := adapter.DeleteReplication(ctx, uvr); err != nil {
 		log.Error(err, "Failed to delete replication from backend")
 		r.Recorder.Eventf(uvr, corev1.EventTypeWarning, "DeletionFailed", "Failed to delete from backend: %v", err)
-		// Retry deletion
+		r.updateCondition(uvr, metav1.Condition{
+			Type:               "Ready",
+			Status:             metav1.ConditionFalse,
+			Reason:             "DeletionFailed",
+			Message:            fmt.Sprintf("Failed to delete from backend: %v", err),
+			ObservedGeneration: uvr.Generation,
+		})
+		if err := r.Status().Update(ctx, uvr); err != nil {
+			log.Error(err, "Failed to update status")
+		}
+		// Retry deletion; the finalizer stays in place until the backend is cleaned up
 		return ctrl.Result{RequeueAfter: requeueDelayError}, err
 	}
 
 	r.Recorder.Event(uvr, corev1.EventTypeNormal, "Deleted", "Replication deleted successfully")
+	if err := r.finishDeletion(ctx, uvr, log); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	log.Info("Deletion completed")
+	return ctrl.Result{}, nil
+}
+
+// finishDeletion drops what the operator keeps for a UVR whose backend replication has been
+// deleted, then removes its finalizer
+func (r *UnifiedVolumeReplicationReconciler) finishDeletion(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) error {
+	forgetDataTransfer(uvr)
+	forgetSimulatedDegradation(uvr)
+	forgetWritePause(uvr)
+	forgetRelationship(uvr)
+	forgetHealthBreaches(uvr)
+
+	// The cached adapter is cleaned up once its user releases it
+	if r.AdapterManager != nil {
+		if err := r.AdapterManager.RemoveAdapter(ctx, uvr); err != nil {
+			log.Error(err, "Failed to remove cached adapter")
+		}
+	}
 
 	// Remove finalizer
 	log.Info("Removing finalizer")
 	controllerutil.RemoveFinalizer(uvr, unifiedReplicationFinalizer)
 	if err := r.Update(ctx, uvr); err != nil {
 		log.Error(err, "Failed to remove finalizer")
-		return ctrl.Result{}, err
+		return err
 	}
+	return nil
+}
 
-	log.Info("Deletion completed")
-	return ctrl.Result{}, nil
+// lockDeletion serializes the deletion cleanup of a UVR and returns the unlock function
+func (r *UnifiedVolumeReplicationReconciler) lockDeletion(uvr *replicationv1alpha1.UnifiedVolumeReplication) func() {
+	lock, _ := r.deletionLocks.LoadOrStore(client.ObjectKeyFromObject(uvr), &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	return lock.(*sync.Mutex).Unlock
 }
 
 // getAdapter retrieves the appropriate adapter for the UVR
@@ -332,9 +917,16 @@ func (r *UnifiedVolumeReplicationReconciler) getAdapter(ctx context.Context, uvr
 	backends, err := r.DiscoveryEngine.DiscoverBackends(ctx)
 	if err != nil {
 		log.Error(err, "Discovery failed, falling back to extension-based selection")
-	} else if backends != nil && len(backends.AvailableBackends) > 0 {
+		explainf(ctx, "Backend discovery failed: %v", err)
+	} else if adapters.FeedDiscoveryResult(r.AdapterRegistry, backends); backends != nil &&
+		(len(backends.AvailableBackends) > 0 || adapters.GenericCSISupports(r.AdapterRegistry, uvr)) {
+		explainf(ctx, "Discovered backends: %v", backends.AvailableBackends)
 		// Select backend using engine logic
 		backend, err := r.selectBackendViaEngine(ctx, uvr, backends.AvailableBackends, log)
+		if err != nil && r.BackendSelectionWebhook != nil && !r.BackendSelectionWebhook.FailOpen {
+			// Failing closed: never fall back to extension-based selection
+			return nil, err
+		}
 		if err == nil {
 			// Get adapter via registry
 			factory, err := r.AdapterRegistry.GetFactory(backend)
@@ -352,6 +944,7 @@ func (r *UnifiedVolumeReplicationReconciler) getAdapter(ctx context.Context, uvr
 
 	// Fallback: extension-based selection
 	log.V(1).Info("Using extension-based adapter selection")
+	explainf(ctx, "No backend selected from discovery, choosing from the spec extensions")
 
 	if uvr.Spec.Extensions != nil {
 		if uvr.Spec.Extensions.Ceph != nil {
@@ -383,11 +976,27 @@ func (r *UnifiedVolumeReplicationReconciler) selectBackendViaEngine(
 	availableBackends []translation.Backend,
 	log logr.Logger,
 ) (translation.Backend, error) {
+	// Defer to the external selection webhook when configured
+	if r.BackendSelectionWebhook != nil {
+		selection, err := r.BackendSelectionWebhook.SelectBackend(ctx, uvr, availableBackends)
+		if err == nil {
+			log.Info("Backend chosen by selection webhook", "backend", selection.Backend, "reason", selection.Reason)
+			explainf(ctx, "Backend %s chosen by the selection webhook: %s", selection.Backend, selection.Reason)
+			return selection.Backend, nil
+		}
+		if !r.BackendSelectionWebhook.FailOpen {
+			return "", fmt.Errorf("backend selection webhook failed: %w", err)
+		}
+		log.Error(err, "Backend selection webhook failed, falling back to built-in selection")
+		explainf(ctx, "Backend selection webhook failed, falling back to built-in selection: %v", err)
+	}
+
 	// Use extension hints first
 	if uvr.Spec.Extensions != nil {
 		if uvr.Spec.Extensions.Ceph != nil {
 			for _, backend := range availableBackends {
 				if backend == translation.BackendCeph {
+					explainf(ctx, "Backend %s chosen from the spec extensions", backend)
 					return backend, nil
 				}
 			}
@@ -395,6 +1004,7 @@ func (r *UnifiedVolumeReplicationReconciler) selectBackendViaEngine(
 		if uvr.Spec.Extensions.Trident != nil {
 			for _, backend := range availableBackends {
 				if backend == translation.BackendTrident {
+					explainf(ctx, "Backend %s chosen from the spec extensions", backend)
 					return backend, nil
 				}
 			}
@@ -402,6 +1012,7 @@ func (r *UnifiedVolumeReplicationReconciler) selectBackendViaEngine(
 		if uvr.Spec.Extensions.Powerstore != nil {
 			for _, backend := range availableBackends {
 				if backend == translation.BackendPowerStore {
+					explainf(ctx, "Backend %s chosen from the spec extensions", backend)
 					return backend, nil
 				}
 			}
@@ -411,24 +1022,31 @@ func (r *UnifiedVolumeReplicationReconciler) selectBackendViaEngine(
 	// Detect from storage class
 	storageClass := uvr.Spec.SourceEndpoint.StorageClass
 	for _, backend := range availableBackends {
+		matched := false
 		switch backend {
 		case translation.BackendCeph:
-			if contains(storageClass, "ceph") || contains(storageClass, "rbd") {
-				return backend, nil
-			}
+			matched = contains(storageClass, "ceph") || contains(storageClass, "rbd")
 		case translation.BackendTrident:
-			if contains(storageClass, "trident") || contains(storageClass, "netapp") {
-				return backend, nil
-			}
+			matched = contains(storageClass, "trident") || contains(storageClass, "netapp")
 		case translation.BackendPowerStore:
-			if contains(storageClass, "powerstore") || contains(storageClass, "dell") {
-				return backend, nil
-			}
+			matched = contains(storageClass, "powerstore") || contains(storageClass, "dell")
 		}
+		if matched {
+			explainf(ctx, "Backend %s chosen from source storage class %s", backend, storageClass)
+			return backend, nil
+		}
+	}
+
+	// Drive the standard VolumeReplication when the generic CSI adapter is configured and
+	// discovery found its CRD
+	if adapters.GenericCSISupports(r.AdapterRegistry, uvr) {
+		explainf(ctx, "Backend %s chosen, nothing in the spec pointed at a specific backend", translation.BackendGenericCSI)
+		return translation.BackendGenericCSI, nil
 	}
 
 	// Use first available
 	if len(availableBackends) > 0 {
+		explainf(ctx, "Backend %s chosen as the first discovered backend, nothing in the spec pointed elsewhere", availableBackends[0])
 		return availableBackends[0], nil
 	}
 
@@ -465,12 +1083,34 @@ func (r *UnifiedVolumeReplicationReconciler) updateStatusFromAdapter(ctx context
 		})
 	}
 
+	if status.PrimaryCluster != "" {
+		uvr.Status.PrimaryCluster = status.PrimaryCluster
+		uvr.Status.PrimarySite = status.PrimarySite
+	}
+	if status.Direction != "" {
+		uvr.Status.Direction = replicationv1alpha1.ReplicationDirection(status.Direction)
+	}
+	r.recordConsistencyLevel(ctx, adapter, uvr, log)
+
 	log.V(1).Info("Updated status from adapter",
 		"state", status.State,
-		"mode", status.Mode)
+		"mode", status.Mode,
+		"primaryCluster", status.PrimaryCluster,
+		"direction", status.Direction)
 	return nil
 }
 
+// recordConsistencyLevel records whether the latest recovery point is crash-consistent or
+// application-consistent, keeping the last known level when the backend cannot be read
+func (r *UnifiedVolumeReplicationReconciler) recordConsistencyLevel(ctx context.Context, adapter adapters.ReplicationAdapter, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) {
+	level, err := adapter.GetConsistencyState(ctx, uvr)
+	if err != nil {
+		log.Error(err, "Failed to read consistency state")
+		return
+	}
+	uvr.Status.ConsistencyLevel = string(level)
+}
+
 // updateStatusFromEngineStatus updates status from integrated engine (with translation)
 func (r *UnifiedVolumeReplicationReconciler) updateStatusFromEngineStatus(uvr *replicationv1alpha1.UnifiedVolumeReplication, status *adapters.ReplicationStatus, log logr.Logger) {
 	// Update observed generation
@@ -487,32 +1127,329 @@ func (r *UnifiedVolumeReplicationReconciler) updateStatusFromEngineStatus(uvr *r
 		})
 	}
 
+	r.recordCurrentState(uvr, status)
+	r.applyUnknownHealthPolicy(status)
+	r.checkRPO(uvr, status)
+	r.recordWritePause(uvr, status)
+	r.updateComputedSchedule(uvr, status, log)
+	r.recordHealthEvent(uvr, status)
+	r.recordDegraded(uvr, status)
+	r.recordInitialSync(uvr, status, log)
+	r.recordDataTransfer(uvr, status)
+	r.recordStateHistory(uvr, status, log)
+	r.recordSplitBrain(uvr, status, log)
+
+	if status.LastSyncTime != nil {
+		lastSync := metav1.NewTime(*status.LastSyncTime)
+		uvr.Status.LastSyncTime = &lastSync
+	}
+	r.recordScheduleStatus(uvr, time.Now())
+
+	// Record where the primary currently lives, when the backend can tell us
+	if status.PrimaryCluster != "" {
+		uvr.Status.PrimaryCluster = status.PrimaryCluster
+		uvr.Status.PrimarySite = status.PrimarySite
+	}
+	if status.Direction != "" {
+		uvr.Status.Direction = replicationv1alpha1.ReplicationDirection(status.Direction)
+	}
 	log.V(1).Info("Updated status from integrated engine",
 		"state", status.State,
-		"mode", status.Mode)
+		"mode", status.Mode,
+		"primaryCluster", status.PrimaryCluster,
+		"direction", status.Direction)
+}
+
+// recordCurrentState records the state the backend reports for validating the next change of
+// spec.replicationState. A state outside the replication states clears it, so a stale state
+// never rejects a change.
+func (r *UnifiedVolumeReplicationReconciler) recordCurrentState(uvr *replicationv1alpha1.UnifiedVolumeReplication, status *adapters.ReplicationStatus) {
+	switch state := replicationv1alpha1.ReplicationState(status.State); state {
+	case replicationv1alpha1.ReplicationStateSource, replicationv1alpha1.ReplicationStateReplica,
+		replicationv1alpha1.ReplicationStatePromoting, replicationv1alpha1.ReplicationStateDemoting,
+		replicationv1alpha1.ReplicationStateSyncing, replicationv1alpha1.ReplicationStateFailed:
+		uvr.Status.CurrentState = state
+	default:
+		uvr.Status.CurrentState = ""
+	}
+}
+
+// recordInitialSync latches the InitialSyncComplete condition the first time the backend
+// reports a full sync. Later incremental syncs report progress below 100% again, so the
+// condition is never cleared once set.
+func (r *UnifiedVolumeReplicationReconciler) recordInitialSync(uvr *replicationv1alpha1.UnifiedVolumeReplication, status *adapters.ReplicationStatus, log logr.Logger) {
+	if cond := r.getCondition(uvr, "InitialSyncComplete"); cond != nil && cond.Status == metav1.ConditionTrue {
+		return
+	}
+
+	if status.SyncProgress == nil || status.SyncProgress.PercentComplete < 100 {
+		r.updateCondition(uvr, metav1.Condition{
+			Type:               "InitialSyncComplete",
+			Status:             metav1.ConditionFalse,
+			Reason:             "InitialSyncInProgress",
+			Message:            "Waiting for the first full sync to complete",
+			ObservedGeneration: uvr.Generation,
+		})
+		return
+	}
+
+	log.Info("Initial sync completed")
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               "InitialSyncComplete",
+		Status:             metav1.ConditionTrue,
+		Reason:             "InitialSyncCompleted",
+		Message:            "First full sync completed, the replica is safe to rely on",
+		ObservedGeneration: uvr.Generation,
+	})
+	r.recordEstablishment(uvr, time.Now(), log)
+	r.recordEventf(uvr, corev1.EventTypeNormal, "InitialSyncCompleted", "First full sync completed")
+}
+
+// recordStateHistory appends an entry to status.stateHistory when the backend reports a
+// different state than the last one recorded, keeping at most maxStateHistoryEntries
+func (r *UnifiedVolumeReplicationReconciler) recordStateHistory(uvr *replicationv1alpha1.UnifiedVolumeReplication, status *adapters.ReplicationStatus, log logr.Logger) {
+	if status.State == "" {
+		return
+	}
+
+	var from string
+	if n := len(uvr.Status.StateHistory); n > 0 {
+		from = uvr.Status.StateHistory[n-1].To
+	}
+	if from == status.State {
+		return
+	}
+
+	reason := "BackendReported"
+	switch {
+	case r.isPlannedOperationActive(uvr):
+		reason = "PlannedOperation"
+	case status.State == string(uvr.Spec.ReplicationState):
+		reason = "SpecRequested"
+	}
+
+	uvr.Status.StateHistory = append(uvr.Status.StateHistory, replicationv1alpha1.StateHistoryEntry{
+		Timestamp: metav1.Now(),
+		From:      from,
+		To:        status.State,
+		Reason:    reason,
+	})
+	if n := len(uvr.Status.StateHistory); n > maxStateHistoryEntries {
+		uvr.Status.StateHistory = uvr.Status.StateHistory[n-maxStateHistoryEntries:]
+	}
+
+	if r.StateMachine != nil {
+		r.StateMachine.RecordTransition(replicationv1alpha1.ReplicationState(from),
+			replicationv1alpha1.ReplicationState(status.State), reason, "")
+	}
+	log.Info("Observed replication state changed", "from", from, "to", status.State, "reason", reason)
 }
 
-// updateCondition updates or adds a condition to the status
+// recordCapacityReservation persists the destination capacity reservation in status, so it
+// survives operator restarts, and surfaces it in conditions. The reservation is released once
+// the destination volume is bound or the initial sync has completed, since the destination
+// volume then holds the capacity itself.
+func (r *UnifiedVolumeReplicationReconciler) recordCapacityReservation(ctx context.Context, adapter adapters.ReplicationAdapter, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) {
+	reserver, ok := adapter.(adapters.CapacityReserver)
+	if !ok {
+		return
+	}
+
+	reserved, ok := reserver.ReservedCapacity(uvr)
+	if !ok {
+		uvr.Status.CapacityReservation = nil
+		return
+	}
+
+	storageClass := uvr.Spec.DestinationEndpoint.StorageClass
+	release := ""
+	if cond := r.getCondition(uvr, "InitialSyncComplete"); cond != nil && cond.Status == metav1.ConditionTrue {
+		release = "Initial sync complete, reservation released"
+	} else if bound, err := reserver.DestinationVolumeBound(ctx, uvr); err != nil {
+		log.Error(err, "Failed to check whether the destination volume is bound")
+	} else if bound {
+		release = "Destination volume is bound, reservation released"
+	}
+	if release != "" {
+		log.Info("Releasing destination capacity reservation", "storageClass", storageClass, "reason", release)
+		reserver.ReleaseCapacity(uvr)
+		uvr.Status.CapacityReservation = nil
+		r.updateCondition(uvr, metav1.Condition{
+			Type:               "CapacityReserved",
+			Status:             metav1.ConditionFalse,
+			Reason:             "Released",
+			Message:            release,
+			ObservedGeneration: uvr.Generation,
+		})
+		return
+	}
+
+	if existing := uvr.Status.CapacityReservation; existing == nil || existing.StorageClass != storageClass || existing.Bytes != reserved {
+		uvr.Status.CapacityReservation = &replicationv1alpha1.CapacityReservation{
+			StorageClass: storageClass,
+			Bytes:        reserved,
+			Time:         metav1.Now(),
+		}
+	}
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               "CapacityReserved",
+		Status:             metav1.ConditionTrue,
+		Reason:             "Reserved",
+		Message:            fmt.Sprintf("Reserved %s on storage class %s", resource.NewQuantity(reserved, resource.BinarySI).String(), storageClass),
+		ObservedGeneration: uvr.Generation,
+	})
+}
+
+// recordLastReconcile summarizes a successful reconcile in status.lastReconcile, comparing
+// the backend-reported state against the spec to decide whether it is fully realized
+func (r *UnifiedVolumeReplicationReconciler) recordLastReconcile(uvr *replicationv1alpha1.UnifiedVolumeReplication, status *adapters.ReplicationStatus) {
+	report := &replicationv1alpha1.LastReconcile{
+		ObservedGeneration: uvr.Generation,
+		Time:               metav1.Now(),
+	}
+
+	switch {
+	case status == nil:
+		report.Result = replicationv1alpha1.ReconcileResultProgressing
+		report.Message = "Backend status not yet available"
+	case status.Health == adapters.ReplicationHealthDegraded || status.Health == adapters.ReplicationHealthUnhealthy:
+		report.Result = replicationv1alpha1.ReconcileResultDegraded
+		report.Message = fmt.Sprintf("Backend reports %s health", status.Health)
+	case status.State != string(uvr.Spec.ReplicationState):
+		report.Result = replicationv1alpha1.ReconcileResultProgressing
+		report.Message = fmt.Sprintf("Waiting for state %s, backend reports %s", uvr.Spec.ReplicationState, status.State)
+	default:
+		report.Result = replicationv1alpha1.ReconcileResultSynced
+		report.Message = "Desired state realized"
+	}
+
+	if status != nil {
+		report.RealizedState = status.State
+		report.RealizedMode = status.Mode
+	}
+
+	uvr.Status.LastReconcile = report
+}
+
+// recordReady sets the Ready condition after a successful ensure. Ready means the replica is
+// usable for DR: the backend reports a healthy relationship that has synced at least once.
+// Until then the condition is False with reason Progressing.
+func (r *UnifiedVolumeReplicationReconciler) recordReady(uvr *replicationv1alpha1.UnifiedVolumeReplication, status *adapters.ReplicationStatus) {
+	condition := metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: uvr.Generation,
+	}
+
+	initialSync := r.getCondition(uvr, "InitialSyncComplete")
+	switch {
+	case uvr.Status.CancelledOperation != nil:
+		condition.Reason = "OperationCancelled"
+		condition.Message = cancelledOperationMessage(uvr.Status.CancelledOperation)
+	case status == nil:
+		condition.Reason = "StatusUnavailable"
+		condition.Message = "Backend status not yet available"
+	case status.Health == adapters.ReplicationHealthDegraded || status.Health == adapters.ReplicationHealthUnhealthy:
+		condition.Reason = "ReplicationUnhealthy"
+		condition.Message = fmt.Sprintf("Backend reports %s health: %s", status.Health, status.Message)
+	case status.LastSyncTime == nil && (initialSync == nil || initialSync.Status != metav1.ConditionTrue):
+		condition.Reason = "Progressing"
+		condition.Message = "Replication is being established, waiting for the first sync to complete"
+	default:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "ReconciliationSucceeded"
+		condition.Message = "Replication is operating normally"
+	}
+
+	r.updateCondition(uvr, condition)
+}
+
+// recordFailedReconcile marks the last reconcile as degraded, reusing the Ready
+// condition message which describes the failure
+func (r *UnifiedVolumeReplicationReconciler) recordFailedReconcile(uvr *replicationv1alpha1.UnifiedVolumeReplication) {
+	report := &replicationv1alpha1.LastReconcile{
+		ObservedGeneration: uvr.Generation,
+		Result:             replicationv1alpha1.ReconcileResultDegraded,
+		Time:               metav1.Now(),
+	}
+	if ready := r.getCondition(uvr, "Ready"); ready != nil {
+		report.Message = ready.Message
+	}
+	uvr.Status.LastReconcile = report
+}
+
+// checkPolicyDrift sets the PolicyDrift condition when the backend's replication
+// class/policy no longer matches the UVR. Adapters without drift detection are skipped.
+func (r *UnifiedVolumeReplicationReconciler) checkPolicyDrift(ctx context.Context, adapter adapters.ReplicationAdapter, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) {
+	detector, ok := adapter.(adapters.PolicyDriftDetector)
+	if !ok {
+		return
+	}
+
+	drift, err := detector.DetectPolicyDrift(ctx, uvr)
+	if err != nil {
+		// Backend resources may not exist yet on first reconcile
+		log.V(1).Info("Unable to check policy drift", "error", err.Error())
+		return
+	}
+
+	if len(drift) == 0 {
+		r.updateCondition(uvr, metav1.Condition{
+			Type:               "PolicyDrift",
+			Status:             metav1.ConditionFalse,
+			Reason:             "PolicyInSync",
+			Message:            "Backend replication policy matches the desired settings",
+			ObservedGeneration: uvr.Generation,
+		})
+		return
+	}
+
+	details := make([]string, 0, len(drift))
+	for _, d := range drift {
+		details = append(details, d.String())
+	}
+	message := fmt.Sprintf("Backend replication policy drifted: %s", strings.Join(details, "; "))
+
+	if existing := r.getCondition(uvr, "PolicyDrift"); existing == nil || existing.Status != metav1.ConditionTrue {
+		r.recordEventf(uvr, corev1.EventTypeWarning, "PolicyDrift", "%s", message)
+	}
+	log.Info("Detected replication policy drift", "drift", details)
+
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               "PolicyDrift",
+		Status:             metav1.ConditionTrue,
+		Reason:             "PolicyMismatch",
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+}
+
+// updateCondition updates or adds a condition to the status. LastTransitionTime is only
+// changed when the condition's status flips.
 func (r *UnifiedVolumeReplicationReconciler) updateCondition(uvr *replicationv1alpha1.UnifiedVolumeReplication, condition metav1.Condition) {
-	condition.LastTransitionTime = metav1.NewTime(time.Now())
+	// A condition describes the generation it was evaluated against, which is the one being
+	// reconciled unless the caller says otherwise
+	if condition.ObservedGeneration == 0 {
+		condition.ObservedGeneration = uvr.Generation
+	}
+	now := metav1.NewTime(time.Now())
 
 	// Find existing condition
 	for i, existingCondition := range uvr.Status.Conditions {
 		if existingCondition.Type == condition.Type {
-			// Update if status changed
-			if existingCondition.Status != condition.Status {
-				uvr.Status.Conditions[i] = condition
-			} else {
-				// Just update message and reason
-				uvr.Status.Conditions[i].Message = condition.Message
-				uvr.Status.Conditions[i].Reason = condition.Reason
-				uvr.Status.Conditions[i].ObservedGeneration = condition.ObservedGeneration
+			// The transition time only moves when the status flips, not on reason or
+			// message updates
+			condition.LastTransitionTime = existingCondition.LastTransitionTime
+			if existingCondition.Status != condition.Status || condition.LastTransitionTime.IsZero() {
+				condition.LastTransitionTime = now
 			}
+			uvr.Status.Conditions[i] = condition
 			return
 		}
 	}
 
 	// Add new condition
+	condition.LastTransitionTime = now
 	uvr.Status.Conditions = append(uvr.Status.Conditions, condition)
 }
 
@@ -534,6 +1471,22 @@ func (r *UnifiedVolumeReplicationReconciler) getMaxConcurrentReconciles() int {
 	return 1 // Default to 1
 }
 
+// getReconcileOrder returns the configured reconcile order
+func (r *UnifiedVolumeReplicationReconciler) getReconcileOrder() ReconcileOrder {
+	if r.ReconcileOrder == "" {
+		return ReconcileOrderFIFO
+	}
+	return r.ReconcileOrder
+}
+
+// getSpecCoalesceWindow returns the spec edit coalescing window, zero when disabled
+func (r *UnifiedVolumeReplicationReconciler) getSpecCoalesceWindow() time.Duration {
+	if r.SpecCoalesceWindow < 0 {
+		return 0
+	}
+	return r.SpecCoalesceWindow
+}
+
 // getReconcileTimeout returns the configured reconcile timeout
 func (r *UnifiedVolumeReplicationReconciler) getReconcileTimeout() time.Duration {
 	if r.ReconcileTimeout > 0 {
@@ -542,20 +1495,10 @@ func (r *UnifiedVolumeReplicationReconciler) getReconcileTimeout() time.Duration
 	return 5 * time.Minute // Default timeout
 }
 
-// getCurrentState extracts the current state from the UVR status
+// getCurrentState returns the state the backend last reported, recorded in status by
+// updateStatusFromEngineStatus. Empty before the first report.
 func (r *UnifiedVolumeReplicationReconciler) getCurrentState(uvr *replicationv1alpha1.UnifiedVolumeReplication) replicationv1alpha1.ReplicationState {
-	// Look for Synced condition which contains current state info
-	for _, cond := range uvr.Status.Conditions {
-		if cond.Type == "Synced" && cond.Status == metav1.ConditionTrue {
-			// State was previously set
-			// For now, return empty if we can't reliably determine it
-			// In production, this would parse the condition message or have explicit status field
-			return ""
-		}
-	}
-
-	// If this is first reconcile, no current state
-	return ""
+	return uvr.Status.CurrentState
 }
 
 // Helper functions
@@ -81,7 +81,7 @@ var _ = Describe("UnifiedVolumeReplicationController", func() {
 				AdapterRegistry:   adapterRegistry,
 				StateMachine:      NewStateMachine(),
 				RetryManager:      NewRetryManager(nil),
-				CircuitBreaker:    NewCircuitBreaker(5, 2, 1*time.Minute),
+				CircuitBreakers:   NewBackendCircuitBreakers(5, 2, 1*time.Minute),
 			}
 
 			// Create test resource
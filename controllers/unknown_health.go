@@ -0,0 +1,76 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// UnknownHealthPolicy selects how a replication is treated while its backend reports a state
+// the adapter cannot map to a health
+type UnknownHealthPolicy string
+
+const (
+	// UnknownHealthDegrade treats an unknown state as degraded and alerts on it
+	UnknownHealthDegrade UnknownHealthPolicy = "Degrade"
+	// UnknownHealthIgnore treats an unknown state as healthy, noting it in the Degraded condition
+	UnknownHealthIgnore UnknownHealthPolicy = "Ignore"
+	// UnknownHealthError treats an unknown state as unhealthy
+	UnknownHealthError UnknownHealthPolicy = "Error"
+)
+
+// ParseUnknownHealthPolicy validates an unknown health policy name, defaulting to Degrade
+// when empty
+func ParseUnknownHealthPolicy(value string) (UnknownHealthPolicy, error) {
+	switch policy := UnknownHealthPolicy(value); policy {
+	case "":
+		return UnknownHealthDegrade, nil
+	case UnknownHealthDegrade, UnknownHealthIgnore, UnknownHealthError:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown health policy %q, must be one of: %s, %s, %s",
+			value, UnknownHealthDegrade, UnknownHealthIgnore, UnknownHealthError)
+	}
+}
+
+// applyUnknownHealthPolicy resolves the health of a backend state the adapter cannot map,
+// reported with reason UnknownState, into a definite one under the configured policy, Degrade
+// when unset. The reason is kept in every case, which also lets recordDegraded note an ignored
+// unknown state. An unknown health with another reason, such as a backend resource not yet
+// created or without conditions while the replication is established, is left alone.
+func (r *UnifiedVolumeReplicationReconciler) applyUnknownHealthPolicy(status *adapters.ReplicationStatus) {
+	if status.Health != adapters.ReplicationHealthUnknown || status.DegradedReason != adapters.DegradedReasonUnknownState {
+		return
+	}
+
+	message := "backend state is unknown"
+	if status.Message != "" {
+		message = fmt.Sprintf("%s: %s", message, status.Message)
+	}
+	switch r.UnknownHealthPolicy {
+	case UnknownHealthIgnore:
+		status.Health = adapters.ReplicationHealthHealthy
+		message = fmt.Sprintf("%s, treated as healthy", message)
+	case UnknownHealthError:
+		status.Health = adapters.ReplicationHealthUnhealthy
+	default:
+		status.Health = adapters.ReplicationHealthDegraded
+	}
+	status.Message = message
+}
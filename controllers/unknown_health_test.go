@@ -0,0 +1,155 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestParseUnknownHealthPolicy(t *testing.T) {
+	policy, err := ParseUnknownHealthPolicy("")
+	require.NoError(t, err)
+	assert.Equal(t, UnknownHealthDegrade, policy)
+
+	for _, valid := range []UnknownHealthPolicy{UnknownHealthDegrade, UnknownHealthIgnore, UnknownHealthError} {
+		policy, err := ParseUnknownHealthPolicy(string(valid))
+		require.NoError(t, err)
+		assert.Equal(t, valid, policy)
+	}
+
+	_, err = ParseUnknownHealthPolicy("degrade")
+	assert.Error(t, err)
+}
+
+func TestReconciler_UnknownHealthPolicy(t *testing.T) {
+	s := createTestScheme(t)
+
+	tests := []struct {
+		name           string
+		policy         UnknownHealthPolicy
+		degraded       metav1.ConditionStatus
+		degradedReason string
+		degradedMsg    string
+		ready          metav1.ConditionStatus
+		readyReason    string
+		event          string
+	}{
+		{
+			name:           "DefaultDegrades",
+			degraded:       metav1.ConditionTrue,
+			degradedReason: "UnknownState",
+			degradedMsg:    "Replication health is Degraded: backend state is unknown: mirror state Paused",
+			ready:          metav1.ConditionFalse,
+			readyReason:    "ReplicationUnhealthy",
+			event:          "Warning ReplicationDegraded",
+		},
+		{
+			name:           "Degrade",
+			policy:         UnknownHealthDegrade,
+			degraded:       metav1.ConditionTrue,
+			degradedReason: "UnknownState",
+			degradedMsg:    "Replication health is Degraded: backend state is unknown: mirror state Paused",
+			ready:          metav1.ConditionFalse,
+			readyReason:    "ReplicationUnhealthy",
+			event:          "Warning ReplicationDegraded",
+		},
+		{
+			name:           "Ignore",
+			policy:         UnknownHealthIgnore,
+			degraded:       metav1.ConditionFalse,
+			degradedReason: "UnknownStateIgnored",
+			degradedMsg:    "backend state is unknown: mirror state Paused, treated as healthy",
+			ready:          metav1.ConditionTrue,
+			readyReason:    "ReconciliationSucceeded",
+		},
+		{
+			name:           "Error",
+			policy:         UnknownHealthError,
+			degraded:       metav1.ConditionTrue,
+			degradedReason: "UnknownState",
+			degradedMsg:    "Replication health is Unhealthy: backend state is unknown: mirror state Paused",
+			ready:          metav1.ConditionFalse,
+			readyReason:    "ReplicationUnhealthy",
+			event:          "Warning ReplicationUnhealthy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uvr := createTestUVR("test-unknown-health", "default")
+			reconciler := createTestReconciler(fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).Build(), s)
+			reconciler.UnknownHealthPolicy = tt.policy
+			recorder := reconciler.Recorder.(*record.FakeRecorder)
+
+			lastSync := time.Now().Add(-time.Minute)
+			status := &adapters.ReplicationStatus{State: "unknown", Health: adapters.ReplicationHealthUnknown,
+				DegradedReason: adapters.DegradedReasonUnknownState, Message: "mirror state Paused", LastSyncTime: &lastSync}
+			reconciler.updateStatusFromEngineStatus(uvr, status, reconciler.Log)
+			reconciler.recordReady(uvr, status)
+
+			degraded := reconciler.getCondition(uvr, degradedCondition)
+			require.NotNil(t, degraded)
+			assert.Equal(t, tt.degraded, degraded.Status)
+			assert.Equal(t, tt.degradedReason, degraded.Reason)
+			assert.Equal(t, tt.degradedMsg, degraded.Message)
+
+			ready := reconciler.getCondition(uvr, "Ready")
+			require.NotNil(t, ready)
+			assert.Equal(t, tt.ready, ready.Status)
+			assert.Equal(t, tt.readyReason, ready.Reason)
+
+			events := drainEvents(recorder)
+			if tt.event == "" {
+				assert.Empty(t, events)
+			} else {
+				require.Len(t, events, 1)
+				assert.Contains(t, events[0], tt.event)
+			}
+		})
+	}
+}
+
+func TestReconciler_UnknownHealthWhileEstablishing(t *testing.T) {
+	s := createTestScheme(t)
+	uvr := createTestUVR("test-unknown-establishing", "default")
+	reconciler := createTestReconciler(fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).Build(), s)
+	reconciler.UnknownHealthPolicy = UnknownHealthError
+	recorder := reconciler.Recorder.(*record.FakeRecorder)
+
+	// A backend resource without conditions yet is not an unmapped state
+	status := &adapters.ReplicationStatus{State: "unknown", Health: adapters.ReplicationHealthUnknown,
+		Message: "No conditions available"}
+	reconciler.updateStatusFromEngineStatus(uvr, status, reconciler.Log)
+	reconciler.recordReady(uvr, status)
+
+	assert.Equal(t, adapters.ReplicationHealthUnknown, status.Health)
+	degraded := reconciler.getCondition(uvr, degradedCondition)
+	assert.True(t, degraded == nil || degraded.Status != metav1.ConditionTrue)
+	ready := reconciler.getCondition(uvr, "Ready")
+	require.NotNil(t, ready)
+	assert.Equal(t, "Progressing", ready.Reason)
+	assert.Empty(t, drainEvents(recorder))
+}
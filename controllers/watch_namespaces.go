@@ -0,0 +1,105 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+// WatchNamespacesCacheOptions returns manager cache options that only watch namespaced objects
+// in the given namespaces. Cluster-scoped objects such as the backend CRDs used for discovery
+// and storage classes are still cached cluster-wide, and so are CSIStorageCapacity objects,
+// which CSI drivers publish in their own namespaces rather than the tenants'. All namespaces
+// are watched when empty.
+func WatchNamespacesCacheOptions(namespaces []string) cache.Options {
+	if len(namespaces) == 0 {
+		return cache.Options{}
+	}
+	defaultNamespaces := make(map[string]cache.Config, len(namespaces))
+	for _, namespace := range namespaces {
+		defaultNamespaces[namespace] = cache.Config{}
+	}
+	return cache.Options{
+		DefaultNamespaces: defaultNamespaces,
+		ByObject: map[client.Object]cache.ByObject{
+			&storagev1.CSIStorageCapacity{}: {Namespaces: map[string]cache.Config{cache.AllNamespaces: {}}},
+		},
+	}
+}
+
+// ValidateWatchNamespaces checks that every namespace to watch exists, so a typo does not
+// leave the operator silently watching nothing. Getting a namespace is cluster-scoped, which
+// least-privilege deployments may not grant; the check is skipped when it is forbidden.
+func ValidateWatchNamespaces(ctx context.Context, c client.Reader, namespaces []string) error {
+	for _, namespace := range namespaces {
+		if err := c.Get(ctx, client.ObjectKey{Name: namespace}, &corev1.Namespace{}); err != nil {
+			if apierrors.IsForbidden(err) {
+				return nil
+			}
+			if apierrors.IsNotFound(err) {
+				return fmt.Errorf("namespace %q does not exist", namespace)
+			}
+			return fmt.Errorf("failed to get namespace %q: %w", namespace, err)
+		}
+	}
+	return nil
+}
+
+// validateSourceNamespaces rejects source PVCs outside the watched namespaces. The cache does
+// not hold them, so they would read as missing rather than as out of scope.
+func (r *UnifiedVolumeReplicationReconciler) validateSourceNamespaces(uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	sources := []replicationv1alpha1.VolumeSource{uvr.Spec.VolumeMapping.Source}
+	for _, member := range uvr.Spec.GroupMembers {
+		sources = append(sources, member.Source)
+	}
+	for _, source := range sources {
+		namespace := source.Namespace
+		if namespace == "" {
+			namespace = uvr.Namespace
+		}
+		if !r.watchesNamespace(namespace) {
+			return fmt.Errorf("source PVC %s/%s is outside the watched namespaces %s",
+				namespace, source.PvcName, strings.Join(r.WatchNamespaces, ","))
+		}
+	}
+	return nil
+}
+
+// watchesNamespace reports whether UVRs in the namespace are reconciled
+func (r *UnifiedVolumeReplicationReconciler) watchesNamespace(namespace string) bool {
+	return len(r.WatchNamespaces) == 0 || slices.Contains(r.WatchNamespaces, namespace)
+}
+
+// watchNamespacesPredicate admits only objects in the given namespaces. The manager cache is
+// already restricted to them; the predicate keeps the controller scoped when it is not.
+func watchNamespacesPredicate(namespaces []string) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return slices.Contains(namespaces, obj.GetNamespace())
+	})
+}
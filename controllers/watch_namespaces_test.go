@@ -0,0 +1,139 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+func TestReconciler_IgnoresUnwatchedNamespaces(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	watched := createTestUVR("test-watched", "tenant-a")
+	unwatched := createTestUVR("test-unwatched", "tenant-b")
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(watched, unwatched).
+		WithStatusSubresource(watched, unwatched).Build()
+	reconciler := createTestReconciler(c, s)
+	reconciler.WatchNamespaces = []string{"tenant-a"}
+
+	result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(unwatched)})
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+	current := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(unwatched), current))
+	assert.False(t, controllerutil.ContainsFinalizer(current, unifiedReplicationFinalizer), "a UVR outside the watched namespaces is left alone")
+
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(watched)})
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(watched), current))
+	assert.True(t, controllerutil.ContainsFinalizer(current, unifiedReplicationFinalizer))
+
+	// Without a restriction every namespace is reconciled
+	reconciler.WatchNamespaces = nil
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(unwatched)})
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(unwatched), current))
+	assert.True(t, controllerutil.ContainsFinalizer(current, unifiedReplicationFinalizer))
+}
+
+func TestReconciler_RejectsSourcePVCsInUnwatchedNamespaces(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	uvr := createTestUVR("test-cross-namespace", "tenant-a")
+	uvr.Spec.VolumeMapping.Source.Namespace = "tenant-b"
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).WithStatusSubresource(uvr).Build()
+	reconciler := createTestReconciler(c, s)
+	reconciler.WatchNamespaces = []string{"tenant-a"}
+
+	for i := 0; i < 2; i++ {
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)})
+		require.NoError(t, err)
+	}
+
+	current := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(uvr), current))
+	ready := meta.FindStatusCondition(current.Status.Conditions, "Ready")
+	require.NotNil(t, ready)
+	assert.Equal(t, "ValidationFailed", ready.Reason)
+	assert.Contains(t, ready.Message, "source PVC tenant-b/"+uvr.Spec.VolumeMapping.Source.PvcName+" is outside the watched namespaces tenant-a")
+}
+
+func TestWatchNamespacesPredicate(t *testing.T) {
+	p := watchNamespacesPredicate([]string{"tenant-a", "tenant-c"})
+	assert.True(t, p.Create(event.CreateEvent{Object: createTestUVR("a", "tenant-a")}))
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: createTestUVR("c", "tenant-c"), ObjectNew: createTestUVR("c", "tenant-c")}))
+	assert.False(t, p.Create(event.CreateEvent{Object: createTestUVR("b", "tenant-b")}))
+}
+
+func TestWatchNamespacesCacheOptions(t *testing.T) {
+	assert.Nil(t, WatchNamespacesCacheOptions(nil).DefaultNamespaces)
+
+	options := WatchNamespacesCacheOptions([]string{"tenant-a", "tenant-b"})
+	assert.Len(t, options.DefaultNamespaces, 2)
+	assert.Contains(t, options.DefaultNamespaces, "tenant-a")
+	assert.Contains(t, options.DefaultNamespaces, "tenant-b")
+
+	// Drivers publish storage capacity in their own namespaces, so it is read cluster-wide
+	require.Len(t, options.ByObject, 1)
+	for obj, byObject := range options.ByObject {
+		assert.IsType(t, &storagev1.CSIStorageCapacity{}, obj)
+		assert.Equal(t, map[string]cache.Config{cache.AllNamespaces: {}}, byObject.Namespaces)
+	}
+}
+
+func TestValidateWatchNamespaces(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithObjects(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-a"}}).Build()
+
+	assert.NoError(t, ValidateWatchNamespaces(ctx, c, nil))
+	assert.NoError(t, ValidateWatchNamespaces(ctx, c, []string{"tenant-a"}))
+	err := ValidateWatchNamespaces(ctx, c, []string{"tenant-a", "tenant-typo"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `namespace "tenant-typo" does not exist`)
+
+	// Without permission to get namespaces the check is skipped rather than failing startup
+	forbidden := fake.NewClientBuilder().WithScheme(s).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				return apierrors.NewForbidden(corev1.Resource("namespaces"), key.Name, errors.New("cluster-scoped access denied"))
+			},
+		}).Build()
+	assert.NoError(t, ValidateWatchNamespaces(ctx, forbidden, []string{"tenant-a", "tenant-typo"}))
+}
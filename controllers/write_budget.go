@@ -0,0 +1,82 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+var (
+	// writeBudgetUtilization reports how much of the global write budget is in use
+	writeBudgetUtilization = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "unified_replication_write_budget_utilization",
+		Help: "Share of the API write budget in use: 0 when idle, 1 when exhausted, above 1 while urgent reconciles overdraw it",
+	})
+
+	// writeBudgetDeferrals counts reconciles requeued because the write budget was exhausted
+	writeBudgetDeferrals = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "unified_replication_write_budget_deferred_reconciles_total",
+		Help: "Number of non-urgent reconciles requeued because the API write budget was exhausted",
+	})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(writeBudgetUtilization, writeBudgetDeferrals)
+}
+
+// isUrgentReconcile reports whether a reconcile must run even when the write budget is
+// exhausted: deletions, and role changes such as a failover, cannot wait for the fleet's
+// routine status updates
+func isUrgentReconcile(uvr *replicationv1alpha1.UnifiedVolumeReplication) bool {
+	if !uvr.DeletionTimestamp.IsZero() {
+		return true
+	}
+	n := len(uvr.Status.StateHistory)
+	if n == 0 {
+		return false
+	}
+	last := replicationv1alpha1.ReplicationState(uvr.Status.StateHistory[n-1].To)
+	switch uvr.Spec.ReplicationState {
+	case replicationv1alpha1.ReplicationStateSource:
+		return last == replicationv1alpha1.ReplicationStateReplica || last == replicationv1alpha1.ReplicationStateDemoting
+	case replicationv1alpha1.ReplicationStateReplica:
+		return last == replicationv1alpha1.ReplicationStateSource || last == replicationv1alpha1.ReplicationStatePromoting
+	case replicationv1alpha1.ReplicationStatePromoting, replicationv1alpha1.ReplicationStateDemoting:
+		return true
+	default:
+		return false
+	}
+}
+
+// admitReconcile checks the UVR's reconcile against the engine's write budget and returns
+// how long to defer it when it must wait
+func (r *UnifiedVolumeReplicationReconciler) admitReconcile(uvr *replicationv1alpha1.UnifiedVolumeReplication) (bool, time.Duration) {
+	if r.ControllerEngine == nil {
+		return true, 0
+	}
+	admitted, delay := r.ControllerEngine.AdmitReconcile(isUrgentReconcile(uvr))
+	writeBudgetUtilization.Set(r.ControllerEngine.WriteBudgetUtilization())
+	if !admitted {
+		writeBudgetDeferrals.Inc()
+	}
+	return admitted, delay
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestIsUrgentReconcile(t *testing.T) {
+	tests := []struct {
+		name     string
+		desired  replicationv1alpha1.ReplicationState
+		observed string
+		deleting bool
+		urgent   bool
+	}{
+		{name: "first reconcile", desired: replicationv1alpha1.ReplicationStateReplica},
+		{name: "steady replica", desired: replicationv1alpha1.ReplicationStateReplica, observed: "replica"},
+		{name: "replica catching up", desired: replicationv1alpha1.ReplicationStateReplica, observed: "syncing"},
+		{name: "failover", desired: replicationv1alpha1.ReplicationStateSource, observed: "replica", urgent: true},
+		{name: "demotion", desired: replicationv1alpha1.ReplicationStateReplica, observed: "source", urgent: true},
+		{name: "promoting", desired: replicationv1alpha1.ReplicationStatePromoting, observed: "replica", urgent: true},
+		{name: "deletion", desired: replicationv1alpha1.ReplicationStateReplica, observed: "replica", deleting: true, urgent: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uvr := createTestUVR("test-urgent", "default")
+			uvr.Spec.ReplicationState = tt.desired
+			if tt.observed != "" {
+				uvr.Status.StateHistory = []replicationv1alpha1.StateHistoryEntry{{To: tt.observed}}
+			}
+			if tt.deleting {
+				now := metav1.Now()
+				uvr.DeletionTimestamp = &now
+			}
+			assert.Equal(t, tt.urgent, isUrgentReconcile(uvr))
+		})
+	}
+}
+
+func TestReconciler_WriteBudget(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	routine := createTestUVR("test-routine", "default")
+	deleting := createTestUVR("test-deleting", "default")
+	deleting.Finalizers = []string{unifiedReplicationFinalizer}
+
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(routine, deleting).
+		WithStatusSubresource(routine, deleting).Build()
+	reconciler := createTestReconciler(c, s)
+	reconciler.ControllerEngine = pkg.NewControllerEngine(c, reconciler.DiscoveryEngine, reconciler.TranslationEngine,
+		adapters.NewRegistry(), &pkg.ControllerEngineConfig{WritesPerSecond: 0.01, WriteBurst: 1})
+	reconciler.Client = reconciler.ControllerEngine.BudgetedClient(c)
+
+	// Spend the budget, then start the deletion while it is exhausted
+	require.NoError(t, reconciler.Delete(ctx, deleting))
+
+	result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(routine)})
+	require.NoError(t, err)
+	assert.Positive(t, result.RequeueAfter, "routine reconciles are deferred")
+	current := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(routine), current))
+	assert.False(t, controllerutil.ContainsFinalizer(current, unifiedReplicationFinalizer), "nothing is written")
+
+	// The deletion proceeds regardless
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(deleting)})
+	require.NoError(t, err)
+	err = c.Get(ctx, client.ObjectKeyFromObject(deleting), current)
+	assert.True(t, apierrors.IsNotFound(err), "the finalizer was removed and the UVR is gone")
+	assert.Greater(t, reconciler.ControllerEngine.WriteBudgetUtilization(), 1.0, "the deletion overdrew the budget")
+}
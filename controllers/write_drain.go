@@ -0,0 +1,163 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// writesDrainedCondition reports whether the writes of a source being demoted have reached
+// the replica
+const writesDrainedCondition = "WritesDrained"
+
+// defaultWriteDrainTimeout bounds the wait for a drain when no timeout is configured
+const defaultWriteDrainTimeout = 5 * time.Minute
+
+// demotingSource reports whether the spec asks to demote a volume last observed as the source
+func demotingSource(uvr *replicationv1alpha1.UnifiedVolumeReplication) bool {
+	switch uvr.Spec.ReplicationState {
+	case replicationv1alpha1.ReplicationStateReplica, replicationv1alpha1.ReplicationStateDemoting:
+		return lastObservedRole(uvr) == replicationv1alpha1.ReplicationStateSource
+	}
+	return false
+}
+
+// drainWritesBeforeDemotion holds back the demotion of a source until the adapter has fenced
+// its writes and flushed them to the replica, so a planned role swap loses no data. Adapters
+// that cannot drain writes are demoted straight away, and so is a source whose drain has not
+// completed within the write drain timeout. It returns true, with the status updated, while
+// the reconcile has to wait for the drain.
+func (r *UnifiedVolumeReplicationReconciler) drainWritesBeforeDemotion(ctx context.Context, adapter adapters.ReplicationAdapter, uvr *replicationv1alpha1.UnifiedVolumeReplication, log logr.Logger) (ctrl.Result, bool) {
+	if !demotingSource(uvr) {
+		// A drain left unfinished, by a demotion that completed without waiting or was
+		// abandoned, must not count towards the timeout of the next one
+		if existing := r.getCondition(uvr, writesDrainedCondition); existing != nil && existing.Status == metav1.ConditionFalse {
+			apimeta.RemoveStatusCondition(&uvr.Status.Conditions, writesDrainedCondition)
+		}
+		return ctrl.Result{}, false
+	}
+	drainer, ok := adapter.(adapters.WriteDrainer)
+	if !ok {
+		return ctrl.Result{}, false
+	}
+
+	drained, err := drainer.DrainWrites(ctx, uvr)
+	if result, throttled := r.handleAPIThrottling(ctx, uvr, err, log); throttled {
+		return result, true
+	}
+	if err != nil {
+		log.Error(err, "Failed to drain writes before demotion")
+		message := fmt.Sprintf("Failed to drain writes before demotion: %v", err)
+		if existing := r.getCondition(uvr, writesDrainedCondition); existing == nil || existing.Reason != "DrainFailed" {
+			r.recordEventf(uvr, corev1.EventTypeWarning, "WriteDrainFailed", "%s", message)
+		}
+		r.setWriteDrain(uvr, metav1.ConditionFalse, "DrainFailed", message)
+		r.updateCondition(uvr, metav1.Condition{
+			Type:               "Ready",
+			Status:             metav1.ConditionFalse,
+			Reason:             "WriteDrainFailed",
+			Message:            message,
+			ObservedGeneration: uvr.Generation,
+		})
+		r.recordFailedReconcile(uvr)
+		if err := r.Status().Update(ctx, uvr); err != nil {
+			log.Error(err, "Failed to update status")
+		}
+		return ctrl.Result{RequeueAfter: requeueDelayError}, true
+	}
+
+	if !drained && r.writeDrainTimedOut(uvr) {
+		message := fmt.Sprintf("Writes did not drain within %s, demoting source anyway", r.getWriteDrainTimeout())
+		if existing := r.getCondition(uvr, writesDrainedCondition); existing.Reason != "DrainTimedOut" {
+			log.Info("Write drain timed out, demoting source", "timeout", r.getWriteDrainTimeout())
+			r.recordEventf(uvr, corev1.EventTypeWarning, "WriteDrainTimedOut", "%s", message)
+		}
+		r.setWriteDrain(uvr, metav1.ConditionFalse, "DrainTimedOut", message)
+		return ctrl.Result{}, false
+	}
+
+	if !drained {
+		log.Info("Waiting for writes to drain before demotion")
+		message := "Writes are fenced, waiting for the replica to catch up before demotion"
+		if existing := r.getCondition(uvr, writesDrainedCondition); existing == nil || existing.Reason != "Draining" {
+			r.recordEventf(uvr, corev1.EventTypeNormal, "DrainingWrites", "%s", message)
+		}
+		r.setWriteDrain(uvr, metav1.ConditionFalse, "Draining", message)
+		r.updateCondition(uvr, metav1.Condition{
+			Type:               "Ready",
+			Status:             metav1.ConditionFalse,
+			Reason:             "DrainingWrites",
+			Message:            message,
+			ObservedGeneration: uvr.Generation,
+		})
+		if err := r.Status().Update(ctx, uvr); err != nil {
+			log.Error(err, "Failed to update status")
+		}
+		return ctrl.Result{RequeueAfter: requeueDelayFast}, true
+	}
+
+	if existing := r.getCondition(uvr, writesDrainedCondition); existing == nil || existing.Status != metav1.ConditionTrue {
+		log.Info("Writes drained, demoting source")
+		r.recordEventf(uvr, corev1.EventTypeNormal, "WritesDrained", "Writes drained to the replica, demoting source")
+	}
+	r.setWriteDrain(uvr, metav1.ConditionTrue, "Drained", "All writes accepted before the fence reached the replica")
+	return ctrl.Result{}, false
+}
+
+// writeDrainTimedOut reports whether the drain in progress started longer ago than the write
+// drain timeout. The drain starts when WritesDrained turns False, which a failed request
+// does too, so retries of the request count towards the timeout.
+func (r *UnifiedVolumeReplicationReconciler) writeDrainTimedOut(uvr *replicationv1alpha1.UnifiedVolumeReplication) bool {
+	existing := r.getCondition(uvr, writesDrainedCondition)
+	if existing == nil || existing.Status != metav1.ConditionFalse {
+		return false
+	}
+	if existing.Reason != "Draining" && existing.Reason != "DrainTimedOut" {
+		return false
+	}
+	return time.Since(existing.LastTransitionTime.Time) > r.getWriteDrainTimeout()
+}
+
+// getWriteDrainTimeout returns the configured timeout, defaultWriteDrainTimeout when unset
+func (r *UnifiedVolumeReplicationReconciler) getWriteDrainTimeout() time.Duration {
+	if r.WriteDrainTimeout <= 0 {
+		return defaultWriteDrainTimeout
+	}
+	return r.WriteDrainTimeout
+}
+
+// setWriteDrain records the progress of a write drain
+func (r *UnifiedVolumeReplicationReconciler) setWriteDrain(uvr *replicationv1alpha1.UnifiedVolumeReplication, status metav1.ConditionStatus, reason, message string) {
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               writesDrainedCondition,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestReconciler_DrainWritesBeforeCephDemotion(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+	s.AddKnownTypes(schema.GroupVersion{Group: "replication.storage.openshift.io", Version: "v1alpha1"},
+		&adapters.VolumeReplication{}, &adapters.VolumeReplicationList{})
+
+	// A source being demoted as part of a planned role swap
+	uvr := createTestUVR("test-drain", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	uvr.Spec.Extensions = &replicationv1alpha1.Extensions{Ceph: &replicationv1alpha1.CephExtensions{}}
+	uvr.Spec.SourceEndpoint.StorageClass = "ceph-rbd"
+	uvr.Spec.DestinationEndpoint.StorageClass = "ceph-rbd"
+	uvr.Status.StateHistory = []replicationv1alpha1.StateHistoryEntry{
+		{Timestamp: metav1.Now(), To: string(replicationv1alpha1.ReplicationStateSource)},
+	}
+	vr := &adapters.VolumeReplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-drain-vr", Namespace: "default"},
+		Spec: adapters.VolumeReplicationSpec{
+			VolumeReplicationClass: "rbd-volumereplicationclass",
+			PvcName:                "source-pvc",
+			ReplicationState:       "primary",
+		},
+	}
+
+	// Record every change made to the VolumeReplication, in order
+	var steps []string
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(cephCRDs(), uvr, vr)...).
+		WithStatusSubresource(uvr).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				if updated, ok := obj.(*adapters.VolumeReplication); ok {
+					if _, drain := updated.Annotations[adapters.DrainRequestedAnnotation]; drain {
+						steps = append(steps, "drain:"+updated.Spec.ReplicationState)
+					} else {
+						steps = append(steps, "update:"+updated.Spec.ReplicationState)
+					}
+				}
+				return c.Update(ctx, obj, opts...)
+			},
+		}).Build()
+	reconciler := createTestReconciler(c, s)
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+
+	// The first reconcile fences writes and waits for them to reach the replica
+	result, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, requeueDelayFast, result.RequeueAfter)
+	assert.Equal(t, []string{"drain:primary"}, steps)
+
+	updated := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	ready := reconciler.getCondition(updated, "Ready")
+	require.NotNil(t, ready)
+	assert.Equal(t, "DrainingWrites", ready.Reason)
+
+	// The source stays primary until a sync completes after the drain request
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"drain:primary"}, steps)
+
+	current := &adapters.VolumeReplication{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(vr), current))
+	synced := metav1.NewTime(time.Now().Add(time.Minute))
+	current.Status.LastSyncTime = &synced
+	require.NoError(t, c.Update(ctx, current))
+	steps = nil
+
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, steps, 2)
+	assert.Equal(t, "drain:primary", steps[0], "the drained sync is recorded as quiesced")
+	assert.Equal(t, "update:secondary", steps[1], "the demotion follows the drain and clears the request")
+
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(vr), current))
+	assert.Equal(t, synced.UTC().Format(time.RFC3339), current.Annotations[adapters.QuiescedSyncTimeAnnotation])
+
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	drained := reconciler.getCondition(updated, writesDrainedCondition)
+	require.NotNil(t, drained)
+	assert.Equal(t, metav1.ConditionTrue, drained.Status)
+	assert.Equal(t, "Drained", drained.Reason)
+	assert.Equal(t, string(adapters.ConsistencyLevelApplication), updated.Status.ConsistencyLevel,
+		"the latest recovery point was taken with writes fenced")
+}
+
+func TestReconciler_MockDrainPrecedesDemotion(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+
+	uvr := createTestUVR("test-mock-drain", "default")
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).WithStatusSubresource(uvr).Build()
+	reconciler := createTestReconciler(c, s)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(uvr), uvr))
+	adapter := newSplitBrainMockAdapter(t, c, uvr)
+
+	// A replica spec on a volume last seen as the source is a demotion
+	uvr.Status.StateHistory = []replicationv1alpha1.StateHistoryEntry{
+		{Timestamp: metav1.Now(), To: string(replicationv1alpha1.ReplicationStateSource)},
+	}
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateReplica
+	_, waiting := reconciler.drainWritesBeforeDemotion(ctx, adapter, uvr, reconciler.Log)
+	require.False(t, waiting)
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+
+	repl, ok := adapter.GetMockReplication(uvr)
+	require.True(t, ok)
+	require.Len(t, repl.Events, 3)
+	assert.Equal(t, adapters.EventTypeDrained, repl.Events[1].Type)
+	assert.Equal(t, adapters.EventTypeUpdated, repl.Events[2].Type)
+	assert.Equal(t, string(replicationv1alpha1.ReplicationStateReplica), repl.State)
+
+	// Volumes that are not being demoted are left alone
+	uvr.Status.StateHistory = append(uvr.Status.StateHistory, replicationv1alpha1.StateHistoryEntry{
+		Timestamp: metav1.Now(), To: string(replicationv1alpha1.ReplicationStateReplica),
+	})
+	_, waiting = reconciler.drainWritesBeforeDemotion(ctx, adapter, uvr, reconciler.Log)
+	require.False(t, waiting)
+	assert.Len(t, repl.Events, 3)
+}
+
+func TestReconciler_WriteDrainTimeout(t *testing.T) {
+	ctx := context.Background()
+	s := createTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+	s.AddKnownTypes(schema.GroupVersion{Group: "replication.storage.openshift.io", Version: "v1alpha1"},
+		&adapters.VolumeReplication{}, &adapters.VolumeReplicationList{})
+
+	// A source whose writes have been draining for longer than the timeout
+	uvr := createTestUVR("test-drain-timeout", "default")
+	uvr.Finalizers = []string{unifiedReplicationFinalizer}
+	uvr.Spec.Extensions = &replicationv1alpha1.Extensions{Ceph: &replicationv1alpha1.CephExtensions{}}
+	uvr.Spec.SourceEndpoint.StorageClass = "ceph-rbd"
+	uvr.Spec.DestinationEndpoint.StorageClass = "ceph-rbd"
+	uvr.Status.StateHistory = []replicationv1alpha1.StateHistoryEntry{
+		{Timestamp: metav1.Now(), To: string(replicationv1alpha1.ReplicationStateSource)},
+	}
+	uvr.Status.Conditions = []metav1.Condition{{
+		Type:               writesDrainedCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Draining",
+		LastTransitionTime: metav1.NewTime(time.Now().Add(-10 * time.Minute)),
+	}}
+	vr := &adapters.VolumeReplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-drain-timeout-vr", Namespace: "default"},
+		Spec: adapters.VolumeReplicationSpec{
+			VolumeReplicationClass: "rbd-volumereplicationclass",
+			PvcName:                "source-pvc",
+			ReplicationState:       "primary",
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(append(cephCRDs(), uvr, vr)...).
+		WithStatusSubresource(uvr).Build()
+	reconciler := createTestReconciler(c, s)
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uvr)}
+
+	// No sync ever completes, but the source is demoted once the timeout has passed
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	current := &adapters.VolumeReplication{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(vr), current))
+	assert.Equal(t, "secondary", current.Spec.ReplicationState)
+	assert.True(t, containsEvent(drainEvents(reconciler.Recorder.(*record.FakeRecorder)), "Warning WriteDrainTimedOut"))
+
+	updated := &replicationv1alpha1.UnifiedVolumeReplication{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	drained := reconciler.getCondition(updated, writesDrainedCondition)
+	require.NotNil(t, drained)
+	assert.Equal(t, "DrainTimedOut", drained.Reason)
+
+	// Once the volume is no longer being demoted the unfinished drain is forgotten, so it
+	// cannot cut short the next one
+	updated.Status.StateHistory = append(updated.Status.StateHistory, replicationv1alpha1.StateHistoryEntry{
+		Timestamp: metav1.Now(), To: string(replicationv1alpha1.ReplicationStateReplica),
+	})
+	_, waiting := reconciler.drainWritesBeforeDemotion(ctx, nil, updated, reconciler.Log)
+	assert.False(t, waiting)
+	assert.Nil(t, reconciler.getCondition(updated, writesDrainedCondition))
+}
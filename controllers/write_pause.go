@@ -0,0 +1,106 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+// writesShouldPauseCondition is the signal application controllers watch to pause writes
+// while the replica lags further behind than spec.schedule.maxLag
+const writesShouldPauseCondition = "WritesShouldPause"
+
+// writesShouldPause mirrors the WritesShouldPause condition, for consumers that watch
+// metrics rather than the UVR
+var writesShouldPause = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "unified_replication_writes_should_pause",
+	Help: "1 while the replication lag exceeds the maximum lag and applications should pause writes",
+}, []string{"namespace", "name"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(writesShouldPause)
+}
+
+// recordWritePause raises WritesShouldPause while the time since the last sync exceeds
+// spec.schedule.maxLag and clears it once the replica catches up. The operator only
+// signals; pausing writes is left to the application.
+func (r *UnifiedVolumeReplicationReconciler) recordWritePause(uvr *replicationv1alpha1.UnifiedVolumeReplication, status *adapters.ReplicationStatus) {
+	existing := r.getCondition(uvr, writesShouldPauseCondition)
+	maxLag, ok := lagLimit(uvr.Spec.Schedule.MaxLag)
+	if !ok {
+		// No signal is given without a limit; release one raised under an earlier spec
+		if existing != nil {
+			r.updateCondition(uvr, metav1.Condition{
+				Type:               writesShouldPauseCondition,
+				Status:             metav1.ConditionFalse,
+				Reason:             "MaxLagUnset",
+				Message:            "No maximum lag is set",
+				ObservedGeneration: uvr.Generation,
+			})
+		}
+		forgetWritePause(uvr)
+		return
+	}
+	lastSync := lastSyncOf(uvr, status)
+	if lastSync == nil {
+		// The lag is unknown until the first sync, keep the current signal
+		return
+	}
+
+	measured := measureSyncLag(*lastSync, maxLag)
+	if measured.Exceeded {
+		message := fmt.Sprintf("Replication lag %s exceeds maximum lag %s, writes should pause", measured.Lag, uvr.Spec.Schedule.MaxLag)
+		if existing == nil || existing.Status != metav1.ConditionTrue {
+			r.recordEventf(uvr, corev1.EventTypeWarning, "WritesShouldPause", "%s", message)
+		}
+		r.updateCondition(uvr, metav1.Condition{
+			Type:               writesShouldPauseCondition,
+			Status:             metav1.ConditionTrue,
+			Reason:             "MaxLagExceeded",
+			Message:            message,
+			ObservedGeneration: uvr.Generation,
+		})
+		writesShouldPause.WithLabelValues(uvr.Namespace, uvr.Name).Set(1)
+		return
+	}
+
+	message := fmt.Sprintf("Replication lag %s is within maximum lag %s", measured.Lag, uvr.Spec.Schedule.MaxLag)
+	if existing != nil && existing.Status == metav1.ConditionTrue {
+		r.recordEventf(uvr, corev1.EventTypeNormal, "WritesMayResume", "%s", message)
+	}
+	r.updateCondition(uvr, metav1.Condition{
+		Type:               writesShouldPauseCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             "LagWithinLimit",
+		Message:            message,
+		ObservedGeneration: uvr.Generation,
+	})
+	writesShouldPause.WithLabelValues(uvr.Namespace, uvr.Name).Set(0)
+}
+
+// forgetWritePause drops the write pause signal of a deleted UVR
+func forgetWritePause(uvr *replicationv1alpha1.UnifiedVolumeReplication) {
+	writesShouldPause.DeleteLabelValues(uvr.Namespace, uvr.Name)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/unified-replication/operator/pkg/adapters"
+)
+
+func TestReconciler_WritesShouldPause(t *testing.T) {
+	s := createTestScheme(t)
+	uvr := createTestUVR("test-write-pause", "default")
+	uvr.Spec.Schedule.MaxLag = "2m"
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(uvr).Build()
+	reconciler := createTestReconciler(c, s)
+	recorder := reconciler.Recorder.(*record.FakeRecorder)
+
+	statusSyncedAgo := func(lag time.Duration) *adapters.ReplicationStatus {
+		lastSync := time.Now().Add(-lag)
+		return &adapters.ReplicationStatus{State: "source", Health: adapters.ReplicationHealthHealthy, LastSyncTime: &lastSync}
+	}
+	signal := func() float64 {
+		metric := &dto.Metric{}
+		require.NoError(t, writesShouldPause.WithLabelValues(uvr.Namespace, uvr.Name).(prometheus.Gauge).Write(metric))
+		return metric.GetGauge().GetValue()
+	}
+
+	// Within the limit, writes carry on
+	reconciler.recordWritePause(uvr, statusSyncedAgo(30*time.Second))
+	cond := reconciler.getCondition(uvr, writesShouldPauseCondition)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, "LagWithinLimit", cond.Reason)
+	assert.Equal(t, 0.0, signal())
+	assert.Empty(t, drainEvents(recorder))
+
+	// Crossing the limit raises the signal once
+	reconciler.recordWritePause(uvr, statusSyncedAgo(5*time.Minute))
+	reconciler.recordWritePause(uvr, statusSyncedAgo(6*time.Minute))
+	cond = reconciler.getCondition(uvr, writesShouldPauseCondition)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, "MaxLagExceeded", cond.Reason)
+	assert.Contains(t, cond.Message, "exceeds maximum lag 2m")
+	assert.Equal(t, 1.0, signal())
+	events := drainEvents(recorder)
+	require.Len(t, events, 1)
+	assert.Contains(t, events[0], "WritesShouldPause")
+
+	// Before the first sync the lag is unknown and the signal is kept
+	reconciler.recordWritePause(uvr, &adapters.ReplicationStatus{State: "source"})
+	assert.Equal(t, metav1.ConditionTrue, reconciler.getCondition(uvr, writesShouldPauseCondition).Status)
+
+	// Catching up clears it
+	reconciler.recordWritePause(uvr, statusSyncedAgo(10*time.Second))
+	cond = reconciler.getCondition(uvr, writesShouldPauseCondition)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, "LagWithinLimit", cond.Reason)
+	assert.Equal(t, 0.0, signal())
+	events = drainEvents(recorder)
+	require.Len(t, events, 1)
+	assert.Contains(t, events[0], "WritesMayResume")
+
+	// Without a limit the signal is released and the metric dropped
+	reconciler.recordWritePause(uvr, statusSyncedAgo(5*time.Minute))
+	uvr.Spec.Schedule.MaxLag = ""
+	reconciler.recordWritePause(uvr, statusSyncedAgo(5*time.Minute))
+	cond = reconciler.getCondition(uvr, writesShouldPauseCondition)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, "MaxLagUnset", cond.Reason)
+	assert.False(t, writesShouldPause.DeleteLabelValues(uvr.Namespace, uvr.Name), "the metric is dropped")
+}
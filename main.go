@@ -17,8 +17,12 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
+	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -26,17 +30,22 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
 	"github.com/unified-replication/operator/controllers"
 	"github.com/unified-replication/operator/pkg"
 	"github.com/unified-replication/operator/pkg/adapters"
 	"github.com/unified-replication/operator/pkg/discovery"
+	"github.com/unified-replication/operator/pkg/security"
 	"github.com/unified-replication/operator/pkg/translation"
 	//+kubebuilder:scaffold:imports
 )
@@ -44,6 +53,9 @@ import (
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
+
+	// version is set at build time with -ldflags "-X main.version=..."
+	version = "dev"
 )
 
 func init() {
@@ -55,6 +67,149 @@ func init() {
 }
 
 func main() {
+	var backendSelectionWebhookURL string
+	var backendSelectionWebhookTimeout time.Duration
+	var backendSelectionWebhookFailOpen bool
+	flag.StringVar(&backendSelectionWebhookURL, "backend-selection-webhook-url", "",
+		"URL of an external webhook that chooses the backend for each replication. Built-in selection is used when empty.")
+	flag.DurationVar(&backendSelectionWebhookTimeout, "backend-selection-webhook-timeout", 5*time.Second,
+		"Timeout for a single backend selection webhook call.")
+	flag.BoolVar(&backendSelectionWebhookFailOpen, "backend-selection-webhook-fail-open", true,
+		"Fall back to built-in backend selection when the webhook fails. When false, reconciliation fails instead.")
+
+	var reconcileWebhookURL string
+	var reconcileWebhookResults string
+	flag.StringVar(&reconcileWebhookURL, "reconcile-webhook-url", "",
+		"URL to POST a summary of every reconcile to, for pipelines that track convergence. Disabled when empty.")
+	flag.StringVar(&reconcileWebhookResults, "reconcile-webhook-results", "",
+		"Comma-separated reconcile results to post to the reconcile webhook: Synced, Progressing, Degraded. All when empty.")
+
+	var isolateBackends bool
+	flag.BoolVar(&isolateBackends, "isolate-backend-reconciles", false,
+		"Reconcile each storage backend with its own workqueue and workers so a slow backend cannot delay the others.")
+
+	var reconcileCaptureDir string
+	var reconcileCaptureMaxFiles int
+	flag.StringVar(&reconcileCaptureDir, "reconcile-capture-dir", "",
+		"Directory to write a capture of every reconcile to, for offline replay with urep-replay. Disabled when empty.")
+	flag.IntVar(&reconcileCaptureMaxFiles, "reconcile-capture-max-files", controllers.DefaultReconcileCaptureMaxFiles,
+		"Captures kept in --reconcile-capture-dir; the oldest are removed as new ones are written. Unlimited when 0.")
+
+	var reconcileOrder string
+	flag.StringVar(&reconcileOrder, "reconcile-order", string(controllers.ReconcileOrderFIFO),
+		"Order in which queued replications are reconciled, most visible after a restart: fifo, age (oldest first) "+
+			"or priority (highest spec.priority first, then oldest).")
+
+	var specCoalesceWindow time.Duration
+	flag.DurationVar(&specCoalesceWindow, "spec-change-coalesce-window", time.Second,
+		"Delay before reconciling a spec edit, so rapid successive edits are applied by one reconcile against the latest spec. 0 disables it.")
+
+	var missingSourcePVCPolicy string
+	flag.StringVar(&missingSourcePVCPolicy, "missing-source-pvc-policy", string(controllers.MissingSourcePVCIgnore),
+		"What to do when a replication's source PVC does not exist or is not Bound: ignore (skip the check), "+
+			"wait (check again every 10s until it is Bound) or fail (stop until the spec changes).")
+
+	var requireFailoverApproval bool
+	flag.BoolVar(&requireFailoverApproval, "require-failover-approval", false,
+		"Hold every failover of a replica to source until the replication carries the "+
+			"replication.storage.io/failover-approved=true annotation.")
+
+	var deletionSyncPolicy string
+	flag.StringVar(&deletionSyncPolicy, "deletion-sync-policy", string(controllers.DeletionSyncImmediate),
+		"What deleting a replication does to a sync in progress: wait (let it finish, up to --deletion-sync-timeout) "+
+			"or immediate (tear the replication down straight away).")
+	var deletionSyncTimeout time.Duration
+	flag.DurationVar(&deletionSyncTimeout, "deletion-sync-timeout", 5*time.Minute,
+		"Longest a deletion waits for an in-progress sync under --deletion-sync-policy=wait, after which the replication is deleted anyway.")
+
+	var writeDrainTimeout time.Duration
+	flag.DurationVar(&writeDrainTimeout, "write-drain-timeout", 5*time.Minute,
+		"Longest a demotion waits for fenced writes to reach the replica, after which the source is demoted anyway.")
+
+	var unknownHealthPolicy string
+	flag.StringVar(&unknownHealthPolicy, "unknown-health-policy", string(controllers.UnknownHealthDegrade),
+		"How to treat a backend state the adapter cannot map to a health: Degrade (degraded, with an alert), "+
+			"Ignore (healthy, noted in the Degraded condition) or Error (unhealthy).")
+
+	var simulateDegradationNamespaces string
+	flag.StringVar(&simulateDegradationNamespaces, "simulate-degradation-namespaces", "",
+		"Comma-separated namespaces whose replications may report a synthetic degradation for DR rehearsals "+
+			"with the replication.storage.io/simulate-degradation annotation. Disabled when empty.")
+
+	var rpoComplianceThresholds string
+	flag.StringVar(&rpoComplianceThresholds, "rpo-compliance-thresholds", "",
+		"Per-backend RPO compliance thresholds as backend=warning:critical pairs, e.g. powerstore=99:95. "+
+			"Compliance below warning reports the replication Degraded, below critical Unhealthy.")
+
+	var destinationFreeSpaceThreshold string
+	flag.StringVar(&destinationFreeSpaceThreshold, "destination-free-space-threshold", "0",
+		"Free space, as a quantity such as 50Gi, below which replications into a destination storage class are "+
+			"paused until space is reclaimed. Only storage classes whose CSI driver publishes capacity are checked. "+
+			"Disabled when 0.")
+
+	var failoverStepGrace string
+	flag.StringVar(&failoverStepGrace, "failover-step-grace", "",
+		"Per-backend time a role change lets the previous one settle, whether a failover's demotion before its promotion "+
+			"or an application switching its members one after the other, "+
+			"as comma-separated backend=duration pairs, e.g. powerstore=5s,ceph=2m. No grace period when unset.")
+
+	var maxConcurrentFailovers int
+	flag.IntVar(&maxConcurrentFailovers, "max-concurrent-failovers", 0,
+		"Promotions that may be in progress at once across the cluster. Further promotions are queued and proceed "+
+			"in the order they were requested as earlier ones complete. Unlimited when 0.")
+
+	var failoverSlotTimeout time.Duration
+	flag.DurationVar(&failoverSlotTimeout, "failover-slot-timeout", 15*time.Minute,
+		"How long a promotion may hold one of the --max-concurrent-failovers slots before it is handed to the next "+
+			"queued promotion. Slots never expire when 0.")
+
+	var watchNamespaces string
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "",
+		"Comma-separated namespaces whose replications the operator reconciles. Namespaced resources are only "+
+			"watched in these namespaces, so the operator can run with namespace-scoped RBAC. All namespaces when empty.")
+
+	var writeBudget float64
+	var writeBurst int
+	flag.Float64Var(&writeBudget, "write-budget", 0,
+		"API writes per second allowed across all reconciles. Routine reconciles are requeued while the budget is "+
+			"spent; deletions and role changes always proceed. Disabled when 0.")
+	flag.IntVar(&writeBurst, "write-budget-burst", 0,
+		"Writes that may be made at once under --write-budget. Defaults to one second's worth.")
+
+	var operationDedupeWindow time.Duration
+	flag.DurationVar(&operationDedupeWindow, "operation-dedupe-window", pkg.DefaultControllerEngineConfig().OperationDedupeWindow,
+		"Skip a backend operation identical to the replication's latest one, same spec and annotations, when that "+
+			"one succeeded within this window. Ensure is never skipped. Disabled when 0.")
+
+	var maxConcurrentBackendOperations int
+	flag.IntVar(&maxConcurrentBackendOperations, "max-concurrent-backend-operations", pkg.DefaultMaxConcurrentBackendOperations,
+		"Backend calls that may run against each backend at a time across all reconciles; further calls wait for a "+
+			"free slot. Disabled when 0.")
+
+	var apiCallLogThreshold int
+	flag.IntVar(&apiCallLogThreshold, "api-call-log-threshold", 0,
+		"Log reconciles making more API requests than this, with their requests by verb. Disabled when 0.")
+
+	var fleetSweepInterval time.Duration
+	flag.DurationVar(&fleetSweepInterval, "fleet-sweep-interval", 0,
+		"Reconcile every replication once per interval, spread evenly across it, to catch drift on the backends "+
+			"that no watch reports, and report backend resources whose replication no longer exists. Disabled when 0.")
+
+	var backendConfigMap string
+	flag.StringVar(&backendConfigMap, "backend-config-configmap", "",
+		"ConfigMap, as namespace/name, holding per-backend adapter settings and state map overrides: one key per "+
+			"backend with its settings as JSON. Edits are applied without a restart. Adapter defaults are used when empty.")
+
+	var genericCSIClass string
+	flag.StringVar(&genericCSIClass, "generic-csi-volume-replication-class", "",
+		"VolumeReplicationClass of the generic CSI adapter, which drives any CSI driver implementing the standard "+
+			"VolumeReplication. Replications that no extension or storage class ties to a specific backend use it once "+
+			"the VolumeReplication CRD is discovered. Disabled when empty.")
+	var genericCSIStates string
+	flag.StringVar(&genericCSIStates, "generic-csi-state-map", "",
+		"State map of the generic CSI adapter as comma-separated unified=driver pairs, e.g. "+
+			"source=primary,replica=secondary,syncing=resync, the default when empty.")
+
 	opts := zap.Options{
 		Development: true,
 	}
@@ -63,8 +218,131 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	order, err := controllers.ParseReconcileOrder(reconcileOrder)
+	if err != nil {
+		setupLog.Error(err, "invalid --reconcile-order")
+		os.Exit(1)
+	}
+
+	sourcePVCPolicy, err := controllers.ParseMissingSourcePVCPolicy(missingSourcePVCPolicy)
+	if err != nil {
+		setupLog.Error(err, "invalid --missing-source-pvc-policy")
+		os.Exit(1)
+	}
+
+	syncPolicy, err := controllers.ParseDeletionSyncPolicy(deletionSyncPolicy)
+	if err != nil {
+		setupLog.Error(err, "invalid --deletion-sync-policy")
+		os.Exit(1)
+	}
+
+	healthPolicy, err := controllers.ParseUnknownHealthPolicy(unknownHealthPolicy)
+	if err != nil {
+		setupLog.Error(err, "invalid --unknown-health-policy")
+		os.Exit(1)
+	}
+
+	webhookResults, err := controllers.ParseReconcileResults(reconcileWebhookResults)
+	if err != nil {
+		setupLog.Error(err, "invalid --reconcile-webhook-results")
+		os.Exit(1)
+	}
+
+	complianceThresholds, err := controllers.ParseRPOComplianceThresholds(rpoComplianceThresholds)
+	if err != nil {
+		setupLog.Error(err, "invalid --rpo-compliance-thresholds")
+		os.Exit(1)
+	}
+
+	stepGracePeriods, err := controllers.ParseStepGracePeriods(failoverStepGrace)
+	if err != nil {
+		setupLog.Error(err, "invalid --failover-step-grace")
+		os.Exit(1)
+	}
+
+	freeSpaceThreshold, err := resource.ParseQuantity(destinationFreeSpaceThreshold)
+	if err != nil {
+		setupLog.Error(err, "invalid --destination-free-space-threshold")
+		os.Exit(1)
+	}
+
+	if writeBudget < 0 || writeBurst < 0 {
+		setupLog.Error(errors.New("must not be negative"), "invalid --write-budget")
+		os.Exit(1)
+	}
+
+	if maxConcurrentFailovers < 0 {
+		setupLog.Error(errors.New("must not be negative"), "invalid --max-concurrent-failovers")
+		os.Exit(1)
+	}
+	if failoverSlotTimeout < 0 {
+		setupLog.Error(errors.New("must not be negative"), "invalid --failover-slot-timeout")
+		os.Exit(1)
+	}
+
+	if reconcileCaptureMaxFiles < 0 {
+		setupLog.Error(errors.New("must not be negative"), "invalid --reconcile-capture-max-files")
+		os.Exit(1)
+	}
+
+	if apiCallLogThreshold < 0 {
+		setupLog.Error(errors.New("must not be negative"), "invalid --api-call-log-threshold")
+		os.Exit(1)
+	}
+
+	var genericCSIFactory *adapters.GenericCSIAdapterFactory
+	if genericCSIClass != "" {
+		states, err := adapters.ParseGenericCSIStateMap(genericCSIStates)
+		if err == nil {
+			genericCSIFactory = adapters.NewGenericCSIAdapterFactory()
+			err = genericCSIFactory.SetDefaults(genericCSIClass, states)
+		}
+		if err != nil {
+			setupLog.Error(err, "invalid --generic-csi-state-map")
+			os.Exit(1)
+		}
+	} else if genericCSIStates != "" {
+		setupLog.Error(errors.New("requires --generic-csi-volume-replication-class"), "invalid --generic-csi-state-map")
+		os.Exit(1)
+	}
+
+	var backendConfigRef types.NamespacedName
+	if backendConfigMap != "" {
+		if backendConfigRef, err = controllers.ParseConfigMapRef(backendConfigMap); err != nil {
+			setupLog.Error(err, "invalid --backend-config-configmap")
+			os.Exit(1)
+		}
+	}
+
+	cfg := ctrl.GetConfigOrDie()
+
+	// The adapter metrics snapshot is served next to /metrics and requires a token allowed
+	// to get the path through RBAC. The manager client is not available yet, so reviews use
+	// a direct client.
+	reviewClient, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create client for access reviews")
+		os.Exit(1)
+	}
+	namespaces := splitNamespaces(watchNamespaces)
+	if err := controllers.ValidateWatchNamespaces(context.Background(), reviewClient, namespaces); err != nil {
+		setupLog.Error(err, "invalid --watch-namespaces")
+		os.Exit(1)
+	}
+	if len(namespaces) > 0 {
+		setupLog.Info("Watching namespaces", "namespaces", namespaces)
+	}
+
+	authorizer := security.NewKubernetesAuthorizer(reviewClient)
+	auditLogger := security.NewAuditLogger(ctrl.Log, true)
+	adapterMetricsHandler := security.RequireAuthorization(authorizer, auditLogger, adapters.GetOperationMetrics())
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
 		Scheme: scheme,
+		Cache:  controllers.WatchNamespacesCacheOptions(namespaces),
+		Metrics: metricsserver.Options{
+			ExtraHandlers: map[string]http.Handler{adapters.AdapterMetricsPath: adapterMetricsHandler},
+		},
 		// Leader election disabled - single replica deployment only
 		LeaderElection: false,
 	})
@@ -73,18 +351,74 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Initialize components
+	// Initialize components. Their requests are counted per reconcile.
+	apiClient := pkg.CountingClient(mgr.GetClient())
 	translationEngine := translation.NewEngine()
-	discoveryEngine := discovery.NewEngine(mgr.GetClient(), discovery.DefaultDiscoveryConfig())
+	discoveryEngine := discovery.NewEngine(apiClient, discovery.DefaultDiscoveryConfig())
 
 	// Initialize adapter registry
 	adapterRegistry := adapters.NewRegistry()
 	adapterRegistry.RegisterFactory(adapters.NewCephAdapterFactory())
 	adapterRegistry.RegisterFactory(adapters.NewTridentAdapterFactory())
 	adapterRegistry.RegisterFactory(adapters.NewPowerStoreAdapterFactory())
+	if genericCSIFactory != nil {
+		adapterRegistry.RegisterFactory(genericCSIFactory)
+		setupLog.Info("Using the generic CSI adapter", "volumeReplicationClass", genericCSIClass)
+	}
 
-	// Initialize controller engine
-	controllerEngine := pkg.NewControllerEngine(mgr.GetClient(), discoveryEngine, translationEngine, adapterRegistry, pkg.DefaultControllerEngineConfig())
+	operatorInfoHandler := adapters.NewOperatorInfoHandler(version, adapterRegistry, replicationv1alpha1.DefaultFeatureGates)
+	if err := mgr.AddMetricsServerExtraHandler(adapters.OperatorInfoPath, security.RequireAuthorization(authorizer, auditLogger, operatorInfoHandler)); err != nil {
+		setupLog.Error(err, "unable to serve operator info")
+		os.Exit(1)
+	}
+	// Lists the factories reconciles create adapters from, and those registered globally
+	adapterRegistryHandler := adapters.NewAdapterRegistryHandler(
+		adapters.NamedRegistry{Name: "operator", Registry: adapterRegistry},
+		adapters.NamedRegistry{Name: "global", Registry: adapters.GetGlobalRegistry()},
+	)
+	if err := mgr.AddMetricsServerExtraHandler(adapters.AdapterRegistryPath, security.RequireAuthorization(authorizer, auditLogger, adapterRegistryHandler)); err != nil {
+		setupLog.Error(err, "unable to serve adapter registry")
+		os.Exit(1)
+	}
+	replicationGraphHandler := controllers.NewReplicationGraphHandler(mgr.GetClient(), 0)
+	if err := mgr.AddMetricsServerExtraHandler(controllers.ReplicationGraphPath, security.RequireAuthorization(authorizer, auditLogger, replicationGraphHandler)); err != nil {
+		setupLog.Error(err, "unable to serve replication graph")
+		os.Exit(1)
+	}
+
+	adapterManager := adapters.NewAdapterManager(adapterRegistry, nil)
+	if backendConfigMap != "" {
+		if err = (&controllers.BackendConfigReconciler{
+			Client:            mgr.GetClient(),
+			Log:               ctrl.Log.WithName("controllers").WithName("BackendConfig"),
+			Recorder:          mgr.GetEventRecorderFor("unified-replication-operator"),
+			AdapterManager:    adapterManager,
+			TranslationEngine: translationEngine,
+			ConfigMap:         backendConfigRef,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "BackendConfig")
+			os.Exit(1)
+		}
+		setupLog.Info("Reading backend configuration", "configMap", backendConfigRef)
+	}
+
+	// Initialize controller engine. When capturing reconciles, only the engine sees the
+	// capturing registry so the reconciler keeps the concrete adapter types.
+	var engineRegistry adapters.Registry = adapterRegistry
+	var reconcileCapturer *controllers.ReconcileCapturer
+	if reconcileCaptureDir != "" {
+		engineRegistry = controllers.NewCapturingRegistry(adapterRegistry)
+		reconcileCapturer = controllers.NewReconcileCapturer(reconcileCaptureDir)
+		reconcileCapturer.MaxFiles = reconcileCaptureMaxFiles
+		setupLog.Info("Capturing reconciles for replay", "dir", reconcileCaptureDir, "maxFiles", reconcileCaptureMaxFiles)
+	}
+	engineConfig := pkg.DefaultControllerEngineConfig()
+	engineConfig.WritesPerSecond = writeBudget
+	engineConfig.WriteBurst = writeBurst
+	engineConfig.OperationDedupeWindow = operationDedupeWindow
+	engineConfig.MaxConcurrentBackendOperations = maxConcurrentBackendOperations
+	controllerEngine := pkg.NewControllerEngine(apiClient, discoveryEngine, translationEngine, engineRegistry, engineConfig)
+	controllerEngine.SetEventRecorder(mgr.GetEventRecorderFor("unified-replication-operator"))
 
 	// Initialize advanced features
 	stateMachine := controllers.NewStateMachine()
@@ -94,32 +428,119 @@ func main() {
 		MaxDelay:     5 * time.Minute,
 		Multiplier:   2.0,
 	})
-	circuitBreaker := controllers.NewCircuitBreaker(5, 2, 60*time.Second)
+	circuitBreakers := controllers.NewBackendCircuitBreakers(5, 2, 60*time.Second)
+	var failoverLimiter *controllers.FailoverLimiter
+	if maxConcurrentFailovers > 0 {
+		failoverLimiter = controllers.NewFailoverLimiter(maxConcurrentFailovers, failoverSlotTimeout)
+		setupLog.Info("Limiting concurrent failovers", "max", maxConcurrentFailovers, "slotTimeout", failoverSlotTimeout)
+	}
+
+	var fleetSweep *controllers.FleetSweep
+	if fleetSweepInterval > 0 {
+		fleetSweep = controllers.NewFleetSweep(mgr.GetClient(), fleetSweepInterval, namespaces, ctrl.Log.WithName("fleet-sweep"))
+	}
+
+	var backendSelectionWebhook *controllers.BackendSelectionWebhook
+	if backendSelectionWebhookURL != "" {
+		backendSelectionWebhook = controllers.NewBackendSelectionWebhook(backendSelectionWebhookURL, backendSelectionWebhookTimeout, backendSelectionWebhookFailOpen)
+		setupLog.Info("Using backend selection webhook", "url", backendSelectionWebhookURL, "failOpen", backendSelectionWebhookFailOpen)
+	}
+
+	var reconcileOutcomeWebhook *controllers.ReconcileOutcomeWebhook
+	if reconcileWebhookURL != "" {
+		reconcileOutcomeWebhook = controllers.NewReconcileOutcomeWebhook(reconcileWebhookURL, 0, webhookResults)
+		if err := mgr.Add(reconcileOutcomeWebhook); err != nil {
+			setupLog.Error(err, "unable to add reconcile outcome webhook")
+			os.Exit(1)
+		}
+		setupLog.Info("Posting reconcile outcomes", "url", reconcileWebhookURL, "results", webhookResults)
+	}
 
 	// Setup the UnifiedVolumeReplication controller
-	if err = (&controllers.UnifiedVolumeReplicationReconciler{
-		Client:                  mgr.GetClient(),
-		Log:                     ctrl.Log.WithName("controllers").WithName("UnifiedVolumeReplication"),
-		Scheme:                  mgr.GetScheme(),
-		Recorder:                mgr.GetEventRecorderFor("unified-replication-operator"),
-		AdapterRegistry:         adapterRegistry,
-		DiscoveryEngine:         discoveryEngine,
-		TranslationEngine:       translationEngine,
-		ControllerEngine:        controllerEngine,
-		StateMachine:            stateMachine,
-		RetryManager:            retryManager,
-		CircuitBreaker:          circuitBreaker,
-		MaxConcurrentReconciles: 3,
-		ReconcileTimeout:        5 * time.Minute,
-	}).SetupWithManager(mgr); err != nil {
+	uvrReconciler := &controllers.UnifiedVolumeReplicationReconciler{
+		Client:                        controllerEngine.BudgetedClient(apiClient),
+		Log:                           ctrl.Log.WithName("controllers").WithName("UnifiedVolumeReplication"),
+		Scheme:                        mgr.GetScheme(),
+		Recorder:                      mgr.GetEventRecorderFor("unified-replication-operator"),
+		AdapterRegistry:               adapterRegistry,
+		DiscoveryEngine:               discoveryEngine,
+		TranslationEngine:             translationEngine,
+		ControllerEngine:              controllerEngine,
+		StateMachine:                  stateMachine,
+		RetryManager:                  retryManager,
+		CircuitBreakers:               circuitBreakers,
+		BackendSelectionWebhook:       backendSelectionWebhook,
+		ReconcileOutcomeWebhook:       reconcileOutcomeWebhook,
+		AdapterManager:                adapterManager,
+		MaxConcurrentReconciles:       3,
+		ReconcileTimeout:              5 * time.Minute,
+		IsolateBackends:               isolateBackends,
+		Capturer:                      reconcileCapturer,
+		ReconcileOrder:                order,
+		SpecCoalesceWindow:            specCoalesceWindow,
+		MissingSourcePVCPolicy:        sourcePVCPolicy,
+		RequireFailoverApproval:       requireFailoverApproval,
+		DeletionSyncPolicy:            syncPolicy,
+		DeletionSyncTimeout:           deletionSyncTimeout,
+		WriteDrainTimeout:             writeDrainTimeout,
+		UnknownHealthPolicy:           healthPolicy,
+		SimulateDegradationNamespaces: splitNamespaces(simulateDegradationNamespaces),
+		RPOComplianceThresholds:       complianceThresholds,
+		DestinationFreeSpaceThreshold: freeSpaceThreshold.Value(),
+		WatchNamespaces:               namespaces,
+		APICallLogThreshold:           apiCallLogThreshold,
+		FailoverLimiter:               failoverLimiter,
+		FleetSweep:                    fleetSweep,
+		StepGracePeriods:              stepGracePeriods,
+	}
+	if err = uvrReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "UnifiedVolumeReplication")
 		os.Exit(1)
 	}
+	if err = (&controllers.ApplicationReplicationReconciler{
+		Client:           controllerEngine.BudgetedClient(mgr.GetClient()),
+		Log:              ctrl.Log.WithName("controllers").WithName("ApplicationReplication"),
+		Scheme:           mgr.GetScheme(),
+		Recorder:         mgr.GetEventRecorderFor("unified-replication-operator"),
+		StepGracePeriods: stepGracePeriods,
+		PromotionGate:    uvrReconciler.PromotionAllowed,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ApplicationReplication")
+		os.Exit(1)
+	}
+	if err = (&controllers.ReplicationTemplateReconciler{
+		Client:   controllerEngine.BudgetedClient(mgr.GetClient()),
+		Log:      ctrl.Log.WithName("controllers").WithName("ReplicationTemplate"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("unified-replication-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ReplicationTemplate")
+		os.Exit(1)
+	}
+	if err = (&controllers.NamespacePromotionReconciler{
+		Client:   controllerEngine.BudgetedClient(mgr.GetClient()),
+		Log:      ctrl.Log.WithName("controllers").WithName("NamespacePromotion"),
+		Recorder: mgr.GetEventRecorderFor("unified-replication-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NamespacePromotion")
+		os.Exit(1)
+	}
 	//+kubebuilder:scaffold:builder
 
-	setupLog.Info("starting manager")
+	setupLog.Info("starting manager", "version", version)
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// splitNamespaces parses a comma-separated list of namespaces, ignoring empty entries
+func splitNamespaces(value string) []string {
+	var namespaces []string
+	for _, namespace := range strings.Split(value, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
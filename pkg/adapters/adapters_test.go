@@ -18,10 +18,12 @@ package adapters
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -129,6 +131,16 @@ func TestAdapterTypes(t *testing.T) {
 		assert.Contains(t, err.Error(), "validate")
 	})
 
+	t.Run("DegradedReason for status errors", func(t *testing.T) {
+		connErr := NewAdapterError(ErrorTypeConnection, translation.BackendCeph, "status", "test-resource", "connection refused")
+		timeoutErr := NewAdapterError(ErrorTypeTimeout, translation.BackendCeph, "status", "test-resource", "timed out")
+		opErr := NewAdapterError(ErrorTypeOperation, translation.BackendCeph, "status", "test-resource", "failed")
+
+		assert.Equal(t, DegradedReasonBackendUnreachable, DegradedReasonForError(connErr))
+		assert.Equal(t, DegradedReasonBackendUnreachable, DegradedReasonForError(fmt.Errorf("wrapped: %w", timeoutErr)))
+		assert.Equal(t, DegradedReasonUnknown, DegradedReasonForError(opErr))
+	})
+
 	t.Run("AdapterMetrics calculations", func(t *testing.T) {
 		metrics := AdapterMetrics{
 			TotalOperations: 100,
@@ -546,6 +558,104 @@ func TestAdapterManager(t *testing.T) {
 	})
 }
 
+func TestAdapterManager_HotSwap(t *testing.T) {
+	client := fake.NewClientBuilder().WithScheme(newCephTestScheme()).Build()
+	translator := translation.NewEngine()
+	ctx := context.Background()
+
+	uvr := createTestUVR("test-uvr", "default")
+	uvr.Spec.Extensions = &replicationv1alpha1.Extensions{
+		Ceph: &replicationv1alpha1.CephExtensions{},
+	}
+
+	t.Run("ReusedWithoutChange", func(t *testing.T) {
+		registry := NewRegistry()
+		_ = registry.RegisterFactory(NewMockAdapterFactory(translation.BackendCeph, DefaultMockConfig()))
+		manager := NewAdapterManager(registry, DefaultManagerConfig())
+
+		adapter, release, err := manager.AcquireAdapter(ctx, uvr, translation.BackendCeph, client, translator)
+		require.NoError(t, err)
+		release()
+
+		again, release, err := manager.AcquireAdapter(ctx, uvr, translation.BackendCeph, client, translator)
+		require.NoError(t, err)
+		release()
+		assert.Same(t, adapter, again)
+	})
+
+	t.Run("SwapWaitsForInflightOperations", func(t *testing.T) {
+		registry := NewRegistry()
+		_ = registry.RegisterFactory(NewMockAdapterFactory(translation.BackendCeph, DefaultMockConfig()))
+		manager := NewAdapterManager(registry, DefaultManagerConfig())
+
+		var swapped []translation.Backend
+		manager.SetSwapHandler(func(_ *replicationv1alpha1.UnifiedVolumeReplication, backend translation.Backend) {
+			swapped = append(swapped, backend)
+		})
+
+		old, releaseOld, err := manager.AcquireAdapter(ctx, uvr, translation.BackendCeph, client, translator)
+		require.NoError(t, err)
+
+		config := DefaultAdapterConfig(translation.BackendCeph)
+		config.CustomSettings["credentialsSecret"] = "ceph-creds-v2"
+		manager.SetBackendConfig(translation.BackendCeph, config)
+
+		replacement, releaseNew, err := manager.AcquireAdapter(ctx, uvr, translation.BackendCeph, client, translator)
+		require.NoError(t, err)
+		defer releaseNew()
+
+		assert.NotSame(t, old, replacement)
+		assert.Equal(t, []translation.Backend{translation.BackendCeph}, swapped)
+		assert.True(t, old.IsHealthy(), "the old adapter stays usable while an operation holds it")
+
+		releaseOld()
+		assert.False(t, old.IsHealthy(), "the old adapter is cleaned up after its last operation")
+		assert.True(t, replacement.IsHealthy())
+	})
+
+	t.Run("SwapOnModeChange", func(t *testing.T) {
+		registry := NewRegistry()
+		_ = registry.RegisterFactory(NewMockAdapterFactory(translation.BackendCeph, DefaultMockConfig()))
+		manager := NewAdapterManager(registry, DefaultManagerConfig())
+
+		mock, err := manager.GetOrCreateAdapter(ctx, uvr, client, translator)
+		require.NoError(t, err)
+		_, isMock := mock.(*MockAdapter)
+		require.True(t, isMock)
+
+		// The operator switches Ceph from the mock to the real adapter
+		_ = registry.UnregisterFactory(translation.BackendCeph)
+		_ = registry.RegisterFactory(NewCephAdapterFactory())
+
+		cephAdapter, err := manager.GetOrCreateAdapter(ctx, uvr, client, translator)
+		require.NoError(t, err)
+		_, isCeph := cephAdapter.(*CephAdapter)
+		assert.True(t, isCeph)
+		assert.False(t, mock.IsHealthy())
+	})
+
+	t.Run("InvalidConfigKeepsCachedAdapter", func(t *testing.T) {
+		registry := NewRegistry()
+		_ = registry.RegisterFactory(NewCephAdapterFactory())
+		manager := NewAdapterManager(registry, DefaultManagerConfig())
+
+		adapter, err := manager.GetOrCreateAdapter(ctx, uvr, client, translator)
+		require.NoError(t, err)
+
+		config := DefaultAdapterConfig(translation.BackendCeph)
+		config.StatusCacheTTL = time.Millisecond
+		manager.SetBackendConfig(translation.BackendCeph, config)
+
+		_, err = manager.GetOrCreateAdapter(ctx, uvr, client, translator)
+		assert.Error(t, err)
+
+		cached, ok := manager.GetAdapter(uvr)
+		require.True(t, ok)
+		assert.Same(t, adapter, cached)
+		assert.True(t, adapter.IsHealthy())
+	})
+}
+
 func TestGlobalRegistry(t *testing.T) {
 	t.Run("GetGlobalRegistry", func(t *testing.T) {
 		registry1 := GetGlobalRegistry()
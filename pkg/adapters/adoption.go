@@ -0,0 +1,113 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+const (
+	// AdoptAnnotation names an existing backend resource the UVR takes over instead of
+	// creating its own. Once adopted, the name is kept in status.adoptedResource.
+	AdoptAnnotation = "replication.storage.io/adopt"
+
+	// AdoptedByAnnotation records which UVR adopted a backend resource
+	AdoptedByAnnotation = "replication.storage.io/adopted-by"
+
+	// AdoptConflictPolicyAnnotation selects what adopting a backend resource whose settings
+	// differ from the spec does; see AdoptConflictPolicy. Fail when unset.
+	AdoptConflictPolicyAnnotation = "replication.storage.io/adopt-conflict-policy"
+)
+
+// AdoptConflictPolicy selects how a mismatch between an adopted backend resource and the UVR
+// spec is resolved. A resource adopted by another UVR is never taken over, whatever the policy.
+type AdoptConflictPolicy string
+
+const (
+	// AdoptConflictPolicyPreferSpec adopts the resource and re-applies the spec to it
+	AdoptConflictPolicyPreferSpec AdoptConflictPolicy = "PreferSpec"
+	// AdoptConflictPolicyPreferExisting adopts the resource and keeps its settings; the UVR
+	// reports the drift instead of applying its spec until the two match
+	AdoptConflictPolicyPreferExisting AdoptConflictPolicy = "PreferExisting"
+	// AdoptConflictPolicyFail refuses to adopt the resource
+	AdoptConflictPolicyFail AdoptConflictPolicy = "Fail"
+)
+
+// AdoptConflictPolicyFor returns the adopt conflict policy the UVR asks for
+func AdoptConflictPolicyFor(uvr *replicationv1alpha1.UnifiedVolumeReplication) (AdoptConflictPolicy, error) {
+	value, ok := uvr.Annotations[AdoptConflictPolicyAnnotation]
+	if !ok {
+		return AdoptConflictPolicyFail, nil
+	}
+	switch policy := AdoptConflictPolicy(value); policy {
+	case AdoptConflictPolicyPreferSpec, AdoptConflictPolicyPreferExisting, AdoptConflictPolicyFail:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid %s annotation %q: must be %s, %s or %s", AdoptConflictPolicyAnnotation, value,
+			AdoptConflictPolicyPreferSpec, AdoptConflictPolicyPreferExisting, AdoptConflictPolicyFail)
+	}
+}
+
+// adoptedResourceName returns the backend resource the UVR adopts, or "" when it manages
+// a resource of its own. The adopted name is kept in status, so removing the annotation
+// does not point the UVR at a new resource.
+func adoptedResourceName(uvr *replicationv1alpha1.UnifiedVolumeReplication) string {
+	if name, ok := uvr.Annotations[AdoptAnnotation]; ok {
+		return name
+	}
+	return uvr.Status.AdoptedResource
+}
+
+// adoptionOwner identifies the UVR that adopted a backend resource
+func adoptionOwner(uvr *replicationv1alpha1.UnifiedVolumeReplication) string {
+	return fmt.Sprintf("%s/%s", uvr.Namespace, uvr.Name)
+}
+
+// newAdoptionConflictError reports the settings of an existing backend resource that do
+// not match the UVR adopting it
+func newAdoptionConflictError(backend translation.Backend, name string, uvr *replicationv1alpha1.UnifiedVolumeReplication, conflicts []PolicyDrift) error {
+	descriptions := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		descriptions = append(descriptions, conflict.String())
+	}
+	return NewAdapterError(ErrorTypeValidation, backend, "adopt", uvr.Name,
+		fmt.Sprintf("%s conflicts with the spec: %s", name, strings.Join(descriptions, ", ")))
+}
+
+// newImmutableAdoptionConflictError reports settings of an existing backend resource that
+// the PreferSpec policy cannot re-apply, because changing them recreates the resource
+func newImmutableAdoptionConflictError(backend translation.Backend, name string, uvr *replicationv1alpha1.UnifiedVolumeReplication, conflicts []PolicyDrift) error {
+	descriptions := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		descriptions = append(descriptions, conflict.String())
+	}
+	return NewAdapterError(ErrorTypeValidation, backend, "adopt", uvr.Name,
+		fmt.Sprintf("%s differs from the spec in settings that cannot change without recreating it, which %s does not do: %s",
+			name, AdoptConflictPolicyPreferSpec, strings.Join(descriptions, ", ")))
+}
+
+// IsAdoptionConflictError returns true when err reports that the backend resource named for
+// adoption does not match the UVR spec or is managed by another UVR
+func IsAdoptionConflictError(err error) bool {
+	var adapterErr *AdapterError
+	return errors.As(err, &adapterErr) && adapterErr.Operation == "adopt" && adapterErr.Type == ErrorTypeValidation
+}
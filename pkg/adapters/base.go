@@ -22,7 +22,9 @@ import (
 	"sync"
 	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -44,6 +46,15 @@ type BaseAdapter struct {
 	// Adapter info
 	info         AdapterInfo
 	capabilities AdapterCapabilities
+
+	// recorder records events on the UVRs the adapter operates on; nil records none
+	recorder record.EventRecorder
+}
+
+// EventRecorderSetter is implemented by adapters that record events on the UVRs they
+// operate on, such as the start and outcome of state transitions
+type EventRecorderSetter interface {
+	SetEventRecorder(recorder record.EventRecorder)
 }
 
 // NewBaseAdapter creates a new base adapter
@@ -315,6 +326,24 @@ func (ba *BaseAdapter) SetInfo(info AdapterInfo) {
 	ba.info = info
 }
 
+// SetEventRecorder sets the recorder of events on the UVRs the adapter operates on
+func (ba *BaseAdapter) SetEventRecorder(recorder record.EventRecorder) {
+	ba.mu.Lock()
+	defer ba.mu.Unlock()
+	ba.recorder = recorder
+}
+
+// recordEventf records an event on uvr when the adapter has an event recorder
+func (ba *BaseAdapter) recordEventf(uvr *replicationv1alpha1.UnifiedVolumeReplication, eventType, reason, messageFmt string, args ...interface{}) {
+	ba.mu.RLock()
+	recorder := ba.recorder
+	ba.mu.RUnlock()
+	if recorder == nil || uvr == nil {
+		return
+	}
+	recorder.Eventf(uvr, eventType, reason, messageFmt, args...)
+}
+
 // WithRetry executes a function with retry logic
 func (ba *BaseAdapter) WithRetry(ctx context.Context, operation string, fn func() error) error {
 	err := wait.ExponentialBackoff(wait.Backoff{
@@ -340,6 +369,12 @@ func (ba *BaseAdapter) WithRetry(ctx context.Context, operation string, fn func(
 			return false, err // Non-retryable error, stop with error
 		}
 
+		// Retrying a throttled request only adds to the API server's load; the caller
+		// backs off for the server's Retry-After instead
+		if apierrors.IsTooManyRequests(err) {
+			return false, err
+		}
+
 		return false, nil // Retryable error, continue trying
 	})
 
@@ -412,6 +447,111 @@ func (ba *BaseAdapter) validateBackendConfig(uvr *replicationv1alpha1.UnifiedVol
 	return nil
 }
 
+// resolvePrimaryIdentity determines which endpoint holds the primary copy given the
+// unified state of the local (source endpoint) volume. In-flight promotions and
+// demotions report the endpoint that will own the primary once they complete.
+// Empty values are returned when the state does not identify a primary.
+func resolvePrimaryIdentity(uvr *replicationv1alpha1.UnifiedVolumeReplication, unifiedState string) (string, string) {
+	switch unifiedState {
+	case string(replicationv1alpha1.ReplicationStateSource), string(replicationv1alpha1.ReplicationStatePromoting):
+		return uvr.Spec.SourceEndpoint.Cluster, uvr.Spec.SourceEndpoint.Region
+	case string(replicationv1alpha1.ReplicationStateReplica), string(replicationv1alpha1.ReplicationStateDemoting):
+		return uvr.Spec.DestinationEndpoint.Cluster, uvr.Spec.DestinationEndpoint.Region
+	default:
+		return "", ""
+	}
+}
+
+// QuiescedSyncTimeAnnotation is set on the backend replication resource once a sync finished
+// while the application was quiesced, by a completed write drain or by application quiesce
+// hooks. Its value is the last sync time, in RFC3339, of that sync.
+const QuiescedSyncTimeAnnotation = "replication.unified.io/quiesced-sync-time"
+
+// DrainRequestedAnnotation is set on the backend replication resource of a source that is
+// about to be demoted. Its value is the time, in RFC3339 with nanoseconds, the drain was
+// requested: write fencing hooks stop application writes and flush the journal when it
+// appears, and the drain is complete once a sync finishes after that time.
+const DrainRequestedAnnotation = "replication.unified.io/drain-requested"
+
+// DeactivatedAnnotation is set on the backend replication resource of a deactivated UVR. Its
+// value is the time, in RFC3339, the replication was deactivated.
+const DeactivatedAnnotation = "replication.unified.io/deactivated"
+
+// PausedAnnotation is set on the backend replication resource of a paused UVR whose backend
+// has no pause of its own. Its value is the time, in RFC3339, the replication was paused.
+const PausedAnnotation = "replication.unified.io/paused"
+
+// drainCompleted reports whether a drain was requested and a sync has finished since, so
+// every write accepted before the fence has reached the replica. The request is compared at
+// full precision: backends report sync times truncated to the second, so a sync in the same
+// second as the request does not count.
+func drainCompleted(annotations map[string]string, lastSyncTime *time.Time) bool {
+	requested, err := time.Parse(time.RFC3339Nano, annotations[DrainRequestedAnnotation])
+	if err != nil || lastSyncTime == nil {
+		return false
+	}
+	return lastSyncTime.After(requested)
+}
+
+// recordQuiescedSync sets QuiescedSyncTimeAnnotation to the last sync time after a completed
+// drain: writes were fenced during that sync, so it is an application-consistent recovery
+// point. It reports whether the annotation changed.
+func recordQuiescedSync(annotations map[string]string, lastSyncTime *time.Time) bool {
+	if !drainCompleted(annotations, lastSyncTime) {
+		return false
+	}
+	value := lastSyncTime.UTC().Format(time.RFC3339)
+	if annotations[QuiescedSyncTimeAnnotation] == value {
+		return false
+	}
+	annotations[QuiescedSyncTimeAnnotation] = value
+	return true
+}
+
+// GetConsistencyState reports ConsistencyLevelUnknown; adapters that read the backend's sync
+// points override it
+func (ba *BaseAdapter) GetConsistencyState(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (ConsistencyLevel, error) {
+	return ConsistencyLevelUnknown, nil
+}
+
+// consistencyLevelOf returns the consistency level reported in a replication status
+func consistencyLevelOf(status *ReplicationStatus, err error) (ConsistencyLevel, error) {
+	if err != nil {
+		return ConsistencyLevelUnknown, err
+	}
+	if status == nil || status.ConsistencyLevel == "" {
+		return ConsistencyLevelUnknown, nil
+	}
+	return status.ConsistencyLevel, nil
+}
+
+// resolveConsistencyLevel reports the latest recovery point as application-consistent when
+// the quiesce annotation of the backend resource names it, and crash-consistent otherwise
+func resolveConsistencyLevel(annotations map[string]string, lastSyncTime *time.Time) ConsistencyLevel {
+	if lastSyncTime == nil {
+		return ConsistencyLevelUnknown
+	}
+	quiesced, err := time.Parse(time.RFC3339, annotations[QuiescedSyncTimeAnnotation])
+	if err != nil || !quiesced.Truncate(time.Second).Equal(lastSyncTime.Truncate(time.Second)) {
+		return ConsistencyLevelCrash
+	}
+	return ConsistencyLevelApplication
+}
+
+// resolveReplicationDirection reports Forward while the source endpoint holds the primary
+// copy and Reverse once the destination endpoint has taken over. It follows the same
+// endpoint resolution as resolvePrimaryIdentity.
+func resolveReplicationDirection(unifiedState string) ReplicationDirection {
+	switch unifiedState {
+	case string(replicationv1alpha1.ReplicationStateSource), string(replicationv1alpha1.ReplicationStatePromoting):
+		return ReplicationDirectionForward
+	case string(replicationv1alpha1.ReplicationStateReplica), string(replicationv1alpha1.ReplicationStateDemoting):
+		return ReplicationDirectionReverse
+	default:
+		return ""
+	}
+}
+
 // NotImplementedError returns an error for operations not implemented by the specific adapter
 func (ba *BaseAdapter) NotImplementedError(operation string) error {
 	return NewAdapterError(ErrorTypeOperation, ba.backend, operation, "",
@@ -439,10 +579,42 @@ func (ba *BaseAdapter) TimeoutError(operation string, timeout time.Duration) err
 		fmt.Sprintf("operation timed out after %s", timeout))
 }
 
-// updateMetrics updates internal metrics (stub implementation)
-func (ba *BaseAdapter) updateMetrics(operation string, success bool, startTime time.Time) {
-	// Stub implementation - metrics tracking can be added later if needed
-	// This prevents errors but doesn't actually track metrics
+// updateMetrics records the outcome of an operation in the shared operation metrics, and
+// when it succeeded, the time of the success on the replication. A successful delete
+// drops the replication's success times.
+func (ba *BaseAdapter) updateMetrics(uvr *replicationv1alpha1.UnifiedVolumeReplication, operation string, success bool, startTime time.Time) {
+	operationMetrics.Record(ba.backend, operation, success, time.Since(startTime))
+	if !success || uvr == nil {
+		return
+	}
+	if operation == "delete" {
+		operationMetrics.ForgetReplication(client.ObjectKeyFromObject(uvr))
+		return
+	}
+	operationMetrics.RecordSuccess(client.ObjectKeyFromObject(uvr), operation, time.Now())
+}
+
+// recordStateChange records the transition operation carried out by an update that changed
+// the replication's state, so promotions and demotions applied through the spec are tracked
+// like explicit ones
+func (ba *BaseAdapter) recordStateChange(uvr *replicationv1alpha1.UnifiedVolumeReplication, startTime time.Time) {
+	if operation := transitionOperation(uvr.Spec.ReplicationState); operation != "" {
+		ba.updateMetrics(uvr, operation, true, startTime)
+	}
+}
+
+// transitionOperation names the operation that moves a replication into the given state,
+// or returns "" for states no transition operation leads to
+func transitionOperation(state replicationv1alpha1.ReplicationState) string {
+	switch state {
+	case replicationv1alpha1.ReplicationStateSource, replicationv1alpha1.ReplicationStatePromoting:
+		return OperationPromote
+	case replicationv1alpha1.ReplicationStateReplica, replicationv1alpha1.ReplicationStateDemoting:
+		return OperationDemote
+	case replicationv1alpha1.ReplicationStateSyncing:
+		return OperationResync
+	}
+	return ""
 }
 
 // GetMetrics returns adapter metrics (stub implementation)
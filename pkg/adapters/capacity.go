@@ -0,0 +1,254 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+// CapacityInfo describes the capacity of a destination storage class
+type CapacityInfo struct {
+	StorageClass string `json:"storage_class"`
+	// AvailableBytes is the largest capacity published for a single topology segment
+	AvailableBytes int64 `json:"available_bytes"`
+	// ReservedBytes is held by replications that have not completed their initial sync
+	ReservedBytes int64 `json:"reserved_bytes"`
+}
+
+// FreeBytes returns the capacity that can still be reserved
+func (ci *CapacityInfo) FreeBytes() int64 {
+	return ci.AvailableBytes - ci.ReservedBytes
+}
+
+// capacityLedger tracks destination reservations per storage class. It is shared by all
+// adapters so concurrent replications targeting the same storage class see each other.
+type capacityLedger struct {
+	mu           sync.Mutex
+	reservations map[string]map[string]int64 // storage class -> UVR -> bytes
+}
+
+var destinationReservations = &capacityLedger{reservations: make(map[string]map[string]int64)}
+
+// reservedLocked returns the bytes reserved on a storage class, counting each UVR once
+// whether its reservation is held in memory, persisted in its status, or both; callers
+// must hold the lock
+func (cl *capacityLedger) reservedLocked(storageClass string, persisted map[string]int64) int64 {
+	var total int64
+	for owner, bytes := range persisted {
+		if _, ok := cl.reservations[storageClass][owner]; !ok {
+			total += bytes
+		}
+	}
+	for _, bytes := range cl.reservations[storageClass] {
+		total += bytes
+	}
+	return total
+}
+
+// record stores the reservation held by a UVR
+func (cl *capacityLedger) record(storageClass, owner string, bytes int64) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	cl.recordLocked(storageClass, owner, bytes)
+}
+
+// recordLocked stores the reservation held by a UVR; callers must hold the lock
+func (cl *capacityLedger) recordLocked(storageClass, owner string, bytes int64) {
+	if cl.reservations[storageClass] == nil {
+		cl.reservations[storageClass] = make(map[string]int64)
+	}
+	cl.reservations[storageClass][owner] = bytes
+}
+
+// lookup returns the reservation held by a UVR, if any
+func (cl *capacityLedger) lookup(storageClass, owner string) (int64, bool) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	bytes, ok := cl.reservations[storageClass][owner]
+	return bytes, ok
+}
+
+// release drops the reservation held by a UVR
+func (cl *capacityLedger) release(storageClass, owner string) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	delete(cl.reservations[storageClass], owner)
+	if len(cl.reservations[storageClass]) == 0 {
+		delete(cl.reservations, storageClass)
+	}
+}
+
+// reserveCapacityEnabled returns true when the UVR asks for destination capacity to be reserved
+func reserveCapacityEnabled(uvr *replicationv1alpha1.UnifiedVolumeReplication) bool {
+	reserve := uvr.Spec.VolumeMapping.Destination.ReserveCapacity
+	return reserve != nil && *reserve
+}
+
+// IsInsufficientCapacityError returns true when err reports that destination capacity could not be reserved
+func IsInsufficientCapacityError(err error) bool {
+	var adapterErr *AdapterError
+	return errors.As(err, &adapterErr) && adapterErr.Operation == "reserve" && adapterErr.Type == ErrorTypeResource
+}
+
+// GetCapacityInfo reports the capacity the CSI driver publishes for a storage class, less the
+// capacity already reserved. Nil is returned when the driver does not publish capacity.
+func (ba *BaseAdapter) GetCapacityInfo(ctx context.Context, storageClass string) (*CapacityInfo, error) {
+	capacities := &storagev1.CSIStorageCapacityList{}
+	if err := ba.client.List(ctx, capacities); err != nil {
+		return nil, NewAdapterErrorWithCause(ErrorTypeConnection, ba.backend, "capacity", storageClass,
+			"failed to list storage capacity", err)
+	}
+
+	var available *resource.Quantity
+	for i := range capacities.Items {
+		item := &capacities.Items[i]
+		if item.StorageClassName != storageClass || item.Capacity == nil {
+			continue
+		}
+		// A volume is placed in a single segment, so the largest segment bounds what fits
+		if available == nil || item.Capacity.Cmp(*available) > 0 {
+			available = item.Capacity
+		}
+	}
+	if available == nil {
+		return nil, nil
+	}
+
+	persisted, err := ba.persistedReservations(ctx, storageClass)
+	if err != nil {
+		return nil, err
+	}
+
+	destinationReservations.mu.Lock()
+	defer destinationReservations.mu.Unlock()
+	return &CapacityInfo{
+		StorageClass:   storageClass,
+		AvailableBytes: available.Value(),
+		ReservedBytes:  destinationReservations.reservedLocked(storageClass, persisted),
+	}, nil
+}
+
+// persistedReservations returns the reservations recorded in UVR status on a storage class,
+// keyed by UVR, so reservations made before an operator restart are still counted
+func (ba *BaseAdapter) persistedReservations(ctx context.Context, storageClass string) (map[string]int64, error) {
+	uvrs := &replicationv1alpha1.UnifiedVolumeReplicationList{}
+	if err := ba.client.List(ctx, uvrs); err != nil {
+		return nil, NewAdapterErrorWithCause(ErrorTypeConnection, ba.backend, "capacity", storageClass,
+			"failed to list capacity reservations", err)
+	}
+
+	persisted := make(map[string]int64)
+	for i := range uvrs.Items {
+		reservation := uvrs.Items[i].Status.CapacityReservation
+		if reservation != nil && reservation.StorageClass == storageClass {
+			persisted[destinationVolumeOwner(&uvrs.Items[i])] = reservation.Bytes
+		}
+	}
+	return persisted, nil
+}
+
+// ReservedCapacity returns the bytes reserved on the destination for this UVR, falling back
+// to the reservation persisted in its status
+func (ba *BaseAdapter) ReservedCapacity(uvr *replicationv1alpha1.UnifiedVolumeReplication) (int64, bool) {
+	storageClass := uvr.Spec.DestinationEndpoint.StorageClass
+	if bytes, ok := destinationReservations.lookup(storageClass, destinationVolumeOwner(uvr)); ok {
+		return bytes, true
+	}
+	if reservation := uvr.Status.CapacityReservation; reservation != nil && reservation.StorageClass == storageClass {
+		return reservation.Bytes, true
+	}
+	return 0, false
+}
+
+// ReleaseCapacity drops the destination reservation held by this UVR. The caller clears the
+// reservation persisted in its status.
+func (ba *BaseAdapter) ReleaseCapacity(uvr *replicationv1alpha1.UnifiedVolumeReplication) {
+	destinationReservations.release(uvr.Spec.DestinationEndpoint.StorageClass, destinationVolumeOwner(uvr))
+}
+
+// reserveDestinationCapacity reserves the source volume size on the destination storage
+// class when requested. Reservation is skipped when the driver does not publish capacity.
+func (ba *BaseAdapter) reserveDestinationCapacity(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	if !reserveCapacityEnabled(uvr) {
+		return nil
+	}
+
+	logger := log.FromContext(ctx).WithValues("uvr", uvr.Name, "backend", ba.backend)
+	storageClass := uvr.Spec.DestinationEndpoint.StorageClass
+	owner := destinationVolumeOwner(uvr)
+
+	if bytes, ok := ba.ReservedCapacity(uvr); ok {
+		// Re-adopt a reservation persisted before a restart
+		destinationReservations.record(storageClass, owner, bytes)
+		return nil
+	}
+
+	// A bound destination volume already takes its capacity out of what the driver
+	// publishes, so reserving on top of it would count the space twice
+	bound, err := ba.DestinationVolumeBound(ctx, uvr)
+	if err != nil {
+		return err
+	}
+	if bound {
+		logger.V(1).Info("Destination volume is already bound, skipping reservation", "storageClass", storageClass)
+		return nil
+	}
+
+	_, size, err := ba.getSourceVolume(ctx, uvr, "reserve")
+	if err != nil {
+		return err
+	}
+
+	info, err := ba.GetCapacityInfo(ctx, storageClass)
+	if err != nil {
+		return err
+	}
+	if info == nil {
+		logger.Info("Destination storage class does not publish capacity, skipping reservation", "storageClass", storageClass)
+		return nil
+	}
+
+	persisted, err := ba.persistedReservations(ctx, storageClass)
+	if err != nil {
+		return err
+	}
+
+	// Re-check under the lock so concurrent reservations cannot both fit into the same space
+	destinationReservations.mu.Lock()
+	defer destinationReservations.mu.Unlock()
+
+	free := info.AvailableBytes - destinationReservations.reservedLocked(storageClass, persisted)
+	if free < size.Value() {
+		return NewAdapterError(ErrorTypeResource, ba.backend, "reserve", uvr.Name,
+			fmt.Sprintf("insufficient capacity on storage class %s: need %s, %s free",
+				storageClass, size.String(), resource.NewQuantity(free, resource.BinarySI).String()))
+	}
+
+	destinationReservations.recordLocked(storageClass, owner, size.Value())
+
+	logger.Info("Reserved destination capacity", "storageClass", storageClass, "size", size.String())
+	return nil
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+func newCapacityTestUVR(t *testing.T, name string) *replicationv1alpha1.UnifiedVolumeReplication {
+	uvr := createTestUVRForPowerStore(name, "default")
+	reserve := true
+	uvr.Spec.VolumeMapping.Destination.ReserveCapacity = &reserve
+	t.Cleanup(func() {
+		destinationReservations.release(uvr.Spec.DestinationEndpoint.StorageClass, destinationVolumeOwner(uvr))
+	})
+	return uvr
+}
+
+func publishCapacity(t *testing.T, c client.Client, size string) {
+	capacity := resource.MustParse(size)
+	require.NoError(t, c.Create(context.Background(), &storagev1.CSIStorageCapacity{
+		ObjectMeta:       metav1.ObjectMeta{Name: "powerstore-block-capacity", Namespace: "default"},
+		StorageClassName: "powerstore-block",
+		Capacity:         &capacity,
+	}))
+}
+
+func replicationGroupExists(t *testing.T, c client.Client, name string) bool {
+	rg := &unstructured.Unstructured{}
+	rg.SetGroupVersionKind(DellCSIReplicationGroupGVK)
+	err := c.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, rg)
+	if errors.IsNotFound(err) {
+		return false
+	}
+	require.NoError(t, err)
+	return true
+}
+
+func TestPowerStoreAdapter_CapacityReservation(t *testing.T) {
+	ctx := context.Background()
+	c := newProvisioningTestClient(t)
+	publishCapacity(t, c, "15Gi")
+
+	adapter, err := NewPowerStoreAdapter(c, translation.NewEngine())
+	require.NoError(t, err)
+
+	first := newCapacityTestUVR(t, "reserve-first")
+	require.NoError(t, adapter.EnsureReplication(ctx, first))
+
+	reserved, ok := adapter.ReservedCapacity(first)
+	require.True(t, ok)
+	assert.Equal(t, int64(10<<30), reserved)
+
+	info, err := adapter.GetCapacityInfo(ctx, "powerstore-block")
+	require.NoError(t, err)
+	require.NotNil(t, info)
+	assert.Equal(t, int64(5<<30), info.FreeBytes())
+
+	// A concurrent replication cannot claim the space already reserved
+	second := newCapacityTestUVR(t, "reserve-second")
+	autoCreate := true
+	second.Spec.VolumeMapping.Destination.AutoCreate = &autoCreate
+	second.Spec.VolumeMapping.Destination.VolumeHandle = "second-dest-volume"
+
+	err = adapter.EnsureReplication(ctx, second)
+	require.Error(t, err)
+	assert.True(t, IsInsufficientCapacityError(err))
+	assert.False(t, replicationGroupExists(t, c, "reserve-second"))
+	_, ok = adapter.ReservedCapacity(second)
+	assert.False(t, ok)
+	err = c.Get(ctx, types.NamespacedName{Name: "second-dest-volume", Namespace: "default"}, &corev1.PersistentVolumeClaim{})
+	assert.True(t, errors.IsNotFound(err), "destination volume must not be provisioned without a reservation")
+
+	// Deleting the first replication frees the reservation for the second
+	require.NoError(t, adapter.DeleteReplication(ctx, first))
+	_, ok = adapter.ReservedCapacity(first)
+	assert.False(t, ok)
+
+	require.NoError(t, adapter.EnsureReplication(ctx, second))
+	assert.True(t, replicationGroupExists(t, c, "reserve-second"))
+}
+
+func TestPowerStoreAdapter_CapacityReservationSkippedWithoutPublishedCapacity(t *testing.T) {
+	ctx := context.Background()
+	c := newProvisioningTestClient(t)
+
+	adapter, err := NewPowerStoreAdapter(c, translation.NewEngine())
+	require.NoError(t, err)
+
+	uvr := newCapacityTestUVR(t, "reserve-unsupported")
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+
+	_, ok := adapter.ReservedCapacity(uvr)
+	assert.False(t, ok)
+	assert.True(t, replicationGroupExists(t, c, "reserve-unsupported"))
+}
+
+func TestPowerStoreAdapter_CapacityReservationPersisted(t *testing.T) {
+	ctx := context.Background()
+	c := newProvisioningTestClient(t)
+	publishCapacity(t, c, "15Gi")
+
+	// A reservation recorded in status before a restart, no longer in memory
+	held := newCapacityTestUVR(t, "reserve-persisted")
+	require.NoError(t, c.Create(ctx, held))
+	held.Status.CapacityReservation = &replicationv1alpha1.CapacityReservation{
+		StorageClass: "powerstore-block",
+		Bytes:        10 << 30,
+		Time:         metav1.Now(),
+	}
+	require.NoError(t, c.Update(ctx, held))
+
+	adapter, err := NewPowerStoreAdapter(c, translation.NewEngine())
+	require.NoError(t, err)
+
+	reserved, ok := adapter.ReservedCapacity(held)
+	require.True(t, ok)
+	assert.Equal(t, int64(10<<30), reserved)
+
+	info, err := adapter.GetCapacityInfo(ctx, "powerstore-block")
+	require.NoError(t, err)
+	require.NotNil(t, info)
+	assert.Equal(t, int64(5<<30), info.FreeBytes())
+
+	// Re-adopting the persisted reservation does not count it twice
+	require.NoError(t, adapter.EnsureReplication(ctx, held))
+	info, err = adapter.GetCapacityInfo(ctx, "powerstore-block")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5<<30), info.FreeBytes())
+
+	other := newCapacityTestUVR(t, "reserve-after-restart")
+	err = adapter.EnsureReplication(ctx, other)
+	require.Error(t, err)
+	assert.True(t, IsInsufficientCapacityError(err))
+}
+
+func TestPowerStoreAdapter_CapacityReservationSkippedForBoundDestination(t *testing.T) {
+	ctx := context.Background()
+	c := newProvisioningTestClient(t)
+	publishCapacity(t, c, "15Gi")
+
+	storageClass := "powerstore-block"
+	dest := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "dest-volume", Namespace: "default"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClass,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+			},
+		},
+	}
+	require.NoError(t, c.Create(ctx, dest))
+	dest.Status.Phase = corev1.ClaimBound
+	require.NoError(t, c.Status().Update(ctx, dest))
+
+	adapter, err := NewPowerStoreAdapter(c, translation.NewEngine())
+	require.NoError(t, err)
+
+	uvr := newCapacityTestUVR(t, "reserve-bound")
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+
+	_, ok := adapter.ReservedCapacity(uvr)
+	assert.False(t, ok, "a bound destination already holds its capacity")
+	bound, err := adapter.DestinationVolumeBound(ctx, uvr)
+	require.NoError(t, err)
+	assert.True(t, bound)
+}
@@ -6,6 +6,9 @@ package adapters
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -13,7 +16,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -29,16 +34,25 @@ const (
 	// VolumeReplication is the Ceph-CSI VolumeReplication CRD
 	VolumeReplicationAPIVersion = "replication.storage.openshift.io/v1alpha1"
 	VolumeReplicationKind       = "VolumeReplication"
+	VolumeReplicationClassKind  = "VolumeReplicationClass"
+
+	// defaultVolumeReplicationClass is the VolumeReplicationClass of the VolumeReplications the adapter creates
+	defaultVolumeReplicationClass = "rbd-volumereplicationclass"
 
 	// State transition timeouts and retry settings
 	DefaultStateTransitionTimeout = 5 * time.Minute
 	StateTransitionRetryInterval  = 30 * time.Second
 	MaxStateTransitionRetries     = 10
 
-	// Cache settings
+	// Cache settings, used when AdapterConfig leaves them unset
 	StatusCacheTTL     = 30 * time.Second
 	StatusCacheMaxSize = 1000
 
+	// Bounds for configurable cache settings
+	MinStatusCacheTTL     = 1 * time.Second
+	MaxStatusCacheTTL     = 1 * time.Hour
+	MaxStatusCacheMaxSize = 100000
+
 	// Ceph-specific constants
 	CephPrimaryState   = "primary"
 	CephSecondaryState = "secondary"
@@ -83,8 +97,8 @@ type VolumeReplicationSpec struct {
 	PvcName string `json:"pvcName"`
 	// replicationState is the state of the volume being replicated
 	ReplicationState string `json:"replicationState"`
-	// dataSource contains the data source information
-	DataSource *corev1.VolumeSource `json:"dataSource,omitempty"`
+	// dataSource references the snapshot or PVC the replication is seeded from
+	DataSource *corev1.TypedLocalObjectReference `json:"dataSource,omitempty"`
 	// autoResync indicates if the volume should be automatically resynced
 	AutoResync *bool `json:"autoResync,omitempty"`
 }
@@ -94,7 +108,7 @@ func (vrs *VolumeReplicationSpec) DeepCopyInto(out *VolumeReplicationSpec) {
 	*out = *vrs
 	if vrs.DataSource != nil {
 		in, out := &vrs.DataSource, &out.DataSource
-		*out = new(corev1.VolumeSource)
+		*out = new(corev1.TypedLocalObjectReference)
 		(*in).DeepCopyInto(*out)
 	}
 	if vrs.AutoResync != nil {
@@ -178,27 +192,44 @@ type CachedStatus struct {
 
 // StatusCache provides thread-safe caching for replication status
 type StatusCache struct {
-	cache map[string]*CachedStatus
-	mutex sync.RWMutex
-	ttl   time.Duration
+	cache   map[string]*CachedStatus
+	mutex   sync.RWMutex
+	ttl     time.Duration
+	maxSize int
 }
 
 // NewStatusCache creates a new status cache
 func NewStatusCache(ttl time.Duration) *StatusCache {
+	return NewStatusCacheWithSize(ttl, StatusCacheMaxSize)
+}
+
+// NewStatusCacheWithSize creates a new status cache holding at most maxSize entries
+func NewStatusCacheWithSize(ttl time.Duration, maxSize int) *StatusCache {
 	return &StatusCache{
-		cache: make(map[string]*CachedStatus),
-		mutex: sync.RWMutex{},
-		ttl:   ttl,
+		cache:   make(map[string]*CachedStatus),
+		mutex:   sync.RWMutex{},
+		ttl:     ttl,
+		maxSize: maxSize,
 	}
 }
 
-// Get retrieves cached status if valid
+// TTL returns how long cached entries stay valid
+func (sc *StatusCache) TTL() time.Duration {
+	return sc.ttl
+}
+
+// MaxSize returns the maximum number of cached entries
+func (sc *StatusCache) MaxSize() int {
+	return sc.maxSize
+}
+
+// Get retrieves cached status if valid. It never reports a nil status as found.
 func (sc *StatusCache) Get(key string) (*ReplicationStatus, bool) {
 	sc.mutex.RLock()
 	defer sc.mutex.RUnlock()
 
 	cached, exists := sc.cache[key]
-	if !exists {
+	if !exists || cached.Status == nil {
 		return nil, false
 	}
 
@@ -209,22 +240,34 @@ func (sc *StatusCache) Get(key string) (*ReplicationStatus, bool) {
 	return cached.Status, true
 }
 
-// Set stores status in cache
+// Set stores status in cache. Setting a nil status removes the entry, like Delete.
 func (sc *StatusCache) Set(key string, status *ReplicationStatus) {
 	sc.mutex.Lock()
 	defer sc.mutex.Unlock()
 
+	if status == nil {
+		delete(sc.cache, key)
+		return
+	}
+
 	sc.cache[key] = &CachedStatus{
 		Status:    status,
 		Timestamp: time.Now(),
 	}
 
 	// Simple cache size management
-	if len(sc.cache) > StatusCacheMaxSize {
+	if len(sc.cache) > sc.maxSize {
 		sc.evictOldest()
 	}
 }
 
+// Delete removes the entry of key, so the next Get misses
+func (sc *StatusCache) Delete(key string) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	delete(sc.cache, key)
+}
+
 // evictOldest removes the oldest cache entry
 func (sc *StatusCache) evictOldest() {
 	var oldestKey string
@@ -255,7 +298,25 @@ type StateTransition struct {
 	To       string
 	Allowed  bool
 	Reason   string
+	Started  time.Time
 	Duration time.Duration
+	// Target is the state whose observation on the VolumeReplication completes the
+	// transition; empty when the operation that started it completes it
+	Target string
+}
+
+// cephTransitionLedger tracks the active state transitions of Ceph replications. It is
+// shared by all Ceph adapters: the controller engine creates one per operation, so a
+// transition started by one adapter is completed by another.
+type cephTransitionLedger struct {
+	mu          sync.RWMutex
+	transitions map[string]*StateTransition
+}
+
+var cephTransitions = newCephTransitionLedger()
+
+func newCephTransitionLedger() *cephTransitionLedger {
+	return &cephTransitionLedger{transitions: make(map[string]*StateTransition)}
 }
 
 // CephAdapter implements the ReplicationAdapter interface for Ceph-CSI
@@ -264,36 +325,78 @@ type CephAdapter struct {
 	client      client.Client
 	statusCache *StatusCache
 
-	// State transition tracking
-	transitionMutex   sync.RWMutex
-	activeTransitions map[string]*StateTransition
+	// State transition tracking, shared by all Ceph adapters
+	transitions *cephTransitionLedger
 
 	// Performance metrics
 	// operationMetrics sync.Map // TODO: Implement metrics collection
 	lastHealthCheck time.Time
 	healthMutex     sync.RWMutex
+
+	// recoverySettleDelay is how long recovery waits for an action to take effect
+	recoverySettleDelay time.Duration
+
+	// transitionPollInterval is how often a state transition is checked for completion
+	transitionPollInterval time.Duration
 }
 
 // NewCephAdapter creates a new CephAdapter instance
 func NewCephAdapter(client client.Client, translator *translation.Engine) (*CephAdapter, error) {
+	return NewCephAdapterWithConfig(client, translator, nil)
+}
+
+// NewCephAdapterWithConfig creates a new Ceph adapter, taking the status cache settings from
+// config. Unset cache settings fall back to StatusCacheTTL and StatusCacheMaxSize.
+func NewCephAdapterWithConfig(client client.Client, translator *translation.Engine, config *AdapterConfig) (*CephAdapter, error) {
 	if client == nil {
 		return nil, fmt.Errorf("client cannot be nil")
 	}
 	if translator == nil {
 		return nil, fmt.Errorf("translator cannot be nil")
 	}
+	if err := validateStatusCacheConfig(config); err != nil {
+		return nil, err
+	}
 
-	baseAdapter := NewBaseAdapter(translation.BackendCeph, client, translator, nil)
+	baseAdapter := NewBaseAdapter(translation.BackendCeph, client, translator, config)
+
+	cacheTTL, cacheMaxSize := StatusCacheTTL, StatusCacheMaxSize
+	if config != nil && config.StatusCacheTTL > 0 {
+		cacheTTL = config.StatusCacheTTL
+	}
+	if config != nil && config.StatusCacheMaxSize > 0 {
+		cacheMaxSize = config.StatusCacheMaxSize
+	}
 
 	return &CephAdapter{
-		BaseAdapter:       baseAdapter,
-		client:            client,
-		statusCache:       NewStatusCache(StatusCacheTTL),
-		activeTransitions: make(map[string]*StateTransition),
-		lastHealthCheck:   time.Now(),
+		BaseAdapter:            baseAdapter,
+		client:                 client,
+		statusCache:            NewStatusCacheWithSize(cacheTTL, cacheMaxSize),
+		transitions:            cephTransitions,
+		lastHealthCheck:        time.Now(),
+		recoverySettleDelay:    StateTransitionRetryInterval,
+		transitionPollInterval: StateTransitionRetryInterval,
 	}, nil
 }
 
+// validateStatusCacheConfig checks that configured cache settings are within sane bounds.
+// Zero values mean the defaults are used.
+func validateStatusCacheConfig(config *AdapterConfig) error {
+	if config == nil {
+		return nil
+	}
+
+	if config.StatusCacheTTL != 0 && (config.StatusCacheTTL < MinStatusCacheTTL || config.StatusCacheTTL > MaxStatusCacheTTL) {
+		return fmt.Errorf("status cache TTL %s must be between %s and %s", config.StatusCacheTTL, MinStatusCacheTTL, MaxStatusCacheTTL)
+	}
+
+	if config.StatusCacheMaxSize < 0 || config.StatusCacheMaxSize > MaxStatusCacheMaxSize {
+		return fmt.Errorf("status cache max size %d must be between 1 and %d", config.StatusCacheMaxSize, MaxStatusCacheMaxSize)
+	}
+
+	return nil
+}
+
 // GetBackendType returns the backend type
 func (ca *CephAdapter) GetBackendType() translation.Backend {
 	return translation.BackendCeph
@@ -329,42 +432,109 @@ func (ca *CephAdapter) isValidStateTransition(from, to string) (bool, string) {
 	return false, fmt.Sprintf("transition from %s to %s is not allowed", from, to)
 }
 
-// trackStateTransition tracks an active state transition
-func (ca *CephAdapter) trackStateTransition(key, from, to string) {
-	ca.transitionMutex.Lock()
-	defer ca.transitionMutex.Unlock()
+// trackStateTransition tracks an active state transition and records its start on uvr
+func (ca *CephAdapter) trackStateTransition(uvr *replicationv1alpha1.UnifiedVolumeReplication, key, from, to string) {
+	ca.trackObservedStateTransition(uvr, key, from, to, "")
+}
+
+// trackObservedStateTransition tracks a state transition like trackStateTransition, to be
+// completed by GetReplicationStatus once the VolumeReplication reports target
+func (ca *CephAdapter) trackObservedStateTransition(uvr *replicationv1alpha1.UnifiedVolumeReplication, key, from, to, target string) {
+	ca.transitions.mu.Lock()
+	defer ca.transitions.mu.Unlock()
 
 	allowed, reason := ca.isValidStateTransition(from, to)
-	ca.activeTransitions[key] = &StateTransition{
+	ca.transitions.transitions[key] = &StateTransition{
 		From:     from,
 		To:       to,
 		Allowed:  allowed,
 		Reason:   reason,
+		Started:  time.Now(),
 		Duration: 0,
+		Target:   target,
 	}
+	ca.recordEventf(uvr, corev1.EventTypeNormal, "StateTransitionStarted",
+		"Ceph transition from %s to %s started", from, to)
 }
 
-// completeStateTransition marks a state transition as complete
-func (ca *CephAdapter) completeStateTransition(key string, success bool) {
-	ca.transitionMutex.Lock()
-	defer ca.transitionMutex.Unlock()
+// completeStateTransition marks a state transition as complete, failed when err is set, and
+// records the outcome and how long the transition took on uvr
+func (ca *CephAdapter) completeStateTransition(uvr *replicationv1alpha1.UnifiedVolumeReplication, key string, err error) {
+	ca.transitions.mu.Lock()
+	defer ca.transitions.mu.Unlock()
 
-	if transition, exists := ca.activeTransitions[key]; exists {
-		if success {
-			delete(ca.activeTransitions, key)
-		} else {
-			transition.Reason = "transition failed"
-		}
+	transition, exists := ca.transitions.transitions[key]
+	if !exists {
+		return
+	}
+	transition.Duration = time.Since(transition.Started).Round(time.Millisecond)
+	if err == nil {
+		delete(ca.transitions.transitions, key)
+		ca.recordEventf(uvr, corev1.EventTypeNormal, "StateTransitionSucceeded",
+			"Ceph transition from %s to %s completed in %s", transition.From, transition.To, transition.Duration)
+		return
+	}
+	// A failed request leaves the VolumeReplication unchanged, so observing the target
+	// state later does not complete it
+	transition.Reason = "transition failed"
+	transition.Target = ""
+	ca.recordEventf(uvr, corev1.EventTypeWarning, "StateTransitionFailed",
+		"Ceph transition from %s to %s failed after %s: %v", transition.From, transition.To, transition.Duration, err)
+}
+
+// completeObservedStateTransition completes the transition tracked by
+// trackObservedStateTransition once the VolumeReplication reports its target state
+func (ca *CephAdapter) completeObservedStateTransition(uvr *replicationv1alpha1.UnifiedVolumeReplication, key, observed string) {
+	transition, exists := ca.getActiveStateTransition(key)
+	if !exists || transition.Target == "" || transition.Target != observed {
+		return
+	}
+	ca.completeStateTransition(uvr, key, nil)
+}
+
+// cephTransitionTarget is the state the mirror daemon reports once a transition to state
+// completes: the promoting and demoting states end as source and replica
+func cephTransitionTarget(state string) string {
+	switch state {
+	case "promoting":
+		return "source"
+	case "demoting":
+		return "replica"
+	}
+	return state
+}
+
+// cephTransitionStep names the step a role change of the VolumeReplication goes through, as
+// PromoteReplica and DemoteSource track it: promoting towards source, demoting from it
+func cephTransitionStep(from, target string) string {
+	switch {
+	case target == "source" && from != "source":
+		return "promoting"
+	case target == "replica" && from == "source":
+		return "demoting"
 	}
+	return target
 }
 
 // getActiveStateTransition retrieves an active state transition
 func (ca *CephAdapter) getActiveStateTransition(key string) (*StateTransition, bool) {
-	ca.transitionMutex.RLock()
-	defer ca.transitionMutex.RUnlock()
+	ca.transitions.mu.RLock()
+	defer ca.transitions.mu.RUnlock()
+
+	transition, exists := ca.transitions.transitions[key]
+	if !exists {
+		return nil, false
+	}
+	// A copy, as completing the transition updates it
+	copied := *transition
+	return &copied, true
+}
 
-	transition, exists := ca.activeTransitions[key]
-	return transition, exists
+// forgetStateTransition drops the transition of a replication being deleted
+func (ca *CephAdapter) forgetStateTransition(uvr *replicationv1alpha1.UnifiedVolumeReplication) {
+	ca.transitions.mu.Lock()
+	defer ca.transitions.mu.Unlock()
+	delete(ca.transitions.transitions, ca.buildTransitionKey(uvr))
 }
 
 // ValidateConfiguration validates the unified configuration for Ceph compatibility
@@ -452,7 +622,7 @@ func (ca *CephAdapter) EnsureReplication(ctx context.Context, uvr *replicationv1
 
 	// Validate configuration
 	if err := ca.ValidateConfiguration(uvr); err != nil {
-		ca.BaseAdapter.updateMetrics("ensure", false, startTime)
+		ca.BaseAdapter.updateMetrics(uvr, "ensure", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendCeph, "ensure", uvr.Name, "configuration validation failed", err)
 	}
 
@@ -470,57 +640,128 @@ func (ca *CephAdapter) EnsureReplication(ctx context.Context, uvr *replicationv1
 			logger.Info("VolumeReplication not found, creating")
 			vr, err := ca.buildVolumeReplication(uvr)
 			if err != nil {
-				ca.BaseAdapter.updateMetrics("create", false, startTime)
+				ca.BaseAdapter.updateMetrics(uvr, "create", false, startTime)
 				return NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendCeph, "create", uvr.Name, "failed to build VolumeReplication", err)
 			}
 
+			// Seed the replication from a consistent snapshot of the source, if requested
+			dataSource, err := ca.ensureBaselineSnapshot(ctx, uvr)
+			if err != nil {
+				ca.BaseAdapter.updateMetrics(uvr, "create", false, startTime)
+				return err
+			}
+			vr.Spec.DataSource = dataSource
+			setResourceOwner(vr, uvr)
+
+			// Reserve capacity and provision the destination before replication is established
+			if err := ca.prepareDestination(ctx, uvr); err != nil {
+				ca.BaseAdapter.updateMetrics(uvr, "create", false, startTime)
+				return err
+			}
+
 			if err := ca.client.Create(ctx, vr); err != nil {
-				ca.BaseAdapter.updateMetrics("create", false, startTime)
+				ca.ReleaseCapacity(uvr)
+				ca.BaseAdapter.updateMetrics(uvr, "create", false, startTime)
 				return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "create", uvr.Name, "failed to create VolumeReplication", err)
 			}
 
-			ca.BaseAdapter.updateMetrics("create", true, startTime)
+			ca.BaseAdapter.updateMetrics(uvr, "create", true, startTime)
 			logger.Info("Successfully created Ceph VolumeReplication", "volumeReplication", vr.ObjectMeta.Name)
 			return nil
 		}
 		// Some other error occurred
-		ca.BaseAdapter.updateMetrics("ensure", false, startTime)
+		ca.BaseAdapter.updateMetrics(uvr, "ensure", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "ensure", uvr.Name, "failed to check existing VolumeReplication", err)
 	}
 
-	// VolumeReplication exists, update it if needed
+	// VolumeReplication exists, update it if needed, unless another UVR owns it
+	if err := checkResourceOwner(existingVR, fmt.Sprintf("VolumeReplication %s/%s", uvr.Namespace, vrName), uvr); err != nil {
+		ca.BaseAdapter.updateMetrics(uvr, "ensure", false, startTime)
+		return NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendCeph, "ensure", uvr.Name, "VolumeReplication belongs to another UVR", err)
+	}
 	logger.V(1).Info("VolumeReplication exists, updating if needed")
 
+	// An existing destination volume is re-validated on every ensure, not only at creation
+	if err := ca.verifyDestinationVolume(ctx, uvr); err != nil {
+		ca.BaseAdapter.updateMetrics(uvr, "ensure", false, startTime)
+		return err
+	}
+
+	// The baseline snapshot is no longer needed once the first sync has completed
+	if existingVR.Status.LastSyncTime != nil {
+		if err := ca.cleanupBaselineSnapshot(ctx, uvr); err != nil {
+			logger.Error(err, "Failed to remove baseline snapshot")
+		}
+	}
+
 	// Translate unified state to Ceph state
 	cephState, _, err := ca.translateToCephState(string(uvr.Spec.ReplicationState))
 	if err != nil {
-		ca.BaseAdapter.updateMetrics("update", false, startTime)
+		ca.BaseAdapter.updateMetrics(uvr, "update", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendCeph, "update", uvr.Name, "state translation failed", err)
 	}
 
 	// Check if update is needed
-	if existingVR.Spec.ReplicationState == cephState {
+	_, drainRequested := existingVR.Annotations[DrainRequestedAnnotation]
+	if existingVR.Spec.ReplicationState == cephState && !drainRequested {
 		logger.V(1).Info("VolumeReplication is already in desired state, no update needed")
-		ca.BaseAdapter.updateMetrics("ensure", true, startTime)
+		ca.BaseAdapter.updateMetrics(uvr, "ensure", true, startTime)
 		return nil
 	}
 
-	// Update the spec
+	// Update the spec. The drain request is done with once the source is demoted, and is
+	// stale if the demotion was abandoned.
+	stateChanged := existingVR.Spec.ReplicationState != cephState
+	transitionKey := ca.buildTransitionKey(uvr)
+	if stateChanged {
+		// A role change driven by the spec is tracked like PromoteReplica and DemoteSource
+		// do, and completes once the VolumeReplication reports the new state
+		from, target := existingVR.Spec.ReplicationState, cephState
+		if unified, _, err := ca.translateFromCephState(from); err == nil {
+			from = unified
+		}
+		if unified, _, err := ca.translateFromCephState(target); err == nil {
+			target = cephTransitionTarget(unified)
+		}
+		ca.trackObservedStateTransition(uvr, transitionKey, from, cephTransitionStep(from, target), target)
+	}
 	existingVR.Spec.ReplicationState = cephState
+	delete(existingVR.Annotations, DrainRequestedAnnotation)
+	setResourceOwner(existingVR, uvr)
 
 	// Update the VolumeReplication resource
 	if err := ca.client.Update(ctx, existingVR); err != nil {
-		ca.BaseAdapter.updateMetrics("update", false, startTime)
+		if stateChanged {
+			ca.completeStateTransition(uvr, transitionKey, err)
+		}
+		ca.BaseAdapter.updateMetrics(uvr, "update", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "update", uvr.Name, "failed to update VolumeReplication", err)
 	}
 
-	ca.BaseAdapter.updateMetrics("update", true, startTime)
+	ca.BaseAdapter.updateMetrics(uvr, "update", true, startTime)
+	if stateChanged {
+		ca.recordStateChange(uvr, startTime)
+	}
 	logger.Info("Successfully updated Ceph VolumeReplication", "volumeReplication", existingVR.ObjectMeta.Name)
 	return nil
 }
 
-// DeleteReplication deletes a VolumeReplication resource
+// DeleteReplication deletes the VolumeReplication, its baseline snapshot and an
+// auto-provisioned destination volume
 func (ca *CephAdapter) DeleteReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	if err := ca.DeleteBackendResource(ctx, uvr); err != nil {
+		return err
+	}
+	if err := ca.cleanupBaselineSnapshot(ctx, uvr); err != nil {
+		return err
+	}
+	ca.forgetStateTransition(uvr)
+	return ca.releaseDestination(ctx, uvr)
+}
+
+// DeleteBackendResource deletes the VolumeReplication, leaving the destination volume and the
+// baseline snapshot in place
+func (ca *CephAdapter) DeleteBackendResource(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
 	logger := log.FromContext(ctx).WithName("ceph-adapter").WithValues("uvr", uvr.Name)
 	logger.Info("Deleting Ceph VolumeReplication")
 
@@ -534,21 +775,28 @@ func (ca *CephAdapter) DeleteReplication(ctx context.Context, uvr *replicationv1
 	}, vr); err != nil {
 		if errors.IsNotFound(err) {
 			logger.Info("VolumeReplication not found, already deleted")
-			ca.BaseAdapter.updateMetrics("delete", true, startTime)
+			ca.BaseAdapter.updateMetrics(uvr, "delete", true, startTime)
 			return nil
 		}
-		ca.BaseAdapter.updateMetrics("delete", false, startTime)
+		ca.BaseAdapter.updateMetrics(uvr, "delete", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "delete", uvr.Name, "failed to get VolumeReplication", err)
 	}
 
+	// A VolumeReplication of the same name owned by another UVR is not this UVR's to delete
+	if err := checkResourceOwner(vr, vr.Name, uvr); err != nil {
+		logger.Info("VolumeReplication belongs to another UVR, leaving it in place", "volumeReplication", vr.Name)
+		ca.BaseAdapter.updateMetrics(uvr, "delete", true, startTime)
+		return nil
+	}
+
 	// Delete the resource
 	if err := ca.client.Delete(ctx, vr); err != nil {
-		ca.BaseAdapter.updateMetrics("delete", false, startTime)
+		ca.BaseAdapter.updateMetrics(uvr, "delete", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "delete", uvr.Name, "failed to delete VolumeReplication", err)
 	}
 
 	// Update metrics
-	ca.BaseAdapter.updateMetrics("delete", true, startTime)
+	ca.BaseAdapter.updateMetrics(uvr, "delete", true, startTime)
 
 	logger.Info("Successfully deleted Ceph VolumeReplication", "volumeReplication", vr.ObjectMeta.Name)
 	return nil
@@ -593,6 +841,8 @@ func (ca *CephAdapter) GetReplicationStatus(ctx context.Context, uvr *replicatio
 		// Return basic status on error
 		status = ca.buildBasicReplicationStatus(vr)
 	}
+	status.ConsistencyLevel = resolveConsistencyLevel(vr.GetAnnotations(), status.LastSyncTime)
+	status.Members = primaryVolumeMembers(uvr, status.LastSyncTime)
 
 	// Cache the status
 	ca.statusCache.Set(cacheKey, status)
@@ -601,6 +851,12 @@ func (ca *CephAdapter) GetReplicationStatus(ctx context.Context, uvr *replicatio
 	return status, nil
 }
 
+// GetConsistencyState reports whether the latest sync point of the VolumeReplication was taken
+// while the application was quiesced
+func (ca *CephAdapter) GetConsistencyState(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (ConsistencyLevel, error) {
+	return consistencyLevelOf(ca.GetReplicationStatus(ctx, uvr))
+}
+
 // buildEnhancedReplicationStatus creates detailed status with condition analysis
 func (ca *CephAdapter) buildEnhancedReplicationStatus(ctx context.Context, vr *VolumeReplication, uvr *replicationv1alpha1.UnifiedVolumeReplication) (*ReplicationStatus, error) {
 	logger := log.FromContext(ctx).WithName("ceph-adapter")
@@ -613,7 +869,7 @@ func (ca *CephAdapter) buildEnhancedReplicationStatus(ctx context.Context, vr *V
 	}
 
 	// Analyze conditions for detailed health status
-	health, detailedMessage := ca.analyzeVolumeReplicationConditions(vr.Status.Conditions)
+	health, degradedReason, detailedMessage := ca.analyzeVolumeReplicationConditions(vr.Status.Conditions)
 
 	// Calculate sync progress with enhanced metrics
 	progress := ca.calculateEnhancedSyncProgress(vr.Status, vr.Spec.ReplicationState)
@@ -626,21 +882,48 @@ func (ca *CephAdapter) buildEnhancedReplicationStatus(ctx context.Context, vr *V
 	activeTransition, hasTransition := ca.getActiveStateTransition(transitionKey)
 	if hasTransition && !activeTransition.Allowed {
 		health = ReplicationHealthDegraded
+		degradedReason = DegradedReasonInvalidTransition
 		detailedMessage += fmt.Sprintf("; Invalid transition: %s", activeTransition.Reason)
 	}
 
 	status := &ReplicationStatus{
 		State:           unifiedState,
 		Health:          health,
+		DegradedReason:  degradedReason,
 		Message:         detailedMessage,
 		SyncProgress:    &progress,
 		BackendSpecific: backendSpecific,
 		Conditions:      ca.convertConditionsToStatusConditions(vr.Status.Conditions),
 	}
+	status.DualPrimary = ca.detectSplitBrain(vr.Status.Conditions)
+
+	// Fold in the rbd-mirror view of the pool; a broken peer never shows on the VolumeReplication
+	mirror, err := ca.GetMirrorPeerStatus(ctx, uvr)
+	if err != nil {
+		logger.V(1).Info("Failed to read rbd-mirror status", "error", err.Error())
+	} else if mirror != nil {
+		mirror.ImageState = cephImageState(vr.Status.Conditions)
+		applyMirrorStatus(status, mirror)
+	}
 
 	if vr.Status.LastSyncTime != nil {
 		status.LastSyncTime = &vr.Status.LastSyncTime.Time
 	}
+	if vr.Status.LastSyncDuration != nil {
+		status.LastSyncDuration = &vr.Status.LastSyncDuration.Duration
+	}
+
+	// Prefer the role the mirror daemon reports over the requested one
+	primaryState := unifiedState
+	if vr.Status.State != "" {
+		if observed, _, err := ca.translateFromCephState(strings.ToLower(vr.Status.State)); err == nil {
+			primaryState = observed
+			ca.completeObservedStateTransition(uvr, transitionKey, observed)
+		}
+	}
+	// The UVR endpoints only say where the primary was asked to be; the mirror names where it is
+	status.PrimaryCluster, status.PrimarySite = cephPrimaryIdentity(mirror, primaryState)
+	status.Direction = resolveReplicationDirection(primaryState)
 
 	// Estimate next sync time based on scheduling
 	if nextSync := ca.estimateNextSyncTime(uvr, vr); nextSync != nil {
@@ -650,6 +933,19 @@ func (ca *CephAdapter) buildEnhancedReplicationStatus(ctx context.Context, vr *V
 	return status, nil
 }
 
+// detectSplitBrain reports whether the mirror daemon flagged both images as primary
+func (ca *CephAdapter) detectSplitBrain(conditions []metav1.Condition) bool {
+	for _, condition := range conditions {
+		if condition.Status != metav1.ConditionTrue {
+			continue
+		}
+		if strings.Contains(strings.ToLower(condition.Reason+" "+condition.Message), "split-brain") {
+			return true
+		}
+	}
+	return false
+}
+
 // buildBasicReplicationStatus creates basic status for fallback
 func (ca *CephAdapter) buildBasicReplicationStatus(vr *VolumeReplication) *ReplicationStatus {
 	// Basic state translation without error handling
@@ -660,45 +956,57 @@ func (ca *CephAdapter) buildBasicReplicationStatus(vr *VolumeReplication) *Repli
 
 	health := ca.mapCephStatusToHealth(vr.Status)
 	progress := ca.calculateSyncProgress(vr.Status)
+	degradedReason := degradedReasonForHealth(health)
+	if health == ReplicationHealthUnknown && vr.Status.State != "" {
+		degradedReason = DegradedReasonUnknownState
+	}
 
 	return &ReplicationStatus{
-		State:        unifiedState,
-		Health:       health,
-		Message:      vr.Status.Message,
-		SyncProgress: &progress,
+		State:          unifiedState,
+		Health:         health,
+		DegradedReason: degradedReason,
+		Message:        vr.Status.Message,
+		SyncProgress:   &progress,
 	}
 }
 
-// analyzeVolumeReplicationConditions provides detailed condition analysis
-func (ca *CephAdapter) analyzeVolumeReplicationConditions(conditions []metav1.Condition) (ReplicationHealth, string) {
+// analyzeVolumeReplicationConditions provides detailed condition analysis, including the
+// degraded reason when the conditions report anything other than healthy
+func (ca *CephAdapter) analyzeVolumeReplicationConditions(conditions []metav1.Condition) (ReplicationHealth, DegradedReason, string) {
 	if len(conditions) == 0 {
-		return ReplicationHealthUnknown, "No conditions available"
+		return ReplicationHealthUnknown, "", "No conditions available"
 	}
 
 	var messages []string
+	var reason DegradedReason
 	health := ReplicationHealthHealthy
+	resyncing := false
 
 	for _, condition := range conditions {
 		switch condition.Type {
 		case "Degraded":
 			if condition.Status == metav1.ConditionTrue {
 				health = ReplicationHealthDegraded
+				reason = DegradedReasonBackendDegraded
 				messages = append(messages, fmt.Sprintf("Degraded: %s", condition.Message))
 			}
 		case "Healthy":
 			if condition.Status == metav1.ConditionFalse {
 				if health == ReplicationHealthHealthy {
 					health = ReplicationHealthDegraded
+					reason = DegradedReasonBackendDegraded
 				}
 				messages = append(messages, fmt.Sprintf("Not healthy: %s", condition.Message))
 			}
 		case "Error", "Failed":
 			if condition.Status == metav1.ConditionTrue {
 				health = ReplicationHealthUnhealthy
+				reason = DegradedReasonBackendError
 				messages = append(messages, fmt.Sprintf("Error: %s", condition.Message))
 			}
 		case "Resyncing":
 			if condition.Status == metav1.ConditionTrue {
+				resyncing = true
 				messages = append(messages, "Resyncing in progress")
 			}
 		case "Ready":
@@ -708,15 +1016,26 @@ func (ca *CephAdapter) analyzeVolumeReplicationConditions(conditions []metav1.Co
 		}
 	}
 
+	// A degraded replica that is resyncing is expected to recover on its own
+	if health == ReplicationHealthDegraded && resyncing {
+		reason = DegradedReasonResyncing
+	}
+
 	if len(messages) == 0 {
 		messages = append(messages, "Status conditions analyzed")
 	}
 
-	return health, strings.Join(messages, "; ")
+	return health, reason, strings.Join(messages, "; ")
 }
 
-// calculateEnhancedSyncProgress provides detailed sync progress
+// calculateEnhancedSyncProgress reports the sync progress Ceph-CSI publishes in the
+// VolumeReplication conditions and message, falling back to an estimate from the state when
+// they carry no numbers
 func (ca *CephAdapter) calculateEnhancedSyncProgress(status VolumeReplicationStatus, state string) SyncProgress {
+	if progress, ok := parseCephSyncProgress(status); ok {
+		return progress
+	}
+
 	progress := SyncProgress{
 		TotalBytes:  100, // Default total
 		SyncedBytes: 0,
@@ -747,6 +1066,87 @@ func (ca *CephAdapter) calculateEnhancedSyncProgress(status VolumeReplicationSta
 	return progress
 }
 
+var (
+	// cephSyncedBytesRegex matches synced and total bytes, e.g. "123456/1000000 bytes"
+	cephSyncedBytesRegex = regexp.MustCompile(`([0-9]+)\s*/\s*([0-9]+)\s*bytes`)
+	// cephSyncingPercentRegex matches the syncing_percent of the rbd-mirror image description
+	cephSyncingPercentRegex = regexp.MustCompile(`"?syncing_percent"?\s*[:=]\s*([0-9]+(?:\.[0-9]+)?)`)
+	// cephResyncPercentRegex matches a percentage in a Resyncing condition, e.g. "resyncing 45%"
+	cephResyncPercentRegex = regexp.MustCompile(`([0-9]+(?:\.[0-9]+)?)\s*%`)
+	// cephBytesPerSnapshotRegex matches the size of a snapshot sync in the rbd-mirror image
+	// description of snapshot-based mirroring
+	cephBytesPerSnapshotRegex = regexp.MustCompile(`"?bytes_per_snapshot"?\s*[:=]\s*([0-9]+(?:\.[0-9]+)?)`)
+	// cephEntriesBehindRegex matches the journal entries the replica lags in journal-based mirroring
+	cephEntriesBehindRegex = regexp.MustCompile(`"?entries_behind_primary"?\s*[:=]\s*([0-9]+)`)
+)
+
+// parseCephSyncProgress reads the sync progress from the numbers Ceph-CSI copies from the
+// rbd-mirror image status into the conditions and message: synced and total bytes,
+// syncing_percent, a bare percentage in the Resyncing condition, and entries_behind_primary
+// of 0 for a caught-up journal replica. bytes_per_snapshot of snapshot-based mirroring only
+// sizes a reported percentage. It returns false unless synced bytes or a percentage is
+// present, so partial numbers never override the state-based estimate.
+func parseCephSyncProgress(status VolumeReplicationStatus) (SyncProgress, bool) {
+	var resyncing, others []string
+	for _, condition := range status.Conditions {
+		if condition.Status != metav1.ConditionTrue {
+			continue
+		}
+		if condition.Type == "Resyncing" {
+			resyncing = append(resyncing, condition.Message)
+		} else {
+			others = append(others, condition.Message)
+		}
+	}
+	// The Resyncing condition is read first, the message last
+	text := strings.Join(append(append(slices.Clone(resyncing), others...), status.Message), "; ")
+
+	var progress SyncProgress
+	hasBytes, hasPercent := false, false
+	if match := cephSyncedBytesRegex.FindStringSubmatch(text); match != nil {
+		synced, syncedErr := strconv.ParseInt(match[1], 10, 64)
+		total, totalErr := strconv.ParseInt(match[2], 10, 64)
+		if syncedErr == nil && totalErr == nil && total > 0 {
+			progress.SyncedBytes, progress.TotalBytes = min(synced, total), total
+			hasBytes = true
+		}
+	}
+	percent := ""
+	if match := cephSyncingPercentRegex.FindStringSubmatch(text); match != nil {
+		percent = match[1]
+	} else if match := cephResyncPercentRegex.FindStringSubmatch(strings.Join(resyncing, "; ")); match != nil {
+		percent = match[1]
+	}
+	if value, err := strconv.ParseFloat(percent, 64); err == nil && value <= 100 {
+		progress.PercentComplete = value
+		hasPercent = true
+	}
+	if match := cephEntriesBehindRegex.FindStringSubmatch(text); match != nil && !hasPercent {
+		// A journal replica is caught up once it has replayed every entry
+		if behind, err := strconv.ParseInt(match[1], 10, 64); err == nil && behind == 0 {
+			progress.PercentComplete = 100
+			hasPercent = true
+		}
+	}
+	if !hasBytes && !hasPercent {
+		return SyncProgress{}, false
+	}
+
+	// Derive what was not reported from what was
+	if hasPercent && !hasBytes {
+		if match := cephBytesPerSnapshotRegex.FindStringSubmatch(text); match != nil {
+			if total, err := strconv.ParseFloat(match[1], 64); err == nil && total > 0 {
+				progress.TotalBytes = int64(total)
+				progress.SyncedBytes = int64(total * progress.PercentComplete / 100)
+			}
+		}
+	}
+	if hasBytes && !hasPercent {
+		progress.PercentComplete = float64(progress.SyncedBytes) / float64(progress.TotalBytes) * 100
+	}
+	return progress, true
+}
+
 // buildBackendSpecificInfo creates Ceph-specific status information
 func (ca *CephAdapter) buildBackendSpecificInfo(vr *VolumeReplication) map[string]interface{} {
 	info := make(map[string]interface{})
@@ -795,9 +1195,9 @@ func (ca *CephAdapter) estimateNextSyncTime(uvr *replicationv1alpha1.UnifiedVolu
 		return &next
 	}
 
-	// For interval mode, use RPO
-	if uvr.Spec.Schedule.Mode == "interval" && uvr.Spec.Schedule.Rpo != "" {
-		if duration, err := time.ParseDuration(uvr.Spec.Schedule.Rpo); err == nil {
+	// For interval and auto mode, use the configured sync interval
+	if uvr.Spec.Schedule.Mode != "continuous" && uvr.SyncInterval() != "" {
+		if duration, err := replicationv1alpha1.ParseScheduleDuration(uvr.SyncInterval()); err == nil {
 			var baseTime time.Time
 			if vr.Status.LastSyncTime != nil {
 				baseTime = vr.Status.LastSyncTime.Time
@@ -843,22 +1243,27 @@ func (ca *CephAdapter) mapCephStatusToHealth(status VolumeReplicationStatus) Rep
 	}
 
 	// Default to healthy if no specific conditions
-	if status.State == "primary" || status.State == "secondary" {
+	if strings.EqualFold(status.State, "primary") || strings.EqualFold(status.State, "secondary") {
 		return ReplicationHealthHealthy
 	}
 
 	return ReplicationHealthUnknown
 }
 
-// calculateSyncProgress calculates sync progress from Ceph status
+// calculateSyncProgress calculates sync progress from Ceph status, estimating it from the
+// state when the status carries no numbers
 func (ca *CephAdapter) calculateSyncProgress(status VolumeReplicationStatus) SyncProgress {
+	if progress, ok := parseCephSyncProgress(status); ok {
+		return progress
+	}
+
 	progress := SyncProgress{
 		TotalBytes:  100, // Default values for now
 		SyncedBytes: 0,
 	}
 
 	// Extract progress information from status message if available
-	if status.State == "primary" || status.State == "secondary" {
+	if strings.EqualFold(status.State, "primary") || strings.EqualFold(status.State, "secondary") {
 		progress.SyncedBytes = 100 // Assume fully synced
 	}
 
@@ -873,9 +1278,6 @@ func (ca *CephAdapter) buildVolumeReplication(uvr *replicationv1alpha1.UnifiedVo
 		return nil, fmt.Errorf("failed to translate state: %w", err)
 	}
 
-	// Default VolumeReplicationClass
-	volumeReplicationClass := "rbd-volumereplicationclass"
-
 	vr := &VolumeReplication{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: VolumeReplicationAPIVersion,
@@ -890,7 +1292,7 @@ func (ca *CephAdapter) buildVolumeReplication(uvr *replicationv1alpha1.UnifiedVo
 			},
 		},
 		Spec: VolumeReplicationSpec{
-			VolumeReplicationClass: volumeReplicationClass,
+			VolumeReplicationClass: defaultVolumeReplicationClass,
 			PvcName:                uvr.Spec.VolumeMapping.Source.PvcName,
 			ReplicationState:       cephState,
 		},
@@ -899,8 +1301,12 @@ func (ca *CephAdapter) buildVolumeReplication(uvr *replicationv1alpha1.UnifiedVo
 	return vr, nil
 }
 
-// buildVolumeReplicationName generates a name for the VolumeReplication resource
+// buildVolumeReplicationName generates a name for the VolumeReplication resource, or returns
+// the adopted VolumeReplication's name
 func (ca *CephAdapter) buildVolumeReplicationName(uvr *replicationv1alpha1.UnifiedVolumeReplication) string {
+	if name := adoptedResourceName(uvr); name != "" {
+		return name
+	}
 	return fmt.Sprintf("%s-vr", uvr.Name)
 }
 
@@ -941,19 +1347,19 @@ func (ca *CephAdapter) PromoteReplica(ctx context.Context, uvr *replicationv1alp
 	// Validate current state allows promotion
 	currentStatus, err := ca.GetReplicationStatus(ctx, uvr)
 	if err != nil {
-		ca.BaseAdapter.updateMetrics("promote", false, startTime)
+		ca.BaseAdapter.updateMetrics(uvr, "promote", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendCeph, "promote", uvr.Name, "failed to get current status", err)
 	}
 
 	// Check if promotion is allowed from current state
 	if allowed, reason := ca.isValidStateTransition(currentStatus.State, "promoting"); !allowed {
-		ca.BaseAdapter.updateMetrics("promote", false, startTime)
+		ca.BaseAdapter.updateMetrics(uvr, "promote", false, startTime)
 		return NewAdapterError(ErrorTypeValidation, translation.BackendCeph, "promote", uvr.Name,
 			fmt.Sprintf("invalid state transition: %s", reason))
 	}
 
 	// Track the state transition
-	ca.trackStateTransition(transitionKey, currentStatus.State, "promoting")
+	ca.trackStateTransition(uvr, transitionKey, currentStatus.State, "promoting")
 
 	// Get the VolumeReplication resource
 	vr := &VolumeReplication{}
@@ -961,38 +1367,38 @@ func (ca *CephAdapter) PromoteReplica(ctx context.Context, uvr *replicationv1alp
 		Name:      ca.buildVolumeReplicationName(uvr),
 		Namespace: uvr.Namespace,
 	}, vr); err != nil {
-		ca.completeStateTransition(transitionKey, false)
-		ca.BaseAdapter.updateMetrics("promote", false, startTime)
+		ca.completeStateTransition(uvr, transitionKey, err)
+		ca.BaseAdapter.updateMetrics(uvr, "promote", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "promote", uvr.Name, "failed to get VolumeReplication", err)
 	}
 
 	// Translate to Ceph promote state
 	cephPromoteState, _, err := ca.translateToCephState("promoting")
 	if err != nil {
-		ca.completeStateTransition(transitionKey, false)
-		ca.BaseAdapter.updateMetrics("promote", false, startTime)
+		ca.completeStateTransition(uvr, transitionKey, err)
+		ca.BaseAdapter.updateMetrics(uvr, "promote", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendCeph, "promote", uvr.Name, "failed to translate promote state", err)
 	}
 
 	// Update VolumeReplication to promote
 	vr.Spec.ReplicationState = cephPromoteState
 	if err := ca.client.Update(ctx, vr); err != nil {
-		ca.completeStateTransition(transitionKey, false)
-		ca.BaseAdapter.updateMetrics("promote", false, startTime)
+		ca.completeStateTransition(uvr, transitionKey, err)
+		ca.BaseAdapter.updateMetrics(uvr, "promote", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "promote", uvr.Name, "failed to update VolumeReplication for promotion", err)
 	}
 
 	// Wait for promotion to complete with timeout
 	if err := ca.waitForStateTransition(ctx, uvr, "source", DefaultStateTransitionTimeout); err != nil {
-		ca.completeStateTransition(transitionKey, false)
-		ca.BaseAdapter.updateMetrics("promote", false, startTime)
+		ca.completeStateTransition(uvr, transitionKey, err)
+		ca.BaseAdapter.updateMetrics(uvr, "promote", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeTimeout, translation.BackendCeph, "promote", uvr.Name, "promotion timed out", err)
 	}
 
 	// Clear cache and complete transition
 	ca.statusCache.Clear()
-	ca.completeStateTransition(transitionKey, true)
-	ca.BaseAdapter.updateMetrics("promote", true, startTime)
+	ca.completeStateTransition(uvr, transitionKey, nil)
+	ca.BaseAdapter.updateMetrics(uvr, "promote", true, startTime)
 
 	logger.Info("Successfully promoted Ceph replica to primary")
 	return nil
@@ -1009,18 +1415,18 @@ func (ca *CephAdapter) DemoteSource(ctx context.Context, uvr *replicationv1alpha
 	// Validate current state allows demotion
 	currentStatus, err := ca.GetReplicationStatus(ctx, uvr)
 	if err != nil {
-		ca.BaseAdapter.updateMetrics("demote", false, startTime)
+		ca.BaseAdapter.updateMetrics(uvr, "demote", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendCeph, "demote", uvr.Name, "failed to get current status", err)
 	}
 
 	if allowed, reason := ca.isValidStateTransition(currentStatus.State, "demoting"); !allowed {
-		ca.BaseAdapter.updateMetrics("demote", false, startTime)
+		ca.BaseAdapter.updateMetrics(uvr, "demote", false, startTime)
 		return NewAdapterError(ErrorTypeValidation, translation.BackendCeph, "demote", uvr.Name,
 			fmt.Sprintf("invalid state transition: %s", reason))
 	}
 
 	// Track the state transition
-	ca.trackStateTransition(transitionKey, currentStatus.State, "demoting")
+	ca.trackStateTransition(uvr, transitionKey, currentStatus.State, "demoting")
 
 	// Get the VolumeReplication resource
 	vr := &VolumeReplication{}
@@ -1028,38 +1434,38 @@ func (ca *CephAdapter) DemoteSource(ctx context.Context, uvr *replicationv1alpha
 		Name:      ca.buildVolumeReplicationName(uvr),
 		Namespace: uvr.Namespace,
 	}, vr); err != nil {
-		ca.completeStateTransition(transitionKey, false)
-		ca.BaseAdapter.updateMetrics("demote", false, startTime)
+		ca.completeStateTransition(uvr, transitionKey, err)
+		ca.BaseAdapter.updateMetrics(uvr, "demote", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "demote", uvr.Name, "failed to get VolumeReplication", err)
 	}
 
 	// Translate to Ceph demote state
 	cephDemoteState, _, err := ca.translateToCephState("demoting")
 	if err != nil {
-		ca.completeStateTransition(transitionKey, false)
-		ca.BaseAdapter.updateMetrics("demote", false, startTime)
+		ca.completeStateTransition(uvr, transitionKey, err)
+		ca.BaseAdapter.updateMetrics(uvr, "demote", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendCeph, "demote", uvr.Name, "failed to translate demote state", err)
 	}
 
 	// Update VolumeReplication to demote
 	vr.Spec.ReplicationState = cephDemoteState
 	if err := ca.client.Update(ctx, vr); err != nil {
-		ca.completeStateTransition(transitionKey, false)
-		ca.BaseAdapter.updateMetrics("demote", false, startTime)
+		ca.completeStateTransition(uvr, transitionKey, err)
+		ca.BaseAdapter.updateMetrics(uvr, "demote", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "demote", uvr.Name, "failed to update VolumeReplication for demotion", err)
 	}
 
 	// Wait for demotion to complete
 	if err := ca.waitForStateTransition(ctx, uvr, "replica", DefaultStateTransitionTimeout); err != nil {
-		ca.completeStateTransition(transitionKey, false)
-		ca.BaseAdapter.updateMetrics("demote", false, startTime)
+		ca.completeStateTransition(uvr, transitionKey, err)
+		ca.BaseAdapter.updateMetrics(uvr, "demote", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeTimeout, translation.BackendCeph, "demote", uvr.Name, "demotion timed out", err)
 	}
 
 	// Clear cache and complete transition
 	ca.statusCache.Clear()
-	ca.completeStateTransition(transitionKey, true)
-	ca.BaseAdapter.updateMetrics("demote", true, startTime)
+	ca.completeStateTransition(uvr, transitionKey, nil)
+	ca.BaseAdapter.updateMetrics(uvr, "demote", true, startTime)
 
 	logger.Info("Successfully demoted Ceph primary to replica")
 	return nil
@@ -1076,12 +1482,12 @@ func (ca *CephAdapter) ResyncReplication(ctx context.Context, uvr *replicationv1
 	// Get current status
 	currentStatus, err := ca.GetReplicationStatus(ctx, uvr)
 	if err != nil {
-		ca.BaseAdapter.updateMetrics("resync", false, startTime)
+		ca.BaseAdapter.updateMetrics(uvr, "resync", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendCeph, "resync", uvr.Name, "failed to get current status", err)
 	}
 
 	// Track transition to syncing state
-	ca.trackStateTransition(transitionKey, currentStatus.State, "syncing")
+	ca.trackStateTransition(uvr, transitionKey, currentStatus.State, "syncing")
 
 	// Get the VolumeReplication resource
 	vr := &VolumeReplication{}
@@ -1089,8 +1495,8 @@ func (ca *CephAdapter) ResyncReplication(ctx context.Context, uvr *replicationv1
 		Name:      ca.buildVolumeReplicationName(uvr),
 		Namespace: uvr.Namespace,
 	}, vr); err != nil {
-		ca.completeStateTransition(transitionKey, false)
-		ca.BaseAdapter.updateMetrics("resync", false, startTime)
+		ca.completeStateTransition(uvr, transitionKey, err)
+		ca.BaseAdapter.updateMetrics(uvr, "resync", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "resync", uvr.Name, "failed to get VolumeReplication", err)
 	}
 
@@ -1100,15 +1506,15 @@ func (ca *CephAdapter) ResyncReplication(ctx context.Context, uvr *replicationv1
 
 	// Update the VolumeReplication resource
 	if err := ca.client.Update(ctx, vr); err != nil {
-		ca.completeStateTransition(transitionKey, false)
-		ca.BaseAdapter.updateMetrics("resync", false, startTime)
+		ca.completeStateTransition(uvr, transitionKey, err)
+		ca.BaseAdapter.updateMetrics(uvr, "resync", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "resync", uvr.Name, "failed to update VolumeReplication for resync", err)
 	}
 
 	// Clear cache to force fresh status
 	ca.statusCache.Clear()
-	ca.completeStateTransition(transitionKey, true)
-	ca.BaseAdapter.updateMetrics("resync", true, startTime)
+	ca.completeStateTransition(uvr, transitionKey, nil)
+	ca.BaseAdapter.updateMetrics(uvr, "resync", true, startTime)
 
 	logger.Info("Successfully triggered Ceph replication resync")
 	return nil
@@ -1121,7 +1527,7 @@ func (ca *CephAdapter) waitForStateTransition(ctx context.Context, uvr *replicat
 	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	ticker := time.NewTicker(StateTransitionRetryInterval)
+	ticker := time.NewTicker(ca.transitionPollInterval)
 	defer ticker.Stop()
 
 	retries := 0
@@ -1130,9 +1536,8 @@ func (ca *CephAdapter) waitForStateTransition(ctx context.Context, uvr *replicat
 		case <-timeoutCtx.Done():
 			return fmt.Errorf("state transition to %s timed out after %v (retries: %d)", targetState, timeout, retries)
 		case <-ticker.C:
-			// Clear cache for fresh status
-			cacheKey := ca.buildStatusCacheKey(uvr)
-			ca.statusCache.Set(cacheKey, nil) // Invalidate cache
+			// Invalidate the cached status so the backend is read again
+			ca.statusCache.Delete(ca.buildStatusCacheKey(uvr))
 
 			status, err := ca.GetReplicationStatus(ctx, uvr)
 			if err != nil {
@@ -1168,7 +1573,7 @@ func (ca *CephAdapter) PauseReplication(ctx context.Context, uvr *replicationv1a
 		Name:      ca.buildVolumeReplicationName(uvr),
 		Namespace: uvr.Namespace,
 	}, vr); err != nil {
-		ca.BaseAdapter.updateMetrics("pause", false, startTime)
+		ca.BaseAdapter.updateMetrics(uvr, "pause", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "pause", uvr.Name, "failed to get VolumeReplication", err)
 	}
 
@@ -1177,12 +1582,12 @@ func (ca *CephAdapter) PauseReplication(ctx context.Context, uvr *replicationv1a
 	vr.Spec.AutoResync = &autoResync
 
 	if err := ca.client.Update(ctx, vr); err != nil {
-		ca.BaseAdapter.updateMetrics("pause", false, startTime)
+		ca.BaseAdapter.updateMetrics(uvr, "pause", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "pause", uvr.Name, "failed to pause replication", err)
 	}
 
 	ca.statusCache.Clear()
-	ca.BaseAdapter.updateMetrics("pause", true, startTime)
+	ca.BaseAdapter.updateMetrics(uvr, "pause", true, startTime)
 	logger.Info("Successfully paused Ceph replication")
 	return nil
 }
@@ -1200,7 +1605,7 @@ func (ca *CephAdapter) ResumeReplication(ctx context.Context, uvr *replicationv1
 		Name:      ca.buildVolumeReplicationName(uvr),
 		Namespace: uvr.Namespace,
 	}, vr); err != nil {
-		ca.BaseAdapter.updateMetrics("resume", false, startTime)
+		ca.BaseAdapter.updateMetrics(uvr, "resume", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "resume", uvr.Name, "failed to get VolumeReplication", err)
 	}
 
@@ -1209,12 +1614,12 @@ func (ca *CephAdapter) ResumeReplication(ctx context.Context, uvr *replicationv1
 	vr.Spec.AutoResync = &autoResync
 
 	if err := ca.client.Update(ctx, vr); err != nil {
-		ca.BaseAdapter.updateMetrics("resume", false, startTime)
+		ca.BaseAdapter.updateMetrics(uvr, "resume", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "resume", uvr.Name, "failed to resume replication", err)
 	}
 
 	ca.statusCache.Clear()
-	ca.BaseAdapter.updateMetrics("resume", true, startTime)
+	ca.BaseAdapter.updateMetrics(uvr, "resume", true, startTime)
 	logger.Info("Successfully resumed Ceph replication")
 	return nil
 }
@@ -1238,7 +1643,9 @@ func (ca *CephAdapter) FailbackReplication(ctx context.Context, uvr *replication
 	return ca.DemoteSource(ctx, uvr)
 }
 
-// RecoverFromError attempts to recover from error states
+// RecoverFromError attempts to recover from error states, trying the recovery actions least
+// destructive first. Actions that could discard the last good copy of the data are skipped,
+// and recovery escalates to manual intervention when the safe ones do not help.
 func (ca *CephAdapter) RecoverFromError(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
 	logger := log.FromContext(ctx).WithName("ceph-adapter").WithValues("uvr", uvr.Name)
 	logger.Info("Attempting recovery from error state")
@@ -1248,47 +1655,57 @@ func (ca *CephAdapter) RecoverFromError(ctx context.Context, uvr *replicationv1a
 	// Get current status to understand the error
 	status, err := ca.GetReplicationStatus(ctx, uvr)
 	if err != nil {
-		ca.BaseAdapter.updateMetrics("recover", false, startTime)
+		ca.BaseAdapter.updateMetrics(uvr, "recover", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendCeph, "recover", uvr.Name, "failed to get current status for recovery", err)
 	}
 
 	if status.Health != ReplicationHealthUnhealthy {
 		logger.Info("Replication is not in error state, no recovery needed", "health", status.Health)
-		ca.BaseAdapter.updateMetrics("recover", true, startTime)
+		ca.BaseAdapter.updateMetrics(uvr, "recover", true, startTime)
 		return nil
 	}
 
-	// Recovery strategy based on current state
-	recoveryActions := []func(context.Context, *replicationv1alpha1.UnifiedVolumeReplication) error{
-		ca.attemptResyncRecovery,
-		ca.attemptRestartRecovery,
-		ca.attemptResetRecovery,
-	}
-
-	for i, action := range recoveryActions {
-		logger.Info("Attempting recovery action", "action", i+1, "totalActions", len(recoveryActions))
+	// Try the least destructive actions first, and only those the current state allows
+	allowed, unsafeReason := assessRecoverySafety(status)
+	actions := ca.recoveryActions()
+	var skipped []string
+	for _, action := range actions {
+		if action.impact > allowed {
+			logger.Info("Skipping unsafe recovery action", "action", action.name, "impact", action.impact, "reason", unsafeReason)
+			skipped = append(skipped, action.name)
+			continue
+		}
+		logger.Info("Attempting recovery action", "action", action.name, "impact", action.impact, "totalActions", len(actions))
 
-		if err := action(ctx, uvr); err != nil {
-			logger.Error(err, "Recovery action failed", "action", i+1)
+		if err := action.run(ctx, uvr); err != nil {
+			logger.Error(err, "Recovery action failed", "action", action.name)
 			continue
 		}
 
 		// Check if recovery was successful
-		time.Sleep(StateTransitionRetryInterval)
+		time.Sleep(ca.recoverySettleDelay)
+		ca.statusCache.Delete(ca.buildStatusCacheKey(uvr))
 		newStatus, err := ca.GetReplicationStatus(ctx, uvr)
 		if err != nil {
-			logger.Error(err, "Failed to check status after recovery action", "action", i+1)
+			logger.Error(err, "Failed to check status after recovery action", "action", action.name)
 			continue
 		}
 
 		if newStatus.Health != ReplicationHealthUnhealthy {
-			logger.Info("Recovery successful", "action", i+1, "newHealth", newStatus.Health)
-			ca.BaseAdapter.updateMetrics("recover", true, startTime)
+			logger.Info("Recovery successful", "action", action.name, "newHealth", newStatus.Health)
+			ca.BaseAdapter.updateMetrics(uvr, "recover", true, startTime)
 			return nil
 		}
 	}
 
-	ca.BaseAdapter.updateMetrics("recover", false, startTime)
+	ca.BaseAdapter.updateMetrics(uvr, "recover", false, startTime)
+	if len(skipped) > 0 {
+		// Escalate rather than risk the last good copy of the data
+		recoverErr := NewAdapterError(ErrorTypeOperation, translation.BackendCeph, "recover", uvr.Name,
+			fmt.Sprintf("non-destructive recovery failed and destructive recovery (%s) is unsafe: %s", strings.Join(skipped, ", "), unsafeReason))
+		recoverErr.Suggestion = "manual intervention required: confirm which image holds the good data before resyncing or recreating the replication"
+		return recoverErr
+	}
 	return NewAdapterError(ErrorTypeOperation, translation.BackendCeph, "recover", uvr.Name, "all recovery attempts failed")
 }
 
@@ -1312,7 +1729,7 @@ func (ca *CephAdapter) attemptRestartRecovery(ctx context.Context, uvr *replicat
 	}
 
 	// Wait a bit for cleanup
-	time.Sleep(StateTransitionRetryInterval)
+	time.Sleep(ca.recoverySettleDelay)
 
 	// Recreate the resource
 	return ca.EnsureReplication(ctx, uvr)
@@ -1345,6 +1762,232 @@ func (ca *CephAdapter) attemptResetRecovery(ctx context.Context, uvr *replicatio
 	return ca.client.Update(ctx, vr)
 }
 
+// DetectPolicyDrift compares the VolumeReplicationClass parameters in effect for the
+// volume against the mirroring mode and RPO requested by the UVR
+func (ca *CephAdapter) DetectPolicyDrift(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) ([]PolicyDrift, error) {
+	vr := &VolumeReplication{}
+	if err := ca.client.Get(ctx, types.NamespacedName{
+		Name:      ca.buildVolumeReplicationName(uvr),
+		Namespace: uvr.Namespace,
+	}, vr); err != nil {
+		return nil, NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "policy-drift", uvr.Name, "failed to get VolumeReplication", err)
+	}
+
+	vrc := &unstructured.Unstructured{}
+	vrc.SetGroupVersionKind(schema.FromAPIVersionAndKind(VolumeReplicationAPIVersion, VolumeReplicationClassKind))
+	if err := ca.client.Get(ctx, types.NamespacedName{Name: vr.Spec.VolumeReplicationClass}, vrc); err != nil {
+		return nil, NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "policy-drift", uvr.Name, "failed to get VolumeReplicationClass", err)
+	}
+
+	parameters, _, _ := unstructured.NestedStringMap(vrc.Object, "spec", "parameters")
+
+	var drift []PolicyDrift
+	if uvr.Spec.Extensions != nil && uvr.Spec.Extensions.Ceph != nil && uvr.Spec.Extensions.Ceph.MirroringMode != nil {
+		if observed, ok := parameters["mirroringMode"]; ok && observed != *uvr.Spec.Extensions.Ceph.MirroringMode {
+			drift = append(drift, PolicyDrift{Field: "mirroringMode", Desired: *uvr.Spec.Extensions.Ceph.MirroringMode, Observed: observed})
+		}
+	}
+	if observed, ok := parameters["schedulingInterval"]; ok && uvr.SyncInterval() != "" && !sameSyncInterval(observed, uvr.SyncInterval()) {
+		desired, err := syncIntervalFor(uvr, translation.BackendCeph, "policy-drift")
+		if err != nil {
+			return nil, err
+		}
+		drift = append(drift, PolicyDrift{Field: "schedulingInterval", Desired: desired, Observed: observed})
+	}
+
+	return drift, nil
+}
+
+// RecreationRequired reports changes a VolumeReplication cannot take in place. csi-addons
+// treats the PVC data source and the VolumeReplicationClass as immutable, so a different
+// source volume or class means deleting and recreating the VolumeReplication.
+func (ca *CephAdapter) RecreationRequired(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) ([]PolicyDrift, error) {
+	vr := &VolumeReplication{}
+	if err := ca.client.Get(ctx, types.NamespacedName{
+		Name:      ca.buildVolumeReplicationName(uvr),
+		Namespace: uvr.Namespace,
+	}, vr); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "recreation-check", uvr.Name, "failed to get VolumeReplication", err)
+	}
+	// Another UVR's VolumeReplication must never be recreated for this one's spec
+	if err := checkResourceOwner(vr, vr.Name, uvr); err != nil {
+		return nil, NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendCeph, "recreation-check", uvr.Name, "VolumeReplication belongs to another UVR", err)
+	}
+
+	var changes []PolicyDrift
+	if desired := uvr.Spec.VolumeMapping.Source.PvcName; vr.Spec.PvcName != desired {
+		changes = append(changes, PolicyDrift{Field: "pvcName", Desired: desired, Observed: vr.Spec.PvcName})
+	}
+	if desired := defaultVolumeReplicationClass; vr.Spec.VolumeReplicationClass != desired {
+		changes = append(changes, PolicyDrift{Field: "volumeReplicationClass", Desired: desired, Observed: vr.Spec.VolumeReplicationClass})
+	}
+
+	return changes, nil
+}
+
+// AdoptReplication takes over an existing VolumeReplication. Its PVC, class and state must
+// match the spec so adopting it does not trigger an update or recreation, unless the UVR's
+// adopt conflict policy tolerates the difference; it is then labeled like the
+// VolumeReplications the adapter creates. PreferSpec tolerates only a different state: a
+// different PVC or class is refused, as re-applying it would recreate the VolumeReplication.
+func (ca *CephAdapter) AdoptReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) ([]PolicyDrift, error) {
+	startTime := time.Now()
+	name := ca.buildVolumeReplicationName(uvr)
+
+	policy, err := AdoptConflictPolicyFor(uvr)
+	if err != nil {
+		ca.BaseAdapter.updateMetrics(uvr, "adopt", false, startTime)
+		return nil, NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendCeph, "adopt", uvr.Name, "invalid adopt conflict policy", err)
+	}
+
+	vr := &VolumeReplication{}
+	if err := ca.client.Get(ctx, types.NamespacedName{Name: name, Namespace: uvr.Namespace}, vr); err != nil {
+		ca.BaseAdapter.updateMetrics(uvr, "adopt", false, startTime)
+		if errors.IsNotFound(err) {
+			return nil, NewAdapterErrorWithCause(ErrorTypeResource, translation.BackendCeph, "adopt", uvr.Name,
+				fmt.Sprintf("VolumeReplication %s/%s to adopt not found", uvr.Namespace, name), err)
+		}
+		return nil, NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "adopt", uvr.Name, "failed to get VolumeReplication", err)
+	}
+
+	cephState, _, err := ca.translateToCephState(string(uvr.Spec.ReplicationState))
+	if err != nil {
+		ca.BaseAdapter.updateMetrics(uvr, "adopt", false, startTime)
+		return nil, NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendCeph, "adopt", uvr.Name, "state translation failed", err)
+	}
+
+	resource := fmt.Sprintf("VolumeReplication %s/%s", uvr.Namespace, name)
+	if owner, ok := vr.Annotations[AdoptedByAnnotation]; ok && owner != adoptionOwner(uvr) {
+		ca.BaseAdapter.updateMetrics(uvr, "adopt", false, startTime)
+		return nil, newAdoptionConflictError(translation.BackendCeph, resource, uvr,
+			[]PolicyDrift{{Field: "adoptedBy", Desired: adoptionOwner(uvr), Observed: owner}})
+	}
+	if err := checkResourceOwner(vr, resource, uvr); err != nil {
+		ca.BaseAdapter.updateMetrics(uvr, "adopt", false, startTime)
+		return nil, NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendCeph, "adopt", uvr.Name, "VolumeReplication belongs to another UVR", err)
+	}
+
+	// The PVC and class cannot change without recreating the VolumeReplication, which would
+	// discard the replication being adopted, so PreferSpec only re-applies the state
+	var immutable []PolicyDrift
+	if desired := uvr.Spec.VolumeMapping.Source.PvcName; vr.Spec.PvcName != desired {
+		immutable = append(immutable, PolicyDrift{Field: "pvcName", Desired: desired, Observed: vr.Spec.PvcName})
+	}
+	if desired := defaultVolumeReplicationClass; vr.Spec.VolumeReplicationClass != desired {
+		immutable = append(immutable, PolicyDrift{Field: "volumeReplicationClass", Desired: desired, Observed: vr.Spec.VolumeReplicationClass})
+	}
+	conflicts := append([]PolicyDrift(nil), immutable...)
+	if vr.Spec.ReplicationState != cephState {
+		conflicts = append(conflicts, PolicyDrift{Field: "replicationState", Desired: cephState, Observed: vr.Spec.ReplicationState})
+	}
+	if len(conflicts) > 0 && policy == AdoptConflictPolicyFail {
+		ca.BaseAdapter.updateMetrics(uvr, "adopt", false, startTime)
+		return nil, newAdoptionConflictError(translation.BackendCeph, resource, uvr, conflicts)
+	}
+	if len(immutable) > 0 && policy == AdoptConflictPolicyPreferSpec {
+		ca.BaseAdapter.updateMetrics(uvr, "adopt", false, startTime)
+		return nil, newImmutableAdoptionConflictError(translation.BackendCeph, resource, uvr, immutable)
+	}
+
+	if vr.Labels["managed-by"] != "unified-replication-operator" || vr.Annotations[AdoptedByAnnotation] != adoptionOwner(uvr) ||
+		(uvr.UID != "" && vr.Labels[OwnerUIDLabel] != string(uvr.UID)) {
+		if vr.Labels == nil {
+			vr.Labels = map[string]string{}
+		}
+		vr.Labels["managed-by"] = "unified-replication-operator"
+		vr.Labels["backend"] = "ceph"
+		setResourceOwner(vr, uvr)
+		if vr.Annotations == nil {
+			vr.Annotations = map[string]string{}
+		}
+		vr.Annotations[AdoptedByAnnotation] = adoptionOwner(uvr)
+		if err := ca.client.Update(ctx, vr); err != nil {
+			ca.BaseAdapter.updateMetrics(uvr, "adopt", false, startTime)
+			return nil, NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "adopt", uvr.Name, "failed to label adopted VolumeReplication", err)
+		}
+	}
+
+	ca.BaseAdapter.updateMetrics(uvr, "adopt", true, startTime)
+	return conflicts, nil
+}
+
+// DrainWrites asks the write fencing hooks to stop writes to the primary image and flush its
+// journal, by setting DrainRequestedAnnotation on the VolumeReplication. The primary has
+// drained once the mirror reports a sync completed after the request; only then may
+// EnsureReplication demote it. That sync is marked with QuiescedSyncTimeAnnotation. When the
+// controller gives up waiting, the demotion itself flushes the image: RBD takes a final
+// mirror snapshot on demote, which the peer has to replay before it can be promoted.
+func (ca *CephAdapter) DrainWrites(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (bool, error) {
+	startTime := time.Now()
+
+	vr := &VolumeReplication{}
+	if err := ca.client.Get(ctx, types.NamespacedName{
+		Name:      ca.buildVolumeReplicationName(uvr),
+		Namespace: uvr.Namespace,
+	}, vr); err != nil {
+		ca.BaseAdapter.updateMetrics(uvr, "drain", false, startTime)
+		if errors.IsNotFound(err) {
+			return false, NewAdapterErrorWithCause(ErrorTypeResource, translation.BackendCeph, "drain", uvr.Name, "VolumeReplication not found", err)
+		}
+		return false, NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "drain", uvr.Name, "failed to get VolumeReplication", err)
+	}
+
+	// Nothing is left to drain once the image is no longer primary
+	if vr.Spec.ReplicationState != CephPrimaryState {
+		return true, nil
+	}
+
+	if _, ok := vr.Annotations[DrainRequestedAnnotation]; !ok {
+		if vr.Annotations == nil {
+			vr.Annotations = map[string]string{}
+		}
+		vr.Annotations[DrainRequestedAnnotation] = time.Now().UTC().Format(time.RFC3339Nano)
+		if err := ca.client.Update(ctx, vr); err != nil {
+			ca.BaseAdapter.updateMetrics(uvr, "drain", false, startTime)
+			return false, NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "drain", uvr.Name, "failed to request write drain", err)
+		}
+		ca.BaseAdapter.updateMetrics(uvr, "drain", true, startTime)
+		return false, nil
+	}
+
+	var lastSyncTime *time.Time
+	if vr.Status.LastSyncTime != nil {
+		lastSyncTime = &vr.Status.LastSyncTime.Time
+	}
+	if !drainCompleted(vr.Annotations, lastSyncTime) {
+		return false, nil
+	}
+
+	// The sync that completed the drain ran with writes fenced, so it is recorded as the
+	// application-consistent recovery point
+	if recordQuiescedSync(vr.Annotations, lastSyncTime) {
+		if err := ca.client.Update(ctx, vr); err != nil {
+			ca.BaseAdapter.updateMetrics(uvr, "drain", false, startTime)
+			return false, NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "drain", uvr.Name, "failed to record the quiesced sync", err)
+		}
+		ca.statusCache.Delete(ca.buildStatusCacheKey(uvr))
+	}
+	return true, nil
+}
+
+// BackendResourceExists reports whether the VolumeReplication for the UVR exists
+func (ca *CephAdapter) BackendResourceExists(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (bool, error) {
+	vr := &VolumeReplication{}
+	if err := ca.client.Get(ctx, types.NamespacedName{
+		Name:      ca.buildVolumeReplicationName(uvr),
+		Namespace: uvr.Namespace,
+	}, vr); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "recreation-check", uvr.Name, "failed to get VolumeReplication", err)
+	}
+	return true, nil
+}
+
 // IsHealthy checks if the adapter and its backend are healthy
 func (ca *CephAdapter) IsHealthy() bool {
 	ca.healthMutex.RLock()
@@ -1384,6 +2027,61 @@ func (ca *CephAdapter) performHealthCheck(ctx context.Context) error {
 	return nil
 }
 
+// EffectiveBackendConfig reports the VolumeReplicationClass used for the VolumeReplication.
+// The mirroring mode is defined by that class; the UVR's mirroringMode extension is only
+// compared against it.
+func (ca *CephAdapter) EffectiveBackendConfig(uvr *replicationv1alpha1.UnifiedVolumeReplication) (string, map[string]string) {
+	return defaultVolumeReplicationClass, nil
+}
+
+// ConfigureNativeSchedule checks that the VolumeReplicationClass of the VolumeReplication
+// has RBD schedule mirror snapshots at least as often as the RPO. The class is shared by
+// every VolumeReplication using it and csi-addons does not let a VolumeReplication change
+// class, so the schedule is the class's; delegating only stops the operator's own syncs.
+func (ca *CephAdapter) ConfigureNativeSchedule(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	rpo, err := replicationv1alpha1.ParseScheduleDuration(uvr.Spec.Schedule.Rpo)
+	if err != nil {
+		return NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendCeph, "schedule", uvr.Name, "invalid RPO", err)
+	}
+
+	className := defaultVolumeReplicationClass
+	vr := &VolumeReplication{}
+	if err := ca.client.Get(ctx, types.NamespacedName{Name: ca.buildVolumeReplicationName(uvr), Namespace: uvr.Namespace}, vr); err == nil {
+		className = vr.Spec.VolumeReplicationClass
+	} else if !errors.IsNotFound(err) {
+		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "schedule", uvr.Name, "failed to get VolumeReplication", err)
+	}
+
+	vrc := &unstructured.Unstructured{}
+	vrc.SetGroupVersionKind(schema.FromAPIVersionAndKind(VolumeReplicationAPIVersion, VolumeReplicationClassKind))
+	if err := ca.client.Get(ctx, types.NamespacedName{Name: className}, vrc); err != nil {
+		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "schedule", uvr.Name,
+			fmt.Sprintf("failed to get VolumeReplicationClass %s", className), err)
+	}
+	interval, _, _ := unstructured.NestedString(vrc.Object, "spec", "parameters", "schedulingInterval")
+	if interval == "" {
+		return NewAdapterError(ErrorTypeValidation, translation.BackendCeph, "schedule", uvr.Name,
+			fmt.Sprintf("VolumeReplicationClass %s sets no schedulingInterval", className))
+	}
+	scheduled, err := replicationv1alpha1.ParseScheduleDuration(interval)
+	if err != nil {
+		return NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendCeph, "schedule", uvr.Name,
+			fmt.Sprintf("VolumeReplicationClass %s has an invalid schedulingInterval", className), err)
+	}
+	if scheduled > rpo {
+		return NewAdapterError(ErrorTypeValidation, translation.BackendCeph, "schedule", uvr.Name,
+			fmt.Sprintf("VolumeReplicationClass %s schedules mirror snapshots every %s, less often than the RPO %s",
+				className, interval, uvr.Spec.Schedule.Rpo))
+	}
+	return nil
+}
+
+// VerifyReplicaReadable always reports the replica as unreadable: RBD mirroring keeps
+// non-primary images locked and ceph-csi refuses to stage them until they are promoted
+func (ca *CephAdapter) VerifyReplicaReadable(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (bool, string, error) {
+	return false, "non-primary RBD images cannot be mounted until promoted", nil
+}
+
 // GetVersion returns the adapter version
 func (ca *CephAdapter) GetVersion() string {
 	return "v1.0.0-ceph"
@@ -1401,6 +2099,7 @@ func (ca *CephAdapter) GetSupportedFeatures() []AdapterFeature {
 		FeaturePauseResume,
 		FeatureAutoResync,
 		FeatureSnapshotBased,
+		FeatureVolumeProvisioning,
 		FeatureMetrics,
 		FeatureProgressTracking,
 		FeatureRealTimeStatus,
@@ -1433,11 +2132,6 @@ func (ca *CephAdapter) Cleanup(ctx context.Context) error {
 	// Clear caches
 	ca.statusCache.Clear()
 
-	// Clear active transitions
-	ca.transitionMutex.Lock()
-	ca.activeTransitions = make(map[string]*StateTransition)
-	ca.transitionMutex.Unlock()
-
 	// Cleanup base adapter
 	return ca.BaseAdapter.Cleanup(ctx)
 }
@@ -1472,7 +2166,7 @@ func (ca *CephAdapter) Reconcile(ctx context.Context, uvr *replicationv1alpha1.U
 	if transition, exists := ca.getActiveStateTransition(transitionKey); exists {
 		if !transition.Allowed {
 			logger.Error(nil, "Invalid state transition detected", "transition", transition)
-			ca.completeStateTransition(transitionKey, false)
+			ca.completeStateTransition(uvr, transitionKey, fmt.Errorf("invalid state transition: %s", transition.Reason))
 		}
 	}
 
@@ -1509,7 +2203,7 @@ func (f *CephAdapterFactory) CreateAdapter(backend translation.Backend, client c
 		return nil, fmt.Errorf("translator is required for Ceph adapter")
 	}
 
-	return NewCephAdapter(client, translator)
+	return NewCephAdapterWithConfig(client, translator, config)
 }
 
 // GetBackendType returns the backend type this factory supports
@@ -1541,7 +2235,7 @@ func (f *CephAdapterFactory) ValidateConfig(config *AdapterConfig) error {
 		return fmt.Errorf("retry attempts cannot be negative")
 	}
 
-	return nil
+	return validateStatusCacheConfig(config)
 }
 
 // Supports returns whether this factory supports the given configuration
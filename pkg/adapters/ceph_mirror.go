@@ -0,0 +1,296 @@
+// Copyright 2024 unified-replication-operator contributors.
+// Licensed under the Apache License, Version 2.0.
+
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+const (
+	// CephBlockPoolAPIVersion and CephBlockPoolKind identify the Rook pool that reports the
+	// rbd-mirror status of its images
+	CephBlockPoolAPIVersion = "ceph.rook.io/v1"
+	CephBlockPoolKind       = "CephBlockPool"
+
+	// CephMirrorStatusKey is the ReplicationStatus.BackendSpecific key holding the
+	// *CephMirrorStatus of the pool backing the volume
+	CephMirrorStatusKey = "mirror_status"
+)
+
+// CephMirrorPeerState describes whether the pool's rbd-mirror peer is reachable
+type CephMirrorPeerState string
+
+const (
+	// CephMirrorPeerConnected indicates a peer's rbd-mirror daemon has connected to the pool
+	CephMirrorPeerConnected CephMirrorPeerState = "Connected"
+	// CephMirrorPeerNotConnected indicates peers are configured but none has connected yet
+	CephMirrorPeerNotConnected CephMirrorPeerState = "NotConnected"
+	// CephMirrorPeerMissing indicates the pool has no mirror peer, so nothing is replicated
+	CephMirrorPeerMissing CephMirrorPeerState = "NoPeer"
+)
+
+// CephMirrorHealth is a health reported by rbd-mirror: OK, WARNING or ERROR
+type CephMirrorHealth string
+
+const (
+	// CephMirrorHealthOK indicates rbd-mirror reports no problem
+	CephMirrorHealthOK CephMirrorHealth = "OK"
+	// CephMirrorHealthWarning indicates mirroring works but needs attention, e.g. a lagging image
+	CephMirrorHealthWarning CephMirrorHealth = "WARNING"
+	// CephMirrorHealthError indicates mirroring has stopped for some or all images
+	CephMirrorHealthError CephMirrorHealth = "ERROR"
+)
+
+// CephMirrorPeer is one rbd-mirror peer of the pool
+type CephMirrorPeer struct {
+	UUID      string `json:"uuid"`
+	SiteName  string `json:"site_name,omitempty"`
+	Direction string `json:"direction,omitempty"`
+	// MirrorUUID is set once the peer's rbd-mirror daemon has connected
+	MirrorUUID string `json:"mirror_uuid,omitempty"`
+}
+
+// CephMirrorStatus is the rbd-mirror status of a volume: that of the pool backing it, as
+// reported by the Rook CephBlockPool, and that of its own image
+type CephMirrorStatus struct {
+	Pool      string              `json:"pool"`
+	PeerState CephMirrorPeerState `json:"peer_state"`
+	Peers     []CephMirrorPeer    `json:"peers,omitempty"`
+
+	// SiteName is the rbd-mirror site name of the local cluster, its fsid unless Rook was
+	// given another
+	SiteName string `json:"site_name,omitempty"`
+
+	// Health is the overall mirroring health of the pool, DaemonHealth that of the
+	// rbd-mirror daemons and ImageHealth that of all mirrored images of the pool
+	Health       CephMirrorHealth `json:"health,omitempty"`
+	DaemonHealth CephMirrorHealth `json:"daemon_health,omitempty"`
+	ImageHealth  CephMirrorHealth `json:"image_health,omitempty"`
+
+	// ImageStates counts the mirrored images of the pool per mirroring state, e.g.
+	// replaying, syncing, stopped or error
+	ImageStates map[string]int64 `json:"image_states,omitempty"`
+
+	// Image is the RBD image backing the volume, empty until its PV is bound
+	Image string `json:"image,omitempty"`
+
+	// ImageState is the mirroring state of the volume's own image, as the csi-addons sidecar
+	// reports it on the VolumeReplication: Replaying, Resyncing or Degraded
+	ImageState string `json:"image_state,omitempty"`
+}
+
+// ReplicationHealth aggregates the peer and daemon status, which every image of the pool
+// depends on, into a replication health, with a message describing what is wrong. The health
+// and states of the pool's images are left out: they cover other volumes too, and the
+// volume's own image is judged from the VolumeReplication conditions.
+func (s *CephMirrorStatus) ReplicationHealth() (ReplicationHealth, string) {
+	var unhealthy, degraded []string
+	switch s.PeerState {
+	case CephMirrorPeerMissing:
+		unhealthy = append(unhealthy, fmt.Sprintf("pool %s has no rbd-mirror peer", s.Pool))
+	case CephMirrorPeerNotConnected:
+		unhealthy = append(unhealthy, fmt.Sprintf("rbd-mirror peer of pool %s is not connected", s.Pool))
+	}
+	switch s.DaemonHealth {
+	case CephMirrorHealthError:
+		unhealthy = append(unhealthy, "rbd-mirror daemon health is ERROR")
+	case CephMirrorHealthWarning:
+		degraded = append(degraded, "rbd-mirror daemon health is WARNING")
+	}
+
+	switch {
+	case len(unhealthy) > 0:
+		return ReplicationHealthUnhealthy, strings.Join(append(unhealthy, degraded...), "; ")
+	case len(degraded) > 0:
+		return ReplicationHealthDegraded, strings.Join(degraded, "; ")
+	default:
+		return ReplicationHealthHealthy, ""
+	}
+}
+
+// GetMirrorPeerStatus reads the rbd-mirror status of the pool behind the UVR's storage class
+// from its Rook CephBlockPool. The pool is the storage class's pool parameter and the
+// CephBlockPool lives in the namespace named by its clusterID, as Rook sets them up. It
+// returns nil when the pool is not managed by Rook or Rook has not reported its mirroring
+// status yet.
+func (ca *CephAdapter) GetMirrorPeerStatus(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (*CephMirrorStatus, error) {
+	storageClass := &storagev1.StorageClass{}
+	if err := ca.client.Get(ctx, types.NamespacedName{Name: uvr.Spec.SourceEndpoint.StorageClass}, storageClass); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "mirror-status", uvr.Name, "failed to get StorageClass", err)
+	}
+	pool, namespace := storageClass.Parameters["pool"], storageClass.Parameters["clusterID"]
+	if pool == "" || namespace == "" {
+		return nil, nil
+	}
+
+	blockPool := &unstructured.Unstructured{}
+	blockPool.SetGroupVersionKind(schema.FromAPIVersionAndKind(CephBlockPoolAPIVersion, CephBlockPoolKind))
+	if err := ca.client.Get(ctx, types.NamespacedName{Name: pool, Namespace: namespace}, blockPool); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendCeph, "mirror-status", uvr.Name, "failed to get CephBlockPool", err)
+	}
+	status := parseCephMirrorStatus(pool, blockPool)
+	if status != nil {
+		status.Image = ca.rbdImageName(ctx, uvr)
+	}
+	return status, nil
+}
+
+// rbdImageName returns the RBD image behind the UVR's source PVC, which Ceph CSI records in
+// the imageName attribute of the PV; empty when it cannot be read
+func (ca *CephAdapter) rbdImageName(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) string {
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := ca.client.Get(ctx, types.NamespacedName{Name: uvr.Spec.VolumeMapping.Source.PvcName, Namespace: uvr.Namespace}, pvc); err != nil || pvc.Spec.VolumeName == "" {
+		return ""
+	}
+	pv := &corev1.PersistentVolume{}
+	if err := ca.client.Get(ctx, types.NamespacedName{Name: pvc.Spec.VolumeName}, pv); err != nil || pv.Spec.CSI == nil {
+		return ""
+	}
+	return pv.Spec.CSI.VolumeAttributes["imageName"]
+}
+
+// cephImageState reads the mirroring state of a volume's image from the VolumeReplication
+// conditions, which the csi-addons sidecar sets from the image's rbd-mirror status; empty
+// before it has reported any
+func cephImageState(conditions []metav1.Condition) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	if condition := meta.FindStatusCondition(conditions, "Resyncing"); condition != nil && condition.Status == metav1.ConditionTrue {
+		return "Resyncing"
+	}
+	if condition := meta.FindStatusCondition(conditions, "Degraded"); condition != nil && condition.Status == metav1.ConditionTrue {
+		return "Degraded"
+	}
+	return "Replaying"
+}
+
+// parseCephMirrorStatus reads the mirroringInfo and mirroringStatus Rook reports on a
+// CephBlockPool, nil when it reports neither
+func parseCephMirrorStatus(pool string, blockPool *unstructured.Unstructured) *CephMirrorStatus {
+	summary, hasSummary, _ := unstructured.NestedMap(blockPool.Object, "status", "mirroringStatus", "summary")
+	peers, hasPeers, _ := unstructured.NestedSlice(blockPool.Object, "status", "mirroringInfo", "peers")
+	if !hasSummary && !hasPeers {
+		return nil
+	}
+
+	status := &CephMirrorStatus{Pool: pool, PeerState: CephMirrorPeerMissing}
+	status.SiteName, _, _ = unstructured.NestedString(blockPool.Object, "status", "mirroringInfo", "site_name")
+	for _, p := range peers {
+		fields, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		peer := CephMirrorPeer{}
+		peer.UUID, _, _ = unstructured.NestedString(fields, "uuid")
+		peer.SiteName, _, _ = unstructured.NestedString(fields, "site_name")
+		peer.Direction, _, _ = unstructured.NestedString(fields, "direction")
+		peer.MirrorUUID, _, _ = unstructured.NestedString(fields, "mirror_uuid")
+		status.Peers = append(status.Peers, peer)
+
+		if peer.MirrorUUID != "" {
+			status.PeerState = CephMirrorPeerConnected
+		} else if status.PeerState == CephMirrorPeerMissing {
+			status.PeerState = CephMirrorPeerNotConnected
+		}
+	}
+	sort.Slice(status.Peers, func(i, j int) bool { return status.Peers[i].UUID < status.Peers[j].UUID })
+
+	health, _, _ := unstructured.NestedString(summary, "health")
+	daemonHealth, _, _ := unstructured.NestedString(summary, "daemon_health")
+	imageHealth, _, _ := unstructured.NestedString(summary, "image_health")
+	status.Health = CephMirrorHealth(strings.ToUpper(health))
+	status.DaemonHealth = CephMirrorHealth(strings.ToUpper(daemonHealth))
+	status.ImageHealth = CephMirrorHealth(strings.ToUpper(imageHealth))
+
+	if states, ok := summary["states"].(map[string]interface{}); ok {
+		status.ImageStates = make(map[string]int64, len(states))
+		for state, count := range states {
+			switch n := count.(type) {
+			case int64:
+				status.ImageStates[state] = n
+			case float64:
+				status.ImageStates[state] = int64(n)
+			}
+		}
+	}
+	return status
+}
+
+// cephPrimaryIdentity names the cluster holding the primary image from the rbd-mirror site
+// names: the local site when the local image is, or is becoming, primary and the peer's site
+// when it is, or is becoming, secondary. rbd-mirror identifies clusters by site, so the name
+// is both the cluster and the site. Empty values are returned when the state does not
+// identify a primary, Rook has not reported the site names or the pool has several peers.
+func cephPrimaryIdentity(mirror *CephMirrorStatus, unifiedState string) (string, string) {
+	if mirror == nil {
+		return "", ""
+	}
+	var site string
+	switch unifiedState {
+	case string(replicationv1alpha1.ReplicationStateSource), string(replicationv1alpha1.ReplicationStatePromoting):
+		site = mirror.SiteName
+	case string(replicationv1alpha1.ReplicationStateReplica), string(replicationv1alpha1.ReplicationStateDemoting):
+		if len(mirror.Peers) == 1 {
+			site = mirror.Peers[0].SiteName
+		}
+	}
+	return site, site
+}
+
+// applyMirrorStatus records the pool's mirror status in the backend-specific information and
+// lowers the health when rbd-mirror reports a worse one than the VolumeReplication
+func applyMirrorStatus(status *ReplicationStatus, mirror *CephMirrorStatus) {
+	if status.BackendSpecific == nil {
+		status.BackendSpecific = make(map[string]interface{})
+	}
+	status.BackendSpecific[CephMirrorStatusKey] = mirror
+
+	health, message := mirror.ReplicationHealth()
+	if health == ReplicationHealthHealthy {
+		return
+	}
+	if mirrorHealthSeverity(health) > mirrorHealthSeverity(status.Health) {
+		status.Health = health
+		status.DegradedReason = DegradedReasonMirrorUnhealthy
+	}
+	if status.Message != "" {
+		message = status.Message + "; " + message
+	}
+	status.Message = message
+}
+
+// mirrorHealthSeverity orders healths from best to worst; Unknown ranks with Healthy so a
+// known mirror problem takes precedence over a VolumeReplication without conditions
+func mirrorHealthSeverity(health ReplicationHealth) int {
+	switch health {
+	case ReplicationHealthDegraded:
+		return 1
+	case ReplicationHealthUnhealthy:
+		return 2
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 unified-replication-operator contributors.
+// Licensed under the Apache License, Version 2.0.
+
+package adapters
+
+import (
+	"context"
+	"fmt"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+// RecoveryImpact ranks what a recovery action can lose, from nothing to a whole copy of
+// the data
+type RecoveryImpact int
+
+const (
+	// RecoveryImpactNone re-applies the desired state without touching data or roles
+	RecoveryImpactNone RecoveryImpact = iota
+	// RecoveryImpactResync lets the mirror daemon discard the replica's data and copy it
+	// again from the primary
+	RecoveryImpactResync
+	// RecoveryImpactDemote demotes the local image, so the peer's data overwrites it
+	RecoveryImpactDemote
+	// RecoveryImpactRecreate deletes and recreates the VolumeReplication; disabling
+	// mirroring deletes a non-primary image and drops the peer's copy of a primary one
+	RecoveryImpactRecreate
+)
+
+// String returns the name of the impact
+func (i RecoveryImpact) String() string {
+	switch i {
+	case RecoveryImpactNone:
+		return "None"
+	case RecoveryImpactResync:
+		return "Resync"
+	case RecoveryImpactDemote:
+		return "Demote"
+	case RecoveryImpactRecreate:
+		return "Recreate"
+	default:
+		return fmt.Sprintf("RecoveryImpact(%d)", int(i))
+	}
+}
+
+// Destructive reports whether an action of this impact can discard data
+func (i RecoveryImpact) Destructive() bool {
+	return i > RecoveryImpactNone
+}
+
+// cephRecoveryAction is a recovery action together with what it can lose
+type cephRecoveryAction struct {
+	name   string
+	impact RecoveryImpact
+	run    func(context.Context, *replicationv1alpha1.UnifiedVolumeReplication) error
+}
+
+// recoveryActions lists the recovery actions least destructive first
+func (ca *CephAdapter) recoveryActions() []cephRecoveryAction {
+	return []cephRecoveryAction{
+		{name: "reapply", impact: RecoveryImpactNone, run: ca.attemptReapplyRecovery},
+		{name: "resync", impact: RecoveryImpactResync, run: ca.attemptResyncRecovery},
+		{name: "reset", impact: RecoveryImpactDemote, run: ca.attemptResetRecovery},
+		{name: "restart", impact: RecoveryImpactRecreate, run: ca.attemptRestartRecovery},
+	}
+}
+
+// assessRecoverySafety returns the most destructive recovery the status allows, with the
+// reason when that rules out any destructive action. The local image may be the last good
+// copy when the peer is gone or both images claim the primary role, and a primary image
+// must not be demoted under a replica that may be behind it. It fails closed: without the
+// rbd-mirror peer state or the role of the local image, no destructive action is allowed.
+func assessRecoverySafety(status *ReplicationStatus) (RecoveryImpact, string) {
+	if status.DualPrimary {
+		return RecoveryImpactNone, "both images claim the primary role (split-brain), so either may hold the only good data"
+	}
+	mirror, ok := status.BackendSpecific[CephMirrorStatusKey].(*CephMirrorStatus)
+	if !ok || mirror == nil {
+		return RecoveryImpactNone, "the rbd-mirror peer state is unknown, so the local image may be the last good copy"
+	}
+	if mirror.PeerState != CephMirrorPeerConnected {
+		return RecoveryImpactNone, fmt.Sprintf("the rbd-mirror peer of pool %s is %s, so the local image may be the last good copy",
+			mirror.Pool, mirror.PeerState)
+	}
+	switch status.Direction {
+	case ReplicationDirectionForward:
+		return RecoveryImpactResync, "the local image is the primary, and the replica may be behind it"
+	case ReplicationDirectionReverse:
+		return RecoveryImpactRecreate, ""
+	}
+	return RecoveryImpactNone, "the role of the local image is unknown, so it may be the last good copy"
+}
+
+// attemptReapplyRecovery tries to recover by re-applying the desired VolumeReplication spec
+func (ca *CephAdapter) attemptReapplyRecovery(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	return ca.EnsureReplication(ctx, uvr)
+}
@@ -0,0 +1,189 @@
+// Copyright 2024 unified-replication-operator contributors.
+// Licensed under the Apache License, Version 2.0.
+
+package adapters
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+func TestAssessRecoverySafety(t *testing.T) {
+	mirror := func(state CephMirrorPeerState) map[string]interface{} {
+		return map[string]interface{}{CephMirrorStatusKey: &CephMirrorStatus{Pool: "replicapool", PeerState: state}}
+	}
+
+	tests := []struct {
+		name    string
+		status  *ReplicationStatus
+		allowed RecoveryImpact
+		reason  string
+	}{
+		{
+			name:    "ReplicaWithConnectedPeer",
+			status:  &ReplicationStatus{Direction: ReplicationDirectionReverse, BackendSpecific: mirror(CephMirrorPeerConnected)},
+			allowed: RecoveryImpactRecreate,
+		},
+		{
+			name:    "MirrorStatusUnknown",
+			status:  &ReplicationStatus{Direction: ReplicationDirectionReverse},
+			allowed: RecoveryImpactNone,
+			reason:  "the rbd-mirror peer state is unknown, so the local image may be the last good copy",
+		},
+		{
+			name:    "RoleUnknown",
+			status:  &ReplicationStatus{BackendSpecific: mirror(CephMirrorPeerConnected)},
+			allowed: RecoveryImpactNone,
+			reason:  "the role of the local image is unknown, so it may be the last good copy",
+		},
+		{
+			name:    "SourceLost",
+			status:  &ReplicationStatus{Direction: ReplicationDirectionReverse, BackendSpecific: mirror(CephMirrorPeerNotConnected)},
+			allowed: RecoveryImpactNone,
+			reason:  "the rbd-mirror peer of pool replicapool is NotConnected, so the local image may be the last good copy",
+		},
+		{
+			name:    "NoPeer",
+			status:  &ReplicationStatus{Direction: ReplicationDirectionForward, BackendSpecific: mirror(CephMirrorPeerMissing)},
+			allowed: RecoveryImpactNone,
+			reason:  "the rbd-mirror peer of pool replicapool is NoPeer, so the local image may be the last good copy",
+		},
+		{
+			name:    "SplitBrain",
+			status:  &ReplicationStatus{DualPrimary: true, BackendSpecific: mirror(CephMirrorPeerConnected)},
+			allowed: RecoveryImpactNone,
+			reason:  "both images claim the primary role (split-brain), so either may hold the only good data",
+		},
+		{
+			name:    "Primary",
+			status:  &ReplicationStatus{Direction: ReplicationDirectionForward, BackendSpecific: mirror(CephMirrorPeerConnected)},
+			allowed: RecoveryImpactResync,
+			reason:  "the local image is the primary, and the replica may be behind it",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, reason := assessRecoverySafety(tt.status)
+			assert.Equal(t, tt.allowed, allowed)
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+}
+
+func TestCephAdapter_RecoveryActionsOrdered(t *testing.T) {
+	adapter, err := NewCephAdapter(fake.NewClientBuilder().WithScheme(newCephTestScheme()).Build(), translation.NewEngine())
+	require.NoError(t, err)
+
+	var names []string
+	actions := adapter.recoveryActions()
+	for i, action := range actions {
+		names = append(names, action.name)
+		if i > 0 {
+			assert.Greater(t, action.impact, actions[i-1].impact, "%s is more destructive than %s", action.name, actions[i-1].name)
+		}
+	}
+	assert.Equal(t, []string{"reapply", "resync", "reset", "restart"}, names)
+	assert.False(t, actions[0].impact.Destructive(), "the first action is safe on any copy")
+}
+
+func TestCephAdapter_RecoverFromErrorSafetyGates(t *testing.T) {
+	connected := []interface{}{map[string]interface{}{"uuid": "b1", "site_name": "dr-site", "mirror_uuid": "m1"}}
+	healthy := map[string]interface{}{"health": "OK", "daemon_health": "OK", "image_health": "OK"}
+
+	tests := []struct {
+		name  string
+		state replicationv1alpha1.ReplicationState
+		peers []interface{}
+		// recovered is set when the restart may run and recreates the VolumeReplication
+		recovered bool
+		message   string
+		// pool overrides the pool the storage class points to
+		pool string
+	}{
+		{
+			name:      "ReplicaWithConnectedPeer",
+			state:     replicationv1alpha1.ReplicationStateReplica,
+			peers:     connected,
+			recovered: true,
+		},
+		{
+			name:    "SourceLost",
+			state:   replicationv1alpha1.ReplicationStateReplica,
+			message: "non-destructive recovery failed and destructive recovery (resync, reset, restart) is unsafe",
+		},
+		{
+			name:    "MirrorStatusUnavailable",
+			state:   replicationv1alpha1.ReplicationStateReplica,
+			peers:   connected,
+			pool:    "missing-pool",
+			message: "non-destructive recovery failed and destructive recovery (resync, reset, restart) is unsafe",
+		},
+		{
+			name:    "Primary",
+			state:   replicationv1alpha1.ReplicationStateSource,
+			peers:   connected,
+			message: "non-destructive recovery failed and destructive recovery (reset, restart) is unsafe",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			uvr := createUnifiedVolumeReplication()
+			uvr.Spec.ReplicationState = tt.state
+
+			adapter, err := NewCephAdapter(fake.NewClientBuilder().WithScheme(newCephTestScheme()).Build(), translation.NewEngine())
+			require.NoError(t, err)
+			adapter.recoverySettleDelay = 0
+			cephState, _, err := adapter.translateToCephState(string(tt.state))
+			require.NoError(t, err)
+
+			storageClass := &storagev1.StorageClass{
+				ObjectMeta:  metav1.ObjectMeta{Name: "ceph-rbd"},
+				Provisioner: "rook-ceph.rbd.csi.ceph.com",
+				Parameters:  map[string]string{"pool": "replicapool", "clusterID": "rook-ceph"},
+			}
+			if tt.pool != "" {
+				storageClass.Parameters["pool"] = tt.pool
+			}
+			vr := &VolumeReplication{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-uvr-vr", Namespace: "default"},
+				Spec:       VolumeReplicationSpec{PvcName: "test-pvc", ReplicationState: cephState},
+				Status: VolumeReplicationStatus{
+					Conditions: []metav1.Condition{{Type: "Error", Status: metav1.ConditionTrue, Message: "image is not replaying"}},
+				},
+			}
+			setResourceOwner(vr, uvr)
+			c := fake.NewClientBuilder().WithScheme(newCephTestScheme()).
+				WithObjects(storageClass, vr, newCephBlockPool(tt.peers, healthy)).Build()
+			adapter.client = c
+
+			err = adapter.RecoverFromError(ctx, uvr)
+
+			current := &VolumeReplication{}
+			require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "test-uvr-vr", Namespace: "default"}, current))
+			if tt.recovered {
+				require.NoError(t, err)
+				assert.Empty(t, current.Status.Conditions, "the VolumeReplication was recreated")
+				return
+			}
+
+			require.Error(t, err)
+			adapterErr, ok := GetAdapterError(err)
+			require.True(t, ok)
+			assert.Contains(t, adapterErr.Message, tt.message)
+			assert.Contains(t, adapterErr.Suggestion, "manual intervention required")
+			assert.NotEmpty(t, current.Status.Conditions, "the VolumeReplication was not recreated")
+			assert.Equal(t, cephState, current.Spec.ReplicationState, "the image was not demoted")
+		})
+	}
+}
@@ -4,15 +4,25 @@
 package adapters
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
 	"github.com/unified-replication/operator/pkg/translation"
@@ -147,3 +157,937 @@ func TestCephAdapterFactory(t *testing.T) {
 		assert.False(t, supported)
 	})
 }
+
+func TestCephAdapter_StatusCacheConfig(t *testing.T) {
+	client := fake.NewClientBuilder().WithScheme(newCephTestScheme()).Build()
+	translator := translation.NewEngine()
+
+	t.Run("Defaults", func(t *testing.T) {
+		adapter, err := NewCephAdapter(client, translator)
+		require.NoError(t, err)
+		assert.Equal(t, StatusCacheTTL, adapter.statusCache.TTL())
+		assert.Equal(t, StatusCacheMaxSize, adapter.statusCache.MaxSize())
+	})
+
+	t.Run("CustomSettings", func(t *testing.T) {
+		config := DefaultAdapterConfig(translation.BackendCeph)
+		config.StatusCacheTTL = 5 * time.Second
+		config.StatusCacheMaxSize = 2
+
+		adapter, err := NewCephAdapterWithConfig(client, translator, config)
+		require.NoError(t, err)
+		assert.Equal(t, 5*time.Second, adapter.statusCache.TTL())
+		assert.Equal(t, 2, adapter.statusCache.MaxSize())
+
+		// The configured size bounds the cache
+		for _, key := range []string{"a", "b", "c"} {
+			adapter.statusCache.Set(key, &ReplicationStatus{State: "source"})
+		}
+		assert.Len(t, adapter.statusCache.cache, 2)
+
+		// The factory honors the same settings
+		created, err := NewCephAdapterFactory().CreateAdapter(translation.BackendCeph, client, translator, config)
+		require.NoError(t, err)
+		assert.Equal(t, 5*time.Second, created.(*CephAdapter).statusCache.TTL())
+	})
+
+	t.Run("Bounds", func(t *testing.T) {
+		config := DefaultAdapterConfig(translation.BackendCeph)
+		config.StatusCacheTTL = 10 * time.Millisecond
+		_, err := NewCephAdapterWithConfig(client, translator, config)
+		assert.Error(t, err)
+		assert.Error(t, NewCephAdapterFactory().ValidateConfig(config))
+
+		config = DefaultAdapterConfig(translation.BackendCeph)
+		config.StatusCacheMaxSize = MaxStatusCacheMaxSize + 1
+		_, err = NewCephAdapterWithConfig(client, translator, config)
+		assert.Error(t, err)
+
+		config = DefaultAdapterConfig(translation.BackendCeph)
+		config.StatusCacheTTL = 2 * time.Hour
+		assert.Error(t, NewCephAdapterFactory().ValidateConfig(config))
+	})
+}
+
+func TestStatusCache_Invalidation(t *testing.T) {
+	cache := NewStatusCache(time.Minute)
+	status := &ReplicationStatus{State: "source"}
+
+	cache.Set("db", status)
+	cached, found := cache.Get("db")
+	assert.True(t, found)
+	assert.Same(t, status, cached)
+
+	// Setting nil invalidates instead of caching a nil status as a hit
+	cache.Set("db", nil)
+	cached, found = cache.Get("db")
+	assert.False(t, found)
+	assert.Nil(t, cached)
+	assert.NotContains(t, cache.cache, "db")
+
+	cache.Set("db", status)
+	cache.Set("web", status)
+	cache.Delete("db")
+	_, found = cache.Get("db")
+	assert.False(t, found)
+	assert.NotContains(t, cache.cache, "db")
+	_, found = cache.Get("web")
+	assert.True(t, found, "other entries are kept")
+	cache.Delete("missing")
+
+	// An entry stored with a nil status directly is not a hit either
+	cache.cache["legacy"] = &CachedStatus{Timestamp: time.Now()}
+	_, found = cache.Get("legacy")
+	assert.False(t, found)
+}
+
+func TestCephAdapter_DegradedReason(t *testing.T) {
+	adapter, err := NewCephAdapter(fake.NewClientBuilder().WithScheme(newCephTestScheme()).Build(), translation.NewEngine())
+	require.NoError(t, err)
+
+	condition := func(condType string, status metav1.ConditionStatus) metav1.Condition {
+		return metav1.Condition{Type: condType, Status: status, Message: condType}
+	}
+
+	tests := []struct {
+		name       string
+		conditions []metav1.Condition
+		health     ReplicationHealth
+		reason     DegradedReason
+	}{
+		{
+			name:       "Healthy",
+			conditions: []metav1.Condition{condition("Healthy", metav1.ConditionTrue)},
+			health:     ReplicationHealthHealthy,
+		},
+		{
+			name:       "DegradedCondition",
+			conditions: []metav1.Condition{condition("Degraded", metav1.ConditionTrue)},
+			health:     ReplicationHealthDegraded,
+			reason:     DegradedReasonBackendDegraded,
+		},
+		{
+			name:       "NotHealthy",
+			conditions: []metav1.Condition{condition("Healthy", metav1.ConditionFalse)},
+			health:     ReplicationHealthDegraded,
+			reason:     DegradedReasonBackendDegraded,
+		},
+		{
+			name:       "Resyncing",
+			conditions: []metav1.Condition{condition("Degraded", metav1.ConditionTrue), condition("Resyncing", metav1.ConditionTrue)},
+			health:     ReplicationHealthDegraded,
+			reason:     DegradedReasonResyncing,
+		},
+		{
+			name:       "Error",
+			conditions: []metav1.Condition{condition("Error", metav1.ConditionTrue)},
+			health:     ReplicationHealthUnhealthy,
+			reason:     DegradedReasonBackendError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			health, reason, _ := adapter.analyzeVolumeReplicationConditions(tt.conditions)
+			assert.Equal(t, tt.health, health)
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+}
+
+func TestCephAdapter_SyncProgress(t *testing.T) {
+	adapter, err := NewCephAdapter(fake.NewClientBuilder().WithScheme(newCephTestScheme()).Build(), translation.NewEngine())
+	require.NoError(t, err)
+
+	resyncing := func(message string) []metav1.Condition {
+		return []metav1.Condition{{Type: "Resyncing", Status: metav1.ConditionTrue, Message: message}}
+	}
+
+	tests := []struct {
+		name     string
+		status   VolumeReplicationStatus
+		expected SyncProgress
+	}{
+		{
+			name:     "PercentAndBytes",
+			status:   VolumeReplicationStatus{State: "resync-promote", Conditions: resyncing("resyncing 45% (123456/1000000 bytes)")},
+			expected: SyncProgress{TotalBytes: 1000000, SyncedBytes: 123456, PercentComplete: 45},
+		},
+		{
+			name:     "BytesOnly",
+			status:   VolumeReplicationStatus{State: "resync-promote", Message: "replaying 250000/1000000 bytes"},
+			expected: SyncProgress{TotalBytes: 1000000, SyncedBytes: 250000, PercentComplete: 25},
+		},
+		{
+			name: "SnapshotDescription",
+			status: VolumeReplicationStatus{State: CephSecondaryState,
+				Message: `replaying, {"bytes_per_snapshot":2048.0,"syncing_percent":50}`},
+			expected: SyncProgress{TotalBytes: 2048, SyncedBytes: 1024, PercentComplete: 50},
+		},
+		{
+			name:     "JournalCaughtUp",
+			status:   VolumeReplicationStatus{State: CephSecondaryState, Message: "replaying, entries_behind_primary=0"},
+			expected: SyncProgress{PercentComplete: 100},
+		},
+		{
+			name:     "JournalBehindOnly",
+			status:   VolumeReplicationStatus{State: CephSecondaryState, Message: "replaying, entries_behind_primary=12"},
+			expected: SyncProgress{TotalBytes: 100, SyncedBytes: 100},
+		},
+		{
+			name:     "SnapshotSizeOnly",
+			status:   VolumeReplicationStatus{State: CephSecondaryState, Message: `replaying, {"bytes_per_snapshot":2048.0}`},
+			expected: SyncProgress{TotalBytes: 100, SyncedBytes: 100},
+		},
+		{
+			name: "PercentOutsideResyncing",
+			status: VolumeReplicationStatus{State: CephSecondaryState, Message: "pool is 85% full",
+				Conditions: []metav1.Condition{{Type: "Degraded", Status: metav1.ConditionTrue, Message: "10% of peers slow"}}},
+			expected: SyncProgress{TotalBytes: 100, SyncedBytes: 100},
+		},
+		{
+			name:     "NoNumbers",
+			status:   VolumeReplicationStatus{State: CephPrimaryState, Message: "volume is marked primary"},
+			expected: SyncProgress{TotalBytes: 100, SyncedBytes: 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, adapter.calculateEnhancedSyncProgress(tt.status, tt.status.State))
+			assert.Equal(t, tt.expected, adapter.calculateSyncProgress(tt.status))
+		})
+	}
+}
+
+// newCephTestScheme returns a scheme with the Ceph VolumeReplication types registered
+func newCephTestScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = replicationv1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+	_ = storagev1.AddToScheme(scheme)
+	scheme.AddKnownTypes(schema.GroupVersion{Group: "replication.storage.openshift.io", Version: "v1alpha1"},
+		&VolumeReplication{}, &VolumeReplicationList{})
+	return scheme
+}
+
+func TestCephAdapter_VerifyReplicaReadable(t *testing.T) {
+	uvr := createUnifiedVolumeReplication()
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateReplica
+
+	client := fake.NewClientBuilder().WithScheme(newCephTestScheme()).Build()
+	adapter, err := NewCephAdapter(client, translation.NewEngine())
+	require.NoError(t, err)
+	assert.NotContains(t, adapter.GetSupportedFeatures(), FeatureReplicaRead)
+
+	readable, reason, err := adapter.VerifyReplicaReadable(context.Background(), uvr)
+	require.NoError(t, err)
+	assert.False(t, readable, "RBD secondaries are not mountable")
+	assert.NotEmpty(t, reason)
+}
+
+func TestCephAdapter_DetectPolicyDrift(t *testing.T) {
+	ctx := context.Background()
+	uvr := createUnifiedVolumeReplication()
+
+	vr := &VolumeReplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-uvr-vr", Namespace: "default"},
+		Spec: VolumeReplicationSpec{
+			VolumeReplicationClass: "rbd-volumereplicationclass",
+			PvcName:                "test-pvc",
+			ReplicationState:       "primary",
+		},
+	}
+
+	vrc := &unstructured.Unstructured{}
+	vrc.SetGroupVersionKind(schema.FromAPIVersionAndKind(VolumeReplicationAPIVersion, VolumeReplicationClassKind))
+	vrc.SetName("rbd-volumereplicationclass")
+	require.NoError(t, unstructured.SetNestedStringMap(vrc.Object, map[string]string{
+		"mirroringMode":      "journal",
+		"schedulingInterval": "5m",
+	}, "spec", "parameters"))
+
+	client := fake.NewClientBuilder().WithScheme(newCephTestScheme()).WithObjects(vr, vrc).Build()
+	adapter, err := NewCephAdapter(client, translation.NewEngine())
+	require.NoError(t, err)
+
+	drift, err := adapter.DetectPolicyDrift(ctx, uvr)
+	require.NoError(t, err)
+	assert.Empty(t, drift, "class matching the UVR should not report drift")
+
+	// Equivalent intervals written differently are not drift
+	uvr.Spec.Schedule.Rpo = "0h5m"
+	drift, err = adapter.DetectPolicyDrift(ctx, uvr)
+	require.NoError(t, err)
+	assert.Empty(t, drift)
+	uvr.Spec.Schedule.Rpo = "5m"
+
+	// Backend admin changes the class parameters
+	require.NoError(t, client.Get(ctx, types.NamespacedName{Name: "rbd-volumereplicationclass"}, vrc))
+	require.NoError(t, unstructured.SetNestedStringMap(vrc.Object, map[string]string{
+		"mirroringMode":      "snapshot",
+		"schedulingInterval": "1h",
+	}, "spec", "parameters"))
+	require.NoError(t, client.Update(ctx, vrc))
+
+	drift, err = adapter.DetectPolicyDrift(ctx, uvr)
+	require.NoError(t, err)
+	require.Len(t, drift, 2)
+	assert.Equal(t, PolicyDrift{Field: "mirroringMode", Desired: "journal", Observed: "snapshot"}, drift[0])
+	assert.Equal(t, PolicyDrift{Field: "schedulingInterval", Desired: "5m", Observed: "1h"}, drift[1])
+}
+
+func TestCephAdapter_RecreationRequired(t *testing.T) {
+	ctx := context.Background()
+	uvr := createUnifiedVolumeReplication()
+
+	client := fake.NewClientBuilder().WithScheme(newCephTestScheme()).Build()
+	adapter, err := NewCephAdapter(client, translation.NewEngine())
+	require.NoError(t, err)
+
+	changes, err := adapter.RecreationRequired(ctx, uvr)
+	require.NoError(t, err)
+	assert.Empty(t, changes, "nothing to recreate before the VolumeReplication exists")
+	exists, err := adapter.BackendResourceExists(ctx, uvr)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, client.Create(ctx, &VolumeReplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-uvr-vr", Namespace: "default"},
+		Spec: VolumeReplicationSpec{
+			VolumeReplicationClass: "rbd-volumereplicationclass",
+			PvcName:                "test-pvc",
+			ReplicationState:       "primary",
+		},
+	}))
+	exists, err = adapter.BackendResourceExists(ctx, uvr)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	changes, err = adapter.RecreationRequired(ctx, uvr)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	// The data source of a VolumeReplication cannot be changed
+	uvr.Spec.VolumeMapping.Source.PvcName = "other-pvc"
+	changes, err = adapter.RecreationRequired(ctx, uvr)
+	require.NoError(t, err)
+	assert.Equal(t, []PolicyDrift{{Field: "pvcName", Desired: "other-pvc", Observed: "test-pvc"}}, changes)
+}
+
+func TestCephAdapter_AdoptReplication(t *testing.T) {
+	ctx := context.Background()
+	uvr := createUnifiedVolumeReplication()
+	uvr.Annotations = map[string]string{AdoptAnnotation: "legacy-vr"}
+
+	client := fake.NewClientBuilder().WithScheme(newCephTestScheme()).Build()
+	adapter, err := NewCephAdapter(client, translation.NewEngine())
+	require.NoError(t, err)
+
+	_, err = adapter.AdoptReplication(ctx, uvr)
+	require.Error(t, err, "nothing to adopt")
+	assert.False(t, IsAdoptionConflictError(err))
+
+	require.NoError(t, client.Create(ctx, &VolumeReplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "legacy-vr",
+			Namespace:   "default",
+			Annotations: map[string]string{AdoptedByAnnotation: "default/other-uvr"},
+		},
+		Spec: VolumeReplicationSpec{
+			VolumeReplicationClass: "rbd-volumereplicationclass",
+			PvcName:                "test-pvc",
+			ReplicationState:       "primary",
+		},
+	}))
+	uvr.Annotations[AdoptConflictPolicyAnnotation] = string(AdoptConflictPolicyPreferSpec)
+	_, err = adapter.AdoptReplication(ctx, uvr)
+	require.Error(t, err)
+	assert.True(t, IsAdoptionConflictError(err), "adopted by another UVR, whatever the policy")
+	delete(uvr.Annotations, AdoptConflictPolicyAnnotation)
+
+	vr := &VolumeReplication{}
+	require.NoError(t, client.Get(ctx, types.NamespacedName{Name: "legacy-vr", Namespace: "default"}, vr))
+	delete(vr.Annotations, AdoptedByAnnotation)
+	require.NoError(t, client.Update(ctx, vr))
+
+	conflicts, err := adapter.AdoptReplication(ctx, uvr)
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+	require.NoError(t, client.Get(ctx, types.NamespacedName{Name: "legacy-vr", Namespace: "default"}, vr))
+	assert.Equal(t, "ceph", vr.Labels["backend"])
+	assert.Equal(t, "default/test-uvr", vr.Annotations[AdoptedByAnnotation])
+
+	// The adopted VolumeReplication is the one the UVR manages from now on
+	exists, err := adapter.BackendResourceExists(ctx, uvr)
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestCephAdapter_ResourceOwnership(t *testing.T) {
+	ctx := context.Background()
+	uvr := createUnifiedVolumeReplication()
+	uvr.UID = "uid-test"
+
+	client := fake.NewClientBuilder().WithScheme(newCephTestScheme()).Build()
+	adapter, err := NewCephAdapter(client, translation.NewEngine())
+	require.NoError(t, err)
+
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+	vr := &VolumeReplication{}
+	key := types.NamespacedName{Name: "test-uvr-vr", Namespace: "default"}
+	require.NoError(t, client.Get(ctx, key, vr))
+	assert.Equal(t, "uid-test", vr.Labels[OwnerUIDLabel], "created resources record their owner")
+
+	// A UVR with another UID deriving the same name is refused and cannot delete it
+	other := createUnifiedVolumeReplication()
+	other.UID = "uid-other"
+	other.Spec.ReplicationState = "replica"
+	err = adapter.EnsureReplication(ctx, other)
+	require.Error(t, err)
+	conflict, ok := AsResourceConflictError(err)
+	require.True(t, ok)
+	assert.Equal(t, types.UID("uid-test"), conflict.OwnerUID)
+
+	_, err = adapter.RecreationRequired(ctx, other)
+	_, ok = AsResourceConflictError(err)
+	assert.True(t, ok)
+
+	require.NoError(t, adapter.DeleteReplication(ctx, other))
+	require.NoError(t, client.Get(ctx, key, vr))
+	assert.Equal(t, CephPrimaryState, vr.Spec.ReplicationState, "the owner's VolumeReplication is untouched")
+
+	// Resources without the label are claimed by the UVR that updates them
+	delete(vr.Labels, OwnerUIDLabel)
+	require.NoError(t, client.Update(ctx, vr))
+	require.NoError(t, adapter.EnsureReplication(ctx, other))
+	require.NoError(t, client.Get(ctx, key, vr))
+	assert.Equal(t, "uid-other", vr.Labels[OwnerUIDLabel])
+}
+
+func TestCephAdapter_DrainWrites(t *testing.T) {
+	ctx := context.Background()
+	uvr := createUnifiedVolumeReplication()
+
+	client := fake.NewClientBuilder().WithScheme(newCephTestScheme()).Build()
+	adapter, err := NewCephAdapter(client, translation.NewEngine())
+	require.NoError(t, err)
+
+	require.NoError(t, client.Create(ctx, &VolumeReplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-uvr-vr", Namespace: "default"},
+		Spec: VolumeReplicationSpec{
+			VolumeReplicationClass: "rbd-volumereplicationclass",
+			PvcName:                "test-pvc",
+			ReplicationState:       "primary",
+		},
+	}))
+	key := types.NamespacedName{Name: "test-uvr-vr", Namespace: "default"}
+
+	// The first call requests the drain and the primary has not caught up yet
+	drained, err := adapter.DrainWrites(ctx, uvr)
+	require.NoError(t, err)
+	assert.False(t, drained)
+
+	vr := &VolumeReplication{}
+	require.NoError(t, client.Get(ctx, key, vr))
+	requested, err := time.Parse(time.RFC3339Nano, vr.Annotations[DrainRequestedAnnotation])
+	require.NoError(t, err)
+
+	// Sync times are reported to the second, so a sync in the second of the request may
+	// have finished before it
+	sameSecond := metav1.NewTime(requested.Truncate(time.Second))
+	vr.Status.LastSyncTime = &sameSecond
+	require.NoError(t, client.Update(ctx, vr))
+	drained, err = adapter.DrainWrites(ctx, uvr)
+	require.NoError(t, err)
+	assert.False(t, drained)
+
+	// A sync that finished before the request does not cover the fenced writes
+	before := metav1.NewTime(requested.Add(-time.Minute))
+	vr.Status.LastSyncTime = &before
+	require.NoError(t, client.Update(ctx, vr))
+	drained, err = adapter.DrainWrites(ctx, uvr)
+	require.NoError(t, err)
+	assert.False(t, drained)
+
+	after := metav1.NewTime(requested.Add(time.Minute))
+	vr.Status.LastSyncTime = &after
+	require.NoError(t, client.Update(ctx, vr))
+	drained, err = adapter.DrainWrites(ctx, uvr)
+	require.NoError(t, err)
+	assert.True(t, drained)
+
+	// The sync that completed the drain is the application-consistent recovery point
+	require.NoError(t, client.Get(ctx, key, vr))
+	assert.Equal(t, after.UTC().Format(time.RFC3339), vr.Annotations[QuiescedSyncTimeAnnotation])
+	level, err := adapter.GetConsistencyState(ctx, uvr)
+	require.NoError(t, err)
+	assert.Equal(t, ConsistencyLevelApplication, level)
+
+	// An abandoned demotion clears the stale request
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+	require.NoError(t, client.Get(ctx, key, vr))
+	assert.Equal(t, "primary", vr.Spec.ReplicationState)
+	assert.NotContains(t, vr.Annotations, DrainRequestedAnnotation)
+}
+
+func TestCephAdapter_StateTransitionEvents(t *testing.T) {
+	ctx := context.Background()
+	uvr := createUnifiedVolumeReplication()
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateReplica
+
+	// The driver completes a requested promotion at once, unless updates fail
+	failUpdates := false
+	c := fake.NewClientBuilder().WithScheme(newCephTestScheme()).WithInterceptorFuncs(interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if failUpdates {
+				return apierrors.NewServiceUnavailable("ceph is down")
+			}
+			if vr, ok := obj.(*VolumeReplication); ok && vr.Spec.ReplicationState == "resync-promote" {
+				vr.Spec.ReplicationState = "primary"
+				vr.Status.State = "primary"
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+	}).Build()
+	adapter, err := NewCephAdapter(c, translation.NewEngine())
+	require.NoError(t, err)
+	adapter.transitionPollInterval = time.Millisecond
+	adapter.transitions = newCephTransitionLedger()
+	recorder := record.NewFakeRecorder(10)
+	adapter.SetEventRecorder(recorder)
+
+	secondary := func() {
+		vr := &VolumeReplication{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-uvr-vr", Namespace: "default"},
+			Spec: VolumeReplicationSpec{
+				VolumeReplicationClass: "rbd-volumereplicationclass",
+				PvcName:                "test-pvc",
+				ReplicationState:       "secondary",
+			},
+			Status: VolumeReplicationStatus{State: "secondary"},
+		}
+		_ = c.Delete(ctx, vr)
+		require.NoError(t, c.Create(ctx, vr))
+		adapter.statusCache.Clear()
+	}
+	events := func() []string {
+		var recorded []string
+		for len(recorder.Events) > 0 {
+			recorded = append(recorded, <-recorder.Events)
+		}
+		return recorded
+	}
+
+	secondary()
+	require.NoError(t, adapter.PromoteReplica(ctx, uvr))
+	recorded := events()
+	require.Len(t, recorded, 2)
+	assert.Equal(t, "Normal StateTransitionStarted Ceph transition from replica to promoting started", recorded[0])
+	assert.Regexp(t, `^Normal StateTransitionSucceeded Ceph transition from replica to promoting completed in \S+$`, recorded[1])
+
+	secondary()
+	failUpdates = true
+	require.Error(t, adapter.PromoteReplica(ctx, uvr))
+	recorded = events()
+	require.Len(t, recorded, 2)
+	assert.Equal(t, "Normal StateTransitionStarted Ceph transition from replica to promoting started", recorded[0])
+	assert.Regexp(t, `^Warning StateTransitionFailed Ceph transition from replica to promoting failed after \S+: .*ceph is down`, recorded[1])
+}
+
+func TestCephAdapter_EnsureReplicationTracksTransitions(t *testing.T) {
+	ctx := context.Background()
+	uvr := createUnifiedVolumeReplication()
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+
+	failUpdates := false
+	c := fake.NewClientBuilder().WithScheme(newCephTestScheme()).WithInterceptorFuncs(interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if failUpdates {
+				return apierrors.NewServiceUnavailable("ceph is down")
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+	}).Build()
+	adapter, err := NewCephAdapter(c, translation.NewEngine())
+	require.NoError(t, err)
+	adapter.transitions = newCephTransitionLedger()
+	recorder := record.NewFakeRecorder(10)
+	adapter.SetEventRecorder(recorder)
+	events := func() []string {
+		var recorded []string
+		for len(recorder.Events) > 0 {
+			recorded = append(recorded, <-recorder.Events)
+		}
+		return recorded
+	}
+
+	vr := &VolumeReplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-uvr-vr", Namespace: "default"},
+		Spec: VolumeReplicationSpec{
+			VolumeReplicationClass: "rbd-volumereplicationclass",
+			PvcName:                "test-pvc",
+			ReplicationState:       "secondary",
+		},
+		Status: VolumeReplicationStatus{State: "secondary"},
+	}
+	require.NoError(t, c.Create(ctx, vr))
+
+	// A promotion driven by the spec starts a transition...
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+	assert.Equal(t, []string{"Normal StateTransitionStarted Ceph transition from replica to promoting started"}, events())
+	_, err = adapter.GetReplicationStatus(ctx, uvr)
+	require.NoError(t, err)
+	assert.Empty(t, events(), "the transition lasts until the mirror daemon reports the new role")
+
+	// ...that completes once the VolumeReplication reports the source role
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(vr), vr))
+	vr.Status.State = "Primary"
+	require.NoError(t, c.Update(ctx, vr))
+	adapter.statusCache.Clear()
+	_, err = adapter.GetReplicationStatus(ctx, uvr)
+	require.NoError(t, err)
+	recorded := events()
+	require.Len(t, recorded, 1)
+	assert.Regexp(t, `^Normal StateTransitionSucceeded Ceph transition from replica to promoting completed in \S+$`, recorded[0])
+
+	// An ensure that changes nothing starts no transition
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+	assert.Empty(t, events())
+
+	// A demotion whose update fails is reported as failed
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateReplica
+	failUpdates = true
+	require.Error(t, adapter.EnsureReplication(ctx, uvr))
+	recorded = events()
+	require.Len(t, recorded, 2)
+	assert.Equal(t, "Normal StateTransitionStarted Ceph transition from source to demoting started", recorded[0])
+	assert.Regexp(t, `^Warning StateTransitionFailed Ceph transition from source to demoting failed after \S+: .*ceph is down`, recorded[1])
+}
+
+func TestCephAdapter_ConfigureNativeSchedule(t *testing.T) {
+	ctx := context.Background()
+	uvr := createUnifiedVolumeReplication()
+	uvr.Spec.Schedule = replicationv1alpha1.Schedule{Mode: replicationv1alpha1.ScheduleModeInterval, Rpo: "10m", Delegate: true}
+
+	classGVK := schema.FromAPIVersionAndKind(VolumeReplicationAPIVersion, VolumeReplicationClassKind)
+	vrc := &unstructured.Unstructured{}
+	vrc.SetGroupVersionKind(classGVK)
+	vrc.SetName("rbd-volumereplicationclass")
+	require.NoError(t, unstructured.SetNestedField(vrc.Object, "rbd.csi.ceph.com", "spec", "provisioner"))
+	require.NoError(t, unstructured.SetNestedStringMap(vrc.Object, map[string]string{
+		"mirroringMode":      "snapshot",
+		"schedulingInterval": "1h",
+	}, "spec", "parameters"))
+
+	client := fake.NewClientBuilder().WithScheme(newCephTestScheme()).WithObjects(vrc).Build()
+	adapter, err := NewCephAdapter(client, translation.NewEngine())
+	require.NoError(t, err)
+
+	// The class snapshots less often than the RPO
+	err = adapter.ConfigureNativeSchedule(ctx, uvr)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "VolumeReplicationClass rbd-volumereplicationclass schedules mirror snapshots every 1h, less often than the RPO 10m")
+
+	// A class meeting the RPO is used as it is, without a class per RPO
+	require.NoError(t, unstructured.SetNestedField(vrc.Object, "5m", "spec", "parameters", "schedulingInterval"))
+	require.NoError(t, client.Update(ctx, vrc))
+	require.NoError(t, adapter.ConfigureNativeSchedule(ctx, uvr))
+	classes := &unstructured.UnstructuredList{}
+	classes.SetGroupVersionKind(classGVK.GroupVersion().WithKind(VolumeReplicationClassKind + "List"))
+	require.NoError(t, client.List(ctx, classes))
+	require.Len(t, classes.Items, 1)
+	parameters, _, _ := unstructured.NestedStringMap(classes.Items[0].Object, "spec", "parameters")
+	assert.Equal(t, map[string]string{"mirroringMode": "snapshot", "schedulingInterval": "5m"}, parameters)
+
+	vr, err := adapter.buildVolumeReplication(uvr)
+	require.NoError(t, err)
+	assert.Equal(t, "rbd-volumereplicationclass", vr.Spec.VolumeReplicationClass)
+
+	// The class of an existing VolumeReplication is checked, not the default one
+	require.NoError(t, unstructured.SetNestedField(vrc.Object, "1h", "spec", "parameters", "schedulingInterval"))
+	require.NoError(t, client.Update(ctx, vrc))
+	fast := vrc.DeepCopy()
+	fast.SetName("rbd-fast")
+	fast.SetResourceVersion("")
+	require.NoError(t, unstructured.SetNestedField(fast.Object, "2m", "spec", "parameters", "schedulingInterval"))
+	require.NoError(t, client.Create(ctx, fast))
+	vr.Spec.VolumeReplicationClass = "rbd-fast"
+	require.NoError(t, client.Create(ctx, vr))
+	require.NoError(t, adapter.ConfigureNativeSchedule(ctx, uvr))
+}
+
+func TestCephAdapter_ConsistencyState(t *testing.T) {
+	ctx := context.Background()
+	syncTime := metav1.NewTime(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name        string
+		lastSync    *metav1.Time
+		annotations map[string]string
+		expected    ConsistencyLevel
+	}{
+		{name: "NoSyncYet", expected: ConsistencyLevelUnknown},
+		{name: "NotQuiesced", lastSync: &syncTime, expected: ConsistencyLevelCrash},
+		{
+			name:        "QuiescedEarlierSync",
+			lastSync:    &syncTime,
+			annotations: map[string]string{QuiescedSyncTimeAnnotation: "2024-05-01T09:45:00Z"},
+			expected:    ConsistencyLevelCrash,
+		},
+		{
+			name:        "QuiescedLatestSync",
+			lastSync:    &syncTime,
+			annotations: map[string]string{QuiescedSyncTimeAnnotation: "2024-05-01T10:00:00Z"},
+			expected:    ConsistencyLevelApplication,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vr := &VolumeReplication{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-uvr-vr", Namespace: "default", Annotations: tt.annotations},
+				Spec:       VolumeReplicationSpec{PvcName: "test-pvc", ReplicationState: "primary"},
+				Status:     VolumeReplicationStatus{LastSyncTime: tt.lastSync},
+			}
+			client := fake.NewClientBuilder().WithScheme(newCephTestScheme()).WithObjects(vr).Build()
+			adapter, err := NewCephAdapter(client, translation.NewEngine())
+			require.NoError(t, err)
+
+			level, err := adapter.GetConsistencyState(ctx, createUnifiedVolumeReplication())
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, level)
+		})
+	}
+}
+
+func TestCephAdapter_MemberSyncStatus(t *testing.T) {
+	syncTime := metav1.NewTime(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	vr := &VolumeReplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-uvr-vr", Namespace: "default"},
+		Spec:       VolumeReplicationSpec{PvcName: "test-pvc", ReplicationState: "secondary"},
+		Status:     VolumeReplicationStatus{LastSyncTime: &syncTime},
+	}
+	client := fake.NewClientBuilder().WithScheme(newCephTestScheme()).WithObjects(vr).Build()
+	adapter, err := NewCephAdapter(client, translation.NewEngine())
+	require.NoError(t, err)
+
+	// Only the primary volume is replicated, so group members are never reported
+	uvr := createUnifiedVolumeReplication()
+	uvr.Spec.GroupMembers = []replicationv1alpha1.VolumeMapping{{
+		Source:      replicationv1alpha1.VolumeSource{PvcName: "data-pvc", Namespace: "default"},
+		Destination: replicationv1alpha1.VolumeDestination{VolumeHandle: "data-dest", Namespace: "default"},
+	}}
+	status, err := adapter.GetReplicationStatus(context.Background(), uvr)
+	require.NoError(t, err)
+	require.Len(t, status.Members, 1)
+	assert.Equal(t, "test-pvc", status.Members[0].PvcName)
+	require.NotNil(t, status.Members[0].LastSyncTime)
+	assert.Equal(t, syncTime.Time, status.Members[0].LastSyncTime.UTC())
+}
+
+// newCephBlockPool returns a Rook CephBlockPool reporting the given mirroring peers and summary
+func newCephBlockPool(peers []interface{}, summary map[string]interface{}) *unstructured.Unstructured {
+	pool := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"mirroringInfo":   map[string]interface{}{"mode": "image", "peers": peers},
+			"mirroringStatus": map[string]interface{}{"summary": summary},
+		},
+	}}
+	pool.SetGroupVersionKind(schema.FromAPIVersionAndKind(CephBlockPoolAPIVersion, CephBlockPoolKind))
+	pool.SetName("replicapool")
+	pool.SetNamespace("rook-ceph")
+	return pool
+}
+
+func TestCephMirrorStatus(t *testing.T) {
+	connected := map[string]interface{}{"uuid": "b1", "site_name": "dr-site", "direction": "rx-tx", "mirror_uuid": "m1"}
+	pending := map[string]interface{}{"uuid": "a2", "site_name": "dr-site", "direction": "rx-tx"}
+	summary := func(health, daemon, image string, states map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"health": health, "daemon_health": daemon, "image_health": image, "states": states}
+	}
+
+	tests := []struct {
+		name      string
+		peers     []interface{}
+		summary   map[string]interface{}
+		peerState CephMirrorPeerState
+		health    ReplicationHealth
+		message   string
+	}{
+		{
+			name:      "Healthy",
+			peers:     []interface{}{connected},
+			summary:   summary("OK", "OK", "OK", map[string]interface{}{"replaying": int64(3)}),
+			peerState: CephMirrorPeerConnected,
+			health:    ReplicationHealthHealthy,
+		},
+		{
+			name:      "OnePeerConnected",
+			peers:     []interface{}{pending, connected},
+			summary:   summary("OK", "OK", "OK", nil),
+			peerState: CephMirrorPeerConnected,
+			health:    ReplicationHealthHealthy,
+		},
+		{
+			name:      "PeerNotConnected",
+			peers:     []interface{}{pending},
+			summary:   summary("WARNING", "OK", "WARNING", map[string]interface{}{"unknown": int64(3)}),
+			peerState: CephMirrorPeerNotConnected,
+			health:    ReplicationHealthUnhealthy,
+			message:   "rbd-mirror peer of pool replicapool is not connected",
+		},
+		{
+			name:      "NoPeer",
+			summary:   summary("OK", "OK", "OK", nil),
+			peerState: CephMirrorPeerMissing,
+			health:    ReplicationHealthUnhealthy,
+			message:   "pool replicapool has no rbd-mirror peer",
+		},
+		{
+			name:      "DaemonError",
+			peers:     []interface{}{connected},
+			summary:   summary("ERROR", "ERROR", "OK", nil),
+			peerState: CephMirrorPeerConnected,
+			health:    ReplicationHealthUnhealthy,
+			message:   "rbd-mirror daemon health is ERROR",
+		},
+		{
+			name:      "DaemonWarning",
+			peers:     []interface{}{connected},
+			summary:   summary("WARNING", "warning", "OK", nil),
+			peerState: CephMirrorPeerConnected,
+			health:    ReplicationHealthDegraded,
+			message:   "rbd-mirror daemon health is WARNING",
+		},
+		{
+			// Other images of the pool say nothing about this volume's image
+			name:      "PoolImagesInError",
+			peers:     []interface{}{connected},
+			summary:   summary("ERROR", "OK", "ERROR", map[string]interface{}{"replaying": int64(2), "error": int64(1)}),
+			peerState: CephMirrorPeerConnected,
+			health:    ReplicationHealthHealthy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := parseCephMirrorStatus("replicapool", newCephBlockPool(tt.peers, tt.summary))
+			require.NotNil(t, status)
+			assert.Equal(t, tt.peerState, status.PeerState)
+			health, message := status.ReplicationHealth()
+			assert.Equal(t, tt.health, health)
+			assert.Contains(t, message, tt.message)
+		})
+	}
+
+	pool := newCephBlockPool(nil, nil)
+	unstructured.RemoveNestedField(pool.Object, "status")
+	assert.Nil(t, parseCephMirrorStatus("replicapool", pool), "nothing is reported before Rook reports mirroring")
+}
+
+func TestCephPrimaryIdentity(t *testing.T) {
+	mirror := &CephMirrorStatus{SiteName: "prod-site", Peers: []CephMirrorPeer{{UUID: "b1", SiteName: "dr-site"}}}
+
+	cluster, site := cephPrimaryIdentity(mirror, string(replicationv1alpha1.ReplicationStateSource))
+	assert.Equal(t, "prod-site", cluster)
+	assert.Equal(t, "prod-site", site)
+	cluster, _ = cephPrimaryIdentity(mirror, string(replicationv1alpha1.ReplicationStatePromoting))
+	assert.Equal(t, "prod-site", cluster)
+
+	// A secondary local image means the peer holds the primary
+	cluster, site = cephPrimaryIdentity(mirror, string(replicationv1alpha1.ReplicationStateReplica))
+	assert.Equal(t, "dr-site", cluster)
+	assert.Equal(t, "dr-site", site)
+
+	// Nothing is guessed without a single peer, a known role or a mirror status
+	cluster, _ = cephPrimaryIdentity(mirror, string(replicationv1alpha1.ReplicationStateSyncing))
+	assert.Empty(t, cluster)
+	mirror.Peers = append(mirror.Peers, CephMirrorPeer{UUID: "c3", SiteName: "backup-site"})
+	cluster, _ = cephPrimaryIdentity(mirror, string(replicationv1alpha1.ReplicationStateDemoting))
+	assert.Empty(t, cluster)
+	cluster, _ = cephPrimaryIdentity(nil, string(replicationv1alpha1.ReplicationStateSource))
+	assert.Empty(t, cluster)
+}
+
+func TestCephAdapter_MirrorPeerStatus(t *testing.T) {
+	ctx := context.Background()
+	uvr := createUnifiedVolumeReplication()
+
+	storageClass := &storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: "ceph-rbd"},
+		Provisioner: "rook-ceph.rbd.csi.ceph.com",
+		Parameters:  map[string]string{"pool": "replicapool", "clusterID": "rook-ceph"},
+	}
+	vr := &VolumeReplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-uvr-vr", Namespace: "default"},
+		Spec:       VolumeReplicationSpec{PvcName: "test-pvc", ReplicationState: "primary"},
+		Status: VolumeReplicationStatus{
+			State:      "Primary",
+			Conditions: []metav1.Condition{{Type: "Healthy", Status: metav1.ConditionTrue}},
+		},
+	}
+	pool := newCephBlockPool([]interface{}{map[string]interface{}{"uuid": "a2", "site_name": "dr-site"}},
+		map[string]interface{}{"health": "WARNING", "daemon_health": "OK", "image_health": "WARNING",
+			"states": map[string]interface{}{"unknown": int64(1)}})
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pvc", Namespace: "default"},
+		Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "pvc-1234"},
+	}
+	pv := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pvc-1234"},
+		Spec: corev1.PersistentVolumeSpec{PersistentVolumeSource: corev1.PersistentVolumeSource{CSI: &corev1.CSIPersistentVolumeSource{
+			Driver:           "rook-ceph.rbd.csi.ceph.com",
+			VolumeHandle:     "0001-0009-rook-ceph-0000000000000002-1234",
+			VolumeAttributes: map[string]string{"imageName": "csi-vol-1234"},
+		}}},
+	}
+
+	require.NoError(t, unstructured.SetNestedField(pool.Object, "prod-site", "status", "mirroringInfo", "site_name"))
+
+	client := fake.NewClientBuilder().WithScheme(newCephTestScheme()).WithObjects(storageClass, vr, pool, pvc, pv).Build()
+	adapter, err := NewCephAdapter(client, translation.NewEngine())
+	require.NoError(t, err)
+
+	mirror, err := adapter.GetMirrorPeerStatus(ctx, uvr)
+	require.NoError(t, err)
+	require.NotNil(t, mirror)
+	assert.Equal(t, "replicapool", mirror.Pool)
+	assert.Equal(t, "prod-site", mirror.SiteName)
+	assert.Equal(t, []CephMirrorPeer{{UUID: "a2", SiteName: "dr-site"}}, mirror.Peers)
+	assert.Equal(t, map[string]int64{"unknown": 1}, mirror.ImageStates)
+	assert.Equal(t, "csi-vol-1234", mirror.Image)
+
+	// The VolumeReplication looks healthy, but the peer never connected
+	status, err := adapter.GetReplicationStatus(ctx, uvr)
+	require.NoError(t, err)
+	assert.Equal(t, ReplicationHealthUnhealthy, status.Health)
+	assert.Equal(t, DegradedReasonMirrorUnhealthy, status.DegradedReason)
+	assert.Contains(t, status.Message, "rbd-mirror peer of pool replicapool is not connected")
+	reported, ok := status.BackendSpecific[CephMirrorStatusKey].(*CephMirrorStatus)
+	require.True(t, ok, "the mirror status is typed")
+	assert.Equal(t, CephMirrorPeerNotConnected, reported.PeerState)
+	assert.Equal(t, "Replaying", reported.ImageState)
+
+	// The local image is primary, so the primary is the local rbd-mirror site whatever the
+	// UVR endpoints say
+	assert.Equal(t, "prod-site", status.PrimaryCluster)
+	assert.Equal(t, "prod-site", status.PrimarySite)
+
+	// The state of the volume's own image comes from the VolumeReplication conditions
+	assert.Equal(t, "Resyncing", cephImageState([]metav1.Condition{
+		{Type: "Degraded", Status: metav1.ConditionTrue},
+		{Type: "Resyncing", Status: metav1.ConditionTrue},
+	}))
+	assert.Equal(t, "Degraded", cephImageState([]metav1.Condition{{Type: "Degraded", Status: metav1.ConditionTrue}}))
+	assert.Empty(t, cephImageState(nil))
+
+	// Storage classes not set up by Rook report no mirror status
+	storageClass.Parameters = nil
+	require.NoError(t, client.Update(ctx, storageClass))
+	mirror, err = adapter.GetMirrorPeerStatus(ctx, uvr)
+	require.NoError(t, err)
+	assert.Nil(t, mirror)
+}
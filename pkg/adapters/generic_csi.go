@@ -0,0 +1,613 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/discovery"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+// StandardVolumeReplicationCRD is the CRD of the standard CSI VolumeReplication, shared by
+// every driver the generic CSI adapter can drive
+const StandardVolumeReplicationCRD = "volumereplications.replication.storage.openshift.io"
+
+// DefaultGenericCSIStateMap maps unified states to the states of the standard VolumeReplication
+// spec. Drivers reporting other states are configured through AdapterConfig.StateMap.
+var DefaultGenericCSIStateMap = map[string]string{
+	"source":  "primary",
+	"replica": "secondary",
+	"syncing": "resync",
+}
+
+// GenericCSIAdapter implements the ReplicationAdapter interface for any CSI driver backed by
+// a VolumeReplicationClass. It creates one standard VolumeReplication per UVR, with the
+// configured class, and translates states with the configured state map instead of the
+// translation engine, so a new driver needs configuration rather than a bespoke adapter.
+type GenericCSIAdapter struct {
+	*BaseAdapter
+	client    client.Client
+	className string
+	stateMap  *translation.TranslationMap
+}
+
+// NewGenericCSIAdapter creates a generic CSI adapter from config, which must name the
+// VolumeReplicationClass; an empty state map falls back to DefaultGenericCSIStateMap
+func NewGenericCSIAdapter(client client.Client, translator *translation.Engine, config *AdapterConfig) (*GenericCSIAdapter, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client cannot be nil")
+	}
+	if translator == nil {
+		return nil, fmt.Errorf("translator cannot be nil")
+	}
+	if config == nil {
+		return nil, fmt.Errorf("config cannot be nil: the VolumeReplicationClass is required")
+	}
+	stateMap, err := genericCSIStateMap(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GenericCSIAdapter{
+		BaseAdapter: NewBaseAdapter(translation.BackendGenericCSI, client, translator, config),
+		client:      client,
+		className:   config.VolumeReplicationClass,
+		stateMap:    stateMap,
+	}, nil
+}
+
+// genericCSIStateMap validates the generic CSI settings of config and returns its state map.
+// The map must cover the source and replica states, and map each to a distinct driver state
+// so the states read back from the driver are unambiguous.
+func genericCSIStateMap(config *AdapterConfig) (*translation.TranslationMap, error) {
+	if config.VolumeReplicationClass == "" {
+		return nil, fmt.Errorf("volume replication class is required for the generic CSI adapter")
+	}
+	states := config.StateMap
+	if len(states) == 0 {
+		states = DefaultGenericCSIStateMap
+	}
+	for _, required := range []replicationv1alpha1.ReplicationState{replicationv1alpha1.ReplicationStateSource, replicationv1alpha1.ReplicationStateReplica} {
+		if states[string(required)] == "" {
+			return nil, fmt.Errorf("state map must map the %s state", required)
+		}
+	}
+	stateMap := translation.NewTranslationMap(states)
+	if err := stateMap.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid state map: %w", err)
+	}
+	return stateMap, nil
+}
+
+// toDriverState translates a unified state with the configured state map
+func (ga *GenericCSIAdapter) toDriverState(unifiedState string) (string, error) {
+	if driverState, ok := ga.stateMap.ToBackend(unifiedState); ok {
+		return driverState, nil
+	}
+	return "", fmt.Errorf("state map has no driver state for %q", unifiedState)
+}
+
+// fromDriverState translates a driver state with the configured state map. Drivers report
+// the status state capitalized (Primary, Secondary), so the lookup falls back to ignoring case.
+func (ga *GenericCSIAdapter) fromDriverState(driverState string) (string, bool) {
+	if unified, ok := ga.stateMap.FromBackend(driverState); ok {
+		return unified, true
+	}
+	for backend, unified := range ga.stateMap.BackendToUnified {
+		if strings.EqualFold(backend, driverState) {
+			return unified, true
+		}
+	}
+	return "", false
+}
+
+// volumeReplicationName is the name of the UVR's VolumeReplication, or of the adopted one
+func (ga *GenericCSIAdapter) volumeReplicationName(uvr *replicationv1alpha1.UnifiedVolumeReplication) string {
+	if name := adoptedResourceName(uvr); name != "" {
+		return name
+	}
+	return fmt.Sprintf("%s-vr", uvr.Name)
+}
+
+// ValidateConfiguration checks the spec and that the state map covers the desired state
+func (ga *GenericCSIAdapter) ValidateConfiguration(uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	if err := ga.BaseAdapter.ValidateConfiguration(uvr); err != nil {
+		return err
+	}
+	if uvr.Spec.VolumeMapping.Source.PvcName == "" {
+		return NewAdapterError(ErrorTypeValidation, translation.BackendGenericCSI, "validate", uvr.Name, "source PVC name is required")
+	}
+	if _, err := ga.toDriverState(string(uvr.Spec.ReplicationState)); err != nil {
+		return NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendGenericCSI, "validate", uvr.Name, "state translation failed", err)
+	}
+	return nil
+}
+
+// SupportsConfiguration reports whether the state map covers the UVR's desired state
+func (ga *GenericCSIAdapter) SupportsConfiguration(uvr *replicationv1alpha1.UnifiedVolumeReplication) (bool, error) {
+	if uvr == nil {
+		return false, fmt.Errorf("UnifiedVolumeReplication cannot be nil")
+	}
+	_, err := ga.toDriverState(string(uvr.Spec.ReplicationState))
+	return err == nil, nil
+}
+
+// EnsureReplication creates the VolumeReplication of the UVR, or moves an existing one to the
+// desired state (idempotent)
+func (ga *GenericCSIAdapter) EnsureReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	startTime := time.Now()
+	if err := ga.ValidateConfiguration(uvr); err != nil {
+		ga.updateMetrics(uvr, "ensure", false, startTime)
+		return NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendGenericCSI, "ensure", uvr.Name, "configuration validation failed", err)
+	}
+	return ga.setDriverState(ctx, uvr, string(uvr.Spec.ReplicationState), "ensure")
+}
+
+// setDriverState sets the VolumeReplication of the UVR to the driver state of unifiedState,
+// creating it when missing
+func (ga *GenericCSIAdapter) setDriverState(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, unifiedState, operation string) error {
+	logger := log.FromContext(ctx).WithName("generic-csi-adapter").WithValues("uvr", uvr.Name)
+	startTime := time.Now()
+
+	driverState, err := ga.toDriverState(unifiedState)
+	if err != nil {
+		ga.updateMetrics(uvr, operation, false, startTime)
+		return NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendGenericCSI, operation, uvr.Name, "state translation failed", err)
+	}
+
+	name := ga.volumeReplicationName(uvr)
+	vr := &VolumeReplication{}
+	err = ga.client.Get(ctx, types.NamespacedName{Name: name, Namespace: uvr.Namespace}, vr)
+	if errors.IsNotFound(err) {
+		vr = &VolumeReplication{
+			TypeMeta: metav1.TypeMeta{APIVersion: VolumeReplicationAPIVersion, Kind: VolumeReplicationKind},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: uvr.Namespace,
+				Labels: map[string]string{
+					"managed-by": "unified-replication-operator",
+					"backend":    string(translation.BackendGenericCSI),
+				},
+			},
+			Spec: VolumeReplicationSpec{
+				VolumeReplicationClass: ga.className,
+				PvcName:                uvr.Spec.VolumeMapping.Source.PvcName,
+				ReplicationState:       driverState,
+			},
+		}
+		setResourceOwner(vr, uvr)
+		if err := ga.client.Create(ctx, vr); err != nil {
+			ga.updateMetrics(uvr, "create", false, startTime)
+			return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendGenericCSI, "create", uvr.Name, "failed to create VolumeReplication", err)
+		}
+		ga.updateMetrics(uvr, "create", true, startTime)
+		logger.Info("Created VolumeReplication", "volumeReplication", name, "class", ga.className, "state", driverState)
+		return nil
+	}
+	if err != nil {
+		ga.updateMetrics(uvr, operation, false, startTime)
+		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendGenericCSI, operation, uvr.Name, "failed to get VolumeReplication", err)
+	}
+	if err := checkResourceOwner(vr, fmt.Sprintf("VolumeReplication %s/%s", uvr.Namespace, name), uvr); err != nil {
+		ga.updateMetrics(uvr, operation, false, startTime)
+		return NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendGenericCSI, operation, uvr.Name, "VolumeReplication belongs to another UVR", err)
+	}
+	if vr.Spec.ReplicationState == driverState {
+		ga.updateMetrics(uvr, operation, true, startTime)
+		return nil
+	}
+
+	vr.Spec.ReplicationState = driverState
+	setResourceOwner(vr, uvr)
+	if err := ga.client.Update(ctx, vr); err != nil {
+		ga.updateMetrics(uvr, operation, false, startTime)
+		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendGenericCSI, operation, uvr.Name, "failed to update VolumeReplication", err)
+	}
+	ga.updateMetrics(uvr, operation, true, startTime)
+	ga.recordStateChange(uvr, startTime)
+	logger.Info("Updated VolumeReplication state", "volumeReplication", name, "state", driverState)
+	return nil
+}
+
+// DeleteReplication deletes the VolumeReplication of the UVR, leaving one owned by another
+// UVR in place
+func (ga *GenericCSIAdapter) DeleteReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	logger := log.FromContext(ctx).WithName("generic-csi-adapter").WithValues("uvr", uvr.Name)
+	startTime := time.Now()
+
+	vr := &VolumeReplication{}
+	if err := ga.client.Get(ctx, types.NamespacedName{Name: ga.volumeReplicationName(uvr), Namespace: uvr.Namespace}, vr); err != nil {
+		if errors.IsNotFound(err) {
+			ga.updateMetrics(uvr, "delete", true, startTime)
+			return nil
+		}
+		ga.updateMetrics(uvr, "delete", false, startTime)
+		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendGenericCSI, "delete", uvr.Name, "failed to get VolumeReplication", err)
+	}
+	if err := checkResourceOwner(vr, vr.Name, uvr); err != nil {
+		logger.Info("VolumeReplication belongs to another UVR, leaving it in place", "volumeReplication", vr.Name)
+		ga.updateMetrics(uvr, "delete", true, startTime)
+		return nil
+	}
+	if err := ga.client.Delete(ctx, vr); client.IgnoreNotFound(err) != nil {
+		ga.updateMetrics(uvr, "delete", false, startTime)
+		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendGenericCSI, "delete", uvr.Name, "failed to delete VolumeReplication", err)
+	}
+	ga.updateMetrics(uvr, "delete", true, startTime)
+	logger.Info("Deleted VolumeReplication", "volumeReplication", vr.Name)
+	return nil
+}
+
+// GetReplicationStatus reads the VolumeReplication status, translating the state the driver
+// reports, or the requested one until the driver reports any
+func (ga *GenericCSIAdapter) GetReplicationStatus(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (*ReplicationStatus, error) {
+	vr := &VolumeReplication{}
+	if err := ga.client.Get(ctx, types.NamespacedName{Name: ga.volumeReplicationName(uvr), Namespace: uvr.Namespace}, vr); err != nil {
+		if errors.IsNotFound(err) {
+			return &ReplicationStatus{
+				State:   "unknown",
+				Health:  ReplicationHealthUnknown,
+				Message: "VolumeReplication resource not found",
+			}, nil
+		}
+		return nil, NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendGenericCSI, "status", uvr.Name, "failed to get VolumeReplication", err)
+	}
+
+	driverState := vr.Status.State
+	if driverState == "" {
+		driverState = vr.Spec.ReplicationState
+	}
+	state, known := ga.fromDriverState(driverState)
+	health := genericCSIHealth(vr.Status.Conditions, known)
+	degradedReason := degradedReasonForHealth(health)
+	if !known {
+		state = "unknown"
+		if health == ReplicationHealthUnknown {
+			degradedReason = DegradedReasonUnknownState
+		}
+	}
+
+	status := &ReplicationStatus{
+		State:              state,
+		Mode:               string(uvr.Spec.ReplicationMode),
+		Health:             health,
+		DegradedReason:     degradedReason,
+		Message:            vr.Status.Message,
+		ObservedGeneration: vr.Generation,
+		Conditions:         genericCSIConditions(vr.Status.Conditions),
+		Direction:          resolveReplicationDirection(state),
+		BackendSpecific: map[string]interface{}{
+			"volumeReplicationClass": vr.Spec.VolumeReplicationClass,
+			"driverState":            driverState,
+		},
+	}
+	status.PrimaryCluster, status.PrimarySite = resolvePrimaryIdentity(uvr, state)
+	if vr.Status.LastSyncTime != nil {
+		lastSync := vr.Status.LastSyncTime.Time
+		status.LastSyncTime = &lastSync
+	}
+	if vr.Status.LastSyncDuration != nil {
+		duration := vr.Status.LastSyncDuration.Duration
+		status.LastSyncDuration = &duration
+	}
+	status.ConsistencyLevel = resolveConsistencyLevel(vr.GetAnnotations(), status.LastSyncTime)
+	status.Members = primaryVolumeMembers(uvr, status.LastSyncTime)
+	return status, nil
+}
+
+// genericCSIHealth derives health from the standard VolumeReplication conditions: Degraded
+// or Resyncing true degrade the replication, and a state outside the state map is unknown
+func genericCSIHealth(conditions []metav1.Condition, knownState bool) ReplicationHealth {
+	for _, condition := range conditions {
+		if condition.Status != metav1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case "Degraded", "Resyncing":
+			return ReplicationHealthDegraded
+		case "Error", "Failed":
+			return ReplicationHealthUnhealthy
+		}
+	}
+	if !knownState {
+		return ReplicationHealthUnknown
+	}
+	return ReplicationHealthHealthy
+}
+
+// genericCSIConditions converts the VolumeReplication conditions to status conditions
+func genericCSIConditions(conditions []metav1.Condition) []StatusCondition {
+	converted := make([]StatusCondition, 0, len(conditions))
+	for _, condition := range conditions {
+		converted = append(converted, StatusCondition{
+			Type:               condition.Type,
+			Status:             string(condition.Status),
+			LastTransitionTime: condition.LastTransitionTime.Time,
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+		})
+	}
+	return converted
+}
+
+// GetConsistencyState reports whether the latest sync point was taken while the application
+// was quiesced
+func (ga *GenericCSIAdapter) GetConsistencyState(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (ConsistencyLevel, error) {
+	return consistencyLevelOf(ga.GetReplicationStatus(ctx, uvr))
+}
+
+// PromoteReplica sets the VolumeReplication to the driver's source state
+func (ga *GenericCSIAdapter) PromoteReplica(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	return ga.setDriverState(ctx, uvr, string(replicationv1alpha1.ReplicationStateSource), "promote")
+}
+
+// DemoteSource sets the VolumeReplication to the driver's replica state
+func (ga *GenericCSIAdapter) DemoteSource(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	return ga.setDriverState(ctx, uvr, string(replicationv1alpha1.ReplicationStateReplica), "demote")
+}
+
+// ResyncReplication sets the VolumeReplication to the driver's syncing state, which the state
+// map must define
+func (ga *GenericCSIAdapter) ResyncReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	return ga.setDriverState(ctx, uvr, string(replicationv1alpha1.ReplicationStateSyncing), "resync")
+}
+
+// FailoverReplication promotes the replica; the standard VolumeReplication has no separate
+// failover operation
+func (ga *GenericCSIAdapter) FailoverReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	return ga.PromoteReplica(ctx, uvr)
+}
+
+// FailbackReplication demotes the current source
+func (ga *GenericCSIAdapter) FailbackReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	return ga.DemoteSource(ctx, uvr)
+}
+
+// Reconcile ensures the replication is in the desired state
+func (ga *GenericCSIAdapter) Reconcile(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	if err := ga.Initialize(ctx); err != nil {
+		return err
+	}
+	return ga.EnsureReplication(ctx, uvr)
+}
+
+// EffectiveBackendConfig reports the VolumeReplicationClass the UVR's VolumeReplication uses
+func (ga *GenericCSIAdapter) EffectiveBackendConfig(uvr *replicationv1alpha1.UnifiedVolumeReplication) (string, map[string]string) {
+	return ga.className, nil
+}
+
+// ParseGenericCSIStateMap parses a state map written as comma-separated unified=driver
+// pairs, e.g. "source=primary,replica=secondary". An empty value is an empty map.
+func ParseGenericCSIStateMap(value string) (map[string]string, error) {
+	states := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		unified, driver, ok := strings.Cut(pair, "=")
+		unified, driver = strings.TrimSpace(unified), strings.TrimSpace(driver)
+		if !ok || unified == "" || driver == "" {
+			return nil, fmt.Errorf("%q is not of the form unified=driver", pair)
+		}
+		if _, exists := states[unified]; exists {
+			return nil, fmt.Errorf("state %q is mapped twice", unified)
+		}
+		states[unified] = driver
+	}
+	return states, nil
+}
+
+// GenericCSIAdapterFactory creates generic CSI adapter instances
+type GenericCSIAdapterFactory struct {
+	info AdapterFactoryInfo
+
+	mu                     sync.RWMutex
+	volumeReplicationFound bool
+	className              string
+	stateMap               map[string]string
+}
+
+// NewGenericCSIAdapterFactory creates a new factory for generic CSI adapters
+func NewGenericCSIAdapterFactory() *GenericCSIAdapterFactory {
+	return &GenericCSIAdapterFactory{
+		info: AdapterFactoryInfo{
+			Name:        "Generic CSI Adapter",
+			Backend:     translation.BackendGenericCSI,
+			Version:     "v1.0.0",
+			Description: "Drives any CSI driver implementing the standard VolumeReplication",
+		},
+	}
+}
+
+// CreateAdapter creates a new generic CSI adapter instance
+func (f *GenericCSIAdapterFactory) CreateAdapter(backend translation.Backend, client client.Client, translator *translation.Engine, config *AdapterConfig) (ReplicationAdapter, error) {
+	if backend != translation.BackendGenericCSI {
+		return nil, fmt.Errorf("unsupported backend: %s", backend)
+	}
+	return NewGenericCSIAdapter(client, translator, f.withDefaults(config))
+}
+
+// SetDefaults sets the VolumeReplicationClass and state map of adapters whose configuration
+// names none, such as those the operator creates without backend settings. An empty state
+// map keeps DefaultGenericCSIStateMap.
+func (f *GenericCSIAdapterFactory) SetDefaults(className string, stateMap map[string]string) error {
+	if _, err := genericCSIStateMap(&AdapterConfig{VolumeReplicationClass: className, StateMap: stateMap}); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.className = className
+	f.stateMap = maps.Clone(stateMap)
+	return nil
+}
+
+// withDefaults returns config, the adapter defaults when nil, completed with the factory's
+// VolumeReplicationClass and state map where it names none
+func (f *GenericCSIAdapterFactory) withDefaults(config *AdapterConfig) *AdapterConfig {
+	if config == nil {
+		config = DefaultAdapterConfig(translation.BackendGenericCSI)
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	completed := *config
+	if completed.VolumeReplicationClass == "" {
+		completed.VolumeReplicationClass = f.className
+	}
+	if len(completed.StateMap) == 0 {
+		completed.StateMap = f.stateMap
+	}
+	return &completed
+}
+
+// GetBackendType returns the backend type this factory supports
+func (f *GenericCSIAdapterFactory) GetBackendType() translation.Backend {
+	return translation.BackendGenericCSI
+}
+
+// GetInfo returns information about this factory
+func (f *GenericCSIAdapterFactory) GetInfo() AdapterFactoryInfo {
+	return f.info
+}
+
+// ValidateConfig validates the adapter configuration, including the VolumeReplicationClass
+// and the state map
+func (f *GenericCSIAdapterFactory) ValidateConfig(config *AdapterConfig) error {
+	if config == nil {
+		return fmt.Errorf("config cannot be nil")
+	}
+	if config.Backend != translation.BackendGenericCSI {
+		return fmt.Errorf("unsupported backend: %s", config.Backend)
+	}
+	if config.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+	if config.RetryAttempts < 0 {
+		return fmt.Errorf("retry attempts cannot be negative")
+	}
+	if _, err := genericCSIStateMap(f.withDefaults(config)); err != nil {
+		return err
+	}
+	return validateStatusCacheConfig(config)
+}
+
+// SetDiscoveryResult records whether discovery found the standard VolumeReplication CRD
+func (f *GenericCSIAdapterFactory) SetDiscoveryResult(result *discovery.DiscoveryResult) {
+	found := false
+	if result != nil {
+		for _, backend := range result.Backends {
+			for _, crd := range backend.CRDs {
+				found = found || (crd.Name == StandardVolumeReplicationCRD && crd.Available)
+			}
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.volumeReplicationFound = found
+}
+
+// Supports returns whether this factory supports the given configuration: any UVR, once
+// SetDefaults has named the VolumeReplicationClass and discovery has found the standard
+// VolumeReplication CRD, except those whose extensions ask for a backend with its own
+// replication CRDs
+func (f *GenericCSIAdapterFactory) Supports(uvr *replicationv1alpha1.UnifiedVolumeReplication) bool {
+	if uvr == nil {
+		return false
+	}
+	if ext := uvr.Spec.Extensions; ext != nil && (ext.Trident != nil || ext.Powerstore != nil) {
+		return false
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.volumeReplicationFound && f.className != ""
+}
+
+// HasDefaultClass reports whether SetDefaults gave the factory a VolumeReplicationClass, which
+// Supports requires besides discovery finding the VolumeReplication CRD
+func (f *GenericCSIAdapterFactory) HasDefaultClass() bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.className != ""
+}
+
+// DiscoverySelector is implemented by factories chosen for a UVR from the latest discovery
+// result rather than from backend CRDs of their own
+type DiscoverySelector interface {
+	SetDiscoveryResult(result *discovery.DiscoveryResult)
+	Supports(uvr *replicationv1alpha1.UnifiedVolumeReplication) bool
+}
+
+// FeedDiscoveryResult hands a discovery result to the factories of registry that select
+// themselves from it
+func FeedDiscoveryResult(registry Registry, result *discovery.DiscoveryResult) {
+	if registry == nil || result == nil {
+		return
+	}
+	for _, factory := range registry.ListFactories() {
+		if selector, ok := factory.(DiscoverySelector); ok {
+			selector.SetDiscoveryResult(result)
+		}
+	}
+}
+
+// GenericCSISupports reports whether registry holds a generic CSI factory able to drive uvr
+// according to the last discovery result fed to it. The factory is looked up among the
+// listed factories, which registries wrapping GetFactory leave unwrapped.
+func GenericCSISupports(registry Registry, uvr *replicationv1alpha1.UnifiedVolumeReplication) bool {
+	if registry == nil {
+		return false
+	}
+	for _, factory := range registry.ListFactories() {
+		if factory.GetBackendType() != translation.BackendGenericCSI {
+			continue
+		}
+		selector, ok := factory.(DiscoverySelector)
+		return ok && selector.Supports(uvr)
+	}
+	return false
+}
+
+// GenericCSIConfigured reports whether registry holds a generic CSI factory with a default
+// VolumeReplicationClass, whether or not discovery has found the VolumeReplication CRD yet
+func GenericCSIConfigured(registry Registry) bool {
+	if registry == nil {
+		return false
+	}
+	for _, factory := range registry.ListFactories() {
+		if generic, ok := factory.(*GenericCSIAdapterFactory); ok {
+			return generic.HasDefaultClass()
+		}
+	}
+	return false
+}
+
+// Register the generic CSI adapter factory with the global registry
+func init() {
+	GetGlobalRegistry().RegisterFactory(NewGenericCSIAdapterFactory())
+}
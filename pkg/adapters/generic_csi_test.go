@@ -0,0 +1,234 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/discovery"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+// genericCSIConfig returns the configuration of a driver whose VolumeReplications use
+// vendor-specific state names
+func genericCSIConfig() *AdapterConfig {
+	config := DefaultAdapterConfig(translation.BackendGenericCSI)
+	config.VolumeReplicationClass = "vendor-replication"
+	config.StateMap = map[string]string{
+		"source":  "active",
+		"replica": "standby",
+		"syncing": "catch-up",
+	}
+	return config
+}
+
+func TestGenericCSIAdapter_Config(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(newCephTestScheme()).Build()
+	factory := NewGenericCSIAdapterFactory()
+
+	require.NoError(t, factory.ValidateConfig(genericCSIConfig()))
+
+	noClass := genericCSIConfig()
+	noClass.VolumeReplicationClass = ""
+	assert.ErrorContains(t, factory.ValidateConfig(noClass), "volume replication class is required")
+
+	noReplica := genericCSIConfig()
+	delete(noReplica.StateMap, "replica")
+	assert.ErrorContains(t, factory.ValidateConfig(noReplica), "state map must map the replica state")
+
+	ambiguous := genericCSIConfig()
+	ambiguous.StateMap["syncing"] = "standby"
+	assert.ErrorContains(t, factory.ValidateConfig(ambiguous), "invalid state map")
+
+	defaults := genericCSIConfig()
+	defaults.StateMap = nil
+	adapter, err := factory.CreateAdapter(translation.BackendGenericCSI, c, translation.NewEngine(), defaults)
+	require.NoError(t, err)
+	assert.Equal(t, translation.BackendGenericCSI, adapter.GetBackendType())
+	driverState, err := adapter.(*GenericCSIAdapter).toDriverState("source")
+	require.NoError(t, err)
+	assert.Equal(t, "primary", driverState, "an empty state map falls back to the standard states")
+
+	_, err = factory.CreateAdapter(translation.BackendCeph, c, translation.NewEngine(), genericCSIConfig())
+	assert.Error(t, err)
+}
+
+func TestGenericCSIAdapter_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(newCephTestScheme()).Build()
+	adapter, err := NewGenericCSIAdapter(c, translation.NewEngine(), genericCSIConfig())
+	require.NoError(t, err)
+
+	uvr := createUnifiedVolumeReplication()
+	uvr.Spec.Extensions = nil
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateReplica
+	uvr.Spec.VolumeMapping.Destination = replicationv1alpha1.VolumeDestination{VolumeHandle: "dest-volume", Namespace: "default"}
+	key := types.NamespacedName{Name: "test-uvr-vr", Namespace: "default"}
+
+	status, err := adapter.GetReplicationStatus(ctx, uvr)
+	require.NoError(t, err)
+	assert.Equal(t, ReplicationHealthUnknown, status.Health)
+	assert.Empty(t, status.DegradedReason, "a VolumeReplication not created yet is no unmapped state")
+
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+	vr := &VolumeReplication{}
+	require.NoError(t, c.Get(ctx, key, vr))
+	assert.Equal(t, "vendor-replication", vr.Spec.VolumeReplicationClass)
+	assert.Equal(t, "test-pvc", vr.Spec.PvcName)
+	assert.Equal(t, "standby", vr.Spec.ReplicationState)
+
+	// The driver reports its state capitalized
+	vr.Status.State = "Standby"
+	require.NoError(t, c.Update(ctx, vr))
+	status, err = adapter.GetReplicationStatus(ctx, uvr)
+	require.NoError(t, err)
+	assert.Equal(t, "replica", status.State)
+	assert.Equal(t, ReplicationHealthHealthy, status.Health)
+
+	vr.Status.Conditions = []metav1.Condition{{Type: "Degraded", Status: metav1.ConditionTrue, Reason: "Resyncing"}}
+	vr.Status.State = "split"
+	require.NoError(t, c.Update(ctx, vr))
+	status, err = adapter.GetReplicationStatus(ctx, uvr)
+	require.NoError(t, err)
+	assert.Equal(t, "unknown", status.State, "states outside the map are not guessed")
+	assert.Equal(t, ReplicationHealthDegraded, status.Health)
+
+	vr.Status.Conditions = nil
+	require.NoError(t, c.Update(ctx, vr))
+	status, err = adapter.GetReplicationStatus(ctx, uvr)
+	require.NoError(t, err)
+	assert.Equal(t, ReplicationHealthUnknown, status.Health)
+	assert.Equal(t, DegradedReasonUnknownState, status.DegradedReason, "the state is reported but not mapped")
+
+	require.NoError(t, adapter.PromoteReplica(ctx, uvr))
+	require.NoError(t, c.Get(ctx, key, vr))
+	assert.Equal(t, "active", vr.Spec.ReplicationState)
+
+	require.NoError(t, adapter.ResyncReplication(ctx, uvr))
+	require.NoError(t, c.Get(ctx, key, vr))
+	assert.Equal(t, "catch-up", vr.Spec.ReplicationState)
+
+	// A state the map does not define cannot be requested
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStatePromoting
+	assert.Error(t, adapter.EnsureReplication(ctx, uvr))
+	supported, err := adapter.SupportsConfiguration(uvr)
+	require.NoError(t, err)
+	assert.False(t, supported)
+
+	require.NoError(t, adapter.DeleteReplication(ctx, uvr))
+	assert.Error(t, c.Get(ctx, key, vr))
+	require.NoError(t, adapter.DeleteReplication(ctx, uvr), "deleting twice is not an error")
+}
+
+func TestGenericCSIAdapterFactory_Supports(t *testing.T) {
+	factory := NewGenericCSIAdapterFactory()
+	uvr := createUnifiedVolumeReplication()
+	uvr.Spec.Extensions = nil
+	assert.False(t, factory.Supports(uvr), "nothing is supported before discovery")
+
+	discovered := func(available bool) *discovery.DiscoveryResult {
+		return &discovery.DiscoveryResult{Backends: map[translation.Backend]discovery.BackendDiscoveryResult{
+			translation.BackendCeph: {CRDs: []discovery.CRDInfo{{Name: StandardVolumeReplicationCRD, Available: available}}},
+		}}
+	}
+	factory.SetDiscoveryResult(discovered(true))
+	assert.False(t, factory.Supports(uvr), "nothing is supported without a VolumeReplicationClass")
+	require.NoError(t, factory.SetDefaults("vendor-replication", nil))
+	assert.True(t, factory.Supports(uvr))
+
+	trident := uvr.DeepCopy()
+	trident.Spec.Extensions = &replicationv1alpha1.Extensions{Trident: &replicationv1alpha1.TridentExtensions{}}
+	assert.False(t, factory.Supports(trident), "Trident has its own replication CRDs")
+	assert.False(t, factory.Supports(nil))
+
+	factory.SetDiscoveryResult(discovered(false))
+	assert.False(t, factory.Supports(uvr))
+}
+
+func TestGenericCSIAdapterFactory_Defaults(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(newCephTestScheme()).Build()
+	factory := NewGenericCSIAdapterFactory()
+
+	_, err := factory.CreateAdapter(translation.BackendGenericCSI, c, translation.NewEngine(), nil)
+	assert.ErrorContains(t, err, "volume replication class is required")
+
+	assert.ErrorContains(t, factory.SetDefaults("", nil), "volume replication class is required")
+	assert.ErrorContains(t, factory.SetDefaults("vendor-replication", map[string]string{"source": "active"}),
+		"state map must map the replica state")
+
+	require.NoError(t, factory.SetDefaults("vendor-replication", genericCSIConfig().StateMap))
+	require.NoError(t, factory.ValidateConfig(DefaultAdapterConfig(translation.BackendGenericCSI)),
+		"the operator's configuration without backend settings takes the defaults")
+	adapter, err := factory.CreateAdapter(translation.BackendGenericCSI, c, translation.NewEngine(), nil)
+	require.NoError(t, err)
+	generic := adapter.(*GenericCSIAdapter)
+	assert.Equal(t, "vendor-replication", generic.className)
+	driverState, err := generic.toDriverState("source")
+	require.NoError(t, err)
+	assert.Equal(t, "active", driverState)
+
+	// A configuration naming its own class keeps it
+	config := genericCSIConfig()
+	config.VolumeReplicationClass = "other-replication"
+	adapter, err = factory.CreateAdapter(translation.BackendGenericCSI, c, translation.NewEngine(), config)
+	require.NoError(t, err)
+	assert.Equal(t, "other-replication", adapter.(*GenericCSIAdapter).className)
+}
+
+func TestParseGenericCSIStateMap(t *testing.T) {
+	states, err := ParseGenericCSIStateMap(" source=active, replica=standby ,syncing=catch-up")
+	require.NoError(t, err)
+	assert.Equal(t, genericCSIConfig().StateMap, states)
+
+	states, err = ParseGenericCSIStateMap("")
+	require.NoError(t, err)
+	assert.Empty(t, states)
+
+	for _, value := range []string{"source", "source=", "=active", "source=active,source=primary"} {
+		_, err := ParseGenericCSIStateMap(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestGenericCSISupports(t *testing.T) {
+	uvr := createUnifiedVolumeReplication()
+	uvr.Spec.Extensions = nil
+	result := &discovery.DiscoveryResult{Backends: map[translation.Backend]discovery.BackendDiscoveryResult{
+		translation.BackendCeph: {CRDs: []discovery.CRDInfo{{Name: StandardVolumeReplicationCRD, Available: true}}},
+	}}
+
+	registry := NewRegistry()
+	require.NoError(t, registry.RegisterFactory(NewCephAdapterFactory()))
+	FeedDiscoveryResult(registry, result)
+	assert.False(t, GenericCSISupports(registry, uvr), "not without a generic CSI factory")
+	assert.False(t, GenericCSISupports(nil, uvr))
+
+	factory := NewGenericCSIAdapterFactory()
+	require.NoError(t, factory.SetDefaults("vendor-replication", nil))
+	require.NoError(t, registry.RegisterFactory(factory))
+	assert.False(t, GenericCSISupports(registry, uvr), "not before discovery")
+	FeedDiscoveryResult(registry, result)
+	assert.True(t, GenericCSISupports(registry, uvr))
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+// preserveGroupMembers returns the volume list stored under spec.<field> with its first entry
+// replaced by primary. Backend specs are rebuilt from the UVR on every update, and the other
+// entries belong to group members that are managed separately.
+func preserveGroupMembers(existing *unstructured.Unstructured, field string, primary map[string]interface{}) []interface{} {
+	entries, found, _ := unstructured.NestedSlice(existing.Object, "spec", field)
+	if !found || len(entries) == 0 {
+		return []interface{}{primary}
+	}
+	entries[0] = primary
+	return entries
+}
+
+// indexOfVolumeEntry returns the index of the entry in spec.<field> whose key equals value, or -1
+func indexOfVolumeEntry(obj *unstructured.Unstructured, field, key, value string) int {
+	entries, _, _ := unstructured.NestedSlice(obj.Object, "spec", field)
+	for i, entry := range entries {
+		if m, ok := entry.(map[string]interface{}); ok && m[key] == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// appendVolumeEntry appends an entry to the volume list stored under spec.<field>
+func appendVolumeEntry(obj *unstructured.Unstructured, field string, entry map[string]interface{}) error {
+	entries, _, _ := unstructured.NestedSlice(obj.Object, "spec", field)
+	return unstructured.SetNestedSlice(obj.Object, append(entries, entry), "spec", field)
+}
+
+// removeVolumeEntry drops the entry at index from the volume list stored under spec.<field>
+func removeVolumeEntry(obj *unstructured.Unstructured, field string, index int) error {
+	entries, _, _ := unstructured.NestedSlice(obj.Object, "spec", field)
+	if index < 0 || index >= len(entries) {
+		return nil
+	}
+	return unstructured.SetNestedSlice(obj.Object, append(entries[:index], entries[index+1:]...), "spec", field)
+}
+
+// primaryVolumeMembers reports the primary volume as the only group member, for backends
+// that replicate one volume per relationship. Group members are never replicated there, so
+// they are reported as missing rather than assumed to be caught up.
+func primaryVolumeMembers(uvr *replicationv1alpha1.UnifiedVolumeReplication, lastSyncTime *time.Time) []MemberSyncStatus {
+	return []MemberSyncStatus{{PvcName: uvr.Spec.VolumeMapping.Source.PvcName, LastSyncTime: lastSyncTime}}
+}
+
+// groupVolumeMembers reports every volume listed in spec.<field> with the sync time of the
+// group, for backends whose sync points cover all volumes of the group at once
+func groupVolumeMembers(obj *unstructured.Unstructured, field, key string, lastSyncTime *time.Time) []MemberSyncStatus {
+	entries, _, _ := unstructured.NestedSlice(obj.Object, "spec", field)
+	members := make([]MemberSyncStatus, 0, len(entries))
+	for _, entry := range entries {
+		m, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if pvcName, _ := m[key].(string); pvcName != "" {
+			members = append(members, MemberSyncStatus{PvcName: pvcName, LastSyncTime: lastSyncTime})
+		}
+	}
+	if len(members) == 0 {
+		return nil
+	}
+	return members
+}
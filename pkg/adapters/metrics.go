@@ -0,0 +1,237 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+// AdapterMetricsPath is where the adapter metrics snapshot is served on the metrics server
+const AdapterMetricsPath = "/debug/adapter-metrics"
+
+// Operations whose last success is tracked per replication
+const (
+	OperationCreate  = "create"
+	OperationUpdate  = "update"
+	OperationPromote = "promote"
+	OperationDemote  = "demote"
+	OperationResync  = "resync"
+)
+
+// trackedOperations are the operations reported in the replication's status
+var trackedOperations = map[string]bool{
+	OperationCreate:  true,
+	OperationUpdate:  true,
+	OperationPromote: true,
+	OperationDemote:  true,
+	OperationResync:  true,
+}
+
+// maxLatencySamples bounds the latencies kept per operation for percentile calculation
+const maxLatencySamples = 1024
+
+// LatencyPercentiles summarizes the recent latencies of an operation
+type LatencyPercentiles struct {
+	Average string `json:"average"`
+	P50     string `json:"p50"`
+	P90     string `json:"p90"`
+	P99     string `json:"p99"`
+}
+
+// OperationMetricsSnapshot is a point-in-time view of the metrics of one backend operation
+type OperationMetricsSnapshot struct {
+	Metrics     AdapterMetrics     `json:"metrics"`
+	SuccessRate float64            `json:"success_rate"`
+	Latency     LatencyPercentiles `json:"latency"`
+}
+
+// operationRecord accumulates the metrics of one backend operation
+type operationRecord struct {
+	metrics   AdapterMetrics
+	totalTime time.Duration
+	latencies []time.Duration // ring buffer of the most recent samples
+	next      int
+}
+
+// OperationMetricsRecorder aggregates adapter operation metrics per backend and operation.
+// Adapters are created per replication and replaced on configuration changes, so metrics
+// are kept here rather than on the adapters to survive them.
+type OperationMetricsRecorder struct {
+	mu         sync.RWMutex
+	operations map[translation.Backend]map[string]*operationRecord
+
+	// lastSuccess holds when each tracked operation last succeeded, per replication
+	lastSuccess map[k8stypes.NamespacedName]map[string]time.Time
+}
+
+// NewOperationMetricsRecorder creates an empty recorder
+func NewOperationMetricsRecorder() *OperationMetricsRecorder {
+	return &OperationMetricsRecorder{
+		operations:  make(map[translation.Backend]map[string]*operationRecord),
+		lastSuccess: make(map[k8stypes.NamespacedName]map[string]time.Time),
+	}
+}
+
+var operationMetrics = NewOperationMetricsRecorder()
+
+// GetOperationMetrics returns the recorder shared by all adapters
+func GetOperationMetrics() *OperationMetricsRecorder {
+	return operationMetrics
+}
+
+// Record adds the outcome of one operation
+func (r *OperationMetricsRecorder) Record(backend translation.Backend, operation string, success bool, latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	operations, ok := r.operations[backend]
+	if !ok {
+		operations = make(map[string]*operationRecord)
+		r.operations[backend] = operations
+	}
+	record, ok := operations[operation]
+	if !ok {
+		record = &operationRecord{}
+		operations[operation] = record
+	}
+
+	record.metrics.TotalOperations++
+	if success {
+		record.metrics.SuccessfulOps++
+	} else {
+		record.metrics.FailedOps++
+	}
+	record.totalTime += latency
+	record.metrics.AverageLatency = record.totalTime / time.Duration(record.metrics.TotalOperations)
+	record.metrics.LastOperationTime = time.Now()
+
+	if len(record.latencies) < maxLatencySamples {
+		record.latencies = append(record.latencies, latency)
+	} else {
+		record.latencies[record.next] = latency
+		record.next = (record.next + 1) % maxLatencySamples
+	}
+}
+
+// RecordSuccess notes that a tracked operation succeeded on a replication at the given time.
+// Untracked operations are ignored.
+func (r *OperationMetricsRecorder) RecordSuccess(replication k8stypes.NamespacedName, operation string, at time.Time) {
+	if !trackedOperations[operation] {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	successes, ok := r.lastSuccess[replication]
+	if !ok {
+		successes = make(map[string]time.Time)
+		r.lastSuccess[replication] = successes
+	}
+	successes[operation] = at
+}
+
+// LastSuccesses returns when each tracked operation last succeeded on a replication
+func (r *OperationMetricsRecorder) LastSuccesses(replication k8stypes.NamespacedName) map[string]time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	successes := make(map[string]time.Time, len(r.lastSuccess[replication]))
+	for operation, at := range r.lastSuccess[replication] {
+		successes[operation] = at
+	}
+	return successes
+}
+
+// ForgetReplication drops the last-success times of a deleted replication
+func (r *OperationMetricsRecorder) ForgetReplication(replication k8stypes.NamespacedName) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.lastSuccess, replication)
+}
+
+// Snapshot returns the current metrics keyed by backend and operation
+func (r *OperationMetricsRecorder) Snapshot() map[translation.Backend]map[string]OperationMetricsSnapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshot := make(map[translation.Backend]map[string]OperationMetricsSnapshot, len(r.operations))
+	for backend, operations := range r.operations {
+		snapshot[backend] = make(map[string]OperationMetricsSnapshot, len(operations))
+		for operation, record := range operations {
+			latencies := append([]time.Duration(nil), record.latencies...)
+			sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+			snapshot[backend][operation] = OperationMetricsSnapshot{
+				Metrics:     record.metrics,
+				SuccessRate: record.metrics.CalculateSuccessRate(),
+				Latency: LatencyPercentiles{
+					Average: record.metrics.AverageLatency.String(),
+					P50:     latencyPercentile(latencies, 50).String(),
+					P90:     latencyPercentile(latencies, 90).String(),
+					P99:     latencyPercentile(latencies, 99).String(),
+				},
+			}
+		}
+	}
+	return snapshot
+}
+
+// Reset drops all recorded metrics
+func (r *OperationMetricsRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.operations = make(map[translation.Backend]map[string]*operationRecord)
+	r.lastSuccess = make(map[k8stypes.NamespacedName]map[string]time.Time)
+}
+
+// ServeHTTP writes the snapshot as indented JSON
+func (r *OperationMetricsRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := json.MarshalIndent(r.Snapshot(), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(append(body, '\n'))
+}
+
+// latencyPercentile returns the nearest-rank percentile of sorted latencies
+func latencyPercentile(sorted []time.Duration, percentile int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (percentile*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+func TestLatencyPercentile(t *testing.T) {
+	var latencies []time.Duration
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	assert.Equal(t, time.Duration(0), latencyPercentile(nil, 50))
+	assert.Equal(t, 50*time.Millisecond, latencyPercentile(latencies, 50))
+	assert.Equal(t, 90*time.Millisecond, latencyPercentile(latencies, 90))
+	assert.Equal(t, 99*time.Millisecond, latencyPercentile(latencies, 99))
+	assert.Equal(t, 7*time.Millisecond, latencyPercentile([]time.Duration{7 * time.Millisecond}, 99))
+}
+
+func TestOperationMetricsEndpoint(t *testing.T) {
+	ctx := context.Background()
+	GetOperationMetrics().Reset()
+	defer GetOperationMetrics().Reset()
+
+	adapter, err := NewTridentAdapter(fake.NewClientBuilder().Build(), translation.NewEngine())
+	require.NoError(t, err)
+
+	uvr := createTestUVRForTrident("test-metrics", "default")
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+	// The relationship has no status yet, which counts as a failed status query
+	_, err = adapter.GetReplicationStatus(ctx, uvr)
+	require.Error(t, err)
+
+	rec := httptest.NewRecorder()
+	GetOperationMetrics().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, AdapterMetricsPath, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var snapshot map[string]map[string]OperationMetricsSnapshot
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &snapshot))
+	require.Contains(t, snapshot, string(translation.BackendTrident))
+
+	operations := snapshot[string(translation.BackendTrident)]
+	assert.Contains(t, operations, "create")
+	assert.Contains(t, operations, "update")
+	assert.Contains(t, operations, "status")
+
+	create := operations["create"]
+	assert.Equal(t, int64(1), create.Metrics.TotalOperations)
+	assert.Equal(t, 100.0, create.SuccessRate)
+	assert.NotEmpty(t, create.Latency.P50)
+	assert.NotEmpty(t, create.Latency.P99)
+
+	status := operations["status"]
+	assert.Equal(t, int64(1), status.Metrics.FailedOps)
+	assert.Equal(t, 0.0, status.SuccessRate)
+
+	rec = httptest.NewRecorder()
+	GetOperationMetrics().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, AdapterMetricsPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestOperationLastSuccesses(t *testing.T) {
+	ctx := context.Background()
+	GetOperationMetrics().Reset()
+	defer GetOperationMetrics().Reset()
+
+	adapter, err := NewTridentAdapter(fake.NewClientBuilder().Build(), translation.NewEngine())
+	require.NoError(t, err)
+
+	uvr := createTestUVRForTrident("test-last-success", "default")
+	key := client.ObjectKeyFromObject(uvr)
+
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+	successes := GetOperationMetrics().LastSuccesses(key)
+	assert.Contains(t, successes, OperationCreate)
+	assert.NotContains(t, successes, OperationPromote)
+
+	// A re-apply without a state change is an update, not a transition
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+	successes = GetOperationMetrics().LastSuccesses(key)
+	assert.Contains(t, successes, OperationUpdate)
+	assert.NotContains(t, successes, OperationPromote)
+	assert.NotContains(t, successes, OperationDemote)
+
+	require.NoError(t, adapter.PromoteReplica(ctx, uvr))
+	promoted := GetOperationMetrics().LastSuccesses(key)[OperationPromote]
+	assert.False(t, promoted.IsZero())
+
+	require.NoError(t, adapter.DemoteSource(ctx, uvr))
+	assert.Contains(t, GetOperationMetrics().LastSuccesses(key), OperationDemote)
+
+	require.NoError(t, adapter.ResyncReplication(ctx, uvr))
+	successes = GetOperationMetrics().LastSuccesses(key)
+	assert.Contains(t, successes, OperationResync)
+	assert.Equal(t, promoted, successes[OperationPromote], "other operations keep their time")
+
+	// Status queries are not tracked, and a delete forgets the replication
+	assert.NotContains(t, successes, "status")
+	require.NoError(t, adapter.DeleteReplication(ctx, uvr))
+	assert.Empty(t, GetOperationMetrics().LastSuccesses(key))
+}
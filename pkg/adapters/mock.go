@@ -132,7 +132,7 @@ func NewMockAdapter(backend translation.Backend, client client.Client, translato
 
 // EnsureReplication ensures the mock replication is in the desired state (idempotent)
 func (m *MockAdapter) EnsureReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
-	if err := m.simulateOperation("ensure"); err != nil {
+	if err := m.simulateOperation(ctx, "ensure"); err != nil {
 		return err
 	}
 
@@ -199,7 +199,7 @@ func (m *MockAdapter) EnsureReplication(ctx context.Context, uvr *replicationv1a
 
 // DeleteReplication deletes a mock replication
 func (m *MockAdapter) DeleteReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
-	if err := m.simulateOperation("delete"); err != nil {
+	if err := m.simulateOperation(ctx, "delete"); err != nil {
 		return err
 	}
 
@@ -229,7 +229,7 @@ func (m *MockAdapter) DeleteReplication(ctx context.Context, uvr *replicationv1a
 
 // GetReplicationStatus returns the status of a mock replication
 func (m *MockAdapter) GetReplicationStatus(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (*ReplicationStatus, error) {
-	if err := m.simulateOperation("get_status"); err != nil {
+	if err := m.simulateOperation(ctx, "get_status"); err != nil {
 		return nil, err
 	}
 
@@ -252,6 +252,8 @@ func (m *MockAdapter) GetReplicationStatus(ctx context.Context, uvr *replication
 		ObservedGeneration: mockRepl.ObservedGeneration,
 		Message:            "Mock replication running",
 	}
+	status.PrimaryCluster, status.PrimarySite = resolvePrimaryIdentity(uvr, mockRepl.State)
+	status.Direction = resolveReplicationDirection(mockRepl.State)
 
 	// Add conditions
 	status.Conditions = []StatusCondition{
@@ -275,7 +277,7 @@ func (m *MockAdapter) GetReplicationStatus(ctx context.Context, uvr *replication
 
 // PromoteReplica promotes a replica to source
 func (m *MockAdapter) PromoteReplica(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
-	if err := m.simulateOperation("promote"); err != nil {
+	if err := m.simulateOperation(ctx, "promote"); err != nil {
 		return err
 	}
 
@@ -284,16 +286,44 @@ func (m *MockAdapter) PromoteReplica(ctx context.Context, uvr *replicationv1alph
 
 // DemoteSource demotes a source to replica
 func (m *MockAdapter) DemoteSource(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
-	if err := m.simulateOperation("demote"); err != nil {
+	if err := m.simulateOperation(ctx, "demote"); err != nil {
 		return err
 	}
 
 	return m.changeState(uvr, "demoting", EventTypeDemoted, "Source demoted to replica")
 }
 
+// DrainWrites simulates fencing writes on the source and flushing them to the replica
+func (m *MockAdapter) DrainWrites(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (bool, error) {
+	if err := m.simulateOperation(ctx, "drain"); err != nil {
+		return false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mockRepl, exists := m.replications[m.getReplicationKey(uvr)]
+	if !exists {
+		return false, NewAdapterError(ErrorTypeResource, m.GetBackendType(), "drain", uvr.Name, "replication not found")
+	}
+
+	// The flush brings the replica up to date with everything written before the fence
+	mockRepl.LastSyncTime = time.Now()
+	if m.config.EventGeneration {
+		mockRepl.Events = append(mockRepl.Events, ReplicationEvent{
+			Type:      EventTypeDrained,
+			Timestamp: time.Now(),
+			Resource:  uvr.Name,
+			Message:   "Writes drained to replica",
+		})
+	}
+
+	return true, nil
+}
+
 // ResyncReplication resyncs a replication
 func (m *MockAdapter) ResyncReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
-	if err := m.simulateOperation("resync"); err != nil {
+	if err := m.simulateOperation(ctx, "resync"); err != nil {
 		return err
 	}
 
@@ -302,7 +332,7 @@ func (m *MockAdapter) ResyncReplication(ctx context.Context, uvr *replicationv1a
 
 // PauseReplication pauses a replication
 func (m *MockAdapter) PauseReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
-	if err := m.simulateOperation("pause"); err != nil {
+	if err := m.simulateOperation(ctx, "pause"); err != nil {
 		return err
 	}
 
@@ -311,7 +341,7 @@ func (m *MockAdapter) PauseReplication(ctx context.Context, uvr *replicationv1al
 
 // ResumeReplication resumes a paused replication
 func (m *MockAdapter) ResumeReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
-	if err := m.simulateOperation("resume"); err != nil {
+	if err := m.simulateOperation(ctx, "resume"); err != nil {
 		return err
 	}
 
@@ -320,7 +350,7 @@ func (m *MockAdapter) ResumeReplication(ctx context.Context, uvr *replicationv1a
 
 // FailoverReplication performs failover
 func (m *MockAdapter) FailoverReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
-	if err := m.simulateOperation("failover"); err != nil {
+	if err := m.simulateOperation(ctx, "failover"); err != nil {
 		return err
 	}
 
@@ -329,7 +359,7 @@ func (m *MockAdapter) FailoverReplication(ctx context.Context, uvr *replicationv
 
 // FailbackReplication performs failback
 func (m *MockAdapter) FailbackReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
-	if err := m.simulateOperation("failback"); err != nil {
+	if err := m.simulateOperation(ctx, "failback"); err != nil {
 		return err
 	}
 
@@ -373,11 +403,18 @@ func (m *MockAdapter) GetAllMockReplications() map[string]*MockReplication {
 	return replications
 }
 
-// simulateOperation simulates operation latency and failure
-func (m *MockAdapter) simulateOperation(operation string) error {
+// simulateOperation simulates operation latency and failure. The operation is abandoned
+// when ctx is done before the simulated latency has passed.
+func (m *MockAdapter) simulateOperation(ctx context.Context, operation string) error {
 	// Simulate latency
-	latency := m.calculateLatency()
-	time.Sleep(latency)
+	timer := time.NewTimer(m.calculateLatency())
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return NewAdapterErrorWithCause(ErrorTypeTimeout, m.GetBackendType(), operation, "",
+			fmt.Sprintf("mock operation %s interrupted", operation), ctx.Err())
+	}
 
 	// Simulate failure
 	m.mu.RLock()
@@ -500,6 +537,11 @@ func NewMockAdapterFactory(backend translation.Backend, mockConfig *MockConfig)
 	}
 }
 
+// IsMock reports that the factory creates simulated adapters
+func (f *MockAdapterFactory) IsMock() bool {
+	return true
+}
+
 // CreateAdapter creates a mock adapter
 func (f *MockAdapterFactory) CreateAdapter(backend translation.Backend, client client.Client, translator *translation.Engine, config *AdapterConfig) (ReplicationAdapter, error) {
 	return NewMockAdapter(backend, client, translator, config, f.mockConfig), nil
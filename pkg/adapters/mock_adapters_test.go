@@ -18,6 +18,7 @@ package adapters
 
 import (
 	"context"
+	goruntime "runtime"
 	"testing"
 	"time"
 
@@ -459,6 +460,7 @@ func TestMockPowerStoreAdapter(t *testing.T) {
 
 		// Should show degraded health due to session issues
 		assert.Equal(t, ReplicationHealthDegraded, status.Health)
+		assert.Equal(t, DegradedReasonSessionFailure, status.DegradedReason)
 		assert.Contains(t, status.Message, "Session connectivity issues")
 	})
 
@@ -569,6 +571,53 @@ func TestMockAdapterFactories(t *testing.T) {
 	})
 }
 
+func TestMockAdapterBackgroundProcessorCleanup(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, replicationv1alpha1.AddToScheme(scheme))
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+	translator := translation.NewEngine()
+
+	const adapterCount = 50
+	baseline := goruntime.NumGoroutine()
+
+	tridentAdapters := make([]*MockTridentAdapter, 0, adapterCount)
+	powerstoreAdapters := make([]*MockPowerStoreAdapter, 0, adapterCount)
+	for i := 0; i < adapterCount; i++ {
+		tridentAdapter := NewMockTridentAdapter(client, translator, nil)
+		powerstoreAdapter := NewMockPowerStoreAdapter(client, translator, nil)
+
+		// Initializing an adapter that is already running must not start a second processor
+		require.NoError(t, tridentAdapter.Initialize(ctx))
+		require.NoError(t, powerstoreAdapter.Initialize(ctx))
+
+		tridentAdapters = append(tridentAdapters, tridentAdapter)
+		powerstoreAdapters = append(powerstoreAdapters, powerstoreAdapter)
+	}
+	assert.LessOrEqual(t, goruntime.NumGoroutine(), baseline+2*adapterCount,
+		"one background processor per adapter")
+
+	for i := 0; i < adapterCount; i++ {
+		require.NoError(t, tridentAdapters[i].Cleanup(ctx))
+		require.NoError(t, powerstoreAdapters[i].Cleanup(ctx))
+	}
+
+	// Sample in place: assert.Eventually runs its condition on extra goroutines
+	deadline := time.Now().Add(5 * time.Second)
+	for goruntime.NumGoroutine() > baseline && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, goruntime.NumGoroutine(), baseline, "background processors should stop on cleanup")
+
+	// Cleanup is safe to repeat and Initialize restarts the processor
+	require.NoError(t, tridentAdapters[0].Cleanup(ctx))
+	require.NoError(t, tridentAdapters[0].Initialize(ctx))
+	tridentAdapters[0].mutex.RLock()
+	assert.NotNil(t, tridentAdapters[0].stopCh)
+	tridentAdapters[0].mutex.RUnlock()
+	require.NoError(t, tridentAdapters[0].Cleanup(ctx))
+}
+
 func TestMockRegistry(t *testing.T) {
 	t.Run("RegisterMockAdapters", func(t *testing.T) {
 		// Clear registry first
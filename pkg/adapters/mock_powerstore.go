@@ -44,6 +44,7 @@ type MockPowerStoreReplication struct {
 	NextSyncTime       *time.Time             `json:"next_sync_time,omitempty"`
 	SyncProgress       *SyncProgress          `json:"sync_progress,omitempty"`
 	Health             ReplicationHealth      `json:"health"`
+	DegradedReason     DegradedReason         `json:"degraded_reason,omitempty"`
 	Message            string                 `json:"message"`
 	Conditions         []StatusCondition      `json:"conditions"`
 	BackendSpecific    map[string]interface{} `json:"backend_specific"`
@@ -117,6 +118,10 @@ type MockPowerStoreAdapter struct {
 	lastHealthCheck time.Time
 	isHealthy       bool
 	sessions        map[string]string // replication key -> session ID
+
+	// stopCh stops the background state processor; nil while it is not running
+	stopCh     chan struct{}
+	background sync.WaitGroup
 }
 
 // NewMockPowerStoreAdapter creates a new mock PowerStore adapter
@@ -145,7 +150,7 @@ func NewMockPowerStoreAdapter(client client.Client, translator *translation.Engi
 
 	// Start background processes if auto-progression is enabled
 	if config.AutoProgressStates {
-		go adapter.backgroundStateProcessor()
+		adapter.startBackgroundProcessor()
 	}
 
 	return adapter
@@ -256,7 +261,7 @@ func (mpa *MockPowerStoreAdapter) DeleteReplication(ctx context.Context, uvr *re
 	mpa.simulateLatency()
 
 	if !mpa.simulateSuccess(mpa.config.DeleteSuccessRate) {
-		mpa.BaseAdapter.updateMetrics("delete", false, startTime)
+		mpa.BaseAdapter.updateMetrics(uvr, "delete", false, startTime)
 		return NewAdapterError(ErrorTypeConnection, translation.BackendPowerStore, "delete", uvr.Name, "simulated deletion failure")
 	}
 
@@ -266,7 +271,7 @@ func (mpa *MockPowerStoreAdapter) DeleteReplication(ctx context.Context, uvr *re
 	replicationKey := fmt.Sprintf("%s/%s", uvr.Namespace, uvr.Name)
 	if _, exists := mpa.replications[replicationKey]; !exists {
 		// Deletion is idempotent - not an error
-		mpa.BaseAdapter.updateMetrics("delete", true, startTime)
+		mpa.BaseAdapter.updateMetrics(uvr, "delete", true, startTime)
 		logger.Info("Mock PowerStore replication already deleted or not found")
 		return nil
 	}
@@ -281,7 +286,7 @@ func (mpa *MockPowerStoreAdapter) DeleteReplication(ctx context.Context, uvr *re
 		Resource:  replicationKey,
 	})
 
-	mpa.BaseAdapter.updateMetrics("delete", true, startTime)
+	mpa.BaseAdapter.updateMetrics(uvr, "delete", true, startTime)
 	logger.Info("Successfully deleted mock PowerStore replication")
 	return nil
 }
@@ -295,7 +300,7 @@ func (mpa *MockPowerStoreAdapter) GetReplicationStatus(ctx context.Context, uvr
 	mpa.simulateLatency()
 
 	if !mpa.simulateSuccess(mpa.config.StatusSuccessRate) {
-		mpa.BaseAdapter.updateMetrics("status", false, startTime)
+		mpa.BaseAdapter.updateMetrics(uvr, "status", false, startTime)
 		return nil, NewAdapterError(ErrorTypeConnection, translation.BackendPowerStore, "status", uvr.Name, "simulated status retrieval failure")
 	}
 
@@ -305,26 +310,27 @@ func (mpa *MockPowerStoreAdapter) GetReplicationStatus(ctx context.Context, uvr
 	replicationKey := fmt.Sprintf("%s/%s", uvr.Namespace, uvr.Name)
 	replication, exists := mpa.replications[replicationKey]
 	if !exists {
-		mpa.BaseAdapter.updateMetrics("status", false, startTime)
+		mpa.BaseAdapter.updateMetrics(uvr, "status", false, startTime)
 		return nil, NewAdapterError(ErrorTypeResource, translation.BackendPowerStore, "status", uvr.Name, "replication not found")
 	}
 
 	// Simulate session failures
 	if mpa.simulateSuccess(mpa.config.SessionFailureRate) {
 		replication.Health = ReplicationHealthDegraded
+		replication.DegradedReason = DegradedReasonSessionFailure
 		replication.Message = "Session connectivity issues"
 	}
 
 	// Translate PowerStore state back to unified state
 	unifiedState, err := mpa.BaseAdapter.translator.TranslateStateFromBackend(translation.BackendPowerStore, replication.State)
 	if err != nil {
-		mpa.BaseAdapter.updateMetrics("status", false, startTime)
+		mpa.BaseAdapter.updateMetrics(uvr, "status", false, startTime)
 		return nil, NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendPowerStore, "status", uvr.Name, "failed to translate state", err)
 	}
 
 	unifiedMode, err := mpa.BaseAdapter.translator.TranslateModeFromBackend(translation.BackendPowerStore, replication.Mode)
 	if err != nil {
-		mpa.BaseAdapter.updateMetrics("status", false, startTime)
+		mpa.BaseAdapter.updateMetrics(uvr, "status", false, startTime)
 		return nil, NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendPowerStore, "status", uvr.Name, "failed to translate mode", err)
 	}
 
@@ -334,6 +340,7 @@ func (mpa *MockPowerStoreAdapter) GetReplicationStatus(ctx context.Context, uvr
 		backendSpecific[k] = v
 	}
 	backendSpecific["rpo_compliance"] = replication.RPOCompliance
+	rpoCompliance := replication.RPOCompliance
 	backendSpecific["rto_estimate"] = replication.RTOEstimate.String()
 	backendSpecific["metro_latency_ms"] = mpa.config.MetroLatencyMs
 
@@ -341,6 +348,7 @@ func (mpa *MockPowerStoreAdapter) GetReplicationStatus(ctx context.Context, uvr
 		State:              unifiedState,
 		Mode:               unifiedMode,
 		Health:             replication.Health,
+		DegradedReason:     replication.DegradedReason,
 		LastSyncTime:       replication.LastSyncTime,
 		NextSyncTime:       replication.NextSyncTime,
 		SyncProgress:       replication.SyncProgress,
@@ -348,9 +356,12 @@ func (mpa *MockPowerStoreAdapter) GetReplicationStatus(ctx context.Context, uvr
 		Message:            replication.Message,
 		ObservedGeneration: replication.Version,
 		Conditions:         replication.Conditions,
+		RPOCompliance:      &rpoCompliance,
 	}
+	status.PrimaryCluster, status.PrimarySite = resolvePrimaryIdentity(uvr, unifiedState)
+	status.Direction = resolveReplicationDirection(unifiedState)
 
-	mpa.BaseAdapter.updateMetrics("status", true, startTime)
+	mpa.BaseAdapter.updateMetrics(uvr, "status", true, startTime)
 	return status, nil
 }
 
@@ -518,6 +529,11 @@ func (mpa *MockPowerStoreAdapter) Initialize(ctx context.Context) error {
 	mpa.lastHealthCheck = time.Now()
 	mpa.mutex.Unlock()
 
+	// Resume state progression if a previous Cleanup stopped it
+	if mpa.config.AutoProgressStates {
+		mpa.startBackgroundProcessor()
+	}
+
 	return mpa.BaseAdapter.Initialize(ctx)
 }
 
@@ -532,6 +548,8 @@ func (mpa *MockPowerStoreAdapter) Cleanup(ctx context.Context) error {
 	mpa.sessions = make(map[string]string)
 	mpa.mutex.Unlock()
 
+	mpa.stopBackgroundProcessor()
+
 	return mpa.BaseAdapter.Cleanup(ctx)
 }
 
@@ -689,11 +707,45 @@ func (mpa *MockPowerStoreAdapter) addEvent(event ReplicationEvent) {
 	mpa.events = append(mpa.events, event)
 }
 
-func (mpa *MockPowerStoreAdapter) backgroundStateProcessor() {
+// startBackgroundProcessor starts the background state processor unless it is already running
+func (mpa *MockPowerStoreAdapter) startBackgroundProcessor() {
+	mpa.mutex.Lock()
+	defer mpa.mutex.Unlock()
+
+	if mpa.stopCh != nil {
+		return
+	}
+	mpa.stopCh = make(chan struct{})
+	mpa.background.Add(1)
+	go mpa.backgroundStateProcessor(mpa.stopCh)
+}
+
+// stopBackgroundProcessor stops the background state processor and waits for it to exit
+func (mpa *MockPowerStoreAdapter) stopBackgroundProcessor() {
+	mpa.mutex.Lock()
+	stopCh := mpa.stopCh
+	mpa.stopCh = nil
+	mpa.mutex.Unlock()
+
+	if stopCh != nil {
+		close(stopCh)
+	}
+	mpa.background.Wait()
+}
+
+func (mpa *MockPowerStoreAdapter) backgroundStateProcessor(stopCh <-chan struct{}) {
+	defer mpa.background.Done()
+
 	ticker := time.NewTicker(mpa.config.StateTransitionDelay)
 	defer ticker.Stop()
 
-	for range ticker.C {
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		}
+
 		mpa.mutex.Lock()
 		for _, replication := range mpa.replications {
 			mpa.updateSyncProgress(replication)
@@ -799,6 +851,11 @@ func (factory *MockPowerStoreAdapterFactory) GetInfo() AdapterFactoryInfo {
 	return factory.info
 }
 
+// IsMock reports that the factory creates simulated adapters
+func (factory *MockPowerStoreAdapterFactory) IsMock() bool {
+	return true
+}
+
 // Supports checks if this factory supports the given configuration
 func (factory *MockPowerStoreAdapterFactory) Supports(uvr *replicationv1alpha1.UnifiedVolumeReplication) bool {
 	// Mock factory supports all configurations
@@ -24,6 +24,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
 	"github.com/unified-replication/operator/pkg/translation"
 )
 
@@ -330,6 +331,64 @@ func TestMockAdapter(t *testing.T) {
 		assert.Equal(t, "5m", mockRepl.SyncProgress.EstimatedTime)
 	})
 
+	t.Run("Primary identity", func(t *testing.T) {
+		mockConfig := DefaultMockConfig()
+		mockConfig.StateTransitions = false
+
+		adapter := NewMockAdapter(translation.BackendCeph, client, translator, config, mockConfig)
+		ctx := context.Background()
+		_ = adapter.Initialize(ctx)
+
+		uvr := createTestUVR("test-repl", "default")
+
+		err := adapter.EnsureReplication(ctx, uvr)
+		require.NoError(t, err)
+
+		// Local volume is the replica, so the primary lives on the destination side
+		status, err := adapter.GetReplicationStatus(ctx, uvr)
+		require.NoError(t, err)
+		assert.Equal(t, "dest-cluster", status.PrimaryCluster)
+		assert.Equal(t, "us-west-1", status.PrimarySite)
+
+		// Promoting the replica side moves the primary to the local cluster
+		err = adapter.PromoteReplica(ctx, uvr)
+		require.NoError(t, err)
+
+		status, err = adapter.GetReplicationStatus(ctx, uvr)
+		require.NoError(t, err)
+		assert.Equal(t, "source-cluster", status.PrimaryCluster)
+		assert.Equal(t, "us-east-1", status.PrimarySite)
+	})
+
+	t.Run("Replication direction", func(t *testing.T) {
+		mockConfig := DefaultMockConfig()
+		mockConfig.StateTransitions = false
+
+		adapter := NewMockAdapter(translation.BackendCeph, client, translator, config, mockConfig)
+		ctx := context.Background()
+		_ = adapter.Initialize(ctx)
+
+		uvr := createTestUVR("test-repl", "default")
+		uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+
+		err := adapter.EnsureReplication(ctx, uvr)
+		require.NoError(t, err)
+
+		// Source endpoint is primary, data flows the configured way
+		status, err := adapter.GetReplicationStatus(ctx, uvr)
+		require.NoError(t, err)
+		assert.Equal(t, ReplicationDirectionForward, status.Direction)
+
+		// Promoting the destination demotes the local source and reverses the flow
+		err = adapter.DemoteSource(ctx, uvr)
+		require.NoError(t, err)
+
+		status, err = adapter.GetReplicationStatus(ctx, uvr)
+		require.NoError(t, err)
+		assert.Equal(t, ReplicationDirectionReverse, status.Direction)
+		assert.Equal(t, "dest-cluster", status.PrimaryCluster)
+	})
+
 	t.Run("Latency simulation", func(t *testing.T) {
 		mockConfig := DefaultMockConfig()
 		mockConfig.LatencyMin = 50 * time.Millisecond
@@ -102,6 +102,10 @@ type MockTridentAdapter struct {
 	mutex           sync.RWMutex
 	lastHealthCheck time.Time
 	isHealthy       bool
+
+	// stopCh stops the background state processor; nil while it is not running
+	stopCh     chan struct{}
+	background sync.WaitGroup
 }
 
 // NewMockTridentAdapter creates a new mock Trident adapter
@@ -129,7 +133,7 @@ func NewMockTridentAdapter(client client.Client, translator *translation.Engine,
 
 	// Start background processes if auto-progression is enabled
 	if config.AutoProgressStates {
-		go adapter.backgroundStateProcessor()
+		adapter.startBackgroundProcessor()
 	}
 
 	return adapter
@@ -239,7 +243,7 @@ func (mta *MockTridentAdapter) DeleteReplication(ctx context.Context, uvr *repli
 	mta.simulateLatency()
 
 	if !mta.simulateSuccess(mta.config.DeleteSuccessRate) {
-		mta.BaseAdapter.updateMetrics("delete", false, startTime)
+		mta.BaseAdapter.updateMetrics(uvr, "delete", false, startTime)
 		return NewAdapterError(ErrorTypeConnection, translation.BackendTrident, "delete", uvr.Name, "simulated deletion failure")
 	}
 
@@ -249,7 +253,7 @@ func (mta *MockTridentAdapter) DeleteReplication(ctx context.Context, uvr *repli
 	replicationKey := fmt.Sprintf("%s/%s", uvr.Namespace, uvr.Name)
 	if _, exists := mta.replications[replicationKey]; !exists {
 		// Deletion is idempotent - not an error
-		mta.BaseAdapter.updateMetrics("delete", true, startTime)
+		mta.BaseAdapter.updateMetrics(uvr, "delete", true, startTime)
 		logger.Info("Mock Trident replication already deleted or not found")
 		return nil
 	}
@@ -262,7 +266,7 @@ func (mta *MockTridentAdapter) DeleteReplication(ctx context.Context, uvr *repli
 		Resource:  replicationKey,
 	})
 
-	mta.BaseAdapter.updateMetrics("delete", true, startTime)
+	mta.BaseAdapter.updateMetrics(uvr, "delete", true, startTime)
 	logger.Info("Successfully deleted mock Trident replication")
 	return nil
 }
@@ -276,7 +280,7 @@ func (mta *MockTridentAdapter) GetReplicationStatus(ctx context.Context, uvr *re
 	mta.simulateLatency()
 
 	if !mta.simulateSuccess(mta.config.StatusSuccessRate) {
-		mta.BaseAdapter.updateMetrics("status", false, startTime)
+		mta.BaseAdapter.updateMetrics(uvr, "status", false, startTime)
 		return nil, NewAdapterError(ErrorTypeConnection, translation.BackendTrident, "status", uvr.Name, "simulated status retrieval failure")
 	}
 
@@ -286,20 +290,20 @@ func (mta *MockTridentAdapter) GetReplicationStatus(ctx context.Context, uvr *re
 	replicationKey := fmt.Sprintf("%s/%s", uvr.Namespace, uvr.Name)
 	replication, exists := mta.replications[replicationKey]
 	if !exists {
-		mta.BaseAdapter.updateMetrics("status", false, startTime)
+		mta.BaseAdapter.updateMetrics(uvr, "status", false, startTime)
 		return nil, NewAdapterError(ErrorTypeResource, translation.BackendTrident, "status", uvr.Name, "replication not found")
 	}
 
 	// Translate Trident state back to unified state
 	unifiedState, err := mta.BaseAdapter.translator.TranslateStateFromBackend(translation.BackendTrident, replication.State)
 	if err != nil {
-		mta.BaseAdapter.updateMetrics("status", false, startTime)
+		mta.BaseAdapter.updateMetrics(uvr, "status", false, startTime)
 		return nil, NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendTrident, "status", uvr.Name, "failed to translate state", err)
 	}
 
 	unifiedMode, err := mta.BaseAdapter.translator.TranslateModeFromBackend(translation.BackendTrident, replication.Mode)
 	if err != nil {
-		mta.BaseAdapter.updateMetrics("status", false, startTime)
+		mta.BaseAdapter.updateMetrics(uvr, "status", false, startTime)
 		return nil, NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendTrident, "status", uvr.Name, "failed to translate mode", err)
 	}
 
@@ -315,8 +319,10 @@ func (mta *MockTridentAdapter) GetReplicationStatus(ctx context.Context, uvr *re
 		ObservedGeneration: replication.Version,
 		Conditions:         replication.Conditions,
 	}
+	status.PrimaryCluster, status.PrimarySite = resolvePrimaryIdentity(uvr, unifiedState)
+	status.Direction = resolveReplicationDirection(unifiedState)
 
-	mta.BaseAdapter.updateMetrics("status", true, startTime)
+	mta.BaseAdapter.updateMetrics(uvr, "status", true, startTime)
 	return status, nil
 }
 
@@ -466,6 +472,11 @@ func (mta *MockTridentAdapter) Initialize(ctx context.Context) error {
 	mta.lastHealthCheck = time.Now()
 	mta.mutex.Unlock()
 
+	// Resume state progression if a previous Cleanup stopped it
+	if mta.config.AutoProgressStates {
+		mta.startBackgroundProcessor()
+	}
+
 	return mta.BaseAdapter.Initialize(ctx)
 }
 
@@ -479,6 +490,8 @@ func (mta *MockTridentAdapter) Cleanup(ctx context.Context) error {
 	mta.events = make([]ReplicationEvent, 0)
 	mta.mutex.Unlock()
 
+	mta.stopBackgroundProcessor()
+
 	return mta.BaseAdapter.Cleanup(ctx)
 }
 
@@ -613,11 +626,45 @@ func (mta *MockTridentAdapter) addEvent(event ReplicationEvent) {
 	mta.events = append(mta.events, event)
 }
 
-func (mta *MockTridentAdapter) backgroundStateProcessor() {
+// startBackgroundProcessor starts the background state processor unless it is already running
+func (mta *MockTridentAdapter) startBackgroundProcessor() {
+	mta.mutex.Lock()
+	defer mta.mutex.Unlock()
+
+	if mta.stopCh != nil {
+		return
+	}
+	mta.stopCh = make(chan struct{})
+	mta.background.Add(1)
+	go mta.backgroundStateProcessor(mta.stopCh)
+}
+
+// stopBackgroundProcessor stops the background state processor and waits for it to exit
+func (mta *MockTridentAdapter) stopBackgroundProcessor() {
+	mta.mutex.Lock()
+	stopCh := mta.stopCh
+	mta.stopCh = nil
+	mta.mutex.Unlock()
+
+	if stopCh != nil {
+		close(stopCh)
+	}
+	mta.background.Wait()
+}
+
+func (mta *MockTridentAdapter) backgroundStateProcessor(stopCh <-chan struct{}) {
+	defer mta.background.Done()
+
 	ticker := time.NewTicker(mta.config.StateTransitionDelay)
 	defer ticker.Stop()
 
-	for range ticker.C {
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		}
+
 		mta.mutex.Lock()
 		for _, replication := range mta.replications {
 			mta.updateSyncProgress(replication)
@@ -711,6 +758,11 @@ func (factory *MockTridentAdapterFactory) GetInfo() AdapterFactoryInfo {
 	return factory.info
 }
 
+// IsMock reports that the factory creates simulated adapters
+func (factory *MockTridentAdapterFactory) IsMock() bool {
+	return true
+}
+
 // Supports checks if this factory supports the given configuration
 func (factory *MockTridentAdapterFactory) Supports(uvr *replicationv1alpha1.UnifiedVolumeReplication) bool {
 	// Mock factory supports all configurations
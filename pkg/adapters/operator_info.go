@@ -0,0 +1,118 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+// OperatorInfoPath is the metrics server path serving the operator's version and capabilities
+const OperatorInfoPath = "/debug/operator-info"
+
+// OperatorInfo describes the running operator so clients can adapt to its feature set
+type OperatorInfo struct {
+	// Version of the operator build
+	Version string `json:"version"`
+	// APIVersions served by the operator, e.g. "replication.unified.io/v1alpha1"
+	APIVersions []string `json:"apiVersions"`
+	// Backends lists the registered adapters, sorted by backend
+	Backends []BackendInfo `json:"backends"`
+	// FeatureGates lists, sorted, the gates enabled for replications that do not set them in
+	// spec.featureGates
+	FeatureGates []replicationv1alpha1.FeatureGate `json:"featureGates"`
+}
+
+// BackendInfo describes a registered adapter, as reported by its factory
+type BackendInfo struct {
+	Backend     translation.Backend `json:"backend"`
+	Name        string              `json:"name"`
+	Version     string              `json:"version"`
+	Description string              `json:"description,omitempty"`
+}
+
+// OperatorInfoHandler serves the operator's version, API versions, registered adapters and
+// enabled feature gates as JSON
+type OperatorInfoHandler struct {
+	version      string
+	registry     Registry
+	featureGates map[replicationv1alpha1.FeatureGate]bool
+}
+
+// NewOperatorInfoHandler creates a handler reporting the adapter factories of registry and
+// the gates featureGates enables. No adapter is created to serve a request.
+func NewOperatorInfoHandler(version string, registry Registry, featureGates map[replicationv1alpha1.FeatureGate]bool) *OperatorInfoHandler {
+	return &OperatorInfoHandler{
+		version:      version,
+		registry:     registry,
+		featureGates: featureGates,
+	}
+}
+
+// Info returns the current operator info
+func (h *OperatorInfoHandler) Info() OperatorInfo {
+	info := OperatorInfo{
+		Version:      h.version,
+		APIVersions:  []string{replicationv1alpha1.GroupVersion.String()},
+		Backends:     []BackendInfo{},
+		FeatureGates: []replicationv1alpha1.FeatureGate{},
+	}
+
+	for _, factory := range h.registry.ListFactories() {
+		factoryInfo := factory.GetInfo()
+		info.Backends = append(info.Backends, BackendInfo{
+			Backend:     factory.GetBackendType(),
+			Name:        factoryInfo.Name,
+			Version:     factoryInfo.Version,
+			Description: factoryInfo.Description,
+		})
+	}
+	sort.Slice(info.Backends, func(i, j int) bool {
+		return info.Backends[i].Backend < info.Backends[j].Backend
+	})
+
+	for gate, enabled := range h.featureGates {
+		if enabled {
+			info.FeatureGates = append(info.FeatureGates, gate)
+		}
+	}
+	sort.Slice(info.FeatureGates, func(i, j int) bool {
+		return info.FeatureGates[i] < info.FeatureGates[j]
+	})
+	return info
+}
+
+// ServeHTTP writes the info as indented JSON
+func (h *OperatorInfoHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := json.MarshalIndent(h.Info(), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(append(body, '\n'))
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+func TestOperatorInfoEndpoint(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.RegisterFactory(NewTridentAdapterFactory()))
+	require.NoError(t, registry.RegisterFactory(NewCephAdapterFactory()))
+
+	gates := map[replicationv1alpha1.FeatureGate]bool{
+		replicationv1alpha1.FeatureGateAdaptiveSchedule: true,
+		"Disabled": false,
+	}
+	handler := NewOperatorInfoHandler("v1.2.3", registry, gates)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, OperatorInfoPath, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var info OperatorInfo
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &info))
+	assert.Equal(t, "v1.2.3", info.Version)
+	assert.Equal(t, []string{"replication.unified.io/v1alpha1"}, info.APIVersions)
+	assert.Equal(t, []replicationv1alpha1.FeatureGate{replicationv1alpha1.FeatureGateAdaptiveSchedule}, info.FeatureGates,
+		"only enabled gates are listed")
+
+	require.Len(t, info.Backends, len(registry.GetSupportedBackends()), "every registered adapter is reported")
+	for _, backend := range info.Backends {
+		factory, err := registry.GetFactory(backend.Backend)
+		require.NoError(t, err)
+		assert.Equal(t, factory.GetInfo().Name, backend.Name)
+		assert.Equal(t, factory.GetInfo().Version, backend.Version)
+		assert.Equal(t, factory.GetInfo().Description, backend.Description)
+	}
+	assert.Equal(t, translation.BackendCeph, info.Backends[0].Backend, "backends are sorted")
+
+	// No gate enabled is reported as an empty list
+	info = NewOperatorInfoHandler("v1.2.3", registry, nil).Info()
+	assert.Equal(t, []replicationv1alpha1.FeatureGate{}, info.FeatureGates)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, OperatorInfoPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+// OwnerUIDLabel records the UID of the UVR that owns a backend resource. Two UVRs can derive
+// the same backend resource name; the label keeps the second from updating the first's.
+const OwnerUIDLabel = "unified-replication.io/owner-uid"
+
+// OwnedResourceKinds are the kinds of the backend resources the adapters label with
+// OwnerUIDLabel
+var OwnedResourceKinds = []schema.GroupVersionKind{
+	schema.FromAPIVersionAndKind(VolumeReplicationAPIVersion, VolumeReplicationKind),
+	TridentMirrorRelationshipGVK,
+	DellCSIReplicationGroupGVK,
+}
+
+// ResourceConflictError reports a backend resource that belongs to another UVR
+type ResourceConflictError struct {
+	// Resource describes the backend resource, e.g. "VolumeReplication default/db-vr"
+	Resource string
+	// OwnerUID is the UID of the UVR that owns it
+	OwnerUID types.UID
+}
+
+func (e *ResourceConflictError) Error() string {
+	return fmt.Sprintf("%s is owned by another UnifiedVolumeReplication (uid %s)", e.Resource, e.OwnerUID)
+}
+
+// AsResourceConflictError returns the ResourceConflictError err wraps, if any
+func AsResourceConflictError(err error) (*ResourceConflictError, bool) {
+	var conflict *ResourceConflictError
+	if errors.As(err, &conflict) {
+		return conflict, true
+	}
+	return nil, false
+}
+
+// checkResourceOwner returns a ResourceConflictError when obj is labeled as owned by a UVR
+// other than uvr. Resources without the label, created before it was introduced or outside
+// the operator, are owned by whichever UVR manages them.
+func checkResourceOwner(obj metav1.Object, resource string, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	owner, ok := obj.GetLabels()[OwnerUIDLabel]
+	if !ok || owner == "" || types.UID(owner) == uvr.UID {
+		return nil
+	}
+	return &ResourceConflictError{Resource: resource, OwnerUID: types.UID(owner)}
+}
+
+// setResourceOwner labels obj as owned by uvr
+func setResourceOwner(obj metav1.Object, uvr *replicationv1alpha1.UnifiedVolumeReplication) {
+	if uvr.UID == "" {
+		return
+	}
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[OwnerUIDLabel] = string(uvr.UID)
+	obj.SetLabels(labels)
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -40,9 +41,31 @@ var DellCSIReplicationGroupGVK = schema.GroupVersionKind{
 	Kind:    "DellCSIReplicationGroup",
 }
 
+const (
+	// defaultMaxReplicationGroups caps the DellCSIReplicationGroups on a PowerStore backend,
+	// in line with the replication group sizing modeled by capability discovery
+	defaultMaxReplicationGroups = 1000
+
+	// MaxReplicationGroupsSetting is the AdapterConfig custom setting that overrides the
+	// replication group limit for arrays sized differently
+	MaxReplicationGroupsSetting = "maxReplicationGroups"
+
+	// replicationGroupsTTL is how long the listed DellCSIReplicationGroups are reused for the
+	// limit check. Groups created or deleted through the adapter are tracked in between.
+	replicationGroupsTTL = time.Minute
+)
+
 // PowerStoreAdapter implements the ReplicationAdapter interface for Dell PowerStore
 type PowerStoreAdapter struct {
 	*BaseAdapter
+
+	maxReplicationGroups int64
+
+	// groups caches the DellCSIReplicationGroups on the backend for the limit check, so
+	// reconciles do not list them cluster-wide every time
+	groupsMu       sync.Mutex
+	groups         map[types.NamespacedName]bool
+	groupsListedAt time.Time
 }
 
 // NewPowerStoreAdapter creates a new PowerStore adapter
@@ -59,7 +82,8 @@ func NewPowerStoreAdapter(client client.Client, translator *translation.Engine)
 	baseAdapter := NewBaseAdapter(translation.BackendPowerStore, client, translator, config)
 
 	adapter := &PowerStoreAdapter{
-		BaseAdapter: baseAdapter,
+		BaseAdapter:          baseAdapter,
+		maxReplicationGroups: defaultMaxReplicationGroups,
 	}
 
 	return adapter, nil
@@ -74,7 +98,7 @@ func (psa *PowerStoreAdapter) EnsureReplication(ctx context.Context, uvr *replic
 
 	// Validate configuration
 	if err := psa.ValidateConfiguration(uvr); err != nil {
-		psa.updateMetrics("ensure", false, startTime)
+		psa.updateMetrics(uvr, "ensure", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendPowerStore, "ensure", uvr.Name, "configuration validation failed", err)
 	}
 
@@ -90,15 +114,33 @@ func (psa *PowerStoreAdapter) EnsureReplication(ctx context.Context, uvr *replic
 		if errors.IsNotFound(err) {
 			// Resource doesn't exist, create it
 			logger.Info("DellCSIReplicationGroup not found, creating")
-			return psa.createPowerStoreReplicationGroup(ctx, uvr, startTime)
+
+			// Reserve capacity and provision the destination before replication is established
+			if err := psa.prepareDestination(ctx, uvr); err != nil {
+				psa.updateMetrics(uvr, "create", false, startTime)
+				return err
+			}
+			if err := psa.createPowerStoreReplicationGroup(ctx, uvr, startTime); err != nil {
+				psa.ReleaseCapacity(uvr)
+				return err
+			}
+			return nil
 		}
 		// Some other error
-		psa.updateMetrics("ensure", false, startTime)
+		psa.updateMetrics(uvr, "ensure", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendPowerStore, "ensure", uvr.Name, "failed to check existing DellCSIReplicationGroup", err)
 	}
 
-	// Resource exists, update it
+	// Resource exists, update it unless another UVR owns it
+	if err := checkResourceOwner(existing, fmt.Sprintf("DellCSIReplicationGroup %s/%s", uvr.Namespace, uvr.Name), uvr); err != nil {
+		psa.updateMetrics(uvr, "ensure", false, startTime)
+		return NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendPowerStore, "ensure", uvr.Name, "DellCSIReplicationGroup belongs to another UVR", err)
+	}
 	logger.V(1).Info("DellCSIReplicationGroup exists, updating if needed")
+	if err := psa.verifyDestinationVolume(ctx, uvr); err != nil {
+		psa.updateMetrics(uvr, "ensure", false, startTime)
+		return err
+	}
 	return psa.updatePowerStoreReplicationGroup(ctx, uvr, existing, startTime)
 }
 
@@ -109,13 +151,19 @@ func (psa *PowerStoreAdapter) createPowerStoreReplicationGroup(ctx context.Conte
 	// Translate state and mode
 	psState, err := psa.TranslateState(string(uvr.Spec.ReplicationState))
 	if err != nil {
-		psa.updateMetrics("create", false, startTime)
+		psa.updateMetrics(uvr, "create", false, startTime)
 		return err
 	}
 
 	psMode, err := psa.TranslateMode(string(uvr.Spec.ReplicationMode))
 	if err != nil {
-		psa.updateMetrics("create", false, startTime)
+		psa.updateMetrics(uvr, "create", false, startTime)
+		return err
+	}
+
+	syncSchedule, err := syncIntervalFor(uvr, translation.BackendPowerStore, "create")
+	if err != nil {
+		psa.updateMetrics(uvr, "create", false, startTime)
 		return err
 	}
 
@@ -131,6 +179,7 @@ func (psa *PowerStoreAdapter) createPowerStoreReplicationGroup(ctx context.Conte
 		"unified-replication.io/name":  uvr.Name,
 	}
 	rg.SetLabels(convertToStringMap(labels))
+	setResourceOwner(rg, uvr)
 
 	// Build spec
 	spec := map[string]interface{}{
@@ -147,25 +196,26 @@ func (psa *PowerStoreAdapter) createPowerStoreReplicationGroup(ctx context.Conte
 				"volumeHandle": uvr.Spec.VolumeMapping.Destination.VolumeHandle,
 			},
 		},
-		"syncSchedule": uvr.Spec.Schedule.Rpo,
+		"syncSchedule": syncSchedule,
 	}
 
 	// PowerStore-specific extensions removed - struct reserved for future use
 
 	if err := unstructured.SetNestedMap(rg.Object, spec, "spec"); err != nil {
-		psa.updateMetrics("create", false, startTime)
+		psa.updateMetrics(uvr, "create", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendPowerStore, "create", uvr.Name,
 			"failed to build DellCSIReplicationGroup spec", err)
 	}
 
 	// Create the resource
 	if err := psa.client.Create(ctx, rg); err != nil {
-		psa.updateMetrics("create", false, startTime)
+		psa.updateMetrics(uvr, "create", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendPowerStore, "create", uvr.Name,
 			"failed to create DellCSIReplicationGroup", err)
 	}
+	psa.trackReplicationGroup(uvr, true)
 
-	psa.updateMetrics("create", true, startTime)
+	psa.updateMetrics(uvr, "create", true, startTime)
 	logger.Info("Successfully created PowerStore replication group")
 	return nil
 }
@@ -177,57 +227,66 @@ func (psa *PowerStoreAdapter) updatePowerStoreReplicationGroup(ctx context.Conte
 	// Translate state and mode
 	psState, err := psa.TranslateState(string(uvr.Spec.ReplicationState))
 	if err != nil {
-		psa.updateMetrics("update", false, startTime)
+		psa.updateMetrics(uvr, "update", false, startTime)
 		return err
 	}
 
 	psMode, err := psa.TranslateMode(string(uvr.Spec.ReplicationMode))
 	if err != nil {
-		psa.updateMetrics("update", false, startTime)
+		psa.updateMetrics(uvr, "update", false, startTime)
 		return err
 	}
 
-	// Update spec fields
+	syncSchedule, err := syncIntervalFor(uvr, translation.BackendPowerStore, "update")
+	if err != nil {
+		psa.updateMetrics(uvr, "update", false, startTime)
+		return err
+	}
+
+	// Update spec fields, keeping the volumes of other group members
 	spec := map[string]interface{}{
 		"state":             psState,
 		"replicationPolicy": psMode,
-		"sourceVolumes": []interface{}{
-			map[string]interface{}{
-				"pvcName":      uvr.Spec.VolumeMapping.Source.PvcName,
-				"volumeHandle": "",
-			},
-		},
-		"remoteVolumes": []interface{}{
-			map[string]interface{}{
-				"volumeHandle": uvr.Spec.VolumeMapping.Destination.VolumeHandle,
-			},
-		},
-		"syncSchedule": uvr.Spec.Schedule.Rpo,
+		"sourceVolumes": preserveGroupMembers(existing, "sourceVolumes", map[string]interface{}{
+			"pvcName":      uvr.Spec.VolumeMapping.Source.PvcName,
+			"volumeHandle": "",
+		}),
+		"remoteVolumes": preserveGroupMembers(existing, "remoteVolumes", map[string]interface{}{
+			"volumeHandle": uvr.Spec.VolumeMapping.Destination.VolumeHandle,
+		}),
+		"syncSchedule": syncSchedule,
 	}
 
 	// PowerStore-specific extensions removed - struct reserved for future use
 
+	previousState, _, _ := unstructured.NestedString(existing.Object, "spec", "state")
+
 	if err := unstructured.SetNestedMap(existing.Object, spec, "spec"); err != nil {
-		psa.updateMetrics("update", false, startTime)
+		psa.updateMetrics(uvr, "update", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendPowerStore, "update", uvr.Name,
 			"failed to update DellCSIReplicationGroup spec", err)
 	}
 
+	setResourceOwner(existing, uvr)
+
 	// Update the resource
 	if err := psa.client.Update(ctx, existing); err != nil {
-		psa.updateMetrics("update", false, startTime)
+		psa.updateMetrics(uvr, "update", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendPowerStore, "update", uvr.Name,
 			"failed to update DellCSIReplicationGroup", err)
 	}
 
-	psa.updateMetrics("update", true, startTime)
+	psa.updateMetrics(uvr, "update", true, startTime)
+	if previousState != psState {
+		psa.recordStateChange(uvr, startTime)
+	}
 	logger.Info("Successfully updated PowerStore replication group")
 	return nil
 }
 
 // updateMetrics is a helper that delegates to BaseAdapter
-func (psa *PowerStoreAdapter) updateMetrics(operation string, success bool, startTime time.Time) {
-	psa.BaseAdapter.updateMetrics(operation, success, startTime)
+func (psa *PowerStoreAdapter) updateMetrics(uvr *replicationv1alpha1.UnifiedVolumeReplication, operation string, success bool, startTime time.Time) {
+	psa.BaseAdapter.updateMetrics(uvr, operation, success, startTime)
 }
 
 // DeleteReplication deletes a DellCSIReplicationGroup resource
@@ -246,17 +305,19 @@ func (psa *PowerStoreAdapter) DeleteReplication(ctx context.Context, uvr *replic
 		if errors.IsNotFound(err) {
 			// Already deleted, success
 			logger.Info("DellCSIReplicationGroup already deleted")
-			psa.updateMetrics("delete", true, startTime)
-			return nil
+			psa.trackReplicationGroup(uvr, false)
+			psa.updateMetrics(uvr, "delete", true, startTime)
+			return psa.releaseDestination(ctx, uvr)
 		}
-		psa.updateMetrics("delete", false, startTime)
+		psa.updateMetrics(uvr, "delete", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendPowerStore, "delete", uvr.Name,
 			"failed to delete DellCSIReplicationGroup", err)
 	}
 
-	psa.updateMetrics("delete", true, startTime)
+	psa.trackReplicationGroup(uvr, false)
+	psa.updateMetrics(uvr, "delete", true, startTime)
 	logger.Info("Successfully deleted PowerStore replication group")
-	return nil
+	return psa.releaseDestination(ctx, uvr)
 }
 
 // GetReplicationStatus gets the status of a DellCSIReplicationGroup
@@ -272,11 +333,11 @@ func (psa *PowerStoreAdapter) GetReplicationStatus(ctx context.Context, uvr *rep
 
 	if err := psa.client.Get(ctx, key, rg); err != nil {
 		if errors.IsNotFound(err) {
-			psa.updateMetrics("status", false, startTime)
+			psa.updateMetrics(uvr, "status", false, startTime)
 			return nil, NewAdapterError(ErrorTypeResource, translation.BackendPowerStore, "status", uvr.Name,
 				"DellCSIReplicationGroup not found")
 		}
-		psa.updateMetrics("status", false, startTime)
+		psa.updateMetrics(uvr, "status", false, startTime)
 		return nil, NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendPowerStore, "status", uvr.Name,
 			"failed to get DellCSIReplicationGroup", err)
 	}
@@ -284,7 +345,7 @@ func (psa *PowerStoreAdapter) GetReplicationStatus(ctx context.Context, uvr *rep
 	// Extract status
 	statusMap, found, err := unstructured.NestedMap(rg.Object, "status")
 	if err != nil || !found {
-		psa.updateMetrics("status", false, startTime)
+		psa.updateMetrics(uvr, "status", false, startTime)
 		return nil, NewAdapterError(ErrorTypeOperation, translation.BackendPowerStore, "status", uvr.Name,
 			"status not available yet")
 	}
@@ -310,16 +371,23 @@ func (psa *PowerStoreAdapter) GetReplicationStatus(ctx context.Context, uvr *rep
 
 	// Determine health
 	health := ReplicationHealthHealthy
+	var degradedReason DegradedReason
 	replicationStatus, _, _ := unstructured.NestedString(statusMap, "replicationLinkState")
 	switch replicationStatus {
 	case "Synchronized":
 		health = ReplicationHealthHealthy
 	case "Synchronizing":
 		health = ReplicationHealthDegraded
+		degradedReason = DegradedReasonResyncing
 	case "Failed", "Error":
 		health = ReplicationHealthUnhealthy
+		degradedReason = DegradedReasonSessionFailure
+	case "":
+		// No link state is reported until the replication group is established
+		health = ReplicationHealthUnknown
 	default:
 		health = ReplicationHealthUnknown
+		degradedReason = DegradedReasonUnknownState
 	}
 
 	// Get sync information
@@ -345,24 +413,210 @@ func (psa *PowerStoreAdapter) GetReplicationStatus(ctx context.Context, uvr *rep
 		State:              unifiedState,
 		Mode:               unifiedMode,
 		Health:             health,
+		DegradedReason:     degradedReason,
 		LastSyncTime:       lastSyncTime,
 		SyncProgress:       syncProgress,
 		ObservedGeneration: uvr.Generation,
 		BackendSpecific:    statusMap,
 	}
 
-	psa.updateMetrics("status", true, startTime)
+	// Identify the array side holding the primary; the replication group records
+	// the peer cluster ID, which is more authoritative than the UVR endpoint
+	status.PrimaryCluster, status.PrimarySite = resolvePrimaryIdentity(uvr, unifiedState)
+	status.Direction = resolveReplicationDirection(unifiedState)
+	status.ConsistencyLevel = resolveConsistencyLevel(rg.GetAnnotations(), lastSyncTime)
+	status.Members = groupVolumeMembers(rg, "sourceVolumes", "pvcName", lastSyncTime)
+	if unifiedState == string(replicationv1alpha1.ReplicationStateReplica) ||
+		unifiedState == string(replicationv1alpha1.ReplicationStateDemoting) {
+		if remoteClusterID, found, _ := unstructured.NestedString(rg.Object, "spec", "remoteClusterId"); found && remoteClusterID != "" {
+			status.PrimaryCluster = remoteClusterID
+		}
+	}
+
+	psa.updateMetrics(uvr, "status", true, startTime)
 	return status, nil
 }
 
+// GetConsistencyState reports whether the latest sync point of the DellCSIReplicationGroup was taken
+// while the application was quiesced
+func (psa *PowerStoreAdapter) GetConsistencyState(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (ConsistencyLevel, error) {
+	return consistencyLevelOf(psa.GetReplicationStatus(ctx, uvr))
+}
+
+// DetectPolicyDrift compares the protection policy and sync schedule on the
+// DellCSIReplicationGroup against the mode and RPO requested by the UVR
+func (psa *PowerStoreAdapter) DetectPolicyDrift(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) ([]PolicyDrift, error) {
+	rg := &unstructured.Unstructured{}
+	rg.SetGroupVersionKind(DellCSIReplicationGroupGVK)
+	key := client.ObjectKey{Name: uvr.Name, Namespace: uvr.Namespace}
+
+	if err := psa.client.Get(ctx, key, rg); err != nil {
+		return nil, NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendPowerStore, "policy-drift", uvr.Name,
+			"failed to get DellCSIReplicationGroup", err)
+	}
+
+	var drift []PolicyDrift
+
+	// Metro protection policies replicate synchronously, everything else is async
+	if protectionPolicy, found, _ := unstructured.NestedString(rg.Object, "spec", "protectionPolicy"); found && protectionPolicy != "" {
+		observedMode := string(replicationv1alpha1.ReplicationModeAsynchronous)
+		if protectionPolicy == "Metro" {
+			observedMode = string(replicationv1alpha1.ReplicationModeSynchronous)
+		}
+		if observedMode != string(uvr.Spec.ReplicationMode) {
+			drift = append(drift, PolicyDrift{Field: "protectionPolicy", Desired: string(uvr.Spec.ReplicationMode), Observed: protectionPolicy})
+		}
+	}
+
+	if syncSchedule, found, _ := unstructured.NestedString(rg.Object, "spec", "syncSchedule"); found && !sameSyncInterval(syncSchedule, uvr.SyncInterval()) {
+		desired, err := syncIntervalFor(uvr, translation.BackendPowerStore, "policy-drift")
+		if err != nil {
+			return nil, err
+		}
+		drift = append(drift, PolicyDrift{Field: "syncSchedule", Desired: desired, Observed: syncSchedule})
+	}
+
+	return drift, nil
+}
+
+// AddGroupMember adds a volume to the replication group. The source and remote volume lists
+// are kept in the same order, so a member sits at the same index in both.
+func (psa *PowerStoreAdapter) AddGroupMember(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, member replicationv1alpha1.VolumeMapping) error {
+	rg, err := psa.getReplicationGroup(ctx, uvr, "add-member")
+	if err != nil {
+		return err
+	}
+
+	if indexOfVolumeEntry(rg, "sourceVolumes", "pvcName", member.Source.PvcName) >= 0 {
+		return nil
+	}
+	if err := appendVolumeEntry(rg, "sourceVolumes", map[string]interface{}{
+		"pvcName":      member.Source.PvcName,
+		"volumeHandle": "",
+	}); err != nil {
+		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendPowerStore, "add-member", uvr.Name,
+			"failed to update source volumes", err)
+	}
+	if err := appendVolumeEntry(rg, "remoteVolumes", map[string]interface{}{
+		"volumeHandle": member.Destination.VolumeHandle,
+	}); err != nil {
+		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendPowerStore, "add-member", uvr.Name,
+			"failed to update remote volumes", err)
+	}
+
+	if err := psa.client.Update(ctx, rg); err != nil {
+		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendPowerStore, "add-member", uvr.Name,
+			"failed to update DellCSIReplicationGroup", err)
+	}
+
+	log.FromContext(ctx).Info("Added volume to PowerStore replication group", "uvr", uvr.Name, "pvc", member.Source.PvcName)
+	return nil
+}
+
+// RemoveGroupMember removes a volume from the replication group
+func (psa *PowerStoreAdapter) RemoveGroupMember(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, member replicationv1alpha1.VolumeMapping) error {
+	rg, err := psa.getReplicationGroup(ctx, uvr, "remove-member")
+	if err != nil {
+		return err
+	}
+
+	index := indexOfVolumeEntry(rg, "sourceVolumes", "pvcName", member.Source.PvcName)
+	if index < 0 {
+		return nil
+	}
+	if err := removeVolumeEntry(rg, "sourceVolumes", index); err != nil {
+		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendPowerStore, "remove-member", uvr.Name,
+			"failed to update source volumes", err)
+	}
+	if err := removeVolumeEntry(rg, "remoteVolumes", index); err != nil {
+		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendPowerStore, "remove-member", uvr.Name,
+			"failed to update remote volumes", err)
+	}
+
+	if err := psa.client.Update(ctx, rg); err != nil {
+		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendPowerStore, "remove-member", uvr.Name,
+			"failed to update DellCSIReplicationGroup", err)
+	}
+
+	log.FromContext(ctx).Info("Removed volume from PowerStore replication group", "uvr", uvr.Name, "pvc", member.Source.PvcName)
+	return nil
+}
+
+// MembershipChangeRequiresPause reports true, PowerStore only modifies paused replication groups
+func (psa *PowerStoreAdapter) MembershipChangeRequiresPause() bool {
+	return true
+}
+
+// GetBackendLimits reports the DellCSIReplicationGroups on the backend against the
+// replication group limit, and whether the UVR's own group is among them. The groups are
+// listed at most once per replicationGroupsTTL.
+func (psa *PowerStoreAdapter) GetBackendLimits(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (*BackendLimits, error) {
+	psa.groupsMu.Lock()
+	defer psa.groupsMu.Unlock()
+
+	if psa.groups == nil || time.Since(psa.groupsListedAt) > replicationGroupsTTL {
+		groups := &unstructured.UnstructuredList{}
+		groups.SetGroupVersionKind(DellCSIReplicationGroupGVK.GroupVersion().WithKind(DellCSIReplicationGroupGVK.Kind + "List"))
+		if err := psa.client.List(ctx, groups); err != nil {
+			return nil, NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendPowerStore, "limits", "",
+				"failed to list DellCSIReplicationGroups", err)
+		}
+		psa.groups = make(map[types.NamespacedName]bool, len(groups.Items))
+		for _, group := range groups.Items {
+			psa.groups[types.NamespacedName{Namespace: group.GetNamespace(), Name: group.GetName()}] = true
+		}
+		psa.groupsListedAt = time.Now()
+	}
+
+	return &BackendLimits{
+		MaxReplications: psa.maxReplicationGroups,
+		Replications:    int64(len(psa.groups)),
+		Counted:         psa.groups[types.NamespacedName{Namespace: uvr.Namespace, Name: uvr.Name}],
+	}, nil
+}
+
+// trackReplicationGroup records a group created or deleted through the adapter in the
+// cached groups, so the limit check sees it before the next listing
+func (psa *PowerStoreAdapter) trackReplicationGroup(uvr *replicationv1alpha1.UnifiedVolumeReplication, exists bool) {
+	psa.groupsMu.Lock()
+	defer psa.groupsMu.Unlock()
+	if psa.groups == nil {
+		return
+	}
+	key := types.NamespacedName{Namespace: uvr.Namespace, Name: uvr.Name}
+	if exists {
+		psa.groups[key] = true
+	} else {
+		delete(psa.groups, key)
+	}
+}
+
+// getReplicationGroup fetches the DellCSIReplicationGroup backing a UVR
+func (psa *PowerStoreAdapter) getReplicationGroup(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, operation string) (*unstructured.Unstructured, error) {
+	rg := &unstructured.Unstructured{}
+	rg.SetGroupVersionKind(DellCSIReplicationGroupGVK)
+	if err := psa.client.Get(ctx, types.NamespacedName{Name: uvr.Name, Namespace: uvr.Namespace}, rg); err != nil {
+		return nil, NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendPowerStore, operation, uvr.Name,
+			"failed to get DellCSIReplicationGroup", err)
+	}
+	return rg, nil
+}
+
 // PromoteReplica promotes a replica to source (failover)
 func (psa *PowerStoreAdapter) PromoteReplica(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
 	logger := log.FromContext(ctx).WithName("powerstore-adapter").WithValues("uvr", uvr.Name)
 	logger.Info("Promoting PowerStore replica (failover)")
 
+	startTime := time.Now()
+
 	// Update state to active/source
 	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
-	return psa.EnsureReplication(ctx, uvr)
+	if err := psa.EnsureReplication(ctx, uvr); err != nil {
+		psa.updateMetrics(uvr, "promote", false, startTime)
+		return err
+	}
+	psa.updateMetrics(uvr, "promote", true, startTime)
+	return nil
 }
 
 // DemoteSource demotes a source to replica (failback)
@@ -370,9 +624,16 @@ func (psa *PowerStoreAdapter) DemoteSource(ctx context.Context, uvr *replication
 	logger := log.FromContext(ctx).WithName("powerstore-adapter").WithValues("uvr", uvr.Name)
 	logger.Info("Demoting PowerStore source (failback)")
 
+	startTime := time.Now()
+
 	// Update state to passive/replica
 	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateReplica
-	return psa.EnsureReplication(ctx, uvr)
+	if err := psa.EnsureReplication(ctx, uvr); err != nil {
+		psa.updateMetrics(uvr, "demote", false, startTime)
+		return err
+	}
+	psa.updateMetrics(uvr, "demote", true, startTime)
+	return nil
 }
 
 // ResyncReplication triggers a resync operation
@@ -380,6 +641,8 @@ func (psa *PowerStoreAdapter) ResyncReplication(ctx context.Context, uvr *replic
 	logger := log.FromContext(ctx).WithName("powerstore-adapter").WithValues("uvr", uvr.Name)
 	logger.Info("Resyncing PowerStore replication group")
 
+	startTime := time.Now()
+
 	// For PowerStore, resync is done by updating to syncing state then back to replica
 	// Get current resource
 	rg := &unstructured.Unstructured{}
@@ -387,6 +650,7 @@ func (psa *PowerStoreAdapter) ResyncReplication(ctx context.Context, uvr *replic
 	key := client.ObjectKey{Name: uvr.Name, Namespace: uvr.Namespace}
 
 	if err := psa.client.Get(ctx, key, rg); err != nil {
+		psa.updateMetrics(uvr, "resync", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendPowerStore, "resync", uvr.Name,
 			"failed to get DellCSIReplicationGroup", err)
 	}
@@ -400,10 +664,12 @@ func (psa *PowerStoreAdapter) ResyncReplication(ctx context.Context, uvr *replic
 	rg.SetAnnotations(annotations)
 
 	if err := psa.client.Update(ctx, rg); err != nil {
+		psa.updateMetrics(uvr, "resync", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendPowerStore, "resync", uvr.Name,
 			"failed to trigger resync", err)
 	}
 
+	psa.updateMetrics(uvr, "resync", true, startTime)
 	logger.Info("Successfully triggered resync operation")
 	return nil
 }
@@ -460,6 +726,65 @@ func (psa *PowerStoreAdapter) ResumeReplication(ctx context.Context, uvr *replic
 	return psa.client.Update(ctx, rg)
 }
 
+// DeactivateReplication suspends the DellCSIReplicationGroup, which stops replication on
+// the array while keeping the replication session
+func (psa *PowerStoreAdapter) DeactivateReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	return psa.setDeactivated(ctx, uvr, true)
+}
+
+// ReactivateReplication resumes a suspended DellCSIReplicationGroup
+func (psa *PowerStoreAdapter) ReactivateReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	return psa.setDeactivated(ctx, uvr, false)
+}
+
+// setDeactivated suspends or resumes the replication group and records it in
+// DeactivatedAnnotation
+func (psa *PowerStoreAdapter) setDeactivated(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, deactivated bool) error {
+	logger := log.FromContext(ctx).WithName("powerstore-adapter").WithValues("uvr", uvr.Name)
+	operation, action := "reactivate", "Resume"
+	if deactivated {
+		operation, action = "deactivate", "Suspend"
+	}
+	startTime := time.Now()
+
+	rg, err := psa.getReplicationGroup(ctx, uvr, operation)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		psa.updateMetrics(uvr, operation, false, startTime)
+		return err
+	}
+	annotations := rg.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if _, ok := annotations[DeactivatedAnnotation]; ok == deactivated {
+		return nil
+	}
+	if deactivated {
+		annotations[DeactivatedAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	} else {
+		delete(annotations, DeactivatedAnnotation)
+	}
+	rg.SetAnnotations(annotations)
+	if err := unstructured.SetNestedField(rg.Object, action, "spec", "action"); err != nil {
+		psa.updateMetrics(uvr, operation, false, startTime)
+		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendPowerStore, operation, uvr.Name,
+			"failed to set DellCSIReplicationGroup action", err)
+	}
+
+	if err := psa.client.Update(ctx, rg); err != nil {
+		psa.updateMetrics(uvr, operation, false, startTime)
+		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendPowerStore, operation, uvr.Name,
+			fmt.Sprintf("failed to %s DellCSIReplicationGroup", operation), err)
+	}
+
+	psa.updateMetrics(uvr, operation, true, startTime)
+	logger.Info("Updated PowerStore replication group", "action", action)
+	return nil
+}
+
 // PowerStoreAdapterFactory creates real PowerStore adapter instances
 type PowerStoreAdapterFactory struct {
 	info AdapterFactoryInfo
@@ -490,7 +815,40 @@ func (f *PowerStoreAdapterFactory) CreateAdapter(backend translation.Backend, cl
 		return nil, fmt.Errorf("translator is required for PowerStore adapter")
 	}
 
-	return NewPowerStoreAdapter(client, translator)
+	adapter, err := NewPowerStoreAdapter(client, translator)
+	if err != nil {
+		return nil, err
+	}
+	if config != nil {
+		if limit, ok := config.CustomSettings[MaxReplicationGroupsSetting]; ok {
+			max, err := replicationGroupLimit(limit)
+			if err != nil {
+				return nil, err
+			}
+			adapter.maxReplicationGroups = max
+		}
+	}
+	return adapter, nil
+}
+
+// replicationGroupLimit converts the configured replication group limit, which may have been
+// decoded from JSON or YAML, to a positive count
+func replicationGroupLimit(value interface{}) (int64, error) {
+	var limit int64
+	switch v := value.(type) {
+	case int:
+		limit = int64(v)
+	case int64:
+		limit = v
+	case float64:
+		limit = int64(v)
+	default:
+		return 0, fmt.Errorf("%s must be a number, got %T", MaxReplicationGroupsSetting, value)
+	}
+	if limit <= 0 {
+		return 0, fmt.Errorf("%s must be positive, got %d", MaxReplicationGroupsSetting, limit)
+	}
+	return limit, nil
 }
 
 // GetBackendType returns the backend type this factory supports
@@ -521,6 +879,12 @@ func (f *PowerStoreAdapterFactory) ValidateConfig(config *AdapterConfig) error {
 		return fmt.Errorf("retry attempts cannot be negative")
 	}
 
+	if limit, ok := config.CustomSettings[MaxReplicationGroupsSetting]; ok {
+		if _, err := replicationGroupLimit(limit); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
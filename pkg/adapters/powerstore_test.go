@@ -19,11 +19,14 @@ package adapters
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
@@ -174,6 +177,117 @@ func TestPowerStoreAdapter_MetroReplication(t *testing.T) {
 	_ = err
 }
 
+func TestPowerStoreAdapter_DetectPolicyDrift(t *testing.T) {
+	client := fake.NewClientBuilder().Build()
+	adapter, err := NewPowerStoreAdapter(client, translation.NewEngine())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	uvr := createTestUVRForPowerStore("test-drift", "default")
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+
+	drift, err := adapter.DetectPolicyDrift(ctx, uvr)
+	require.NoError(t, err)
+	assert.Empty(t, drift)
+
+	// Storage admin switches the group to a Metro protection policy
+	rg := &unstructured.Unstructured{}
+	rg.SetGroupVersionKind(DellCSIReplicationGroupGVK)
+	require.NoError(t, client.Get(ctx, types.NamespacedName{Name: "test-drift", Namespace: "default"}, rg))
+	require.NoError(t, unstructured.SetNestedField(rg.Object, "Metro", "spec", "protectionPolicy"))
+	require.NoError(t, client.Update(ctx, rg))
+
+	drift, err = adapter.DetectPolicyDrift(ctx, uvr)
+	require.NoError(t, err)
+	require.Len(t, drift, 1)
+	assert.Equal(t, "protectionPolicy", drift[0].Field)
+	assert.Equal(t, "Metro", drift[0].Observed)
+}
+
+func TestPowerStoreAdapter_GetBackendLimits(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientBuilder().Build()
+	factory := NewPowerStoreAdapterFactory()
+
+	config := DefaultAdapterConfig(translation.BackendPowerStore)
+	config.CustomSettings[MaxReplicationGroupsSetting] = float64(2) // as decoded from JSON
+	require.NoError(t, factory.ValidateConfig(config))
+	created, err := factory.CreateAdapter(translation.BackendPowerStore, client, translation.NewEngine(), config)
+	require.NoError(t, err)
+	adapter := created.(*PowerStoreAdapter)
+
+	first := createTestUVRForPowerStore("test-limits-1", "default")
+	limits, err := adapter.GetBackendLimits(ctx, first)
+	require.NoError(t, err)
+	assert.Equal(t, &BackendLimits{MaxReplications: 2, Replications: 0}, limits)
+
+	// Groups created through the adapter are counted without listing them again
+	require.NoError(t, adapter.EnsureReplication(ctx, first))
+	require.NoError(t, adapter.EnsureReplication(ctx, createTestUVRForPowerStore("test-limits-2", "default")))
+	limits, err = adapter.GetBackendLimits(ctx, first)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), limits.Replications)
+	assert.True(t, limits.Exhausted())
+	assert.True(t, limits.Counted, "the UVR's own group is part of the usage")
+
+	limits, err = adapter.GetBackendLimits(ctx, createTestUVRForPowerStore("test-limits-3", "default"))
+	require.NoError(t, err)
+	assert.False(t, limits.Counted)
+
+	require.NoError(t, adapter.DeleteReplication(ctx, first))
+	limits, err = adapter.GetBackendLimits(ctx, first)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), limits.Replications)
+	assert.False(t, limits.Counted)
+
+	// Without an override the default limit applies
+	defaultAdapter, err := NewPowerStoreAdapter(client, translation.NewEngine())
+	require.NoError(t, err)
+	limits, err = defaultAdapter.GetBackendLimits(ctx, first)
+	require.NoError(t, err)
+	assert.Equal(t, int64(defaultMaxReplicationGroups), limits.MaxReplications)
+
+	config.CustomSettings[MaxReplicationGroupsSetting] = "many"
+	assert.Error(t, factory.ValidateConfig(config))
+	config.CustomSettings[MaxReplicationGroupsSetting] = 0
+	assert.Error(t, factory.ValidateConfig(config))
+}
+
+func TestPowerStoreAdapter_DegradedReason(t *testing.T) {
+	client := fake.NewClientBuilder().Build()
+	adapter, err := NewPowerStoreAdapter(client, translation.NewEngine())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	uvr := createTestUVRForPowerStore("test-degraded", "default")
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+
+	tests := []struct {
+		linkState string
+		health    ReplicationHealth
+		reason    DegradedReason
+	}{
+		{linkState: "Synchronized", health: ReplicationHealthHealthy},
+		{linkState: "Synchronizing", health: ReplicationHealthDegraded, reason: DegradedReasonResyncing},
+		{linkState: "Failed", health: ReplicationHealthUnhealthy, reason: DegradedReasonSessionFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.linkState, func(t *testing.T) {
+			rg := &unstructured.Unstructured{}
+			rg.SetGroupVersionKind(DellCSIReplicationGroupGVK)
+			require.NoError(t, client.Get(ctx, types.NamespacedName{Name: "test-degraded", Namespace: "default"}, rg))
+			require.NoError(t, unstructured.SetNestedField(rg.Object, tt.linkState, "status", "replicationLinkState"))
+			require.NoError(t, client.Update(ctx, rg))
+
+			status, err := adapter.GetReplicationStatus(ctx, uvr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.health, status.Health)
+			assert.Equal(t, tt.reason, status.DegradedReason)
+		})
+	}
+}
+
 // Helper function
 func createTestUVRForPowerStore(name, namespace string) *replicationv1alpha1.UnifiedVolumeReplication {
 	return &replicationv1alpha1.UnifiedVolumeReplication{
@@ -215,3 +329,107 @@ func createTestUVRForPowerStore(name, namespace string) *replicationv1alpha1.Uni
 		},
 	}
 }
+
+func TestPowerStoreAdapter_GroupMembership(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientBuilder().Build()
+	adapter, err := NewPowerStoreAdapter(client, translation.NewEngine())
+	require.NoError(t, err)
+
+	uvr := createTestUVRForPowerStore("test-group", "default")
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+
+	volumes := func(field, key string) []string {
+		rg, err := adapter.getReplicationGroup(ctx, uvr, "test")
+		require.NoError(t, err)
+		entries, _, _ := unstructured.NestedSlice(rg.Object, "spec", field)
+		var values []string
+		for _, e := range entries {
+			values = append(values, e.(map[string]interface{})[key].(string))
+		}
+		return values
+	}
+
+	member := func(name string) replicationv1alpha1.VolumeMapping {
+		return replicationv1alpha1.VolumeMapping{
+			Source:      replicationv1alpha1.VolumeSource{PvcName: name, Namespace: "default"},
+			Destination: replicationv1alpha1.VolumeDestination{VolumeHandle: name + "-dest", Namespace: "default"},
+		}
+	}
+
+	assert.True(t, adapter.MembershipChangeRequiresPause())
+	require.NoError(t, adapter.AddGroupMember(ctx, uvr, member("data-1")))
+	require.NoError(t, adapter.AddGroupMember(ctx, uvr, member("data-2")))
+	assert.Equal(t, []string{"source-pvc", "data-1", "data-2"}, volumes("sourceVolumes", "pvcName"))
+	assert.Equal(t, []string{"dest-volume", "data-1-dest", "data-2-dest"}, volumes("remoteVolumes", "volumeHandle"))
+
+	// Removing a member keeps source and remote volumes aligned
+	require.NoError(t, adapter.RemoveGroupMember(ctx, uvr, member("data-1")))
+	assert.Equal(t, []string{"source-pvc", "data-2"}, volumes("sourceVolumes", "pvcName"))
+	assert.Equal(t, []string{"dest-volume", "data-2-dest"}, volumes("remoteVolumes", "volumeHandle"))
+
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+	assert.Equal(t, []string{"source-pvc", "data-2"}, volumes("sourceVolumes", "pvcName"))
+}
+
+func TestPowerStoreAdapter_MemberSyncStatus(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientBuilder().Build()
+	adapter, err := NewPowerStoreAdapter(client, translation.NewEngine())
+	require.NoError(t, err)
+
+	uvr := createTestUVRForPowerStore("test-members", "default")
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+	require.NoError(t, adapter.AddGroupMember(ctx, uvr, replicationv1alpha1.VolumeMapping{
+		Source:      replicationv1alpha1.VolumeSource{PvcName: "data-pvc", Namespace: "default"},
+		Destination: replicationv1alpha1.VolumeDestination{VolumeHandle: "data-dest", Namespace: "default"},
+	}))
+
+	rg, err := adapter.getReplicationGroup(ctx, uvr, "test")
+	require.NoError(t, err)
+	require.NoError(t, unstructured.SetNestedField(rg.Object, map[string]interface{}{
+		"state":                "source",
+		"replicationLinkState": "Synchronized",
+		"lastSyncTime":         "2024-05-01T10:00:00Z",
+	}, "status"))
+	require.NoError(t, client.Update(ctx, rg))
+
+	// A replication group syncs all of its volumes at once
+	status, err := adapter.GetReplicationStatus(ctx, uvr)
+	require.NoError(t, err)
+	require.Len(t, status.Members, 2)
+	syncTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	for i, pvcName := range []string{"source-pvc", "data-pvc"} {
+		assert.Equal(t, pvcName, status.Members[i].PvcName)
+		require.NotNil(t, status.Members[i].LastSyncTime)
+		assert.Equal(t, syncTime, status.Members[i].LastSyncTime.UTC())
+	}
+}
+
+func TestPowerStoreAdapter_Deactivation(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientBuilder().Build()
+	adapter, err := NewPowerStoreAdapter(client, translation.NewEngine())
+	require.NoError(t, err)
+
+	uvr := createTestUVRForPowerStore("test-deactivate", "default")
+	require.NoError(t, adapter.DeactivateReplication(ctx, uvr), "nothing to deactivate before the group exists")
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+
+	group := func() (string, map[string]string) {
+		rg, err := adapter.getReplicationGroup(ctx, uvr, "test")
+		require.NoError(t, err)
+		action, _, _ := unstructured.NestedString(rg.Object, "spec", "action")
+		return action, rg.GetAnnotations()
+	}
+
+	require.NoError(t, adapter.DeactivateReplication(ctx, uvr))
+	action, annotations := group()
+	assert.Equal(t, "Suspend", action)
+	assert.Contains(t, annotations, DeactivatedAnnotation)
+
+	require.NoError(t, adapter.ReactivateReplication(ctx, uvr))
+	action, annotations = group()
+	assert.Equal(t, "Resume", action)
+	assert.NotContains(t, annotations, DeactivatedAnnotation)
+}
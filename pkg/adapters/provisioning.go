@@ -0,0 +1,237 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+const (
+	// DestinationVolumeCreatedByAnnotation records which UVR auto-created a destination volume
+	DestinationVolumeCreatedByAnnotation = "replication.storage.io/created-by"
+)
+
+// destinationAutoCreateEnabled returns true when the UVR asks for the destination volume to be provisioned
+func destinationAutoCreateEnabled(uvr *replicationv1alpha1.UnifiedVolumeReplication) bool {
+	autoCreate := uvr.Spec.VolumeMapping.Destination.AutoCreate
+	return autoCreate != nil && *autoCreate
+}
+
+// destinationVolumeOwner identifies the UVR that owns an auto-created destination volume
+func destinationVolumeOwner(uvr *replicationv1alpha1.UnifiedVolumeReplication) string {
+	return fmt.Sprintf("%s/%s", uvr.Namespace, uvr.Name)
+}
+
+// getSourceVolume returns the source PVC together with its size, preferring the provisioned
+// capacity over the requested size
+func (ba *BaseAdapter) getSourceVolume(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, operation string) (*corev1.PersistentVolumeClaim, resource.Quantity, error) {
+	source := uvr.Spec.VolumeMapping.Source
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := ba.client.Get(ctx, types.NamespacedName{Name: source.PvcName, Namespace: source.Namespace}, pvc); err != nil {
+		return nil, resource.Quantity{}, NewAdapterErrorWithCause(ErrorTypeResource, ba.backend, operation, uvr.Name,
+			fmt.Sprintf("failed to get source PVC %s/%s", source.Namespace, source.PvcName), err)
+	}
+
+	size, ok := pvc.Status.Capacity[corev1.ResourceStorage]
+	if !ok {
+		size, ok = pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	}
+	if !ok {
+		return nil, resource.Quantity{}, NewAdapterError(ErrorTypeValidation, ba.backend, operation, uvr.Name,
+			"source PVC does not declare a storage size")
+	}
+
+	return pvc, size, nil
+}
+
+// prepareDestination reserves destination capacity and provisions the destination volume
+// ahead of establishing a new replication. The reservation is dropped again if provisioning
+// fails; callers release it themselves if establishing the replication fails afterwards.
+func (ba *BaseAdapter) prepareDestination(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	if err := ba.reserveDestinationCapacity(ctx, uvr); err != nil {
+		return err
+	}
+	if err := ba.ensureDestinationVolume(ctx, uvr); err != nil {
+		ba.ReleaseCapacity(uvr)
+		return err
+	}
+	return nil
+}
+
+// releaseDestination undoes prepareDestination once the replication has been deleted
+func (ba *BaseAdapter) releaseDestination(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	ba.ReleaseCapacity(uvr)
+	return ba.cleanupDestinationVolume(ctx, uvr)
+}
+
+// ensureDestinationVolume provisions the destination PVC, sized from the source PVC, when
+// AutoCreate is enabled and the volume does not exist yet. An existing destination volume
+// is checked for size and storage class compatibility instead.
+func (ba *BaseAdapter) ensureDestinationVolume(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	if !destinationAutoCreateEnabled(uvr) {
+		return nil
+	}
+
+	logger := log.FromContext(ctx).WithValues("uvr", uvr.Name, "backend", ba.backend)
+	mapping := uvr.Spec.VolumeMapping
+
+	source, size, err := ba.getSourceVolume(ctx, uvr, "provision")
+	if err != nil {
+		return err
+	}
+
+	storageClass := uvr.Spec.DestinationEndpoint.StorageClass
+	if err := ba.client.Get(ctx, types.NamespacedName{Name: storageClass}, &storagev1.StorageClass{}); err != nil {
+		return NewAdapterErrorWithCause(ErrorTypeValidation, ba.backend, "provision", uvr.Name,
+			fmt.Sprintf("destination storage class %s is not available", storageClass), err)
+	}
+
+	existing := &corev1.PersistentVolumeClaim{}
+	err = ba.client.Get(ctx, types.NamespacedName{Name: mapping.Destination.VolumeHandle, Namespace: mapping.Destination.Namespace}, existing)
+	if err == nil {
+		return ba.validateDestinationVolume(uvr, existing, storageClass, size)
+	}
+	if !errors.IsNotFound(err) {
+		return NewAdapterErrorWithCause(ErrorTypeConnection, ba.backend, "provision", uvr.Name,
+			"failed to check destination volume", err)
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mapping.Destination.VolumeHandle,
+			Namespace: mapping.Destination.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "unified-replication-operator",
+			},
+			Annotations: map[string]string{
+				DestinationVolumeCreatedByAnnotation: destinationVolumeOwner(uvr),
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      source.Spec.AccessModes,
+			VolumeMode:       source.Spec.VolumeMode,
+			StorageClassName: &storageClass,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: size},
+			},
+		},
+	}
+
+	if err := ba.client.Create(ctx, pvc); err != nil {
+		return NewAdapterErrorWithCause(ErrorTypeOperation, ba.backend, "provision", uvr.Name,
+			"failed to create destination volume", err)
+	}
+
+	logger.Info("Provisioned destination volume", "pvc", pvc.Name, "namespace", pvc.Namespace, "size", size.String())
+	return nil
+}
+
+// verifyDestinationVolume re-validates an existing destination volume on ensures of an
+// established replication, so a destination that was replaced or shrunk after creation is
+// reported rather than silently replicated into. A missing volume is left to the backend.
+func (ba *BaseAdapter) verifyDestinationVolume(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	if !destinationAutoCreateEnabled(uvr) {
+		return nil
+	}
+
+	mapping := uvr.Spec.VolumeMapping
+	existing := &corev1.PersistentVolumeClaim{}
+	if err := ba.client.Get(ctx, types.NamespacedName{Name: mapping.Destination.VolumeHandle, Namespace: mapping.Destination.Namespace}, existing); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return NewAdapterErrorWithCause(ErrorTypeConnection, ba.backend, "provision", uvr.Name,
+			"failed to check destination volume", err)
+	}
+
+	_, size, err := ba.getSourceVolume(ctx, uvr, "provision")
+	if err != nil {
+		return err
+	}
+	return ba.validateDestinationVolume(uvr, existing, uvr.Spec.DestinationEndpoint.StorageClass, size)
+}
+
+// DestinationVolumeBound returns true when the destination PVC exists and is bound, at which
+// point it holds its capacity itself and a reservation for it can be released
+func (ba *BaseAdapter) DestinationVolumeBound(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (bool, error) {
+	mapping := uvr.Spec.VolumeMapping
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := ba.client.Get(ctx, types.NamespacedName{Name: mapping.Destination.VolumeHandle, Namespace: mapping.Destination.Namespace}, pvc); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, NewAdapterErrorWithCause(ErrorTypeConnection, ba.backend, "reserve", uvr.Name,
+			"failed to check destination volume", err)
+	}
+	return pvc.Status.Phase == corev1.ClaimBound, nil
+}
+
+// validateDestinationVolume checks that a pre-existing destination volume can hold the source data
+func (ba *BaseAdapter) validateDestinationVolume(uvr *replicationv1alpha1.UnifiedVolumeReplication, pvc *corev1.PersistentVolumeClaim, storageClass string, size resource.Quantity) error {
+	if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName != storageClass {
+		return NewAdapterError(ErrorTypeValidation, ba.backend, "provision", uvr.Name,
+			fmt.Sprintf("destination volume uses storage class %s, expected %s", *pvc.Spec.StorageClassName, storageClass))
+	}
+
+	if requested, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok && requested.Cmp(size) < 0 {
+		return NewAdapterError(ErrorTypeValidation, ba.backend, "provision", uvr.Name,
+			fmt.Sprintf("destination volume size %s is smaller than source size %s", requested.String(), size.String()))
+	}
+
+	return nil
+}
+
+// cleanupDestinationVolume removes a destination volume previously created for this UVR.
+// Volumes that were pre-created by the user are left untouched.
+func (ba *BaseAdapter) cleanupDestinationVolume(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	if !destinationAutoCreateEnabled(uvr) {
+		return nil
+	}
+
+	mapping := uvr.Spec.VolumeMapping
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := ba.client.Get(ctx, types.NamespacedName{Name: mapping.Destination.VolumeHandle, Namespace: mapping.Destination.Namespace}, pvc); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return NewAdapterErrorWithCause(ErrorTypeConnection, ba.backend, "cleanup", uvr.Name,
+			"failed to get destination volume", err)
+	}
+
+	if pvc.Annotations[DestinationVolumeCreatedByAnnotation] != destinationVolumeOwner(uvr) {
+		return nil
+	}
+
+	if err := ba.client.Delete(ctx, pvc); err != nil && !errors.IsNotFound(err) {
+		return NewAdapterErrorWithCause(ErrorTypeOperation, ba.backend, "cleanup", uvr.Name,
+			"failed to delete destination volume", err)
+	}
+
+	log.FromContext(ctx).Info("Removed auto-created destination volume", "pvc", pvc.Name, "namespace", pvc.Namespace)
+	return nil
+}
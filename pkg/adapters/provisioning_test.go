@@ -0,0 +1,184 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+func newProvisioningTestClient(t *testing.T) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, replicationv1alpha1.AddToScheme(scheme))
+
+	sourcePVC := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "source-pvc", Namespace: "default"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+			},
+		},
+	}
+	storageClass := &storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: "powerstore-block"},
+		Provisioner: "csi-powerstore.dellemc.com",
+	}
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(sourcePVC, storageClass).Build()
+}
+
+func TestPowerStoreAdapter_DestinationAutoCreate(t *testing.T) {
+	ctx := context.Background()
+	destKey := types.NamespacedName{Name: "dest-volume", Namespace: "default"}
+
+	t.Run("AutoCreate", func(t *testing.T) {
+		c := newProvisioningTestClient(t)
+		adapter, err := NewPowerStoreAdapter(c, translation.NewEngine())
+		require.NoError(t, err)
+
+		uvr := createTestUVRForPowerStore("auto-create", "default")
+		autoCreate := true
+		uvr.Spec.VolumeMapping.Destination.AutoCreate = &autoCreate
+
+		require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+
+		dest := &corev1.PersistentVolumeClaim{}
+		require.NoError(t, c.Get(ctx, destKey, dest))
+		assert.Equal(t, "powerstore-block", *dest.Spec.StorageClassName)
+		size := dest.Spec.Resources.Requests[corev1.ResourceStorage]
+		assert.Equal(t, "10Gi", size.String())
+		assert.Equal(t, "default/auto-create", dest.Annotations[DestinationVolumeCreatedByAnnotation])
+
+		// Ensuring again accepts the volume it created
+		require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+
+		require.NoError(t, adapter.DeleteReplication(ctx, uvr))
+		err = c.Get(ctx, destKey, &corev1.PersistentVolumeClaim{})
+		assert.True(t, errors.IsNotFound(err), "auto-created destination volume should be removed")
+	})
+
+	t.Run("ExpectsPreExistingByDefault", func(t *testing.T) {
+		c := newProvisioningTestClient(t)
+		adapter, err := NewPowerStoreAdapter(c, translation.NewEngine())
+		require.NoError(t, err)
+
+		uvr := createTestUVRForPowerStore("pre-existing", "default")
+
+		require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+
+		err = c.Get(ctx, destKey, &corev1.PersistentVolumeClaim{})
+		assert.True(t, errors.IsNotFound(err), "destination volume should not be provisioned")
+	})
+
+	t.Run("RejectsUndersizedDestination", func(t *testing.T) {
+		c := newProvisioningTestClient(t)
+		storageClass := "powerstore-block"
+		require.NoError(t, c.Create(ctx, &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "dest-volume", Namespace: "default"},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: &storageClass,
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("5Gi")},
+				},
+			},
+		}))
+
+		adapter, err := NewPowerStoreAdapter(c, translation.NewEngine())
+		require.NoError(t, err)
+
+		uvr := createTestUVRForPowerStore("undersized", "default")
+		autoCreate := true
+		uvr.Spec.VolumeMapping.Destination.AutoCreate = &autoCreate
+
+		err = adapter.EnsureReplication(ctx, uvr)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "smaller than source size")
+
+		// A volume the adapter did not create survives deletion
+		require.NoError(t, adapter.DeleteReplication(ctx, uvr))
+		require.NoError(t, c.Get(ctx, destKey, &corev1.PersistentVolumeClaim{}))
+	})
+
+	t.Run("RevalidatesExistingDestination", func(t *testing.T) {
+		c := newProvisioningTestClient(t)
+		adapter, err := NewPowerStoreAdapter(c, translation.NewEngine())
+		require.NoError(t, err)
+
+		uvr := createTestUVRForPowerStore("revalidate", "default")
+		autoCreate := true
+		uvr.Spec.VolumeMapping.Destination.AutoCreate = &autoCreate
+		require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+
+		// The destination is swapped for one on another storage class after creation
+		dest := &corev1.PersistentVolumeClaim{}
+		require.NoError(t, c.Get(ctx, destKey, dest))
+		other := "other-class"
+		dest.Spec.StorageClassName = &other
+		require.NoError(t, c.Update(ctx, dest))
+
+		err = adapter.EnsureReplication(ctx, uvr)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "uses storage class other-class")
+	})
+}
+
+func TestTridentAdapter_DeleteBackendResourceKeepsDestination(t *testing.T) {
+	ctx := context.Background()
+	c := newProvisioningTestClient(t)
+	require.NoError(t, c.Create(ctx, &storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: "trident-nas"},
+		Provisioner: "csi.trident.netapp.io",
+	}))
+	adapter, err := NewTridentAdapter(c, translation.NewEngine())
+	require.NoError(t, err)
+
+	uvr := createTestUVRForTrident("recreate", "default")
+	autoCreate := true
+	uvr.Spec.VolumeMapping.Destination.AutoCreate = &autoCreate
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+	destKey := types.NamespacedName{Name: uvr.Spec.VolumeMapping.Destination.VolumeHandle, Namespace: "default"}
+	require.NoError(t, c.Get(ctx, destKey, &corev1.PersistentVolumeClaim{}))
+
+	// Recreation removes only the mirror relationship
+	require.NoError(t, adapter.DeleteBackendResource(ctx, uvr))
+	exists, err := adapter.BackendResourceExists(ctx, uvr)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	require.NoError(t, c.Get(ctx, destKey, &corev1.PersistentVolumeClaim{}), "the destination volume is kept")
+
+	require.NoError(t, adapter.DeleteReplication(ctx, uvr))
+	err = c.Get(ctx, destKey, &corev1.PersistentVolumeClaim{})
+	assert.True(t, errors.IsNotFound(err), "deleting the replication removes the auto-created destination")
+}
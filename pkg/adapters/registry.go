@@ -18,6 +18,8 @@ package adapters
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"sync"
 	"time"
@@ -37,6 +39,18 @@ type AdapterFactory interface {
 	ValidateConfig(config *AdapterConfig) error
 }
 
+// MockFactory is implemented by factories creating simulated adapters, which keep their state
+// in memory instead of driving a storage backend
+type MockFactory interface {
+	IsMock() bool
+}
+
+// IsMockFactory reports whether factory creates simulated adapters
+func IsMockFactory(factory AdapterFactory) bool {
+	mock, ok := factory.(MockFactory)
+	return ok && mock.IsMock()
+}
+
 // AdapterFactoryInfo provides information about an adapter factory
 type AdapterFactoryInfo struct {
 	Name        string              `json:"name"`
@@ -285,10 +299,23 @@ func (f *BaseAdapterFactory) CreateAdapter(backend translation.Backend, client c
 
 // AdapterManager provides high-level adapter management functionality
 type AdapterManager struct {
-	registry Registry
-	adapters map[string]ReplicationAdapter // keyed by instance identifier
-	mu       sync.RWMutex
-	config   *ManagerConfig
+	registry       Registry
+	adapters       map[string]*managedAdapter // keyed by instance identifier
+	backendConfigs map[translation.Backend]*AdapterConfig
+	onSwap         AdapterSwapHandler
+	mu             sync.RWMutex
+	config         *ManagerConfig
+}
+
+// AdapterSwapHandler is notified when a cached adapter is replaced after a configuration change
+type AdapterSwapHandler func(uvr *replicationv1alpha1.UnifiedVolumeReplication, backend translation.Backend)
+
+// managedAdapter is a cached adapter together with the configuration it was created from
+type managedAdapter struct {
+	adapter     ReplicationAdapter
+	fingerprint string
+	inflight    int  // operations currently using the adapter
+	retired     bool // replaced or removed; cleaned up once inflight drops to zero
 }
 
 // ManagerConfig contains configuration for the adapter manager
@@ -316,64 +343,163 @@ func NewAdapterManager(registry Registry, config *ManagerConfig) *AdapterManager
 	}
 
 	return &AdapterManager{
-		registry: registry,
-		adapters: make(map[string]ReplicationAdapter),
-		config:   config,
+		registry:       registry,
+		adapters:       make(map[string]*managedAdapter),
+		backendConfigs: make(map[translation.Backend]*AdapterConfig),
+		config:         config,
 	}
 }
 
-// GetOrCreateAdapter gets an existing adapter or creates a new one
-func (m *AdapterManager) GetOrCreateAdapter(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, client client.Client, translator *translation.Engine) (ReplicationAdapter, error) {
-	instanceKey := m.getInstanceKey(uvr)
-
-	m.mu.RLock()
-	if adapter, exists := m.adapters[instanceKey]; exists {
-		m.mu.RUnlock()
-		return adapter, nil
+// SetBackendConfig sets the operator-level configuration (credentials, tuning) for a backend.
+// Cached adapters created from a different configuration are swapped on next use.
+func (m *AdapterManager) SetBackendConfig(backend translation.Backend, config *AdapterConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if config == nil {
+		delete(m.backendConfigs, backend)
+		return
 	}
-	m.mu.RUnlock()
+	m.backendConfigs[backend] = config
+}
+
+// SetSwapHandler registers a handler called whenever a cached adapter is swapped
+func (m *AdapterManager) SetSwapHandler(handler AdapterSwapHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onSwap = handler
+}
 
+// GetOrCreateAdapter gets an existing adapter or creates a new one
+func (m *AdapterManager) GetOrCreateAdapter(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, client client.Client, translator *translation.Engine) (ReplicationAdapter, error) {
 	// Determine backend type from UVR
 	backend, err := m.determineBackend(uvr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to determine backend for %s: %w", uvr.Name, err)
 	}
 
-	// Create adapter configuration
+	adapter, release, err := m.AcquireAdapter(ctx, uvr, backend, client, translator)
+	if err != nil {
+		return nil, err
+	}
+	release()
+	return adapter, nil
+}
+
+// AcquireAdapter returns the cached adapter for the UVR, creating it when missing. When the
+// registered factory (for example real vs mock) or the backend configuration changed since the
+// adapter was created, a new adapter replaces it; the old one is cleaned up once every
+// operation holding it has called the returned release function.
+func (m *AdapterManager) AcquireAdapter(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, backend translation.Backend, client client.Client, translator *translation.Engine) (ReplicationAdapter, func(), error) {
+	instanceKey := m.getInstanceKey(uvr)
+
+	factory, err := m.registry.GetFactory(backend)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create adapter for %s: %w", uvr.Name, err)
+	}
 	config := m.createAdapterConfig(backend, uvr)
+	fingerprint := adapterFingerprint(factory, config)
+
+	m.mu.Lock()
+	if entry, exists := m.adapters[instanceKey]; exists && entry.fingerprint == fingerprint {
+		entry.inflight++
+		m.mu.Unlock()
+		return entry.adapter, m.releaseFunc(entry), nil
+	}
+	m.mu.Unlock()
+
+	// An invalid new configuration leaves the cached adapter in place
+	if err := factory.ValidateConfig(config); err != nil {
+		return nil, nil, fmt.Errorf("configuration validation failed for backend %s: %w", backend, err)
+	}
 
 	// Create adapter
-	adapter, err := m.registry.CreateAdapter(backend, client, translator, config)
+	adapter, err := factory.CreateAdapter(backend, client, translator, config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create adapter for %s: %w", uvr.Name, err)
+		return nil, nil, fmt.Errorf("failed to create adapter for %s: %w", uvr.Name, err)
 	}
 
 	// Initialize adapter
 	if err := adapter.Initialize(ctx); err != nil {
-		return nil, fmt.Errorf("failed to initialize adapter for %s: %w", uvr.Name, err)
+		return nil, nil, fmt.Errorf("failed to initialize adapter for %s: %w", uvr.Name, err)
 	}
 
-	// Store adapter
+	entry := &managedAdapter{adapter: adapter, fingerprint: fingerprint, inflight: 1}
+
 	m.mu.Lock()
-	m.adapters[instanceKey] = adapter
+	previous, swapped := m.adapters[instanceKey]
+	if swapped && previous.fingerprint == fingerprint {
+		// Lost a race with a concurrent caller creating the same adapter
+		previous.inflight++
+		m.mu.Unlock()
+		m.cleanupAdapter(ctx, adapter)
+		return previous.adapter, m.releaseFunc(previous), nil
+	}
+	m.adapters[instanceKey] = entry
+	idle := swapped && m.retireLocked(previous)
+	onSwap := m.onSwap
 	m.mu.Unlock()
 
-	return adapter, nil
+	if swapped {
+		log.FromContext(ctx).Info("Swapped adapter after configuration change", "uvr", instanceKey, "backend", backend)
+		if idle {
+			m.cleanupAdapter(ctx, previous.adapter)
+		}
+		if onSwap != nil {
+			onSwap(uvr, backend)
+		}
+	}
+
+	return adapter, m.releaseFunc(entry), nil
+}
+
+// releaseFunc returns a function that ends one operation on the entry, cleaning up a retired
+// adapter after its last operation
+func (m *AdapterManager) releaseFunc(entry *managedAdapter) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.mu.Lock()
+			entry.inflight--
+			idle := entry.retired && entry.inflight == 0
+			m.mu.Unlock()
+
+			if idle {
+				m.cleanupAdapter(context.Background(), entry.adapter)
+			}
+		})
+	}
+}
+
+// retireLocked marks an entry as replaced and reports whether it can be cleaned up right away;
+// callers must hold the lock
+func (m *AdapterManager) retireLocked(entry *managedAdapter) bool {
+	entry.retired = true
+	return entry.inflight == 0
 }
 
-// RemoveAdapter removes an adapter instance
+// cleanupAdapter cleans up an adapter that is no longer cached
+func (m *AdapterManager) cleanupAdapter(ctx context.Context, adapter ReplicationAdapter) {
+	if err := adapter.Cleanup(ctx); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to cleanup adapter", "backend", adapter.GetBackendType())
+	}
+}
+
+// RemoveAdapter removes an adapter instance. An adapter still in use is cleaned up once
+// released.
 func (m *AdapterManager) RemoveAdapter(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
 	instanceKey := m.getInstanceKey(uvr)
 
 	m.mu.Lock()
-	adapter, exists := m.adapters[instanceKey]
+	entry, exists := m.adapters[instanceKey]
+	idle := false
 	if exists {
 		delete(m.adapters, instanceKey)
+		idle = m.retireLocked(entry)
 	}
 	m.mu.Unlock()
 
-	if exists {
-		return adapter.Cleanup(ctx)
+	if idle {
+		return entry.adapter.Cleanup(ctx)
 	}
 
 	return nil
@@ -386,18 +512,21 @@ func (m *AdapterManager) GetAdapter(uvr *replicationv1alpha1.UnifiedVolumeReplic
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	adapter, exists := m.adapters[instanceKey]
-	return adapter, exists
+	entry, exists := m.adapters[instanceKey]
+	if !exists {
+		return nil, false
+	}
+	return entry.adapter, true
 }
 
 // Shutdown shuts down all adapters
 func (m *AdapterManager) Shutdown(ctx context.Context) error {
 	m.mu.Lock()
 	adapters := make([]ReplicationAdapter, 0, len(m.adapters))
-	for _, adapter := range m.adapters {
-		adapters = append(adapters, adapter)
+	for _, entry := range m.adapters {
+		adapters = append(adapters, entry.adapter)
 	}
-	m.adapters = make(map[string]ReplicationAdapter)
+	m.adapters = make(map[string]*managedAdapter)
 	m.mu.Unlock()
 
 	// Cleanup all adapters
@@ -416,8 +545,8 @@ func (m *AdapterManager) GetStats() map[string]AdapterStats {
 	defer m.mu.RUnlock()
 
 	stats := make(map[string]AdapterStats)
-	for key, adapter := range m.adapters {
-		if statsProvider, ok := adapter.(*BaseAdapter); ok {
+	for key, entry := range m.adapters {
+		if statsProvider, ok := entry.adapter.(*BaseAdapter); ok {
 			stats[key] = statsProvider.GetStats()
 		}
 	}
@@ -425,6 +554,18 @@ func (m *AdapterManager) GetStats() map[string]AdapterStats {
 	return stats
 }
 
+// adapterFingerprint identifies the factory and configuration an adapter was created from, so
+// a changed registration (real vs mock) or changed credentials can be detected
+func adapterFingerprint(factory AdapterFactory, config *AdapterConfig) string {
+	info := factory.GetInfo()
+	encoded, err := json.Marshal(config)
+	if err != nil {
+		// Custom settings that cannot be encoded as JSON; fmt prints maps in key order
+		encoded = []byte(fmt.Sprintf("%+v", *config))
+	}
+	return fmt.Sprintf("%T/%s/%s/%x", factory, info.Name, info.Version, sha256.Sum256(encoded))
+}
+
 // getInstanceKey creates a unique key for an adapter instance
 func (m *AdapterManager) getInstanceKey(uvr *replicationv1alpha1.UnifiedVolumeReplication) string {
 	return fmt.Sprintf("%s/%s", uvr.Namespace, uvr.Name)
@@ -454,6 +595,17 @@ func (m *AdapterManager) determineBackend(uvr *replicationv1alpha1.UnifiedVolume
 
 // createAdapterConfig creates adapter configuration based on UVR and manager settings
 func (m *AdapterManager) createAdapterConfig(backend translation.Backend, uvr *replicationv1alpha1.UnifiedVolumeReplication) *AdapterConfig {
+	m.mu.RLock()
+	backendConfig := m.backendConfigs[backend]
+	m.mu.RUnlock()
+
+	// Operator-provided backend configuration takes precedence over manager defaults
+	if backendConfig != nil {
+		config := *backendConfig
+		config.Backend = backend
+		return &config
+	}
+
 	config := DefaultAdapterConfig(backend)
 
 	// Apply manager-level defaults
@@ -0,0 +1,113 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// AdapterRegistryPath is the metrics server path listing the registered adapter factories
+const AdapterRegistryPath = "/debug/adapter-registry"
+
+// AdapterRegistryInfo is the body served at AdapterRegistryPath
+type AdapterRegistryInfo struct {
+	// Registries are listed in the order the handler was given them
+	Registries []RegistryListing `json:"registries"`
+}
+
+// RegistryListing lists the factories of one adapter registry
+type RegistryListing struct {
+	// Name identifies the registry, e.g. "operator" for the one reconciles create adapters
+	// from and "global" for GetGlobalRegistry
+	Name string `json:"name"`
+	// Factories are the registered factories, sorted by backend
+	Factories []RegisteredFactory `json:"factories"`
+}
+
+// RegisteredFactory describes a factory registered for a backend
+type RegisteredFactory struct {
+	AdapterFactoryInfo
+	// Type is the Go type of the factory, e.g. "*adapters.TridentAdapterFactory"
+	Type string `json:"type"`
+	// Mock is true when the factory creates simulated adapters rather than ones driving the backend
+	Mock bool `json:"mock"`
+}
+
+// NamedRegistry pairs a registry with the name it is listed under
+type NamedRegistry struct {
+	Name     string
+	Registry Registry
+}
+
+// ListRegistry lists the factories currently registered in registry
+func ListRegistry(name string, registry Registry) RegistryListing {
+	listing := RegistryListing{Name: name, Factories: []RegisteredFactory{}}
+	for _, factory := range registry.ListFactories() {
+		info := factory.GetInfo()
+		// The registry is keyed by GetBackendType, which is what selection goes by
+		info.Backend = factory.GetBackendType()
+		listing.Factories = append(listing.Factories, RegisteredFactory{
+			AdapterFactoryInfo: info,
+			Type:               fmt.Sprintf("%T", factory),
+			Mock:               IsMockFactory(factory),
+		})
+	}
+	sort.Slice(listing.Factories, func(i, j int) bool {
+		return listing.Factories[i].Backend < listing.Factories[j].Backend
+	})
+	return listing
+}
+
+// AdapterRegistryHandler serves the factories of adapter registries as JSON, read when
+// requested, so registrations made after startup are listed
+type AdapterRegistryHandler struct {
+	registries []NamedRegistry
+}
+
+// NewAdapterRegistryHandler creates a handler listing registries in the given order
+func NewAdapterRegistryHandler(registries ...NamedRegistry) *AdapterRegistryHandler {
+	return &AdapterRegistryHandler{registries: registries}
+}
+
+// Info returns the current factories of every registry
+func (h *AdapterRegistryHandler) Info() AdapterRegistryInfo {
+	info := AdapterRegistryInfo{Registries: make([]RegistryListing, 0, len(h.registries))}
+	for _, named := range h.registries {
+		info.Registries = append(info.Registries, ListRegistry(named.Name, named.Registry))
+	}
+	return info
+}
+
+// ServeHTTP writes the info as indented JSON
+func (h *AdapterRegistryHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := json.MarshalIndent(h.Info(), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(append(body, '\n'))
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+func TestAdapterRegistryEndpoint(t *testing.T) {
+	operator := NewRegistry()
+	require.NoError(t, operator.RegisterFactory(NewTridentAdapterFactory()))
+	require.NoError(t, operator.RegisterFactory(NewCephAdapterFactory()))
+	global := NewRegistry()
+	handler := NewAdapterRegistryHandler(NamedRegistry{Name: "operator", Registry: operator}, NamedRegistry{Name: "global", Registry: global})
+
+	fetch := func() AdapterRegistryInfo {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, AdapterRegistryPath, nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		var info AdapterRegistryInfo
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &info))
+		return info
+	}
+
+	info := fetch()
+	require.Len(t, info.Registries, 2)
+	assert.Equal(t, "operator", info.Registries[0].Name)
+	assert.Equal(t, "global", info.Registries[1].Name)
+	assert.Empty(t, info.Registries[1].Factories)
+
+	factories := info.Registries[0].Factories
+	require.Len(t, factories, 2)
+	assert.Equal(t, translation.BackendCeph, factories[0].Backend, "factories are sorted by backend")
+	assert.Equal(t, NewCephAdapterFactory().GetInfo(), factories[0].AdapterFactoryInfo)
+	assert.Equal(t, "*adapters.CephAdapterFactory", factories[0].Type)
+	assert.False(t, factories[0].Mock)
+	assert.Equal(t, translation.BackendTrident, factories[1].Backend)
+	assert.Equal(t, NewTridentAdapterFactory().GetInfo().Version, factories[1].Version)
+	assert.False(t, factories[1].Mock)
+
+	// A mock registered later is listed on the next request
+	mock := NewMockPowerStoreAdapterFactory(nil)
+	require.NoError(t, global.RegisterFactory(mock))
+	require.NoError(t, operator.UnregisterFactory(translation.BackendTrident))
+	require.NoError(t, operator.RegisterFactory(NewMockTridentAdapterFactory(nil)))
+
+	info = fetch()
+	require.Len(t, info.Registries[1].Factories, 1)
+	listed := info.Registries[1].Factories[0]
+	assert.Equal(t, translation.BackendPowerStore, listed.Backend)
+	assert.Equal(t, mock.GetInfo().Name, listed.Name)
+	assert.Equal(t, "*adapters.MockPowerStoreAdapterFactory", listed.Type)
+	assert.True(t, listed.Mock)
+
+	factories = info.Registries[0].Factories
+	require.Len(t, factories, 2)
+	assert.Equal(t, translation.BackendTrident, factories[1].Backend)
+	assert.Equal(t, "*adapters.MockTridentAdapterFactory", factories[1].Type)
+	assert.True(t, factories[1].Mock, "the mock now replaces the real Trident factory")
+
+	assert.True(t, IsMockFactory(NewMockAdapterFactory(translation.BackendCeph, nil)))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, AdapterRegistryPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"fmt"
+	"time"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+// BackendSyncInterval converts a schedule duration to the single-unit form the backends
+// accept: the largest of days, hours and minutes that expresses it exactly, so "1h30m"
+// becomes "90m" and "60m" becomes "1h". Backends schedule syncs with minute granularity,
+// so a duration that is not a whole number of minutes is rejected. An empty value stays
+// empty.
+func BackendSyncInterval(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	d, err := replicationv1alpha1.ParseScheduleDuration(value)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case d <= 0:
+		return "", fmt.Errorf("sync interval %q must be positive", value)
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour)), nil
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour), nil
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute), nil
+	}
+	return "", fmt.Errorf("sync interval %q is not a whole number of minutes", value)
+}
+
+// syncIntervalFor returns the sync interval of a UVR in backend form, as a validation
+// error of the backend when the backend cannot express it
+func syncIntervalFor(uvr *replicationv1alpha1.UnifiedVolumeReplication, backend translation.Backend, operation string) (string, error) {
+	interval, err := BackendSyncInterval(uvr.SyncInterval())
+	if err != nil {
+		return "", NewAdapterErrorWithCause(ErrorTypeValidation, backend, operation, uvr.Name,
+			"sync interval cannot be expressed by the backend", err)
+	}
+	return interval, nil
+}
+
+// sameSyncInterval reports whether an interval read from a backend resource is the
+// duration a UVR asks for, so "60m" and "1h" compare equal. Values that do not parse are
+// compared as written.
+func sameSyncInterval(observed, desired string) bool {
+	o, oerr := replicationv1alpha1.ParseScheduleDuration(observed)
+	d, derr := replicationv1alpha1.ParseScheduleDuration(desired)
+	if oerr != nil || derr != nil {
+		return observed == desired
+	}
+	return o == d
+}
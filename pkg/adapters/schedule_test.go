@@ -0,0 +1,60 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackendSyncInterval(t *testing.T) {
+	tests := []struct {
+		value    string
+		interval string
+	}{
+		{value: "", interval: ""},
+		{value: "5m", interval: "5m"},
+		{value: "60m", interval: "1h"},
+		{value: "1h30m", interval: "90m"},
+		{value: "24h", interval: "1d"},
+		{value: "1d12h", interval: "36h"},
+		{value: "2m0s", interval: "2m"},
+		{value: "120s", interval: "2m"},
+		{value: "30s"},
+		{value: "1h30s"},
+		{value: "0m"},
+		{value: "5 minutes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			interval, err := BackendSyncInterval(tt.value)
+			if tt.interval == "" && tt.value != "" {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.interval, interval)
+		})
+	}
+
+	assert.True(t, sameSyncInterval("1h", "60m"))
+	assert.True(t, sameSyncInterval("1d", "24h"))
+	assert.False(t, sameSyncInterval("1h", "90m"))
+	assert.False(t, sameSyncInterval("hourly", "1h"))
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+const (
+	// VolumeSnapshot is the CSI external-snapshotter CRD used for replication baselines
+	VolumeSnapshotGroup      = "snapshot.storage.k8s.io"
+	VolumeSnapshotAPIVersion = "snapshot.storage.k8s.io/v1"
+	VolumeSnapshotKind       = "VolumeSnapshot"
+
+	// BaselineSnapshotCreatedByAnnotation records which UVR created a baseline snapshot
+	BaselineSnapshotCreatedByAnnotation = "replication.storage.io/created-by"
+)
+
+// baselineSnapshotName returns the VolumeSnapshot used as the baseline of a UVR
+func baselineSnapshotName(uvr *replicationv1alpha1.UnifiedVolumeReplication) string {
+	if name := uvr.Spec.VolumeMapping.Source.BaselineSnapshot.Name; name != "" {
+		return name
+	}
+	return fmt.Sprintf("%s-baseline", uvr.Name)
+}
+
+// newVolumeSnapshot returns an empty unstructured VolumeSnapshot
+func newVolumeSnapshot() *unstructured.Unstructured {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(schema.FromAPIVersionAndKind(VolumeSnapshotAPIVersion, VolumeSnapshotKind))
+	return snapshot
+}
+
+// ensureBaselineSnapshot makes sure the baseline snapshot of the source PVC exists and is
+// ready to use, creating it when the UVR does not name an existing one. It returns the
+// data source reference for the replication, or nil when no baseline was requested.
+func (ba *BaseAdapter) ensureBaselineSnapshot(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (*corev1.TypedLocalObjectReference, error) {
+	source := uvr.Spec.VolumeMapping.Source
+	if source.BaselineSnapshot == nil {
+		return nil, nil
+	}
+
+	name := baselineSnapshotName(uvr)
+	snapshot := newVolumeSnapshot()
+	err := ba.client.Get(ctx, types.NamespacedName{Name: name, Namespace: source.Namespace}, snapshot)
+	switch {
+	case errors.IsNotFound(err) && source.BaselineSnapshot.Name != "":
+		return nil, NewAdapterErrorWithCause(ErrorTypeValidation, ba.backend, "baseline", uvr.Name,
+			fmt.Sprintf("baseline snapshot %s/%s does not exist", source.Namespace, name), err)
+	case errors.IsNotFound(err):
+		if err := ba.createBaselineSnapshot(ctx, uvr, name); err != nil {
+			return nil, err
+		}
+		return nil, NewAdapterError(ErrorTypeResource, ba.backend, "baseline", uvr.Name,
+			fmt.Sprintf("waiting for baseline snapshot %s/%s to become ready", source.Namespace, name))
+	case err != nil:
+		return nil, NewAdapterErrorWithCause(ErrorTypeConnection, ba.backend, "baseline", uvr.Name,
+			"failed to get baseline snapshot", err)
+	}
+
+	if ready, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse"); !ready {
+		return nil, NewAdapterError(ErrorTypeResource, ba.backend, "baseline", uvr.Name,
+			fmt.Sprintf("waiting for baseline snapshot %s/%s to become ready", source.Namespace, name))
+	}
+
+	group := VolumeSnapshotGroup
+	return &corev1.TypedLocalObjectReference{APIGroup: &group, Kind: VolumeSnapshotKind, Name: name}, nil
+}
+
+// createBaselineSnapshot snapshots the source PVC, marking the snapshot as owned by the UVR
+func (ba *BaseAdapter) createBaselineSnapshot(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, name string) error {
+	source := uvr.Spec.VolumeMapping.Source
+
+	snapshot := newVolumeSnapshot()
+	snapshot.SetName(name)
+	snapshot.SetNamespace(source.Namespace)
+	snapshot.SetLabels(map[string]string{"app.kubernetes.io/managed-by": "unified-replication-operator"})
+	snapshot.SetAnnotations(map[string]string{BaselineSnapshotCreatedByAnnotation: client.ObjectKeyFromObject(uvr).String()})
+
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": source.PvcName,
+		},
+	}
+	if className := source.BaselineSnapshot.VolumeSnapshotClassName; className != nil {
+		spec["volumeSnapshotClassName"] = *className
+	}
+	if err := unstructured.SetNestedMap(snapshot.Object, spec, "spec"); err != nil {
+		return NewAdapterErrorWithCause(ErrorTypeOperation, ba.backend, "baseline", uvr.Name,
+			"failed to build baseline snapshot spec", err)
+	}
+
+	if err := ba.client.Create(ctx, snapshot); err != nil && !errors.IsAlreadyExists(err) {
+		return NewAdapterErrorWithCause(ErrorTypeOperation, ba.backend, "baseline", uvr.Name,
+			"failed to create baseline snapshot", err)
+	}
+
+	log.FromContext(ctx).Info("Created baseline snapshot", "snapshot", name, "namespace", source.Namespace, "pvc", source.PvcName)
+	return nil
+}
+
+// cleanupBaselineSnapshot removes a baseline snapshot previously created for this UVR.
+// Snapshots named in the spec belong to the user and are left untouched.
+func (ba *BaseAdapter) cleanupBaselineSnapshot(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	source := uvr.Spec.VolumeMapping.Source
+	if source.BaselineSnapshot == nil || source.BaselineSnapshot.Name != "" {
+		return nil
+	}
+
+	snapshot := newVolumeSnapshot()
+	if err := ba.client.Get(ctx, types.NamespacedName{Name: baselineSnapshotName(uvr), Namespace: source.Namespace}, snapshot); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return NewAdapterErrorWithCause(ErrorTypeConnection, ba.backend, "cleanup", uvr.Name,
+			"failed to get baseline snapshot", err)
+	}
+
+	if snapshot.GetAnnotations()[BaselineSnapshotCreatedByAnnotation] != client.ObjectKeyFromObject(uvr).String() {
+		return nil
+	}
+
+	if err := ba.client.Delete(ctx, snapshot); err != nil && !errors.IsNotFound(err) {
+		return NewAdapterErrorWithCause(ErrorTypeOperation, ba.backend, "cleanup", uvr.Name,
+			"failed to delete baseline snapshot", err)
+	}
+
+	log.FromContext(ctx).Info("Removed baseline snapshot", "snapshot", snapshot.GetName(), "namespace", snapshot.GetNamespace())
+	return nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+// markSnapshotReady simulates the snapshot controller finishing the snapshot
+func markSnapshotReady(t *testing.T, c client.Client, key types.NamespacedName) {
+	snapshot := newVolumeSnapshot()
+	require.NoError(t, c.Get(context.Background(), key, snapshot))
+	require.NoError(t, unstructured.SetNestedField(snapshot.Object, true, "status", "readyToUse"))
+	require.NoError(t, c.Update(context.Background(), snapshot))
+}
+
+// markFirstSyncComplete simulates the mirror daemon reporting a completed sync
+func markFirstSyncComplete(t *testing.T, c client.Client, key types.NamespacedName) {
+	vr := &VolumeReplication{}
+	require.NoError(t, c.Get(context.Background(), key, vr))
+	now := metav1.Now()
+	vr.Status.LastSyncTime = &now
+	require.NoError(t, c.Update(context.Background(), vr))
+}
+
+func TestCephAdapter_BaselineSnapshot(t *testing.T) {
+	ctx := context.Background()
+	snapshotKey := types.NamespacedName{Name: "test-uvr-baseline", Namespace: "default"}
+	vrKey := types.NamespacedName{Name: "test-uvr-vr", Namespace: "default"}
+
+	t.Run("CreatedReferencedAndCleanedUp", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithScheme(newCephTestScheme()).Build()
+		adapter, err := NewCephAdapter(c, translation.NewEngine())
+		require.NoError(t, err)
+
+		uvr := createUnifiedVolumeReplication()
+		className := "csi-rbdplugin-snapclass"
+		uvr.Spec.VolumeMapping.Source.BaselineSnapshot = &replicationv1alpha1.BaselineSnapshot{VolumeSnapshotClassName: &className}
+
+		// The replication waits for the snapshot to become ready
+		err = adapter.EnsureReplication(ctx, uvr)
+		require.Error(t, err)
+		adapterErr, ok := err.(*AdapterError)
+		require.True(t, ok)
+		assert.True(t, adapterErr.IsRetryable())
+
+		snapshot := newVolumeSnapshot()
+		require.NoError(t, c.Get(ctx, snapshotKey, snapshot))
+		pvcName, _, _ := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName")
+		assert.Equal(t, "test-pvc", pvcName)
+		class, _, _ := unstructured.NestedString(snapshot.Object, "spec", "volumeSnapshotClassName")
+		assert.Equal(t, className, class)
+		assert.Equal(t, "default/test-uvr", snapshot.GetAnnotations()[BaselineSnapshotCreatedByAnnotation])
+		assert.True(t, errors.IsNotFound(c.Get(ctx, vrKey, &VolumeReplication{})))
+
+		markSnapshotReady(t, c, snapshotKey)
+		require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+
+		vr := &VolumeReplication{}
+		require.NoError(t, c.Get(ctx, vrKey, vr))
+		require.NotNil(t, vr.Spec.DataSource)
+		assert.Equal(t, VolumeSnapshotGroup, *vr.Spec.DataSource.APIGroup)
+		assert.Equal(t, VolumeSnapshotKind, vr.Spec.DataSource.Kind)
+		assert.Equal(t, "test-uvr-baseline", vr.Spec.DataSource.Name)
+
+		// The snapshot is kept until the baseline has been replicated
+		require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+		require.NoError(t, c.Get(ctx, snapshotKey, newVolumeSnapshot()))
+
+		markFirstSyncComplete(t, c, vrKey)
+		require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+		assert.True(t, errors.IsNotFound(c.Get(ctx, snapshotKey, newVolumeSnapshot())))
+	})
+
+	t.Run("CleanedUpOnDelete", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithScheme(newCephTestScheme()).Build()
+		adapter, err := NewCephAdapter(c, translation.NewEngine())
+		require.NoError(t, err)
+
+		uvr := createUnifiedVolumeReplication()
+		uvr.Spec.VolumeMapping.Source.BaselineSnapshot = &replicationv1alpha1.BaselineSnapshot{}
+
+		require.Error(t, adapter.EnsureReplication(ctx, uvr))
+		markSnapshotReady(t, c, snapshotKey)
+		require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+
+		require.NoError(t, adapter.DeleteReplication(ctx, uvr))
+		assert.True(t, errors.IsNotFound(c.Get(ctx, snapshotKey, newVolumeSnapshot())))
+	})
+
+	t.Run("UserSnapshotKept", func(t *testing.T) {
+		userKey := types.NamespacedName{Name: "user-snapshot", Namespace: "default"}
+		userSnapshot := newVolumeSnapshot()
+		userSnapshot.SetName(userKey.Name)
+		userSnapshot.SetNamespace(userKey.Namespace)
+		require.NoError(t, unstructured.SetNestedField(userSnapshot.Object, true, "status", "readyToUse"))
+
+		c := fake.NewClientBuilder().WithScheme(newCephTestScheme()).WithObjects(userSnapshot).Build()
+		adapter, err := NewCephAdapter(c, translation.NewEngine())
+		require.NoError(t, err)
+
+		uvr := createUnifiedVolumeReplication()
+		uvr.Spec.VolumeMapping.Source.BaselineSnapshot = &replicationv1alpha1.BaselineSnapshot{Name: userKey.Name}
+
+		require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+		vr := &VolumeReplication{}
+		require.NoError(t, c.Get(ctx, vrKey, vr))
+		require.NotNil(t, vr.Spec.DataSource)
+		assert.Equal(t, userKey.Name, vr.Spec.DataSource.Name)
+
+		markFirstSyncComplete(t, c, vrKey)
+		require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+		require.NoError(t, adapter.DeleteReplication(ctx, uvr))
+		require.NoError(t, c.Get(ctx, userKey, newVolumeSnapshot()))
+	})
+
+	t.Run("MissingUserSnapshot", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithScheme(newCephTestScheme()).Build()
+		adapter, err := NewCephAdapter(c, translation.NewEngine())
+		require.NoError(t, err)
+
+		uvr := createUnifiedVolumeReplication()
+		uvr.Spec.VolumeMapping.Source.BaselineSnapshot = &replicationv1alpha1.BaselineSnapshot{Name: "missing"}
+
+		err = adapter.EnsureReplication(ctx, uvr)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not exist")
+		assert.True(t, errors.IsNotFound(c.Get(ctx, vrKey, &VolumeReplication{})))
+	})
+}
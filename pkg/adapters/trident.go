@@ -19,9 +19,11 @@ package adapters
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -64,6 +66,7 @@ func NewTridentAdapter(client client.Client, translator *translation.Engine) (*T
 
 	config := DefaultAdapterConfig(translation.BackendTrident)
 	baseAdapter := NewBaseAdapter(translation.BackendTrident, client, translator, config)
+	baseAdapter.capabilities.Features = append(baseAdapter.capabilities.Features, FeatureReplicaRead)
 
 	adapter := &TridentAdapter{
 		BaseAdapter: baseAdapter,
@@ -81,10 +84,15 @@ func (ta *TridentAdapter) EnsureReplication(ctx context.Context, uvr *replicatio
 
 	// Validate configuration
 	if err := ta.ValidateConfiguration(uvr); err != nil {
-		ta.updateMetrics("ensure", false, startTime)
+		ta.updateMetrics(uvr, "ensure", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendTrident, "ensure", uvr.Name, "configuration validation failed", err)
 	}
 
+	if err := ta.validateStorageClass(ctx, uvr); err != nil {
+		ta.updateMetrics(uvr, "ensure", false, startTime)
+		return err
+	}
+
 	// Check if TridentMirrorRelationship exists
 	existing := &unstructured.Unstructured{}
 	existing.SetGroupVersionKind(TridentMirrorRelationshipGVK)
@@ -97,15 +105,33 @@ func (ta *TridentAdapter) EnsureReplication(ctx context.Context, uvr *replicatio
 		if errors.IsNotFound(err) {
 			// Resource doesn't exist, create it
 			logger.Info("TridentMirrorRelationship not found, creating")
-			return ta.createTridentMirrorRelationship(ctx, uvr, startTime)
+
+			// Reserve capacity and provision the destination before replication is established
+			if err := ta.prepareDestination(ctx, uvr); err != nil {
+				ta.updateMetrics(uvr, "create", false, startTime)
+				return err
+			}
+			if err := ta.createTridentMirrorRelationship(ctx, uvr, startTime); err != nil {
+				ta.ReleaseCapacity(uvr)
+				return err
+			}
+			return nil
 		}
 		// Some other error
-		ta.updateMetrics("ensure", false, startTime)
+		ta.updateMetrics(uvr, "ensure", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendTrident, "ensure", uvr.Name, "failed to check existing TridentMirrorRelationship", err)
 	}
 
-	// Resource exists, update it
+	// Resource exists, update it unless another UVR owns it
+	if err := checkResourceOwner(existing, fmt.Sprintf("TridentMirrorRelationship %s/%s", uvr.Namespace, uvr.Name), uvr); err != nil {
+		ta.updateMetrics(uvr, "ensure", false, startTime)
+		return NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendTrident, "ensure", uvr.Name, "TridentMirrorRelationship belongs to another UVR", err)
+	}
 	logger.V(1).Info("TridentMirrorRelationship exists, updating if needed")
+	if err := ta.verifyDestinationVolume(ctx, uvr); err != nil {
+		ta.updateMetrics(uvr, "ensure", false, startTime)
+		return err
+	}
 	return ta.updateTridentMirrorRelationship(ctx, uvr, existing, startTime)
 }
 
@@ -116,13 +142,13 @@ func (ta *TridentAdapter) createTridentMirrorRelationship(ctx context.Context, u
 	// Translate state and mode
 	tridentState, err := ta.TranslateState(string(uvr.Spec.ReplicationState))
 	if err != nil {
-		ta.updateMetrics("create", false, startTime)
+		ta.updateMetrics(uvr, "create", false, startTime)
 		return err
 	}
 
 	tridentMode, err := ta.TranslateMode(string(uvr.Spec.ReplicationMode))
 	if err != nil {
-		ta.updateMetrics("create", false, startTime)
+		ta.updateMetrics(uvr, "create", false, startTime)
 		return err
 	}
 
@@ -138,6 +164,7 @@ func (ta *TridentAdapter) createTridentMirrorRelationship(ctx context.Context, u
 		"unified-replication.io/name":  uvr.Name,
 	}
 	tmr.SetLabels(convertToStringMap(labels))
+	setResourceOwner(tmr, uvr)
 
 	// Build volumeMappings array (required by Trident CRD)
 	volumeMapping := map[string]interface{}{
@@ -148,31 +175,37 @@ func (ta *TridentAdapter) createTridentMirrorRelationship(ctx context.Context, u
 	// Normalize extended states to actual Trident states
 	normalizedState := normalizeTridentState(tridentState)
 
+	schedule, err := tridentReplicationSchedule(uvr, "create")
+	if err != nil {
+		ta.updateMetrics(uvr, "create", false, startTime)
+		return err
+	}
+
 	// Build spec
 	spec := map[string]interface{}{
 		"state":               normalizedState,
 		"replicationPolicy":   tridentMode,
-		"volumeGroupName":     fmt.Sprintf("%s-vg", uvr.Name),
-		"replicationSchedule": uvr.Spec.Schedule.Rpo,
+		"volumeGroupName":     tridentVolumeGroupName(uvr),
+		"replicationSchedule": schedule,
 		"volumeMappings":      []interface{}{volumeMapping}, // Array with one mapping
 	}
 
 	// Trident-specific extensions removed - struct reserved for future use
 
 	if err := unstructured.SetNestedMap(tmr.Object, spec, "spec"); err != nil {
-		ta.updateMetrics("create", false, startTime)
+		ta.updateMetrics(uvr, "create", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendTrident, "create", uvr.Name,
 			"failed to build TridentMirrorRelationship spec", err)
 	}
 
 	// Create the resource
 	if err := ta.client.Create(ctx, tmr); err != nil {
-		ta.updateMetrics("create", false, startTime)
+		ta.updateMetrics(uvr, "create", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendTrident, "create", uvr.Name,
 			"failed to create TridentMirrorRelationship", err)
 	}
 
-	ta.updateMetrics("create", true, startTime)
+	ta.updateMetrics(uvr, "create", true, startTime)
 	logger.Info("Successfully created Trident mirror relationship")
 	return nil
 }
@@ -184,13 +217,13 @@ func (ta *TridentAdapter) updateTridentMirrorRelationship(ctx context.Context, u
 	// Translate state and mode
 	tridentState, err := ta.TranslateState(string(uvr.Spec.ReplicationState))
 	if err != nil {
-		ta.updateMetrics("update", false, startTime)
+		ta.updateMetrics(uvr, "update", false, startTime)
 		return err
 	}
 
 	tridentMode, err := ta.TranslateMode(string(uvr.Spec.ReplicationMode))
 	if err != nil {
-		ta.updateMetrics("update", false, startTime)
+		ta.updateMetrics(uvr, "update", false, startTime)
 		return err
 	}
 
@@ -203,42 +236,68 @@ func (ta *TridentAdapter) updateTridentMirrorRelationship(ctx context.Context, u
 	// Normalize extended states to actual Trident states
 	normalizedState := normalizeTridentState(tridentState)
 
-	// Update spec fields
+	schedule, err := tridentReplicationSchedule(uvr, "update")
+	if err != nil {
+		ta.updateMetrics(uvr, "update", false, startTime)
+		return err
+	}
+
+	// Update spec fields, keeping the mappings of other group members
 	spec := map[string]interface{}{
 		"state":               normalizedState,
 		"replicationPolicy":   tridentMode,
-		"volumeGroupName":     fmt.Sprintf("%s-vg", uvr.Name),
-		"replicationSchedule": uvr.Spec.Schedule.Rpo,
-		"volumeMappings":      []interface{}{volumeMapping}, // Array with one mapping
+		"volumeGroupName":     tridentVolumeGroupName(uvr),
+		"replicationSchedule": schedule,
+		"volumeMappings":      preserveGroupMembers(existing, "volumeMappings", volumeMapping),
 	}
 
-	// Trident-specific extensions removed - struct reserved for future use
+	// A paused relationship keeps running without a schedule until it is resumed
+	if _, paused := existing.GetAnnotations()[PausedAnnotation]; paused {
+		delete(spec, "replicationSchedule")
+	}
+
+	previousState, _, _ := unstructured.NestedString(existing.Object, "spec", "state")
 
 	if err := unstructured.SetNestedMap(existing.Object, spec, "spec"); err != nil {
-		ta.updateMetrics("update", false, startTime)
+		ta.updateMetrics(uvr, "update", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendTrident, "update", uvr.Name,
 			"failed to update TridentMirrorRelationship spec", err)
 	}
 
+	setResourceOwner(existing, uvr)
+
 	// Update the resource
 	if err := ta.client.Update(ctx, existing); err != nil {
-		ta.updateMetrics("update", false, startTime)
+		ta.updateMetrics(uvr, "update", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendTrident, "update", uvr.Name,
 			"failed to update TridentMirrorRelationship", err)
 	}
 
-	ta.updateMetrics("update", true, startTime)
+	ta.updateMetrics(uvr, "update", true, startTime)
+	if previousState != normalizedState {
+		ta.recordStateChange(uvr, startTime)
+	}
 	logger.Info("Successfully updated Trident mirror relationship")
 	return nil
 }
 
 // updateMetrics is a helper that delegates to BaseAdapter
-func (ta *TridentAdapter) updateMetrics(operation string, success bool, startTime time.Time) {
-	ta.BaseAdapter.updateMetrics(operation, success, startTime)
+func (ta *TridentAdapter) updateMetrics(uvr *replicationv1alpha1.UnifiedVolumeReplication, operation string, success bool, startTime time.Time) {
+	ta.BaseAdapter.updateMetrics(uvr, operation, success, startTime)
 }
 
-// DeleteReplication deletes a TridentMirrorRelationship resource
+// DeleteReplication deletes the TridentMirrorRelationship and an auto-provisioned
+// destination volume
 func (ta *TridentAdapter) DeleteReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	if err := ta.DeleteBackendResource(ctx, uvr); err != nil {
+		return err
+	}
+	return ta.releaseDestination(ctx, uvr)
+}
+
+// DeleteBackendResource deletes the TridentMirrorRelationship, leaving the destination
+// volume in place
+func (ta *TridentAdapter) DeleteBackendResource(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
 	logger := log.FromContext(ctx).WithName("trident-adapter").WithValues("uvr", uvr.Name)
 	logger.Info("Deleting Trident mirror relationship")
 
@@ -253,15 +312,15 @@ func (ta *TridentAdapter) DeleteReplication(ctx context.Context, uvr *replicatio
 		if errors.IsNotFound(err) {
 			// Already deleted, success
 			logger.Info("TridentMirrorRelationship already deleted")
-			ta.updateMetrics("delete", true, startTime)
+			ta.updateMetrics(uvr, "delete", true, startTime)
 			return nil
 		}
-		ta.updateMetrics("delete", false, startTime)
+		ta.updateMetrics(uvr, "delete", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendTrident, "delete", uvr.Name,
 			"failed to delete TridentMirrorRelationship", err)
 	}
 
-	ta.updateMetrics("delete", true, startTime)
+	ta.updateMetrics(uvr, "delete", true, startTime)
 	logger.Info("Successfully deleted Trident mirror relationship")
 	return nil
 }
@@ -279,11 +338,11 @@ func (ta *TridentAdapter) GetReplicationStatus(ctx context.Context, uvr *replica
 
 	if err := ta.client.Get(ctx, key, tmr); err != nil {
 		if errors.IsNotFound(err) {
-			ta.updateMetrics("status", false, startTime)
+			ta.updateMetrics(uvr, "status", false, startTime)
 			return nil, NewAdapterError(ErrorTypeResource, translation.BackendTrident, "status", uvr.Name,
 				"TridentMirrorRelationship not found")
 		}
-		ta.updateMetrics("status", false, startTime)
+		ta.updateMetrics(uvr, "status", false, startTime)
 		return nil, NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendTrident, "status", uvr.Name,
 			"failed to get TridentMirrorRelationship", err)
 	}
@@ -291,7 +350,7 @@ func (ta *TridentAdapter) GetReplicationStatus(ctx context.Context, uvr *replica
 	// Extract status
 	statusMap, found, err := unstructured.NestedMap(tmr.Object, "status")
 	if err != nil || !found {
-		ta.updateMetrics("status", false, startTime)
+		ta.updateMetrics(uvr, "status", false, startTime)
 		return nil, NewAdapterError(ErrorTypeOperation, translation.BackendTrident, "status", uvr.Name,
 			"status not available yet")
 	}
@@ -348,23 +407,346 @@ func (ta *TridentAdapter) GetReplicationStatus(ctx context.Context, uvr *replica
 		State:              unifiedState,
 		Mode:               unifiedMode,
 		Health:             health,
+		DegradedReason:     degradedReasonForHealth(health),
 		LastSyncTime:       lastSyncTime,
 		ObservedGeneration: uvr.Generation,
 		BackendSpecific:    statusMap,
 	}
+	status.PrimaryCluster, status.PrimarySite = resolvePrimaryIdentity(uvr, unifiedState)
+	status.Direction = resolveReplicationDirection(unifiedState)
+	status.Members = tridentMemberSyncStatus(statusMap)
+	status.ConsistencyLevel = resolveConsistencyLevel(tmr.GetAnnotations(), lastSyncTime)
 
-	ta.updateMetrics("status", true, startTime)
+	ta.updateMetrics(uvr, "status", true, startTime)
 	return status, nil
 }
 
+// GetConsistencyState reports whether the latest sync point of the TridentMirrorRelationship was taken
+// while the application was quiesced
+func (ta *TridentAdapter) GetConsistencyState(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (ConsistencyLevel, error) {
+	return consistencyLevelOf(ta.GetReplicationStatus(ctx, uvr))
+}
+
+// tridentMemberSyncStatus reads the per-volume transfer times Trident reports for each
+// volume mapping of the volume group
+func tridentMemberSyncStatus(statusMap map[string]interface{}) []MemberSyncStatus {
+	mappings, _, _ := unstructured.NestedSlice(statusMap, "volumeMappings")
+	members := make([]MemberSyncStatus, 0, len(mappings))
+	for _, mapping := range mappings {
+		mappingMap, ok := mapping.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		pvcName, _, _ := unstructured.NestedString(mappingMap, "localPVCName")
+		if pvcName == "" {
+			continue
+		}
+		member := MemberSyncStatus{PvcName: pvcName}
+		if lastTransfer, _, _ := unstructured.NestedString(mappingMap, "lastTransferTime"); lastTransfer != "" {
+			if t, err := time.Parse(time.RFC3339, lastTransfer); err == nil {
+				member.LastSyncTime = &t
+			}
+		}
+		members = append(members, member)
+	}
+	if len(members) == 0 {
+		return nil
+	}
+	return members
+}
+
+// AddGroupMember adds a volume mapping to the mirror relationship's volume group
+func (ta *TridentAdapter) AddGroupMember(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, member replicationv1alpha1.VolumeMapping) error {
+	tmr, err := ta.getTridentMirrorRelationship(ctx, uvr, "add-member")
+	if err != nil {
+		return err
+	}
+
+	if indexOfVolumeEntry(tmr, "volumeMappings", "localPVCName", member.Source.PvcName) >= 0 {
+		return nil
+	}
+	if err := appendVolumeEntry(tmr, "volumeMappings", map[string]interface{}{
+		"localPVCName":       member.Source.PvcName,
+		"remoteVolumeHandle": member.Destination.VolumeHandle,
+	}); err != nil {
+		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendTrident, "add-member", uvr.Name,
+			"failed to update volume mappings", err)
+	}
+
+	if err := ta.client.Update(ctx, tmr); err != nil {
+		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendTrident, "add-member", uvr.Name,
+			"failed to update TridentMirrorRelationship", err)
+	}
+
+	log.FromContext(ctx).Info("Added volume to Trident volume group", "uvr", uvr.Name, "pvc", member.Source.PvcName)
+	return nil
+}
+
+// RemoveGroupMember removes a volume mapping from the mirror relationship's volume group
+func (ta *TridentAdapter) RemoveGroupMember(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, member replicationv1alpha1.VolumeMapping) error {
+	tmr, err := ta.getTridentMirrorRelationship(ctx, uvr, "remove-member")
+	if err != nil {
+		return err
+	}
+
+	index := indexOfVolumeEntry(tmr, "volumeMappings", "localPVCName", member.Source.PvcName)
+	if index < 0 {
+		return nil
+	}
+	if err := removeVolumeEntry(tmr, "volumeMappings", index); err != nil {
+		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendTrident, "remove-member", uvr.Name,
+			"failed to update volume mappings", err)
+	}
+
+	if err := ta.client.Update(ctx, tmr); err != nil {
+		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendTrident, "remove-member", uvr.Name,
+			"failed to update TridentMirrorRelationship", err)
+	}
+
+	log.FromContext(ctx).Info("Removed volume from Trident volume group", "uvr", uvr.Name, "pvc", member.Source.PvcName)
+	return nil
+}
+
+// MembershipChangeRequiresPause reports false, Trident updates volume groups in place
+func (ta *TridentAdapter) MembershipChangeRequiresPause() bool {
+	return false
+}
+
+// DeactivateReplication removes the replication schedule from the TridentMirrorRelationship,
+// so ONTAP stops scheduled SnapMirror updates while the relationship stays established
+func (ta *TridentAdapter) DeactivateReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	logger := log.FromContext(ctx).WithName("trident-adapter").WithValues("uvr", uvr.Name)
+	startTime := time.Now()
+
+	tmr, err := ta.getTridentMirrorRelationship(ctx, uvr, "deactivate")
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		ta.updateMetrics(uvr, "deactivate", false, startTime)
+		return err
+	}
+
+	unstructured.RemoveNestedField(tmr.Object, "spec", "replicationSchedule")
+	annotations := tmr.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if _, ok := annotations[DeactivatedAnnotation]; !ok {
+		annotations[DeactivatedAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	}
+	tmr.SetAnnotations(annotations)
+
+	if err := ta.client.Update(ctx, tmr); err != nil {
+		ta.updateMetrics(uvr, "deactivate", false, startTime)
+		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendTrident, "deactivate", uvr.Name,
+			"failed to deactivate TridentMirrorRelationship", err)
+	}
+
+	ta.updateMetrics(uvr, "deactivate", true, startTime)
+	logger.Info("Deactivated Trident mirror relationship")
+	return nil
+}
+
+// ReactivateReplication removes the deactivation marker; EnsureReplication restores the schedule
+func (ta *TridentAdapter) ReactivateReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	logger := log.FromContext(ctx).WithName("trident-adapter").WithValues("uvr", uvr.Name)
+	startTime := time.Now()
+
+	tmr, err := ta.getTridentMirrorRelationship(ctx, uvr, "reactivate")
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		ta.updateMetrics(uvr, "reactivate", false, startTime)
+		return err
+	}
+	annotations := tmr.GetAnnotations()
+	if _, ok := annotations[DeactivatedAnnotation]; !ok {
+		return nil
+	}
+	delete(annotations, DeactivatedAnnotation)
+	tmr.SetAnnotations(annotations)
+
+	if err := ta.client.Update(ctx, tmr); err != nil {
+		ta.updateMetrics(uvr, "reactivate", false, startTime)
+		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendTrident, "reactivate", uvr.Name,
+			"failed to reactivate TridentMirrorRelationship", err)
+	}
+
+	ta.updateMetrics(uvr, "reactivate", true, startTime)
+	logger.Info("Reactivated Trident mirror relationship")
+	return nil
+}
+
+// getTridentMirrorRelationship fetches the TridentMirrorRelationship backing a UVR
+func (ta *TridentAdapter) getTridentMirrorRelationship(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, operation string) (*unstructured.Unstructured, error) {
+	tmr := &unstructured.Unstructured{}
+	tmr.SetGroupVersionKind(TridentMirrorRelationshipGVK)
+	if err := ta.client.Get(ctx, types.NamespacedName{Name: uvr.Name, Namespace: uvr.Namespace}, tmr); err != nil {
+		return nil, NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendTrident, operation, uvr.Name,
+			"failed to get TridentMirrorRelationship", err)
+	}
+	return tmr, nil
+}
+
+// tridentVolumeGroupName returns the volume group name of the mirror relationship
+func tridentVolumeGroupName(uvr *replicationv1alpha1.UnifiedVolumeReplication) string {
+	return fmt.Sprintf("%s-vg", uvr.Name)
+}
+
+// tridentReplicationSchedule returns the replicationSchedule for the mirror relationship:
+// the SnapMirror cron schedule when the schedule is delegated to ONTAP, the sync interval
+// otherwise
+func tridentReplicationSchedule(uvr *replicationv1alpha1.UnifiedVolumeReplication, operation string) (string, error) {
+	if uvr.Spec.Schedule.Delegate {
+		if schedule, err := SnapMirrorSchedule(uvr.Spec.Schedule.Rpo); err == nil {
+			return schedule, nil
+		}
+	}
+	return syncIntervalFor(uvr, translation.BackendTrident, operation)
+}
+
+// SnapMirrorSchedule converts an RPO to the cron schedule ONTAP runs SnapMirror updates on.
+// Cron schedules have minute granularity and must divide the hour or the day evenly, so
+// RPOs such as 45m, 5h or 1h30m cannot be delegated.
+func SnapMirrorSchedule(rpo string) (string, error) {
+	d, err := replicationv1alpha1.ParseScheduleDuration(rpo)
+	if err != nil || d <= 0 {
+		return "", fmt.Errorf("invalid RPO %q", rpo)
+	}
+	if d%time.Minute != 0 {
+		return "", fmt.Errorf("RPO %q is below the one minute granularity of SnapMirror schedules", rpo)
+	}
+
+	minutes := int(d / time.Minute)
+	switch {
+	case minutes < 60 && 60%minutes == 0:
+		return fmt.Sprintf("*/%d * * * *", minutes), nil
+	case minutes == 60:
+		return "0 * * * *", nil
+	case minutes%60 == 0 && minutes < 24*60 && (24*60)%minutes == 0:
+		return fmt.Sprintf("0 */%d * * *", minutes/60), nil
+	case minutes == 24*60:
+		return "0 0 * * *", nil
+	}
+	return "", fmt.Errorf("RPO %q cannot be expressed as a SnapMirror cron schedule", rpo)
+}
+
+// ConfigureNativeSchedule checks that the RPO maps to a SnapMirror schedule. The schedule is
+// written to the mirror relationship's replicationSchedule by EnsureReplication, after which
+// ONTAP runs the updates itself.
+func (ta *TridentAdapter) ConfigureNativeSchedule(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	if _, err := SnapMirrorSchedule(uvr.Spec.Schedule.Rpo); err != nil {
+		return NewAdapterErrorWithCause(ErrorTypeValidation, translation.BackendTrident, "schedule", uvr.Name,
+			"schedule cannot be delegated to SnapMirror", err)
+	}
+	return nil
+}
+
+// EffectiveBackendConfig reports the volume group the operator names for the relationship.
+// Trident has no replication class.
+func (ta *TridentAdapter) EffectiveBackendConfig(uvr *replicationv1alpha1.UnifiedVolumeReplication) (string, map[string]string) {
+	return "", map[string]string{"volumeGroupName": tridentVolumeGroupName(uvr)}
+}
+
+// RecreationRequired reports changes the mirror relationship cannot take in place: ONTAP
+// does not convert a SnapMirror relationship between synchronous and asynchronous policies,
+// nor move it to a different destination volume.
+func (ta *TridentAdapter) RecreationRequired(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) ([]PolicyDrift, error) {
+	tmr := &unstructured.Unstructured{}
+	tmr.SetGroupVersionKind(TridentMirrorRelationshipGVK)
+	if err := ta.client.Get(ctx, types.NamespacedName{Name: uvr.Name, Namespace: uvr.Namespace}, tmr); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendTrident, "recreation-check", uvr.Name,
+			"failed to get TridentMirrorRelationship", err)
+	}
+
+	var changes []PolicyDrift
+	tridentMode, err := ta.TranslateMode(string(uvr.Spec.ReplicationMode))
+	if err != nil {
+		return nil, err
+	}
+	if policy, found, _ := unstructured.NestedString(tmr.Object, "spec", "replicationPolicy"); found && policy != tridentMode {
+		changes = append(changes, PolicyDrift{Field: "replicationPolicy", Desired: tridentMode, Observed: policy})
+	}
+
+	mappings, _, _ := unstructured.NestedSlice(tmr.Object, "spec", "volumeMappings")
+	if len(mappings) > 0 {
+		if mapping, ok := mappings[0].(map[string]interface{}); ok {
+			desired := uvr.Spec.VolumeMapping.Destination.VolumeHandle
+			if observed, ok := mapping["remoteVolumeHandle"].(string); ok && observed != desired {
+				changes = append(changes, PolicyDrift{Field: "remoteVolumeHandle", Desired: desired, Observed: observed})
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+// BackendResourceExists reports whether the TridentMirrorRelationship for the UVR exists
+func (ta *TridentAdapter) BackendResourceExists(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (bool, error) {
+	tmr := &unstructured.Unstructured{}
+	tmr.SetGroupVersionKind(TridentMirrorRelationshipGVK)
+	if err := ta.client.Get(ctx, types.NamespacedName{Name: uvr.Name, Namespace: uvr.Namespace}, tmr); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendTrident, "recreation-check", uvr.Name,
+			"failed to get TridentMirrorRelationship", err)
+	}
+	return true, nil
+}
+
+// VerifyReplicaReadable reports whether the SnapMirror destination volume can be mounted
+// read-only. ONTAP exposes destination volumes read-only once the baseline transfer has
+// completed, so the replica is readable while the mirror relationship is established.
+func (ta *TridentAdapter) VerifyReplicaReadable(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (bool, string, error) {
+	tmr, err := ta.getTridentMirrorRelationship(ctx, uvr, "verify-replica")
+	if err != nil {
+		return false, "", err
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(tmr.Object, "status", "conditions")
+	for _, cond := range conditions {
+		condMap, ok := cond.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condType, _, _ := unstructured.NestedString(condMap, "type"); condType == "Error" {
+			message, _, _ := unstructured.NestedString(condMap, "message")
+			return false, fmt.Sprintf("mirror relationship reports an error: %s", message), nil
+		}
+	}
+
+	state, _, _ := unstructured.NestedString(tmr.Object, "status", "state")
+	switch state {
+	case "established", "reestablished":
+		return true, "SnapMirror destination volume is mountable read-only", nil
+	case "":
+		return false, "mirror relationship has not completed its baseline transfer", nil
+	default:
+		return false, fmt.Sprintf("mirror relationship is %s", state), nil
+	}
+}
+
 // PromoteReplica promotes a replica to source
 func (ta *TridentAdapter) PromoteReplica(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
 	logger := log.FromContext(ctx).WithName("trident-adapter").WithValues("uvr", uvr.Name)
 	logger.Info("Promoting Trident replica")
 
+	startTime := time.Now()
+
 	// For Trident, promotion is done by updating state to "established" (source)
 	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
-	return ta.EnsureReplication(ctx, uvr)
+	if err := ta.EnsureReplication(ctx, uvr); err != nil {
+		ta.updateMetrics(uvr, "promote", false, startTime)
+		return err
+	}
+	ta.updateMetrics(uvr, "promote", true, startTime)
+	return nil
 }
 
 // DemoteSource demotes a source to replica
@@ -372,9 +754,16 @@ func (ta *TridentAdapter) DemoteSource(ctx context.Context, uvr *replicationv1al
 	logger := log.FromContext(ctx).WithName("trident-adapter").WithValues("uvr", uvr.Name)
 	logger.Info("Demoting Trident source")
 
+	startTime := time.Now()
+
 	// For Trident, demotion is done by updating state to "snapmirrored" (replica)
 	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateReplica
-	return ta.EnsureReplication(ctx, uvr)
+	if err := ta.EnsureReplication(ctx, uvr); err != nil {
+		ta.updateMetrics(uvr, "demote", false, startTime)
+		return err
+	}
+	ta.updateMetrics(uvr, "demote", true, startTime)
+	return nil
 }
 
 // ResyncReplication triggers a resync operation
@@ -382,6 +771,8 @@ func (ta *TridentAdapter) ResyncReplication(ctx context.Context, uvr *replicatio
 	logger := log.FromContext(ctx).WithName("trident-adapter").WithValues("uvr", uvr.Name)
 	logger.Info("Resyncing Trident mirror relationship")
 
+	startTime := time.Now()
+
 	// Create TridentActionMirrorUpdate for resync
 	action := &unstructured.Unstructured{}
 	action.SetGroupVersionKind(TridentActionMirrorUpdateGVK)
@@ -394,19 +785,152 @@ func (ta *TridentAdapter) ResyncReplication(ctx context.Context, uvr *replicatio
 	}
 
 	if err := unstructured.SetNestedMap(action.Object, spec, "spec"); err != nil {
+		ta.updateMetrics(uvr, "resync", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendTrident, "resync", uvr.Name,
 			"failed to build action spec", err)
 	}
 
 	if err := ta.client.Create(ctx, action); err != nil {
+		ta.updateMetrics(uvr, "resync", false, startTime)
 		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendTrident, "resync", uvr.Name,
 			"failed to create resync action", err)
 	}
 
+	ta.updateMetrics(uvr, "resync", true, startTime)
 	logger.Info("Successfully triggered resync action")
 	return nil
 }
 
+// tridentProvisioners are the provisioners of the storage classes Trident serves, the CSI
+// driver and the legacy frontend
+var tridentProvisioners = []string{"csi.trident.netapp.io", "netapp.io/trident"}
+
+// validateStorageClass checks that the UVR's source storage class is served by Trident: a
+// name pointing to Trident or NetApp, as the factory matches, or failing that a Trident
+// provisioner. A class that does not exist is left to the source PVC, which cannot be bound
+// without it.
+func (ta *TridentAdapter) validateStorageClass(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	name := uvr.Spec.SourceEndpoint.StorageClass
+	if isTridentStorageClass(name) {
+		return nil
+	}
+	storageClass := &storagev1.StorageClass{}
+	if err := ta.client.Get(ctx, types.NamespacedName{Name: name}, storageClass); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return NewAdapterErrorWithCause(ErrorTypeConnection, translation.BackendTrident, "validate", uvr.Name,
+			"failed to get StorageClass", err)
+	}
+	if !slices.Contains(tridentProvisioners, storageClass.Provisioner) {
+		return NewAdapterError(ErrorTypeValidation, translation.BackendTrident, "validate", uvr.Name,
+			fmt.Sprintf("storage class %s is provisioned by %s, not Trident", name, storageClass.Provisioner))
+	}
+	return nil
+}
+
+// PauseReplication stops scheduled SnapMirror updates by removing the replication schedule
+// from the TridentMirrorRelationship and marking it with PausedAnnotation, which keeps
+// EnsureReplication from restoring the schedule until the replication is resumed
+func (ta *TridentAdapter) PauseReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	logger := log.FromContext(ctx).WithName("trident-adapter").WithValues("uvr", uvr.Name)
+	startTime := time.Now()
+
+	tmr, err := ta.getTridentMirrorRelationship(ctx, uvr, "pause")
+	if err != nil {
+		ta.updateMetrics(uvr, "pause", false, startTime)
+		return err
+	}
+
+	annotations := tmr.GetAnnotations()
+	if _, ok := annotations[PausedAnnotation]; ok {
+		return nil
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[PausedAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	tmr.SetAnnotations(annotations)
+	unstructured.RemoveNestedField(tmr.Object, "spec", "replicationSchedule")
+
+	if err := ta.client.Update(ctx, tmr); err != nil {
+		ta.updateMetrics(uvr, "pause", false, startTime)
+		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendTrident, "pause", uvr.Name,
+			"failed to pause TridentMirrorRelationship", err)
+	}
+
+	ta.updateMetrics(uvr, "pause", true, startTime)
+	logger.Info("Paused Trident mirror relationship")
+	return nil
+}
+
+// ResumeReplication restores the replication schedule of a paused TridentMirrorRelationship
+func (ta *TridentAdapter) ResumeReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	logger := log.FromContext(ctx).WithName("trident-adapter").WithValues("uvr", uvr.Name)
+	startTime := time.Now()
+
+	tmr, err := ta.getTridentMirrorRelationship(ctx, uvr, "resume")
+	if err != nil {
+		ta.updateMetrics(uvr, "resume", false, startTime)
+		return err
+	}
+
+	annotations := tmr.GetAnnotations()
+	if _, ok := annotations[PausedAnnotation]; !ok {
+		return nil
+	}
+	schedule, err := tridentReplicationSchedule(uvr, "resume")
+	if err != nil {
+		ta.updateMetrics(uvr, "resume", false, startTime)
+		return err
+	}
+	delete(annotations, PausedAnnotation)
+	tmr.SetAnnotations(annotations)
+	if err := unstructured.SetNestedField(tmr.Object, schedule, "spec", "replicationSchedule"); err != nil {
+		ta.updateMetrics(uvr, "resume", false, startTime)
+		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendTrident, "resume", uvr.Name,
+			"failed to restore the replication schedule", err)
+	}
+
+	if err := ta.client.Update(ctx, tmr); err != nil {
+		ta.updateMetrics(uvr, "resume", false, startTime)
+		return NewAdapterErrorWithCause(ErrorTypeOperation, translation.BackendTrident, "resume", uvr.Name,
+			"failed to resume TridentMirrorRelationship", err)
+	}
+
+	ta.updateMetrics(uvr, "resume", true, startTime)
+	logger.Info("Resumed Trident mirror relationship")
+	return nil
+}
+
+// FailoverReplication performs a failover operation
+func (ta *TridentAdapter) FailoverReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	logger := log.FromContext(ctx).WithName("trident-adapter").WithValues("uvr", uvr.Name)
+	logger.Info("Performing Trident failover")
+
+	// Failover breaks the SnapMirror relationship and makes the destination writable
+	return ta.PromoteReplica(ctx, uvr)
+}
+
+// FailbackReplication performs a failback operation: the source is demoted back to a
+// replica and a SnapMirror update brings it level with the new source
+func (ta *TridentAdapter) FailbackReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error {
+	logger := log.FromContext(ctx).WithName("trident-adapter").WithValues("uvr", uvr.Name)
+	logger.Info("Performing Trident failback")
+
+	if err := ta.DemoteSource(ctx, uvr); err != nil {
+		return err
+	}
+	return ta.ResyncReplication(ctx, uvr)
+}
+
+// isTridentStorageClass reports whether a storage class name points to Trident or NetApp
+func isTridentStorageClass(storageClass string) bool {
+	storageClass = strings.ToLower(storageClass)
+	return strings.Contains(storageClass, "trident") || strings.Contains(storageClass, "netapp") ||
+		strings.Contains(storageClass, "ontap")
+}
+
 // Helper functions
 
 // normalizeTridentState normalizes extended translation states to actual Trident states
@@ -508,8 +1032,7 @@ func (f *TridentAdapterFactory) Supports(uvr *replicationv1alpha1.UnifiedVolumeR
 	}
 
 	// Check if storage class indicates Trident/NetApp
-	storageClass := strings.ToLower(uvr.Spec.SourceEndpoint.StorageClass)
-	return strings.Contains(storageClass, "trident") || strings.Contains(storageClass, "netapp")
+	return isTridentStorageClass(uvr.Spec.SourceEndpoint.StorageClass)
 }
 
 // Register the Trident adapter factory with the global registry
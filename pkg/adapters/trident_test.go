@@ -19,11 +19,15 @@ package adapters
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	storagev1 "k8s.io/api/storage/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
@@ -146,6 +150,101 @@ func TestTridentAdapter_StateTranslation(t *testing.T) {
 	})
 }
 
+func TestTridentAdapter_ValidateStorageClass(t *testing.T) {
+	ctx := context.Background()
+	classes := []client.Object{
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "gold"}, Provisioner: "csi.trident.netapp.io"},
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "block"}, Provisioner: "rbd.csi.ceph.com"},
+	}
+	adapter, err := NewTridentAdapter(fake.NewClientBuilder().WithObjects(classes...).Build(), translation.NewEngine())
+	require.NoError(t, err)
+
+	for storageClass, expected := range map[string]bool{
+		"trident-nas":      true,
+		"netapp-ontap-san": true,
+		"gold":             true,
+		"block":            false,
+		"missing":          true,
+	} {
+		uvr := createTestUVRForTrident("test-validate", "default")
+		uvr.Spec.SourceEndpoint.StorageClass = storageClass
+		err := adapter.validateStorageClass(ctx, uvr)
+		if expected {
+			assert.NoError(t, err, "storage class %q", storageClass)
+		} else {
+			assert.Error(t, err, "storage class %q", storageClass)
+		}
+	}
+
+	// EnsureReplication creates nothing for a storage class Trident does not serve
+	uvr := createTestUVRForTrident("test-validate", "default")
+	uvr.Spec.SourceEndpoint.StorageClass = "block"
+	err = adapter.EnsureReplication(ctx, uvr)
+	adapterErr, ok := GetAdapterError(err)
+	require.True(t, ok)
+	assert.Equal(t, ErrorTypeValidation, adapterErr.Type)
+	exists, err := adapter.BackendResourceExists(ctx, uvr)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestTridentAdapter_PauseResume(t *testing.T) {
+	ctx := context.Background()
+	adapter, err := NewTridentAdapter(fake.NewClientBuilder().Build(), translation.NewEngine())
+	require.NoError(t, err)
+
+	uvr := createTestUVRForTrident("test-pause", "default")
+	uvr.Spec.Schedule.Mode = replicationv1alpha1.ScheduleModeInterval
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+
+	relationship := func() (string, bool) {
+		tmr, err := adapter.getTridentMirrorRelationship(ctx, uvr, "test")
+		require.NoError(t, err)
+		schedule, _, _ := unstructured.NestedString(tmr.Object, "spec", "replicationSchedule")
+		_, paused := tmr.GetAnnotations()[PausedAnnotation]
+		return schedule, paused
+	}
+
+	require.NoError(t, adapter.PauseReplication(ctx, uvr))
+	schedule, paused := relationship()
+	assert.True(t, paused)
+	assert.Empty(t, schedule, "ONTAP runs no scheduled updates while paused")
+
+	// Ensuring the spec does not restore the schedule of a paused relationship
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+	schedule, _ = relationship()
+	assert.Empty(t, schedule)
+
+	require.NoError(t, adapter.ResumeReplication(ctx, uvr))
+	schedule, paused = relationship()
+	assert.False(t, paused)
+	assert.Equal(t, "15m", schedule)
+	require.NoError(t, adapter.ResumeReplication(ctx, uvr), "resuming is idempotent")
+
+	assert.Error(t, adapter.PauseReplication(ctx, createTestUVRForTrident("test-missing", "default")))
+}
+
+func TestTridentAdapter_FailoverFailback(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().Build()
+	adapter, err := NewTridentAdapter(c, translation.NewEngine())
+	require.NoError(t, err)
+
+	uvr := createTestUVRForTrident("test-failover", "default")
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateReplica
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+
+	require.NoError(t, adapter.FailoverReplication(ctx, uvr))
+	assert.Equal(t, replicationv1alpha1.ReplicationStateSource, uvr.Spec.ReplicationState)
+
+	require.NoError(t, adapter.FailbackReplication(ctx, uvr))
+	assert.Equal(t, replicationv1alpha1.ReplicationStateReplica, uvr.Spec.ReplicationState)
+	actions := &unstructured.UnstructuredList{}
+	actions.SetGroupVersionKind(TridentActionMirrorUpdateGVK.GroupVersion().WithKind("TridentActionMirrorUpdateList"))
+	require.NoError(t, c.List(ctx, actions))
+	assert.Len(t, actions.Items, 1, "failback brings the demoted volume level with a mirror update")
+}
+
 // Helper function
 func createTestUVRForTrident(name, namespace string) *replicationv1alpha1.UnifiedVolumeReplication {
 	return &replicationv1alpha1.UnifiedVolumeReplication{
@@ -187,3 +286,266 @@ func createTestUVRForTrident(name, namespace string) *replicationv1alpha1.Unifie
 		},
 	}
 }
+
+func TestTridentAdapter_GroupMembership(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientBuilder().Build()
+	adapter, err := NewTridentAdapter(client, translation.NewEngine())
+	require.NoError(t, err)
+
+	uvr := createTestUVRForTrident("test-group", "default")
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+
+	localPVCs := func() []string {
+		tmr, err := adapter.getTridentMirrorRelationship(ctx, uvr, "test")
+		require.NoError(t, err)
+		mappings, _, _ := unstructured.NestedSlice(tmr.Object, "spec", "volumeMappings")
+		var names []string
+		for _, m := range mappings {
+			names = append(names, m.(map[string]interface{})["localPVCName"].(string))
+		}
+		return names
+	}
+
+	member := replicationv1alpha1.VolumeMapping{
+		Source:      replicationv1alpha1.VolumeSource{PvcName: "data-pvc", Namespace: "default"},
+		Destination: replicationv1alpha1.VolumeDestination{VolumeHandle: "data-dest", Namespace: "default"},
+	}
+
+	assert.False(t, adapter.MembershipChangeRequiresPause())
+	require.NoError(t, adapter.AddGroupMember(ctx, uvr, member))
+	require.NoError(t, adapter.AddGroupMember(ctx, uvr, member), "adding twice is a no-op")
+	assert.Equal(t, []string{"source-pvc", "data-pvc"}, localPVCs())
+
+	// Spec updates of the relationship keep the group members
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateSource
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+	assert.Equal(t, []string{"source-pvc", "data-pvc"}, localPVCs())
+
+	require.NoError(t, adapter.RemoveGroupMember(ctx, uvr, member))
+	assert.Equal(t, []string{"source-pvc"}, localPVCs())
+}
+
+func TestTridentAdapter_MemberSyncStatus(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientBuilder().Build()
+	adapter, err := NewTridentAdapter(client, translation.NewEngine())
+	require.NoError(t, err)
+
+	uvr := createTestUVRForTrident("test-members", "default")
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+
+	tmr, err := adapter.getTridentMirrorRelationship(ctx, uvr, "test")
+	require.NoError(t, err)
+	require.NoError(t, unstructured.SetNestedField(tmr.Object, map[string]interface{}{
+		"state":            "established",
+		"lastTransferTime": "2024-05-01T10:00:00Z",
+		"volumeMappings": []interface{}{
+			map[string]interface{}{"localPVCName": "source-pvc", "lastTransferTime": "2024-05-01T10:00:00Z"},
+			map[string]interface{}{"localPVCName": "data-pvc"},
+		},
+	}, "status"))
+	require.NoError(t, client.Update(ctx, tmr))
+
+	status, err := adapter.GetReplicationStatus(ctx, uvr)
+	require.NoError(t, err)
+	require.Len(t, status.Members, 2)
+	assert.Equal(t, "source-pvc", status.Members[0].PvcName)
+	require.NotNil(t, status.Members[0].LastSyncTime)
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), status.Members[0].LastSyncTime.UTC())
+	assert.Equal(t, "data-pvc", status.Members[1].PvcName)
+	assert.Nil(t, status.Members[1].LastSyncTime, "member has not transferred yet")
+}
+
+func TestTridentAdapter_RecreationRequired(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientBuilder().Build()
+	adapter, err := NewTridentAdapter(client, translation.NewEngine())
+	require.NoError(t, err)
+
+	uvr := createTestUVRForTrident("test-recreate", "default")
+	changes, err := adapter.RecreationRequired(ctx, uvr)
+	require.NoError(t, err)
+	assert.Empty(t, changes, "nothing to recreate before the relationship exists")
+
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+	exists, err := adapter.BackendResourceExists(ctx, uvr)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	changes, err = adapter.RecreationRequired(ctx, uvr)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	// Neither the SnapMirror policy type nor the destination volume can change in place
+	desired := uvr.DeepCopy()
+	desired.Spec.ReplicationMode = replicationv1alpha1.ReplicationModeSynchronous
+	desired.Spec.VolumeMapping.Destination.VolumeHandle = "other-volume"
+	changes, err = adapter.RecreationRequired(ctx, desired)
+	require.NoError(t, err)
+	assert.Equal(t, []PolicyDrift{
+		{Field: "replicationPolicy", Desired: "Sync", Observed: "Async"},
+		{Field: "remoteVolumeHandle", Desired: "other-volume", Observed: uvr.Spec.VolumeMapping.Destination.VolumeHandle},
+	}, changes)
+
+	require.NoError(t, adapter.DeleteReplication(ctx, uvr))
+	exists, err = adapter.BackendResourceExists(ctx, uvr)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestSnapMirrorSchedule(t *testing.T) {
+	tests := []struct {
+		rpo      string
+		schedule string
+	}{
+		{rpo: "5m", schedule: "*/5 * * * *"},
+		{rpo: "15m", schedule: "*/15 * * * *"},
+		{rpo: "60m", schedule: "0 * * * *"},
+		{rpo: "1h", schedule: "0 * * * *"},
+		{rpo: "6h", schedule: "0 */6 * * *"},
+		{rpo: "1d", schedule: "0 0 * * *"},
+		{rpo: "1h60m", schedule: "0 */2 * * *"},
+		{rpo: "0d30m", schedule: "*/30 * * * *"},
+		{rpo: "45m"},
+		{rpo: "1h30m"},
+		{rpo: "5m30s"},
+		{rpo: "5h"},
+		{rpo: "2d"},
+		{rpo: "30s"},
+		{rpo: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rpo, func(t *testing.T) {
+			schedule, err := SnapMirrorSchedule(tt.rpo)
+			if tt.schedule == "" {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.schedule, schedule)
+		})
+	}
+}
+
+func TestTridentAdapter_DelegatedSchedule(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientBuilder().Build()
+	adapter, err := NewTridentAdapter(client, translation.NewEngine())
+	require.NoError(t, err)
+
+	replicationSchedule := func(uvr *replicationv1alpha1.UnifiedVolumeReplication) string {
+		tmr, err := adapter.getTridentMirrorRelationship(ctx, uvr, "test")
+		require.NoError(t, err)
+		schedule, _, _ := unstructured.NestedString(tmr.Object, "spec", "replicationSchedule")
+		return schedule
+	}
+
+	uvr := createTestUVRForTrident("test-delegated", "default")
+	uvr.Spec.Schedule.Mode = replicationv1alpha1.ScheduleModeInterval
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+	assert.Equal(t, "15m", replicationSchedule(uvr), "the operator's interval without delegation")
+
+	uvr.Spec.Schedule.Delegate = true
+	require.NoError(t, adapter.ConfigureNativeSchedule(ctx, uvr))
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+	assert.Equal(t, "*/15 * * * *", replicationSchedule(uvr))
+
+	uvr.Spec.Schedule.Rpo = "45m"
+	assert.Error(t, adapter.ConfigureNativeSchedule(ctx, uvr), "45m does not divide the hour")
+
+	// Without delegation compound RPOs are written in a single unit
+	uvr.Spec.Schedule.Delegate = false
+	uvr.Spec.Schedule.Rpo = "1h30m"
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+	assert.Equal(t, "90m", replicationSchedule(uvr))
+
+	uvr.Spec.Schedule.Rpo = "5m30s"
+	err = adapter.EnsureReplication(ctx, uvr)
+	require.Error(t, err, "ONTAP cannot schedule sub-minute intervals")
+	assert.Equal(t, "90m", replicationSchedule(uvr))
+}
+
+func TestTridentAdapter_VerifyReplicaReadable(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientBuilder().Build()
+	adapter, err := NewTridentAdapter(client, translation.NewEngine())
+	require.NoError(t, err)
+	assert.Contains(t, adapter.GetSupportedFeatures(), FeatureReplicaRead)
+
+	uvr := createTestUVRForTrident("test-readable", "default")
+	uvr.Spec.ReplicationState = replicationv1alpha1.ReplicationStateReplica
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+
+	setStatus := func(status map[string]interface{}) {
+		tmr, err := adapter.getTridentMirrorRelationship(ctx, uvr, "test")
+		require.NoError(t, err)
+		require.NoError(t, unstructured.SetNestedField(tmr.Object, status, "status"))
+		require.NoError(t, client.Update(ctx, tmr))
+	}
+
+	readable, reason, err := adapter.VerifyReplicaReadable(ctx, uvr)
+	require.NoError(t, err)
+	assert.False(t, readable, "baseline transfer has not completed")
+	assert.Contains(t, reason, "baseline")
+
+	setStatus(map[string]interface{}{"state": "established"})
+	readable, _, err = adapter.VerifyReplicaReadable(ctx, uvr)
+	require.NoError(t, err)
+	assert.True(t, readable)
+
+	setStatus(map[string]interface{}{
+		"state": "established",
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "Error", "status": "True", "message": "destination volume offline"},
+		},
+	})
+	readable, reason, err = adapter.VerifyReplicaReadable(ctx, uvr)
+	require.NoError(t, err)
+	assert.False(t, readable)
+	assert.Contains(t, reason, "destination volume offline")
+}
+
+func TestTridentAdapter_ConsistencyState(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientBuilder().Build()
+	adapter, err := NewTridentAdapter(client, translation.NewEngine())
+	require.NoError(t, err)
+
+	uvr := createTestUVRForTrident("test-consistency", "default")
+	require.NoError(t, adapter.EnsureReplication(ctx, uvr))
+
+	transfer := func(at string, quiesced bool) {
+		tmr, err := adapter.getTridentMirrorRelationship(ctx, uvr, "test")
+		require.NoError(t, err)
+		require.NoError(t, unstructured.SetNestedField(tmr.Object, map[string]interface{}{
+			"state":            "established",
+			"lastTransferTime": at,
+		}, "status"))
+		if quiesced {
+			tmr.SetAnnotations(map[string]string{QuiescedSyncTimeAnnotation: at})
+		}
+		require.NoError(t, client.Update(ctx, tmr))
+	}
+
+	// A scheduled transfer without quiescing the application
+	transfer("2024-05-01T10:00:00Z", false)
+	level, err := adapter.GetConsistencyState(ctx, uvr)
+	require.NoError(t, err)
+	assert.Equal(t, ConsistencyLevelCrash, level)
+
+	// A quiesce hook froze the application for the next transfer
+	transfer("2024-05-01T10:15:00Z", true)
+	level, err = adapter.GetConsistencyState(ctx, uvr)
+	require.NoError(t, err)
+	assert.Equal(t, ConsistencyLevelApplication, level)
+	status, err := adapter.GetReplicationStatus(ctx, uvr)
+	require.NoError(t, err)
+	assert.Equal(t, ConsistencyLevelApplication, status.ConsistencyLevel)
+
+	// A later transfer supersedes the quiesced sync point
+	transfer("2024-05-01T10:30:00Z", false)
+	level, err = adapter.GetConsistencyState(ctx, uvr)
+	require.NoError(t, err)
+	assert.Equal(t, ConsistencyLevelCrash, level)
+}
@@ -18,6 +18,8 @@ package adapters
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
@@ -31,6 +33,10 @@ type ReplicationAdapter interface {
 	DeleteReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error
 	GetReplicationStatus(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (*ReplicationStatus, error)
 
+	// GetConsistencyState reports whether the latest recovery point is crash-consistent
+	// or application-consistent
+	GetConsistencyState(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (ConsistencyLevel, error)
+
 	// Configuration and validation
 	ValidateConfiguration(uvr *replicationv1alpha1.UnifiedVolumeReplication) error
 	SupportsConfiguration(uvr *replicationv1alpha1.UnifiedVolumeReplication) (bool, error)
@@ -58,18 +64,202 @@ type ReplicationAdapter interface {
 	Reconcile(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error
 }
 
+// PolicyDriftDetector is implemented by adapters that can compare the backend's
+// effective replication class/policy against the settings requested by the UVR
+type PolicyDriftDetector interface {
+	DetectPolicyDrift(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) ([]PolicyDrift, error)
+}
+
+// CapacityReserver is implemented by adapters that reserve destination capacity ahead of
+// the initial sync, so concurrent replications cannot collectively overcommit the destination
+type CapacityReserver interface {
+	GetCapacityInfo(ctx context.Context, storageClass string) (*CapacityInfo, error)
+	ReservedCapacity(uvr *replicationv1alpha1.UnifiedVolumeReplication) (int64, bool)
+	ReleaseCapacity(uvr *replicationv1alpha1.UnifiedVolumeReplication)
+	DestinationVolumeBound(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (bool, error)
+}
+
+// GroupMembershipManager is implemented by adapters that replicate several volumes as one
+// consistency group and can add or remove members without recreating the group
+type GroupMembershipManager interface {
+	AddGroupMember(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, member replicationv1alpha1.VolumeMapping) error
+	RemoveGroupMember(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, member replicationv1alpha1.VolumeMapping) error
+
+	// MembershipChangeRequiresPause reports whether the group must be paused while its
+	// membership changes
+	MembershipChangeRequiresPause() bool
+}
+
+// ReplicaReadabilityVerifier is implemented by adapters that can tell whether the replica
+// volume can be mounted read-only while replication continues
+type ReplicaReadabilityVerifier interface {
+	// VerifyReplicaReadable returns whether the replica is readable and the reason for the result
+	VerifyReplicaReadable(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (bool, string, error)
+}
+
+// EffectiveConfigReporter is implemented by adapters that apply settings of their own
+// choosing, so the operator can report them alongside the requested configuration
+type EffectiveConfigReporter interface {
+	// EffectiveBackendConfig returns the replication class in use, if any, and other
+	// backend-specific settings applied for the UVR
+	EffectiveBackendConfig(uvr *replicationv1alpha1.UnifiedVolumeReplication) (string, map[string]string)
+}
+
+// RecreationDetector is implemented by adapters whose backend resource has settings that
+// cannot be changed in place, so a spec change to them means deleting and recreating it
+type RecreationDetector interface {
+	// RecreationRequired returns the settings of the existing backend resource that differ
+	// from the UVR and cannot be updated in place. It returns none when the resource does
+	// not exist yet.
+	RecreationRequired(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) ([]PolicyDrift, error)
+
+	// BackendResourceExists reports whether the backend resource for the UVR still exists
+	BackendResourceExists(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (bool, error)
+
+	// DeleteBackendResource deletes only the replication relationship so it can be created
+	// again. Unlike DeleteReplication it keeps the destination volume, the baseline snapshot
+	// and any capacity reservation.
+	DeleteBackendResource(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error
+}
+
+// BackendLimitsReporter is implemented by adapters whose backend caps the number of
+// replication relationships it can hold
+type BackendLimitsReporter interface {
+	// GetBackendLimits reports the backend's usage, and whether the UVR's backend resource
+	// is already part of it
+	GetBackendLimits(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (*BackendLimits, error)
+}
+
+// NativeScheduler is implemented by adapters whose backend can run the sync schedule
+// itself, so the operator does not need to drive syncs
+type NativeScheduler interface {
+	// ConfigureNativeSchedule prepares or checks the backend schedule for the UVR's RPO. It
+	// is called before EnsureReplication and must not require recreating the backend
+	// resource, so the schedule can be delegated and changed in place.
+	ConfigureNativeSchedule(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error
+}
+
+// ReplicationAdopter is implemented by adapters that can take over a backend resource created
+// outside the operator, named by the AdoptAnnotation, instead of creating their own
+type ReplicationAdopter interface {
+	// AdoptReplication validates that the named backend resource matches the UVR spec and
+	// marks it as managed by the UVR. It returns an error satisfying IsAdoptionConflictError
+	// when the resource belongs to another UVR, or does not match the spec and the UVR's
+	// AdoptConflictPolicy is Fail. Under the other policies the resource is adopted and the
+	// settings that differ are returned.
+	AdoptReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) ([]PolicyDrift, error)
+}
+
+// WriteDrainer is implemented by adapters that can stop writes on a source and flush them to
+// the replica, so a planned demotion does not lose writes that were still in flight
+type WriteDrainer interface {
+	// DrainWrites fences writes on the source and flushes pending data to the replica. It is
+	// called on every reconcile until it reports that the replica has caught up, after which
+	// the source is demoted.
+	DrainWrites(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) (bool, error)
+}
+
+// ReplicationDeactivator is implemented by adapters that can leave a replication dormant:
+// syncs stop but the backend resource and its relationship are kept, so the replication can
+// be reactivated without a new baseline
+type ReplicationDeactivator interface {
+	// DeactivateReplication stops the replication's syncs and marks its backend resource with
+	// DeactivatedAnnotation. It does nothing when the backend resource does not exist.
+	DeactivateReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error
+
+	// ReactivateReplication undoes DeactivateReplication. EnsureReplication then restores
+	// any setting the deactivation cleared.
+	ReactivateReplication(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication) error
+}
+
+// BandwidthLimiter is implemented by adapters whose backend can cap the bandwidth of a
+// replication's syncs
+type BandwidthLimiter interface {
+	// SetBandwidthLimit caps the syncs of the replication at bitsPerSecond, or removes the
+	// cap when it is 0. It does nothing when the backend resource does not exist.
+	SetBandwidthLimit(ctx context.Context, uvr *replicationv1alpha1.UnifiedVolumeReplication, bitsPerSecond int64) error
+}
+
+// BackendLimits describes the replication relationships a backend holds against its cap
+type BackendLimits struct {
+	// MaxReplications is the number of replication relationships the backend allows
+	MaxReplications int64 `json:"max_replications"`
+	// Replications is the number of replication relationships currently on the backend
+	Replications int64 `json:"replications"`
+	// Counted is set when the UVR's own replication relationship already exists and is
+	// included in Replications
+	Counted bool `json:"counted,omitempty"`
+}
+
+// Exhausted returns true when no further replication relationship can be created
+func (bl *BackendLimits) Exhausted() bool {
+	return bl.Replications >= bl.MaxReplications
+}
+
+// PolicyDrift describes a single setting where the backend policy diverges from the UVR
+type PolicyDrift struct {
+	Field    string `json:"field"`
+	Desired  string `json:"desired"`
+	Observed string `json:"observed"`
+}
+
+// String returns a human readable description of the drift
+func (pd PolicyDrift) String() string {
+	return fmt.Sprintf("%s: desired %q, observed %q", pd.Field, pd.Desired, pd.Observed)
+}
+
 // ReplicationStatus represents the status of a replication relationship
 type ReplicationStatus struct {
 	State              string                 `json:"state"`
 	Mode               string                 `json:"mode"`
 	Health             ReplicationHealth      `json:"health"`
 	LastSyncTime       *time.Time             `json:"last_sync_time,omitempty"`
+	LastSyncDuration   *time.Duration         `json:"last_sync_duration,omitempty"`
 	NextSyncTime       *time.Time             `json:"next_sync_time,omitempty"`
 	SyncProgress       *SyncProgress          `json:"sync_progress,omitempty"`
 	BackendSpecific    map[string]interface{} `json:"backend_specific,omitempty"`
 	Message            string                 `json:"message,omitempty"`
 	ObservedGeneration int64                  `json:"observed_generation"`
 	Conditions         []StatusCondition      `json:"conditions,omitempty"`
+
+	// PrimaryCluster and PrimarySite identify where the primary copy currently lives
+	PrimaryCluster string `json:"primary_cluster,omitempty"`
+	PrimarySite    string `json:"primary_site,omitempty"`
+
+	// Direction reports whether replication flows the configured way or has been reversed
+	Direction ReplicationDirection `json:"direction,omitempty"`
+
+	// DualPrimary is set when both endpoints claim the primary role (split-brain)
+	DualPrimary bool `json:"dual_primary,omitempty"`
+
+	// DegradedReason explains a Degraded or Unhealthy health in machine-readable form
+	DegradedReason DegradedReason `json:"degraded_reason,omitempty"`
+
+	// Members reports the sync state of each volume of a replication group, including the
+	// primary volume. Empty when the backend does not report per-volume status.
+	Members []MemberSyncStatus `json:"members,omitempty"`
+
+	// ConsistencyLevel reports what the latest recovery point would recover to
+	ConsistencyLevel ConsistencyLevel `json:"consistency_level,omitempty"`
+
+	// RPOCompliance is the percentage of recent syncs that met the RPO, for backends that
+	// report one. The controller derives a compliance from LastSyncTime for the others.
+	RPOCompliance *float64 `json:"rpo_compliance,omitempty"`
+
+	// RPOCompliancePercent is how well the last sync meets the configured RPO: 100 while it
+	// is within the RPO, falling as the last sync ages past it (50 at twice the RPO). Set by
+	// the controller from LastSyncTime, unlike the backend-reported RPOCompliance.
+	RPOCompliancePercent *float64 `json:"rpo_compliance_percent,omitempty"`
+
+	// RPOViolated is set by the controller when the last sync is older than the configured RPO
+	RPOViolated bool `json:"rpo_violated,omitempty"`
+}
+
+// MemberSyncStatus is the sync state of one volume in a replication group
+type MemberSyncStatus struct {
+	// PvcName is the source PVC of the member
+	PvcName      string     `json:"pvc_name"`
+	LastSyncTime *time.Time `json:"last_sync_time,omitempty"`
 }
 
 // ReplicationHealth represents the health of a replication relationship
@@ -82,6 +272,63 @@ const (
 	ReplicationHealthUnknown   ReplicationHealth = "Unknown"
 )
 
+// DegradedReason categorizes why a replication is not healthy, so alerts can target
+// specific causes instead of matching on free-form messages
+type DegradedReason string
+
+const (
+	// DegradedReasonSyncLagExceeded indicates the last sync is older than the configured RPO
+	DegradedReasonSyncLagExceeded DegradedReason = "SyncLagExceeded"
+	// DegradedReasonRPOComplianceLow indicates the RPO compliance, reported by the backend or
+	// derived from the last sync, is below the configured threshold
+	DegradedReasonRPOComplianceLow DegradedReason = "RPOComplianceLow"
+	// DegradedReasonBackendUnreachable indicates the backend could not be reached to read status
+	DegradedReasonBackendUnreachable DegradedReason = "BackendUnreachable"
+	// DegradedReasonInvalidTransition indicates a requested state transition is not allowed
+	DegradedReasonInvalidTransition DegradedReason = "InvalidTransition"
+	// DegradedReasonSessionFailure indicates the backend replication session or link has failed
+	DegradedReasonSessionFailure DegradedReason = "SessionFailure"
+	// DegradedReasonResyncing indicates the replica is catching up and not yet in sync
+	DegradedReasonResyncing DegradedReason = "Resyncing"
+	// DegradedReasonBackendDegraded indicates the backend reports the replication as degraded or not ready
+	DegradedReasonBackendDegraded DegradedReason = "BackendDegraded"
+	// DegradedReasonBackendError indicates the backend reports the replication in an error state
+	DegradedReasonBackendError DegradedReason = "BackendError"
+	// DegradedReasonMirrorUnhealthy indicates rbd-mirror reports the peer disconnected, or its
+	// daemons or images unhealthy
+	DegradedReasonMirrorUnhealthy DegradedReason = "MirrorUnhealthy"
+	// DegradedReasonUnknownState indicates the backend reports a state its adapter cannot map
+	// to a health
+	DegradedReasonUnknownState DegradedReason = "UnknownState"
+	// DegradedReasonUnknown indicates the replication is not healthy for an unclassified reason
+	DegradedReasonUnknown DegradedReason = "Unknown"
+	// DegradedReasonSimulated indicates the degradation was injected for a DR rehearsal and
+	// the replication itself is unaffected
+	DegradedReasonSimulated DegradedReason = "Simulated"
+)
+
+// ReplicationDirection represents which way data flows between the endpoints
+type ReplicationDirection string
+
+const (
+	ReplicationDirectionForward ReplicationDirection = "Forward"
+	ReplicationDirectionReverse ReplicationDirection = "Reverse"
+)
+
+// ConsistencyLevel describes the consistency of the latest recovery point
+type ConsistencyLevel string
+
+const (
+	// ConsistencyLevelApplication indicates the latest sync was taken while the application
+	// was quiesced
+	ConsistencyLevelApplication ConsistencyLevel = "ApplicationConsistent"
+	// ConsistencyLevelCrash indicates the latest sync holds the data as of a point in time,
+	// like after a power loss
+	ConsistencyLevelCrash ConsistencyLevel = "CrashConsistent"
+	// ConsistencyLevelUnknown indicates there is no recovery point yet or the backend cannot tell
+	ConsistencyLevelUnknown ConsistencyLevel = "Unknown"
+)
+
 // SyncProgress represents the progress of synchronization
 type SyncProgress struct {
 	TotalBytes      int64   `json:"total_bytes"`
@@ -118,12 +365,14 @@ const (
 	FeaturePauseResume AdapterFeature = "PauseResume"
 
 	// Advanced features
-	FeatureSnapshotBased     AdapterFeature = "SnapshotBased"
-	FeatureJournalBased      AdapterFeature = "JournalBased"
-	FeatureConsistencyGroups AdapterFeature = "ConsistencyGroups"
-	FeatureVolumeGroups      AdapterFeature = "VolumeGroups"
-	FeatureAutoResync        AdapterFeature = "AutoResync"
-	FeatureScheduledSync     AdapterFeature = "ScheduledSync"
+	FeatureSnapshotBased      AdapterFeature = "SnapshotBased"
+	FeatureJournalBased       AdapterFeature = "JournalBased"
+	FeatureConsistencyGroups  AdapterFeature = "ConsistencyGroups"
+	FeatureVolumeGroups       AdapterFeature = "VolumeGroups"
+	FeatureAutoResync         AdapterFeature = "AutoResync"
+	FeatureScheduledSync      AdapterFeature = "ScheduledSync"
+	FeatureVolumeProvisioning AdapterFeature = "VolumeProvisioning"
+	FeatureReplicaRead        AdapterFeature = "ReplicaRead"
 
 	// Performance features
 	FeatureHighThroughput AdapterFeature = "HighThroughput"
@@ -147,6 +396,16 @@ type AdapterConfig struct {
 	HealthCheckInterval time.Duration          `json:"health_check_interval"`
 	MetricsEnabled      bool                   `json:"metrics_enabled"`
 	CustomSettings      map[string]interface{} `json:"custom_settings,omitempty"`
+
+	// Status cache tuning; zero values fall back to the adapter defaults
+	StatusCacheTTL     time.Duration `json:"status_cache_ttl,omitempty"`
+	StatusCacheMaxSize int           `json:"status_cache_max_size,omitempty"`
+
+	// Generic CSI adapter settings: the VolumeReplicationClass VolumeReplications are created
+	// with, and the driver's VolumeReplication state for each unified state. An empty state
+	// map falls back to DefaultGenericCSIStateMap.
+	VolumeReplicationClass string            `json:"volume_replication_class,omitempty"`
+	StateMap               map[string]string `json:"state_map,omitempty"`
 }
 
 // DefaultAdapterConfig returns the default configuration for adapters
@@ -160,6 +419,8 @@ func DefaultAdapterConfig(backend translation.Backend) *AdapterConfig {
 		HealthCheckInterval: 1 * time.Minute,
 		MetricsEnabled:      false,
 		CustomSettings:      make(map[string]interface{}),
+		StatusCacheTTL:      StatusCacheTTL,
+		StatusCacheMaxSize:  StatusCacheMaxSize,
 	}
 }
 
@@ -270,6 +531,31 @@ func GetAdapterError(err error) (*AdapterError, bool) {
 	return ae, ok
 }
 
+// degradedReasonForHealth returns the generic reason for a health reported by the backend
+// without further detail
+func degradedReasonForHealth(health ReplicationHealth) DegradedReason {
+	switch health {
+	case ReplicationHealthDegraded:
+		return DegradedReasonBackendDegraded
+	case ReplicationHealthUnhealthy:
+		return DegradedReasonBackendError
+	default:
+		return ""
+	}
+}
+
+// DegradedReasonForError classifies a failure to read replication status
+func DegradedReasonForError(err error) DegradedReason {
+	var adapterErr *AdapterError
+	if errors.As(err, &adapterErr) {
+		switch adapterErr.Type {
+		case ErrorTypeConnection, ErrorTypeTimeout:
+			return DegradedReasonBackendUnreachable
+		}
+	}
+	return DegradedReasonUnknown
+}
+
 // AdapterMetrics contains metrics for adapter operations
 type AdapterMetrics struct {
 	TotalOperations     int64         `json:"total_operations"`
@@ -318,6 +604,7 @@ const (
 	EventTypeDeleted    ReplicationEventType = "Deleted"
 	EventTypePromoted   ReplicationEventType = "Promoted"
 	EventTypeDemoted    ReplicationEventType = "Demoted"
+	EventTypeDrained    ReplicationEventType = "WritesDrained"
 	EventTypeResynced   ReplicationEventType = "Resynced"
 	EventTypePaused     ReplicationEventType = "Paused"
 	EventTypeResumed    ReplicationEventType = "Resumed"
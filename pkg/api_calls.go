@@ -0,0 +1,186 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkg
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// APICalls counts the API requests made through a counting client with a context returned
+// by WithAPICallCounting, by verb. Reads served from the informer cache are counted too, as
+// the point is to find chatty code paths.
+type APICalls struct {
+	mu    sync.Mutex
+	verbs map[string]int
+}
+
+type apiCallsKey struct{}
+
+// WithAPICallCounting returns a context whose API requests are counted in the returned
+// APICalls, typically one per reconcile
+func WithAPICallCounting(ctx context.Context) (context.Context, *APICalls) {
+	calls := &APICalls{verbs: map[string]int{}}
+	return context.WithValue(ctx, apiCallsKey{}, calls), calls
+}
+
+// record counts a request; it is a no-op on a nil APICalls
+func (c *APICalls) record(verb string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.verbs[verb]++
+}
+
+// Total returns the number of requests counted
+func (c *APICalls) Total() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	total := 0
+	for _, count := range c.verbs {
+		total += count
+	}
+	return total
+}
+
+// ByVerb returns the number of requests counted per verb, such as get or status-update
+func (c *APICalls) ByVerb() map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	verbs := make(map[string]int, len(c.verbs))
+	for verb, count := range c.verbs {
+		verbs[verb] = count
+	}
+	return verbs
+}
+
+// recordAPICall counts a request against the APICalls of ctx, if any
+func recordAPICall(ctx context.Context, verb string) {
+	calls, _ := ctx.Value(apiCallsKey{}).(*APICalls)
+	calls.record(verb)
+}
+
+// CountingClient returns c with every request counted against the APICalls of the
+// request's context. Requests made with other contexts are passed through uncounted.
+func CountingClient(c client.Client) client.Client {
+	return &countingClient{Client: c}
+}
+
+// countingClient counts every request against the APICalls of its context
+type countingClient struct {
+	client.Client
+}
+
+func (c *countingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	recordAPICall(ctx, "get")
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *countingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	recordAPICall(ctx, "list")
+	return c.Client.List(ctx, list, opts...)
+}
+
+func (c *countingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	recordAPICall(ctx, "create")
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *countingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	recordAPICall(ctx, "update")
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *countingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	recordAPICall(ctx, "patch")
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *countingClient) Apply(ctx context.Context, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+	recordAPICall(ctx, "apply")
+	return c.Client.Apply(ctx, obj, opts...)
+}
+
+func (c *countingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	recordAPICall(ctx, "delete")
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *countingClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	recordAPICall(ctx, "deletecollection")
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *countingClient) Status() client.SubResourceWriter {
+	return &countingSubResourceWriter{SubResourceWriter: c.Client.Status(), subResource: "status"}
+}
+
+func (c *countingClient) SubResource(subResource string) client.SubResourceClient {
+	return &countingSubResourceClient{SubResourceClient: c.Client.SubResource(subResource), subResource: subResource}
+}
+
+// countingSubResourceWriter counts status writes, with verbs such as status-update
+type countingSubResourceWriter struct {
+	client.SubResourceWriter
+	subResource string
+}
+
+func (w *countingSubResourceWriter) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	recordAPICall(ctx, w.subResource+"-create")
+	return w.SubResourceWriter.Create(ctx, obj, subResource, opts...)
+}
+
+func (w *countingSubResourceWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	recordAPICall(ctx, w.subResource+"-update")
+	return w.SubResourceWriter.Update(ctx, obj, opts...)
+}
+
+func (w *countingSubResourceWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	recordAPICall(ctx, w.subResource+"-patch")
+	return w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+}
+
+// countingSubResourceClient counts subresource requests
+type countingSubResourceClient struct {
+	client.SubResourceClient
+	subResource string
+}
+
+func (c *countingSubResourceClient) Get(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceGetOption) error {
+	recordAPICall(ctx, c.subResource+"-get")
+	return c.SubResourceClient.Get(ctx, obj, subResource, opts...)
+}
+
+func (c *countingSubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	recordAPICall(ctx, c.subResource+"-create")
+	return c.SubResourceClient.Create(ctx, obj, subResource, opts...)
+}
+
+func (c *countingSubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	recordAPICall(ctx, c.subResource+"-update")
+	return c.SubResourceClient.Update(ctx, obj, opts...)
+}
+
+func (c *countingSubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	recordAPICall(ctx, c.subResource+"-patch")
+	return c.SubResourceClient.Patch(ctx, obj, patch, opts...)
+}
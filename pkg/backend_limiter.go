@@ -0,0 +1,64 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"golang.org/x/sync/semaphore"
+
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+// DefaultMaxConcurrentBackendOperations is how many adapter calls may run against one
+// backend at a time by default
+const DefaultMaxConcurrentBackendOperations = 5
+
+// backendLimiter caps the adapter calls running against each backend at a time, so
+// reconciles of many UVRs on the same backend cannot overwhelm it. Backends are limited
+// independently: a slow backend does not hold up the others.
+type backendLimiter struct {
+	mu    sync.Mutex
+	limit int64
+	slots map[translation.Backend]*semaphore.Weighted
+}
+
+func newBackendLimiter(limit int) *backendLimiter {
+	return &backendLimiter{limit: int64(limit), slots: make(map[translation.Backend]*semaphore.Weighted)}
+}
+
+// acquire waits for a free slot of backend and returns the function releasing it. It
+// returns the context's error when ctx ends first. A nil limiter never waits.
+func (l *backendLimiter) acquire(ctx context.Context, backend translation.Backend) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	l.mu.Lock()
+	slots, ok := l.slots[backend]
+	if !ok {
+		slots = semaphore.NewWeighted(l.limit)
+		l.slots[backend] = slots
+	}
+	l.mu.Unlock()
+
+	if err := slots.Acquire(ctx, 1); err != nil {
+		return nil, fmt.Errorf("waiting for a free %s backend slot: %w", backend, err)
+	}
+	return func() { slots.Release(1) }, nil
+}
@@ -20,9 +20,11 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
@@ -44,12 +46,28 @@ type ControllerEngine struct {
 	cacheExpiry         time.Duration
 	lastDiscoveryTime   time.Time
 
+	// State-changing backend operations, serialized per UVR
+	operations *operationQueue
+
+	// Recently completed operations, to skip identical repeats; nil when disabled
+	dedupe *operationDedupe
+
+	// Global cap on API writes, nil when unlimited
+	writes *writeBudget
+
+	// Per-backend cap on concurrent adapter calls, nil when unlimited
+	backendSlots *backendLimiter
+
+	// Handed to the adapters it creates, nil when they record no events
+	recorder record.EventRecorder
+
 	// Configuration
 	enableCaching   bool
 	batchOperations bool
 
 	// Metrics
 	operationCount int64
+	dedupedCount   int64
 	cacheHits      int64
 	cacheMisses    int64
 }
@@ -60,6 +78,20 @@ type ControllerEngineConfig struct {
 	CacheExpiry       time.Duration
 	BatchOperations   bool
 	DiscoveryInterval time.Duration
+
+	// WritesPerSecond caps the API writes of all reconciles together; 0 disables the cap.
+	// WriteBurst is how many writes may be made at once, defaulting to one second's worth.
+	WritesPerSecond float64
+	WriteBurst      int
+
+	// OperationDedupeWindow is how long a completed backend operation is remembered: an
+	// identical operation for the same UVR within it is skipped. 0 disables deduplication.
+	OperationDedupeWindow time.Duration
+
+	// MaxConcurrentBackendOperations caps the adapter calls EnsureReplication and
+	// GetReplicationStatus make against each backend at a time; further calls wait for a
+	// free slot. 0 disables the cap.
+	MaxConcurrentBackendOperations int
 }
 
 // DefaultControllerEngineConfig returns default configuration
@@ -69,6 +101,9 @@ func DefaultControllerEngineConfig() *ControllerEngineConfig {
 		CacheExpiry:       5 * time.Minute,
 		BatchOperations:   false, // Enable in future for optimization
 		DiscoveryInterval: 1 * time.Minute,
+
+		OperationDedupeWindow:          5 * time.Second,
+		MaxConcurrentBackendOperations: DefaultMaxConcurrentBackendOperations,
 	}
 }
 
@@ -84,18 +119,42 @@ func NewControllerEngine(
 		config = DefaultControllerEngineConfig()
 	}
 
+	var writes *writeBudget
+	if config.WritesPerSecond > 0 {
+		writes = newWriteBudget(config.WritesPerSecond, config.WriteBurst)
+		client = &budgetedClient{Client: client, budget: writes}
+	}
+	var dedupe *operationDedupe
+	if config.OperationDedupeWindow > 0 {
+		dedupe = newOperationDedupe(config.OperationDedupeWindow)
+	}
+	var backendSlots *backendLimiter
+	if config.MaxConcurrentBackendOperations > 0 {
+		backendSlots = newBackendLimiter(config.MaxConcurrentBackendOperations)
+	}
+
 	return &ControllerEngine{
 		client:            client,
 		discoveryEngine:   discoveryEngine,
 		translationEngine: translationEngine,
 		adapterRegistry:   adapterRegistry,
 		discoveryCache:    make(map[string]*discovery.DiscoveryResult),
+		operations:        newOperationQueue(),
+		dedupe:            dedupe,
+		writes:            writes,
+		backendSlots:      backendSlots,
 		enableCaching:     config.EnableCaching,
 		cacheExpiry:       config.CacheExpiry,
 		batchOperations:   config.BatchOperations,
 	}
 }
 
+// SetEventRecorder sets the recorder handed to the adapters the engine creates, so those
+// implementing adapters.EventRecorderSetter record events on the UVRs they operate on
+func (ce *ControllerEngine) SetEventRecorder(recorder record.EventRecorder) {
+	ce.recorder = recorder
+}
+
 // EnsureReplication executes the complete workflow for a replication
 // Discovery → Validation → Translation → Adapter Selection → Backend Operation
 // This method is idempotent and ensures the backend is in the desired state
@@ -107,7 +166,7 @@ func (ce *ControllerEngine) EnsureReplication(
 	log.Info("Ensuring replication is in desired state",
 		"backend_hint", ce.getBackendHint(uvr))
 
-	ce.operationCount++
+	atomic.AddInt64(&ce.operationCount, 1)
 
 	// Step 1: Discovery - Find available backends
 	backends, err := ce.discoverBackends(ctx, log)
@@ -148,7 +207,14 @@ func (ce *ControllerEngine) EnsureReplication(
 	}
 
 	// Step 6: Backend Operation - Ensure replication is in desired state
-	if err := adapter.EnsureReplication(ctx, uvr); err != nil {
+	if err := ce.RunOperation(ctx, uvr, "ensure", log, func(ctx context.Context) error {
+		release, err := ce.backendSlots.acquire(ctx, selectedBackend)
+		if err != nil {
+			return err
+		}
+		defer release()
+		return adapter.EnsureReplication(ctx, uvr)
+	}); err != nil {
 		return fmt.Errorf("ensure replication failed: %w", err)
 	}
 
@@ -159,10 +225,10 @@ func (ce *ControllerEngine) EnsureReplication(
 // discoverBackends discovers available backends in the cluster
 func (ce *ControllerEngine) discoverBackends(ctx context.Context, log logr.Logger) ([]translation.Backend, error) {
 	// Check cache first
-	if ce.enableCaching && time.Since(ce.lastDiscoveryTime) < ce.cacheExpiry {
+	if ce.enableCaching {
 		ce.discoveryCacheMutex.RLock()
-		if len(ce.discoveryCache) > 0 {
-			ce.cacheHits++
+		if time.Since(ce.lastDiscoveryTime) < ce.cacheExpiry && len(ce.discoveryCache) > 0 {
+			atomic.AddInt64(&ce.cacheHits, 1)
 			backends := make([]translation.Backend, 0, len(ce.discoveryCache))
 			for backend := range ce.discoveryCache {
 				backends = append(backends, translation.Backend(backend))
@@ -174,13 +240,14 @@ func (ce *ControllerEngine) discoverBackends(ctx context.Context, log logr.Logge
 		ce.discoveryCacheMutex.RUnlock()
 	}
 
-	ce.cacheMisses++
+	atomic.AddInt64(&ce.cacheMisses, 1)
 
 	// Perform discovery
 	result, err := ce.discoveryEngine.DiscoverBackends(ctx)
 	if err != nil {
 		return nil, err
 	}
+	adapters.FeedDiscoveryResult(ce.adapterRegistry, result)
 
 	// Update cache
 	if ce.enableCaching {
@@ -227,7 +294,14 @@ func (ce *ControllerEngine) selectBackend(
 		log.V(1).Info("Could not detect backend from storage class", "storageClass", storageClass)
 	}
 
-	// Strategy 3: Use first available backend
+	// Strategy 3: Drive the standard VolumeReplication when the generic CSI adapter is
+	// configured and discovery found its CRD
+	if adapters.GenericCSISupports(ce.adapterRegistry, uvr) {
+		log.Info("No explicit backend configured, using the generic CSI adapter")
+		return translation.BackendGenericCSI, nil
+	}
+
+	// Strategy 4: Use first available backend
 	if len(availableBackends) > 0 {
 		log.Info("No explicit backend configured, using first available", "backend", availableBackends[0])
 		return availableBackends[0], nil
@@ -309,6 +383,11 @@ func (ce *ControllerEngine) translateToBackend(
 	backend translation.Backend,
 	log logr.Logger,
 ) (string, string, error) {
+	// The generic CSI adapter translates with its own configured state map
+	if backend == translation.BackendGenericCSI {
+		return "", "", nil
+	}
+
 	state, err := ce.translationEngine.TranslateStateToBackend(backend, string(uvr.Spec.ReplicationState))
 	if err != nil {
 		return "", "", fmt.Errorf("state translation failed: %w", err)
@@ -343,6 +422,9 @@ func (ce *ControllerEngine) getAdapter(
 	if err != nil {
 		return nil, fmt.Errorf("failed to create adapter for backend %s: %w", backend, err)
 	}
+	if setter, ok := adapter.(adapters.EventRecorderSetter); ok && ce.recorder != nil {
+		setter.SetEventRecorder(ce.recorder)
+	}
 
 	// Initialize adapter
 	if err := adapter.Initialize(ctx); err != nil {
@@ -380,13 +462,84 @@ func (ce *ControllerEngine) ProcessReplication(
 			return fmt.Errorf("adapter selection failed: %w", err)
 		}
 
-		return adapter.DeleteReplication(ctx, uvr)
+		return ce.RunOperation(ctx, uvr, "delete", log, func(ctx context.Context) error {
+			return adapter.DeleteReplication(ctx, uvr)
+		})
 	}
 
 	// For all other operations, just ensure the replication is in desired state
 	return ce.EnsureReplication(ctx, uvr, log)
 }
 
+// RunOperation runs a state-changing backend operation for a UVR. Operations for the same
+// UVR run one at a time in the order they were submitted, however many reconciles run
+// concurrently, so e.g. a promote always completes before a pause requested after it.
+// fn must not submit further operations for the same UVR.
+//
+// An operation identical to the UVR's latest one, same name, spec and annotations, is
+// skipped when that one succeeded within the dedupe window, including when it was still
+// running as this one was submitted. "ensure" is never skipped: it corrects drift the
+// backend may have gone through since the last call, which the spec does not reflect.
+func (ce *ControllerEngine) RunOperation(
+	ctx context.Context,
+	uvr *replicationv1alpha1.UnifiedVolumeReplication,
+	operation string,
+	log logr.Logger,
+	fn func(ctx context.Context) error,
+) error {
+	key := client.ObjectKeyFromObject(uvr).String()
+	if pending := ce.operations.pending(key); pending > 0 {
+		log.V(1).Info("Waiting for earlier operations", "operation", operation, "pending", pending)
+	}
+	var signature string
+	if ce.dedupe != nil && operation != "ensure" {
+		signature = operationSignature(operation, uvr)
+	}
+	return ce.operations.run(ctx, key, func(ctx context.Context) error {
+		if ce.dedupe.isDuplicate(key, signature) {
+			log.V(1).Info("Skipping operation identical to one just completed", "operation", operation)
+			atomic.AddInt64(&ce.dedupedCount, 1)
+			return nil
+		}
+		err := fn(ctx)
+		ce.dedupe.record(key, signature, err)
+		return err
+	})
+}
+
+// PendingOperations returns the number of running and queued operations for a UVR
+func (ce *ControllerEngine) PendingOperations(uvr *replicationv1alpha1.UnifiedVolumeReplication) int {
+	return ce.operations.pending(client.ObjectKeyFromObject(uvr).String())
+}
+
+// AdmitReconcile reports whether a reconcile may start under the write budget, and when
+// not, how long to wait before retrying. Urgent reconciles, such as deletions and
+// failovers, are always admitted and may overdraw the budget.
+func (ce *ControllerEngine) AdmitReconcile(urgent bool) (bool, time.Duration) {
+	if ce.writes == nil {
+		return true, 0
+	}
+	return ce.writes.admit(urgent)
+}
+
+// BudgetedClient returns c with its writes charged to the write budget, so writes made
+// outside the engine count against it too. c is returned as is without a budget.
+func (ce *ControllerEngine) BudgetedClient(c client.Client) client.Client {
+	if ce.writes == nil {
+		return c
+	}
+	return &budgetedClient{Client: c, budget: ce.writes}
+}
+
+// WriteBudgetUtilization returns the share of the write budget in use, above 1 while urgent
+// writes have overdrawn it, and 0 without a budget
+func (ce *ControllerEngine) WriteBudgetUtilization() float64 {
+	if ce.writes == nil {
+		return 0
+	}
+	return ce.writes.utilization()
+}
+
 // GetReplicationStatus retrieves status from the backend with translation
 func (ce *ControllerEngine) GetReplicationStatus(
 	ctx context.Context,
@@ -412,7 +565,12 @@ func (ce *ControllerEngine) GetReplicationStatus(
 	}
 
 	// Get status from adapter
+	release, err := ce.backendSlots.acquire(ctx, backend)
+	if err != nil {
+		return nil, err
+	}
 	status, err := adapter.GetReplicationStatus(ctx, uvr)
+	release()
 	if err != nil {
 		return nil, err
 	}
@@ -448,11 +606,12 @@ func (ce *ControllerEngine) GetMetrics() map[string]interface{} {
 	defer ce.discoveryCacheMutex.RUnlock()
 
 	return map[string]interface{}{
-		"operation_count": ce.operationCount,
-		"cache_hits":      ce.cacheHits,
-		"cache_misses":    ce.cacheMisses,
-		"cache_entries":   len(ce.discoveryCache),
-		"last_discovery":  ce.lastDiscoveryTime,
+		"operation_count":    atomic.LoadInt64(&ce.operationCount),
+		"deduped_operations": atomic.LoadInt64(&ce.dedupedCount),
+		"cache_hits":         atomic.LoadInt64(&ce.cacheHits),
+		"cache_misses":       atomic.LoadInt64(&ce.cacheMisses),
+		"cache_entries":      len(ce.discoveryCache),
+		"last_discovery":     ce.lastDiscoveryTime,
 	}
 }
 
@@ -18,13 +18,24 @@ package pkg
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
 	"github.com/unified-replication/operator/pkg/adapters"
@@ -164,6 +175,52 @@ func TestControllerEngine_BackendSelection(t *testing.T) {
 	}
 }
 
+func TestControllerEngine_GenericCSISelection(t *testing.T) {
+	ctx := context.Background()
+	log := ctrl.Log.WithName("test")
+
+	client := fake.NewClientBuilder().Build()
+	factory := adapters.NewGenericCSIAdapterFactory()
+	require.NoError(t, factory.SetDefaults("vendor-replication", nil))
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewTridentAdapterFactory()))
+	require.NoError(t, registry.RegisterFactory(factory))
+	engine := NewControllerEngine(client, discovery.NewEngine(client, nil), translation.NewEngine(), registry, nil)
+
+	uvr := &replicationv1alpha1.UnifiedVolumeReplication{
+		Spec: replicationv1alpha1.UnifiedVolumeReplicationSpec{
+			SourceEndpoint: replicationv1alpha1.Endpoint{StorageClass: "generic-storage"},
+		},
+	}
+	availableBackends := []translation.Backend{translation.BackendTrident}
+
+	backend, err := engine.selectBackend(ctx, uvr, availableBackends, log)
+	require.NoError(t, err)
+	assert.Equal(t, translation.BackendTrident, backend, "the generic CSI adapter waits for discovery")
+
+	adapters.FeedDiscoveryResult(registry, &discovery.DiscoveryResult{Backends: map[translation.Backend]discovery.BackendDiscoveryResult{
+		translation.BackendCeph: {CRDs: []discovery.CRDInfo{{Name: adapters.StandardVolumeReplicationCRD, Available: true}}},
+	}})
+	backend, err = engine.selectBackend(ctx, uvr, availableBackends, log)
+	require.NoError(t, err)
+	assert.Equal(t, translation.BackendGenericCSI, backend)
+
+	// Hints for a specific backend still win
+	uvr.Spec.SourceEndpoint.StorageClass = "trident-nas"
+	backend, err = engine.selectBackend(ctx, uvr, availableBackends, log)
+	require.NoError(t, err)
+	assert.Equal(t, translation.BackendTrident, backend)
+
+	// The adapter translates states with its own map
+	state, mode, err := engine.translateToBackend(uvr, translation.BackendGenericCSI, log)
+	require.NoError(t, err)
+	assert.Empty(t, state)
+	assert.Empty(t, mode)
+	adapter, err := engine.getAdapter(ctx, translation.BackendGenericCSI, log)
+	require.NoError(t, err)
+	assert.Equal(t, translation.BackendGenericCSI, adapter.GetBackendType())
+}
+
 func TestControllerEngine_Translation(t *testing.T) {
 	log := ctrl.Log.WithName("test")
 
@@ -364,3 +421,315 @@ func createTestUVR(name, namespace string) *replicationv1alpha1.UnifiedVolumeRep
 		},
 	}
 }
+
+func TestControllerEngine_RunOperationOrdering(t *testing.T) {
+	log := ctrl.Log.WithName("test")
+	engine := NewControllerEngine(fake.NewClientBuilder().Build(), nil, translation.NewEngine(), adapters.NewRegistry(), nil)
+	uvr := createTestUVR("test-ordering", "default")
+
+	var mu sync.Mutex
+	var applied []string
+	record := func(entry string) {
+		mu.Lock()
+		defer mu.Unlock()
+		applied = append(applied, entry)
+	}
+	operation := func(name string, release <-chan struct{}) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			record(name + "-start")
+			if release != nil {
+				<-release
+			}
+			record(name + "-end")
+			return nil
+		}
+	}
+	waitPending := func(n int) {
+		assert.Eventually(t, func() bool { return engine.PendingOperations(uvr) == n }, time.Second, time.Millisecond)
+	}
+
+	releasePromote := make(chan struct{})
+	var wg sync.WaitGroup
+	submit := func(name string, release <-chan struct{}) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, engine.RunOperation(context.Background(), uvr, name, log, operation(name, release)))
+		}()
+	}
+
+	submit("promote", releasePromote)
+	waitPending(1)
+	submit("pause", nil)
+	waitPending(2)
+
+	// Operations on other UVRs are not held up by this one
+	other := createTestUVR("test-ordering-other", "default")
+	assert.NoError(t, engine.RunOperation(context.Background(), other, "resync", log, func(ctx context.Context) error { return nil }))
+
+	// A queued operation whose context ends is skipped without breaking the order
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan error)
+	go func() {
+		cancelled <- engine.RunOperation(ctx, uvr, "cancelled", log, operation("cancelled", nil))
+	}()
+	waitPending(3)
+	cancel()
+	assert.ErrorIs(t, <-cancelled, context.Canceled)
+	submit("resume", nil)
+
+	close(releasePromote)
+	wg.Wait()
+
+	assert.Equal(t, []string{"promote-start", "promote-end", "pause-start", "pause-end", "resume-start", "resume-end"}, applied)
+	assert.Zero(t, engine.PendingOperations(uvr))
+}
+
+func TestControllerEngine_RunOperationDedupe(t *testing.T) {
+	log := ctrl.Log.WithName("test")
+	engine := NewControllerEngine(fake.NewClientBuilder().Build(), nil, translation.NewEngine(), adapters.NewRegistry(), nil)
+	now := time.Now()
+	engine.dedupe.now = func() time.Time { return now }
+	uvr := createTestUVR("test-dedupe", "default")
+
+	var writes atomic.Int64
+	setState := func(ctx context.Context) error {
+		writes.Add(1)
+		return nil
+	}
+
+	// Reconciles racing to set the same state: one runs, the others queue behind it and
+	// find it already applied
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		assert.NoError(t, engine.RunOperation(context.Background(), uvr, "promote", log, func(ctx context.Context) error {
+			close(started)
+			<-release
+			return setState(ctx)
+		}))
+	}()
+	<-started
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, engine.RunOperation(context.Background(), uvr.DeepCopy(), "promote", log, setState))
+		}()
+	}
+	assert.Eventually(t, func() bool { return engine.PendingOperations(uvr) == 6 }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int64(1), writes.Load(), "only one backend write for identical operations")
+	assert.Equal(t, int64(5), engine.GetMetrics()["deduped_operations"])
+
+	// A different operation, or the same one for another spec, runs
+	require.NoError(t, engine.RunOperation(context.Background(), uvr, "pause", log, setState))
+	require.NoError(t, engine.RunOperation(context.Background(), uvr, "promote", log, setState))
+	assert.Equal(t, int64(3), writes.Load(), "promote runs again after a pause")
+	changed := uvr.DeepCopy()
+	changed.Spec.ReplicationMode = replicationv1alpha1.ReplicationModeSynchronous
+	require.NoError(t, engine.RunOperation(context.Background(), changed, "promote", log, setState))
+	assert.Equal(t, int64(4), writes.Load())
+
+	// Once the window has passed the operation runs again
+	now = now.Add(DefaultControllerEngineConfig().OperationDedupeWindow)
+	require.NoError(t, engine.RunOperation(context.Background(), changed, "promote", log, setState))
+	assert.Equal(t, int64(5), writes.Load())
+
+	// A failed operation is retried right away
+	failed := errors.New("backend unavailable")
+	assert.ErrorIs(t, engine.RunOperation(context.Background(), uvr, "resync", log, func(ctx context.Context) error {
+		writes.Add(1)
+		return failed
+	}), failed)
+	require.NoError(t, engine.RunOperation(context.Background(), uvr, "resync", log, setState))
+	assert.Equal(t, int64(7), writes.Load())
+
+	// ensure always reaches the backend, to correct drift the spec does not show
+	require.NoError(t, engine.RunOperation(context.Background(), uvr, "ensure", log, setState))
+	require.NoError(t, engine.RunOperation(context.Background(), uvr, "ensure", log, setState))
+	assert.Equal(t, int64(9), writes.Load())
+
+	// Without a window every operation runs
+	config := DefaultControllerEngineConfig()
+	config.OperationDedupeWindow = 0
+	engine = NewControllerEngine(fake.NewClientBuilder().Build(), nil, translation.NewEngine(), adapters.NewRegistry(), config)
+	require.NoError(t, engine.RunOperation(context.Background(), uvr, "promote", log, setState))
+	require.NoError(t, engine.RunOperation(context.Background(), uvr, "promote", log, setState))
+	assert.Equal(t, int64(11), writes.Load())
+}
+
+func TestControllerEngine_WriteBudget(t *testing.T) {
+	const (
+		writesPerSecond    = 50
+		burst              = 10
+		workers            = 20
+		writesPerReconcile = 2
+		floodDuration      = time.Second
+	)
+	config := DefaultControllerEngineConfig()
+	config.WritesPerSecond = writesPerSecond
+	config.WriteBurst = burst
+	engine := NewControllerEngine(fake.NewClientBuilder().Build(), nil, translation.NewEngine(), adapters.NewRegistry(), config)
+	c := engine.BudgetedClient(fake.NewClientBuilder().Build())
+
+	var writes, deferred, urgent atomic.Int64
+	write := func(ctx context.Context, name string) {
+		require.NoError(t, c.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}))
+		writes.Add(1)
+	}
+
+	ctx := context.Background()
+	start := time.Now()
+	var wg sync.WaitGroup
+	// Routine reconciles flood the engine, retrying shortly after each deferral
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; time.Since(start) < floodDuration; i++ {
+				if admitted, delay := engine.AdmitReconcile(false); !admitted {
+					assert.GreaterOrEqual(t, delay, minBudgetRequeueDelay)
+					deferred.Add(1)
+					time.Sleep(5 * time.Millisecond)
+					continue
+				}
+				for n := 0; n < writesPerReconcile; n++ {
+					write(ctx, fmt.Sprintf("routine-%d-%d-%d", w, i, n))
+				}
+			}
+		}(w)
+	}
+	// A failover keeps going while the budget is spent
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; time.Since(start) < floodDuration; i++ {
+			admitted, _ := engine.AdmitReconcile(true)
+			assert.True(t, admitted, "urgent reconciles are never deferred")
+			write(ctx, fmt.Sprintf("urgent-%d", i))
+			urgent.Add(1)
+			time.Sleep(50 * time.Millisecond)
+		}
+	}()
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	// Routine reconciles stop at the budget; only reconciles admitted together at the moment
+	// the budget ran out, and urgent writes made while it was overdrawn, can exceed it
+	limit := int64(writesPerSecond*elapsed.Seconds()) + burst + workers*writesPerReconcile + urgent.Load()
+	assert.LessOrEqual(t, writes.Load(), limit, "writes must stay within the budget")
+	assert.Positive(t, deferred.Load())
+	assert.Positive(t, urgent.Load())
+	assert.Greater(t, engine.WriteBudgetUtilization(), 0.0)
+
+	// Status writes count against the budget as well
+	spent := NewControllerEngine(fake.NewClientBuilder().Build(), nil, translation.NewEngine(), adapters.NewRegistry(),
+		&ControllerEngineConfig{WritesPerSecond: 0.1, WriteBurst: 1})
+	uvr := createTestUVR("test-budget", "default")
+	sc := spent.BudgetedClient(fake.NewClientBuilder().WithScheme(newBudgetTestScheme(t)).WithObjects(uvr).WithStatusSubresource(uvr).Build())
+	admitted, _ := spent.AdmitReconcile(false)
+	assert.True(t, admitted)
+	require.NoError(t, sc.Status().Update(ctx, uvr))
+	admitted, _ = spent.AdmitReconcile(false)
+	assert.False(t, admitted)
+}
+
+func TestControllerEngine_NoWriteBudget(t *testing.T) {
+	engine := NewControllerEngine(fake.NewClientBuilder().Build(), nil, translation.NewEngine(), adapters.NewRegistry(), nil)
+	c := fake.NewClientBuilder().Build()
+	assert.Same(t, c, engine.BudgetedClient(c))
+	for i := 0; i < 1000; i++ {
+		admitted, _ := engine.AdmitReconcile(false)
+		require.True(t, admitted)
+	}
+	assert.Zero(t, engine.WriteBudgetUtilization())
+}
+
+func newBudgetTestScheme(t *testing.T) *runtime.Scheme {
+	s := runtime.NewScheme()
+	require.NoError(t, replicationv1alpha1.AddToScheme(s))
+	return s
+}
+
+func TestControllerEngine_BackendConcurrencyLimit(t *testing.T) {
+	ctx := context.Background()
+	log := ctrl.Log.WithName("test")
+	s := newBudgetTestScheme(t)
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+	var crds []client.Object
+	for _, definition := range discovery.TridentCRDs {
+		crds = append(crds, &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: definition.Name},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group:    definition.Group,
+				Names:    apiextensionsv1.CustomResourceDefinitionNames{Kind: definition.Kind},
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: definition.Version, Served: true, Storage: true}},
+			},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{
+				Conditions: []apiextensionsv1.CustomResourceDefinitionCondition{
+					{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue},
+				},
+			},
+		})
+	}
+
+	// Creating a mirror relationship blocks until the test frees it
+	var started, inFlight, maxInFlight atomic.Int64
+	unblock := make(chan struct{})
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(crds...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if u, ok := obj.(*unstructured.Unstructured); ok && u.GroupVersionKind() == adapters.TridentMirrorRelationshipGVK {
+					started.Add(1)
+					current := inFlight.Add(1)
+					for previous := maxInFlight.Load(); current > previous && !maxInFlight.CompareAndSwap(previous, current); {
+						previous = maxInFlight.Load()
+					}
+					<-unblock
+					inFlight.Add(-1)
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).Build()
+
+	registry := adapters.NewRegistry()
+	require.NoError(t, registry.RegisterFactory(adapters.NewTridentAdapterFactory()))
+	config := DefaultControllerEngineConfig()
+	config.MaxConcurrentBackendOperations = 2
+	engine := NewControllerEngine(c, discovery.NewEngine(c, nil), translation.NewEngine(), registry, config)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, engine.EnsureReplication(ctx, createTestUVR(fmt.Sprintf("test-limit-%d", i), "default"), log))
+		}(i)
+	}
+
+	// Two ensures hold the backend's slots, the third waits for one of them
+	require.Eventually(t, func() bool { return started.Load() == 2 }, 5*time.Second, time.Millisecond)
+	assert.Never(t, func() bool { return started.Load() > 2 }, 100*time.Millisecond, time.Millisecond)
+
+	unblock <- struct{}{}
+	require.Eventually(t, func() bool { return started.Load() == 3 }, 5*time.Second, time.Millisecond)
+	close(unblock)
+	wg.Wait()
+	assert.Equal(t, int64(2), maxInFlight.Load())
+
+	// A waiting call gives up when its context ends
+	limiter := newBackendLimiter(1)
+	release, err := limiter.acquire(ctx, translation.BackendTrident)
+	require.NoError(t, err)
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = limiter.acquire(cancelled, translation.BackendTrident)
+	assert.ErrorIs(t, err, context.Canceled)
+	other, err := limiter.acquire(ctx, translation.BackendCeph)
+	require.NoError(t, err, "other backends have their own slots")
+	other()
+	release()
+}
@@ -22,6 +22,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/unified-replication/operator/pkg/translation"
@@ -162,6 +164,51 @@ func TestEngine_CRDOperations(t *testing.T) {
 	})
 }
 
+func TestEngine_CheckBackendMaintenance(t *testing.T) {
+	ctx := context.Background()
+	vrCRD := "volumereplications.replication.storage.openshift.io"
+
+	t.Run("NoSignal", func(t *testing.T) {
+		engine := NewEngine(createFakeClient(createCRD(vrCRD, "replication.storage.openshift.io", "v1alpha1", "VolumeReplication", true)), nil)
+		signal, err := engine.CheckBackendMaintenance(ctx, translation.BackendCeph)
+		require.NoError(t, err)
+		assert.Nil(t, signal)
+	})
+
+	t.Run("Annotation", func(t *testing.T) {
+		crd := createCRD(vrCRD, "replication.storage.openshift.io", "v1alpha1", "VolumeReplication", true)
+		crd.Annotations = map[string]string{MaintenanceAnnotation: "rbd-mirror daemon restarting"}
+		engine := NewEngine(createFakeClient(crd), nil)
+
+		signal, err := engine.CheckBackendMaintenance(ctx, translation.BackendCeph)
+		require.NoError(t, err)
+		require.NotNil(t, signal)
+		assert.Equal(t, translation.BackendCeph, signal.Backend)
+		assert.Equal(t, vrCRD, signal.CRD)
+		assert.Equal(t, "rbd-mirror daemon restarting", signal.Reason)
+
+		// Other backends are unaffected
+		signal, err = engine.CheckBackendMaintenance(ctx, translation.BackendTrident)
+		require.NoError(t, err)
+		assert.Nil(t, signal)
+	})
+
+	t.Run("Condition", func(t *testing.T) {
+		crd := createCRD(vrCRD, "replication.storage.openshift.io", "v1alpha1", "VolumeReplication", true)
+		crd.Status.Conditions = append(crd.Status.Conditions, apiextensionsv1.CustomResourceDefinitionCondition{
+			Type:    MaintenanceConditionType,
+			Status:  apiextensionsv1.ConditionTrue,
+			Message: "cluster upgrade",
+		})
+		engine := NewEngine(createFakeClient(crd), nil)
+
+		signal, err := engine.CheckBackendMaintenance(ctx, translation.BackendCeph)
+		require.NoError(t, err)
+		require.NotNil(t, signal)
+		assert.Equal(t, "cluster upgrade", signal.Reason)
+	})
+}
+
 func TestBaseDetector(t *testing.T) {
 	t.Run("DetectBackend with all CRDs available", func(t *testing.T) {
 		// Create all required CRDs for Ceph
@@ -0,0 +1,98 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"context"
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/unified-replication/operator/pkg/translation"
+)
+
+const (
+	// MaintenanceAnnotation on a backend CRD signals that the backend is under maintenance.
+	// The value describes the maintenance, e.g. "rbd-mirror daemon restarting".
+	MaintenanceAnnotation = "replication.unified.io/maintenance"
+
+	// MaintenanceConditionType is a CRD status condition that, when True, signals maintenance
+	// the same way as the annotation. Its message describes the maintenance.
+	MaintenanceConditionType apiextensionsv1.CustomResourceDefinitionConditionType = "Maintenance"
+)
+
+// MaintenanceSignal describes a maintenance window advertised by a backend
+type MaintenanceSignal struct {
+	// Backend is the backend under maintenance
+	Backend translation.Backend `json:"backend"`
+	// CRD is the backend CRD carrying the signal
+	CRD string `json:"crd"`
+	// Reason is the maintenance description provided by the backend
+	Reason string `json:"reason"`
+}
+
+// CheckBackendMaintenance reports whether any CRD of the backend signals maintenance, either
+// through MaintenanceAnnotation or a True MaintenanceConditionType condition. It returns nil
+// when the backend is not under maintenance.
+func (e *Engine) CheckBackendMaintenance(ctx context.Context, backend translation.Backend) (*MaintenanceSignal, error) {
+	crds, ok := GetRequiredCRDsForBackend(backend)
+	if !ok {
+		return nil, NewDiscoveryError(ErrorTypeUnknown, backend, "",
+			fmt.Sprintf("unknown backend %s", backend))
+	}
+
+	for _, definition := range crds {
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := e.client.Get(ctx, client.ObjectKey{Name: definition.Name}, crd); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, NewDiscoveryErrorWithCause(ErrorTypeUnknown, backend, definition.Name,
+				"failed to read maintenance signal", err)
+		}
+
+		if signal := maintenanceSignalFromCRD(backend, crd); signal != nil {
+			return signal, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// maintenanceSignalFromCRD extracts a maintenance signal from a CRD, if it carries one
+func maintenanceSignalFromCRD(backend translation.Backend, crd *apiextensionsv1.CustomResourceDefinition) *MaintenanceSignal {
+	if reason, ok := crd.Annotations[MaintenanceAnnotation]; ok {
+		if reason == "" {
+			reason = "maintenance annotation set"
+		}
+		return &MaintenanceSignal{Backend: backend, CRD: crd.Name, Reason: reason}
+	}
+
+	for _, condition := range crd.Status.Conditions {
+		if condition.Type == MaintenanceConditionType && condition.Status == apiextensionsv1.ConditionTrue {
+			reason := condition.Message
+			if reason == "" {
+				reason = condition.Reason
+			}
+			return &MaintenanceSignal{Backend: backend, CRD: crd.Name, Reason: reason}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	replicationv1alpha1 "github.com/unified-replication/operator/api/v1alpha1"
+)
+
+// operationDedupe remembers the latest successful operation of each UVR for a short window,
+// so a reconcile repeating it with the same spec is skipped instead of writing to the
+// backend again. Only the latest operation counts: a pause after a resume is never skipped.
+type operationDedupe struct {
+	mu     sync.Mutex
+	window time.Duration
+	recent map[string]recentOperation
+
+	// now returns the current time; replaced in tests
+	now func() time.Time
+}
+
+// recentOperation is the signature of a completed operation and when it completed
+type recentOperation struct {
+	signature string
+	completed time.Time
+}
+
+func newOperationDedupe(window time.Duration) *operationDedupe {
+	return &operationDedupe{window: window, recent: make(map[string]recentOperation), now: time.Now}
+}
+
+// operationSignature identifies an operation by its name and what it was asked to apply:
+// the UVR's spec and annotations, which adapters read for e.g. adoption
+func operationSignature(operation string, uvr *replicationv1alpha1.UnifiedVolumeReplication) string {
+	desired, err := json.Marshal(struct {
+		Spec        replicationv1alpha1.UnifiedVolumeReplicationSpec `json:"spec"`
+		Annotations map[string]string                                `json:"annotations,omitempty"`
+	}{uvr.Spec, uvr.Annotations})
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(desired)
+	return operation + "/" + hex.EncodeToString(sum[:8])
+}
+
+// isDuplicate reports whether the latest operation of key had the same signature and
+// completed within the window
+func (d *operationDedupe) isDuplicate(key, signature string) bool {
+	if d == nil || signature == "" {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	last, ok := d.recent[key]
+	if !ok {
+		return false
+	}
+	if d.now().Sub(last.completed) >= d.window {
+		delete(d.recent, key)
+		return false
+	}
+	return last.signature == signature
+}
+
+// record remembers the outcome of an operation of key. A failed operation may have left
+// the backend anywhere, so it clears the record and the next operation always runs.
+func (d *operationDedupe) record(key, signature string, err error) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err != nil || signature == "" {
+		delete(d.recent, key)
+		return
+	}
+	d.recent[key] = recentOperation{signature: signature, completed: d.now()}
+	// Drop the records that expired, so UVRs gone since do not accumulate
+	for other, last := range d.recent {
+		if d.now().Sub(last.completed) >= d.window {
+			delete(d.recent, other)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkg
+
+import (
+	"context"
+	"sync"
+)
+
+// operationQueue runs operations submitted for the same key one at a time, in the order
+// they were submitted. Each submission waits for the one before it to finish.
+type operationQueue struct {
+	mu    sync.Mutex
+	lanes map[string]*operationLane
+}
+
+// operationLane tracks the operations of one key. tail is closed when the most recently
+// submitted operation has finished.
+type operationLane struct {
+	tail    chan struct{}
+	pending int
+}
+
+func newOperationQueue() *operationQueue {
+	return &operationQueue{lanes: make(map[string]*operationLane)}
+}
+
+// run waits for the operations submitted earlier for key, then runs fn. If ctx is done
+// while waiting, fn is skipped and ctx.Err() returned; later operations still wait for
+// the earlier ones.
+func (q *operationQueue) run(ctx context.Context, key string, fn func(ctx context.Context) error) error {
+	q.mu.Lock()
+	lane, ok := q.lanes[key]
+	if !ok {
+		lane = &operationLane{}
+		q.lanes[key] = lane
+	}
+	previous := lane.tail
+	done := make(chan struct{})
+	lane.tail = done
+	lane.pending++
+	q.mu.Unlock()
+
+	if previous != nil {
+		select {
+		case <-previous:
+		case <-ctx.Done():
+			go func() {
+				<-previous
+				q.finish(key, done)
+			}()
+			return ctx.Err()
+		}
+	}
+
+	defer q.finish(key, done)
+	return fn(ctx)
+}
+
+// finish releases the next operation for key and drops the lane once it is idle
+func (q *operationQueue) finish(key string, done chan struct{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	close(done)
+	lane := q.lanes[key]
+	lane.pending--
+	if lane.pending == 0 {
+		delete(q.lanes, key)
+	}
+}
+
+// pending returns the number of running and waiting operations for key
+func (q *operationQueue) pending(key string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if lane, ok := q.lanes[key]; ok {
+		return lane.pending
+	}
+	return 0
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package security
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RequestAuthorizer decides whether the holder of a bearer token may read a URL path
+type RequestAuthorizer interface {
+	Authorize(ctx context.Context, token, verb, path string) (allowed bool, user string, err error)
+}
+
+// KubernetesAuthorizer authenticates tokens with a TokenReview and authorizes them with a
+// SubjectAccessReview on the non-resource URL, so access is granted through regular RBAC
+// (nonResourceURLs rules)
+type KubernetesAuthorizer struct {
+	client client.Client
+}
+
+// NewKubernetesAuthorizer creates an authorizer backed by the Kubernetes API
+func NewKubernetesAuthorizer(c client.Client) *KubernetesAuthorizer {
+	return &KubernetesAuthorizer{client: c}
+}
+
+// Authorize implements RequestAuthorizer
+func (ka *KubernetesAuthorizer) Authorize(ctx context.Context, token, verb, path string) (bool, string, error) {
+	tokenReview := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}
+	if err := ka.client.Create(ctx, tokenReview); err != nil {
+		return false, "", fmt.Errorf("token review failed: %w", err)
+	}
+	if !tokenReview.Status.Authenticated {
+		return false, "", nil
+	}
+
+	user := tokenReview.Status.User
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for key, values := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(values)
+	}
+	accessReview := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+			NonResourceAttributes: &authorizationv1.NonResourceAttributes{
+				Path: path,
+				Verb: verb,
+			},
+		},
+	}
+	if err := ka.client.Create(ctx, accessReview); err != nil {
+		return false, user.Username, fmt.Errorf("subject access review failed: %w", err)
+	}
+	return accessReview.Status.Allowed, user.Username, nil
+}
+
+// RequireAuthorization only lets requests through to handler when their bearer token is
+// allowed to access the request path. Rejected requests are recorded by auditLogger, which
+// may be nil.
+func RequireAuthorization(authorizer RequestAuthorizer, auditLogger *AuditLogger, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		operation := fmt.Sprintf("%s %s", r.Method, r.URL.Path)
+		deny := func(status int, user, reason string) {
+			if auditLogger != nil {
+				auditLogger.LogAuthFailure(r.Context(), user, operation, reason)
+			}
+			http.Error(w, http.StatusText(status), status)
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			deny(http.StatusUnauthorized, "", "missing bearer token")
+			return
+		}
+
+		allowed, user, err := authorizer.Authorize(r.Context(), token, strings.ToLower(r.Method), r.URL.Path)
+		switch {
+		case err != nil:
+			deny(http.StatusInternalServerError, user, err.Error())
+		case user == "":
+			deny(http.StatusUnauthorized, "", "invalid bearer token")
+		case !allowed:
+			deny(http.StatusForbidden, user, "access denied")
+		default:
+			handler.ServeHTTP(w, r)
+		}
+	})
+}
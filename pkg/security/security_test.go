@@ -18,6 +18,8 @@ package security
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -151,6 +153,7 @@ func TestSecurityValidator(t *testing.T) {
 			"30s",
 			"1d",
 			"120m",
+			"1h30m",
 		}
 
 		for _, expr := range validExpressions {
@@ -164,6 +167,7 @@ func TestSecurityValidator(t *testing.T) {
 			"1.5h",
 			"invalid",
 			"-15m",
+			"30m1h",
 		}
 
 		for _, expr := range invalidExpressions {
@@ -387,6 +391,54 @@ func TestRBACPolicy(t *testing.T) {
 	})
 }
 
+// staticAuthorizer allows known tokens and reports unknown ones as unauthenticated
+type staticAuthorizer struct {
+	users   map[string]string // token -> user
+	allowed map[string]bool   // user -> allowed
+}
+
+func (sa *staticAuthorizer) Authorize(ctx context.Context, token, verb, path string) (bool, string, error) {
+	user := sa.users[token]
+	return verb == "get" && sa.allowed[user], user, nil
+}
+
+// TestRequireAuthorization tests the bearer token gate of debug endpoints
+func TestRequireAuthorization(t *testing.T) {
+	authorizer := &staticAuthorizer{
+		users:   map[string]string{"reader-token": "reader", "other-token": "other"},
+		allowed: map[string]bool{"reader": true},
+	}
+	al := NewAuditLogger(ctrl.Log.WithName("test"), true)
+	handler := RequireAuthorization(authorizer, al, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	tests := []struct {
+		name   string
+		header string
+		status int
+	}{
+		{"MissingToken", "", http.StatusUnauthorized},
+		{"UnknownToken", "Bearer bogus", http.StatusUnauthorized},
+		{"Denied", "Bearer other-token", http.StatusForbidden},
+		{"Allowed", "Bearer reader-token", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/debug/adapter-metrics", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, tt.status, rec.Code)
+		})
+	}
+
+	// Every rejected request is audited
+	assert.Equal(t, 3, al.GetEventCountByType(AuditEventAuthFailure))
+}
+
 // TLS certificate tests are in pkg/webhook/tls_test.go since the functions are in that package
 
 // TestSecurityIntegration tests security features working together
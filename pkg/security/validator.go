@@ -276,10 +276,10 @@ func (sv *SecurityValidator) ValidateScheduleExpression(expr string) error {
 		return nil // Empty is allowed (optional field)
 	}
 
-	// Must match pattern: number + unit (s, m, h, d)
-	scheduleRegex := regexp.MustCompile(`^[0-9]+(s|m|h|d)$`)
+	// Must match pattern: numbers + units (d, h, m, s), largest unit first
+	scheduleRegex := regexp.MustCompile(`^([0-9]+d([0-9]+h)?([0-9]+m)?([0-9]+s)?|[0-9]+h([0-9]+m)?([0-9]+s)?|[0-9]+m([0-9]+s)?|[0-9]+s)$`)
 	if !scheduleRegex.MatchString(expr) {
-		return fmt.Errorf("invalid schedule expression: must be like '15m', '1h', '30s'")
+		return fmt.Errorf("invalid schedule expression: must be like '15m', '1h', '1h30m', '30s'")
 	}
 
 	return nil
@@ -17,12 +17,20 @@ limitations under the License.
 package translation
 
 import (
+	"fmt"
+	"sort"
 	"strings"
+	"sync"
 )
 
-// Engine implements the Translator interface with static mapping tables
+// Engine implements the Translator interface with the built-in mapping tables, whose state
+// maps can be replaced at runtime with ReloadStateMaps
 type Engine struct {
-	// No state needed for static translation engine
+	mu sync.RWMutex
+	// stateMaps holds the state maps loaded by ReloadStateMaps; nil until the first reload
+	stateMaps map[Backend]*TranslationMap
+	// reloadHooks are called after each reload
+	reloadHooks []func(StateMapChange)
 }
 
 // NewEngine creates a new translation engine
@@ -30,9 +38,141 @@ func NewEngine() *Engine {
 	return &Engine{}
 }
 
+// StateMapChange is a reload of the state maps: the map of each backend before and after
+type StateMapChange struct {
+	Previous map[Backend]*TranslationMap
+	Current  map[Backend]*TranslationMap
+}
+
+// Reinterpret returns the unified state that the backend state read as unifiedState under
+// the previous maps reads as under the current ones. ok is false when the backend state is
+// no longer recognized.
+func (c StateMapChange) Reinterpret(backend Backend, unifiedState string) (string, bool) {
+	previous, current := c.Previous[backend], c.Current[backend]
+	if previous == nil || current == nil {
+		return unifiedState, true
+	}
+	backendState, exists := previous.ToBackend(unifiedState)
+	if !exists {
+		return unifiedState, true
+	}
+	return current.FromBackend(backendState)
+}
+
+// Retranslated reports whether a unified state is written to the backend as a different
+// backend state under the current maps
+func (c StateMapChange) Retranslated(backend Backend, unifiedState string) bool {
+	previous, current := c.Previous[backend], c.Current[backend]
+	if previous == nil || current == nil {
+		return false
+	}
+	before, _ := previous.ToBackend(unifiedState)
+	after, _ := current.ToBackend(unifiedState)
+	return before != after
+}
+
+// StateMap returns the state map in force for a backend
+func (e *Engine) StateMap(backend Backend) (*TranslationMap, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if stateMap, exists := e.stateMaps[backend]; exists {
+		return stateMap, nil
+	}
+	return GetStateMap(backend)
+}
+
+// ReloadStateMaps replaces the state maps with the built-in ones updated by overrides, a set
+// of unified-to-backend entries per backend. Every unified state must still translate to a
+// backend state and back to itself; otherwise the maps in force are kept and the error says
+// why. The functions registered with OnReload are called when the maps change.
+func (e *Engine) ReloadStateMaps(overrides map[Backend]map[string]string) error {
+	for backend := range overrides {
+		if !IsBackendSupported(backend) {
+			return NewTranslationError(ErrorTypeUnsupportedMapping, backend, "backend", string(backend),
+				"backend not supported for state translation")
+		}
+	}
+	current := make(map[Backend]*TranslationMap, len(BackendStateMaps))
+	for backend, builtIn := range BackendStateMaps {
+		entries := make(map[string]string, len(builtIn.UnifiedToBackend))
+		for unified, backendState := range builtIn.UnifiedToBackend {
+			entries[unified] = backendState
+		}
+		for unified, backendState := range overrides[backend] {
+			if _, exists := entries[unified]; !exists {
+				return NewTranslationError(ErrorTypeInvalidValue, backend, "state", unified,
+					"override of a state the backend does not support")
+			}
+			entries[unified] = backendState
+		}
+		stateMap := NewTranslationMap(entries)
+		if err := validateStateRoundTrips(backend, stateMap); err != nil {
+			return err
+		}
+		current[backend] = stateMap
+	}
+
+	e.mu.Lock()
+	previous := make(map[Backend]*TranslationMap, len(current))
+	changed := false
+	for backend, stateMap := range current {
+		previous[backend] = BackendStateMaps[backend]
+		if loaded, exists := e.stateMaps[backend]; exists {
+			previous[backend] = loaded
+		}
+		changed = changed || !equalStateMaps(previous[backend], stateMap)
+	}
+	e.stateMaps = current
+	hooks := append([]func(StateMapChange){}, e.reloadHooks...)
+	e.mu.Unlock()
+
+	if changed {
+		for _, hook := range hooks {
+			hook(StateMapChange{Previous: previous, Current: current})
+		}
+	}
+	return nil
+}
+
+// OnReload registers fn to be called after each reload that changes the state maps
+func (e *Engine) OnReload(fn func(StateMapChange)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.reloadHooks = append(e.reloadHooks, fn)
+}
+
+// validateStateRoundTrips checks that every unified state of a state map translates to a
+// backend state and back to itself
+func validateStateRoundTrips(backend Backend, stateMap *TranslationMap) error {
+	if err := stateMap.Validate(); err != nil {
+		return NewTranslationErrorWithCause(ErrorTypeInconsistentMapping, backend, "state", "",
+			"state mapping validation failed", err)
+	}
+	for unified, backendState := range stateMap.UnifiedToBackend {
+		if roundTrip, _ := stateMap.FromBackend(backendState); roundTrip != unified {
+			return NewTranslationError(ErrorTypeInconsistentMapping, backend, "state", unified,
+				fmt.Sprintf("round trip through backend state '%s' yields '%s'", backendState, roundTrip))
+		}
+	}
+	return nil
+}
+
+// equalStateMaps reports whether two state maps translate every unified state alike
+func equalStateMaps(a, b *TranslationMap) bool {
+	if len(a.UnifiedToBackend) != len(b.UnifiedToBackend) {
+		return false
+	}
+	for unified, backendState := range a.UnifiedToBackend {
+		if other, exists := b.ToBackend(unified); !exists || other != backendState {
+			return false
+		}
+	}
+	return true
+}
+
 // TranslateStateToBackend translates unified state to backend-specific state
 func (e *Engine) TranslateStateToBackend(backend Backend, unifiedState string) (string, error) {
-	stateMap, err := GetStateMap(backend)
+	stateMap, err := e.StateMap(backend)
 	if err != nil {
 		return "", err
 	}
@@ -48,7 +188,7 @@ func (e *Engine) TranslateStateToBackend(backend Backend, unifiedState string) (
 
 // TranslateStateFromBackend translates backend-specific state to unified state
 func (e *Engine) TranslateStateFromBackend(backend Backend, backendState string) (string, error) {
-	stateMap, err := GetStateMap(backend)
+	stateMap, err := e.StateMap(backend)
 	if err != nil {
 		return "", err
 	}
@@ -94,14 +234,55 @@ func (e *Engine) TranslateModeFromBackend(backend Backend, backendMode string) (
 	return unifiedMode, nil
 }
 
+// ValidateRoundTrip translates every unified state the backend supports to its backend
+// state and back, and reports all states that do not come back as themselves, e.g. two
+// unified states sharing one backend state
+func (e *Engine) ValidateRoundTrip(backend Backend) error {
+	states, err := e.GetSupportedStates(backend)
+	if err != nil {
+		return err
+	}
+	sort.Strings(states)
+
+	var failed, failures []string
+	for _, unified := range states {
+		backendState, err := e.TranslateStateToBackend(backend, unified)
+		if err != nil {
+			failed = append(failed, unified)
+			failures = append(failures, fmt.Sprintf("%s does not translate to the backend", unified))
+			continue
+		}
+		roundTrip, err := e.TranslateStateFromBackend(backend, backendState)
+		if err != nil {
+			failed = append(failed, unified)
+			failures = append(failures, fmt.Sprintf("%s->%s is not recognized back", unified, backendState))
+			continue
+		}
+		if roundTrip != unified {
+			failed = append(failed, unified)
+			failures = append(failures, fmt.Sprintf("%s->%s->%s", unified, backendState, roundTrip))
+		}
+	}
+	if len(failed) > 0 {
+		return NewTranslationError(ErrorTypeInconsistentMapping, backend, "state", strings.Join(failed, ","),
+			fmt.Sprintf("states do not round-trip: %s", strings.Join(failures, "; ")))
+	}
+	return nil
+}
+
 // ValidateTranslation validates that a translation is bidirectionally consistent
 func (e *Engine) ValidateTranslation(backend Backend) error {
 	// Validate state map
-	stateMap, err := GetStateMap(backend)
+	stateMap, err := e.StateMap(backend)
 	if err != nil {
 		return err
 	}
 
+	// Report states that do not round-trip by name before the generic map check
+	if err := e.ValidateRoundTrip(backend); err != nil {
+		return err
+	}
+
 	if err := stateMap.Validate(); err != nil {
 		return NewTranslationErrorWithCause(ErrorTypeInconsistentMapping, backend, "state", "",
 			"state mapping validation failed", err)
@@ -123,7 +304,7 @@ func (e *Engine) ValidateTranslation(backend Backend) error {
 
 // GetSupportedStates returns all supported states for a backend
 func (e *Engine) GetSupportedStates(backend Backend) ([]string, error) {
-	stateMap, err := GetStateMap(backend)
+	stateMap, err := e.StateMap(backend)
 	if err != nil {
 		return nil, err
 	}
@@ -255,7 +436,7 @@ func (e *Engine) GetBackendInfo(backend Backend) (BackendInfo, error) {
 		return BackendInfo{}, err
 	}
 
-	stateMap, _ := GetStateMap(backend)
+	stateMap, _ := e.StateMap(backend)
 	modeMap, _ := GetModeMap(backend)
 
 	return BackendInfo{
@@ -361,6 +361,114 @@ func TestEngine_ValidationIntegration(t *testing.T) {
 	})
 }
 
+func TestEngine_ValidateRoundTrip(t *testing.T) {
+	tests := []struct {
+		backend Backend
+		// drift replaces entries of the built-in state map
+		drift   map[string]string
+		failed  string
+		message string
+	}{
+		{
+			backend: BackendCeph,
+			drift:   map[string]string{"promoting": "resync", "demoting": "resync"},
+			failed:  "demoting,promoting",
+			message: "states do not round-trip: demoting->resync->syncing; promoting->resync->syncing",
+		},
+		{
+			backend: BackendTrident,
+			drift:   map[string]string{"failed": "established"},
+			failed:  "failed",
+			message: "states do not round-trip: failed->established->source",
+		},
+		{
+			backend: BackendPowerStore,
+			drift:   map[string]string{"syncing": "destination"},
+			failed:  "syncing",
+			message: "states do not round-trip: syncing->destination->replica",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.backend), func(t *testing.T) {
+			engine := NewEngine()
+			assert.NoError(t, engine.ValidateRoundTrip(tt.backend), "the built-in map round-trips")
+
+			// The drifted states are written as another state's backend state, which still
+			// reads back as that state
+			builtIn := BackendStateMaps[tt.backend]
+			drifted := &TranslationMap{UnifiedToBackend: map[string]string{}, BackendToUnified: map[string]string{}}
+			for unified, backendState := range builtIn.UnifiedToBackend {
+				drifted.UnifiedToBackend[unified] = backendState
+			}
+			for backendState, unified := range builtIn.BackendToUnified {
+				drifted.BackendToUnified[backendState] = unified
+			}
+			for unified, backendState := range tt.drift {
+				drifted.UnifiedToBackend[unified] = backendState
+			}
+			engine.stateMaps = map[Backend]*TranslationMap{tt.backend: drifted}
+
+			err := engine.ValidateRoundTrip(tt.backend)
+			translationErr, ok := GetTranslationError(err)
+			if assert.True(t, ok, "got %v", err) {
+				assert.Equal(t, ErrorTypeInconsistentMapping, translationErr.Type)
+				assert.Equal(t, tt.failed, translationErr.Value)
+				assert.Contains(t, translationErr.Message, tt.message)
+			}
+			assert.Equal(t, err, engine.ValidateTranslation(tt.backend), "ValidateTranslation reports the drift")
+		})
+	}
+
+	assert.Error(t, NewEngine().ValidateRoundTrip("unknown"))
+}
+
+func TestEngine_ReloadStateMaps(t *testing.T) {
+	engine := NewEngine()
+	var changes []StateMapChange
+	engine.OnReload(func(change StateMapChange) { changes = append(changes, change) })
+
+	// Swapping two backend states reinterprets both, and leaves the others alone
+	swapped := map[Backend]map[string]string{BackendTrident: {
+		"syncing": "established-failed",
+		"failed":  "established-syncing",
+	}}
+	assert.NoError(t, engine.ReloadStateMaps(swapped))
+	state, err := engine.TranslateStateFromBackend(BackendTrident, "established-syncing")
+	assert.NoError(t, err)
+	assert.Equal(t, "failed", state)
+	assert.Len(t, changes, 1)
+
+	reinterpreted, ok := changes[0].Reinterpret(BackendTrident, "syncing")
+	assert.True(t, ok)
+	assert.Equal(t, "failed", reinterpreted)
+	reinterpreted, _ = changes[0].Reinterpret(BackendTrident, "source")
+	assert.Equal(t, "source", reinterpreted)
+	reinterpreted, _ = changes[0].Reinterpret(BackendCeph, "syncing")
+	assert.Equal(t, "syncing", reinterpreted)
+	assert.True(t, changes[0].Retranslated(BackendTrident, "syncing"))
+	assert.False(t, changes[0].Retranslated(BackendTrident, "replica"))
+
+	// Reloading the same overrides changes nothing
+	assert.NoError(t, engine.ReloadStateMaps(swapped))
+	assert.Len(t, changes, 1)
+
+	// Overrides breaking the round trip, or naming unknown states or backends, are rejected
+	// and the maps in force are kept
+	assert.Error(t, engine.ReloadStateMaps(map[Backend]map[string]string{BackendTrident: {"replica": "established"}}))
+	assert.Error(t, engine.ReloadStateMaps(map[Backend]map[string]string{BackendTrident: {"paused": "quiesced"}}))
+	assert.Error(t, engine.ReloadStateMaps(map[Backend]map[string]string{"unknown": {"source": "primary"}}))
+	state, _ = engine.TranslateStateFromBackend(BackendTrident, "established-syncing")
+	assert.Equal(t, "failed", state)
+	assert.Len(t, changes, 1)
+
+	// Reloading without overrides restores the built-in maps
+	assert.NoError(t, engine.ReloadStateMaps(nil))
+	state, _ = engine.TranslateStateFromBackend(BackendTrident, "established-syncing")
+	assert.Equal(t, "syncing", state)
+	assert.Len(t, changes, 2)
+	assert.NoError(t, engine.ValidateAllTranslations())
+}
+
 func TestTranslationError(t *testing.T) {
 	t.Run("basic error", func(t *testing.T) {
 		err := NewTranslationError(ErrorTypeInvalidValue, BackendCeph, "state", "invalid", "test message")
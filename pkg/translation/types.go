@@ -30,6 +30,9 @@ const (
 	BackendTrident Backend = "trident"
 	// BackendPowerStore represents Dell PowerStore
 	BackendPowerStore Backend = "powerstore"
+	// BackendGenericCSI represents any CSI driver implementing the standard VolumeReplication,
+	// translated with a configured state map rather than a built-in one
+	BackendGenericCSI Backend = "generic-csi"
 )
 
 // TranslationError represents various types of translation failures
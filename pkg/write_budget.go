@@ -0,0 +1,152 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkg
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"golang.org/x/time/rate"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// minBudgetRequeueDelay is the shortest delay a reconcile deferred by the write budget waits
+const minBudgetRequeueDelay = time.Second
+
+// writeBudget caps the API writes made across all reconciles. It is a token bucket: every
+// write takes a token, and non-urgent reconciles only start while a token is available.
+// Writes never wait, so an urgent reconcile can overdraw the bucket; non-urgent ones then
+// wait until the debt is paid back, which keeps the long-run write rate within the budget.
+type writeBudget struct {
+	limiter *rate.Limiter
+	burst   int
+}
+
+func newWriteBudget(writesPerSecond float64, burst int) *writeBudget {
+	if burst < 1 {
+		burst = max(1, int(writesPerSecond))
+	}
+	return &writeBudget{limiter: rate.NewLimiter(rate.Limit(writesPerSecond), burst), burst: burst}
+}
+
+// admit reports whether a reconcile may start. Urgent reconciles are always admitted;
+// otherwise it returns how long to wait, which grows with the debt urgent writes left.
+func (b *writeBudget) admit(urgent bool) (bool, time.Duration) {
+	if urgent {
+		return true, 0
+	}
+	tokens := b.limiter.Tokens()
+	if tokens >= 1 {
+		return true, 0
+	}
+	delay := max(time.Duration((1-tokens)/float64(b.limiter.Limit())*float64(time.Second)), minBudgetRequeueDelay)
+	// Spread the deferred reconciles so they do not all return at the same moment
+	return false, delay + time.Duration(rand.Int63n(int64(delay)))
+}
+
+// charge takes a token for a write without waiting for it
+func (b *writeBudget) charge() {
+	b.limiter.Reserve()
+}
+
+// utilization is the share of the burst in use: 0 when the bucket is full, 1 when it is
+// empty, above 1 while urgent writes have overdrawn it
+func (b *writeBudget) utilization() float64 {
+	return max(0, 1-b.limiter.Tokens()/float64(b.burst))
+}
+
+// budgetedClient charges every write to the budget
+type budgetedClient struct {
+	client.Client
+	budget *writeBudget
+}
+
+func (c *budgetedClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	c.budget.charge()
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *budgetedClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	c.budget.charge()
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *budgetedClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.budget.charge()
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *budgetedClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	c.budget.charge()
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *budgetedClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	c.budget.charge()
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *budgetedClient) Status() client.SubResourceWriter {
+	return &budgetedSubResourceWriter{SubResourceWriter: c.Client.Status(), budget: c.budget}
+}
+
+func (c *budgetedClient) SubResource(subResource string) client.SubResourceClient {
+	return &budgetedSubResourceClient{SubResourceClient: c.Client.SubResource(subResource), budget: c.budget}
+}
+
+// budgetedSubResourceWriter charges status and other subresource writes to the budget
+type budgetedSubResourceWriter struct {
+	client.SubResourceWriter
+	budget *writeBudget
+}
+
+func (w *budgetedSubResourceWriter) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	w.budget.charge()
+	return w.SubResourceWriter.Create(ctx, obj, subResource, opts...)
+}
+
+func (w *budgetedSubResourceWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	w.budget.charge()
+	return w.SubResourceWriter.Update(ctx, obj, opts...)
+}
+
+func (w *budgetedSubResourceWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	w.budget.charge()
+	return w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+}
+
+// budgetedSubResourceClient is a subresource client whose writes are charged to the budget
+type budgetedSubResourceClient struct {
+	client.SubResourceClient
+	budget *writeBudget
+}
+
+func (c *budgetedSubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	c.budget.charge()
+	return c.SubResourceClient.Create(ctx, obj, subResource, opts...)
+}
+
+func (c *budgetedSubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	c.budget.charge()
+	return c.SubResourceClient.Update(ctx, obj, opts...)
+}
+
+func (c *budgetedSubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	c.budget.charge()
+	return c.SubResourceClient.Patch(ctx, obj, patch, opts...)
+}
@@ -79,6 +79,7 @@ kubectl delete mutatingwebhookconfiguration "${RELEASE_NAME}-mutating-webhook" 2
 # Step 5: Delete CRDs
 echo_info "Step 4: Deleting Custom Resource Definitions..."
 kubectl delete crd unifiedvolumereplications.replication.unified.io 2>/dev/null || echo_info "CRD not found"
+kubectl delete crd applicationreplications.replication.unified.io 2>/dev/null || echo_info "ApplicationReplication CRD not found"
 
 # Step 6: Delete OpenShift SCC if exists
 echo_info "Step 5: Deleting OpenShift SCC (if exists)..."
@@ -147,14 +147,14 @@ func ValidScheduleModes() []replicationv1alpha1.ScheduleMode {
 // ValidTimePatterns returns valid time patterns for RPO/RTO
 func ValidTimePatterns() []string {
 	return []string{
-		"5s", "30s", "1m", "5m", "15m", "30m", "1h", "2h", "6h", "12h", "1d",
+		"5s", "30s", "1m", "5m", "15m", "30m", "90m", "1h", "1h30m", "2h", "6h", "12h", "1d", "1d12h",
 	}
 }
 
 // InvalidTimePatterns returns invalid time patterns for testing validation
 func InvalidTimePatterns() []string {
 	return []string{
-		"", "5", "5x", "5ms", "1.5h", "30sm", "invalid", "5 minutes",
+		"", "5", "5x", "5ms", "1.5h", "30sm", "30m1h", "1h1h", "1h 30m", "invalid", "5 minutes",
 	}
 }
 
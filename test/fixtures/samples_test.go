@@ -146,7 +146,7 @@ func TestValidTimePatterns(t *testing.T) {
 	assert.Greater(t, len(patterns), 0, "Should have valid time patterns")
 
 	// Test each pattern matches the expected regex
-	timeRegex := `^[0-9]+(s|m|h|d)$`
+	timeRegex := replicationv1alpha1.ScheduleDurationPattern
 	for _, pattern := range patterns {
 		assert.Regexp(t, timeRegex, pattern, "Pattern %s should match time regex", pattern)
 	}
@@ -158,7 +158,7 @@ func TestInvalidTimePatterns(t *testing.T) {
 	assert.Greater(t, len(patterns), 0, "Should have invalid time patterns")
 
 	// These patterns should NOT match the time regex
-	timeRegex := `^[0-9]+(s|m|h|d)$`
+	timeRegex := replicationv1alpha1.ScheduleDurationPattern
 	for _, pattern := range patterns {
 		assert.NotRegexp(t, timeRegex, pattern, "Pattern %s should NOT match time regex", pattern)
 	}